@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Output modes of the remote client subcommands.
+const (
+	clientOutputText = "text"
+	clientOutputJSON = "json"
+)
+
+// clientOptions holds the connection flags shared by every remote client
+// subcommand (send, devices, chats, messages, login). They talk to a running
+// server over the REST API instead of booting a local WhatsApp client.
+var clientOptions struct {
+	URL     string
+	Auth    string
+	Device  string
+	Output  string
+	Timeout time.Duration
+}
+
+// addClientFlags registers the connection flags on a client command group.
+// Unset flags fall back to GOWA_URL, GOWA_AUTH, GOWA_DEVICE and GOWA_OUTPUT.
+func addClientFlags(cmd *cobra.Command) {
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&clientOptions.URL, "url", "",
+		`server base URL, including APP_BASE_PATH --url <string> | example: --url="https://wa.example.com/gowa" (default "http://localhost:<port>")`)
+	flags.StringVar(&clientOptions.Auth, "auth", "",
+		`basic auth credential --auth <user:secret> | example: --auth="admin:secret" (default: first --basic-auth entry)`)
+	flags.StringVar(&clientOptions.Device, "device", "",
+		`device id sent as X-Device-Id --device <string> | example: --device="my-device"`)
+	flags.StringVar(&clientOptions.Output, "output", clientOutputText,
+		`output format --output <text|json> | example: --output=json`)
+	flags.DurationVar(&clientOptions.Timeout, "timeout", 2*time.Minute,
+		`request timeout --timeout <duration> | example: --timeout=30s`)
+}
+
+// apiClient is a thin REST client for the server's own API.
+type apiClient struct {
+	baseURL    string
+	username   string
+	password   string
+	deviceID   string
+	httpClient *http.Client
+}
+
+// apiResponse mirrors utils.ResponseData with the results left undecoded.
+type apiResponse struct {
+	Status  int             `json:"status"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Results json.RawMessage `json:"results"`
+}
+
+// newAPIClient builds a client from the connection flags and their env
+// fallbacks.
+func newAPIClient(cmd *cobra.Command) (*apiClient, error) {
+	baseURL := flagOrEnv(cmd, "url", clientOptions.URL, "gowa_url")
+	if baseURL == "" {
+		// Default to the local server configured by the same flags/.env.
+		port := flagOrEnv(cmd, "port", config.AppPort, "app_port")
+		basePath := flagOrEnv(cmd, "base-path", config.AppBasePath, "app_base_path")
+		baseURL = "http://localhost:" + port + basePath
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid --url %q: expected an absolute http(s) URL", baseURL)
+	}
+
+	output := flagOrEnv(cmd, "output", clientOptions.Output, "gowa_output")
+	if output != clientOutputText && output != clientOutputJSON {
+		return nil, fmt.Errorf("invalid --output %q: must be %q or %q", output, clientOutputText, clientOutputJSON)
+	}
+	clientOptions.Output = output
+
+	client := &apiClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		deviceID:   flagOrEnv(cmd, "device", clientOptions.Device, "gowa_device"),
+		httpClient: &http.Client{Timeout: clientOptions.Timeout},
+	}
+
+	auth := flagOrEnv(cmd, "auth", clientOptions.Auth, "gowa_auth")
+	if auth == "" {
+		// Fall back to the server's own credentials (--basic-auth / APP_BASIC_AUTH).
+		credentials := config.AppBasicAuthCredential
+		if f := cmd.Flags().Lookup("basic-auth"); (f == nil || !f.Changed) && viper.GetString("app_basic_auth") != "" {
+			credentials = strings.Split(viper.GetString("app_basic_auth"), ",")
+		}
+		if len(credentials) > 0 {
+			auth = strings.TrimSpace(credentials[0])
+		}
+	}
+	if auth != "" {
+		username, password, ok := strings.Cut(auth, ":")
+		if !ok {
+			return nil, errors.New("invalid --auth: expected <user>:<secret>")
+		}
+		client.username, client.password = username, password
+	}
+	return client, nil
+}
+
+// flagOrEnv returns the flag value when it was set explicitly, otherwise the
+// env/.env value of envKey, otherwise the flag default.
+func flagOrEnv(cmd *cobra.Command, flag, value, envKey string) string {
+	if f := cmd.Flags().Lookup(flag); f != nil && f.Changed {
+		return value
+	}
+	if env := strings.TrimSpace(viper.GetString(envKey)); env != "" {
+		return env
+	}
+	return value
+}
+
+func (c *apiClient) send(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	if c.deviceID != "" {
+		req.Header.Set(middleware.DeviceIDHeader, c.deviceID)
+	}
+	return c.httpClient.Do(req)
+}
+
+func (c *apiClient) do(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string) (*apiResponse, error) {
+	resp, err := c.send(ctx, method, path, query, body, contentType)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var result apiResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		if resp.StatusCode >= http.StatusBadRequest {
+			return nil, fmt.Errorf("%s %s: HTTP %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(raw)))
+		}
+		return nil, fmt.Errorf("%s %s: unexpected response: %w", method, path, err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, responseError(resp.StatusCode, &result)
+	}
+	return &result, nil
+}
+
+// download streams a non-JSON response body (an export or file) to w and
+// returns the attachment filename the server suggested, if any.
+func (c *apiClient) download(ctx context.Context, path string, query url.Values, w io.Writer) (string, error) {
+	resp, err := c.send(ctx, http.MethodGet, path, query, nil, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		var result apiResponse
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return "", fmt.Errorf("GET %s: HTTP %d: %s", path, resp.StatusCode, strings.TrimSpace(string(raw)))
+		}
+		return "", responseError(resp.StatusCode, &result)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return "", err
+	}
+	filename := ""
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		filename = filepath.Base(params["filename"])
+	}
+	return filename, nil
+}
+
+func responseError(status int, result *apiResponse) error {
+	code := result.Code
+	if code == "" {
+		code = fmt.Sprintf("HTTP %d", status)
+	}
+	return fmt.Errorf("%s: %s", code, result.Message)
+}
+
+func (c *apiClient) get(ctx context.Context, path string, query url.Values) (*apiResponse, error) {
+	return c.do(ctx, http.MethodGet, path, query, nil, "")
+}
+
+func (c *apiClient) delete(ctx context.Context, path string) (*apiResponse, error) {
+	return c.do(ctx, http.MethodDelete, path, nil, nil, "")
+}
+
+func (c *apiClient) postJSON(ctx context.Context, path string, payload any) (*apiResponse, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, http.MethodPost, path, nil, bytes.NewReader(body), "application/json")
+}
+
+// postMultipart sends fields plus, when filePath is set, the file under
+// fileField.
+func (c *apiClient) postMultipart(ctx context.Context, path string, fields map[string]string, fileField, filePath string) (*apiResponse, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			return nil, err
+		}
+	}
+	if filePath != "" {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		part, err := writer.CreateFormFile(fileField, filepath.Base(filePath))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(part, file); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return c.do(ctx, http.MethodPost, path, nil, &body, writer.FormDataContentType())
+}
+
+// printResult writes the response: the raw results as indented JSON in json
+// mode, otherwise whatever text renders.
+func printResult(cmd *cobra.Command, resp *apiResponse, text func(w io.Writer) error) error {
+	out := cmd.OutOrStdout()
+	if clientOptions.Output == clientOutputJSON {
+		return writeIndentedJSON(out, resp.Results)
+	}
+	return text(out)
+}
+
+func writeIndentedJSON(w io.Writer, raw json.RawMessage) error {
+	if len(raw) == 0 {
+		raw = json.RawMessage("null")
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// newTable returns a tabwriter for aligned text output; callers must Flush.
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+// isRemoteURL reports whether a send argument is a URL for the server to
+// fetch rather than a local file to upload.
+func isRemoteURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/spf13/cobra"
+)
+
+var chatsListOptions struct {
+	Limit  int
+	Offset int
+	Search string
+}
+
+var chatsExportOptions struct {
+	Format       string
+	IncludeMedia bool
+	File         string
+	StartTime    string
+	EndTime      string
+	Timezone     string
+}
+
+var messagesExportOptions struct {
+	File      string
+	StartTime string
+	EndTime   string
+}
+
+var chatsCmd = &cobra.Command{
+	Use:   "chats",
+	Short: "Browse chats on a running server",
+}
+
+var chatsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List chats of the selected device",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(chatsListOptions.Limit))
+		query.Set("offset", strconv.Itoa(chatsListOptions.Offset))
+		if chatsListOptions.Search != "" {
+			query.Set("search", chatsListOptions.Search)
+		}
+		resp, err := client.get(cmd.Context(), "/chats", query)
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, resp, func(w io.Writer) error {
+			var chats domainChat.ListChatsResponse
+			if err := json.Unmarshal(resp.Results, &chats); err != nil {
+				return err
+			}
+			table := newTable(w)
+			fmt.Fprintln(table, "JID\tNAME\tLAST MESSAGE")
+			for _, chat := range chats.Data {
+				fmt.Fprintf(table, "%s\t%s\t%s\n", chat.JID, chat.Name, chat.LastMessageTime)
+			}
+			if err := table.Flush(); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(w, "\n%d of %d chats\n", len(chats.Data), chats.Pagination.Total)
+			return err
+		})
+	},
+}
+
+var chatsExportCmd = &cobra.Command{
+	Use:   "export [chat_jid]",
+	Short: "Export one chat, or every chat, as JSON, CSV, HTML or WhatsApp text",
+	Long: `Downloads GET /chat/:chat_jid/export (or /chats/export without a chat) and
+saves it under the server's suggested filename, or --file ("-" for stdout).
+With --media the transcript and every downloadable media file are bundled into
+a zip archive.`,
+	Example: `  ./whatsapp chats export 6289685028129@s.whatsapp.net --format=html --media
+  ./whatsapp chats export --format=txt --timezone=Asia/Jakarta --file=-`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		chatJID := ""
+		if len(args) == 1 {
+			chatJID = args[0]
+		}
+		return runChatExport(cmd, client, chatJID, chatsExportOptions.File, chatExportQuery{
+			Format:       chatsExportOptions.Format,
+			IncludeMedia: chatsExportOptions.IncludeMedia,
+			StartTime:    chatsExportOptions.StartTime,
+			EndTime:      chatsExportOptions.EndTime,
+			Timezone:     chatsExportOptions.Timezone,
+		})
+	},
+}
+
+var messagesCmd = &cobra.Command{
+	Use:   "messages",
+	Short: "Work with stored messages on a running server",
+}
+
+var messagesExportCmd = &cobra.Command{
+	Use:   "export <chat_jid>",
+	Short: "Export a chat's stored messages",
+	Long: `Downloads the same server-side export as "chats export" for one chat and
+writes it to stdout, or --file. With --output=json the export is the JSON
+document; otherwise the WhatsApp-style text transcript.`,
+	Example: `  ./whatsapp messages export 6289685028129@s.whatsapp.net --output=json --file=chat.json`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		format := domainChat.ExportFormatText
+		if clientOptions.Output == clientOutputJSON {
+			format = domainChat.ExportFormatJSON
+		}
+		file := messagesExportOptions.File
+		if file == "" {
+			file = "-"
+		}
+		return runChatExport(cmd, client, args[0], file, chatExportQuery{
+			Format:    format,
+			StartTime: messagesExportOptions.StartTime,
+			EndTime:   messagesExportOptions.EndTime,
+		})
+	},
+}
+
+func init() {
+	addClientFlags(chatsCmd)
+	rootCmd.AddCommand(chatsCmd)
+	chatsCmd.AddCommand(chatsListCmd, chatsExportCmd)
+
+	chatsListCmd.Flags().IntVar(&chatsListOptions.Limit, "limit", 25, `page size (max 100) --limit <number>`)
+	chatsListCmd.Flags().IntVar(&chatsListOptions.Offset, "offset", 0, `page offset --offset <number>`)
+	chatsListCmd.Flags().StringVar(&chatsListOptions.Search, "search", "", `filter chats by name --search <string>`)
+
+	chatsExportCmd.Flags().StringVar(&chatsExportOptions.Format, "format", "json", `export format --format <json|csv|html|txt>`)
+	chatsExportCmd.Flags().BoolVar(&chatsExportOptions.IncludeMedia, "media", false, `bundle downloaded media into a zip --media <true/false>`)
+	chatsExportCmd.Flags().StringVar(&chatsExportOptions.File, "file", "", `output path, "-" for stdout --file <path> (default: server filename)`)
+	chatsExportCmd.Flags().StringVar(&chatsExportOptions.StartTime, "start-time", "", `only messages at or after this RFC3339 time --start-time <string>`)
+	chatsExportCmd.Flags().StringVar(&chatsExportOptions.EndTime, "end-time", "", `only messages at or before this RFC3339 time --end-time <string>`)
+	chatsExportCmd.Flags().StringVar(&chatsExportOptions.Timezone, "timezone", "",
+		`IANA zone for csv/html/txt timestamps --timezone <string> | example: --timezone="Asia/Jakarta"`)
+
+	addClientFlags(messagesCmd)
+	rootCmd.AddCommand(messagesCmd)
+	messagesCmd.AddCommand(messagesExportCmd)
+
+	messagesExportCmd.Flags().StringVar(&messagesExportOptions.File, "file", "", `write to a file instead of stdout --file <path>`)
+	messagesExportCmd.Flags().StringVar(&messagesExportOptions.StartTime, "start-time", "",
+		`only messages at or after this RFC3339 time --start-time <string> | example: --start-time="2025-01-01T00:00:00Z"`)
+	messagesExportCmd.Flags().StringVar(&messagesExportOptions.EndTime, "end-time", "",
+		`only messages at or before this RFC3339 time --end-time <string>`)
+}
+
+// chatExportQuery holds the options of GET /chat/:chat_jid/export and
+// /chats/export.
+type chatExportQuery struct {
+	Format       string
+	IncludeMedia bool
+	StartTime    string
+	EndTime      string
+	Timezone     string
+}
+
+// runChatExport downloads the server-side export of chatJID, or of every chat
+// when it is empty, to file ("-" for stdout, "" for the server's filename).
+func runChatExport(cmd *cobra.Command, client *apiClient, chatJID, file string, options chatExportQuery) error {
+	path := "/chats/export"
+	if chatJID != "" {
+		path = "/chat/" + url.PathEscape(chatJID) + "/export"
+	}
+	query := url.Values{}
+	query.Set("format", options.Format)
+	if options.IncludeMedia {
+		query.Set("include_media", "true")
+	}
+	for key, value := range map[string]string{
+		"start_time": options.StartTime,
+		"end_time":   options.EndTime,
+		"timezone":   options.Timezone,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	if file == "-" {
+		_, err := client.download(cmd.Context(), path, query, cmd.OutOrStdout())
+		return err
+	}
+	return downloadToFile(cmd, client, path, query, file)
+}
+
+// downloadToFile saves a download to file, or to the server's suggested
+// filename in the working directory when file is empty.
+func downloadToFile(cmd *cobra.Command, client *apiClient, path string, query url.Values, file string) error {
+	dir := "."
+	if file != "" {
+		dir = filepath.Dir(file)
+	}
+	tmp, err := os.CreateTemp(dir, ".gowa-download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	filename, err := client.download(cmd.Context(), path, query, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if file == "" {
+		file = filename
+		if file == "" {
+			file = "export"
+		}
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+
+	if clientOptions.Output == clientOutputJSON {
+		raw, err := json.Marshal(map[string]any{"file": file, "bytes": info.Size()})
+		if err != nil {
+			return err
+		}
+		return writeIndentedJSON(cmd.OutOrStdout(), raw)
+	}
+	_, err = fmt.Fprintf(cmd.OutOrStdout(), "Saved %s (%d bytes)\n", file, info.Size())
+	return err
+}
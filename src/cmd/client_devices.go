@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/spf13/cobra"
+)
+
+var devicesCmd = &cobra.Command{
+	Use:   "devices",
+	Short: "Manage devices on a running server",
+}
+
+var devicesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered devices",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+		resp, err := client.get(cmd.Context(), "/devices", nil)
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, resp, func(w io.Writer) error {
+			var devices []domainDevice.Device
+			if err := json.Unmarshal(resp.Results, &devices); err != nil {
+				return err
+			}
+			table := newTable(w)
+			fmt.Fprintln(table, "ID\tSTATE\tJID\tNAME")
+			for _, device := range devices {
+				fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", device.ID, device.State, device.JID, device.DisplayName)
+			}
+			return table.Flush()
+		})
+	},
+}
+
+var devicesAddCmd = &cobra.Command{
+	Use:     "add [device_id]",
+	Short:   "Create a device slot (id is generated when omitted)",
+	Example: `  ./whatsapp devices add my-device && ./whatsapp login --device=my-device`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+		payload := map[string]any{}
+		if len(args) == 1 {
+			payload["device_id"] = args[0]
+		}
+		resp, err := client.postJSON(cmd.Context(), "/devices", payload)
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, resp, func(w io.Writer) error {
+			var device struct {
+				ID    string `json:"id"`
+				State string `json:"state"`
+			}
+			if err := json.Unmarshal(resp.Results, &device); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(w, "Device %s created (%s)\n", device.ID, device.State)
+			return err
+		})
+	},
+}
+
+var devicesRemoveCmd = &cobra.Command{
+	Use:   "remove <device_id>",
+	Short: "Remove a device and its session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+		resp, err := client.delete(cmd.Context(), "/devices/"+url.PathEscape(args[0]))
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, resp, func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "Device %s removed\n", args[0])
+			return err
+		})
+	},
+}
+
+func init() {
+	addClientFlags(devicesCmd)
+	rootCmd.AddCommand(devicesCmd)
+	devicesCmd.AddCommand(devicesListCmd, devicesAddCmd, devicesRemoveCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/spf13/cobra"
+)
+
+var loginOptions struct {
+	Code string
+}
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Pair a device on a running server (QR link or pairing code)",
+	Long: `Starts pairing for the device selected with --device (or the only device).
+Without --code it prints the QR code image link to scan; with --code it
+requests an 8-character pairing code for that phone number instead.`,
+	Example: `  ./whatsapp login --device=my-device
+  ./whatsapp login --device=my-device --code=6289685028129`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+
+		if loginOptions.Code != "" {
+			resp, err := client.get(cmd.Context(), "/app/login-with-code", url.Values{"phone": {loginOptions.Code}})
+			if err != nil {
+				return err
+			}
+			return printResult(cmd, resp, func(w io.Writer) error {
+				var result struct {
+					DeviceID string `json:"device_id"`
+					PairCode string `json:"pair_code"`
+				}
+				if err := json.Unmarshal(resp.Results, &result); err != nil {
+					return err
+				}
+				_, err := fmt.Fprintf(w, "Pairing code for %s: %s\nEnter it on the phone under Linked devices > Link with phone number.\n", result.DeviceID, result.PairCode)
+				return err
+			})
+		}
+
+		resp, err := client.get(cmd.Context(), "/app/login", nil)
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, resp, func(w io.Writer) error {
+			var result struct {
+				DeviceID   string `json:"device_id"`
+				QRLink     string `json:"qr_link"`
+				QRDuration int    `json:"qr_duration"`
+			}
+			if err := json.Unmarshal(resp.Results, &result); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(w, "Scan the QR code for %s within %ds:\n%s\n", result.DeviceID, result.QRDuration, result.QRLink)
+			return err
+		})
+	},
+}
+
+func init() {
+	addClientFlags(loginCmd)
+	rootCmd.AddCommand(loginCmd)
+
+	loginCmd.Flags().StringVar(&loginOptions.Code, "code", "",
+		`pair with a code instead of a QR for this phone number --code <phone> | example: --code=6289685028129`)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+var sendOptions struct {
+	ReplyTo  string
+	Caption  string
+	ViewOnce bool
+}
+
+var sendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Send messages through a running server",
+}
+
+var sendTextCmd = &cobra.Command{
+	Use:     "text <phone> <message>",
+	Short:   "Send a text message",
+	Example: `  ./whatsapp send text 6289685028129 "Hello from the CLI" --device=my-device`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+		payload := map[string]any{"phone": args[0], "message": args[1]}
+		if sendOptions.ReplyTo != "" {
+			payload["reply_message_id"] = sendOptions.ReplyTo
+		}
+		resp, err := client.postJSON(cmd.Context(), "/send/message", payload)
+		if err != nil {
+			return err
+		}
+		return printSendResult(cmd, resp)
+	},
+}
+
+var sendImageCmd = &cobra.Command{
+	Use:     "image <phone> <path|url>",
+	Short:   "Send an image from a local file or URL",
+	Example: `  ./whatsapp send image 6289685028129 ./photo.jpg --caption="Look"`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+		fields := map[string]string{
+			"phone":     args[0],
+			"caption":   sendOptions.Caption,
+			"view_once": strconv.FormatBool(sendOptions.ViewOnce),
+		}
+		return sendMedia(cmd, client, "/send/image", fields, "image", args[1])
+	},
+}
+
+var sendFileCmd = &cobra.Command{
+	Use:     "file <phone> <path|url>",
+	Short:   "Send a document from a local file or URL",
+	Example: `  ./whatsapp send file 6289685028129 ./report.pdf --caption="Q3 report"`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := newAPIClient(cmd)
+		if err != nil {
+			return err
+		}
+		fields := map[string]string{
+			"phone":   args[0],
+			"caption": sendOptions.Caption,
+		}
+		return sendMedia(cmd, client, "/send/file", fields, "file", args[1])
+	},
+}
+
+func init() {
+	addClientFlags(sendCmd)
+	rootCmd.AddCommand(sendCmd)
+	sendCmd.AddCommand(sendTextCmd, sendImageCmd, sendFileCmd)
+
+	sendCmd.PersistentFlags().StringVar(&sendOptions.ReplyTo, "reply-to", "",
+		`message id to reply to --reply-to <string> | example: --reply-to="3EB0B430B6F8F1D0E053AC120E0A9E5C"`)
+	sendImageCmd.Flags().StringVar(&sendOptions.Caption, "caption", "", `image caption --caption <string>`)
+	sendImageCmd.Flags().BoolVar(&sendOptions.ViewOnce, "view-once", false, `send as view-once --view-once <true/false>`)
+	sendFileCmd.Flags().StringVar(&sendOptions.Caption, "caption", "", `file caption --caption <string>`)
+}
+
+// sendMedia uploads source as fileField, or passes it as <fileField>_url when
+// it is a remote URL the server should download itself.
+func sendMedia(cmd *cobra.Command, client *apiClient, path string, fields map[string]string, fileField, source string) error {
+	if sendOptions.ReplyTo != "" {
+		fields["reply_message_id"] = sendOptions.ReplyTo
+	}
+	filePath := source
+	if isRemoteURL(source) {
+		fields[fileField+"_url"] = source
+		filePath = ""
+	}
+	resp, err := client.postMultipart(cmd.Context(), path, fields, fileField, filePath)
+	if err != nil {
+		return err
+	}
+	return printSendResult(cmd, resp)
+}
+
+func printSendResult(cmd *cobra.Command, resp *apiResponse) error {
+	return printResult(cmd, resp, func(w io.Writer) error {
+		var result struct {
+			MessageID string `json:"message_id"`
+			Status    string `json:"status"`
+		}
+		if err := json.Unmarshal(resp.Results, &result); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(w, "%s\nmessage_id: %s\n", result.Status, result.MessageID)
+		return err
+	})
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runClient executes a client subcommand against the root command and returns
+// its stdout. Flags keep their values between runs, so tests pass every flag
+// they rely on explicitly.
+func runClient(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(args)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	})
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
+func writeEnvelope(w http.ResponseWriter, status int, code, message string, results any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"status":  status,
+		"code":    code,
+		"message": message,
+		"results": results,
+	})
+}
+
+func TestClientSendText(t *testing.T) {
+	var gotPath, gotDevice, gotUser, gotPass string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotDevice = r.Header.Get(middleware.DeviceIDHeader)
+		gotUser, gotPass, _ = r.BasicAuth()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+		writeEnvelope(w, http.StatusOK, "SUCCESS", "Success", map[string]any{
+			"message_id": "3EB0ABC",
+			"status":     "Message sent to 628123@s.whatsapp.net",
+		})
+	}))
+	defer server.Close()
+
+	out, err := runClient(t, "send", "text", "628123", "hello",
+		"--url", server.URL+"/gowa", "--auth", "admin:secret", "--device", "dev-1", "--output", "text")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/gowa/send/message", gotPath)
+	assert.Equal(t, "dev-1", gotDevice)
+	assert.Equal(t, "admin", gotUser)
+	assert.Equal(t, "secret", gotPass)
+	assert.Equal(t, "628123", gotBody["phone"])
+	assert.Equal(t, "hello", gotBody["message"])
+	assert.Contains(t, out, "message_id: 3EB0ABC")
+}
+
+func TestClientErrorEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeEnvelope(w, http.StatusBadRequest, "DEVICE_NOT_FOUND", "device dev-x not found", nil)
+	}))
+	defer server.Close()
+
+	_, err := runClient(t, "devices", "list", "--url", server.URL, "--auth", "a:b", "--device", "dev-x", "--output", "text")
+	require.Error(t, err)
+	assert.Equal(t, "DEVICE_NOT_FOUND: device dev-x not found", err.Error())
+}
+
+func TestClientDevicesListJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/devices", r.URL.Path)
+		writeEnvelope(w, http.StatusOK, "SUCCESS", "Fetch device success", []map[string]any{
+			{"id": "dev-1", "state": "logged_in", "jid": "628123@s.whatsapp.net"},
+		})
+	}))
+	defer server.Close()
+
+	out, err := runClient(t, "devices", "list", "--url", server.URL, "--auth", "a:b", "--device", "", "--output", "json")
+	require.NoError(t, err)
+
+	var devices []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &devices))
+	require.Len(t, devices, 1)
+	assert.Equal(t, "dev-1", devices[0]["id"])
+}
+
+func TestClientMessagesExportUsesServerExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/628123@s.whatsapp.net/export", r.URL.Path)
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+		assert.Equal(t, "2025-01-01T00:00:00Z", r.URL.Query().Get("start_time"))
+		assert.Empty(t, r.URL.Query().Get("include_media"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"device_id":"dev-1","chats":[{"jid":"628123@s.whatsapp.net","messages":[{"id":"msg-0"}]}]}`))
+	}))
+	defer server.Close()
+
+	out, err := runClient(t, "messages", "export", "628123@s.whatsapp.net",
+		"--url", server.URL, "--auth", "a:b", "--device", "dev-1", "--output", "json",
+		"--start-time", "2025-01-01T00:00:00Z")
+	require.NoError(t, err)
+
+	var export map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &export))
+	assert.Equal(t, "dev-1", export["device_id"])
+}
+
+func TestClientChatsExportSavesFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/628123@s.whatsapp.net/export", r.URL.Path)
+		assert.Equal(t, "html", r.URL.Query().Get("format"))
+		assert.Equal(t, "true", r.URL.Query().Get("include_media"))
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="chat-628123-20260301-093000.zip"`)
+		_, _ = w.Write([]byte("PK-archive"))
+	}))
+	defer server.Close()
+
+	target := filepath.Join(t.TempDir(), "alice.zip")
+	out, err := runClient(t, "chats", "export", "628123@s.whatsapp.net",
+		"--url", server.URL, "--auth", "a:b", "--device", "dev-1", "--output", "text",
+		"--format", "html", "--media", "--file", target)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "PK-archive", string(data))
+	assert.Contains(t, out, "Saved "+target)
+}
+
+func TestClientChatsExportSurfacesErrorEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeEnvelope(w, http.StatusBadRequest, "VALIDATION_ERROR", "format: must be a valid value.", nil)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	_, err := runClient(t, "chats", "export", "--url", server.URL, "--auth", "a:b", "--device", "dev-1",
+		"--output", "text", "--format", "pdf", "--media=false", "--file", filepath.Join(dir, "out.pdf"))
+	require.Error(t, err)
+	assert.Equal(t, "VALIDATION_ERROR: format: must be a valid value.", err.Error())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a failed export must not leave files behind")
+}
+
+func TestNewAPIClientEnvFallback(t *testing.T) {
+	t.Setenv("GOWA_URL", "http://wa.internal:3000/gowa/")
+	t.Setenv("GOWA_AUTH", "env-user:env-secret")
+
+	client, err := newAPIClient(loginCmd)
+	require.NoError(t, err)
+	assert.Equal(t, "http://wa.internal:3000/gowa", client.baseURL)
+	assert.Equal(t, "env-user", client.username)
+	assert.Equal(t, "env-secret", client.password)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	uimcp "github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp"
 	mcpoauth "github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp/oauth"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v3"
 	"github.com/spf13/viper"
 )
@@ -98,7 +99,9 @@ func registerMcpOAuth(app *fiber.App, dm *whatsapp.DeviceManager) (*mcpoauth.Ser
 		return nil, false, err
 	}
 
-	oauthServer.RegisterPublic(app)
+	// The OAuth endpoints sit in front of Basic Auth, outside the global audit
+	// middleware, so token issuance and revocation are audited on their own.
+	oauthServer.RegisterPublic(app, middleware.Audit(auditUsecase, nil))
 
 	var mcpRouter fiber.Router = app
 	if config.AppBasePath != "" {
@@ -106,12 +109,15 @@ func registerMcpOAuth(app *fiber.App, dm *whatsapp.DeviceManager) (*mcpoauth.Ser
 	}
 	mcpRouter = mcpRouter.Group("", oauthServer.MCPAuthMiddleware(validateCredential))
 	uimcp.Register(mcpRouter, dm, uimcp.Deps{
-		App:     appUsecase,
-		Send:    sendUsecase,
-		Chat:    chatUsecase,
-		User:    userUsecase,
-		Message: messageUsecase,
-		Group:   groupUsecase,
+		App:        appUsecase,
+		Send:       sendUsecase,
+		Chat:       chatUsecase,
+		User:       userUsecase,
+		Message:    messageUsecase,
+		Group:      groupUsecase,
+		Newsletter: newsletterUsecase,
+		Device:     deviceUsecase,
+		Audit:      auditUsecase,
 	})
 
 	return oauthServer, true, nil
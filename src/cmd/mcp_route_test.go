@@ -74,17 +74,50 @@ func TestMcpEndpointListsFiveTools(t *testing.T) {
 	require.True(t, ok, "tools/list result: %v", listRes)
 	tools, ok := result["tools"].([]any)
 	require.True(t, ok)
-	require.Len(t, tools, 5)
+	require.Len(t, tools, 8)
 
 	names := map[string]bool{}
 	for _, tl := range tools {
 		names[tl.(map[string]any)["name"].(string)] = true
 	}
-	for _, want := range []string{"whatsapp_send", "whatsapp_message", "whatsapp_chat", "whatsapp_group", "whatsapp_app"} {
+	for _, want := range []string{"whatsapp_send", "whatsapp_message", "whatsapp_chat", "whatsapp_group", "whatsapp_newsletter", "whatsapp_app", "whatsapp_user", "whatsapp_device"} {
 		assert.True(t, names[want], "missing tool %s", want)
 	}
 }
 
+func TestMcpEndpointListsResourcesAndPrompts(t *testing.T) {
+	app := newMcpTestApp(false)
+
+	req := httptest.NewRequest("POST", "/mcp", strings.NewReader(initializeRPC))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, 200, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Mcp-Session-Id"), "mcp-session-"), "initialize should open a session for subscriptions")
+
+	// Requests without the session header keep working statelessly.
+	rec, listRes := mcpRPC(t, app, `{"jsonrpc":"2.0","id":2,"method":"resources/list","params":{}}`, false)
+	require.Equal(t, 200, rec.Code)
+	resources := listRes["result"].(map[string]any)["resources"].([]any)
+	assert.Len(t, resources, 2)
+
+	rec, listRes = mcpRPC(t, app, `{"jsonrpc":"2.0","id":3,"method":"resources/templates/list","params":{}}`, false)
+	require.Equal(t, 200, rec.Code)
+	templates := listRes["result"].(map[string]any)["resourceTemplates"].([]any)
+	assert.Len(t, templates, 2)
+
+	rec, listRes = mcpRPC(t, app, `{"jsonrpc":"2.0","id":4,"method":"prompts/list","params":{}}`, false)
+	require.Equal(t, 200, rec.Code)
+	names := map[string]bool{}
+	for _, p := range listRes["result"].(map[string]any)["prompts"].([]any) {
+		names[p.(map[string]any)["name"].(string)] = true
+	}
+	assert.True(t, names["summarize_unread_chat"])
+	assert.True(t, names["draft_reply"])
+}
+
 func TestMcpEndpointRequiresBasicAuth(t *testing.T) {
 	app := newMcpTestApp(true)
 
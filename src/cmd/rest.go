@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -16,6 +17,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/uiasset"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	uimcp "github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest"
@@ -33,10 +35,11 @@ import (
 
 // rootCmd represents the base command when called without any subcommands
 var restCmd = &cobra.Command{
-	Use:   "rest",
-	Short: "Send whatsapp API over http",
-	Long:  `This application is from clone https://github.com/aldinokemal/go-whatsapp-web-multidevice`,
-	Run:   restServer,
+	Use:    "rest",
+	Short:  "Send whatsapp API over http",
+	Long:   `This application is from clone https://github.com/aldinokemal/go-whatsapp-web-multidevice`,
+	PreRun: serverPreRun,
+	Run:    restServer,
 }
 
 func init() {
@@ -45,6 +48,19 @@ func init() {
 func restServer(_ *cobra.Command, _ []string) {
 	// registerMcpOAuth depends on these values being loaded after flag parsing.
 	loadMcpOAuthEnvConfig()
+
+	shutdownTracing, err := telemetry.Init(context.Background())
+	if err != nil {
+		logrus.Fatalln("Failed to initialize OpenTelemetry: ", err.Error())
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logrus.Warnf("OpenTelemetry shutdown: %v", err)
+		}
+	}()
+
 	fiberConfig := fiber.Config{
 		TrustProxy: true,
 		BodyLimit:  int(config.WhatsappSettingMaxVideoSize),
@@ -64,6 +80,10 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	app.Use(config.AppBasePath+"/statics", static.New("./statics"))
 
+	// Tracing wraps Recovery so spans see the status of recovered panics.
+	if config.OtelEnabled {
+		app.Use(middleware.Tracing())
+	}
 	app.Use(middleware.Recovery())
 	app.Use(middleware.RequestTimeout(middleware.DefaultRequestTimeout))
 	if config.AppDebug {
@@ -83,6 +103,19 @@ func restServer(_ *cobra.Command, _ []string) {
 		return c.Status(http.StatusServiceUnavailable).SendString("Service Unavailable")
 	})
 
+	// High availability: replicas sharing one Postgres store lease devices
+	// between them instead of each connecting every session.
+	var leaseCoordinator *whatsapp.LeaseCoordinator
+	if config.HAEnabled {
+		leaseCoordinator, err = whatsapp.InitLeaseCoordinator(context.Background(), dm)
+		if err != nil {
+			logrus.Fatalln("Failed to initialize high availability: ", err.Error())
+		}
+	}
+
+	// Kubernetes liveness/readiness probes (public, root path like /health).
+	rest.InitRestHealth(app, readinessChecks(dm)...)
+
 	// Chatwoot webhook - registered BEFORE basic auth middleware
 	// This allows Chatwoot to send webhooks without authentication. The handler
 	// is stateless and shared with the authenticated sync routes registered below.
@@ -132,6 +165,22 @@ func restServer(_ *cobra.Command, _ []string) {
 		app.Use(newBasicAuthMiddleware(account))
 	}
 
+	// Requests for a device leased by another replica are forwarded there
+	// before Principal and Audit; the owner authenticates and records them.
+	if leaseCoordinator != nil {
+		app.Use(middleware.HAProxy(leaseCoordinator))
+	}
+
+	app.Use(middleware.Principal())
+
+	// Audit trail for every authenticated mutating call. It sits behind Basic
+	// Auth so rejected requests are never recorded. The MCP endpoint is skipped
+	// because its tools are audited one call at a time (see ui/mcp/audit.go).
+	mcpPath := config.AppBasePath + "/mcp"
+	app.Use(middleware.Audit(auditUsecase, func(c fiber.Ctx) bool {
+		return c.Path() == mcpPath
+	}))
+
 	// Create base path group or use app directly
 	var apiGroup fiber.Router = app
 	if config.AppBasePath != "" {
@@ -142,6 +191,7 @@ func restServer(_ *cobra.Command, _ []string) {
 		rest.InitRestApp(r, appUsecase)
 		rest.InitRestCall(r, callUsecase)
 		rest.InitRestChat(r, chatUsecase)
+		rest.InitRestContact(r, contactUsecase)
 		rest.InitRestSend(r, sendUsecase)
 		rest.InitRestUser(r, userUsecase)
 		rest.InitRestMessage(r, messageUsecase, sendUsecase)
@@ -156,17 +206,33 @@ func restServer(_ *cobra.Command, _ []string) {
 	// App info (version, limits) for standalone UIs; no device required
 	rest.InitRestAppInfo(apiGroup)
 
+	// Audit log query/export; no device required
+	rest.InitRestAudit(apiGroup, auditUsecase)
+
+	// Connected MCP OAuth clients and their grants; no device required
+	if oauthServer != nil {
+		oauthServer.RegisterAdmin(apiGroup)
+	}
+
+	// Device lease table of the HA cluster; no device required
+	if leaseCoordinator != nil {
+		rest.InitRestHA(apiGroup, leaseCoordinator)
+	}
+
 	// MCP endpoint — same usecase instances as REST, so both surfaces share
 	// one whatsmeow session. With OAuth disabled it keeps the existing global
 	// Basic Auth behavior; OAuth-enabled MCP was already mounted above.
 	if config.McpEnabled && !mcpOAuthRegistered {
 		uimcp.Register(apiGroup, dm, uimcp.Deps{
-			App:     appUsecase,
-			Send:    sendUsecase,
-			Chat:    chatUsecase,
-			User:    userUsecase,
-			Message: messageUsecase,
-			Group:   groupUsecase,
+			App:        appUsecase,
+			Send:       sendUsecase,
+			Chat:       chatUsecase,
+			User:       userUsecase,
+			Message:    messageUsecase,
+			Group:      groupUsecase,
+			Newsletter: newsletterUsecase,
+			Device:     deviceUsecase,
+			Audit:      auditUsecase,
 		})
 	}
 
@@ -194,11 +260,17 @@ func restServer(_ *cobra.Command, _ []string) {
 
 	go websocket.RunHub()
 
-	// Set auto reconnect to whatsapp server after booting
-	go helpers.SetAutoConnectAfterBooting(appUsecase)
+	if leaseCoordinator != nil {
+		// In HA mode a replica connects only the devices it leases; the
+		// coordinator also reconnects them, replacing the checks below.
+		leaseCoordinator.Start()
+	} else {
+		// Set auto reconnect to whatsapp server after booting
+		go helpers.SetAutoConnectAfterBooting(appUsecase)
 
-	// Set auto reconnect checking with a guaranteed client instance
-	startAutoReconnectCheckerIfClientAvailable()
+		// Set auto reconnect checking with a guaranteed client instance
+		startAutoReconnectCheckerIfClientAvailable()
+	}
 
 	// Set daily presence pulse scheduler when enabled
 	startPresencePulseSchedulerIfEnabled()
@@ -227,11 +299,21 @@ func restServer(_ *cobra.Command, _ []string) {
 		if err := app.ShutdownWithContext(shutdownCtx); err != nil {
 			logrus.Warnf("HTTP server shutdown: %v", err)
 		}
+		// Hand the leased devices over to the remaining replicas right away
+		// instead of letting them wait for the leases to expire.
+		if leaseCoordinator != nil {
+			if err := leaseCoordinator.Stop(shutdownCtx); err != nil {
+				logrus.Warnf("HA lease release: %v", err)
+			}
+		}
 		// Release any Chatwoot direct-Postgres importer pools opened by per-device
 		// sync services. Safe when Chatwoot is disabled or none were initialized.
 		if err := chatwoot.CloseAllSyncServices(); err != nil {
 			logrus.Warnf("Chatwoot sync close: %v", err)
 		}
+		// Presence writes are debounced; store the pending ones while the
+		// chat storage is still open.
+		whatsapp.FlushPresences()
 		if chatStorageDB != nil {
 			if err := chatStorageDB.Close(); err != nil {
 				logrus.Warnf("Chat storage close: %v", err)
@@ -310,6 +392,33 @@ func newCORSMiddleware() fiber.Handler {
 	})
 }
 
+// readinessChecks lists the dependencies /readyz verifies: the whatsmeow store,
+// the chat storage database and, when enabled with a global account, Chatwoot.
+func readinessChecks(dm *whatsapp.DeviceManager) []rest.HealthCheck {
+	checks := []rest.HealthCheck{
+		{Name: "whatsapp_store", Check: dm.PingStore},
+		{Name: "chat_storage", Check: func(ctx context.Context) error {
+			if chatStorageDB == nil {
+				return errors.New("chat storage not initialized")
+			}
+			return chatStorageDB.PingContext(ctx)
+		}},
+	}
+	if config.ChatwootEnabled {
+		checks = append(checks, rest.HealthCheck{Name: "chatwoot", Check: func(ctx context.Context) error {
+			// Only the global account is probed: per-device configs are
+			// tenants, and one unreachable tenant should not take the whole
+			// instance out of rotation.
+			client := chatwoot.GetDefaultClient()
+			if !client.IsConfigured() {
+				return nil
+			}
+			return client.Ping(ctx)
+		}})
+	}
+	return checks
+}
+
 func newBasicAuthMiddleware(accounts map[string]string) fiber.Handler {
 	return basicauth.New(basicauth.Config{
 		Authorizer: func(username, password string, _ fiber.Ctx) bool {
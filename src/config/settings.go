@@ -33,6 +33,17 @@ var (
 	// the REST server. Streamable HTTP transport; inherits basic auth.
 	McpEnabled = true
 
+	// OpenTelemetry tracing. When OtelEnabled is true, spans for REST
+	// handlers, usecases, WhatsApp sends, webhook submissions and Chatwoot
+	// API calls are exported over OTLP/HTTP to OtelEndpoint (host:port or
+	// full URL; empty falls back to the OTEL_EXPORTER_OTLP_* env vars).
+	// OtelSampleRatio is the parent-based trace sampling ratio (0..1).
+	OtelEnabled     = false
+	OtelEndpoint    = ""
+	OtelInsecure    = false
+	OtelServiceName = "gowa"
+	OtelSampleRatio = 1.0
+
 	PathQrCode    = "statics/qrcode"
 	PathSendItems = "statics/senditems"
 	PathMedia     = "statics/media"
@@ -42,15 +53,33 @@ var (
 	DBURI     = "file:storages/whatsapp.db"
 	DBKeysURI = ""
 
+	// High availability: replicas sharing one Postgres DBURI split the paired
+	// devices through leases stored in that database. HAReplicaID defaults to
+	// the hostname; HAAdvertiseURL is the base URL (scheme://host:port) other
+	// replicas use to proxy requests for devices this replica owns. Leases are
+	// renewed every HALeaseTTL/3 and taken over once they expire.
+	HAEnabled      = false
+	HAReplicaID    = ""
+	HAAdvertiseURL = ""
+	HALeaseTTL     = 30 * time.Second
+
+	// Device lifecycle alerts: events in WhatsappAlertEvents are posted to
+	// WhatsappAlertWebhook and/or sent to WhatsappAlertPhone from another
+	// connected device. Both targets are optional.
+	WhatsappAlertWebhook = ""
+	WhatsappAlertPhone   = ""
+	WhatsappAlertEvents  = []string{"device.logged_out", "device.stream_replaced", "device.temporary_ban"}
+
 	WhatsappAutoReplyMessage          string
 	WhatsappAutoMarkRead              = false // Auto-mark incoming messages as read
 	WhatsappAutoDownloadMedia         = true  // Auto-download media from incoming messages
 	WhatsappWebhook                   []string
 	WhatsappWebhookSecret             = "secret"
-	WhatsappWebhookInsecureSkipVerify = false          // Skip TLS certificate verification for webhooks (insecure)
-	WhatsappWebhookEvents             []string         // Whitelist of events to forward to webhook (empty = all events)
-	WhatsappWebhookIgnoreJids         []string         // JIDs (or "@g.us"/"@s.whatsapp.net"/"@lid" wildcards) to skip when forwarding to webhooks
-	WhatsappAutoRejectCall                     = false // Auto-reject incoming calls
+	WhatsappWebhookInsecureSkipVerify = false  // Skip TLS certificate verification for webhooks (insecure)
+	WhatsappWebhookEvents             []string // Whitelist of events to forward to webhook (empty = all events)
+	WhatsappWebhookIgnoreJids         []string // JIDs (or "@g.us"/"@s.whatsapp.net"/"@lid" wildcards) to skip when forwarding to webhooks
+	WhatsappAutoRejectCall            = false  // Auto-reject incoming calls
+	WhatsappAutoRejectCallMessage     string   // Text sent to auto-rejected callers (empty = none); devices can override it
 	WhatsappLogLevel                           = "ERROR"
 	WhatsappSettingMaxImageSize       int64    = 20000000  // 20MB
 	WhatsappSettingMaxFileSize        int64    = 50000000  // 50MB
@@ -73,6 +102,9 @@ var (
 	// egress restrictions where standard HTTP_PROXY env vars do not apply
 	// to the WhatsApp WebSocket dialer.
 	WhatsappProxy = ""
+	// WhatsappProxyFailoverAttempts is how many failed reconnects a device
+	// tolerates on its active proxy before moving to the next one in its pool.
+	WhatsappProxyFailoverAttempts = 3
 
 	ChatStorageURI               = "file:storages/chatstorage.db"
 	ChatStorageEnableForeignKeys = true
@@ -163,4 +195,14 @@ var (
 	// message when the opposite side reports deletion.
 	ChatwootMessageRead   = false
 	ChatwootMessageDelete = false
+
+	// Chatwoot presence relay. ChatwootTyping mirrors typing indicators both
+	// ways: an agent typing in Chatwoot shows "typing..." on WhatsApp and the
+	// customer typing on WhatsApp toggles Chatwoot's indicator.
+	// ChatwootContactAvatar copies WhatsApp profile pictures (group pictures for
+	// group conversations) onto Chatwoot contacts, re-checking an active chat's
+	// picture at most once per ChatwootAvatarRefreshInterval.
+	ChatwootTyping                = false
+	ChatwootContactAvatar         = true
+	ChatwootAvatarRefreshInterval = 24 * time.Hour
 )
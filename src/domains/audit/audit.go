@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"context"
+	"io"
+)
+
+// Audit sources and results recorded on every entry.
+const (
+	SourceREST = "rest"
+	SourceMCP  = "mcp"
+
+	ResultSuccess = "success"
+	ResultError   = "error"
+
+	PrincipalBasic     = "basic"
+	PrincipalOAuth     = "oauth"
+	PrincipalAnonymous = "anonymous"
+)
+
+type IAuditUsecase interface {
+	// Record appends an entry to the audit trail. Failures are returned so the
+	// caller can log them, but must never fail the audited request.
+	Record(ctx context.Context, entry RecordRequest) error
+	ListAudit(ctx context.Context, request ListAuditRequest) (ListAuditResponse, error)
+	// ExportAudit validates the filters and returns a function that writes
+	// every matching entry to w as JSON lines, newest first, one batch at a
+	// time. Limit/Offset are ignored.
+	ExportAudit(ctx context.Context, request ListAuditRequest) (write func(w io.Writer) error, err error)
+}
+
+// Principal identifies who performed an audited call: a basic-auth user or
+// an MCP OAuth subject (with the OAuth client that obtained the token).
+type Principal struct {
+	Type     string `json:"type"`
+	Subject  string `json:"subject"`
+	ClientID string `json:"client_id,omitempty"`
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal stores the authenticated principal on ctx.
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored by ContextWithPrincipal.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	if ctx == nil {
+		return Principal{}, false
+	}
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
+
+type RecordRequest struct {
+	Source       string
+	Principal    Principal
+	DeviceID     string
+	Action       string
+	TargetJID    string
+	MessageID    string
+	Result       string
+	StatusCode   int
+	ErrorMessage string
+}
+
+type ListAuditRequest struct {
+	Limit     int    `json:"limit" query:"limit"`
+	Offset    int    `json:"offset" query:"offset"`
+	Source    string `json:"source" query:"source"`
+	Principal string `json:"principal" query:"principal"`
+	DeviceID  string `json:"device_id" query:"device_id"`
+	Action    string `json:"action" query:"action"`
+	TargetJID string `json:"target_jid" query:"target_jid"`
+	MessageID string `json:"message_id" query:"message_id"`
+	Result    string `json:"result" query:"result"`
+	// Since/Until are RFC3339 timestamps bounding the entry time (inclusive).
+	Since string `json:"since" query:"since"`
+	Until string `json:"until" query:"until"`
+}
+
+type ListAuditResponse struct {
+	Data       []Entry            `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type Entry struct {
+	ID           int64     `json:"id"`
+	Timestamp    string    `json:"timestamp"`
+	Source       string    `json:"source"`
+	Principal    Principal `json:"principal"`
+	DeviceID     string    `json:"device_id,omitempty"`
+	Action       string    `json:"action"`
+	TargetJID    string    `json:"target_jid,omitempty"`
+	MessageID    string    `json:"message_id,omitempty"`
+	Result       string    `json:"result"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
@@ -4,9 +4,49 @@ import "context"
 
 type ICallUsecase interface {
 	RejectCall(ctx context.Context, callerJID string, callID string) error
+	ListCalls(ctx context.Context, request ListCallsRequest) (response ListCallsResponse, err error)
 }
 
 type RejectCallRequest struct {
 	CallerJID string `json:"caller_jid" form:"caller_jid"`
 	CallID    string `json:"call_id" form:"call_id"`
 }
+
+type ListCallsRequest struct {
+	Limit  int `json:"limit" query:"limit"`
+	Offset int `json:"offset" query:"offset"`
+	// ChatJID narrows the history to one caller or group.
+	ChatJID string `json:"chat_jid" query:"chat_jid"`
+	Status  string `json:"status" query:"status"`
+	// Since/Until are RFC3339 timestamps bounding the offer time (inclusive).
+	Since string `json:"since" query:"since"`
+	Until string `json:"until" query:"until"`
+}
+
+type ListCallsResponse struct {
+	Data       []CallInfo         `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type CallInfo struct {
+	CallID          string `json:"call_id"`
+	ChatJID         string `json:"chat_jid"`
+	CallerJID       string `json:"caller_jid"`
+	GroupJID        string `json:"group_jid,omitempty"`
+	IsVideo         bool   `json:"is_video"`
+	Status          string `json:"status"`
+	AutoRejected    bool   `json:"auto_rejected"`
+	RemotePlatform  string `json:"remote_platform,omitempty"`
+	RemoteVersion   string `json:"remote_version,omitempty"`
+	EndReason       string `json:"end_reason,omitempty"`
+	OfferedAt       string `json:"offered_at"`
+	AcceptedAt      string `json:"accepted_at,omitempty"`
+	EndedAt         string `json:"ended_at,omitempty"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
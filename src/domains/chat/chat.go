@@ -99,6 +99,23 @@ type ArchiveChatRequest struct {
 	Archived bool   `json:"archived"`
 }
 
+// RequestHistoryRequest asks the phone for up to Count messages older than
+// BeforeMessageID, or older than the oldest stored message when it is empty.
+type RequestHistoryRequest struct {
+	ChatJID         string `json:"chat_jid" uri:"chat_jid"`
+	BeforeMessageID string `json:"before_message_id"`
+	Count           int    `json:"count"`
+}
+
+type RequestHistoryResponse struct {
+	RequestID       string `json:"request_id"`
+	ChatJID         string `json:"chat_jid"`
+	BeforeMessageID string `json:"before_message_id"`
+	Count           int    `json:"count"`
+	Status          string `json:"status"`
+	RequestedAt     string `json:"requested_at"`
+}
+
 type ArchiveChatResponse struct {
 	Status   string `json:"status"`
 	Message  string `json:"message"`
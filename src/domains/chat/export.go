@@ -0,0 +1,50 @@
+package chat
+
+// Formats accepted by ExportChat.
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+	ExportFormatHTML = "html"
+	// ExportFormatText is WhatsApp's own "Export chat" transcript format.
+	ExportFormatText = "txt"
+)
+
+// ExportChatRequest selects what ExportChat writes. An empty ChatJID exports
+// every chat of the device.
+type ExportChatRequest struct {
+	ChatJID   string `json:"chat_jid" uri:"chat_jid"`
+	Format    string `json:"format" query:"format"`
+	StartTime string `json:"start_time" query:"start_time"`
+	EndTime   string `json:"end_time" query:"end_time"`
+	// Timezone is the IANA zone used for timestamps in the csv, html and txt
+	// formats (default UTC). JSON timestamps are always RFC3339.
+	Timezone string `json:"timezone" query:"timezone"`
+	// IncludeMedia downloads every media message and bundles it with the
+	// transcript into a zip archive.
+	IncludeMedia bool `json:"include_media" query:"include_media"`
+}
+
+// ExportChatResponse describes the document ExportChat wrote.
+type ExportChatResponse struct {
+	Filename     string `json:"filename"`
+	ContentType  string `json:"content_type"`
+	ChatCount    int    `json:"chat_count"`
+	MessageCount int    `json:"message_count"`
+	MediaCount   int    `json:"media_count"`
+}
+
+// ExportMessage is a message as written by ExportChat: the stored message plus
+// its edit history and, in zip bundles, the path of its downloaded media.
+type ExportMessage struct {
+	MessageInfo
+	Edits     []MessageEditInfo `json:"edits,omitempty"`
+	MediaFile string            `json:"media_file,omitempty"`
+}
+
+// MessageEditInfo is one edit applied to an exported message.
+type MessageEditInfo struct {
+	EditorJID       string `json:"editor_jid"`
+	PreviousContent string `json:"previous_content"`
+	NewContent      string `json:"new_content"`
+	EditedAt        string `json:"edited_at"`
+}
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"context"
+	"io"
 )
 
 // IChatUsecase defines the interface for chat-related operations
@@ -11,4 +12,10 @@ type IChatUsecase interface {
 	PinChat(ctx context.Context, request PinChatRequest) (response PinChatResponse, err error)
 	SetDisappearingTimer(ctx context.Context, request SetDisappearingTimerRequest) (response SetDisappearingTimerResponse, err error)
 	ArchiveChat(ctx context.Context, request ArchiveChatRequest) (response ArchiveChatResponse, err error)
+	// RequestHistory asks the primary phone for older messages of a chat. The
+	// messages are stored when the phone answers, asynchronously.
+	RequestHistory(ctx context.Context, request RequestHistoryRequest) (response RequestHistoryResponse, err error)
+	// ExportChat writes one chat, or every chat of the device, to w in the
+	// requested format (a zip archive when media is included).
+	ExportChat(ctx context.Context, request ExportChatRequest, w io.Writer) (response ExportChatResponse, err error)
 }
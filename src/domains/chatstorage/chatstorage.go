@@ -81,17 +81,22 @@ type ChatwootMessageLink struct {
 // inbox. DeviceID is the user-facing device id; DeviceJID mirrors the WhatsApp
 // storage JID so the registry can resolve a client from either identity (the
 // forward/link paths key on the JID, the REST/reverse paths on the device id).
+// LabelSync, ArchiveOnResolve and ClosingMessage control how conversation
+// labels and status changes are mirrored back to the WhatsApp chat.
 type ChatwootDeviceConfig struct {
-	ID          int64     `db:"id"`
-	DeviceID    string    `db:"device_id"`
-	DeviceJID   string    `db:"device_jid"`
-	ChatwootURL string    `db:"chatwoot_url"`
-	AccountID   int       `db:"account_id"`
-	InboxID     int       `db:"inbox_id"`
-	APIToken    string    `db:"api_token"`
-	Enabled     bool      `db:"enabled"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID               int64     `db:"id"`
+	DeviceID         string    `db:"device_id"`
+	DeviceJID        string    `db:"device_jid"`
+	ChatwootURL      string    `db:"chatwoot_url"`
+	AccountID        int       `db:"account_id"`
+	InboxID          int       `db:"inbox_id"`
+	APIToken         string    `db:"api_token"`
+	Enabled          bool      `db:"enabled"`
+	LabelSync        bool      `db:"label_sync"`
+	ArchiveOnResolve bool      `db:"archive_on_resolve"`
+	ClosingMessage   string    `db:"closing_message"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
 }
 
 // ChatwootForwardEvent is a durable retry record for a live WhatsApp event
@@ -105,10 +110,89 @@ type ChatwootForwardEvent struct {
 	Attempts          int       `db:"attempts"`
 	LastError         string    `db:"last_error"`
 	NextAttemptAt     time.Time `db:"next_attempt_at"`
+	TraceParent       string    `db:"trace_parent"` // W3C traceparent of the forward that queued the retry
 	CreatedAt         time.Time `db:"created_at"`
 	UpdatedAt         time.Time `db:"updated_at"`
 }
 
+// AuditEntry is one append-only audit record for a mutating REST or MCP call.
+// Rows are never updated or deleted by the application, including on device
+// removal or chat truncation.
+type AuditEntry struct {
+	ID            int64     `db:"id"`
+	Source        string    `db:"source"`         // "rest" or "mcp"
+	PrincipalType string    `db:"principal_type"` // "basic", "oauth" or "anonymous"
+	Principal     string    `db:"principal"`
+	ClientID      string    `db:"client_id"`
+	DeviceID      string    `db:"device_id"`
+	Action        string    `db:"action"`
+	TargetJID     string    `db:"target_jid"`
+	MessageID     string    `db:"message_id"`
+	Result        string    `db:"result"` // "success" or "error"
+	StatusCode    int       `db:"status_code"`
+	ErrorMessage  string    `db:"error_message"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// GroupMetadata is the cached state of a group as a device last saw it,
+// kept current from group events so lookups do not have to ask WhatsApp.
+// UpdatedAt is when the snapshot was last fetched; patches from events keep
+// it, so an old snapshot still expires and is fetched again.
+type GroupMetadata struct {
+	DeviceID         string                     `db:"device_id"`
+	GroupJID         string                     `db:"group_jid"`
+	Name             string                     `db:"name"`
+	Topic            string                     `db:"topic"`
+	OwnerJID         string                     `db:"owner_jid"`
+	IsLocked         bool                       `db:"is_locked"`
+	IsAnnounce       bool                       `db:"is_announce"`
+	IsEphemeral      bool                       `db:"is_ephemeral"`
+	EphemeralSeconds uint32                     `db:"ephemeral_seconds"`
+	JoinApproval     bool                       `db:"join_approval"`
+	MemberAddMode    string                     `db:"member_add_mode"`
+	IsParent         bool                       `db:"is_parent"`
+	LinkedParentJID  string                     `db:"linked_parent_jid"`
+	GroupCreated     time.Time                  `db:"group_created"`
+	Participants     []GroupMetadataParticipant `db:"participants_json"`
+	UpdatedAt        time.Time                  `db:"updated_at"`
+}
+
+// GroupMetadataParticipant is one member of a cached group with its role.
+type GroupMetadataParticipant struct {
+	JID          string `json:"jid"`
+	PhoneNumber  string `json:"phone_number,omitempty"`
+	LID          string `json:"lid,omitempty"`
+	DisplayName  string `json:"display_name,omitempty"`
+	IsAdmin      bool   `json:"is_admin"`
+	IsSuperAdmin bool   `json:"is_super_admin"`
+}
+
+// GroupParticipantEvent is one append-only join, leave, promote or demote
+// record. ActorJID is empty when the server made the change on its own.
+type GroupParticipantEvent struct {
+	ID             int64     `db:"id"`
+	DeviceID       string    `db:"device_id"`
+	GroupJID       string    `db:"group_jid"`
+	ParticipantJID string    `db:"participant_jid"`
+	Action         string    `db:"action"`
+	ActorJID       string    `db:"actor_jid"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// GroupDescriptionEvent is one append-only group description change.
+// Deleted marks the description being cleared; ActorJID is empty when
+// WhatsApp does not report who made the change.
+type GroupDescriptionEvent struct {
+	ID          int64     `db:"id"`
+	DeviceID    string    `db:"device_id"`
+	GroupJID    string    `db:"group_jid"`
+	Description string    `db:"description"`
+	TopicID     string    `db:"topic_id"`
+	Deleted     bool      `db:"deleted"`
+	ActorJID    string    `db:"actor_jid"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
 // MediaInfo represents downloadable media information
 type MediaInfo struct {
 	MessageID     string
@@ -137,6 +221,16 @@ type DeviceRecord struct {
 	WebhookSecret             string    `db:"webhook_secret"`
 	WebhookEvents             string    `db:"webhook_events"`
 	WebhookInsecureSkipVerify bool      `db:"webhook_insecure_skip_verify"`
+	WebhookMethod             string    `db:"webhook_method"`
+	WebhookHeaders            string    `db:"webhook_headers"`
+	WebhookTemplate           string    `db:"webhook_template"`
+	WebhookSignatureAlgorithm string    `db:"webhook_signature_algorithm"`
+	CallAutoReject            *bool     `db:"call_auto_reject"`
+	CallRejectMessage         string    `db:"call_reject_message"`
+	ProxyURL                  string    `db:"proxy_url"`
+	ProxyPool                 string    `db:"proxy_pool"`
+	Tags                      string    `db:"tags"`
+	Metadata                  string    `db:"metadata"`
 	CreatedAt                 time.Time `db:"created_at"`
 	UpdatedAt                 time.Time `db:"updated_at"`
 }
@@ -147,6 +241,49 @@ type DeviceWebhookConfig struct {
 	WebhookSecret             string  `json:"webhook_secret,omitempty"`
 	WebhookEvents             string  `json:"webhook_events,omitempty"`
 	WebhookInsecureSkipVerify bool    `json:"webhook_insecure_skip_verify,omitempty"`
+	// WebhookMethod is the HTTP method of each delivery: POST (default), PUT or PATCH.
+	WebhookMethod string `json:"webhook_method,omitempty"`
+	// WebhookHeaders are static headers sent with every delivery, such as an
+	// Authorization bearer token expected by the receiver.
+	WebhookHeaders map[string]string `json:"webhook_headers,omitempty"`
+	// WebhookTemplate is a Go text/template rendered with the event payload to
+	// build the request body. Empty sends the payload itself as JSON.
+	WebhookTemplate string `json:"webhook_template,omitempty"`
+	// WebhookSignatureAlgorithm is the HMAC hash of the body signature: sha256
+	// (default), sha1, sha512, or none to send no signature.
+	WebhookSignatureAlgorithm string `json:"webhook_signature_algorithm,omitempty"`
+}
+
+// Webhook signature algorithms of DeviceWebhookConfig.WebhookSignatureAlgorithm.
+const (
+	WebhookSignatureSHA256 = "sha256"
+	WebhookSignatureSHA1   = "sha1"
+	WebhookSignatureSHA512 = "sha512"
+	WebhookSignatureNone   = "none"
+)
+
+// DeviceCallConfig holds the incoming-call handling of a device. A nil
+// AutoReject falls back to the global auto-reject setting; an empty
+// RejectMessage falls back to the global reject message.
+type DeviceCallConfig struct {
+	AutoReject    *bool  `json:"auto_reject"`
+	RejectMessage string `json:"reject_message"`
+}
+
+// DeviceProxyConfig holds the outbound proxy of a device. An empty ProxyURL
+// and Pool fall back to the global WHATSAPP_PROXY. Pool lists fallback
+// proxies tried in order once the active one stops connecting.
+type DeviceProxyConfig struct {
+	ProxyURL string   `json:"proxy_url"`
+	Pool     []string `json:"proxy_pool"`
+}
+
+// DeviceAttributes holds the operator-assigned labels of a device: free-form
+// tags used to group and filter devices, and a string metadata map (customer,
+// team, ...). Neither affects how the device connects.
+type DeviceAttributes struct {
+	Tags     []string          `json:"tags"`
+	Metadata map[string]string `json:"metadata"`
 }
 
 // MessageFilter represents query filters for messages
@@ -159,6 +296,8 @@ type MessageFilter struct {
 	EndTime   *time.Time
 	MediaOnly bool
 	IsFromMe  *bool
+	// OldestFirst orders by timestamp ascending instead of newest first.
+	OldestFirst bool
 }
 
 // ChatFilter represents query filters for chats
@@ -170,3 +309,132 @@ type ChatFilter struct {
 	HasMedia   bool
 	IsArchived *bool
 }
+
+// AuditFilter represents query filters for audit entries. Empty fields match
+// everything; Since/Until bound created_at inclusively.
+type AuditFilter struct {
+	Source    string
+	Principal string
+	DeviceID  string
+	Action    string
+	TargetJID string
+	MessageID string
+	Result    string
+	Since     *time.Time
+	Until     *time.Time
+	Limit     int
+	Offset    int
+}
+
+// GroupParticipantEventFilter represents query filters for group participant
+// history. Empty fields match everything.
+type GroupParticipantEventFilter struct {
+	DeviceID       string
+	GroupJID       string
+	ParticipantJID string
+	Action         string
+	Limit          int
+	Offset         int
+}
+
+// GroupDescriptionEventFilter represents query filters for group description
+// history. Empty fields match everything.
+type GroupDescriptionEventFilter struct {
+	DeviceID string
+	GroupJID string
+	Limit    int
+	Offset   int
+}
+
+// Call record statuses, in lifecycle order.
+const (
+	CallStatusRinging  = "ringing"
+	CallStatusAccepted = "accepted"
+	CallStatusRejected = "rejected"
+	CallStatusMissed   = "missed"
+	CallStatusEnded    = "ended"
+)
+
+// CallRecord tracks one incoming call from offer to termination.
+type CallRecord struct {
+	DeviceID        string     `json:"device_id"`
+	CallID          string     `json:"call_id"`
+	ChatJID         string     `json:"chat_jid"`
+	CallerJID       string     `json:"caller_jid"`
+	GroupJID        string     `json:"group_jid,omitempty"`
+	IsVideo         bool       `json:"is_video"`
+	Status          string     `json:"status"`
+	AutoRejected    bool       `json:"auto_rejected"`
+	RemotePlatform  string     `json:"remote_platform,omitempty"`
+	RemoteVersion   string     `json:"remote_version,omitempty"`
+	EndReason       string     `json:"end_reason,omitempty"`
+	OfferedAt       time.Time  `json:"offered_at"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationSeconds int        `json:"duration_seconds"`
+}
+
+// CallRecordFilter selects call records; zero values are ignored.
+type CallRecordFilter struct {
+	DeviceID string
+	ChatJID  string
+	Status   string
+	Since    *time.Time
+	Until    *time.Time
+	Limit    int
+	Offset   int
+}
+
+// Contact is one entry of a device's contact book. Names, avatar and last
+// interaction are kept current from WhatsApp events; Tags and Notes are set
+// through the API only.
+type Contact struct {
+	DeviceID          string     `json:"device_id"`
+	JID               string     `json:"jid"`
+	Phone             string     `json:"phone"`
+	LID               string     `json:"lid"`
+	PushName          string     `json:"push_name"`
+	BusinessName      string     `json:"business_name"`
+	SavedName         string     `json:"saved_name"`
+	AvatarURL         string     `json:"avatar_url"`
+	LastInteractionAt *time.Time `json:"last_interaction_at"`
+	Tags              []string   `json:"tags"`
+	Notes             string     `json:"notes"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// ContactFilter selects contacts; zero values are ignored. Search matches the
+// JID, phone, names and notes; Tag requires an exact tag.
+type ContactFilter struct {
+	DeviceID string
+	Search   string
+	Tag      string
+	Limit    int
+	Offset   int
+}
+
+// Presence is the last known presence of a user as seen by a device. Online
+// state comes from presence updates, which WhatsApp only sends for subscribed
+// users; ChatState is the latest typing state the user sent in ChatJID.
+type Presence struct {
+	DeviceID    string     `json:"device_id"`
+	JID         string     `json:"jid"`
+	Available   bool       `json:"available"`
+	LastSeenAt  *time.Time `json:"last_seen_at"`
+	ChatJID     string     `json:"chat_jid"`
+	ChatState   string     `json:"chat_state"`
+	ChatMedia   string     `json:"chat_media"`
+	ChatStateAt *time.Time `json:"chat_state_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Label is a WhatsApp Business chat label as last seen in app state updates.
+type Label struct {
+	DeviceID  string    `json:"device_id"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     int32     `json:"color"`
+	Deleted   bool      `json:"deleted"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
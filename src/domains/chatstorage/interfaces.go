@@ -112,6 +112,84 @@ type IChatStorageRepository interface {
 	SetDeviceWebhookConfig(deviceID string, config *DeviceWebhookConfig) error
 	// GetDeviceWebhookConfig retrieves the full webhook configuration for a device.
 	GetDeviceWebhookConfig(deviceID string) (*DeviceWebhookConfig, error)
+	// SetDeviceCallConfig sets the incoming-call handling for a device.
+	SetDeviceCallConfig(deviceID string, config *DeviceCallConfig) error
+	// GetDeviceCallConfig returns nil when the device does not exist.
+	GetDeviceCallConfig(deviceID string) (*DeviceCallConfig, error)
+	// SetDeviceProxyConfig sets the outbound proxy and fallback pool for a device.
+	SetDeviceProxyConfig(deviceID string, config *DeviceProxyConfig) error
+	// GetDeviceProxyConfig returns nil when the device does not exist.
+	GetDeviceProxyConfig(deviceID string) (*DeviceProxyConfig, error)
+	// SetDeviceAttributes replaces the tags and metadata of a device.
+	SetDeviceAttributes(deviceID string, attributes *DeviceAttributes) error
+	// GetDeviceAttributes returns nil when the device does not exist.
+	GetDeviceAttributes(deviceID string) (*DeviceAttributes, error)
+
+	// Audit log (append-only)
+	StoreAuditEntry(entry *AuditEntry) error
+	// GetAuditEntries returns entries newest first. A zero Limit returns every
+	// matching row, which the JSON-lines export relies on.
+	GetAuditEntries(filter *AuditFilter) ([]*AuditEntry, error)
+	CountAuditEntries(filter *AuditFilter) (int64, error)
+
+	// Group metadata cache, participant and description history
+	SaveGroupMetadata(metadata *GroupMetadata) error
+	// GetGroupMetadata returns nil when the group has not been cached yet.
+	GetGroupMetadata(deviceID, groupJID string) (*GroupMetadata, error)
+	DeleteGroupMetadata(deviceID, groupJID string) error
+	StoreGroupParticipantEvents(events []*GroupParticipantEvent) error
+	// GetGroupParticipantEvents returns events newest first.
+	GetGroupParticipantEvents(filter *GroupParticipantEventFilter) ([]*GroupParticipantEvent, error)
+	CountGroupParticipantEvents(filter *GroupParticipantEventFilter) (int64, error)
+	StoreGroupDescriptionEvent(event *GroupDescriptionEvent) error
+	// GetGroupDescriptionEvents returns events newest first.
+	GetGroupDescriptionEvents(filter *GroupDescriptionEventFilter) ([]*GroupDescriptionEvent, error)
+	CountGroupDescriptionEvents(filter *GroupDescriptionEventFilter) (int64, error)
+
+	// Call history
+	SaveCallRecord(record *CallRecord) error
+	// GetCallRecord returns nil when the call is unknown.
+	GetCallRecord(deviceID, callID string) (*CallRecord, error)
+	// GetCallRecords returns records newest offer first.
+	GetCallRecords(filter *CallRecordFilter) ([]*CallRecord, error)
+	CountCallRecords(filter *CallRecordFilter) (int64, error)
+
+	// Contact book
+	// UpsertContact merges contact into the stored entry: empty names keep the
+	// stored value and LastInteractionAt only moves forward. Tags, notes and
+	// the avatar are left untouched.
+	UpsertContact(contact *Contact) error
+	// SetContactAvatar stores the contact's current avatar URL ("" when removed).
+	SetContactAvatar(deviceID, jid, avatarURL string) error
+	// UpdateContactAnnotations replaces the tags and/or notes of a contact,
+	// creating the entry when needed. A nil argument leaves that field as is.
+	UpdateContactAnnotations(deviceID, jid string, tags []string, notes *string) error
+	// GetContact returns nil when the contact is unknown.
+	GetContact(deviceID, jid string) (*Contact, error)
+	// GetContacts returns contacts with the most recent interaction first.
+	GetContacts(filter *ContactFilter) ([]*Contact, error)
+	CountContacts(filter *ContactFilter) (int64, error)
+
+	// Presence
+	// SavePresence replaces the stored presence of presence.JID.
+	SavePresence(presence *Presence) error
+	// GetPresence returns nil when nothing is known about the user.
+	GetPresence(deviceID, jid string) (*Presence, error)
+	// AddPresenceSubscription remembers a subscription so it survives reconnects.
+	AddPresenceSubscription(deviceID, jid string) error
+	RemovePresenceSubscription(deviceID, jid string) error
+	HasPresenceSubscription(deviceID, jid string) (bool, error)
+	GetPresenceSubscriptions(deviceID string) ([]string, error)
+
+	// WhatsApp Business labels
+	// SaveLabel replaces the stored name, color and deleted flag of a label.
+	SaveLabel(label *Label) error
+	// GetLabels returns the device's labels, deleted ones included.
+	GetLabels(deviceID string) ([]*Label, error)
+	// SetChatLabel records whether labelID is applied to chatJID.
+	SetChatLabel(deviceID, chatJID, labelID string, labeled bool) error
+	// GetChatLabelIDs returns the ids of the labels applied to chatJID.
+	GetChatLabelIDs(deviceID, chatJID string) ([]string, error)
 
 	// Schema operations
 	InitializeSchema() error
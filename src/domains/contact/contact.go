@@ -0,0 +1,51 @@
+package contact
+
+type ListContactsRequest struct {
+	Limit  int `json:"limit" query:"limit"`
+	Offset int `json:"offset" query:"offset"`
+	// Search matches the JID, phone number, names and notes (case-insensitive).
+	Search string `json:"search" query:"search"`
+	Tag    string `json:"tag" query:"tag"`
+}
+
+type ListContactsResponse struct {
+	Data       []Contact          `json:"data"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+type GetContactRequest struct {
+	JID string `json:"jid" uri:"jid"`
+}
+
+// UpdateContactRequest changes only the fields that are present: nil Tags or
+// Notes leave them as they are, an empty list or string clears them.
+type UpdateContactRequest struct {
+	JID   string    `json:"jid" uri:"jid"`
+	Tags  *[]string `json:"tags"`
+	Notes *string   `json:"notes"`
+}
+
+type Contact struct {
+	JID               string   `json:"jid"`
+	Phone             string   `json:"phone"`
+	LID               string   `json:"lid,omitempty"`
+	Name              string   `json:"name"`
+	PushName          string   `json:"push_name"`
+	BusinessName      string   `json:"business_name"`
+	SavedName         string   `json:"saved_name"`
+	AvatarURL         string   `json:"avatar_url"`
+	LastInteractionAt string   `json:"last_interaction_at,omitempty"`
+	Tags              []string `json:"tags"`
+	Notes             string   `json:"notes"`
+	UpdatedAt         string   `json:"updated_at"`
+}
+
+type SyncContactsResponse struct {
+	Synced int `json:"synced"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
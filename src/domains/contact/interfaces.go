@@ -0,0 +1,13 @@
+package contact
+
+import "context"
+
+// IContactUsecase manages the device's contact book: entries kept current from
+// WhatsApp events, plus the tags and notes teams attach to them.
+type IContactUsecase interface {
+	ListContacts(ctx context.Context, request ListContactsRequest) (response ListContactsResponse, err error)
+	GetContact(ctx context.Context, request GetContactRequest) (response Contact, err error)
+	UpdateContact(ctx context.Context, request UpdateContactRequest) (response Contact, err error)
+	// SyncContacts imports the WhatsApp address book into the contact book.
+	SyncContacts(ctx context.Context) (response SyncContactsResponse, err error)
+}
@@ -20,5 +20,53 @@ type Device struct {
 	DisplayName string      `json:"display_name,omitempty"`
 	State       DeviceState `json:"state"`
 	JID         string      `json:"jid,omitempty"`
-	CreatedAt   time.Time   `json:"created_at"`
+	// Proxy is the outbound proxy the device currently connects through,
+	// with credentials masked. Empty means a direct connection.
+	Proxy string `json:"proxy,omitempty"`
+	// Tags and Metadata are operator-assigned labels (customer, team, ...)
+	// set through PATCH /devices/{device_id}.
+	Tags      []string          `json:"tags,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// DeviceFilter narrows a device listing. Empty fields match every device.
+type DeviceFilter struct {
+	// Tags lists tags a device must all carry (case-insensitive).
+	Tags []string
+	// State matches the device's current state.
+	State DeviceState
+	// Search is a case-insensitive substring of the device id, display name,
+	// phone number, JID, a tag or a metadata value.
+	Search string
+}
+
+// UpdateDeviceRequest changes a device's tags and metadata. A nil field is
+// left unchanged; an empty one clears it.
+type UpdateDeviceRequest struct {
+	Tags     []string          `json:"tags"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// DeviceHealth summarises a device's connection state and recent connection
+// history for monitoring. Timestamps are omitted until the event first occurs.
+type DeviceHealth struct {
+	ID                 string      `json:"id"`
+	JID                string      `json:"jid,omitempty"`
+	State              DeviceState `json:"state"`
+	IsConnected        bool        `json:"is_connected"`
+	IsLoggedIn         bool        `json:"is_logged_in"`
+	LastConnectedAt    *time.Time  `json:"last_connected_at,omitempty"`
+	LastDisconnectedAt *time.Time  `json:"last_disconnected_at,omitempty"`
+	ReconnectAttempts  int         `json:"reconnect_attempts"`
+	LastError          string      `json:"last_error,omitempty"`
+	LastErrorAt        *time.Time  `json:"last_error_at,omitempty"`
+}
+
+// DevicesHealth is the fleet-wide health summary returned by /devices/health.
+type DevicesHealth struct {
+	Total     int            `json:"total"`
+	Connected int            `json:"connected"`
+	LoggedIn  int            `json:"logged_in"`
+	Devices   []DeviceHealth `json:"devices"`
 }
@@ -9,8 +9,11 @@ import (
 
 // IDeviceUsecase defines device lifecycle operations.
 type IDeviceUsecase interface {
-	ListDevices(ctx context.Context) ([]Device, error)
+	// ListDevices returns the devices matching filter.
+	ListDevices(ctx context.Context, filter DeviceFilter) ([]Device, error)
 	GetDevice(ctx context.Context, deviceID string) (*Device, error)
+	// UpdateDevice sets a device's tags and metadata.
+	UpdateDevice(ctx context.Context, deviceID string, request UpdateDeviceRequest) (*Device, error)
 	// AddDevice creates a new device. A non-nil webhook applies the full device-specific
 	// webhook configuration (url, secret, events, insecure_skip_verify) at creation time.
 	AddDevice(ctx context.Context, deviceID string, webhook *chatstorage.DeviceWebhookConfig) (*Device, error)
@@ -20,6 +23,8 @@ type IDeviceUsecase interface {
 	LogoutDevice(ctx context.Context, deviceID string) error
 	ReconnectDevice(ctx context.Context, deviceID string) error
 	GetStatus(ctx context.Context, deviceID string) (isConnected bool, isLoggedIn bool, err error)
+	// GetDevicesHealth summarises connection state and history for every device.
+	GetDevicesHealth(ctx context.Context) (DevicesHealth, error)
 	// SetDeviceWebhook sets the webhook URL for a specific device.
 	SetDeviceWebhook(ctx context.Context, deviceID string, webhookURL string) error
 	// GetDeviceWebhook retrieves the webhook URL for a specific device.
@@ -28,4 +33,18 @@ type IDeviceUsecase interface {
 	SetDeviceWebhookConfig(ctx context.Context, deviceID string, config *chatstorage.DeviceWebhookConfig) error
 	// GetDeviceWebhookConfig retrieves the complete webhook configuration for a specific device.
 	GetDeviceWebhookConfig(ctx context.Context, deviceID string) (*chatstorage.DeviceWebhookConfig, error)
+	// SetDeviceCallConfig sets how a specific device handles incoming calls.
+	SetDeviceCallConfig(ctx context.Context, deviceID string, config *chatstorage.DeviceCallConfig) error
+	// GetDeviceCallConfig retrieves how a specific device handles incoming calls.
+	GetDeviceCallConfig(ctx context.Context, deviceID string) (*chatstorage.DeviceCallConfig, error)
+	// SetDeviceProxyConfig sets a device's outbound proxy and fallback pool and
+	// applies them to its client.
+	SetDeviceProxyConfig(ctx context.Context, deviceID string, config *chatstorage.DeviceProxyConfig) error
+	// GetDeviceProxyConfig retrieves a device's outbound proxy and fallback pool.
+	GetDeviceProxyConfig(ctx context.Context, deviceID string) (*chatstorage.DeviceProxyConfig, error)
+	// ExportDeviceBackup returns a device's session and settings encrypted with passphrase.
+	ExportDeviceBackup(ctx context.Context, deviceID string, passphrase string) ([]byte, error)
+	// ImportDeviceBackup restores a backup as a device slot, named deviceID when
+	// given. The restored device is not connected.
+	ImportDeviceBackup(ctx context.Context, backup []byte, passphrase string, deviceID string) (*Device, error)
 }
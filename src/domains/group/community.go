@@ -0,0 +1,55 @@
+package group
+
+type CreateCommunityRequest struct {
+	Name         string   `json:"name" form:"name"`
+	Description  string   `json:"description" form:"description"`
+	Participants []string `json:"participants" form:"participants"`
+}
+
+type CommunityGroupRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+	GroupID     string `json:"group_id" form:"group_id"`
+}
+
+type GetCommunityGroupsRequest struct {
+	CommunityID string `json:"community_id" query:"community_id"`
+}
+
+type CommunityGroup struct {
+	JID            string `json:"jid"`
+	Name           string `json:"name"`
+	IsAnnouncement bool   `json:"is_announcement"`
+}
+
+type GetCommunityGroupsResponse struct {
+	CommunityID string           `json:"community_id"`
+	Name        string           `json:"name"`
+	Groups      []CommunityGroup `json:"groups"`
+}
+
+type GetCommunityParticipantsRequest struct {
+	CommunityID string `json:"community_id" query:"community_id"`
+}
+
+type CommunityParticipant struct {
+	JID         string `json:"jid"`
+	PhoneNumber string `json:"phone_number"`
+	DisplayName string `json:"display_name,omitempty"`
+}
+
+type GetCommunityParticipantsResponse struct {
+	CommunityID  string                 `json:"community_id"`
+	Participants []CommunityParticipant `json:"participants"`
+}
+
+type CommunityAnnouncementRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+	Message     string `json:"message" form:"message"`
+}
+
+type CommunityAnnouncementResponse struct {
+	CommunityID         string `json:"community_id"`
+	AnnouncementGroupID string `json:"announcement_group_id"`
+	MessageID           string `json:"message_id"`
+	Status              string `json:"status"`
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 // NOTE: IGroupUsecase is now defined in interfaces.go with proper segregation
@@ -20,6 +21,8 @@ type LeaveGroupRequest struct {
 type CreateGroupRequest struct {
 	Title        string   `json:"title" form:"title"`
 	Participants []string `json:"participants" form:"participants"`
+	// CommunityID creates the group inside an existing community when set.
+	CommunityID string `json:"community_id,omitempty" form:"community_id"`
 }
 
 type ParticipantRequest struct {
@@ -36,6 +39,8 @@ type ParticipantStatus struct {
 
 type GetGroupParticipantsRequest struct {
 	GroupID string `json:"group_id" query:"group_id"`
+	// Refresh bypasses the cached group and fetches it from WhatsApp.
+	Refresh bool `json:"refresh" query:"refresh"`
 }
 
 type GroupParticipant struct {
@@ -53,6 +58,63 @@ type GetGroupParticipantsResponse struct {
 	Participants []GroupParticipant `json:"participants"`
 }
 
+// Participant history actions, as recorded from group events.
+const (
+	ParticipantActionJoin    = "join"
+	ParticipantActionLeave   = "leave"
+	ParticipantActionPromote = "promote"
+	ParticipantActionDemote  = "demote"
+)
+
+type GetGroupParticipantHistoryRequest struct {
+	GroupID     string `json:"group_id" query:"group_id"`
+	Participant string `json:"participant" query:"participant"`
+	Action      string `json:"action" query:"action"`
+	Limit       int    `json:"limit" query:"limit"`
+	Offset      int    `json:"offset" query:"offset"`
+}
+
+type GroupParticipantHistoryEntry struct {
+	ID          int64  `json:"id"`
+	Participant string `json:"participant"`
+	Action      string `json:"action"`
+	ChangedBy   string `json:"changed_by,omitempty"`
+	Timestamp   string `json:"timestamp"`
+}
+
+type GetGroupParticipantHistoryResponse struct {
+	GroupID    string                         `json:"group_id"`
+	Data       []GroupParticipantHistoryEntry `json:"data"`
+	Pagination PaginationResponse             `json:"pagination"`
+}
+
+type GetGroupDescriptionHistoryRequest struct {
+	GroupID string `json:"group_id" query:"group_id"`
+	Limit   int    `json:"limit" query:"limit"`
+	Offset  int    `json:"offset" query:"offset"`
+}
+
+type GroupDescriptionHistoryEntry struct {
+	ID            int64  `json:"id"`
+	Description   string `json:"description"`
+	DescriptionID string `json:"description_id,omitempty"`
+	Deleted       bool   `json:"deleted"`
+	ChangedBy     string `json:"changed_by,omitempty"`
+	Timestamp     string `json:"timestamp"`
+}
+
+type GetGroupDescriptionHistoryResponse struct {
+	GroupID    string                         `json:"group_id"`
+	Data       []GroupDescriptionHistoryEntry `json:"data"`
+	Pagination PaginationResponse             `json:"pagination"`
+}
+
+type PaginationResponse struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
 type GetGroupRequestParticipantsRequest struct {
 	GroupID string `json:"group_id" query:"group_id"`
 }
@@ -100,6 +162,23 @@ type SetGroupTopicRequest struct {
 	Topic   string `json:"topic" form:"topic"`
 }
 
+type SetGroupMemberAddModeRequest struct {
+	GroupID string                   `json:"group_id" form:"group_id"`
+	Mode    types.GroupMemberAddMode `json:"mode" form:"mode"`
+}
+
+type SetGroupJoinApprovalRequest struct {
+	GroupID string `json:"group_id" form:"group_id"`
+	Enabled bool   `json:"enabled" form:"enabled"`
+}
+
+// SetGroupEphemeralRequest sets the group's default disappearing-message timer;
+// 0 turns it off.
+type SetGroupEphemeralRequest struct {
+	GroupID      string `json:"group_id" form:"group_id"`
+	TimerSeconds uint32 `json:"timer_seconds" form:"timer_seconds"`
+}
+
 type GetGroupInfoFromLinkRequest struct {
 	Link string `json:"link" form:"link"`
 }
@@ -118,6 +197,8 @@ type GetGroupInfoFromLinkResponse struct {
 
 type GroupInfoRequest struct {
 	GroupID string `json:"group_id" query:"group_id"`
+	// Refresh bypasses the cached group and fetches it from WhatsApp.
+	Refresh bool `json:"refresh" query:"refresh"`
 }
 
 type GetGroupInviteLinkRequest struct {
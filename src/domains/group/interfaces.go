@@ -18,6 +18,7 @@ type IGroupManagement interface {
 type IGroupParticipants interface {
 	ManageParticipant(ctx context.Context, request ParticipantRequest) (result []ParticipantStatus, err error)
 	GetGroupParticipants(ctx context.Context, request GetGroupParticipantsRequest) (response GetGroupParticipantsResponse, err error)
+	GetGroupParticipantHistory(ctx context.Context, request GetGroupParticipantHistoryRequest) (response GetGroupParticipantHistoryResponse, err error)
 	GetGroupRequestParticipants(ctx context.Context, request GetGroupRequestParticipantsRequest) (result []GetGroupRequestParticipantsResponse, err error)
 	ManageGroupRequestParticipants(ctx context.Context, request GroupRequestParticipantsRequest) (result []ParticipantStatus, err error)
 }
@@ -29,6 +30,20 @@ type IGroupSettings interface {
 	SetGroupLocked(ctx context.Context, request SetGroupLockedRequest) (err error)
 	SetGroupAnnounce(ctx context.Context, request SetGroupAnnounceRequest) (err error)
 	SetGroupTopic(ctx context.Context, request SetGroupTopicRequest) (err error)
+	GetGroupDescriptionHistory(ctx context.Context, request GetGroupDescriptionHistoryRequest) (response GetGroupDescriptionHistoryResponse, err error)
+	SetGroupMemberAddMode(ctx context.Context, request SetGroupMemberAddModeRequest) (err error)
+	SetGroupJoinApproval(ctx context.Context, request SetGroupJoinApprovalRequest) (err error)
+	SetGroupEphemeral(ctx context.Context, request SetGroupEphemeralRequest) (err error)
+}
+
+// IGroupCommunity handles communities and the groups linked to them
+type IGroupCommunity interface {
+	CreateCommunity(ctx context.Context, request CreateCommunityRequest) (communityID string, err error)
+	LinkGroup(ctx context.Context, request CommunityGroupRequest) (err error)
+	UnlinkGroup(ctx context.Context, request CommunityGroupRequest) (err error)
+	GetCommunityGroups(ctx context.Context, request GetCommunityGroupsRequest) (response GetCommunityGroupsResponse, err error)
+	GetCommunityParticipants(ctx context.Context, request GetCommunityParticipantsRequest) (response GetCommunityParticipantsResponse, err error)
+	SendCommunityAnnouncement(ctx context.Context, request CommunityAnnouncementRequest) (response CommunityAnnouncementResponse, err error)
 }
 
 // IGroupUsecase combines all group interfaces for backward compatibility
@@ -36,4 +51,5 @@ type IGroupUsecase interface {
 	IGroupManagement
 	IGroupParticipants
 	IGroupSettings
+	IGroupCommunity
 }
@@ -1,16 +1,70 @@
 package newsletter
 
-import "context"
+import (
+	"context"
+	"mime/multipart"
+)
+
+const (
+	PostTypeText  = "text"
+	PostTypeImage = "image"
+	PostTypeVideo = "video"
+)
 
 type INewsletterUsecase interface {
+	Create(ctx context.Context, request CreateRequest) (response CreateResponse, err error)
+	Follow(ctx context.Context, request FollowRequest) (err error)
 	Unfollow(ctx context.Context, request UnfollowRequest) (err error)
+	Update(ctx context.Context, request UpdateRequest) (err error)
+	Mute(ctx context.Context, request MuteRequest) (err error)
 	GetMessages(ctx context.Context, request GetMessagesRequest) (response GetMessagesResponse, err error)
+	SendPost(ctx context.Context, request SendPostRequest) (response PostResponse, err error)
+	EditPost(ctx context.Context, request EditPostRequest) (response PostResponse, err error)
+	DeletePost(ctx context.Context, request DeletePostRequest) (response PostResponse, err error)
+	GetPostReactions(ctx context.Context, request GetPostReactionsRequest) (response PostReactionsResponse, err error)
+}
+
+// CreateRequest creates a newsletter (channel) owned by the logged-in account.
+type CreateRequest struct {
+	Name        string `json:"name" form:"name"`
+	Description string `json:"description" form:"description"`
+	// Picture or PictureURL optionally sets the channel picture; it is
+	// cropped to a square JPEG like group photos.
+	Picture    *multipart.FileHeader `json:"picture" form:"picture"`
+	PictureURL string                `json:"picture_url" form:"picture_url"`
+}
+
+type CreateResponse struct {
+	NewsletterID string `json:"newsletter_id"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	InviteLink   string `json:"invite_link,omitempty"`
+}
+
+type FollowRequest struct {
+	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
 }
 
 type UnfollowRequest struct {
 	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
 }
 
+// UpdateRequest changes the name, description and/or picture of a newsletter
+// the account administers. Nil fields are left untouched; an empty
+// Description clears it.
+type UpdateRequest struct {
+	NewsletterID string                `json:"newsletter_id" form:"newsletter_id"`
+	Name         *string               `json:"name" form:"name"`
+	Description  *string               `json:"description" form:"description"`
+	Picture      *multipart.FileHeader `json:"picture" form:"picture"`
+	PictureURL   string                `json:"picture_url" form:"picture_url"`
+}
+
+type MuteRequest struct {
+	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
+	Mute         bool   `json:"mute" form:"mute"`
+}
+
 // GetMessagesRequest fetches the latest messages posted in a newsletter (channel)
 // directly from WhatsApp servers.
 type GetMessagesRequest struct {
@@ -37,3 +91,50 @@ type Message struct {
 type GetMessagesResponse struct {
 	Data []Message `json:"data"`
 }
+
+// SendPostRequest publishes a post to a newsletter as its admin. Type defaults
+// to text; image and video posts take either an uploaded Media file or a
+// MediaURL fetched server-side.
+type SendPostRequest struct {
+	NewsletterID string                `json:"newsletter_id" form:"newsletter_id"`
+	Type         string                `json:"type" form:"type"`
+	Message      string                `json:"message" form:"message"`
+	Caption      string                `json:"caption" form:"caption"`
+	Media        *multipart.FileHeader `json:"media" form:"media"`
+	MediaURL     string                `json:"media_url" form:"media_url"`
+}
+
+type EditPostRequest struct {
+	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
+	MessageID    string `json:"message_id" form:"message_id"`
+	Message      string `json:"message" form:"message"`
+}
+
+type DeletePostRequest struct {
+	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
+	MessageID    string `json:"message_id" form:"message_id"`
+}
+
+// PostResponse reports a published, edited or deleted post. ServerID is the
+// newsletter-wide post number used by GetPostReactionsRequest; it is only
+// known for new posts.
+type PostResponse struct {
+	MessageID string `json:"message_id"`
+	ServerID  int    `json:"server_id,omitempty"`
+	Status    string `json:"status"`
+}
+
+// GetPostReactionsRequest reads the view and reaction counters of one post,
+// addressed by its server ID (see Message.ServerID).
+type GetPostReactionsRequest struct {
+	NewsletterID string `json:"newsletter_id" query:"newsletter_id"`
+	ServerID     int    `json:"server_id" query:"server_id"`
+}
+
+type PostReactionsResponse struct {
+	ServerID       int            `json:"server_id"`
+	MessageID      string         `json:"message_id"`
+	ViewsCount     int            `json:"views_count"`
+	ReactionCounts map[string]int `json:"reaction_counts"`
+	TotalReactions int            `json:"total_reactions"`
+}
@@ -110,3 +110,25 @@ type BusinessProfileResponse struct {
 	BusinessHoursTimeZone string                       `json:"business_hours_timezone"`
 	BusinessHours         []BusinessProfileHoursConfig `json:"business_hours"`
 }
+
+type PresenceSubscriptionRequest struct {
+	Phone string `json:"phone" form:"phone"`
+}
+
+type PresenceRequest struct {
+	Phone string `json:"phone" query:"phone"`
+}
+
+type PresenceResponse struct {
+	JID string `json:"jid"`
+	// Status is "typing" or "recording" while a chat state is fresh, else
+	// "online", "offline", or "unknown" when no presence was ever received.
+	Status      string `json:"status"`
+	LastSeen    string `json:"last_seen,omitempty"`
+	ChatJID     string `json:"chat_jid,omitempty"`
+	ChatState   string `json:"chat_state,omitempty"`
+	ChatMedia   string `json:"chat_media,omitempty"`
+	ChatStateAt string `json:"chat_state_at,omitempty"`
+	Subscribed  bool   `json:"subscribed"`
+	UpdatedAt   string `json:"updated_at,omitempty"`
+}
@@ -510,9 +510,13 @@ func (r *SQLiteRepository) GetMessages(filter *domainChatStorage.MessageFilter)
 			media_type, call_metadata, filename, url, direct_path, media_key, file_sha256,
 			file_enc_sha256, file_length, referral_metadata, created_at, updated_at
 		FROM messages
-		WHERE ` + strings.Join(conditions, " AND ") + `
-		ORDER BY timestamp DESC
-	`
+		WHERE ` + strings.Join(conditions, " AND ")
+	if filter.OldestFirst {
+		// id breaks timestamp ties so offset paging stays stable.
+		query += " ORDER BY timestamp ASC, id ASC"
+	} else {
+		query += " ORDER BY timestamp DESC"
+	}
 
 	// Safely add LIMIT and OFFSET using parameterized values
 	if filter.Limit > 0 {
@@ -903,16 +907,17 @@ func (r *SQLiteRepository) EnqueueChatwootForwardEvent(event *domainChatStorage.
 	_, err := r.db.Exec(`
 		INSERT INTO chatwoot_forward_queue (
 			device_id, event_name, wa_message_id, payload_json,
-			attempts, last_error, next_attempt_at, created_at, updated_at
+			attempts, last_error, next_attempt_at, trace_parent, created_at, updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(device_id, event_name, wa_message_id) DO UPDATE SET
 			payload_json = excluded.payload_json,
 			last_error = excluded.last_error,
 			next_attempt_at = excluded.next_attempt_at,
+			trace_parent = excluded.trace_parent,
 			updated_at = excluded.updated_at
 	`, event.DeviceID, event.EventName, event.WhatsAppMessageID, event.PayloadJSON,
-		event.Attempts, event.LastError, event.NextAttemptAt, event.CreatedAt, event.UpdatedAt)
+		event.Attempts, event.LastError, event.NextAttemptAt, event.TraceParent, event.CreatedAt, event.UpdatedAt)
 	return err
 }
 
@@ -923,7 +928,7 @@ func (r *SQLiteRepository) ListDueChatwootForwardEvents(now time.Time, limit int
 
 	rows, err := r.db.Query(`
 		SELECT id, device_id, event_name, wa_message_id, payload_json,
-			attempts, last_error, next_attempt_at, created_at, updated_at
+			attempts, last_error, next_attempt_at, trace_parent, created_at, updated_at
 		FROM chatwoot_forward_queue
 		WHERE next_attempt_at <= ?
 		ORDER BY next_attempt_at ASC, id ASC
@@ -940,7 +945,7 @@ func (r *SQLiteRepository) ListDueChatwootForwardEvents(now time.Time, limit int
 		if err := rows.Scan(
 			&event.ID, &event.DeviceID, &event.EventName, &event.WhatsAppMessageID,
 			&event.PayloadJSON, &event.Attempts, &event.LastError, &event.NextAttemptAt,
-			&event.CreatedAt, &event.UpdatedAt,
+			&event.TraceParent, &event.CreatedAt, &event.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1033,13 +1038,15 @@ func (r *SQLiteRepository) DeleteChatwootMessageLinksByConfig(configID int64) er
 	return err
 }
 
-const chatwootDeviceConfigColumns = `id, device_id, device_jid, chatwoot_url, account_id, inbox_id, api_token, enabled, created_at, updated_at`
+const chatwootDeviceConfigColumns = `id, device_id, device_jid, chatwoot_url, account_id, inbox_id, api_token, enabled,
+	label_sync, archive_on_resolve, closing_message, created_at, updated_at`
 
 func (r *SQLiteRepository) scanChatwootDeviceConfig(scanner interface{ Scan(...any) error }) (*domainChatStorage.ChatwootDeviceConfig, error) {
 	cfg := &domainChatStorage.ChatwootDeviceConfig{}
 	err := scanner.Scan(
 		&cfg.ID, &cfg.DeviceID, &cfg.DeviceJID, &cfg.ChatwootURL,
 		&cfg.AccountID, &cfg.InboxID, &cfg.APIToken, &cfg.Enabled,
+		&cfg.LabelSync, &cfg.ArchiveOnResolve, &cfg.ClosingMessage,
 		&cfg.CreatedAt, &cfg.UpdatedAt,
 	)
 	return cfg, err
@@ -1063,10 +1070,12 @@ func (r *SQLiteRepository) SaveChatwootDeviceConfig(cfg *domainChatStorage.Chatw
 	result, err := r.db.Exec(`
 		UPDATE chatwoot_device_configs
 		SET device_jid = ?, chatwoot_url = ?, account_id = ?, inbox_id = ?,
-		    api_token = ?, enabled = ?, updated_at = ?
+		    api_token = ?, enabled = ?, label_sync = ?, archive_on_resolve = ?,
+		    closing_message = ?, updated_at = ?
 		WHERE device_id = ?
 	`, cfg.DeviceJID, cfg.ChatwootURL, cfg.AccountID, cfg.InboxID,
-		cfg.APIToken, cfg.Enabled, cfg.UpdatedAt, cfg.DeviceID)
+		cfg.APIToken, cfg.Enabled, cfg.LabelSync, cfg.ArchiveOnResolve,
+		cfg.ClosingMessage, cfg.UpdatedAt, cfg.DeviceID)
 	if err != nil {
 		return err
 	}
@@ -1076,11 +1085,13 @@ func (r *SQLiteRepository) SaveChatwootDeviceConfig(cfg *domainChatStorage.Chatw
 		res, err := r.db.Exec(`
 			INSERT INTO chatwoot_device_configs (
 				device_id, device_jid, chatwoot_url, account_id, inbox_id,
-				api_token, enabled, created_at, updated_at
+				api_token, enabled, label_sync, archive_on_resolve, closing_message,
+				created_at, updated_at
 			)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		`, cfg.DeviceID, cfg.DeviceJID, cfg.ChatwootURL, cfg.AccountID, cfg.InboxID,
-			cfg.APIToken, cfg.Enabled, cfg.CreatedAt, cfg.UpdatedAt)
+			cfg.APIToken, cfg.Enabled, cfg.LabelSync, cfg.ArchiveOnResolve, cfg.ClosingMessage,
+			cfg.CreatedAt, cfg.UpdatedAt)
 		if err != nil {
 			return err
 		}
@@ -1311,10 +1322,60 @@ func (r *SQLiteRepository) TruncateAllChats() error {
 		return fmt.Errorf("failed to delete chats: %w", err)
 	}
 
+	_, err = tx.Exec("DELETE FROM group_metadata")
+	if err != nil {
+		return fmt.Errorf("failed to delete group metadata: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM group_participant_events")
+	if err != nil {
+		return fmt.Errorf("failed to delete group participant history: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM group_description_events")
+	if err != nil {
+		return fmt.Errorf("failed to delete group description history: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM call_records")
+	if err != nil {
+		return fmt.Errorf("failed to delete call records: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM contact_tags")
+	if err != nil {
+		return fmt.Errorf("failed to delete contact tags: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM contacts")
+	if err != nil {
+		return fmt.Errorf("failed to delete contacts: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM presences")
+	if err != nil {
+		return fmt.Errorf("failed to delete presences: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM presence_subscriptions")
+	if err != nil {
+		return fmt.Errorf("failed to delete presence subscriptions: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM chat_labels")
+	if err != nil {
+		return fmt.Errorf("failed to delete chat labels: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM labels")
+	if err != nil {
+		return fmt.Errorf("failed to delete labels: %w", err)
+	}
+
 	return tx.Commit()
 }
 
-// DeleteDeviceData deletes all chats, messages, and edit history for a specific device_id.
+// DeleteDeviceData deletes all chats, messages, edit history, cached groups, call records, contacts, presences and labels for a specific device_id.
 func (r *SQLiteRepository) DeleteDeviceData(deviceID string) error {
 	if deviceID == "" {
 		return fmt.Errorf("device id is required")
@@ -1351,6 +1412,46 @@ func (r *SQLiteRepository) DeleteDeviceData(deviceID string) error {
 		return fmt.Errorf("failed to delete device chats: %w", err)
 	}
 
+	if _, err := tx.Exec("DELETE FROM group_metadata WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device group metadata: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM group_participant_events WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device group participant history: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM group_description_events WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device group description history: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM call_records WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device call records: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM contact_tags WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device contact tags: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM contacts WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device contacts: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM presences WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device presences: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM presence_subscriptions WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device presence subscriptions: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM chat_labels WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device chat labels: %w", err)
+	}
+
+	if _, err := tx.Exec("DELETE FROM labels WHERE device_id = ?", deviceID); err != nil {
+		return fmt.Errorf("failed to delete device labels: %w", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -1388,7 +1489,7 @@ func (r *SQLiteRepository) SaveDeviceRecord(record *domainChatStorage.DeviceReco
 // ListDeviceRecords returns all registered devices.
 func (r *SQLiteRepository) ListDeviceRecords() ([]*domainChatStorage.DeviceRecord, error) {
 	rows, err := r.db.Query(`
-		SELECT device_id, display_name, jid, COALESCE(ad_jid, ''), created_at, updated_at
+		SELECT device_id, display_name, jid, COALESCE(ad_jid, ''), COALESCE(tags, ''), COALESCE(metadata, ''), created_at, updated_at
 		FROM devices
 		ORDER BY created_at ASC
 	`)
@@ -1400,7 +1501,7 @@ func (r *SQLiteRepository) ListDeviceRecords() ([]*domainChatStorage.DeviceRecor
 	var records []*domainChatStorage.DeviceRecord
 	for rows.Next() {
 		var rec domainChatStorage.DeviceRecord
-		if err := rows.Scan(&rec.DeviceID, &rec.DisplayName, &rec.JID, &rec.ADJID, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		if err := rows.Scan(&rec.DeviceID, &rec.DisplayName, &rec.JID, &rec.ADJID, &rec.Tags, &rec.Metadata, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
 			return nil, err
 		}
 		records = append(records, &rec)
@@ -1417,11 +1518,11 @@ func (r *SQLiteRepository) GetDeviceRecord(deviceID string) (*domainChatStorage.
 
 	rec := &domainChatStorage.DeviceRecord{}
 	err := r.db.QueryRow(`
-		SELECT device_id, display_name, jid, COALESCE(ad_jid, ''), created_at, updated_at
+		SELECT device_id, display_name, jid, COALESCE(ad_jid, ''), COALESCE(tags, ''), COALESCE(metadata, ''), created_at, updated_at
 		FROM devices
 		WHERE device_id = ?
 		LIMIT 1
-	`, deviceID).Scan(&rec.DeviceID, &rec.DisplayName, &rec.JID, &rec.ADJID, &rec.CreatedAt, &rec.UpdatedAt)
+	`, deviceID).Scan(&rec.DeviceID, &rec.DisplayName, &rec.JID, &rec.ADJID, &rec.Tags, &rec.Metadata, &rec.CreatedAt, &rec.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -1442,7 +1543,7 @@ func (r *SQLiteRepository) GetDeviceRecordByJID(jid string) (*domainChatStorage.
 	}
 
 	rows, err := r.db.Query(`
-		SELECT device_id, display_name, jid, COALESCE(ad_jid, ''), webhook_url, COALESCE(webhook_secret, ''), COALESCE(webhook_events, ''), COALESCE(webhook_insecure_skip_verify, FALSE), created_at, updated_at
+		SELECT device_id, display_name, jid, COALESCE(ad_jid, ''), webhook_url, COALESCE(webhook_secret, ''), COALESCE(webhook_events, ''), COALESCE(webhook_insecure_skip_verify, FALSE), COALESCE(webhook_method, ''), COALESCE(webhook_headers, ''), COALESCE(webhook_template, ''), COALESCE(webhook_signature_algorithm, ''), call_auto_reject, COALESCE(call_reject_message, ''), COALESCE(proxy_url, ''), COALESCE(proxy_pool, ''), created_at, updated_at
 		FROM devices
 		WHERE jid = ? OR ad_jid = ?
 		LIMIT 2
@@ -1464,6 +1565,14 @@ func (r *SQLiteRepository) GetDeviceRecordByJID(jid string) (*domainChatStorage.
 			&rec.WebhookSecret,
 			&rec.WebhookEvents,
 			&rec.WebhookInsecureSkipVerify,
+			&rec.WebhookMethod,
+			&rec.WebhookHeaders,
+			&rec.WebhookTemplate,
+			&rec.WebhookSignatureAlgorithm,
+			&rec.CallAutoReject,
+			&rec.CallRejectMessage,
+			&rec.ProxyURL,
+			&rec.ProxyPool,
 			&rec.CreatedAt,
 			&rec.UpdatedAt,
 		); err != nil {
@@ -1557,11 +1666,22 @@ func (r *SQLiteRepository) SetDeviceWebhookConfig(deviceID string, config *domai
 		webhookURL = config.WebhookURL
 	}
 
+	headers := ""
+	if len(config.WebhookHeaders) > 0 {
+		encoded, err := json.Marshal(config.WebhookHeaders)
+		if err != nil {
+			return fmt.Errorf("failed to encode webhook headers: %w", err)
+		}
+		headers = string(encoded)
+	}
+
 	result, err := r.db.Exec(`
 		UPDATE devices
-		SET webhook_url = ?, webhook_secret = ?, webhook_events = ?, webhook_insecure_skip_verify = ?, updated_at = ?
+		SET webhook_url = ?, webhook_secret = ?, webhook_events = ?, webhook_insecure_skip_verify = ?,
+			webhook_method = ?, webhook_headers = ?, webhook_template = ?, webhook_signature_algorithm = ?, updated_at = ?
 		WHERE device_id = ?
-	`, webhookURL, config.WebhookSecret, config.WebhookEvents, config.WebhookInsecureSkipVerify, time.Now(), deviceID)
+	`, webhookURL, config.WebhookSecret, config.WebhookEvents, config.WebhookInsecureSkipVerify,
+		config.WebhookMethod, headers, config.WebhookTemplate, config.WebhookSignatureAlgorithm, time.Now(), deviceID)
 	if err != nil {
 		return err
 	}
@@ -1583,10 +1703,13 @@ func (r *SQLiteRepository) GetDeviceWebhookConfig(deviceID string) (*domainChatS
 	}
 	var config domainChatStorage.DeviceWebhookConfig
 	var webhookURL *string
+	var headers string
 	err := r.db.QueryRow(`
-		SELECT webhook_url, COALESCE(webhook_secret, ''), COALESCE(webhook_events, ''), COALESCE(webhook_insecure_skip_verify, FALSE)
+		SELECT webhook_url, COALESCE(webhook_secret, ''), COALESCE(webhook_events, ''), COALESCE(webhook_insecure_skip_verify, FALSE),
+			COALESCE(webhook_method, ''), COALESCE(webhook_headers, ''), COALESCE(webhook_template, ''), COALESCE(webhook_signature_algorithm, '')
 		FROM devices WHERE device_id = ? LIMIT 1
-	`, deviceID).Scan(&webhookURL, &config.WebhookSecret, &config.WebhookEvents, &config.WebhookInsecureSkipVerify)
+	`, deviceID).Scan(&webhookURL, &config.WebhookSecret, &config.WebhookEvents, &config.WebhookInsecureSkipVerify,
+		&config.WebhookMethod, &headers, &config.WebhookTemplate, &config.WebhookSignatureAlgorithm)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -1594,9 +1717,202 @@ func (r *SQLiteRepository) GetDeviceWebhookConfig(deviceID string) (*domainChatS
 		return nil, err
 	}
 	config.WebhookURL = webhookURL
+	if headers != "" {
+		if err := json.Unmarshal([]byte(headers), &config.WebhookHeaders); err != nil {
+			return nil, fmt.Errorf("failed to decode webhook headers: %w", err)
+		}
+	}
+	return &config, nil
+}
+
+// SetDeviceCallConfig updates the incoming-call handling of a device.
+// Returns sql.ErrNoRows if the device does not exist.
+func (r *SQLiteRepository) SetDeviceCallConfig(deviceID string, config *domainChatStorage.DeviceCallConfig) error {
+	if strings.TrimSpace(deviceID) == "" {
+		return fmt.Errorf("device id is required")
+	}
+	if config == nil {
+		return fmt.Errorf("call config is required")
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE devices
+		SET call_auto_reject = ?, call_reject_message = ?, updated_at = ?
+		WHERE device_id = ?
+	`, config.AutoReject, config.RejectMessage, time.Now(), deviceID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetDeviceCallConfig retrieves the incoming-call handling of a device.
+// Returns (nil, nil) if the device does not exist.
+func (r *SQLiteRepository) GetDeviceCallConfig(deviceID string) (*domainChatStorage.DeviceCallConfig, error) {
+	if strings.TrimSpace(deviceID) == "" {
+		return nil, fmt.Errorf("device id is required")
+	}
+	var config domainChatStorage.DeviceCallConfig
+	err := r.db.QueryRow(`
+		SELECT call_auto_reject, COALESCE(call_reject_message, '')
+		FROM devices WHERE device_id = ? LIMIT 1
+	`, deviceID).Scan(&config.AutoReject, &config.RejectMessage)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SetDeviceProxyConfig updates the outbound proxy of a device.
+// Returns sql.ErrNoRows if the device does not exist.
+func (r *SQLiteRepository) SetDeviceProxyConfig(deviceID string, config *domainChatStorage.DeviceProxyConfig) error {
+	if strings.TrimSpace(deviceID) == "" {
+		return fmt.Errorf("device id is required")
+	}
+	if config == nil {
+		return fmt.Errorf("proxy config is required")
+	}
+
+	pool := ""
+	if len(config.Pool) > 0 {
+		encoded, err := json.Marshal(config.Pool)
+		if err != nil {
+			return fmt.Errorf("failed to encode proxy pool: %w", err)
+		}
+		pool = string(encoded)
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE devices
+		SET proxy_url = ?, proxy_pool = ?, updated_at = ?
+		WHERE device_id = ?
+	`, config.ProxyURL, pool, time.Now(), deviceID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetDeviceProxyConfig retrieves the outbound proxy of a device.
+// Returns (nil, nil) if the device does not exist.
+func (r *SQLiteRepository) GetDeviceProxyConfig(deviceID string) (*domainChatStorage.DeviceProxyConfig, error) {
+	if strings.TrimSpace(deviceID) == "" {
+		return nil, fmt.Errorf("device id is required")
+	}
+	var config domainChatStorage.DeviceProxyConfig
+	var pool string
+	err := r.db.QueryRow(`
+		SELECT COALESCE(proxy_url, ''), COALESCE(proxy_pool, '')
+		FROM devices WHERE device_id = ? LIMIT 1
+	`, deviceID).Scan(&config.ProxyURL, &pool)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pool != "" {
+		if err := json.Unmarshal([]byte(pool), &config.Pool); err != nil {
+			return nil, fmt.Errorf("failed to decode proxy pool: %w", err)
+		}
+	}
 	return &config, nil
 }
 
+// SetDeviceAttributes replaces the tags and metadata of a device.
+// Returns sql.ErrNoRows if the device does not exist.
+func (r *SQLiteRepository) SetDeviceAttributes(deviceID string, attributes *domainChatStorage.DeviceAttributes) error {
+	if strings.TrimSpace(deviceID) == "" {
+		return fmt.Errorf("device id is required")
+	}
+	if attributes == nil {
+		return fmt.Errorf("device attributes are required")
+	}
+
+	tags := ""
+	if len(attributes.Tags) > 0 {
+		encoded, err := json.Marshal(attributes.Tags)
+		if err != nil {
+			return fmt.Errorf("failed to encode device tags: %w", err)
+		}
+		tags = string(encoded)
+	}
+	metadata := ""
+	if len(attributes.Metadata) > 0 {
+		encoded, err := json.Marshal(attributes.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode device metadata: %w", err)
+		}
+		metadata = string(encoded)
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE devices
+		SET tags = ?, metadata = ?, updated_at = ?
+		WHERE device_id = ?
+	`, tags, metadata, time.Now(), deviceID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetDeviceAttributes retrieves the tags and metadata of a device.
+// Returns (nil, nil) if the device does not exist.
+func (r *SQLiteRepository) GetDeviceAttributes(deviceID string) (*domainChatStorage.DeviceAttributes, error) {
+	if strings.TrimSpace(deviceID) == "" {
+		return nil, fmt.Errorf("device id is required")
+	}
+	var tags, metadata string
+	err := r.db.QueryRow(`
+		SELECT COALESCE(tags, ''), COALESCE(metadata, '')
+		FROM devices WHERE device_id = ? LIMIT 1
+	`, deviceID).Scan(&tags, &metadata)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := &domainChatStorage.DeviceAttributes{Tags: []string{}, Metadata: map[string]string{}}
+	if tags != "" {
+		if err := json.Unmarshal([]byte(tags), &attributes.Tags); err != nil {
+			return nil, fmt.Errorf("failed to decode device tags: %w", err)
+		}
+	}
+	if metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &attributes.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode device metadata: %w", err)
+		}
+	}
+	return attributes, nil
+}
+
 // GetChatNameWithPushName determines the appropriate name for a chat with pushname support
 func (r *SQLiteRepository) GetChatNameWithPushName(jid types.JID, chatJID string, senderUser string, pushName string) string {
 	// First, check if chat already exists with a name
@@ -2309,66 +2625,1079 @@ func (r *SQLiteRepository) StoreSentMessageWithContext(ctx context.Context, mess
 	return nil
 }
 
-// _____________________________________________________________________________________________________________________
+// StoreAuditEntry appends an audit record. There is intentionally no update
+// or delete counterpart: the audit trail is append-only.
+func (r *SQLiteRepository) StoreAuditEntry(entry *domainChatStorage.AuditEntry) error {
+	if entry == nil || strings.TrimSpace(entry.Action) == "" {
+		return fmt.Errorf("audit entry requires an action")
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
 
-// initializeSchema creates or migrates the database schema
-func (r *SQLiteRepository) InitializeSchema() error {
-	// Get current schema version
-	version, err := r.getSchemaVersion()
+	result, err := r.db.Exec(`
+		INSERT INTO audit_logs (
+			source, principal_type, principal, client_id, device_id, action,
+			target_jid, message_id, result, status_code, error_message, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.Source, entry.PrincipalType, entry.Principal, entry.ClientID, entry.DeviceID, entry.Action,
+		entry.TargetJID, entry.MessageID, entry.Result, entry.StatusCode, entry.ErrorMessage, entry.CreatedAt)
 	if err != nil {
 		return err
 	}
-
-	// Run migrations based on version
-	migrations := r.getMigrations()
-	for i := version; i < len(migrations); i++ {
-		if err := r.runMigration(migrations[i], i+1); err != nil {
-			return fmt.Errorf("failed to run migration %d: %w", i+1, err)
-		}
+	if id, err := result.LastInsertId(); err == nil {
+		entry.ID = id
 	}
-
 	return nil
 }
 
-// getSchemaVersion returns the current schema version
-func (r *SQLiteRepository) getSchemaVersion() (int, error) {
-	// Create schema_info table if it doesn't exist
-	_, err := r.db.Exec(`
-		CREATE TABLE IF NOT EXISTS schema_info (
-			version INTEGER PRIMARY KEY,
-			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-		)
-	`)
-	if err != nil {
-		return 0, err
-	}
+// GetAuditEntries returns audit entries matching filter, newest first.
+func (r *SQLiteRepository) GetAuditEntries(filter *domainChatStorage.AuditFilter) ([]*domainChatStorage.AuditEntry, error) {
+	query := `
+		SELECT id, source, principal_type, principal, client_id, device_id, action,
+			target_jid, message_id, result, status_code, error_message, created_at
+		FROM audit_logs
+	`
 
-	// Get current version
-	var version int
-	err = r.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_info").Scan(&version)
-	if err != nil {
-		return 0, err
+	conditions, args := r.buildAuditFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
 	}
+	query += " ORDER BY created_at DESC, id DESC"
 
-	return version, nil
-}
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
 
-// runMigration executes a migration
-func (r *SQLiteRepository) runMigration(migration string, version int) error {
-	tx, err := r.db.Begin()
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	// Execute migration (single statement)
-	if _, err := tx.Exec(migration); err != nil {
-		return err
+	entries := make([]*domainChatStorage.AuditEntry, 0)
+	for rows.Next() {
+		entry := &domainChatStorage.AuditEntry{}
+		if err := rows.Scan(
+			&entry.ID, &entry.Source, &entry.PrincipalType, &entry.Principal, &entry.ClientID,
+			&entry.DeviceID, &entry.Action, &entry.TargetJID, &entry.MessageID, &entry.Result,
+			&entry.StatusCode, &entry.ErrorMessage, &entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
 	}
+	return entries, rows.Err()
+}
 
-	// Update schema version - delete then insert for cross-db compatibility
-	_, _ = tx.Exec("DELETE FROM schema_info WHERE version = ?", version)
-	if _, err := tx.Exec("INSERT INTO schema_info (version) VALUES (?)", version); err != nil {
+// CountAuditEntries returns the number of audit entries matching filter.
+func (r *SQLiteRepository) CountAuditEntries(filter *domainChatStorage.AuditFilter) (int64, error) {
+	query := "SELECT COUNT(*) FROM audit_logs"
+	conditions, args := r.buildAuditFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return r.getCount(query, args...)
+}
+
+// buildAuditFilterQuery builds the WHERE conditions shared by the audit list and count queries.
+func (r *SQLiteRepository) buildAuditFilterQuery(filter *domainChatStorage.AuditFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	if filter == nil {
+		return conditions, args
+	}
+
+	equals := []struct {
+		column string
+		value  string
+	}{
+		{"source", filter.Source},
+		{"principal", filter.Principal},
+		{"device_id", filter.DeviceID},
+		{"action", filter.Action},
+		{"target_jid", filter.TargetJID},
+		{"message_id", filter.MessageID},
+		{"result", filter.Result},
+	}
+	for _, eq := range equals {
+		if eq.value != "" {
+			conditions = append(conditions, eq.column+" = ?")
+			args = append(args, eq.value)
+		}
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.Since)
+	}
+	if filter.Until != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, *filter.Until)
+	}
+	return conditions, args
+}
+
+// SaveGroupMetadata upserts the cached state of a group, replacing its participant list.
+func (r *SQLiteRepository) SaveGroupMetadata(metadata *domainChatStorage.GroupMetadata) error {
+	if metadata == nil || metadata.DeviceID == "" || metadata.GroupJID == "" {
+		return fmt.Errorf("group metadata requires a device id and group jid")
+	}
+	if metadata.UpdatedAt.IsZero() {
+		metadata.UpdatedAt = time.Now()
+	}
+
+	participants := metadata.Participants
+	if participants == nil {
+		participants = []domainChatStorage.GroupMetadataParticipant{}
+	}
+	participantsJSON, err := json.Marshal(participants)
+	if err != nil {
+		return fmt.Errorf("failed to encode group participants: %w", err)
+	}
+
+	var groupCreated any
+	if !metadata.GroupCreated.IsZero() {
+		groupCreated = metadata.GroupCreated
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO group_metadata (
+			device_id, group_jid, name, topic, owner_jid, is_locked, is_announce, is_ephemeral,
+			ephemeral_seconds, join_approval, member_add_mode, is_parent, linked_parent_jid,
+			group_created, participants_json, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, group_jid) DO UPDATE SET
+			name = excluded.name,
+			topic = excluded.topic,
+			owner_jid = excluded.owner_jid,
+			is_locked = excluded.is_locked,
+			is_announce = excluded.is_announce,
+			is_ephemeral = excluded.is_ephemeral,
+			ephemeral_seconds = excluded.ephemeral_seconds,
+			join_approval = excluded.join_approval,
+			member_add_mode = excluded.member_add_mode,
+			is_parent = excluded.is_parent,
+			linked_parent_jid = excluded.linked_parent_jid,
+			group_created = excluded.group_created,
+			participants_json = excluded.participants_json,
+			updated_at = excluded.updated_at
+	`, metadata.DeviceID, metadata.GroupJID, metadata.Name, metadata.Topic, metadata.OwnerJID,
+		metadata.IsLocked, metadata.IsAnnounce, metadata.IsEphemeral, metadata.EphemeralSeconds,
+		metadata.JoinApproval, metadata.MemberAddMode, metadata.IsParent, metadata.LinkedParentJID,
+		groupCreated, string(participantsJSON), metadata.UpdatedAt)
+	return err
+}
+
+// GetGroupMetadata returns the cached state of a group, or nil when it is not cached.
+func (r *SQLiteRepository) GetGroupMetadata(deviceID, groupJID string) (*domainChatStorage.GroupMetadata, error) {
+	metadata := &domainChatStorage.GroupMetadata{}
+	var groupCreated sql.NullTime
+	var participantsJSON string
+	err := r.db.QueryRow(`
+		SELECT device_id, group_jid, name, topic, owner_jid, is_locked, is_announce, is_ephemeral,
+			ephemeral_seconds, join_approval, member_add_mode, is_parent, linked_parent_jid,
+			group_created, participants_json, updated_at
+		FROM group_metadata
+		WHERE device_id = ? AND group_jid = ?
+	`, deviceID, groupJID).Scan(
+		&metadata.DeviceID, &metadata.GroupJID, &metadata.Name, &metadata.Topic, &metadata.OwnerJID,
+		&metadata.IsLocked, &metadata.IsAnnounce, &metadata.IsEphemeral, &metadata.EphemeralSeconds,
+		&metadata.JoinApproval, &metadata.MemberAddMode, &metadata.IsParent, &metadata.LinkedParentJID,
+		&groupCreated, &participantsJSON, &metadata.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if groupCreated.Valid {
+		metadata.GroupCreated = groupCreated.Time
+	}
+	if err := json.Unmarshal([]byte(participantsJSON), &metadata.Participants); err != nil {
+		return nil, fmt.Errorf("failed to decode participants of group %s: %w", groupJID, err)
+	}
+	return metadata, nil
+}
+
+// DeleteGroupMetadata drops the cached state of a group; unknown groups are ignored.
+func (r *SQLiteRepository) DeleteGroupMetadata(deviceID, groupJID string) error {
+	_, err := r.db.Exec(`DELETE FROM group_metadata WHERE device_id = ? AND group_jid = ?`, deviceID, groupJID)
+	return err
+}
+
+// StoreGroupParticipantEvents appends participant history rows in one transaction.
+func (r *SQLiteRepository) StoreGroupParticipantEvents(events []*domainChatStorage.GroupParticipantEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, event := range events {
+		if event == nil || event.GroupJID == "" || event.ParticipantJID == "" || event.Action == "" {
+			return fmt.Errorf("group participant event requires a group, participant and action")
+		}
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+		result, err := tx.Exec(`
+			INSERT INTO group_participant_events (device_id, group_jid, participant_jid, action, actor_jid, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, event.DeviceID, event.GroupJID, event.ParticipantJID, event.Action, event.ActorJID, event.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if id, err := result.LastInsertId(); err == nil {
+			event.ID = id
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetGroupParticipantEvents returns participant history matching filter, newest first.
+func (r *SQLiteRepository) GetGroupParticipantEvents(filter *domainChatStorage.GroupParticipantEventFilter) ([]*domainChatStorage.GroupParticipantEvent, error) {
+	query := `
+		SELECT id, device_id, group_jid, participant_jid, action, actor_jid, created_at
+		FROM group_participant_events
+	`
+
+	conditions, args := r.buildGroupParticipantEventFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*domainChatStorage.GroupParticipantEvent, 0)
+	for rows.Next() {
+		event := &domainChatStorage.GroupParticipantEvent{}
+		if err := rows.Scan(
+			&event.ID, &event.DeviceID, &event.GroupJID, &event.ParticipantJID,
+			&event.Action, &event.ActorJID, &event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// CountGroupParticipantEvents returns the number of participant history rows matching filter.
+func (r *SQLiteRepository) CountGroupParticipantEvents(filter *domainChatStorage.GroupParticipantEventFilter) (int64, error) {
+	query := "SELECT COUNT(*) FROM group_participant_events"
+	conditions, args := r.buildGroupParticipantEventFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return r.getCount(query, args...)
+}
+
+// buildGroupParticipantEventFilterQuery builds the WHERE conditions shared by the history list and count queries.
+func (r *SQLiteRepository) buildGroupParticipantEventFilterQuery(filter *domainChatStorage.GroupParticipantEventFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	if filter == nil {
+		return conditions, args
+	}
+
+	equals := []struct {
+		column string
+		value  string
+	}{
+		{"device_id", filter.DeviceID},
+		{"group_jid", filter.GroupJID},
+		{"participant_jid", filter.ParticipantJID},
+		{"action", filter.Action},
+	}
+	for _, eq := range equals {
+		if eq.value != "" {
+			conditions = append(conditions, eq.column+" = ?")
+			args = append(args, eq.value)
+		}
+	}
+	return conditions, args
+}
+
+// StoreGroupDescriptionEvent appends a description history row.
+func (r *SQLiteRepository) StoreGroupDescriptionEvent(event *domainChatStorage.GroupDescriptionEvent) error {
+	if event == nil || event.GroupJID == "" {
+		return fmt.Errorf("group description event requires a group")
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	result, err := r.db.Exec(`
+		INSERT INTO group_description_events (device_id, group_jid, description, topic_id, deleted, actor_jid, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, event.DeviceID, event.GroupJID, event.Description, event.TopicID, event.Deleted, event.ActorJID, event.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		event.ID = id
+	}
+	return nil
+}
+
+// GetGroupDescriptionEvents returns description history matching filter, newest first.
+func (r *SQLiteRepository) GetGroupDescriptionEvents(filter *domainChatStorage.GroupDescriptionEventFilter) ([]*domainChatStorage.GroupDescriptionEvent, error) {
+	query := `
+		SELECT id, device_id, group_jid, description, topic_id, deleted, actor_jid, created_at
+		FROM group_description_events
+	`
+
+	conditions, args := r.buildGroupDescriptionEventFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*domainChatStorage.GroupDescriptionEvent, 0)
+	for rows.Next() {
+		event := &domainChatStorage.GroupDescriptionEvent{}
+		if err := rows.Scan(
+			&event.ID, &event.DeviceID, &event.GroupJID, &event.Description,
+			&event.TopicID, &event.Deleted, &event.ActorJID, &event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// CountGroupDescriptionEvents returns the number of description history rows matching filter.
+func (r *SQLiteRepository) CountGroupDescriptionEvents(filter *domainChatStorage.GroupDescriptionEventFilter) (int64, error) {
+	query := "SELECT COUNT(*) FROM group_description_events"
+	conditions, args := r.buildGroupDescriptionEventFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return r.getCount(query, args...)
+}
+
+// buildGroupDescriptionEventFilterQuery builds the WHERE conditions shared by the description history list and count queries.
+func (r *SQLiteRepository) buildGroupDescriptionEventFilterQuery(filter *domainChatStorage.GroupDescriptionEventFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	if filter == nil {
+		return conditions, args
+	}
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+	if filter.GroupJID != "" {
+		conditions = append(conditions, "group_jid = ?")
+		args = append(args, filter.GroupJID)
+	}
+	return conditions, args
+}
+
+// SaveCallRecord upserts a call record; later lifecycle events overwrite the earlier state.
+func (r *SQLiteRepository) SaveCallRecord(record *domainChatStorage.CallRecord) error {
+	if record == nil || record.DeviceID == "" || record.CallID == "" {
+		return fmt.Errorf("call record requires a device id and call id")
+	}
+	if record.OfferedAt.IsZero() {
+		record.OfferedAt = time.Now()
+	}
+	// Store UTC so the offered_at range filters compare consistently.
+	record.OfferedAt = record.OfferedAt.UTC()
+	if record.AcceptedAt != nil {
+		acceptedAt := record.AcceptedAt.UTC()
+		record.AcceptedAt = &acceptedAt
+	}
+	if record.EndedAt != nil {
+		endedAt := record.EndedAt.UTC()
+		record.EndedAt = &endedAt
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO call_records (
+			device_id, call_id, chat_jid, caller_jid, group_jid, is_video, status, auto_rejected,
+			remote_platform, remote_version, end_reason, offered_at, accepted_at, ended_at, duration_seconds
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, call_id) DO UPDATE SET
+			chat_jid = excluded.chat_jid,
+			caller_jid = excluded.caller_jid,
+			group_jid = excluded.group_jid,
+			is_video = excluded.is_video,
+			status = excluded.status,
+			auto_rejected = excluded.auto_rejected,
+			remote_platform = excluded.remote_platform,
+			remote_version = excluded.remote_version,
+			end_reason = excluded.end_reason,
+			offered_at = excluded.offered_at,
+			accepted_at = excluded.accepted_at,
+			ended_at = excluded.ended_at,
+			duration_seconds = excluded.duration_seconds
+	`, record.DeviceID, record.CallID, record.ChatJID, record.CallerJID, record.GroupJID, record.IsVideo,
+		record.Status, record.AutoRejected, record.RemotePlatform, record.RemoteVersion, record.EndReason,
+		record.OfferedAt, record.AcceptedAt, record.EndedAt, record.DurationSeconds)
+	return err
+}
+
+const callRecordColumns = `device_id, call_id, chat_jid, caller_jid, group_jid, is_video, status, auto_rejected,
+	remote_platform, remote_version, end_reason, offered_at, accepted_at, ended_at, duration_seconds`
+
+// GetCallRecord returns a single call, or nil when it is not recorded.
+func (r *SQLiteRepository) GetCallRecord(deviceID, callID string) (*domainChatStorage.CallRecord, error) {
+	row := r.db.QueryRow("SELECT "+callRecordColumns+" FROM call_records WHERE device_id = ? AND call_id = ?", deviceID, callID)
+	record, err := scanCallRecord(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return record, err
+}
+
+// GetCallRecords returns calls matching filter, newest offer first.
+func (r *SQLiteRepository) GetCallRecords(filter *domainChatStorage.CallRecordFilter) ([]*domainChatStorage.CallRecord, error) {
+	query := "SELECT " + callRecordColumns + " FROM call_records"
+
+	conditions, args := r.buildCallRecordFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY offered_at DESC, call_id DESC"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make([]*domainChatStorage.CallRecord, 0)
+	for rows.Next() {
+		record, err := scanCallRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// CountCallRecords returns the number of calls matching filter.
+func (r *SQLiteRepository) CountCallRecords(filter *domainChatStorage.CallRecordFilter) (int64, error) {
+	query := "SELECT COUNT(*) FROM call_records"
+	conditions, args := r.buildCallRecordFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return r.getCount(query, args...)
+}
+
+func scanCallRecord(row interface{ Scan(dest ...any) error }) (*domainChatStorage.CallRecord, error) {
+	record := &domainChatStorage.CallRecord{}
+	var acceptedAt, endedAt sql.NullTime
+	if err := row.Scan(
+		&record.DeviceID, &record.CallID, &record.ChatJID, &record.CallerJID, &record.GroupJID, &record.IsVideo,
+		&record.Status, &record.AutoRejected, &record.RemotePlatform, &record.RemoteVersion, &record.EndReason,
+		&record.OfferedAt, &acceptedAt, &endedAt, &record.DurationSeconds,
+	); err != nil {
+		return nil, err
+	}
+	if acceptedAt.Valid {
+		record.AcceptedAt = &acceptedAt.Time
+	}
+	if endedAt.Valid {
+		record.EndedAt = &endedAt.Time
+	}
+	return record, nil
+}
+
+// buildCallRecordFilterQuery builds the WHERE conditions shared by the call list and count queries.
+func (r *SQLiteRepository) buildCallRecordFilterQuery(filter *domainChatStorage.CallRecordFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	if filter == nil {
+		return conditions, args
+	}
+
+	equals := []struct {
+		column string
+		value  string
+	}{
+		{"device_id", filter.DeviceID},
+		{"chat_jid", filter.ChatJID},
+		{"status", filter.Status},
+	}
+	for _, eq := range equals {
+		if eq.value != "" {
+			conditions = append(conditions, eq.column+" = ?")
+			args = append(args, eq.value)
+		}
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "offered_at >= ?")
+		args = append(args, filter.Since.UTC())
+	}
+	if filter.Until != nil {
+		conditions = append(conditions, "offered_at <= ?")
+		args = append(args, filter.Until.UTC())
+	}
+	return conditions, args
+}
+
+// UpsertContact merges contact into the stored entry. Empty names keep what is
+// stored, so partial updates (a push name change, a saved name from the address
+// book) never wipe the rest of the entry.
+func (r *SQLiteRepository) UpsertContact(contact *domainChatStorage.Contact) error {
+	if contact == nil || contact.DeviceID == "" || contact.JID == "" {
+		return fmt.Errorf("contact requires a device id and jid")
+	}
+
+	now := time.Now().UTC()
+	if contact.CreatedAt.IsZero() {
+		contact.CreatedAt = now
+	}
+	contact.UpdatedAt = now
+
+	// Stored in UTC so the text timestamps compare in time order.
+	var lastInteraction any
+	if contact.LastInteractionAt != nil {
+		lastInteraction = contact.LastInteractionAt.UTC()
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO contacts (
+			device_id, jid, phone, lid, push_name, business_name, saved_name,
+			last_interaction_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, jid) DO UPDATE SET
+			phone = CASE WHEN excluded.phone <> '' THEN excluded.phone ELSE contacts.phone END,
+			lid = CASE WHEN excluded.lid <> '' THEN excluded.lid ELSE contacts.lid END,
+			push_name = CASE WHEN excluded.push_name <> '' THEN excluded.push_name ELSE contacts.push_name END,
+			business_name = CASE WHEN excluded.business_name <> '' THEN excluded.business_name ELSE contacts.business_name END,
+			saved_name = CASE WHEN excluded.saved_name <> '' THEN excluded.saved_name ELSE contacts.saved_name END,
+			last_interaction_at = CASE
+				WHEN contacts.last_interaction_at IS NULL OR excluded.last_interaction_at > contacts.last_interaction_at
+				THEN excluded.last_interaction_at
+				ELSE contacts.last_interaction_at
+			END,
+			updated_at = excluded.updated_at
+	`, contact.DeviceID, contact.JID, contact.Phone, contact.LID, contact.PushName, contact.BusinessName,
+		contact.SavedName, lastInteraction, contact.CreatedAt, contact.UpdatedAt)
+	return err
+}
+
+// SetContactAvatar stores the contact's current avatar URL, creating the entry when needed.
+func (r *SQLiteRepository) SetContactAvatar(deviceID, jid, avatarURL string) error {
+	if deviceID == "" || jid == "" {
+		return fmt.Errorf("contact requires a device id and jid")
+	}
+
+	now := time.Now().UTC()
+	_, err := r.db.Exec(`
+		INSERT INTO contacts (device_id, jid, avatar_url, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, jid) DO UPDATE SET
+			avatar_url = excluded.avatar_url,
+			updated_at = excluded.updated_at
+	`, deviceID, jid, avatarURL, now, now)
+	return err
+}
+
+// UpdateContactAnnotations replaces the tags and/or notes of a contact in one
+// transaction, creating the entry when needed.
+func (r *SQLiteRepository) UpdateContactAnnotations(deviceID, jid string, tags []string, notes *string) error {
+	if deviceID == "" || jid == "" {
+		return fmt.Errorf("contact requires a device id and jid")
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	if _, err := tx.Exec(`
+		INSERT INTO contacts (device_id, jid, created_at, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(device_id, jid) DO UPDATE SET updated_at = excluded.updated_at
+	`, deviceID, jid, now, now); err != nil {
+		return err
+	}
+
+	if notes != nil {
+		if _, err := tx.Exec("UPDATE contacts SET notes = ? WHERE device_id = ? AND jid = ?", *notes, deviceID, jid); err != nil {
+			return err
+		}
+	}
+
+	if tags != nil {
+		if _, err := tx.Exec("DELETE FROM contact_tags WHERE device_id = ? AND jid = ?", deviceID, jid); err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if _, err := tx.Exec(`
+				INSERT INTO contact_tags (device_id, jid, tag) VALUES (?, ?, ?)
+				ON CONFLICT(device_id, jid, tag) DO NOTHING
+			`, deviceID, jid, tag); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+const contactColumns = `device_id, jid, phone, lid, push_name, business_name, saved_name, avatar_url,
+	notes, last_interaction_at, created_at, updated_at`
+
+// GetContact returns a single contact with its tags, or nil when it is unknown.
+func (r *SQLiteRepository) GetContact(deviceID, jid string) (*domainChatStorage.Contact, error) {
+	row := r.db.QueryRow("SELECT "+contactColumns+" FROM contacts WHERE device_id = ? AND jid = ?", deviceID, jid)
+	contact, err := scanContact(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadContactTags([]*domainChatStorage.Contact{contact}); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// GetContacts returns contacts matching filter, most recent interaction first
+// and never-contacted entries last.
+func (r *SQLiteRepository) GetContacts(filter *domainChatStorage.ContactFilter) ([]*domainChatStorage.Contact, error) {
+	query := "SELECT " + contactColumns + " FROM contacts"
+
+	conditions, args := r.buildContactFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY last_interaction_at IS NULL, last_interaction_at DESC, jid"
+
+	if filter != nil && filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+		if filter.Offset > 0 {
+			query += " OFFSET ?"
+			args = append(args, filter.Offset)
+		}
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := make([]*domainChatStorage.Contact, 0)
+	for rows.Next() {
+		contact, err := scanContact(rows)
+		if err != nil {
+			return nil, err
+		}
+		contacts = append(contacts, contact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadContactTags(contacts); err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// CountContacts returns the number of contacts matching filter.
+func (r *SQLiteRepository) CountContacts(filter *domainChatStorage.ContactFilter) (int64, error) {
+	query := "SELECT COUNT(*) FROM contacts"
+	conditions, args := r.buildContactFilterQuery(filter)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	return r.getCount(query, args...)
+}
+
+func scanContact(row interface{ Scan(dest ...any) error }) (*domainChatStorage.Contact, error) {
+	contact := &domainChatStorage.Contact{Tags: []string{}}
+	var lastInteraction sql.NullTime
+	if err := row.Scan(
+		&contact.DeviceID, &contact.JID, &contact.Phone, &contact.LID, &contact.PushName, &contact.BusinessName,
+		&contact.SavedName, &contact.AvatarURL, &contact.Notes, &lastInteraction, &contact.CreatedAt, &contact.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if lastInteraction.Valid {
+		contact.LastInteractionAt = &lastInteraction.Time
+	}
+	return contact, nil
+}
+
+// loadContactTags fills in the tags of contacts with a single query.
+func (r *SQLiteRepository) loadContactTags(contacts []*domainChatStorage.Contact) error {
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	byKey := make(map[string]*domainChatStorage.Contact, len(contacts))
+	placeholders := make([]string, 0, len(contacts))
+	args := make([]any, 0, len(contacts)*2)
+	for _, contact := range contacts {
+		byKey[contact.DeviceID+"|"+contact.JID] = contact
+		placeholders = append(placeholders, "(device_id = ? AND jid = ?)")
+		args = append(args, contact.DeviceID, contact.JID)
+	}
+
+	rows, err := r.db.Query(
+		"SELECT device_id, jid, tag FROM contact_tags WHERE "+strings.Join(placeholders, " OR ")+" ORDER BY tag",
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deviceID, jid, tag string
+		if err := rows.Scan(&deviceID, &jid, &tag); err != nil {
+			return err
+		}
+		if contact, ok := byKey[deviceID+"|"+jid]; ok {
+			contact.Tags = append(contact.Tags, tag)
+		}
+	}
+	return rows.Err()
+}
+
+// buildContactFilterQuery builds the WHERE conditions shared by the contact list and count queries.
+func (r *SQLiteRepository) buildContactFilterQuery(filter *domainChatStorage.ContactFilter) ([]string, []any) {
+	var conditions []string
+	var args []any
+	if filter == nil {
+		return conditions, args
+	}
+
+	if filter.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filter.DeviceID)
+	}
+	if filter.Search != "" {
+		pattern := "%" + strings.ToLower(filter.Search) + "%"
+		conditions = append(conditions, `(LOWER(jid) LIKE ? OR phone LIKE ? OR LOWER(push_name) LIKE ?
+			OR LOWER(business_name) LIKE ? OR LOWER(saved_name) LIKE ? OR LOWER(notes) LIKE ?)`)
+		args = append(args, pattern, pattern, pattern, pattern, pattern, pattern)
+	}
+	if filter.Tag != "" {
+		conditions = append(conditions, `EXISTS (
+			SELECT 1 FROM contact_tags t
+			WHERE t.device_id = contacts.device_id AND t.jid = contacts.jid AND t.tag = ?
+		)`)
+		args = append(args, filter.Tag)
+	}
+	return conditions, args
+}
+
+// SavePresence replaces the stored presence of a user.
+func (r *SQLiteRepository) SavePresence(presence *domainChatStorage.Presence) error {
+	if presence == nil || presence.DeviceID == "" || presence.JID == "" {
+		return fmt.Errorf("presence requires a device id and jid")
+	}
+	if presence.UpdatedAt.IsZero() {
+		presence.UpdatedAt = time.Now()
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO presences (
+			device_id, jid, available, last_seen_at, chat_jid, chat_state, chat_media, chat_state_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, jid) DO UPDATE SET
+			available = excluded.available,
+			last_seen_at = excluded.last_seen_at,
+			chat_jid = excluded.chat_jid,
+			chat_state = excluded.chat_state,
+			chat_media = excluded.chat_media,
+			chat_state_at = excluded.chat_state_at,
+			updated_at = excluded.updated_at
+	`, presence.DeviceID, presence.JID, presence.Available, utcTimeOrNil(presence.LastSeenAt), presence.ChatJID,
+		presence.ChatState, presence.ChatMedia, utcTimeOrNil(presence.ChatStateAt), presence.UpdatedAt.UTC())
+	return err
+}
+
+// GetPresence returns the last known presence of a user, or nil when none was seen.
+func (r *SQLiteRepository) GetPresence(deviceID, jid string) (*domainChatStorage.Presence, error) {
+	presence := &domainChatStorage.Presence{}
+	var lastSeen, chatStateAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT device_id, jid, available, last_seen_at, chat_jid, chat_state, chat_media, chat_state_at, updated_at
+		FROM presences WHERE device_id = ? AND jid = ?
+	`, deviceID, jid).Scan(
+		&presence.DeviceID, &presence.JID, &presence.Available, &lastSeen, &presence.ChatJID,
+		&presence.ChatState, &presence.ChatMedia, &chatStateAt, &presence.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lastSeen.Valid {
+		presence.LastSeenAt = &lastSeen.Time
+	}
+	if chatStateAt.Valid {
+		presence.ChatStateAt = &chatStateAt.Time
+	}
+	return presence, nil
+}
+
+// AddPresenceSubscription records a presence subscription; adding it twice is a no-op.
+func (r *SQLiteRepository) AddPresenceSubscription(deviceID, jid string) error {
+	if deviceID == "" || jid == "" {
+		return fmt.Errorf("presence subscription requires a device id and jid")
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO presence_subscriptions (device_id, jid, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(device_id, jid) DO NOTHING
+	`, deviceID, jid, time.Now().UTC())
+	return err
+}
+
+// RemovePresenceSubscription forgets a presence subscription.
+func (r *SQLiteRepository) RemovePresenceSubscription(deviceID, jid string) error {
+	_, err := r.db.Exec("DELETE FROM presence_subscriptions WHERE device_id = ? AND jid = ?", deviceID, jid)
+	return err
+}
+
+// HasPresenceSubscription reports whether the device is subscribed to jid.
+func (r *SQLiteRepository) HasPresenceSubscription(deviceID, jid string) (bool, error) {
+	count, err := r.getCount("SELECT COUNT(*) FROM presence_subscriptions WHERE device_id = ? AND jid = ?", deviceID, jid)
+	return count > 0, err
+}
+
+// GetPresenceSubscriptions returns the JIDs the device is subscribed to, oldest first.
+func (r *SQLiteRepository) GetPresenceSubscriptions(deviceID string) ([]string, error) {
+	rows, err := r.db.Query(
+		"SELECT jid FROM presence_subscriptions WHERE device_id = ? ORDER BY created_at, jid",
+		deviceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jids := make([]string, 0)
+	for rows.Next() {
+		var jid string
+		if err := rows.Scan(&jid); err != nil {
+			return nil, err
+		}
+		jids = append(jids, jid)
+	}
+	return jids, rows.Err()
+}
+
+// SaveLabel upserts a WhatsApp Business label.
+func (r *SQLiteRepository) SaveLabel(label *domainChatStorage.Label) error {
+	if label == nil || label.DeviceID == "" || label.ID == "" {
+		return fmt.Errorf("label requires a device id and label id")
+	}
+	if label.UpdatedAt.IsZero() {
+		label.UpdatedAt = time.Now()
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO labels (device_id, label_id, name, color, deleted, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id, label_id) DO UPDATE SET
+			name = excluded.name,
+			color = excluded.color,
+			deleted = excluded.deleted,
+			updated_at = excluded.updated_at
+	`, label.DeviceID, label.ID, label.Name, label.Color, label.Deleted, label.UpdatedAt.UTC())
+	return err
+}
+
+// GetLabels returns the device's labels ordered by id.
+func (r *SQLiteRepository) GetLabels(deviceID string) ([]*domainChatStorage.Label, error) {
+	rows, err := r.db.Query(`
+		SELECT device_id, label_id, name, color, deleted, updated_at
+		FROM labels WHERE device_id = ? ORDER BY label_id
+	`, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := make([]*domainChatStorage.Label, 0)
+	for rows.Next() {
+		label := &domainChatStorage.Label{}
+		if err := rows.Scan(&label.DeviceID, &label.ID, &label.Name, &label.Color, &label.Deleted, &label.UpdatedAt); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+	return labels, rows.Err()
+}
+
+// SetChatLabel applies or removes a label on a chat.
+func (r *SQLiteRepository) SetChatLabel(deviceID, chatJID, labelID string, labeled bool) error {
+	if deviceID == "" || chatJID == "" || labelID == "" {
+		return fmt.Errorf("chat label requires a device id, chat jid and label id")
+	}
+	if !labeled {
+		_, err := r.db.Exec("DELETE FROM chat_labels WHERE device_id = ? AND chat_jid = ? AND label_id = ?", deviceID, chatJID, labelID)
+		return err
+	}
+	_, err := r.db.Exec(`
+		INSERT INTO chat_labels (device_id, chat_jid, label_id) VALUES (?, ?, ?)
+		ON CONFLICT(device_id, chat_jid, label_id) DO NOTHING
+	`, deviceID, chatJID, labelID)
+	return err
+}
+
+// GetChatLabelIDs returns the ids of the labels applied to a chat.
+func (r *SQLiteRepository) GetChatLabelIDs(deviceID, chatJID string) ([]string, error) {
+	rows, err := r.db.Query(
+		"SELECT label_id FROM chat_labels WHERE device_id = ? AND chat_jid = ? ORDER BY label_id",
+		deviceID, chatJID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func utcTimeOrNil(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// _____________________________________________________________________________________________________________________
+
+// initializeSchema creates or migrates the database schema
+func (r *SQLiteRepository) InitializeSchema() error {
+	// Get current schema version
+	version, err := r.getSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	// Run migrations based on version
+	migrations := r.getMigrations()
+	for i := version; i < len(migrations); i++ {
+		if err := r.runMigration(migrations[i], i+1); err != nil {
+			return fmt.Errorf("failed to run migration %d: %w", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// getSchemaVersion returns the current schema version
+func (r *SQLiteRepository) getSchemaVersion() (int, error) {
+	// Create schema_info table if it doesn't exist
+	_, err := r.db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_info (
+			version INTEGER PRIMARY KEY,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return 0, err
+	}
+
+	// Get current version
+	var version int
+	err = r.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_info").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// runMigration executes a migration
+func (r *SQLiteRepository) runMigration(migration string, version int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Execute migration (single statement)
+	if _, err := tx.Exec(migration); err != nil {
+		return err
+	}
+
+	// Update schema version - delete then insert for cross-db compatibility
+	_, _ = tx.Exec("DELETE FROM schema_info WHERE version = ?", version)
+	if _, err := tx.Exec("INSERT INTO schema_info (version) VALUES (?)", version); err != nil {
 		return err
 	}
 
@@ -2596,5 +3925,194 @@ func (r *SQLiteRepository) getMigrations() []string {
 		`CREATE INDEX IF NOT EXISTS idx_chatwoot_links_conversation_account ON chatwoot_message_links(chatwoot_conversation_id, chatwoot_account_id, updated_at)`,
 		// Migration 43: Count/delete message links by owning config without a full-table scan
 		`CREATE INDEX IF NOT EXISTS idx_chatwoot_links_config ON chatwoot_message_links(chatwoot_config_id)`,
+		// Migration 44: Append-only audit trail of mutating REST/MCP calls
+		`CREATE TABLE IF NOT EXISTS audit_logs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			source VARCHAR(20) NOT NULL DEFAULT '',
+			principal_type VARCHAR(20) NOT NULL DEFAULT '',
+			principal VARCHAR(255) NOT NULL DEFAULT '',
+			client_id VARCHAR(255) NOT NULL DEFAULT '',
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			action VARCHAR(255) NOT NULL DEFAULT '',
+			target_jid VARCHAR(255) NOT NULL DEFAULT '',
+			message_id VARCHAR(255) NOT NULL DEFAULT '',
+			result VARCHAR(20) NOT NULL DEFAULT '',
+			status_code INTEGER NOT NULL DEFAULT 0,
+			error_message TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)`,
+		// Migration 45: Page audit entries newest first
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at, id)`,
+		// Migration 46: Filter audit entries by device
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_device ON audit_logs(device_id, created_at)`,
+		// Migration 47: Filter audit entries by principal
+		`CREATE INDEX IF NOT EXISTS idx_audit_logs_principal ON audit_logs(principal, created_at)`,
+		// Migration 48: Cached group metadata, kept current from group events
+		`CREATE TABLE IF NOT EXISTS group_metadata (
+			device_id VARCHAR(255) NOT NULL,
+			group_jid VARCHAR(255) NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			topic TEXT NOT NULL DEFAULT '',
+			owner_jid VARCHAR(255) NOT NULL DEFAULT '',
+			is_locked BOOLEAN NOT NULL DEFAULT FALSE,
+			is_announce BOOLEAN NOT NULL DEFAULT FALSE,
+			is_ephemeral BOOLEAN NOT NULL DEFAULT FALSE,
+			ephemeral_seconds INTEGER NOT NULL DEFAULT 0,
+			join_approval BOOLEAN NOT NULL DEFAULT FALSE,
+			member_add_mode VARCHAR(50) NOT NULL DEFAULT '',
+			is_parent BOOLEAN NOT NULL DEFAULT FALSE,
+			linked_parent_jid VARCHAR(255) NOT NULL DEFAULT '',
+			group_created TIMESTAMP,
+			participants_json TEXT NOT NULL DEFAULT '[]',
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, group_jid)
+		)`,
+		// Migration 49: Append-only group participant join/leave/promote/demote history
+		`CREATE TABLE IF NOT EXISTS group_participant_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			group_jid VARCHAR(255) NOT NULL,
+			participant_jid VARCHAR(255) NOT NULL,
+			action VARCHAR(20) NOT NULL,
+			actor_jid VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)`,
+		// Migration 50: Page a group's participant history newest first
+		`CREATE INDEX IF NOT EXISTS idx_group_participant_events_group ON group_participant_events(device_id, group_jid, created_at, id)`,
+		// Migration 51: Incoming call history, one row per call updated through its lifecycle
+		`CREATE TABLE IF NOT EXISTS call_records (
+			device_id VARCHAR(255) NOT NULL,
+			call_id VARCHAR(255) NOT NULL,
+			chat_jid VARCHAR(255) NOT NULL DEFAULT '',
+			caller_jid VARCHAR(255) NOT NULL DEFAULT '',
+			group_jid VARCHAR(255) NOT NULL DEFAULT '',
+			is_video BOOLEAN NOT NULL DEFAULT FALSE,
+			status VARCHAR(20) NOT NULL,
+			auto_rejected BOOLEAN NOT NULL DEFAULT FALSE,
+			remote_platform VARCHAR(50) NOT NULL DEFAULT '',
+			remote_version VARCHAR(50) NOT NULL DEFAULT '',
+			end_reason VARCHAR(100) NOT NULL DEFAULT '',
+			offered_at TIMESTAMP NOT NULL,
+			accepted_at TIMESTAMP,
+			ended_at TIMESTAMP,
+			duration_seconds INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (device_id, call_id)
+		)`,
+		// Migration 52: Page a device's call history newest first
+		`CREATE INDEX IF NOT EXISTS idx_call_records_offered ON call_records(device_id, offered_at)`,
+		// Migration 53: Per-device auto-reject override (NULL = global setting)
+		`ALTER TABLE devices ADD COLUMN call_auto_reject BOOLEAN DEFAULT NULL`,
+		// Migration 54: Per-device text sent to rejected callers
+		`ALTER TABLE devices ADD COLUMN call_reject_message TEXT DEFAULT ''`,
+		// Migration 55: Contact book, kept current from contact, push name and message events
+		`CREATE TABLE IF NOT EXISTS contacts (
+			device_id VARCHAR(255) NOT NULL,
+			jid VARCHAR(255) NOT NULL,
+			phone VARCHAR(50) NOT NULL DEFAULT '',
+			lid VARCHAR(255) NOT NULL DEFAULT '',
+			push_name TEXT NOT NULL DEFAULT '',
+			business_name TEXT NOT NULL DEFAULT '',
+			saved_name TEXT NOT NULL DEFAULT '',
+			avatar_url TEXT NOT NULL DEFAULT '',
+			notes TEXT NOT NULL DEFAULT '',
+			last_interaction_at TIMESTAMP,
+			created_at TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, jid)
+		)`,
+		// Migration 56: Page a device's contacts by most recent interaction
+		`CREATE INDEX IF NOT EXISTS idx_contacts_interaction ON contacts(device_id, last_interaction_at)`,
+		// Migration 57: Free-form contact tags set through the API
+		`CREATE TABLE IF NOT EXISTS contact_tags (
+			device_id VARCHAR(255) NOT NULL,
+			jid VARCHAR(255) NOT NULL,
+			tag VARCHAR(100) NOT NULL,
+			PRIMARY KEY (device_id, jid, tag)
+		)`,
+		// Migration 58: Filter contacts by tag
+		`CREATE INDEX IF NOT EXISTS idx_contact_tags_tag ON contact_tags(device_id, tag)`,
+
+		// Migration 59: Last known presence per user
+		`CREATE TABLE IF NOT EXISTS presences (
+			device_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			available BOOLEAN NOT NULL DEFAULT FALSE,
+			last_seen_at TIMESTAMP,
+			chat_jid TEXT NOT NULL DEFAULT '',
+			chat_state TEXT NOT NULL DEFAULT '',
+			chat_media TEXT NOT NULL DEFAULT '',
+			chat_state_at TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, jid)
+		)`,
+
+		// Migration 60: Presence subscriptions restored after reconnect
+		`CREATE TABLE IF NOT EXISTS presence_subscriptions (
+			device_id TEXT NOT NULL,
+			jid TEXT NOT NULL,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, jid)
+		)`,
+
+		// Migration 61: Mirror Chatwoot conversation labels to WhatsApp chat labels
+		`ALTER TABLE chatwoot_device_configs ADD COLUMN label_sync BOOLEAN NOT NULL DEFAULT FALSE`,
+		// Migration 62: Archive the WhatsApp chat when its conversation is resolved
+		`ALTER TABLE chatwoot_device_configs ADD COLUMN archive_on_resolve BOOLEAN NOT NULL DEFAULT FALSE`,
+		// Migration 63: Message sent when a conversation is resolved
+		`ALTER TABLE chatwoot_device_configs ADD COLUMN closing_message TEXT NOT NULL DEFAULT ''`,
+
+		// Migration 64: WhatsApp Business labels seen in app state
+		`CREATE TABLE IF NOT EXISTS labels (
+			device_id TEXT NOT NULL,
+			label_id TEXT NOT NULL,
+			name TEXT NOT NULL DEFAULT '',
+			color INTEGER NOT NULL DEFAULT 0,
+			deleted BOOLEAN NOT NULL DEFAULT FALSE,
+			updated_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (device_id, label_id)
+		)`,
+
+		// Migration 65: Labels applied to each chat
+		`CREATE TABLE IF NOT EXISTS chat_labels (
+			device_id TEXT NOT NULL,
+			chat_jid TEXT NOT NULL,
+			label_id TEXT NOT NULL,
+			PRIMARY KEY (device_id, chat_jid, label_id)
+		)`,
+
+		// Migration 66: Per-device outbound proxy ('' = WHATSAPP_PROXY)
+		`ALTER TABLE devices ADD COLUMN proxy_url TEXT DEFAULT ''`,
+		// Migration 67: Per-device fallback proxies, JSON array
+		`ALTER TABLE devices ADD COLUMN proxy_pool TEXT DEFAULT ''`,
+
+		// Migration 68: Operator-assigned device tags, JSON array
+		`ALTER TABLE devices ADD COLUMN tags TEXT DEFAULT ''`,
+		// Migration 69: Operator-assigned device metadata, JSON object
+		`ALTER TABLE devices ADD COLUMN metadata TEXT DEFAULT ''`,
+
+		// Migration 70: Per-device webhook HTTP method ('' = POST)
+		`ALTER TABLE devices ADD COLUMN webhook_method TEXT DEFAULT ''`,
+		// Migration 71: Per-device static webhook headers, JSON object
+		`ALTER TABLE devices ADD COLUMN webhook_headers TEXT DEFAULT ''`,
+		// Migration 72: Per-device webhook body template ('' = raw JSON payload)
+		`ALTER TABLE devices ADD COLUMN webhook_template TEXT DEFAULT ''`,
+		// Migration 73: Per-device webhook signature algorithm ('' = sha256)
+		`ALTER TABLE devices ADD COLUMN webhook_signature_algorithm TEXT DEFAULT ''`,
+		// Migration 74: Append-only group description history
+		`CREATE TABLE IF NOT EXISTS group_description_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			group_jid VARCHAR(255) NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			topic_id VARCHAR(255) NOT NULL DEFAULT '',
+			deleted BOOLEAN NOT NULL DEFAULT FALSE,
+			actor_jid VARCHAR(255) NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL
+		)`,
+		// Migration 75: Page a group's description history newest first
+		`CREATE INDEX IF NOT EXISTS idx_group_description_events_group ON group_description_events(device_id, group_jid, created_at, id)`,
+
+		// Migration 76: Link queued Chatwoot forward retries to the trace that queued them
+		`ALTER TABLE chatwoot_forward_queue ADD COLUMN trace_parent TEXT DEFAULT ''`,
 	}
 }
@@ -0,0 +1,84 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEntriesFilterAndPaginate(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	base := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	seed := []*domainChatStorage.AuditEntry{
+		{Source: "rest", PrincipalType: "basic", Principal: "admin", DeviceID: "dev-1", Action: "POST /send/message", TargetJID: "628@s.whatsapp.net", MessageID: "M1", Result: "success", StatusCode: 200, CreatedAt: base},
+		{Source: "rest", PrincipalType: "basic", Principal: "ops", DeviceID: "dev-1", Action: "POST /devices/:device_id/logout", Result: "success", StatusCode: 200, CreatedAt: base.Add(time.Minute)},
+		{Source: "mcp", PrincipalType: "oauth", Principal: "admin", ClientID: "cli", DeviceID: "dev-2", Action: "whatsapp_group.remove_participants", TargetJID: "123@g.us", Result: "error", ErrorMessage: "not admin", CreatedAt: base.Add(2 * time.Minute)},
+	}
+	for _, entry := range seed {
+		require.NoError(t, repo.StoreAuditEntry(entry))
+		assert.NotZero(t, entry.ID)
+	}
+
+	all, err := repo.GetAuditEntries(&domainChatStorage.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "whatsapp_group.remove_participants", all[0].Action, "newest first")
+	assert.Equal(t, "cli", all[0].ClientID)
+	assert.Equal(t, "not admin", all[0].ErrorMessage)
+
+	tests := []struct {
+		name   string
+		filter domainChatStorage.AuditFilter
+		want   int64
+	}{
+		{"by principal", domainChatStorage.AuditFilter{Principal: "admin"}, 2},
+		{"by device", domainChatStorage.AuditFilter{DeviceID: "dev-1"}, 2},
+		{"by result", domainChatStorage.AuditFilter{Result: "error"}, 1},
+		{"by source and target", domainChatStorage.AuditFilter{Source: "rest", TargetJID: "628@s.whatsapp.net"}, 1},
+		{"by message id", domainChatStorage.AuditFilter{MessageID: "M1"}, 1},
+		{"since", domainChatStorage.AuditFilter{Since: timePtr(base.Add(time.Minute))}, 2},
+		{"until", domainChatStorage.AuditFilter{Until: timePtr(base)}, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			count, err := repo.CountAuditEntries(&tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, count)
+
+			entries, err := repo.GetAuditEntries(&tc.filter)
+			require.NoError(t, err)
+			assert.Len(t, entries, int(tc.want))
+		})
+	}
+
+	page, err := repo.GetAuditEntries(&domainChatStorage.AuditFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "POST /devices/:device_id/logout", page[0].Action)
+}
+
+func TestAuditEntriesSurviveDeviceDataDeletion(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	require.NoError(t, repo.StoreAuditEntry(&domainChatStorage.AuditEntry{
+		Source: "rest", DeviceID: "dev-1", Action: "DELETE /devices/:device_id", Result: "success",
+	}))
+	require.NoError(t, repo.DeleteDeviceData("dev-1"))
+	require.NoError(t, repo.TruncateAllChats())
+
+	count, err := repo.CountAuditEntries(&domainChatStorage.AuditFilter{DeviceID: "dev-1"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestStoreAuditEntryRequiresAction(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	assert.Error(t, repo.StoreAuditEntry(&domainChatStorage.AuditEntry{Source: "rest"}))
+	assert.Error(t, repo.StoreAuditEntry(nil))
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
@@ -0,0 +1,122 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallRecordsLifecycleAndFilters(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	base := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	seed := []*domainChatStorage.CallRecord{
+		{DeviceID: "dev-1", CallID: "C1", ChatJID: "628111@s.whatsapp.net", CallerJID: "628111@s.whatsapp.net", Status: domainChatStorage.CallStatusRinging, OfferedAt: base},
+		{DeviceID: "dev-1", CallID: "C2", ChatJID: "628222@s.whatsapp.net", CallerJID: "628222@s.whatsapp.net", Status: domainChatStorage.CallStatusRejected, AutoRejected: true, OfferedAt: base.Add(time.Minute)},
+		{DeviceID: "dev-2", CallID: "C3", ChatJID: "123@g.us", CallerJID: "628111@s.whatsapp.net", GroupJID: "123@g.us", IsVideo: true, Status: domainChatStorage.CallStatusRinging, OfferedAt: base.Add(2 * time.Minute)},
+	}
+	for _, record := range seed {
+		require.NoError(t, repo.SaveCallRecord(record))
+	}
+
+	// Saving the same call again updates it in place.
+	accepted := base.Add(10 * time.Second)
+	ended := base.Add(70 * time.Second)
+	seed[0].Status = domainChatStorage.CallStatusEnded
+	seed[0].AcceptedAt = &accepted
+	seed[0].EndedAt = &ended
+	seed[0].DurationSeconds = 60
+	require.NoError(t, repo.SaveCallRecord(seed[0]))
+
+	got, err := repo.GetCallRecord("dev-1", "C1")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, domainChatStorage.CallStatusEnded, got.Status)
+	assert.Equal(t, 60, got.DurationSeconds)
+	require.NotNil(t, got.AcceptedAt)
+	assert.True(t, accepted.Equal(*got.AcceptedAt))
+	require.NotNil(t, got.EndedAt)
+	assert.True(t, ended.Equal(*got.EndedAt))
+
+	missing, err := repo.GetCallRecord("dev-2", "C1")
+	require.NoError(t, err)
+	assert.Nil(t, missing, "calls are scoped per device")
+
+	all, err := repo.GetCallRecords(&domainChatStorage.CallRecordFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "C3", all[0].CallID, "newest offer first")
+	assert.True(t, all[0].IsVideo)
+
+	tests := []struct {
+		name   string
+		filter domainChatStorage.CallRecordFilter
+		want   int64
+	}{
+		{"by device", domainChatStorage.CallRecordFilter{DeviceID: "dev-1"}, 2},
+		{"by chat", domainChatStorage.CallRecordFilter{ChatJID: "123@g.us"}, 1},
+		{"by status", domainChatStorage.CallRecordFilter{Status: domainChatStorage.CallStatusRejected}, 1},
+		{"since", domainChatStorage.CallRecordFilter{Since: timePtr(base.Add(time.Minute))}, 2},
+		{"until in another zone", domainChatStorage.CallRecordFilter{Until: timePtr(base.In(time.FixedZone("WIB", 7*3600)))}, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			count, err := repo.CountCallRecords(&tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, count)
+
+			records, err := repo.GetCallRecords(&tc.filter)
+			require.NoError(t, err)
+			assert.Len(t, records, int(tc.want))
+		})
+	}
+
+	page, err := repo.GetCallRecords(&domainChatStorage.CallRecordFilter{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "C2", page[0].CallID)
+
+	require.NoError(t, repo.DeleteDeviceData("dev-1"))
+	remaining, err := repo.CountCallRecords(&domainChatStorage.CallRecordFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), remaining)
+}
+
+func TestDeviceCallConfigRoundTrip(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	err := repo.SetDeviceCallConfig("dev-1", &domainChatStorage.DeviceCallConfig{})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.SaveDeviceRecord(&domainChatStorage.DeviceRecord{DeviceID: "dev-1", JID: "628111@s.whatsapp.net"}))
+
+	cfg, err := repo.GetDeviceCallConfig("dev-1")
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.Nil(t, cfg.AutoReject, "unset means the global setting applies")
+	assert.Empty(t, cfg.RejectMessage)
+
+	enabled := true
+	require.NoError(t, repo.SetDeviceCallConfig("dev-1", &domainChatStorage.DeviceCallConfig{
+		AutoReject:    &enabled,
+		RejectMessage: "Calls are not monitored, please send a message.",
+	}))
+
+	// Re-saving the device record must not reset its call handling.
+	require.NoError(t, repo.SaveDeviceRecord(&domainChatStorage.DeviceRecord{DeviceID: "dev-1", JID: "628111@s.whatsapp.net", DisplayName: "Support"}))
+
+	record, err := repo.GetDeviceRecordByJID("628111@s.whatsapp.net")
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	require.NotNil(t, record.CallAutoReject)
+	assert.True(t, *record.CallAutoReject)
+	assert.Equal(t, "Calls are not monitored, please send a message.", record.CallRejectMessage)
+
+	missing, err := repo.GetDeviceCallConfig("dev-9")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
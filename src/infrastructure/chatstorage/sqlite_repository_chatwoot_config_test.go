@@ -315,3 +315,39 @@ func TestSQLiteRepositoryDeleteChatwootMessageLinksByConfig(t *testing.T) {
 		t.Fatal("legacy link must survive")
 	}
 }
+
+func TestSQLiteRepositoryChatwootDeviceConfigSyncOptions(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	cfg := &domainChatStorage.ChatwootDeviceConfig{
+		DeviceID:         "busine",
+		ChatwootURL:      "https://chat.example.com",
+		AccountID:        1,
+		InboxID:          5,
+		APIToken:         "tok-a",
+		Enabled:          true,
+		LabelSync:        true,
+		ArchiveOnResolve: true,
+		ClosingMessage:   "Thanks for reaching out!",
+	}
+	if err := repo.SaveChatwootDeviceConfig(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+	got, err := repo.GetChatwootDeviceConfig("busine")
+	if err != nil || got == nil {
+		t.Fatalf("get config: %v (got=%v)", err, got)
+	}
+	if !got.LabelSync || !got.ArchiveOnResolve || got.ClosingMessage != "Thanks for reaching out!" {
+		t.Fatalf("sync options not stored: %+v", got)
+	}
+
+	cfg.LabelSync = false
+	cfg.ClosingMessage = ""
+	if err := repo.SaveChatwootDeviceConfig(cfg); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	got, _ = repo.GetChatwootDeviceConfig("busine")
+	if got.LabelSync || !got.ArchiveOnResolve || got.ClosingMessage != "" {
+		t.Fatalf("sync options not updated: %+v", got)
+	}
+}
@@ -0,0 +1,124 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContactUpsertMergesNamesAndInteraction(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	later := time.Date(2026, time.October, 2, 10, 0, 0, 0, time.UTC)
+	earlier := later.Add(-time.Hour)
+
+	require.NoError(t, repo.UpsertContact(&domainChatStorage.Contact{
+		DeviceID: "dev-1", JID: "628111@s.whatsapp.net", Phone: "628111",
+		PushName: "Budi", LastInteractionAt: &later,
+	}))
+	// Empty fields and older interactions never overwrite what is known.
+	require.NoError(t, repo.UpsertContact(&domainChatStorage.Contact{
+		DeviceID: "dev-1", JID: "628111@s.whatsapp.net",
+		LID: "9001@lid", SavedName: "Budi Office", LastInteractionAt: &earlier,
+	}))
+
+	got, err := repo.GetContact("dev-1", "628111@s.whatsapp.net")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "628111", got.Phone)
+	assert.Equal(t, "9001@lid", got.LID)
+	assert.Equal(t, "Budi", got.PushName)
+	assert.Equal(t, "Budi Office", got.SavedName)
+	require.NotNil(t, got.LastInteractionAt)
+	assert.True(t, later.Equal(*got.LastInteractionAt))
+	assert.Equal(t, []string{}, got.Tags)
+
+	missing, err := repo.GetContact("dev-2", "628111@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.Nil(t, missing, "contacts are scoped per device")
+}
+
+func TestContactAvatarAndAnnotations(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	jid := "628222@s.whatsapp.net"
+
+	require.NoError(t, repo.SetContactAvatar("dev-1", jid, "https://pps.whatsapp.net/a.jpg"))
+	notes := "VIP customer"
+	require.NoError(t, repo.UpdateContactAnnotations("dev-1", jid, []string{"vip", "lead"}, &notes))
+
+	got, err := repo.GetContact("dev-1", jid)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "https://pps.whatsapp.net/a.jpg", got.AvatarURL)
+	assert.ElementsMatch(t, []string{"vip", "lead"}, got.Tags)
+	assert.Equal(t, "VIP customer", got.Notes)
+
+	// Event upserts leave tags and notes alone; nil annotations are untouched.
+	require.NoError(t, repo.UpsertContact(&domainChatStorage.Contact{DeviceID: "dev-1", JID: jid, PushName: "Sari"}))
+	require.NoError(t, repo.UpdateContactAnnotations("dev-1", jid, []string{"customer"}, nil))
+	require.NoError(t, repo.SetContactAvatar("dev-1", jid, ""))
+
+	got, err = repo.GetContact("dev-1", jid)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"customer"}, got.Tags)
+	assert.Equal(t, "VIP customer", got.Notes)
+	assert.Equal(t, "Sari", got.PushName)
+	assert.Empty(t, got.AvatarURL, "removed pictures clear the avatar")
+}
+
+func TestContactFiltersAndDeviceCleanup(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	base := time.Date(2026, time.October, 3, 8, 0, 0, 0, time.UTC)
+	older := base.Add(-time.Hour)
+	seed := []*domainChatStorage.Contact{
+		{DeviceID: "dev-1", JID: "628111@s.whatsapp.net", Phone: "628111", PushName: "Andi", LastInteractionAt: &older},
+		{DeviceID: "dev-1", JID: "628222@s.whatsapp.net", Phone: "628222", BusinessName: "Toko Sari", LastInteractionAt: &base},
+		{DeviceID: "dev-1", JID: "628333@s.whatsapp.net", Phone: "628333", SavedName: "Citra"},
+		{DeviceID: "dev-2", JID: "628444@s.whatsapp.net", Phone: "628444", PushName: "Dewi"},
+	}
+	for _, contact := range seed {
+		require.NoError(t, repo.UpsertContact(contact))
+	}
+	require.NoError(t, repo.UpdateContactAnnotations("dev-1", "628333@s.whatsapp.net", []string{"lead"}, nil))
+
+	all, err := repo.GetContacts(&domainChatStorage.ContactFilter{DeviceID: "dev-1"})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "628222@s.whatsapp.net", all[0].JID, "most recent interaction first")
+	assert.Equal(t, "628333@s.whatsapp.net", all[2].JID, "never-contacted last")
+	assert.Equal(t, []string{"lead"}, all[2].Tags)
+
+	tests := []struct {
+		name   string
+		filter domainChatStorage.ContactFilter
+		want   int64
+	}{
+		{"by device", domainChatStorage.ContactFilter{DeviceID: "dev-2"}, 1},
+		{"search name", domainChatStorage.ContactFilter{DeviceID: "dev-1", Search: "sari"}, 1},
+		{"search phone", domainChatStorage.ContactFilter{DeviceID: "dev-1", Search: "628111"}, 1},
+		{"by tag", domainChatStorage.ContactFilter{DeviceID: "dev-1", Tag: "lead"}, 1},
+		{"unknown tag", domainChatStorage.ContactFilter{DeviceID: "dev-1", Tag: "vip"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			count, err := repo.CountContacts(&filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, count)
+		})
+	}
+
+	page, err := repo.GetContacts(&domainChatStorage.ContactFilter{DeviceID: "dev-1", Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "628111@s.whatsapp.net", page[0].JID)
+
+	require.NoError(t, repo.DeleteDeviceData("dev-1"))
+	remaining, err := repo.CountContacts(&domainChatStorage.ContactFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), remaining)
+}
@@ -0,0 +1,54 @@
+package chatstorage
+
+import (
+	"database/sql"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceAttributesRoundTrip(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	err := repo.SetDeviceAttributes("dev-1", &domainChatStorage.DeviceAttributes{})
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	require.NoError(t, repo.SaveDeviceRecord(&domainChatStorage.DeviceRecord{DeviceID: "dev-1", JID: "628111@s.whatsapp.net"}))
+
+	attributes, err := repo.GetDeviceAttributes("dev-1")
+	require.NoError(t, err)
+	require.NotNil(t, attributes)
+	assert.Empty(t, attributes.Tags)
+	assert.Empty(t, attributes.Metadata)
+
+	require.NoError(t, repo.SetDeviceAttributes("dev-1", &domainChatStorage.DeviceAttributes{
+		Tags:     []string{"tenant-acme", "support"},
+		Metadata: map[string]string{"customer": "Acme", "team": "Support EU"},
+	}))
+
+	// Re-saving the device record must not reset its attributes.
+	require.NoError(t, repo.SaveDeviceRecord(&domainChatStorage.DeviceRecord{DeviceID: "dev-1", JID: "628111@s.whatsapp.net", DisplayName: "Support"}))
+
+	attributes, err = repo.GetDeviceAttributes("dev-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant-acme", "support"}, attributes.Tags)
+	assert.Equal(t, map[string]string{"customer": "Acme", "team": "Support EU"}, attributes.Metadata)
+
+	records, err := repo.ListDeviceRecords()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.JSONEq(t, `["tenant-acme","support"]`, records[0].Tags)
+	assert.JSONEq(t, `{"customer":"Acme","team":"Support EU"}`, records[0].Metadata)
+
+	require.NoError(t, repo.SetDeviceAttributes("dev-1", &domainChatStorage.DeviceAttributes{}))
+	record, err := repo.GetDeviceRecord("dev-1")
+	require.NoError(t, err)
+	assert.Empty(t, record.Tags)
+	assert.Empty(t, record.Metadata)
+
+	missing, err := repo.GetDeviceAttributes("dev-9")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
@@ -0,0 +1,153 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupMetadataUpsertAndLookup(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	missing, err := repo.GetGroupMetadata("dev-1", "120363@g.us")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	created := time.Date(2025, time.May, 4, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SaveGroupMetadata(&domainChatStorage.GroupMetadata{
+		DeviceID:         "dev-1",
+		GroupJID:         "120363@g.us",
+		Name:             "Moderators",
+		Topic:            "Rules",
+		IsLocked:         true,
+		IsEphemeral:      true,
+		EphemeralSeconds: 86400,
+		MemberAddMode:    "admin_add",
+		GroupCreated:     created,
+		Participants: []domainChatStorage.GroupMetadataParticipant{
+			{JID: "6281@s.whatsapp.net", IsAdmin: true, IsSuperAdmin: true},
+			{JID: "6282@s.whatsapp.net"},
+		},
+	}))
+
+	got, err := repo.GetGroupMetadata("dev-1", "120363@g.us")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "Moderators", got.Name)
+	assert.True(t, got.IsLocked)
+	assert.Equal(t, uint32(86400), got.EphemeralSeconds)
+	assert.Equal(t, "admin_add", got.MemberAddMode)
+	assert.True(t, got.GroupCreated.Equal(created))
+	assert.False(t, got.UpdatedAt.IsZero())
+	require.Len(t, got.Participants, 2)
+	assert.True(t, got.Participants[0].IsSuperAdmin)
+
+	got.Name = "Mods"
+	got.Participants = got.Participants[:1]
+	require.NoError(t, repo.SaveGroupMetadata(got))
+
+	updated, err := repo.GetGroupMetadata("dev-1", "120363@g.us")
+	require.NoError(t, err)
+	assert.Equal(t, "Mods", updated.Name)
+	assert.Len(t, updated.Participants, 1)
+
+	other, err := repo.GetGroupMetadata("dev-2", "120363@g.us")
+	require.NoError(t, err)
+	assert.Nil(t, other, "metadata is scoped per device")
+
+	require.NoError(t, repo.DeleteGroupMetadata("dev-1", "120363@g.us"))
+	deleted, err := repo.GetGroupMetadata("dev-1", "120363@g.us")
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestGroupParticipantEventsFilterAndPaginate(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	base := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.StoreGroupParticipantEvents([]*domainChatStorage.GroupParticipantEvent{
+		{DeviceID: "dev-1", GroupJID: "g1@g.us", ParticipantJID: "6281@s.whatsapp.net", Action: "join", CreatedAt: base},
+		{DeviceID: "dev-1", GroupJID: "g1@g.us", ParticipantJID: "6281@s.whatsapp.net", Action: "promote", ActorJID: "6289@s.whatsapp.net", CreatedAt: base.Add(time.Minute)},
+		{DeviceID: "dev-1", GroupJID: "g1@g.us", ParticipantJID: "6282@s.whatsapp.net", Action: "leave", CreatedAt: base.Add(2 * time.Minute)},
+		{DeviceID: "dev-1", GroupJID: "g2@g.us", ParticipantJID: "6281@s.whatsapp.net", Action: "join", CreatedAt: base},
+	}))
+
+	events, err := repo.GetGroupParticipantEvents(&domainChatStorage.GroupParticipantEventFilter{DeviceID: "dev-1", GroupJID: "g1@g.us"})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "leave", events[0].Action, "newest first")
+	assert.Equal(t, "6289@s.whatsapp.net", events[1].ActorJID)
+
+	count, err := repo.CountGroupParticipantEvents(&domainChatStorage.GroupParticipantEventFilter{GroupJID: "g1@g.us", ParticipantJID: "6281@s.whatsapp.net"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	page, err := repo.GetGroupParticipantEvents(&domainChatStorage.GroupParticipantEventFilter{GroupJID: "g1@g.us", Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "promote", page[0].Action)
+
+	require.Error(t, repo.StoreGroupParticipantEvents([]*domainChatStorage.GroupParticipantEvent{{GroupJID: "g1@g.us"}}))
+}
+
+func TestGroupDescriptionEventsPaginateNewestFirst(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	base := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	for _, event := range []*domainChatStorage.GroupDescriptionEvent{
+		{DeviceID: "dev-1", GroupJID: "g1@g.us", Description: "Rules v1", TopicID: "T1", CreatedAt: base},
+		{DeviceID: "dev-1", GroupJID: "g1@g.us", Description: "Rules v2", TopicID: "T2", ActorJID: "6289@s.whatsapp.net", CreatedAt: base.Add(time.Minute)},
+		{DeviceID: "dev-1", GroupJID: "g1@g.us", Deleted: true, TopicID: "T3", CreatedAt: base.Add(2 * time.Minute)},
+		{DeviceID: "dev-2", GroupJID: "g1@g.us", Description: "Other device", CreatedAt: base},
+	} {
+		require.NoError(t, repo.StoreGroupDescriptionEvent(event))
+		assert.NotZero(t, event.ID)
+	}
+
+	events, err := repo.GetGroupDescriptionEvents(&domainChatStorage.GroupDescriptionEventFilter{DeviceID: "dev-1", GroupJID: "g1@g.us"})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.True(t, events[0].Deleted, "newest first")
+	assert.Equal(t, "Rules v2", events[1].Description)
+	assert.Equal(t, "6289@s.whatsapp.net", events[1].ActorJID)
+
+	count, err := repo.CountGroupDescriptionEvents(&domainChatStorage.GroupDescriptionEventFilter{GroupJID: "g1@g.us"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+
+	page, err := repo.GetGroupDescriptionEvents(&domainChatStorage.GroupDescriptionEventFilter{DeviceID: "dev-1", GroupJID: "g1@g.us", Limit: 1, Offset: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "T1", page[0].TopicID)
+
+	require.Error(t, repo.StoreGroupDescriptionEvent(&domainChatStorage.GroupDescriptionEvent{DeviceID: "dev-1"}))
+}
+
+func TestDeleteDeviceDataRemovesGroupCache(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	require.NoError(t, repo.SaveGroupMetadata(&domainChatStorage.GroupMetadata{DeviceID: "dev-1", GroupJID: "g1@g.us", Name: "One"}))
+	require.NoError(t, repo.SaveGroupMetadata(&domainChatStorage.GroupMetadata{DeviceID: "dev-2", GroupJID: "g1@g.us", Name: "One"}))
+	require.NoError(t, repo.StoreGroupParticipantEvents([]*domainChatStorage.GroupParticipantEvent{
+		{DeviceID: "dev-1", GroupJID: "g1@g.us", ParticipantJID: "6281@s.whatsapp.net", Action: "join"},
+	}))
+	require.NoError(t, repo.StoreGroupDescriptionEvent(&domainChatStorage.GroupDescriptionEvent{DeviceID: "dev-1", GroupJID: "g1@g.us", Description: "Rules"}))
+
+	require.NoError(t, repo.DeleteDeviceData("dev-1"))
+
+	gone, err := repo.GetGroupMetadata("dev-1", "g1@g.us")
+	require.NoError(t, err)
+	assert.Nil(t, gone)
+	kept, err := repo.GetGroupMetadata("dev-2", "g1@g.us")
+	require.NoError(t, err)
+	assert.NotNil(t, kept)
+	count, err := repo.CountGroupParticipantEvents(&domainChatStorage.GroupParticipantEventFilter{DeviceID: "dev-1"})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	count, err = repo.CountGroupDescriptionEvents(&domainChatStorage.GroupDescriptionEventFilter{DeviceID: "dev-1"})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,55 @@
+package chatstorage
+
+import (
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelSaveAndList(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	require.NoError(t, repo.SaveLabel(&domainChatStorage.Label{DeviceID: "dev-1", ID: "2", Name: "VIP", Color: 3}))
+	require.NoError(t, repo.SaveLabel(&domainChatStorage.Label{DeviceID: "dev-1", ID: "1", Name: "New customer"}))
+	require.NoError(t, repo.SaveLabel(&domainChatStorage.Label{DeviceID: "dev-1", ID: "2", Name: "Gold", Color: 4, Deleted: true}))
+	require.NoError(t, repo.SaveLabel(&domainChatStorage.Label{DeviceID: "dev-2", ID: "1", Name: "Other"}))
+	require.Error(t, repo.SaveLabel(&domainChatStorage.Label{DeviceID: "dev-1"}))
+
+	labels, err := repo.GetLabels("dev-1")
+	require.NoError(t, err)
+	require.Len(t, labels, 2)
+	assert.Equal(t, "1", labels[0].ID)
+	assert.Equal(t, "New customer", labels[0].Name)
+	assert.Equal(t, "Gold", labels[1].Name, "saving again replaces the label")
+	assert.Equal(t, int32(4), labels[1].Color)
+	assert.True(t, labels[1].Deleted)
+}
+
+func TestChatLabels(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	chat := "628111@s.whatsapp.net"
+
+	require.NoError(t, repo.SetChatLabel("dev-1", chat, "2", true))
+	require.NoError(t, repo.SetChatLabel("dev-1", chat, "1", true))
+	require.NoError(t, repo.SetChatLabel("dev-1", chat, "1", true), "labeling twice is a no-op")
+	require.NoError(t, repo.SetChatLabel("dev-2", chat, "3", true))
+
+	ids, err := repo.GetChatLabelIDs("dev-1", chat)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, ids)
+
+	require.NoError(t, repo.SetChatLabel("dev-1", chat, "2", false))
+	ids, err = repo.GetChatLabelIDs("dev-1", chat)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, ids)
+
+	require.NoError(t, repo.DeleteDeviceData("dev-1"))
+	ids, err = repo.GetChatLabelIDs("dev-1", chat)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	labels, err := repo.GetChatLabelIDs("dev-2", chat)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3"}, labels, "other devices keep their labels")
+}
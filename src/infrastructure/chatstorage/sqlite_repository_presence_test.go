@@ -0,0 +1,76 @@
+package chatstorage
+
+import (
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenceSaveAndGet(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	jid := "628111@s.whatsapp.net"
+
+	missing, err := repo.GetPresence("dev-1", jid)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	lastSeen := time.Date(2026, time.October, 4, 8, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.SavePresence(&domainChatStorage.Presence{DeviceID: "dev-1", JID: jid, LastSeenAt: &lastSeen}))
+
+	typingAt := lastSeen.Add(time.Minute)
+	require.NoError(t, repo.SavePresence(&domainChatStorage.Presence{
+		DeviceID: "dev-1", JID: jid, Available: true, LastSeenAt: &lastSeen,
+		ChatJID: jid, ChatState: "composing", ChatStateAt: &typingAt,
+	}))
+
+	got, err := repo.GetPresence("dev-1", jid)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.True(t, got.Available)
+	require.NotNil(t, got.LastSeenAt)
+	assert.True(t, lastSeen.Equal(*got.LastSeenAt))
+	assert.Equal(t, "composing", got.ChatState)
+	require.NotNil(t, got.ChatStateAt)
+	assert.True(t, typingAt.Equal(*got.ChatStateAt))
+	assert.False(t, got.UpdatedAt.IsZero())
+
+	other, err := repo.GetPresence("dev-2", jid)
+	require.NoError(t, err)
+	assert.Nil(t, other, "presences are scoped per device")
+}
+
+func TestPresenceSubscriptions(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	require.NoError(t, repo.AddPresenceSubscription("dev-1", "628111@s.whatsapp.net"))
+	require.NoError(t, repo.AddPresenceSubscription("dev-1", "628222@s.whatsapp.net"))
+	require.NoError(t, repo.AddPresenceSubscription("dev-1", "628111@s.whatsapp.net"), "subscribing twice is a no-op")
+	require.NoError(t, repo.AddPresenceSubscription("dev-2", "628333@s.whatsapp.net"))
+	require.NoError(t, repo.SavePresence(&domainChatStorage.Presence{DeviceID: "dev-1", JID: "628111@s.whatsapp.net", Available: true}))
+
+	jids, err := repo.GetPresenceSubscriptions("dev-1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"628111@s.whatsapp.net", "628222@s.whatsapp.net"}, jids)
+
+	require.NoError(t, repo.RemovePresenceSubscription("dev-1", "628222@s.whatsapp.net"))
+	subscribed, err := repo.HasPresenceSubscription("dev-1", "628222@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.False(t, subscribed)
+	subscribed, err = repo.HasPresenceSubscription("dev-1", "628111@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.True(t, subscribed)
+
+	require.NoError(t, repo.DeleteDeviceData("dev-1"))
+	jids, err = repo.GetPresenceSubscriptions("dev-1")
+	require.NoError(t, err)
+	assert.Empty(t, jids)
+	presence, err := repo.GetPresence("dev-1", "628111@s.whatsapp.net")
+	require.NoError(t, err)
+	assert.Nil(t, presence)
+	jids, err = repo.GetPresenceSubscriptions("dev-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"628333@s.whatsapp.net"}, jids)
+}
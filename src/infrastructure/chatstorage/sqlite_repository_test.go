@@ -102,6 +102,48 @@ func TestSQLiteRepositorySetDeviceWebhookConfigReturnsErrNoRowsForMissingDevice(
 	}
 }
 
+func TestSQLiteRepositoryStoresWebhookDeliverySettings(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	webhookURL := "https://hooks.slack.example.com/services/T000/B000"
+	if err := repo.SaveDeviceRecord(&domainChatStorage.DeviceRecord{
+		DeviceID: "session-a",
+		JID:      "628123456789@s.whatsapp.net",
+	}); err != nil {
+		t.Fatalf("save device record: %v", err)
+	}
+
+	want := &domainChatStorage.DeviceWebhookConfig{
+		WebhookURL:                &webhookURL,
+		WebhookMethod:             "PUT",
+		WebhookHeaders:            map[string]string{"Authorization": "Bearer t0ken"},
+		WebhookTemplate:           `{"text": {{ json .event }}}`,
+		WebhookSignatureAlgorithm: "sha512",
+	}
+	if err := repo.SetDeviceWebhookConfig("session-a", want); err != nil {
+		t.Fatalf("set device webhook config: %v", err)
+	}
+
+	got, err := repo.GetDeviceWebhookConfig("session-a")
+	if err != nil {
+		t.Fatalf("get device webhook config: %v", err)
+	}
+	if got.WebhookMethod != "PUT" || got.WebhookTemplate != want.WebhookTemplate || got.WebhookSignatureAlgorithm != "sha512" {
+		t.Fatalf("delivery settings not stored: %+v", got)
+	}
+	if got.WebhookHeaders["Authorization"] != "Bearer t0ken" {
+		t.Fatalf("expected webhook headers to round-trip, got %v", got.WebhookHeaders)
+	}
+
+	record, err := repo.GetDeviceRecordByJID("628123456789@s.whatsapp.net")
+	if err != nil || record == nil {
+		t.Fatalf("get device record by jid: %v", err)
+	}
+	if record.WebhookMethod != "PUT" || record.WebhookHeaders != `{"Authorization":"Bearer t0ken"}` || record.WebhookSignatureAlgorithm != "sha512" {
+		t.Fatalf("record does not carry the delivery settings: %+v", record)
+	}
+}
+
 func TestSQLiteRepositoryStoresUpdatesRemovesAndHydratesReactions(t *testing.T) {
 	repo := newTestSQLiteRepository(t)
 	deviceID := "device-a@s.whatsapp.net"
@@ -333,6 +375,44 @@ func TestSQLiteRepositoryStoresBatchMessageDirectPath(t *testing.T) {
 	}
 }
 
+func TestSQLiteRepositoryGetMessagesOldestFirstPagesInOrder(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	deviceID := "628999999999@s.whatsapp.net"
+	chatJID := "628123456789@s.whatsapp.net"
+	base := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	// msg-b and msg-c share a timestamp; the id tie-break keeps paging stable.
+	seedChatMessage(t, repo, deviceID, chatJID, "msg-c", "third", base.Add(time.Minute))
+	seedChatMessage(t, repo, deviceID, chatJID, "msg-a", "first", base)
+	seedChatMessage(t, repo, deviceID, chatJID, "msg-b", "second", base.Add(time.Minute))
+
+	var got []string
+	for offset := 0; offset < 3; offset += 2 {
+		page, err := repo.GetMessages(&domainChatStorage.MessageFilter{
+			DeviceID:    deviceID,
+			ChatJID:     chatJID,
+			Limit:       2,
+			Offset:      offset,
+			OldestFirst: true,
+		})
+		if err != nil {
+			t.Fatalf("get messages: %v", err)
+		}
+		for _, message := range page {
+			got = append(got, message.ID)
+		}
+	}
+
+	want := []string{"msg-a", "msg-b", "msg-c"}
+	if len(got) != len(want) {
+		t.Fatalf("message ids = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("message ids = %v, want %v", got, want)
+		}
+	}
+}
+
 func seedChatMessage(t *testing.T, repo *SQLiteRepository, deviceID, chatJID, messageID, content string, timestamp time.Time) {
 	t.Helper()
 	if err := repo.StoreChat(&domainChatStorage.Chat{
@@ -0,0 +1,76 @@
+package chatwoot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+var avatarChecked sync.Map // contact key -> time.Time of the last picture check
+
+// avatarDue reports whether the contact's picture should be checked now,
+// recording the check when it is. force skips the refresh interval.
+func (c *Client) avatarDue(contact *Contact, now time.Time, force bool) bool {
+	key := fmt.Sprintf("%s|%d|%d", c.BaseURL, c.AccountID, contact.ID)
+	if !force {
+		if last, ok := avatarChecked.Load(key); ok && now.Sub(last.(time.Time)) < config.ChatwootAvatarRefreshInterval {
+			return false
+		}
+	}
+	avatarChecked.Store(key, now)
+	return true
+}
+
+// SyncContactAvatar copies the WhatsApp picture of chatJID (the group picture
+// for groups) onto the Chatwoot contact. A contact is checked at most once per
+// ChatwootAvatarRefreshInterval unless force is set. The picture id kept in
+// gowa_avatar_id lets WhatsApp answer "unchanged" without a new upload, and an
+// avatar set in Chatwoot itself (a thumbnail without that attribute) is left
+// alone.
+func (c *Client) SyncContactAvatar(ctx context.Context, waClient *whatsmeow.Client, contact *Contact, chatJID string, force bool) {
+	if !config.ChatwootContactAvatar || waClient == nil || contact == nil || contact.ID == 0 {
+		return
+	}
+	storedID, tracked := contact.CustomAttributes["gowa_avatar_id"].(string)
+	if !tracked && contact.Thumbnail != "" {
+		return
+	}
+	jid, err := types.ParseJID(chatJID)
+	if err != nil || !c.avatarDue(contact, time.Now(), force) {
+		return
+	}
+
+	pictureCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	pic, err := waClient.GetProfilePictureInfo(pictureCtx, jid, &whatsmeow.GetProfilePictureParams{ExistingID: storedID})
+	switch {
+	case errors.Is(err, whatsmeow.ErrProfilePictureNotSet):
+		if storedID == "" {
+			return
+		}
+		if err := c.RemoveContactAvatar(contact); err != nil {
+			logrus.Warnf("Chatwoot: Failed to remove avatar of contact %d: %v", contact.ID, err)
+			return
+		}
+		logrus.Infof("Chatwoot: Removed avatar of contact %d", contact.ID)
+	case errors.Is(err, whatsmeow.ErrProfilePictureUnauthorized):
+		// Hidden by the contact's privacy settings; keep what Chatwoot has.
+	case err != nil:
+		logrus.Debugf("Chatwoot: Failed to fetch picture of %s: %v", chatJID, err)
+	case pic == nil || pic.URL == "":
+		// Unchanged since the stored picture id.
+	default:
+		if err := c.UpdateContactAvatar(contact, pic.URL, pic.ID); err != nil {
+			logrus.Warnf("Chatwoot: Failed to update avatar of contact %d: %v", contact.ID, err)
+			return
+		}
+		logrus.Infof("Chatwoot: Updated avatar of contact %d from %s", contact.ID, chatJID)
+	}
+}
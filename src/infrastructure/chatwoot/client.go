@@ -2,6 +2,7 @@ package chatwoot
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,8 +19,10 @@ import (
 	"sync"
 	"syscall"
 	"time"
+	"unicode"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -171,6 +174,10 @@ func newChatwootClient(baseURL, apiToken string, accountID, inboxID int, ssrfGua
 	if ssrfGuard {
 		httpClient.Transport = ssrfGuardedTransport()
 	}
+	if config.OtelEnabled {
+		// Every Chatwoot API call gets a client span and traceparent header.
+		httpClient.Transport = telemetry.Transport(httpClient.Transport)
+	}
 	return &Client{
 		BaseURL:    canonical,
 		APIToken:   strings.TrimSpace(apiToken),
@@ -208,6 +215,30 @@ func (c *Client) IsConfigured() bool {
 	return c.BaseURL != "" && c.APIToken != "" && c.AccountID != 0 && c.InboxID != 0
 }
 
+// Ping checks that Chatwoot is reachable and the token can read the
+// configured inbox. It backs the readiness probe.
+func (c *Client) Ping(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/inboxes/%d", c.BaseURL, c.AccountID, c.InboxID)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("api_access_token", c.APIToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &HTTPStatusError{StatusCode: resp.StatusCode, Op: "ping", Body: string(body)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
 func (c *Client) FindContactByIdentifier(identifier string, isGroup bool) (*Contact, error) {
 	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/contacts/search", c.BaseURL, c.AccountID)
 	logrus.Debugf("Chatwoot: Finding contact by identifier endpoint=%s identifier=%s isGroup=%v", endpoint, identifier, isGroup)
@@ -383,11 +414,56 @@ func (c *Client) FindOrCreateContact(name, identifier string, isGroup bool) (*Co
 
 // UpdateContactName updates the name of an existing contact
 func (c *Client) UpdateContactName(contactID int, name string) error {
-	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/contacts/%d", c.BaseURL, c.AccountID, contactID)
+	return c.updateContact(contactID, map[string]any{"name": name})
+}
+
+// UpdateContactAvatar points the contact's avatar at avatarURL, which Chatwoot
+// downloads in the background, and records the WhatsApp picture id in the
+// gowa_avatar_id custom attribute so unchanged pictures are not re-uploaded.
+func (c *Client) UpdateContactAvatar(contact *Contact, avatarURL, pictureID string) error {
+	return c.updateContact(contact.ID, map[string]any{
+		"avatar_url":        avatarURL,
+		"custom_attributes": contactAvatarAttributes(contact, pictureID),
+	})
+}
+
+// RemoveContactAvatar deletes the contact's avatar and clears gowa_avatar_id.
+func (c *Client) RemoveContactAvatar(contact *Contact) error {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/contacts/%d/avatar", c.BaseURL, c.AccountID, contact.ID)
+	req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("api_access_token", c.APIToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return &HTTPStatusError{StatusCode: resp.StatusCode, Op: "remove contact avatar", Body: string(body)}
+	}
+	return c.updateContact(contact.ID, map[string]any{"custom_attributes": contactAvatarAttributes(contact, "")})
+}
 
-	payload := map[string]any{
-		"name": name,
+// contactAvatarAttributes returns the contact's custom attributes with
+// gowa_avatar_id set. The full set is sent because an update replaces the
+// stored attributes.
+func contactAvatarAttributes(contact *Contact, pictureID string) map[string]any {
+	attrs := make(map[string]any, len(contact.CustomAttributes)+1)
+	for k, v := range contact.CustomAttributes {
+		attrs[k] = v
 	}
+	attrs["gowa_avatar_id"] = pictureID
+	return attrs
+}
+
+// updateContact sends a partial contact update.
+func (c *Client) updateContact(contactID int, payload map[string]any) error {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/contacts/%d", c.BaseURL, c.AccountID, contactID)
 
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
@@ -506,6 +582,100 @@ func (c *Client) ToggleConversationStatus(conversationID int, status string) err
 	return nil
 }
 
+// GetConversationLabels returns the labels applied to a conversation.
+func (c *Client) GetConversationLabels(conversationID int) ([]string, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/conversations/%d/labels", c.BaseURL, c.AccountID, conversationID)
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("api_access_token", c.APIToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &HTTPStatusError{StatusCode: resp.StatusCode, Op: "get conversation labels", Body: string(body)}
+	}
+
+	var result struct {
+		Payload []string `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Payload, nil
+}
+
+// SetConversationLabels replaces the labels of a conversation via
+// POST /conversations/{id}/labels.
+func (c *Client) SetConversationLabels(conversationID int, labels []string) error {
+	endpoint := fmt.Sprintf("%s/api/v1/accounts/%d/conversations/%d/labels", c.BaseURL, c.AccountID, conversationID)
+	if labels == nil {
+		labels = []string{}
+	}
+	jsonPayload, err := json.Marshal(map[string][]string{"labels": labels})
+	if err != nil {
+		return fmt.Errorf("failed to marshal labels payload: %w", err)
+	}
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("api_access_token", c.APIToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &HTTPStatusError{StatusCode: resp.StatusCode, Op: "set conversation labels", Body: string(body)}
+	}
+	return nil
+}
+
+// FindConversation returns the contact's open conversation in this client's
+// inbox, else its latest one, without creating or reopening anything. Returns
+// nil when the contact has no conversation in the inbox.
+func (c *Client) FindConversation(contactID int) (*Conversation, error) {
+	items, err := c.listContactConversations(contactID)
+	if err != nil {
+		return nil, err
+	}
+	if open := selectOpenConversation(items, c.InboxID, contactID); open != nil {
+		return open, nil
+	}
+	return selectLatestConversation(items, c.InboxID, contactID), nil
+}
+
+// LabelTitle converts a WhatsApp label name into a Chatwoot label title.
+// Chatwoot only accepts lowercase letters, digits, "-" and "_", so anything
+// else becomes "-" ("Pending Payment" -> "pending-payment").
+func LabelTitle(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
+
 // conversationStatusForNew returns the status a newly created or reopened
 // conversation should land in: "pending" routes it to the unassigned queue
 // when ChatwootConversationPending is set, otherwise "open" puts it in the
@@ -773,26 +943,32 @@ func (c *Client) DeleteMessage(conversationID, messageID int) error {
 	return nil
 }
 
-func (c *Client) UpdateLastSeen(conversationID int, contactInboxSourceID string) error {
+// inboxIdentifier returns the public identifier of the client's inbox, used by
+// the contact-side public API.
+func (c *Client) inboxIdentifier() (string, error) {
 	// inbox_identifier is immutable, so resolve it once and reuse the cached
 	// value — read sync fires this per receipt and a full inbox-list GET each
 	// time is pure overhead.
-	inboxIdentifier := c.InboxIdentifier
-	if inboxIdentifier == "" {
-		inboxes, err := c.ListInboxes()
-		if err != nil {
-			return err
-		}
-		for _, inbox := range inboxes {
-			if inbox.ID == c.InboxID {
-				inboxIdentifier = inbox.InboxIdentifier
-				break
-			}
-		}
-		if inboxIdentifier == "" {
-			return fmt.Errorf("chatwoot inbox %d has no inbox_identifier", c.InboxID)
+	if c.InboxIdentifier != "" {
+		return c.InboxIdentifier, nil
+	}
+	inboxes, err := c.ListInboxes()
+	if err != nil {
+		return "", err
+	}
+	for _, inbox := range inboxes {
+		if inbox.ID == c.InboxID && inbox.InboxIdentifier != "" {
+			c.InboxIdentifier = inbox.InboxIdentifier
+			return c.InboxIdentifier, nil
 		}
-		c.InboxIdentifier = inboxIdentifier
+	}
+	return "", fmt.Errorf("chatwoot inbox %d has no inbox_identifier", c.InboxID)
+}
+
+func (c *Client) UpdateLastSeen(conversationID int, contactInboxSourceID string) error {
+	inboxIdentifier, err := c.inboxIdentifier()
+	if err != nil {
+		return err
 	}
 
 	endpoint := fmt.Sprintf(
@@ -821,6 +997,51 @@ func (c *Client) UpdateLastSeen(conversationID int, contactInboxSourceID string)
 	return nil
 }
 
+// ToggleTyping turns the contact's typing indicator on or off in a
+// conversation. Like UpdateLastSeen it acts as the contact through the public
+// API, so agents see the customer typing.
+func (c *Client) ToggleTyping(conversationID int, contactInboxSourceID string, typing bool) error {
+	inboxIdentifier, err := c.inboxIdentifier()
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/public/api/v1/inboxes/%s/contacts/%s/conversations/%d/toggle_typing",
+		c.BaseURL,
+		url.PathEscape(inboxIdentifier),
+		url.PathEscape(contactInboxSourceID),
+		conversationID,
+	)
+	status := "off"
+	if typing {
+		status = "on"
+	}
+	jsonPayload, err := json.Marshal(map[string]string{"typing_status": status})
+	if err != nil {
+		return fmt.Errorf("failed to marshal typing payload: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("api_access_token", c.APIToken)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(resp.Body)
+		return &HTTPStatusError{StatusCode: resp.StatusCode, Op: "toggle typing", Body: string(body)}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
 func (c *Client) createMessageWithAttachments(endpoint, content, messageType string, attachments []string, opt MessageOptions) (int, error) {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -1,6 +1,7 @@
 package chatwoot
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -15,6 +16,40 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 )
 
+// --- Ping ------------------------------------------------------------------
+
+func TestPing_ReadsConfiguredInbox(t *testing.T) {
+	// Ping backs the readiness probe: it must hit the configured inbox with
+	// the API token so a wrong token or deleted inbox fails readiness.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/accounts/1/inboxes/2" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.String())
+		}
+		if r.Header.Get("api_access_token") != "token" {
+			t.Fatalf("api_access_token = %q, want token", r.Header.Get("api_access_token"))
+		}
+		writeJSON(t, w, http.StatusOK, Inbox{ID: 2, Name: "WhatsApp"})
+	}))
+	defer server.Close()
+
+	if err := newTestClient(t, server.URL).Ping(context.Background()); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+}
+
+func TestPing_ReturnsStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusUnauthorized, map[string]any{"error": "invalid token"})
+	}))
+	defer server.Close()
+
+	err := newTestClient(t, server.URL).Ping(context.Background())
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Ping error = %v, want HTTPStatusError 401", err)
+	}
+}
+
 // --- ListInboxes -----------------------------------------------------------
 
 func TestListInboxes_DecodesPayload(t *testing.T) {
@@ -0,0 +1,128 @@
+package chatwoot
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestGetConversationLabels_DecodesPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/accounts/1/conversations/55/labels" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.String())
+		}
+		writeJSON(t, w, http.StatusOK, map[string]any{"payload": []string{"vip", "billing"}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL)
+	labels, err := c.GetConversationLabels(55)
+	if err != nil {
+		t.Fatalf("GetConversationLabels: %v", err)
+	}
+	if !slices.Equal(labels, []string{"vip", "billing"}) {
+		t.Errorf("labels = %v, want [vip billing]", labels)
+	}
+}
+
+func TestSetConversationLabels_PostsFullSet(t *testing.T) {
+	var got map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/accounts/1/conversations/55/labels" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.String())
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		writeJSON(t, w, http.StatusOK, map[string]any{"payload": got["labels"]})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL)
+	// A nil slice must clear the labels, not send "labels": null.
+	if err := c.SetConversationLabels(55, nil); err != nil {
+		t.Fatalf("SetConversationLabels: %v", err)
+	}
+	if got["labels"] == nil || len(got["labels"]) != 0 {
+		t.Errorf("labels = %#v, want empty list", got["labels"])
+	}
+}
+
+func TestSetConversationLabels_Non200(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusNotFound, map[string]any{"error": "no such conversation"})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL)
+	err := c.SetConversationLabels(55, []string{"vip"})
+	var httpErr *HTTPStatusError
+	if !errors.As(err, &httpErr) || httpErr.Op != "set conversation labels" {
+		t.Fatalf("err = %v, want 'set conversation labels' HTTPStatusError", err)
+	}
+}
+
+func TestFindConversation_PrefersOpenAndNeverCreates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/accounts/1/contacts/9/conversations" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.String())
+		}
+		writeJSON(t, w, http.StatusOK, map[string]any{"payload": []map[string]any{
+			{"id": 30, "inbox_id": 2, "status": "resolved"},
+			{"id": 20, "inbox_id": 2, "status": "open"},
+			{"id": 40, "inbox_id": 7, "status": "open"},
+		}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL)
+	conv, err := c.FindConversation(9)
+	if err != nil {
+		t.Fatalf("FindConversation: %v", err)
+	}
+	if conv == nil || conv.ID != 20 {
+		t.Fatalf("conversation = %+v, want open conversation 20", conv)
+	}
+}
+
+func TestLabelTitle(t *testing.T) {
+	tests := map[string]string{
+		"VIP":                "vip",
+		"Pending Payment":    "pending-payment",
+		"  New  customer!! ": "new-customer",
+		"follow_up":          "follow_up",
+		"Order #42 - paid":   "order-42-paid",
+		"Pagamento Pendente": "pagamento-pendente",
+		"!!!":                "",
+	}
+	for name, want := range tests {
+		if got := LabelTitle(name); got != want {
+			t.Errorf("LabelTitle(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWebhookPayload_LabelsOnlyWhenPresent(t *testing.T) {
+	var withoutLabels, cleared, labeled WebhookPayload
+	for raw, dst := range map[string]*WebhookPayload{
+		`{"event":"conversation_updated","id":5}`:                  &withoutLabels,
+		`{"event":"conversation_updated","id":5,"labels":[]}`:      &cleared,
+		`{"event":"conversation_updated","id":5,"labels":["vip"]}`: &labeled,
+	} {
+		if err := json.Unmarshal([]byte(raw), dst); err != nil {
+			t.Fatalf("unmarshal %s: %v", raw, err)
+		}
+	}
+	if withoutLabels.Labels != nil {
+		t.Errorf("labels = %v, want nil when the field is absent", *withoutLabels.Labels)
+	}
+	if cleared.Labels == nil || len(*cleared.Labels) != 0 {
+		t.Errorf("labels = %v, want an empty set", cleared.Labels)
+	}
+	if labeled.Labels == nil || !slices.Equal(*labeled.Labels, []string{"vip"}) {
+		t.Errorf("labels = %v, want [vip]", labeled.Labels)
+	}
+}
@@ -0,0 +1,139 @@
+package chatwoot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+func TestToggleTyping_UsesPublicContactEndpoint(t *testing.T) {
+	var inboxLookups int
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/accounts/1/inboxes":
+			inboxLookups++
+			writeJSON(t, w, http.StatusOK, map[string]any{"payload": []map[string]any{
+				{"id": 2, "name": "WhatsApp", "inbox_identifier": "inbox-abc"},
+			}})
+		case "/public/api/v1/inboxes/inbox-abc/contacts/628111@s.whatsapp.net/conversations/55/toggle_typing":
+			if r.Method != http.MethodPost {
+				t.Fatalf("method = %s, want POST", r.Method)
+			}
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.String())
+		}
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL)
+	if err := c.ToggleTyping(55, "628111@s.whatsapp.net", true); err != nil {
+		t.Fatalf("ToggleTyping on: %v", err)
+	}
+	if got["typing_status"] != "on" {
+		t.Errorf("typing_status = %q, want on", got["typing_status"])
+	}
+	if err := c.ToggleTyping(55, "628111@s.whatsapp.net", false); err != nil {
+		t.Fatalf("ToggleTyping off: %v", err)
+	}
+	if got["typing_status"] != "off" {
+		t.Errorf("typing_status = %q, want off", got["typing_status"])
+	}
+	if inboxLookups != 1 {
+		t.Errorf("inbox lookups = %d, want 1 (identifier is cached)", inboxLookups)
+	}
+}
+
+func TestUpdateContactAvatar_KeepsCustomAttributes(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/accounts/1/contacts/9" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.String())
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		writeJSON(t, w, http.StatusOK, map[string]any{"payload": map[string]any{"id": 9}})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL)
+	contact := &Contact{ID: 9, CustomAttributes: map[string]any{"gowa_whatsapp_jid": "628111@s.whatsapp.net"}}
+	if err := c.UpdateContactAvatar(contact, "https://pps.whatsapp.net/v/pic.jpg", "1712345678"); err != nil {
+		t.Fatalf("UpdateContactAvatar: %v", err)
+	}
+	if got["avatar_url"] != "https://pps.whatsapp.net/v/pic.jpg" {
+		t.Errorf("avatar_url = %v", got["avatar_url"])
+	}
+	attrs, _ := got["custom_attributes"].(map[string]any)
+	if attrs["gowa_whatsapp_jid"] != "628111@s.whatsapp.net" || attrs["gowa_avatar_id"] != "1712345678" {
+		t.Errorf("custom_attributes = %v", attrs)
+	}
+	if _, ok := contact.CustomAttributes["gowa_avatar_id"]; ok {
+		t.Error("contact attributes must not be mutated")
+	}
+}
+
+func TestRemoveContactAvatar_DeletesAndClearsPictureID(t *testing.T) {
+	var calls []string
+	var attrs map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPut {
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			attrs, _ = body["custom_attributes"].(map[string]any)
+		}
+		writeJSON(t, w, http.StatusOK, map[string]any{})
+	}))
+	defer server.Close()
+
+	c := newTestClient(t, server.URL)
+	contact := &Contact{ID: 9, CustomAttributes: map[string]any{"gowa_avatar_id": "1712345678"}}
+	if err := c.RemoveContactAvatar(contact); err != nil {
+		t.Fatalf("RemoveContactAvatar: %v", err)
+	}
+	want := []string{"DELETE /api/v1/accounts/1/contacts/9/avatar", "PUT /api/v1/accounts/1/contacts/9"}
+	if len(calls) != 2 || calls[0] != want[0] || calls[1] != want[1] {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	if id, ok := attrs["gowa_avatar_id"]; !ok || id != "" {
+		t.Errorf("gowa_avatar_id = %v (present=%v), want empty", id, ok)
+	}
+}
+
+func TestAvatarDue_ThrottlesPerContact(t *testing.T) {
+	original := config.ChatwootAvatarRefreshInterval
+	config.ChatwootAvatarRefreshInterval = time.Hour
+	t.Cleanup(func() { config.ChatwootAvatarRefreshInterval = original })
+
+	c := newTestClient(t, "https://avatar-due.example.com")
+	contact := &Contact{ID: 41}
+	now := time.Now()
+
+	if !c.avatarDue(contact, now, false) {
+		t.Fatal("first check must be due")
+	}
+	if c.avatarDue(contact, now.Add(30*time.Minute), false) {
+		t.Fatal("check within the interval must not be due")
+	}
+	if !c.avatarDue(contact, now.Add(31*time.Minute), true) {
+		t.Fatal("forced check must be due")
+	}
+	if !c.avatarDue(contact, now.Add(92*time.Minute), false) {
+		t.Fatal("check after the interval must be due")
+	}
+	if !c.avatarDue(&Contact{ID: 42}, now, false) {
+		t.Fatal("other contacts are throttled separately")
+	}
+}
@@ -20,10 +20,28 @@ var ErrClientRegistryUnavailable = errors.New("chatwoot: client registry not ini
 // ResolvedConfig is the outcome of resolving a device (or inbox) to a Chatwoot
 // destination. ConfigID is the chatwoot_device_configs row id, or 0 for the
 // legacy/env config used when no per-device config rows exist.
+//
+// LabelSync, ArchiveOnResolve and ClosingMessage carry the per-device
+// conversation sync options; the legacy config leaves them off.
 type ResolvedConfig struct {
-	ConfigID int64
-	DeviceID string
-	Client   *Client
+	ConfigID         int64
+	DeviceID         string
+	Client           *Client
+	LabelSync        bool
+	ArchiveOnResolve bool
+	ClosingMessage   string
+}
+
+// newResolvedConfig builds the resolved destination of a per-device config row.
+func newResolvedConfig(cfg *domainChatStorage.ChatwootDeviceConfig) *ResolvedConfig {
+	return &ResolvedConfig{
+		ConfigID:         cfg.ID,
+		DeviceID:         cfg.DeviceID,
+		Client:           NewClientFromConfig(cfg.ChatwootURL, cfg.APIToken, cfg.AccountID, cfg.InboxID),
+		LabelSync:        cfg.LabelSync,
+		ArchiveOnResolve: cfg.ArchiveOnResolve,
+		ClosingMessage:   cfg.ClosingMessage,
+	}
 }
 
 // ClientRegistry resolves a per-device Chatwoot *Client (plus its scope) from
@@ -77,11 +95,7 @@ func (r *ClientRegistry) Resolve(identifier string) (*ResolvedConfig, error) {
 			// Explicitly disabled for this device: do not forward, do not fall back.
 			return nil, nil
 		}
-		rc := &ResolvedConfig{
-			ConfigID: cfg.ID,
-			DeviceID: cfg.DeviceID,
-			Client:   NewClientFromConfig(cfg.ChatwootURL, cfg.APIToken, cfg.AccountID, cfg.InboxID),
-		}
+		rc := newResolvedConfig(cfg)
 		r.store(identifier, rc)
 		return rc, nil
 	}
@@ -114,11 +128,7 @@ func (r *ClientRegistry) ResolveByInbox(accountID, inboxID int) (*ResolvedConfig
 	if cfg == nil || !cfg.Enabled {
 		return nil, nil
 	}
-	return &ResolvedConfig{
-		ConfigID: cfg.ID,
-		DeviceID: cfg.DeviceID,
-		Client:   NewClientFromConfig(cfg.ChatwootURL, cfg.APIToken, cfg.AccountID, cfg.InboxID),
-	}, nil
+	return newResolvedConfig(cfg), nil
 }
 
 // Invalidate drops every cached entry for a device so the next Resolve rebuilds
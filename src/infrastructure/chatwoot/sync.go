@@ -300,6 +300,7 @@ func (s *SyncService) syncChat(
 		return fmt.Errorf("failed to create contact: %w", err)
 	}
 	logrus.Debugf("Chatwoot Sync: Contact ID: %d", contact.ID)
+	s.client.SyncContactAvatar(ctx, waClient, contact, chat.JID, false)
 
 	// 2. Find or create conversation
 	var conversation *Conversation
@@ -6,6 +6,7 @@ type Contact struct {
 	Email            string         `json:"email"`
 	PhoneNumber      string         `json:"phone_number"`
 	Identifier       string         `json:"identifier"`
+	Thumbnail        string         `json:"thumbnail"`
 	CustomAttributes map[string]any `json:"custom_attributes"`
 }
 
@@ -83,6 +84,25 @@ type WebhookPayload struct {
 	Conversation      ConversationWebhook `json:"conversation"`
 	Sender            Contact             `json:"sender"`
 	Attachments       []Attachment        `json:"attachments"`
+	// Conversation events (conversation_status_changed, conversation_updated)
+	// carry the conversation itself at the top level, so ID is then the
+	// conversation id. Labels is nil when the payload has no labels field.
+	InboxID int              `json:"inbox_id"`
+	Status  string           `json:"status"`
+	Labels  *[]string        `json:"labels"`
+	Meta    ConversationMeta `json:"meta"`
+	// Typing events (conversation_typing_on/off) name who is typing and
+	// whether it is a private note.
+	User      TypingUser `json:"user"`
+	IsPrivate bool       `json:"is_private"`
+}
+
+// TypingUser is the typist of a typing event: "user" for agents, "contact"
+// for the customer.
+type TypingUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 type Attachment struct {
@@ -94,9 +114,10 @@ type Attachment struct {
 }
 
 type ConversationWebhook struct {
-	ID      int              `json:"id"`
-	InboxID int              `json:"inbox_id"`
-	Meta    ConversationMeta `json:"meta"`
+	ID        int              `json:"id"`
+	AccountID int              `json:"account_id"`
+	InboxID   int              `json:"inbox_id"`
+	Meta      ConversationMeta `json:"meta"`
 }
 
 type ConversationMeta struct {
@@ -342,3 +342,217 @@ func (r *deviceChatStorage) SetDeviceWebhookConfig(deviceID string, config *doma
 func (r *deviceChatStorage) GetDeviceWebhookConfig(deviceID string) (*domainChatStorage.DeviceWebhookConfig, error) {
 	return r.base.GetDeviceWebhookConfig(deviceID)
 }
+
+// SetDeviceCallConfig delegates to the base repository.
+func (r *deviceChatStorage) SetDeviceCallConfig(deviceID string, config *domainChatStorage.DeviceCallConfig) error {
+	return r.base.SetDeviceCallConfig(deviceID, config)
+}
+
+// GetDeviceCallConfig delegates to the base repository.
+func (r *deviceChatStorage) GetDeviceCallConfig(deviceID string) (*domainChatStorage.DeviceCallConfig, error) {
+	return r.base.GetDeviceCallConfig(deviceID)
+}
+
+// SetDeviceProxyConfig delegates to the base repository.
+func (r *deviceChatStorage) SetDeviceProxyConfig(deviceID string, config *domainChatStorage.DeviceProxyConfig) error {
+	return r.base.SetDeviceProxyConfig(deviceID, config)
+}
+
+// GetDeviceProxyConfig delegates to the base repository.
+func (r *deviceChatStorage) GetDeviceProxyConfig(deviceID string) (*domainChatStorage.DeviceProxyConfig, error) {
+	return r.base.GetDeviceProxyConfig(deviceID)
+}
+
+// SetDeviceAttributes delegates to the base repository.
+func (r *deviceChatStorage) SetDeviceAttributes(deviceID string, attributes *domainChatStorage.DeviceAttributes) error {
+	return r.base.SetDeviceAttributes(deviceID, attributes)
+}
+
+// GetDeviceAttributes delegates to the base repository.
+func (r *deviceChatStorage) GetDeviceAttributes(deviceID string) (*domainChatStorage.DeviceAttributes, error) {
+	return r.base.GetDeviceAttributes(deviceID)
+}
+
+// StoreAuditEntry delegates to the base repository. Audit rows carry their
+// own device_id (the operator-facing id), so no injection happens here.
+func (r *deviceChatStorage) StoreAuditEntry(entry *domainChatStorage.AuditEntry) error {
+	return r.base.StoreAuditEntry(entry)
+}
+
+// GetAuditEntries delegates to the base repository.
+func (r *deviceChatStorage) GetAuditEntries(filter *domainChatStorage.AuditFilter) ([]*domainChatStorage.AuditEntry, error) {
+	return r.base.GetAuditEntries(filter)
+}
+
+// CountAuditEntries delegates to the base repository.
+func (r *deviceChatStorage) CountAuditEntries(filter *domainChatStorage.AuditFilter) (int64, error) {
+	return r.base.CountAuditEntries(filter)
+}
+
+// SaveGroupMetadata delegates to the base repository, filling in the device when unset.
+func (r *deviceChatStorage) SaveGroupMetadata(metadata *domainChatStorage.GroupMetadata) error {
+	if metadata != nil && metadata.DeviceID == "" {
+		metadata.DeviceID = r.deviceID
+	}
+	return r.base.SaveGroupMetadata(metadata)
+}
+
+// GetGroupMetadata delegates to the base repository.
+func (r *deviceChatStorage) GetGroupMetadata(deviceID, groupJID string) (*domainChatStorage.GroupMetadata, error) {
+	return r.base.GetGroupMetadata(deviceID, groupJID)
+}
+
+// DeleteGroupMetadata delegates to the base repository.
+func (r *deviceChatStorage) DeleteGroupMetadata(deviceID, groupJID string) error {
+	return r.base.DeleteGroupMetadata(deviceID, groupJID)
+}
+
+// StoreGroupParticipantEvents delegates to the base repository, filling in the device when unset.
+func (r *deviceChatStorage) StoreGroupParticipantEvents(events []*domainChatStorage.GroupParticipantEvent) error {
+	for _, event := range events {
+		if event != nil && event.DeviceID == "" {
+			event.DeviceID = r.deviceID
+		}
+	}
+	return r.base.StoreGroupParticipantEvents(events)
+}
+
+// GetGroupParticipantEvents delegates to the base repository.
+func (r *deviceChatStorage) GetGroupParticipantEvents(filter *domainChatStorage.GroupParticipantEventFilter) ([]*domainChatStorage.GroupParticipantEvent, error) {
+	return r.base.GetGroupParticipantEvents(filter)
+}
+
+// CountGroupParticipantEvents delegates to the base repository.
+func (r *deviceChatStorage) CountGroupParticipantEvents(filter *domainChatStorage.GroupParticipantEventFilter) (int64, error) {
+	return r.base.CountGroupParticipantEvents(filter)
+}
+
+// StoreGroupDescriptionEvent delegates to the base repository, filling in the device when unset.
+func (r *deviceChatStorage) StoreGroupDescriptionEvent(event *domainChatStorage.GroupDescriptionEvent) error {
+	if event != nil && event.DeviceID == "" {
+		event.DeviceID = r.deviceID
+	}
+	return r.base.StoreGroupDescriptionEvent(event)
+}
+
+// GetGroupDescriptionEvents delegates to the base repository.
+func (r *deviceChatStorage) GetGroupDescriptionEvents(filter *domainChatStorage.GroupDescriptionEventFilter) ([]*domainChatStorage.GroupDescriptionEvent, error) {
+	return r.base.GetGroupDescriptionEvents(filter)
+}
+
+// CountGroupDescriptionEvents delegates to the base repository.
+func (r *deviceChatStorage) CountGroupDescriptionEvents(filter *domainChatStorage.GroupDescriptionEventFilter) (int64, error) {
+	return r.base.CountGroupDescriptionEvents(filter)
+}
+
+// SaveCallRecord delegates to the base repository, filling in the device when unset.
+func (r *deviceChatStorage) SaveCallRecord(record *domainChatStorage.CallRecord) error {
+	if record != nil && record.DeviceID == "" {
+		record.DeviceID = r.deviceID
+	}
+	return r.base.SaveCallRecord(record)
+}
+
+// GetCallRecord delegates to the base repository.
+func (r *deviceChatStorage) GetCallRecord(deviceID, callID string) (*domainChatStorage.CallRecord, error) {
+	return r.base.GetCallRecord(deviceID, callID)
+}
+
+// GetCallRecords delegates to the base repository.
+func (r *deviceChatStorage) GetCallRecords(filter *domainChatStorage.CallRecordFilter) ([]*domainChatStorage.CallRecord, error) {
+	return r.base.GetCallRecords(filter)
+}
+
+// CountCallRecords delegates to the base repository.
+func (r *deviceChatStorage) CountCallRecords(filter *domainChatStorage.CallRecordFilter) (int64, error) {
+	return r.base.CountCallRecords(filter)
+}
+
+// UpsertContact delegates to the base repository, filling in the device when unset.
+func (r *deviceChatStorage) UpsertContact(contact *domainChatStorage.Contact) error {
+	if contact != nil && contact.DeviceID == "" {
+		contact.DeviceID = r.deviceID
+	}
+	return r.base.UpsertContact(contact)
+}
+
+// SetContactAvatar delegates to the base repository.
+func (r *deviceChatStorage) SetContactAvatar(deviceID, jid, avatarURL string) error {
+	return r.base.SetContactAvatar(deviceID, jid, avatarURL)
+}
+
+// UpdateContactAnnotations delegates to the base repository.
+func (r *deviceChatStorage) UpdateContactAnnotations(deviceID, jid string, tags []string, notes *string) error {
+	return r.base.UpdateContactAnnotations(deviceID, jid, tags, notes)
+}
+
+// GetContact delegates to the base repository.
+func (r *deviceChatStorage) GetContact(deviceID, jid string) (*domainChatStorage.Contact, error) {
+	return r.base.GetContact(deviceID, jid)
+}
+
+// GetContacts delegates to the base repository.
+func (r *deviceChatStorage) GetContacts(filter *domainChatStorage.ContactFilter) ([]*domainChatStorage.Contact, error) {
+	return r.base.GetContacts(filter)
+}
+
+// CountContacts delegates to the base repository.
+func (r *deviceChatStorage) CountContacts(filter *domainChatStorage.ContactFilter) (int64, error) {
+	return r.base.CountContacts(filter)
+}
+
+// SavePresence ensures device scoping before delegating.
+func (r *deviceChatStorage) SavePresence(presence *domainChatStorage.Presence) error {
+	if presence != nil && presence.DeviceID == "" {
+		presence.DeviceID = r.deviceID
+	}
+	return r.base.SavePresence(presence)
+}
+
+// GetPresence delegates to the base repository.
+func (r *deviceChatStorage) GetPresence(deviceID, jid string) (*domainChatStorage.Presence, error) {
+	return r.base.GetPresence(deviceID, jid)
+}
+
+// AddPresenceSubscription delegates to the base repository.
+func (r *deviceChatStorage) AddPresenceSubscription(deviceID, jid string) error {
+	return r.base.AddPresenceSubscription(deviceID, jid)
+}
+
+// RemovePresenceSubscription delegates to the base repository.
+func (r *deviceChatStorage) RemovePresenceSubscription(deviceID, jid string) error {
+	return r.base.RemovePresenceSubscription(deviceID, jid)
+}
+
+// HasPresenceSubscription delegates to the base repository.
+func (r *deviceChatStorage) HasPresenceSubscription(deviceID, jid string) (bool, error) {
+	return r.base.HasPresenceSubscription(deviceID, jid)
+}
+
+// GetPresenceSubscriptions delegates to the base repository.
+func (r *deviceChatStorage) GetPresenceSubscriptions(deviceID string) ([]string, error) {
+	return r.base.GetPresenceSubscriptions(deviceID)
+}
+
+// SaveLabel ensures the device ID is set before storing the label.
+func (r *deviceChatStorage) SaveLabel(label *domainChatStorage.Label) error {
+	if label != nil && label.DeviceID == "" {
+		label.DeviceID = r.deviceID
+	}
+	return r.base.SaveLabel(label)
+}
+
+// GetLabels delegates to the base repository.
+func (r *deviceChatStorage) GetLabels(deviceID string) ([]*domainChatStorage.Label, error) {
+	return r.base.GetLabels(deviceID)
+}
+
+// SetChatLabel delegates to the base repository.
+func (r *deviceChatStorage) SetChatLabel(deviceID, chatJID, labelID string, labeled bool) error {
+	return r.base.SetChatLabel(deviceID, chatJID, labelID, labeled)
+}
+
+// GetChatLabelIDs delegates to the base repository.
+func (r *deviceChatStorage) GetChatLabelIDs(deviceID, chatJID string) ([]string, error) {
+	return r.base.GetChatLabelIDs(deviceID, chatJID)
+}
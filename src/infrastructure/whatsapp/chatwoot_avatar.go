@@ -0,0 +1,29 @@
+package whatsapp
+
+import (
+	"context"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// refreshChatwootAvatar applies a WhatsApp picture change to the chat's
+// Chatwoot contact right away, without waiting for the refresh interval.
+func refreshChatwootAvatar(ctx context.Context, evt *events.Picture, deviceID string, client *whatsmeow.Client) {
+	if !config.ChatwootEnabled || !config.ChatwootContactAvatar || evt == nil || client == nil {
+		return
+	}
+	resolved, err := getChatwootClientFn(deviceID)
+	if err != nil || resolved == nil || resolved.Client == nil || !resolved.Client.IsConfigured() {
+		return
+	}
+	chatJID := NormalizeJIDFromLID(ctx, evt.JID.ToNonAD(), client).ToNonAD().String()
+	contact, err := findChatwootContact(resolved.Client, chatJID)
+	if err != nil {
+		logrus.Debugf("Chatwoot: Failed to find contact for %s: %v", chatJID, err)
+		return
+	}
+	resolved.Client.SyncContactAvatar(ctx, client, contact, chatJID, true)
+}
@@ -0,0 +1,239 @@
+package whatsapp
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// recordLabelAppState keeps the device's label registry and chat labels in
+// step with WhatsApp, and mirrors chat label changes to the chat's Chatwoot
+// conversation when label sync is enabled for the device.
+//
+// Label names are only known once WhatsApp sends a label edit (creating or
+// renaming a label); the initial app state sync does not emit events.
+func recordLabelAppState(ctx context.Context, evt *events.AppState, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	if deviceID == "" || chatStorageRepo == nil || evt == nil || len(evt.Index) < 2 {
+		return
+	}
+
+	switch evt.Index[0] {
+	case appstate.IndexLabelEdit:
+		action := evt.LabelEditAction
+		if action == nil {
+			return
+		}
+		if err := chatStorageRepo.SaveLabel(&domainChatStorage.Label{
+			DeviceID: deviceID,
+			ID:       evt.Index[1],
+			Name:     action.GetName(),
+			Color:    action.GetColor(),
+			Deleted:  action.GetDeleted(),
+		}); err != nil {
+			logrus.Warnf("Failed to store label %s: %v", evt.Index[1], err)
+		}
+	case appstate.IndexLabelAssociationChat:
+		if len(evt.Index) < 3 || evt.LabelAssociationAction == nil {
+			return
+		}
+		jid, err := types.ParseJID(evt.Index[2])
+		if err != nil {
+			return
+		}
+		chatJID := NormalizeJIDFromLID(ctx, jid.ToNonAD(), client).ToNonAD().String()
+		labelID := evt.Index[1]
+		labeled := evt.LabelAssociationAction.GetLabeled()
+		if err := chatStorageRepo.SetChatLabel(deviceID, chatJID, labelID, labeled); err != nil {
+			logrus.Warnf("Failed to store label %s of %s: %v", labelID, chatJID, err)
+		}
+
+		go syncChatLabelToChatwoot(chatStorageRepo, deviceID, chatJID, labelID, labeled)
+	}
+}
+
+// syncChatLabelToChatwoot adds or removes the label's Chatwoot counterpart on
+// the chat's conversation.
+func syncChatLabelToChatwoot(chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID, chatJID, labelID string, labeled bool) {
+	if !config.ChatwootEnabled {
+		return
+	}
+	resolved, err := getChatwootClientFn(deviceID)
+	if err != nil || resolved == nil || resolved.Client == nil || !resolved.LabelSync {
+		return
+	}
+	cw := resolved.Client
+
+	title := labelTitleByID(chatStorageRepo, deviceID, labelID)
+	if title == "" {
+		logrus.Debugf("Chatwoot: Skipping label %s of %s: label name unknown", labelID, chatJID)
+		return
+	}
+
+	conversation, err := findChatwootConversation(cw, chatJID)
+	if err != nil {
+		logrus.Errorf("Chatwoot: Failed to find conversation for %s: %v", chatJID, err)
+		return
+	}
+	if conversation == nil {
+		return
+	}
+
+	current, err := cw.GetConversationLabels(conversation.ID)
+	if err != nil {
+		logrus.Errorf("Chatwoot: Failed to load labels of conversation %d: %v", conversation.ID, err)
+		return
+	}
+	updated, changed := toggleChatwootLabel(current, title, labeled)
+	if !changed {
+		return
+	}
+	if err := cw.SetConversationLabels(conversation.ID, updated); err != nil {
+		logrus.Errorf("Chatwoot: Failed to update labels of conversation %d: %v", conversation.ID, err)
+		return
+	}
+	logrus.Infof("Chatwoot: Synced label %q (labeled=%v) to conversation %d", title, labeled, conversation.ID)
+}
+
+// findChatwootConversation looks up the conversation of a chat without
+// creating one: label changes on chats that never reached Chatwoot are ignored.
+func findChatwootConversation(cw *chatwoot.Client, chatJID string) (*chatwoot.Conversation, error) {
+	contact, err := findChatwootContact(cw, chatJID)
+	if err != nil || contact == nil {
+		return nil, err
+	}
+	return cw.FindConversation(contact.ID)
+}
+
+// findChatwootContact looks up the Chatwoot contact of a chat (the group
+// contact for groups) without creating one.
+func findChatwootContact(cw *chatwoot.Client, chatJID string) (*chatwoot.Contact, error) {
+	isGroup := utils.IsGroupJID(chatJID)
+	identifier := chatJID
+	if !isGroup {
+		identifier = chatwootIdentifierForJID(chatJID)
+	}
+	return cw.FindContactByIdentifier(identifier, isGroup)
+}
+
+// labelTitleByID returns the Chatwoot title of a known, non-deleted label.
+func labelTitleByID(chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID, labelID string) string {
+	labels, err := chatStorageRepo.GetLabels(deviceID)
+	if err != nil {
+		logrus.Warnf("Failed to load labels: %v", err)
+		return ""
+	}
+	for _, label := range labels {
+		if label.ID == labelID && !label.Deleted {
+			return chatwoot.LabelTitle(label.Name)
+		}
+	}
+	return ""
+}
+
+// toggleChatwootLabel adds or removes title, reporting whether labels changed.
+func toggleChatwootLabel(labels []string, title string, labeled bool) ([]string, bool) {
+	index := slices.Index(labels, title)
+	switch {
+	case labeled && index < 0:
+		return append(slices.Clone(labels), title), true
+	case !labeled && index >= 0:
+		return slices.Delete(slices.Clone(labels), index, index+1), true
+	default:
+		return labels, false
+	}
+}
+
+// chatLabelChanges compares a chat's WhatsApp labels with the Chatwoot titles
+// it should carry. Only labels with a known name take part: titles without a
+// WhatsApp label are skipped rather than created, and unnamed labels are left
+// alone.
+func chatLabelChanges(labels []*domainChatStorage.Label, current, titles []string) (add, remove []string) {
+	byTitle := make(map[string]string)
+	for _, label := range labels {
+		if title := chatwoot.LabelTitle(label.Name); title != "" && !label.Deleted {
+			if _, ok := byTitle[title]; !ok {
+				byTitle[title] = label.ID
+			}
+		}
+	}
+
+	want := make(map[string]bool)
+	for _, title := range titles {
+		if id, ok := byTitle[title]; ok {
+			want[id] = true
+		}
+	}
+	have := make(map[string]bool)
+	for _, id := range current {
+		have[id] = true
+	}
+
+	for _, id := range byTitle {
+		switch {
+		case want[id] && !have[id]:
+			add = append(add, id)
+		case !want[id] && have[id]:
+			remove = append(remove, id)
+		}
+	}
+	slices.Sort(add)
+	slices.Sort(remove)
+	return add, remove
+}
+
+// ApplyChatwootLabels makes the WhatsApp labels of chatJID match the titles of
+// its Chatwoot conversation labels.
+func ApplyChatwootLabels(ctx context.Context, client *whatsmeow.Client, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, chatJID types.JID, titles []string) error {
+	labels, err := chatStorageRepo.GetLabels(deviceID)
+	if err != nil {
+		return err
+	}
+	current, err := chatStorageRepo.GetChatLabelIDs(deviceID, chatJID.String())
+	if err != nil {
+		return err
+	}
+
+	add, remove := chatLabelChanges(labels, current, titles)
+	apply := func(labelID string, labeled bool) error {
+		if err := client.SendAppState(ctx, appstate.BuildLabelChat(chatJID, labelID, labeled)); err != nil {
+			return err
+		}
+		return chatStorageRepo.SetChatLabel(deviceID, chatJID.String(), labelID, labeled)
+	}
+	for _, labelID := range add {
+		if err := apply(labelID, true); err != nil {
+			return err
+		}
+	}
+	for _, labelID := range remove {
+		if err := apply(labelID, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetChatArchived archives or unarchives a chat and updates the stored chat.
+func SetChatArchived(ctx context.Context, client *whatsmeow.Client, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, chatJID types.JID, archived bool) error {
+	if err := client.SendAppState(ctx, appstate.BuildArchive(chatJID, archived, time.Now(), nil)); err != nil {
+		return err
+	}
+	if chatStorageRepo == nil {
+		return nil
+	}
+	if chat, _ := chatStorageRepo.GetChatByDevice(deviceID, chatJID.String()); chat != nil {
+		chat.Archived = archived
+		return chatStorageRepo.StoreChat(chat)
+	}
+	return nil
+}
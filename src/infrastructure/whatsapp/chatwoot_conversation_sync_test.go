@@ -0,0 +1,123 @@
+package whatsapp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/proto/waSyncAction"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+type labelRepoSpy struct {
+	domainChatStorage.IChatStorageRepository
+	labels     []domainChatStorage.Label
+	chatLabels []string
+}
+
+func (r *labelRepoSpy) SaveLabel(label *domainChatStorage.Label) error {
+	r.labels = append(r.labels, *label)
+	return nil
+}
+
+func (r *labelRepoSpy) SetChatLabel(deviceID, chatJID, labelID string, labeled bool) error {
+	state := "off"
+	if labeled {
+		state = "on"
+	}
+	r.chatLabels = append(r.chatLabels, deviceID+"|"+chatJID+"|"+labelID+"|"+state)
+	return nil
+}
+
+func TestRecordLabelAppStateStoresLabelsAndChatLabels(t *testing.T) {
+	repo := &labelRepoSpy{}
+
+	recordLabelAppState(context.Background(), &events.AppState{
+		Index: []string{appstate.IndexLabelEdit, "4"},
+		SyncActionValue: &waSyncAction.SyncActionValue{LabelEditAction: &waSyncAction.LabelEditAction{
+			Name:  proto.String("Pending Payment"),
+			Color: proto.Int32(2),
+		}},
+	}, repo, "dev-1", nil)
+	recordLabelAppState(context.Background(), &events.AppState{
+		Index: []string{appstate.IndexLabelAssociationChat, "4", "628111:3@s.whatsapp.net"},
+		SyncActionValue: &waSyncAction.SyncActionValue{LabelAssociationAction: &waSyncAction.LabelAssociationAction{
+			Labeled: proto.Bool(true),
+		}},
+	}, repo, "dev-1", nil)
+
+	if len(repo.labels) != 1 {
+		t.Fatalf("stored labels = %+v, want one", repo.labels)
+	}
+	if got := repo.labels[0]; got.DeviceID != "dev-1" || got.ID != "4" || got.Name != "Pending Payment" || got.Color != 2 || got.Deleted {
+		t.Fatalf("stored label = %+v", got)
+	}
+	if want := []string{"dev-1|628111@s.whatsapp.net|4|on"}; !slices.Equal(repo.chatLabels, want) {
+		t.Fatalf("chat labels = %v, want %v", repo.chatLabels, want)
+	}
+
+	// Without a device there is nowhere to keep the label.
+	recordLabelAppState(context.Background(), &events.AppState{
+		Index:           []string{appstate.IndexLabelEdit, "5"},
+		SyncActionValue: &waSyncAction.SyncActionValue{LabelEditAction: &waSyncAction.LabelEditAction{Name: proto.String("VIP")}},
+	}, repo, "", nil)
+	if len(repo.labels) != 1 {
+		t.Fatalf("label stored without device: %+v", repo.labels)
+	}
+}
+
+func TestChatLabelChanges(t *testing.T) {
+	labels := []*domainChatStorage.Label{
+		{ID: "1", Name: "VIP"},
+		{ID: "2", Name: "Pending Payment"},
+		{ID: "3", Name: "Old", Deleted: true},
+		{ID: "4", Name: "Paid"},
+	}
+
+	tests := []struct {
+		name       string
+		current    []string
+		titles     []string
+		wantAdd    []string
+		wantRemove []string
+	}{
+		{"adds matching titles", nil, []string{"vip", "pending-payment"}, []string{"1", "2"}, nil},
+		{"removes labels no longer in chatwoot", []string{"1", "4"}, []string{"vip"}, nil, []string{"4"}},
+		{"ignores titles without a whatsapp label", nil, []string{"billing"}, nil, nil},
+		{"leaves unknown and deleted labels alone", []string{"3", "9"}, nil, nil, nil},
+		{"no changes when in sync", []string{"1"}, []string{"vip"}, nil, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			add, remove := chatLabelChanges(labels, tc.current, tc.titles)
+			if !slices.Equal(add, tc.wantAdd) || !slices.Equal(remove, tc.wantRemove) {
+				t.Fatalf("changes = +%v -%v, want +%v -%v", add, remove, tc.wantAdd, tc.wantRemove)
+			}
+		})
+	}
+}
+
+func TestToggleChatwootLabel(t *testing.T) {
+	labels := []string{"vip", "billing"}
+
+	added, changed := toggleChatwootLabel(labels, "paid", true)
+	if !changed || !slices.Equal(added, []string{"vip", "billing", "paid"}) {
+		t.Fatalf("add = %v (%v)", added, changed)
+	}
+	removed, changed := toggleChatwootLabel(labels, "vip", false)
+	if !changed || !slices.Equal(removed, []string{"billing"}) {
+		t.Fatalf("remove = %v (%v)", removed, changed)
+	}
+	if !slices.Equal(labels, []string{"vip", "billing"}) {
+		t.Fatalf("input mutated: %v", labels)
+	}
+	if _, changed := toggleChatwootLabel(labels, "vip", true); changed {
+		t.Fatal("adding a present label must be a no-op")
+	}
+	if _, changed := toggleChatwootLabel(labels, "paid", false); changed {
+		t.Fatal("removing a missing label must be a no-op")
+	}
+}
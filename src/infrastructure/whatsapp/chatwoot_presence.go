@@ -0,0 +1,86 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// chatwootTypingConversationTTL bounds how long a chat's conversation id is
+// reused for typing events. Typing arrives in bursts, and looking the
+// conversation up costs two Chatwoot requests.
+const chatwootTypingConversationTTL = 10 * time.Minute
+
+type chatwootConversationCacheEntry struct {
+	conversationID int
+	expiresAt      time.Time
+}
+
+var chatwootTypingConversations sync.Map // key -> chatwootConversationCacheEntry
+
+// relayChatPresenceToChatwoot toggles the contact's typing indicator on the
+// chat's Chatwoot conversation. Chats without a conversation are ignored.
+func relayChatPresenceToChatwoot(ctx context.Context, evt *events.ChatPresence, deviceID string, client *whatsmeow.Client) {
+	if !config.ChatwootEnabled || !config.ChatwootTyping || evt == nil || evt.IsFromMe {
+		return
+	}
+	chatJID := NormalizeJIDFromLID(ctx, evt.Chat.ToNonAD(), client).ToNonAD().String()
+	if utils.IsSystemBroadcastJID(chatJID) || utils.IsNewsletterJID(chatJID) || utils.MatchesIgnoredJID(chatJID, config.ChatwootIgnoreJids) {
+		return
+	}
+
+	resolved, err := getChatwootClientFn(deviceID)
+	if err != nil || resolved == nil || resolved.Client == nil || !resolved.Client.IsConfigured() {
+		return
+	}
+	cw := resolved.Client
+
+	conversationID, err := chatwootConversationIDForChat(cw, chatJID, time.Now())
+	if err != nil {
+		logrus.Debugf("Chatwoot: Failed to find conversation for typing in %s: %v", chatJID, err)
+		return
+	}
+	if conversationID == 0 {
+		return
+	}
+	// The conversation's contact_inbox is keyed by the chat JID (see
+	// chatwootContactInfo.ChatJID), which the public API uses as the contact.
+	typing := evt.State == types.ChatPresenceComposing
+	if err := cw.ToggleTyping(conversationID, chatJID, typing); err != nil {
+		logrus.Debugf("Chatwoot: Failed to toggle typing on conversation %d: %v", conversationID, err)
+	}
+}
+
+// chatwootConversationIDForChat returns the chat's conversation id, or 0 when
+// the chat has none, caching both answers for chatwootTypingConversationTTL.
+func chatwootConversationIDForChat(cw *chatwoot.Client, chatJID string, now time.Time) (int, error) {
+	key := fmt.Sprintf("%s|%d|%d|%s", cw.BaseURL, cw.AccountID, cw.InboxID, chatJID)
+	if cached, ok := chatwootTypingConversations.Load(key); ok {
+		if entry := cached.(chatwootConversationCacheEntry); now.Before(entry.expiresAt) {
+			return entry.conversationID, nil
+		}
+	}
+
+	conversation, err := findChatwootConversation(cw, chatJID)
+	if err != nil {
+		return 0, err
+	}
+	conversationID := 0
+	if conversation != nil {
+		conversationID = conversation.ID
+	}
+	chatwootTypingConversations.Store(key, chatwootConversationCacheEntry{
+		conversationID: conversationID,
+		expiresAt:      now.Add(chatwootTypingConversationTTL),
+	})
+	return conversationID, nil
+}
@@ -0,0 +1,66 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+)
+
+func TestChatwootConversationIDForChatCachesLookups(t *testing.T) {
+	var searches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/accounts/1/contacts/search":
+			searches++
+			contacts := []map[string]any{}
+			if r.URL.Query().Get("q") == "+628111" {
+				contacts = append(contacts, map[string]any{"id": 9, "phone_number": "+628111"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"payload": contacts})
+		case "/api/v1/accounts/1/contacts/9/conversations":
+			_ = json.NewEncoder(w).Encode(map[string]any{"payload": []map[string]any{
+				{"id": 55, "inbox_id": 2, "status": "open"},
+			}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.String())
+		}
+	}))
+	defer server.Close()
+
+	cw := chatwoot.NewClientFromConfig(server.URL, "token", 1, 2)
+	cw.HTTPClient = http.DefaultClient
+	now := time.Now()
+
+	for range 2 {
+		id, err := chatwootConversationIDForChat(cw, "628111@s.whatsapp.net", now)
+		if err != nil || id != 55 {
+			t.Fatalf("conversation = %d, %v; want 55", id, err)
+		}
+	}
+	if searches != 1 {
+		t.Fatalf("searches = %d, want 1 (second lookup is cached)", searches)
+	}
+
+	// Chats without a conversation are cached as well.
+	for range 2 {
+		if id, err := chatwootConversationIDForChat(cw, "628999@s.whatsapp.net", now); err != nil || id != 0 {
+			t.Fatalf("conversation = %d, %v; want 0", id, err)
+		}
+	}
+	if searches != 2 {
+		t.Fatalf("searches = %d, want 2", searches)
+	}
+
+	// Expired entries are looked up again.
+	if _, err := chatwootConversationIDForChat(cw, "628111@s.whatsapp.net", now.Add(chatwootTypingConversationTTL+time.Second)); err != nil {
+		t.Fatalf("lookup after TTL: %v", err)
+	}
+	if searches != 3 {
+		t.Fatalf("searches = %d, want 3 after TTL", searches)
+	}
+}
@@ -2,6 +2,7 @@ package whatsapp
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -40,3 +41,17 @@ func initDatabase(ctx context.Context, dbLog waLog.Logger, DBURI string) (*sqlst
 
 	return nil, fmt.Errorf("unknown database type: %s. Currently only sqlite3(file:) and postgres are supported", DBURI)
 }
+
+// openStoreSQL opens a plain handle on a whatsmeow store database for the
+// row-level work sqlstore does not expose (device backups).
+func openStoreSQL(DBURI string) (*sql.DB, error) {
+	DBURI = strings.Trim(DBURI, `"'`)
+
+	if strings.HasPrefix(DBURI, "file:") {
+		return sql.Open(sqlite.DriverName, sqlite.FormatChatStorageURI(DBURI, true, true))
+	} else if strings.HasPrefix(DBURI, "postgres:") {
+		return sql.Open("postgres", DBURI)
+	}
+
+	return nil, fmt.Errorf("unknown database type: %s. Currently only sqlite3(file:) and postgres are supported", DBURI)
+}
@@ -0,0 +1,756 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	deviceBackupFormat     = "gowa-device-backup"
+	deviceBackupVersion    = 1
+	deviceBackupKDF        = "pbkdf2-sha256"
+	deviceBackupIterations = 600_000
+)
+
+// deviceBackupEnvelope is the file handed to the user. Only the KDF
+// parameters are readable; everything about the device is in Ciphertext.
+type deviceBackupEnvelope struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// deviceBackup is the plaintext of a backup: the slot, its settings and the
+// whatsmeow rows that make up the paired session.
+type deviceBackup struct {
+	CreatedAt  time.Time                               `json:"created_at"`
+	AppVersion string                                  `json:"app_version"`
+	Device     deviceBackupRecord                      `json:"device"`
+	Chatwoot   *domainChatStorage.ChatwootDeviceConfig `json:"chatwoot,omitempty"`
+	Store      []backupTable                           `json:"store"`
+}
+
+type deviceBackupRecord struct {
+	DeviceID    string                                 `json:"device_id"`
+	DisplayName string                                 `json:"display_name"`
+	JID         string                                 `json:"jid"`
+	ADJID       string                                 `json:"ad_jid"`
+	Webhook     *domainChatStorage.DeviceWebhookConfig `json:"webhook,omitempty"`
+	Call        *domainChatStorage.DeviceCallConfig    `json:"call,omitempty"`
+	Proxy       *domainChatStorage.DeviceProxyConfig   `json:"proxy,omitempty"`
+	Attributes  *domainChatStorage.DeviceAttributes    `json:"attributes,omitempty"`
+}
+
+type backupTable struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+type backupColumnKind int
+
+const (
+	backupText backupColumnKind = iota
+	backupBytes
+	backupInt
+	backupBool
+)
+
+type backupColumn struct {
+	name string
+	kind backupColumnKind
+}
+
+// backupTableSpec describes one whatsmeow table of a device. Columns are typed
+// explicitly so a backup taken on SQLite restores on Postgres and vice versa.
+type backupTableSpec struct {
+	name  string
+	owner string // column holding the device's AD JID
+	// keys marks tables served by the keys store when DB_KEYS_URI is set
+	// (see applyKeyCacheStore).
+	keys    bool
+	columns []backupColumn
+}
+
+// deviceBackupTables lists the per-device whatsmeow tables in foreign-key
+// order. The event and retry buffers are transient and left out, as is the
+// LID map, which is shared by all devices and refilled by whatsmeow.
+var deviceBackupTables = []backupTableSpec{
+	{name: "whatsmeow_device", owner: "jid", columns: []backupColumn{
+		{"jid", backupText}, {"lid", backupText}, {"facebook_uuid", backupText},
+		{"registration_id", backupInt}, {"noise_key", backupBytes}, {"identity_key", backupBytes},
+		{"signed_pre_key", backupBytes}, {"signed_pre_key_id", backupInt}, {"signed_pre_key_sig", backupBytes},
+		{"adv_key", backupBytes}, {"adv_details", backupBytes}, {"adv_account_sig", backupBytes},
+		{"adv_account_sig_key", backupBytes}, {"adv_device_sig", backupBytes},
+		{"platform", backupText}, {"business_name", backupText}, {"push_name", backupText},
+		{"lid_migration_ts", backupInt}, {"companion_meta_nonce", backupText},
+	}},
+	{name: "whatsmeow_identity_keys", owner: "our_jid", keys: true, columns: []backupColumn{
+		{"our_jid", backupText}, {"their_id", backupText}, {"identity", backupBytes},
+	}},
+	{name: "whatsmeow_pre_keys", owner: "jid", keys: true, columns: []backupColumn{
+		{"jid", backupText}, {"key_id", backupInt}, {"key", backupBytes}, {"uploaded", backupBool},
+	}},
+	{name: "whatsmeow_sessions", owner: "our_jid", keys: true, columns: []backupColumn{
+		{"our_jid", backupText}, {"their_id", backupText}, {"session", backupBytes},
+	}},
+	{name: "whatsmeow_sender_keys", owner: "our_jid", keys: true, columns: []backupColumn{
+		{"our_jid", backupText}, {"chat_id", backupText}, {"sender_id", backupText}, {"sender_key", backupBytes},
+	}},
+	{name: "whatsmeow_app_state_sync_keys", owner: "jid", columns: []backupColumn{
+		{"jid", backupText}, {"key_id", backupBytes}, {"key_data", backupBytes},
+		{"timestamp", backupInt}, {"fingerprint", backupBytes},
+	}},
+	{name: "whatsmeow_app_state_version", owner: "jid", columns: []backupColumn{
+		{"jid", backupText}, {"name", backupText}, {"version", backupInt}, {"hash", backupBytes},
+	}},
+	{name: "whatsmeow_app_state_mutation_macs", owner: "jid", columns: []backupColumn{
+		{"jid", backupText}, {"name", backupText}, {"version", backupInt},
+		{"index_mac", backupBytes}, {"value_mac", backupBytes},
+	}},
+	{name: "whatsmeow_contacts", owner: "our_jid", columns: []backupColumn{
+		{"our_jid", backupText}, {"their_jid", backupText}, {"first_name", backupText},
+		{"full_name", backupText}, {"push_name", backupText}, {"business_name", backupText},
+		{"redacted_phone", backupText},
+	}},
+	{name: "whatsmeow_chat_settings", owner: "our_jid", columns: []backupColumn{
+		{"our_jid", backupText}, {"chat_jid", backupText}, {"muted_until", backupInt},
+		{"pinned", backupBool}, {"archived", backupBool},
+	}},
+	{name: "whatsmeow_message_secrets", owner: "our_jid", keys: true, columns: []backupColumn{
+		{"our_jid", backupText}, {"chat_jid", backupText}, {"sender_jid", backupText},
+		{"message_id", backupText}, {"key", backupBytes},
+	}},
+	{name: "whatsmeow_privacy_tokens", owner: "our_jid", columns: []backupColumn{
+		{"our_jid", backupText}, {"their_jid", backupText}, {"token", backupBytes},
+		{"timestamp", backupInt}, {"sender_timestamp", backupInt},
+	}},
+	{name: "whatsmeow_nct_salt", owner: "our_jid", columns: []backupColumn{
+		{"our_jid", backupText}, {"salt", backupBytes},
+	}},
+}
+
+// ExportDeviceBackup returns the paired session of a device together with its
+// registry record, webhook/call/proxy settings and Chatwoot config, encrypted
+// with passphrase.
+func (m *DeviceManager) ExportDeviceBackup(ctx context.Context, deviceID, passphrase string) ([]byte, error) {
+	if m == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+	inst, ok := m.GetDevice(deviceID)
+	if !ok || inst == nil {
+		return nil, pkgError.ErrDeviceNotFound
+	}
+	adJID := sessionKey(inst)
+	if adJID == "" {
+		return nil, pkgError.ValidationError(fmt.Sprintf("device %s is not paired; there is no session to back up", deviceID))
+	}
+
+	primary, keys, closeDBs, err := m.openStoreDBs()
+	if err != nil {
+		return nil, err
+	}
+	defer closeDBs()
+
+	tables, err := exportStoreRows(ctx, primary, keys, adJID)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 || len(tables[0].Rows) == 0 {
+		return nil, pkgError.ValidationError(fmt.Sprintf("device %s has no session in the whatsmeow store", deviceID))
+	}
+
+	backup, err := m.deviceSettings(inst)
+	if err != nil {
+		return nil, err
+	}
+	backup.CreatedAt = time.Now().UTC()
+	backup.AppVersion = config.AppVersion
+	backup.Store = tables
+
+	plaintext, err := json.Marshal(backup)
+	if err != nil {
+		return nil, err
+	}
+	return sealDeviceBackup(plaintext, passphrase)
+}
+
+// ImportDeviceBackup restores a backup as a device slot, named deviceID when
+// given and after the exported slot otherwise. It refuses to create a second
+// copy of a session: the companion must be absent from the store and from
+// every slot (remove it with PurgeDevice first). The restored device is left
+// disconnected; connect it once the original instance no longer runs it.
+func (m *DeviceManager) ImportDeviceBackup(ctx context.Context, data []byte, passphrase, deviceID string) (*DeviceInstance, error) {
+	if m == nil || m.store == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+
+	plaintext, err := openDeviceBackup(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	var backup deviceBackup
+	decoder := json.NewDecoder(bytes.NewReader(plaintext))
+	decoder.UseNumber()
+	if err := decoder.Decode(&backup); err != nil {
+		return nil, pkgError.ValidationError("backup content is malformed")
+	}
+
+	adJID, err := backupSessionJID(backup)
+	if err != nil {
+		return nil, err
+	}
+	nonAD := adJID.ToNonAD().String()
+
+	deviceID = strings.TrimSpace(deviceID)
+	if deviceID == "" {
+		deviceID = backup.Device.DeviceID
+	}
+	if deviceID == "" {
+		deviceID = nonAD
+	}
+
+	// One companion session may back one slot only (issue #760): importing it
+	// next to a live copy would make both fight over the same identity.
+	var target *DeviceInstance
+	for _, inst := range m.ListDevices() {
+		if sessionKey(inst) == adJID.String() {
+			return nil, pkgError.ConflictError(fmt.Sprintf("session %s is already used by device %s; remove that device first", adJID, inst.ID()))
+		}
+		if inst.ID() == deviceID {
+			if storeIdentity(inst) != "" {
+				return nil, pkgError.ConflictError(fmt.Sprintf("device %s is paired with another session; choose another device_id", deviceID))
+			}
+			target = inst
+		}
+	}
+	existing, err := findStoreDeviceByJID(ctx, m.store, adJID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, pkgError.ConflictError(fmt.Sprintf("session %s already exists in the whatsmeow store; remove its device first", adJID))
+	}
+	// The primary store has no row, so anything left under this exact
+	// companion (a stale keys-store row) is an orphan from an earlier purge.
+	if err := m.deleteStoreRowsForJID(ctx, adJID.String()); err != nil {
+		return nil, err
+	}
+
+	primary, keys, closeDBs, err := m.openStoreDBs()
+	if err != nil {
+		return nil, err
+	}
+	defer closeDBs()
+
+	if err := importStoreRows(ctx, primary, keys, backup.Store, adJID.String()); err != nil {
+		if cleanupErr := m.deleteStoreRowsForJID(ctx, adJID.String()); cleanupErr != nil {
+			logrus.WithError(cleanupErr).Warnf("[DEVICE_BACKUP] failed to roll back partial restore of %s", adJID)
+		}
+		return nil, fmt.Errorf("failed to restore session rows: %w", err)
+	}
+
+	if target == nil {
+		target = NewDeviceInstance(deviceID, nil, nil)
+	}
+	m.applyStoreJID(target, adJID)
+	target.mu.Lock()
+	target.displayName = backup.Device.DisplayName
+	target.mu.Unlock()
+	m.AddDevice(target)
+
+	if err := m.restoreDeviceSettings(deviceID, backup); err != nil {
+		return nil, fmt.Errorf("device %s restored but its settings could not be saved: %w", deviceID, err)
+	}
+
+	logrus.Infof("[DEVICE_BACKUP] restored session %s as device %s", adJID, deviceID)
+	return target, nil
+}
+
+// deviceSettings returns the registry record, webhook/call/proxy settings,
+// attributes and Chatwoot config of a slot, without any session rows. Backups
+// add the session to it; HA hands it to the replica taking the session over.
+func (m *DeviceManager) deviceSettings(inst *DeviceInstance) (deviceBackup, error) {
+	deviceID := inst.ID()
+	backup := deviceBackup{
+		Device: deviceBackupRecord{
+			DeviceID:    deviceID,
+			DisplayName: inst.DisplayName(),
+			JID:         inst.JID(),
+			ADJID:       sessionKey(inst),
+		},
+	}
+	if m.storage == nil {
+		return backup, nil
+	}
+
+	var err error
+	if backup.Device.Webhook, err = m.storage.GetDeviceWebhookConfig(deviceID); err != nil {
+		return backup, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+	if backup.Device.Call, err = m.storage.GetDeviceCallConfig(deviceID); err != nil {
+		return backup, fmt.Errorf("failed to read call config: %w", err)
+	}
+	if backup.Device.Proxy, err = m.storage.GetDeviceProxyConfig(deviceID); err != nil {
+		return backup, fmt.Errorf("failed to read proxy config: %w", err)
+	}
+	if backup.Device.Attributes, err = m.storage.GetDeviceAttributes(deviceID); err != nil {
+		return backup, fmt.Errorf("failed to read device attributes: %w", err)
+	}
+	if backup.Chatwoot, err = m.storage.GetChatwootDeviceConfig(deviceID); err != nil {
+		return backup, fmt.Errorf("failed to read chatwoot config: %w", err)
+	}
+	return backup, nil
+}
+
+func (m *DeviceManager) restoreDeviceSettings(deviceID string, backup deviceBackup) error {
+	if m.storage == nil {
+		return nil
+	}
+	if cfg := backup.Device.Webhook; cfg != nil {
+		if err := m.storage.SetDeviceWebhookConfig(deviceID, cfg); err != nil {
+			return err
+		}
+	}
+	if cfg := backup.Device.Call; cfg != nil {
+		if err := m.storage.SetDeviceCallConfig(deviceID, cfg); err != nil {
+			return err
+		}
+	}
+	if cfg := backup.Device.Proxy; cfg != nil {
+		if err := m.storage.SetDeviceProxyConfig(deviceID, cfg); err != nil {
+			return err
+		}
+	}
+	if attributes := backup.Device.Attributes; attributes != nil {
+		if err := m.storage.SetDeviceAttributes(deviceID, attributes); err != nil {
+			return err
+		}
+		if inst, ok := m.GetDevice(deviceID); ok {
+			inst.SetAttributes(attributes)
+		}
+	}
+	if cfg := backup.Chatwoot; cfg != nil {
+		cfg.ID = 0
+		cfg.DeviceID = deviceID
+		if err := m.storage.SaveChatwootDeviceConfig(cfg); err != nil {
+			return err
+		}
+		if reg := chatwoot.GetClientRegistry(); reg != nil {
+			reg.Invalidate(deviceID)
+		}
+	}
+	return nil
+}
+
+// backupSessionJID returns the companion JID of the device row in a backup.
+func backupSessionJID(backup deviceBackup) (types.JID, error) {
+	for _, table := range backup.Store {
+		if table.Name != "whatsmeow_device" || len(table.Rows) != 1 {
+			continue
+		}
+		for i, column := range table.Columns {
+			if column != "jid" || i >= len(table.Rows[0]) {
+				continue
+			}
+			raw, _ := table.Rows[0][i].(string)
+			jid, err := types.ParseJID(raw)
+			if err != nil || jid.User == "" || jid.Device == 0 {
+				break
+			}
+			if backup.Device.ADJID != "" && backup.Device.ADJID != jid.String() {
+				break
+			}
+			return jid, nil
+		}
+	}
+	return types.JID{}, pkgError.ValidationError("backup holds no usable device session")
+}
+
+// openStoreDBs opens plain handles on the whatsmeow store and, when it is a
+// separate database, the keys store. keys is nil when both are the same.
+func (m *DeviceManager) openStoreDBs() (primary, keys *sql.DB, closeDBs func(), err error) {
+	dbURI, keysURI := m.StoreInfo()
+	primary, err = openStoreSQL(dbURI)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if keysURI != "" && keysURI != dbURI {
+		if keys, err = openStoreSQL(keysURI); err != nil {
+			_ = primary.Close()
+			return nil, nil, nil, err
+		}
+	}
+	return primary, keys, func() {
+		_ = primary.Close()
+		if keys != nil {
+			_ = keys.Close()
+		}
+	}, nil
+}
+
+// exportStoreRows reads every backup table row owned by adJID. Key tables
+// come from keys when it is set.
+func exportStoreRows(ctx context.Context, primary, keys *sql.DB, adJID string) ([]backupTable, error) {
+	tables := make([]backupTable, 0, len(deviceBackupTables))
+	for _, spec := range deviceBackupTables {
+		db := primary
+		if spec.keys && keys != nil {
+			db = keys
+		}
+
+		names := make([]string, len(spec.columns))
+		for i, column := range spec.columns {
+			names[i] = column.name
+		}
+		rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1",
+			strings.Join(names, ", "), spec.name, spec.owner), adJID)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", spec.name, err)
+		}
+
+		table := backupTable{Name: spec.name, Columns: names, Rows: [][]any{}}
+		for rows.Next() {
+			values := make([]any, len(spec.columns))
+			pointers := make([]any, len(values))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+			if err := rows.Scan(pointers...); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("read %s: %w", spec.name, err)
+			}
+			for i, column := range spec.columns {
+				if values[i], err = exportBackupValue(column.kind, values[i]); err != nil {
+					_ = rows.Close()
+					return nil, fmt.Errorf("read %s.%s: %w", spec.name, column.name, err)
+				}
+			}
+			table.Rows = append(table.Rows, values)
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", spec.name, err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+// importStoreRows writes backup tables in one transaction per database. The
+// device row goes to both databases because the key tables reference it.
+// Every row must belong to adJID: the store is shared by all devices, and a
+// crafted backup must not overwrite another session's keys. When the keys
+// database commits but the primary one does not, the keys rows are deleted
+// again so no half-restored session is left behind.
+func importStoreRows(ctx context.Context, primary, keys *sql.DB, tables []backupTable, adJID string) error {
+	specs := make(map[string]backupTableSpec, len(deviceBackupTables))
+	for _, spec := range deviceBackupTables {
+		specs[spec.name] = spec
+	}
+	byName := make(map[string]backupTable, len(tables))
+	for _, table := range tables {
+		spec, ok := specs[table.Name]
+		if !ok {
+			return pkgError.ValidationError(fmt.Sprintf("backup contains unknown table %s", table.Name))
+		}
+		if err := checkBackupOwner(spec, table, adJID); err != nil {
+			return err
+		}
+		byName[table.Name] = table
+	}
+
+	primaryTx, err := primary.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = primaryTx.Rollback() }()
+
+	var keysTx *sql.Tx
+	if keys != nil {
+		if keysTx, err = keys.BeginTx(ctx, nil); err != nil {
+			return err
+		}
+		defer func() { _ = keysTx.Rollback() }()
+	}
+
+	// Insert in foreign-key order regardless of the order in the file.
+	for _, spec := range deviceBackupTables {
+		table, ok := byName[spec.name]
+		if !ok {
+			continue
+		}
+		targets := []*sql.Tx{primaryTx}
+		switch {
+		case keysTx != nil && spec.name == "whatsmeow_device":
+			targets = append(targets, keysTx)
+		case keysTx != nil && spec.keys:
+			targets = []*sql.Tx{keysTx}
+		}
+		for _, tx := range targets {
+			if err := insertBackupTable(ctx, tx, spec, table); err != nil {
+				return err
+			}
+		}
+	}
+
+	if keysTx == nil {
+		return primaryTx.Commit()
+	}
+	if err := keysTx.Commit(); err != nil {
+		return err
+	}
+	if err := primaryTx.Commit(); err != nil {
+		if cleanupErr := deleteBackupRows(ctx, keys, adJID); cleanupErr != nil {
+			logrus.WithError(cleanupErr).Warnf("[DEVICE_BACKUP] failed to undo keys rows of %s", adJID)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkBackupOwner rejects a table with any row whose owner column is not the
+// session being restored.
+func checkBackupOwner(spec backupTableSpec, table backupTable, adJID string) error {
+	owner := -1
+	for i, name := range table.Columns {
+		if name == spec.owner {
+			owner = i
+			break
+		}
+	}
+	if owner < 0 && len(table.Rows) > 0 {
+		return pkgError.ValidationError(fmt.Sprintf("backup table %s has no %s column", spec.name, spec.owner))
+	}
+	for _, row := range table.Rows {
+		if owner >= len(row) {
+			return pkgError.ValidationError(fmt.Sprintf("backup row of %s has no %s value", spec.name, spec.owner))
+		}
+		if value, _ := row[owner].(string); value != adJID {
+			return pkgError.ValidationError(fmt.Sprintf("backup table %s holds rows of another session", spec.name))
+		}
+	}
+	return nil
+}
+
+// deleteBackupRows removes what a restore wrote to the keys database: the key
+// tables first, then the device row they reference.
+func deleteBackupRows(ctx context.Context, db *sql.DB, adJID string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for i := len(deviceBackupTables) - 1; i >= 0; i-- {
+		spec := deviceBackupTables[i]
+		if !spec.keys && spec.name != "whatsmeow_device" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s = $1", spec.name, spec.owner), adJID); err != nil {
+			return fmt.Errorf("delete %s: %w", spec.name, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func insertBackupTable(ctx context.Context, tx *sql.Tx, spec backupTableSpec, table backupTable) error {
+	kinds := make([]backupColumnKind, len(table.Columns))
+	placeholders := make([]string, len(table.Columns))
+	for i, name := range table.Columns {
+		found := false
+		for _, column := range spec.columns {
+			if column.name == name {
+				kinds[i], found = column.kind, true
+				break
+			}
+		}
+		if !found {
+			return pkgError.ValidationError(fmt.Sprintf("backup column %s.%s is not supported by this version", spec.name, name))
+		}
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		spec.name, strings.Join(table.Columns, ", "), strings.Join(placeholders, ", "))
+
+	for _, row := range table.Rows {
+		if len(row) != len(kinds) {
+			return pkgError.ValidationError(fmt.Sprintf("backup row of %s has %d values for %d columns", spec.name, len(row), len(kinds)))
+		}
+		args := make([]any, len(row))
+		for i, value := range row {
+			arg, err := importBackupValue(kinds[i], value)
+			if err != nil {
+				return pkgError.ValidationError(fmt.Sprintf("backup value %s.%s: %v", spec.name, table.Columns[i], err))
+			}
+			args[i] = arg
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("write %s: %w", spec.name, err)
+		}
+	}
+	return nil
+}
+
+// exportBackupValue normalizes a scanned value across SQL drivers: SQLite
+// returns integers for booleans and either strings or bytes for text.
+func exportBackupValue(kind backupColumnKind, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch kind {
+	case backupText:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case []byte:
+			return string(v), nil
+		}
+	case backupBytes:
+		switch v := value.(type) {
+		case []byte:
+			return bytes.Clone(v), nil
+		case string:
+			return []byte(v), nil
+		}
+	case backupInt:
+		if v, ok := value.(int64); ok {
+			return v, nil
+		}
+	case backupBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case int64:
+			return v != 0, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %T", value)
+}
+
+// importBackupValue converts a JSON-decoded value (numbers as json.Number,
+// bytes as base64 strings) back to its SQL argument.
+func importBackupValue(kind backupColumnKind, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch kind {
+	case backupText:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case backupBytes:
+		if v, ok := value.(string); ok {
+			return base64.StdEncoding.DecodeString(v)
+		}
+	case backupInt:
+		if v, ok := value.(json.Number); ok {
+			return v.Int64()
+		}
+	case backupBool:
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %T", value)
+}
+
+func deviceBackupAAD(envelope deviceBackupEnvelope) []byte {
+	return fmt.Appendf(nil, "%s/%d/%s/%d", envelope.Format, envelope.Version, envelope.KDF, envelope.Iterations)
+}
+
+func deviceBackupKey(passphrase string, salt []byte, iterations int) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+}
+
+// sealDeviceBackup encrypts a backup with AES-256-GCM under a key derived
+// from passphrase.
+func sealDeviceBackup(plaintext []byte, passphrase string) ([]byte, error) {
+	envelope := deviceBackupEnvelope{
+		Format:     deviceBackupFormat,
+		Version:    deviceBackupVersion,
+		KDF:        deviceBackupKDF,
+		Iterations: deviceBackupIterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(envelope.Salt); err != nil {
+		return nil, err
+	}
+	key, err := deviceBackupKey(passphrase, envelope.Salt, envelope.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return nil, err
+	}
+	envelope.Ciphertext = gcm.Seal(nil, envelope.Nonce, plaintext, deviceBackupAAD(envelope))
+	return json.Marshal(envelope)
+}
+
+// openDeviceBackup checks the envelope and decrypts it. A wrong passphrase
+// and a tampered file are indistinguishable by design.
+func openDeviceBackup(data []byte, passphrase string) ([]byte, error) {
+	var envelope deviceBackupEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Format != deviceBackupFormat {
+		return nil, pkgError.ValidationError("file is not a device backup")
+	}
+	if envelope.Version != deviceBackupVersion || envelope.KDF != deviceBackupKDF {
+		return nil, pkgError.ValidationError(fmt.Sprintf("unsupported device backup version %d (%s)", envelope.Version, envelope.KDF))
+	}
+	if envelope.Iterations < 100_000 || envelope.Iterations > 10_000_000 || len(envelope.Salt) < 16 {
+		return nil, pkgError.ValidationError("device backup has invalid key derivation parameters")
+	}
+
+	key, err := deviceBackupKey(passphrase, envelope.Salt, envelope.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(envelope.Nonce) != gcm.NonceSize() {
+		return nil, pkgError.ValidationError("device backup is corrupted")
+	}
+	plaintext, err := gcm.Open(nil, envelope.Nonce, envelope.Ciphertext, deviceBackupAAD(envelope))
+	if err != nil {
+		return nil, pkgError.ValidationError("wrong passphrase or corrupted device backup")
+	}
+	return plaintext, nil
+}
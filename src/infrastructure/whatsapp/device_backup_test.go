@@ -0,0 +1,266 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/sqlite"
+	"go.mau.fi/whatsmeow/store/sqlstore"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// backupStubStorage keeps the per-device settings a backup carries in memory.
+type backupStubStorage struct {
+	domainChatStorage.IChatStorageRepository
+	webhooks map[string]*domainChatStorage.DeviceWebhookConfig
+	chatwoot map[string]*domainChatStorage.ChatwootDeviceConfig
+	attrs    map[string]*domainChatStorage.DeviceAttributes
+}
+
+func newBackupStubStorage() *backupStubStorage {
+	return &backupStubStorage{
+		webhooks: make(map[string]*domainChatStorage.DeviceWebhookConfig),
+		chatwoot: make(map[string]*domainChatStorage.ChatwootDeviceConfig),
+		attrs:    make(map[string]*domainChatStorage.DeviceAttributes),
+	}
+}
+
+func (s *backupStubStorage) SaveDeviceRecord(*domainChatStorage.DeviceRecord) error { return nil }
+
+func (s *backupStubStorage) GetDeviceWebhookConfig(deviceID string) (*domainChatStorage.DeviceWebhookConfig, error) {
+	return s.webhooks[deviceID], nil
+}
+
+func (s *backupStubStorage) SetDeviceWebhookConfig(deviceID string, cfg *domainChatStorage.DeviceWebhookConfig) error {
+	s.webhooks[deviceID] = cfg
+	return nil
+}
+
+func (s *backupStubStorage) GetDeviceCallConfig(string) (*domainChatStorage.DeviceCallConfig, error) {
+	return nil, nil
+}
+
+func (s *backupStubStorage) GetDeviceProxyConfig(string) (*domainChatStorage.DeviceProxyConfig, error) {
+	return nil, nil
+}
+
+func (s *backupStubStorage) GetDeviceAttributes(deviceID string) (*domainChatStorage.DeviceAttributes, error) {
+	return s.attrs[deviceID], nil
+}
+
+func (s *backupStubStorage) SetDeviceAttributes(deviceID string, attributes *domainChatStorage.DeviceAttributes) error {
+	s.attrs[deviceID] = attributes
+	return nil
+}
+
+func (s *backupStubStorage) GetChatwootDeviceConfig(deviceID string) (*domainChatStorage.ChatwootDeviceConfig, error) {
+	return s.chatwoot[deviceID], nil
+}
+
+func (s *backupStubStorage) SaveChatwootDeviceConfig(cfg *domainChatStorage.ChatwootDeviceConfig) error {
+	s.chatwoot[cfg.DeviceID] = cfg
+	return nil
+}
+
+// newBackupTestStore creates a SQLite whatsmeow store and returns it with the
+// URI that openStoreSQL needs to reach the same file.
+func newBackupTestStore(t *testing.T) (*sqlstore.Container, string) {
+	t.Helper()
+
+	dbURI := "file:" + filepath.Join(t.TempDir(), "whatsmeow.db")
+	container, err := sqlstore.New(context.Background(), sqlite.DriverName, sqlite.FormatChatStorageURI(dbURI, true, true), nil)
+	if err != nil {
+		t.Fatalf("create sqlstore: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = container.Close()
+	})
+	return container, dbURI
+}
+
+// useStoreURI points StoreInfo at one side of the backup for the rest of the test.
+func useStoreURI(t *testing.T, dbURI string) {
+	t.Helper()
+	prevDB, prevKeys := config.DBURI, config.DBKeysURI
+	config.DBURI, config.DBKeysURI = dbURI, ""
+	t.Cleanup(func() {
+		config.DBURI, config.DBKeysURI = prevDB, prevKeys
+	})
+}
+
+func TestDeviceBackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	adJID := types.NewADJID("6281234567890", 0, 7)
+	hook := "https://hooks.example.com/sales"
+
+	// Source instance: a paired session with keys and a webhook.
+	srcStore, srcURI := newBackupTestStore(t)
+	device := newTestStoreDevice(srcStore, adJID, "Sales")
+	if err := srcStore.PutDevice(ctx, device); err != nil {
+		t.Fatalf("save device: %v", err)
+	}
+	if err := device.Sessions.PutSession(ctx, "6289876543210.0", []byte("session")); err != nil {
+		t.Fatalf("put session: %v", err)
+	}
+	if err := device.Identities.PutIdentity(ctx, "6289876543210.0", [32]byte{1}); err != nil {
+		t.Fatalf("put identity: %v", err)
+	}
+	preKeys, err := device.PreKeys.GetOrGenPreKeys(ctx, 3)
+	if err != nil {
+		t.Fatalf("gen pre-keys: %v", err)
+	}
+	if err := device.PreKeys.MarkPreKeysAsUploaded(ctx, preKeys[1].KeyID); err != nil {
+		t.Fatalf("mark pre-keys: %v", err)
+	}
+
+	srcStorage := newBackupStubStorage()
+	srcStorage.webhooks["sales"] = &domainChatStorage.DeviceWebhookConfig{WebhookURL: &hook, WebhookSecret: "s3cret"}
+	srcStorage.attrs["sales"] = &domainChatStorage.DeviceAttributes{Tags: []string{"tenant-a"}, Metadata: map[string]string{"team": "sales"}}
+	srcStorage.chatwoot["sales"] = &domainChatStorage.ChatwootDeviceConfig{ID: 4, DeviceID: "sales", ChatwootURL: "https://cw.example.com", APIToken: "tok"}
+
+	src := NewDeviceManager(srcStore, nil, srcStorage)
+	srcInst := NewDeviceInstance("sales", nil, nil)
+	src.applyStoreJID(srcInst, adJID)
+	src.AddDevice(srcInst)
+
+	useStoreURI(t, srcURI)
+	backup, err := src.ExportDeviceBackup(ctx, "sales", "correct horse")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	// Target instance: an empty store on another host.
+	dstStore, dstURI := newBackupTestStore(t)
+	dstStorage := newBackupStubStorage()
+	dst := NewDeviceManager(dstStore, nil, dstStorage)
+	useStoreURI(t, dstURI)
+
+	var validationErr pkgError.ValidationError
+	if _, err := dst.ImportDeviceBackup(ctx, backup, "wrong horse", ""); !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error for a wrong passphrase, got %v", err)
+	}
+
+	inst, err := dst.ImportDeviceBackup(ctx, backup, "correct horse", "")
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if inst.ID() != "sales" || inst.ADJID() != adJID.String() || inst.JID() != adJID.ToNonAD().String() {
+		t.Fatalf("unexpected restored slot id=%s jid=%s ad=%s", inst.ID(), inst.JID(), inst.ADJID())
+	}
+
+	restored, err := dstStore.GetDevice(ctx, adJID)
+	if err != nil || restored == nil {
+		t.Fatalf("restored device missing: %v", err)
+	}
+	if *restored.IdentityKey.Priv != *device.IdentityKey.Priv || restored.PushName != "Sales" {
+		t.Fatal("restored device does not carry the source identity")
+	}
+	if session, err := restored.Sessions.GetSession(ctx, "6289876543210.0"); err != nil || string(session) != "session" {
+		t.Fatalf("restored session = %q, %v", session, err)
+	}
+	uploaded, err := restored.PreKeys.UploadedPreKeyCount(ctx)
+	if err != nil || uploaded != 2 {
+		t.Fatalf("expected 2 uploaded pre-keys, got %d (%v)", uploaded, err)
+	}
+
+	if cfg := dstStorage.webhooks["sales"]; cfg == nil || cfg.WebhookURL == nil || *cfg.WebhookURL != hook || cfg.WebhookSecret != "s3cret" {
+		t.Fatalf("webhook config not restored: %+v", cfg)
+	}
+	if tags := inst.Tags(); len(tags) != 1 || tags[0] != "tenant-a" || inst.Metadata()["team"] != "sales" {
+		t.Fatalf("device attributes not restored: %v %v", tags, inst.Metadata())
+	}
+	if cfg := dstStorage.chatwoot["sales"]; cfg == nil || cfg.ID != 0 || cfg.APIToken != "tok" {
+		t.Fatalf("chatwoot config not restored: %+v", cfg)
+	}
+
+	// The same session cannot be restored twice, under any slot name.
+	var conflict pkgError.ConflictError
+	if _, err := dst.ImportDeviceBackup(ctx, backup, "correct horse", "sales-copy"); !errors.As(err, &conflict) {
+		t.Fatalf("expected a conflict for a duplicate session, got %v", err)
+	}
+}
+
+func TestDeviceBackupRejectsUnpairedDevice(t *testing.T) {
+	dm := NewDeviceManager(nil, nil, nil)
+	dm.AddDevice(NewDeviceInstance("fresh", nil, nil))
+
+	var validationErr pkgError.ValidationError
+	if _, err := dm.ExportDeviceBackup(context.Background(), "fresh", "passphrase"); !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if _, err := dm.ExportDeviceBackup(context.Background(), "missing", "passphrase"); !errors.Is(err, pkgError.ErrDeviceNotFound) {
+		t.Fatalf("expected device not found, got %v", err)
+	}
+}
+
+func TestDeviceBackupRejectsRowsOfAnotherSession(t *testing.T) {
+	ctx := context.Background()
+	adJID := types.NewADJID("6281234567890", 0, 7)
+	victim := types.NewADJID("6281111111111", 0, 3)
+
+	srcStore, srcURI := newBackupTestStore(t)
+	device := newTestStoreDevice(srcStore, adJID, "Sales")
+	if err := srcStore.PutDevice(ctx, device); err != nil {
+		t.Fatalf("save device: %v", err)
+	}
+	if err := device.Sessions.PutSession(ctx, "6289876543210.0", []byte("session")); err != nil {
+		t.Fatalf("put session: %v", err)
+	}
+	src := NewDeviceManager(srcStore, nil, newBackupStubStorage())
+	srcInst := NewDeviceInstance("sales", nil, nil)
+	src.applyStoreJID(srcInst, adJID)
+	src.AddDevice(srcInst)
+
+	useStoreURI(t, srcURI)
+	sealed, err := src.ExportDeviceBackup(ctx, "sales", "correct horse")
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	// Rewrite the session row so it claims to belong to another device.
+	plaintext, err := openDeviceBackup(sealed, "correct horse")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	var backup deviceBackup
+	decoder := json.NewDecoder(bytes.NewReader(plaintext))
+	decoder.UseNumber()
+	if err := decoder.Decode(&backup); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for i, table := range backup.Store {
+		if table.Name == "whatsmeow_sessions" {
+			backup.Store[i].Rows[0][0] = victim.String()
+		}
+	}
+	plaintext, err = json.Marshal(backup)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	crafted, err := sealDeviceBackup(plaintext, "correct horse")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+
+	dstStore, dstURI := newBackupTestStore(t)
+	dst := NewDeviceManager(dstStore, nil, newBackupStubStorage())
+	useStoreURI(t, dstURI)
+
+	var validationErr pkgError.ValidationError
+	if _, err := dst.ImportDeviceBackup(ctx, crafted, "correct horse", ""); !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if restored, err := dstStore.GetDevice(ctx, adJID); err != nil || restored != nil {
+		t.Fatalf("expected nothing to be restored, got %v (%v)", restored, err)
+	}
+	if _, ok := dst.GetDevice("sales"); ok {
+		t.Fatal("expected no slot for a rejected backup")
+	}
+}
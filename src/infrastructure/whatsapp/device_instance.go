@@ -1,6 +1,8 @@
 package whatsapp
 
 import (
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -28,6 +30,34 @@ type DeviceInstance struct {
 	passkeyChallenge     *types.WebAuthnPublicKey
 	passkeyCode          string
 	passkeySkipHandoffUX bool
+
+	// Connection health, updated from connection events (see handler) and the
+	// client's auto-reconnect hook.
+	connStats ConnectionStats
+
+	// Outbound proxies in failover order (see proxyCandidates); proxyIndex is
+	// the one the client currently dials through and proxyFailures counts
+	// failed reconnects on it.
+	proxies       []string
+	proxyIndex    int
+	proxyFailures int
+
+	// Operator-assigned tags and metadata, mirrored from the device registry
+	// so listing and webhook enrichment never hit storage.
+	tags     []string
+	metadata map[string]string
+}
+
+// ConnectionStats is a snapshot of a device's connection history since the
+// process started. Zero times mean the event has not happened yet.
+type ConnectionStats struct {
+	LastConnectedAt    time.Time
+	LastDisconnectedAt time.Time
+	// ReconnectAttempts counts automatic reconnect attempts since the last
+	// successful connection; it resets to zero on connect.
+	ReconnectAttempts int
+	LastError         string
+	LastErrorAt       time.Time
 }
 
 func NewDeviceInstance(deviceID string, client *whatsmeow.Client, chatStorageRepo domainChatStorage.IChatStorageRepository) *DeviceInstance {
@@ -110,6 +140,33 @@ func (d *DeviceInstance) CreatedAt() time.Time {
 	return d.createdAt
 }
 
+// Tags returns a copy of the device's operator-assigned tags.
+func (d *DeviceInstance) Tags() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return slices.Clone(d.tags)
+}
+
+// Metadata returns a copy of the device's operator-assigned metadata.
+func (d *DeviceInstance) Metadata() map[string]string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return maps.Clone(d.metadata)
+}
+
+// SetAttributes replaces the in-memory tags and metadata. Callers persist them
+// through the chat storage first.
+func (d *DeviceInstance) SetAttributes(attributes *domainChatStorage.DeviceAttributes) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if attributes == nil {
+		d.tags, d.metadata = nil, nil
+		return
+	}
+	d.tags = slices.Clone(attributes.Tags)
+	d.metadata = maps.Clone(attributes.Metadata)
+}
+
 // SetClient attaches a WhatsApp client to this instance and updates metadata.
 func (d *DeviceInstance) SetClient(client *whatsmeow.Client) {
 	d.mu.Lock()
@@ -238,3 +295,137 @@ func (d *DeviceInstance) TriggerLoggedOut() {
 		callback(deviceID)
 	}
 }
+
+// RecordConnected marks a successful (re)connection and resets the reconnect counter.
+func (d *DeviceInstance) RecordConnected() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connStats.LastConnectedAt = time.Now()
+	d.connStats.ReconnectAttempts = 0
+	d.proxyFailures = 0
+}
+
+// RecordDisconnected marks the websocket as lost. A non-empty reason (permanent
+// disconnects such as bans or connect failures) also becomes the last error.
+func (d *DeviceInstance) RecordDisconnected(reason string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := time.Now()
+	d.connStats.LastDisconnectedAt = now
+	if reason != "" {
+		d.connStats.LastError = reason
+		d.connStats.LastErrorAt = now
+	}
+}
+
+// RecordReconnectAttempt counts an automatic reconnect attempt. err is the
+// failure of the previous attempt, if any.
+func (d *DeviceInstance) RecordReconnectAttempt(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connStats.ReconnectAttempts++
+	if err != nil {
+		d.connStats.LastError = err.Error()
+		d.connStats.LastErrorAt = time.Now()
+	}
+}
+
+// RecordConnectionError stores a connection problem that did not (yet) drop
+// the websocket, e.g. keepalive timeouts or unknown stream errors.
+func (d *DeviceInstance) RecordConnectionError(reason string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.connStats.LastError = reason
+	d.connStats.LastErrorAt = time.Now()
+}
+
+// RecordConnectFailure stores a failed manual connect and counts it towards
+// failing over to the next pooled proxy, like a failed automatic reconnect.
+func (d *DeviceInstance) RecordConnectFailure(err error) {
+	if err == nil {
+		return
+	}
+	d.RecordConnectionError(err.Error())
+	if client := d.GetClient(); client != nil {
+		failoverProxy(client, d)
+	}
+}
+
+// ConnectionStats returns a copy of the tracked connection history.
+func (d *DeviceInstance) ConnectionStats() ConnectionStats {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.connStats
+}
+
+// ActiveProxy returns the redacted proxy the client currently connects
+// through, or "" for a direct connection.
+func (d *DeviceInstance) ActiveProxy() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if len(d.proxies) == 0 {
+		return ""
+	}
+	return RedactProxyURL(d.proxies[d.proxyIndex])
+}
+
+// setProxyCandidates replaces the proxy list and makes its first entry active.
+// It returns that entry ("" when the list is empty) and whether the previous
+// list had any proxy.
+func (d *DeviceInstance) setProxyCandidates(proxies []string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hadProxy := len(d.proxies) > 0
+	d.proxies = proxies
+	d.proxyIndex = 0
+	d.proxyFailures = 0
+	if len(proxies) == 0 {
+		return "", hadProxy
+	}
+	return proxies[0], hadProxy
+}
+
+// recordProxyFailure counts a failed connection attempt on the active proxy.
+// Once threshold is reached on a device with more than one proxy it resets the
+// count and returns the other proxies in failover order.
+func (d *DeviceInstance) recordProxyFailure(threshold int) ([]string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.proxies) < 2 {
+		return nil, false
+	}
+	d.proxyFailures++
+	if d.proxyFailures < threshold {
+		return nil, false
+	}
+	d.proxyFailures = 0
+	next := make([]string, 0, len(d.proxies)-1)
+	for i := 1; i < len(d.proxies); i++ {
+		next = append(next, d.proxies[(d.proxyIndex+i)%len(d.proxies)])
+	}
+	return next, true
+}
+
+// setActiveProxy marks proxy as the one the client now connects through.
+func (d *DeviceInstance) setActiveProxy(proxy string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, candidate := range d.proxies {
+		if candidate == proxy {
+			d.proxyIndex = i
+			return
+		}
+	}
+}
+
+// installReconnectHook counts whatsmeow's automatic reconnect attempts on the
+// instance and fails over to the next pooled proxy when the active one keeps
+// failing. whatsmeow calls the hook after each failed attempt and keeps
+// retrying as long as it returns true.
+func installReconnectHook(client *whatsmeow.Client, instance *DeviceInstance) {
+	client.AutoReconnectHook = func(err error) bool {
+		instance.RecordReconnectAttempt(err)
+		failoverProxy(client, instance)
+		return true
+	}
+}
@@ -2,6 +2,7 @@ package whatsapp
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"slices"
@@ -142,6 +143,31 @@ func (m *DeviceManager) IsHealthy() bool {
 	return m.store != nil
 }
 
+// PingStore verifies that the whatsmeow store (and the separate keys store,
+// when configured) answers queries. Unlike IsHealthy it touches the database,
+// so it backs the readiness probe.
+func (m *DeviceManager) PingStore(ctx context.Context) error {
+	if m == nil {
+		return fmt.Errorf("device manager not initialized")
+	}
+	m.mu.RLock()
+	primary, keys := m.store, m.keys
+	m.mu.RUnlock()
+
+	if primary == nil {
+		return fmt.Errorf("whatsapp store not initialized")
+	}
+	if _, err := primary.GetAllDevices(ctx); err != nil {
+		return fmt.Errorf("whatsapp store: %w", err)
+	}
+	if keys != nil && keys != primary {
+		if _, err := keys.GetAllDevices(ctx); err != nil {
+			return fmt.Errorf("whatsapp keys store: %w", err)
+		}
+	}
+	return nil
+}
+
 // DefaultDevice returns the only registered device when running in single-device mode.
 func (m *DeviceManager) DefaultDevice() *DeviceInstance {
 	if m == nil {
@@ -579,6 +605,79 @@ func (m *DeviceManager) LoadExistingDevices(ctx context.Context) error {
 	return nil
 }
 
+// storeSessions maps every paired session in the whatsmeow store (keyed by AD JID)
+// to its slot. Sessions paired by another replica after boot get a slot here the
+// same way LoadExistingDevices adopts unknown rows, named after slotIDs (the slot
+// the session ran under on its last owner) so callers keep using the same
+// device id after a failover; rows whose number is already claimed by a
+// different companion are skipped for the same reason as there.
+func (m *DeviceManager) storeSessions(ctx context.Context, slotIDs map[string]string) (map[string]*DeviceInstance, error) {
+	if m == nil || m.store == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+
+	devices, err := m.store.GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make(map[string]*DeviceInstance, len(devices))
+	for _, dev := range devices {
+		if dev == nil || dev.ID == nil {
+			continue
+		}
+		adJID := dev.ID.String()
+		nonAD := dev.ID.ToNonAD().String()
+
+		m.mu.RLock()
+		var match *DeviceInstance
+		numberClaimed := false
+		for _, inst := range m.devices {
+			if sessionKey(inst) == adJID {
+				match = inst
+				break
+			}
+			if inst.JID() == nonAD {
+				numberClaimed = true
+			}
+		}
+		m.mu.RUnlock()
+
+		if match == nil {
+			if numberClaimed {
+				continue
+			}
+			slotID := nonAD
+			if id := slotIDs[adJID]; id != "" {
+				if _, taken := m.GetDevice(id); !taken {
+					slotID = id
+				}
+			}
+			logrus.Infof("[DEVICE_MANAGER] adopting companion session %s paired on another replica as %s", adJID, slotID)
+			match = NewDeviceInstance(slotID, nil, newDeviceChatStorage(nonAD, m.storage))
+			match.SetState(domainDevice.DeviceStateDisconnected)
+			m.applyStoreJID(match, *dev.ID)
+			m.AddDevice(match)
+		}
+		sessions[adJID] = match
+	}
+
+	return sessions, nil
+}
+
+// sessionKey returns the AD JID of the session a slot runs, preferring the live
+// client's store identity over the recorded one (fresh pairings update the
+// record only on the next state refresh).
+func sessionKey(inst *DeviceInstance) string {
+	if inst == nil {
+		return ""
+	}
+	if client := inst.GetClient(); client != nil && client.Store != nil && client.Store.ID != nil {
+		return client.Store.ID.String()
+	}
+	return inst.ADJID()
+}
+
 // applyStoreJID stamps a store row's identity onto a slot: the bare number as the
 // chat-storage partition key and the full AD JID as the companion identity.
 func (m *DeviceManager) applyStoreJID(instance *DeviceInstance, storeJID types.JID) {
@@ -681,6 +780,7 @@ func (m *DeviceManager) loadFromRegistry(records []*domainChatStorage.DeviceReco
 		instance.displayName = rec.DisplayName
 		instance.jid = rec.JID
 		instance.adJID = rec.ADJID
+		instance.SetAttributes(recordAttributes(rec))
 
 		// If we had an existing device with client, transfer the client
 		if existingByJID != nil {
@@ -750,13 +850,7 @@ func (m *DeviceManager) EnsureClient(ctx context.Context, deviceID string) (*Dev
 
 	baseLogger := waLog.Stdout(fmt.Sprintf("Client-%s", deviceID), config.WhatsappLogLevel, true)
 	client := whatsmeow.NewClient(storeDevice, newFilteredLogger(baseLogger))
-	if proxyURL := config.WhatsappProxy; proxyURL != "" {
-		if err := client.SetProxyAddress(proxyURL); err != nil {
-			baseLogger.Errorf("failed to apply WHATSAPP_PROXY=%q for device %s: %v", redactProxyURL(proxyURL), deviceID, err)
-		} else {
-			baseLogger.Infof("applied outbound proxy from WHATSAPP_PROXY for device %s", deviceID)
-		}
-	}
+	applyProxy(client, inst, m.deviceProxyConfig(inst.ID()), baseLogger)
 	client.EnableAutoReconnect = true
 	client.AutoTrustIdentity = true
 
@@ -769,6 +863,7 @@ func (m *DeviceManager) EnsureClient(ctx context.Context, deviceID string) (*Dev
 	client.AddEventHandler(func(rawEvt any) {
 		handler(ctx, inst, rawEvt)
 	})
+	installReconnectHook(client, inst)
 
 	inst.SetOnLoggedOut(func(deviceID string) {
 		// On remote logout (device unlinked from the phone) keep the slot so it can
@@ -785,6 +880,65 @@ func (m *DeviceManager) EnsureClient(ctx context.Context, deviceID string) (*Dev
 	return inst, nil
 }
 
+// deviceProxyConfig returns the stored proxy settings of a device, or nil when
+// it has none or they cannot be read.
+func (m *DeviceManager) deviceProxyConfig(deviceID string) *domainChatStorage.DeviceProxyConfig {
+	if m.storage == nil {
+		return nil
+	}
+	cfg, err := m.storage.GetDeviceProxyConfig(deviceID)
+	if err != nil {
+		logrus.WithError(err).Warnf("failed to load proxy config for device %s", deviceID)
+		return nil
+	}
+	return cfg
+}
+
+// recordAttributes decodes the tags and metadata stored on a registry record.
+// Undecodable values are logged and dropped rather than failing the boot.
+func recordAttributes(rec *domainChatStorage.DeviceRecord) *domainChatStorage.DeviceAttributes {
+	attributes := &domainChatStorage.DeviceAttributes{}
+	if rec.Tags != "" {
+		if err := json.Unmarshal([]byte(rec.Tags), &attributes.Tags); err != nil {
+			logrus.WithError(err).Warnf("[DEVICE_MANAGER] ignoring unreadable tags of device %s", rec.DeviceID)
+		}
+	}
+	if rec.Metadata != "" {
+		if err := json.Unmarshal([]byte(rec.Metadata), &attributes.Metadata); err != nil {
+			logrus.WithError(err).Warnf("[DEVICE_MANAGER] ignoring unreadable metadata of device %s", rec.DeviceID)
+		}
+	}
+	return attributes
+}
+
+// ApplyDeviceProxy re-reads a device's proxy settings and applies them to its
+// client. A connected client is reconnected so the new proxy takes effect.
+func (m *DeviceManager) ApplyDeviceProxy(deviceID string) error {
+	if m == nil {
+		return fmt.Errorf("device manager not initialized")
+	}
+	inst, ok := m.GetDevice(deviceID)
+	if !ok {
+		return fmt.Errorf("device %s not found", deviceID)
+	}
+	client := inst.GetClient()
+	if client == nil {
+		// Applied by EnsureClient when the client is created.
+		return nil
+	}
+
+	applyProxy(client, inst, m.deviceProxyConfig(inst.ID()), waLog.Stdout(fmt.Sprintf("Client-%s", deviceID), config.WhatsappLogLevel, true))
+	if !client.IsConnected() || client.Store == nil || client.Store.ID == nil {
+		return nil
+	}
+	client.Disconnect()
+	if err := client.Connect(); err != nil {
+		inst.RecordConnectFailure(err)
+		return fmt.Errorf("failed to reconnect device %s through new proxy: %w", deviceID, err)
+	}
+	return nil
+}
+
 func (m *DeviceManager) ensureInstance(deviceID string) *DeviceInstance {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -3,22 +3,38 @@ package whatsapp
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// callRejectMessageCooldown is the minimum time between two automatic
+// rejection messages to the same caller, so a caller redialing in a loop gets
+// one reply instead of one per call.
+const callRejectMessageCooldown = 10 * time.Minute
+
+var (
+	callRejectRepliesMu   sync.Mutex
+	callRejectRepliesLast = make(map[string]time.Time)
 )
 
 // handleCallOffer handles incoming call events and optionally auto-rejects them
 func handleCallOffer(ctx context.Context, evt *events.CallOffer, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	logrus.Infof("Incoming call from %s (CallID: %s)", evt.CallCreator.String(), evt.CallID)
 
-	// Auto-reject call if configured
+	// Auto-reject call if configured, per device first and globally otherwise
+	autoReject, _ := callHandling(deviceID)
 	autoRejected := false
-	if config.WhatsappAutoRejectCall {
+	if autoReject {
 		rejectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel()
 
@@ -31,6 +47,8 @@ func handleCallOffer(ctx context.Context, evt *events.CallOffer, chatStorageRepo
 	}
 
 	if chatStorageRepo != nil {
+		// The chat history keeps its "Incoming call" row; the call record
+		// below tracks the call's lifecycle for GET /calls.
 		if err := chatStorageRepo.CreateIncomingCallRecord(ctx, evt, autoRejected); err != nil {
 			switch {
 			case errors.Is(err, domainChatStorage.ErrMissingDeviceContext),
@@ -40,6 +58,25 @@ func handleCallOffer(ctx context.Context, evt *events.CallOffer, chatStorageRepo
 				logrus.Errorf("Failed to persist incoming call: %v", err)
 			}
 		}
+
+		record := newCallRecord(ctx, evt.BasicCallMeta, deviceID, client, offerIsVideo(evt.Data))
+		record.RemotePlatform = evt.RemotePlatform
+		record.RemoteVersion = evt.RemoteVersion
+		if autoRejected {
+			record.AutoRejected = true
+			applyCallTransition(record, domainChatStorage.CallStatusRejected, evt.Timestamp, "")
+		}
+		if err := chatStorageRepo.SaveCallRecord(record); err != nil {
+			logrus.Errorf("Failed to store call record %s: %v", evt.CallID, err)
+		}
+	}
+
+	if autoRejected && evt.GroupJID.IsEmpty() && allowCallRejectMessage(deviceID, evt.CallCreator, time.Now()) {
+		go func(caller types.JID) {
+			replyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+			defer cancel()
+			SendCallRejectMessage(replyCtx, client, chatStorageRepo, deviceID, caller)
+		}(evt.CallCreator)
 	}
 
 	// Forward call event to webhook
@@ -52,6 +89,208 @@ func handleCallOffer(ctx context.Context, evt *events.CallOffer, chatStorageRepo
 	}(evt, client, autoRejected)
 }
 
+// handleCallOfferNotice records group call offers, which arrive as a notice
+// rather than a regular offer.
+func handleCallOfferNotice(ctx context.Context, evt *events.CallOfferNotice, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	logrus.Infof("Incoming %s %s call from %s (CallID: %s)", evt.Type, evt.Media, evt.CallCreator.String(), evt.CallID)
+	if chatStorageRepo == nil {
+		return
+	}
+
+	record := newCallRecord(ctx, evt.BasicCallMeta, deviceID, client, evt.Media == "video")
+	if err := chatStorageRepo.SaveCallRecord(record); err != nil {
+		logrus.Errorf("Failed to store call record %s: %v", evt.CallID, err)
+	}
+}
+
+// handleCallAccept marks a ringing call as answered.
+func handleCallAccept(evt *events.CallAccept, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string) {
+	updateCallRecord(chatStorageRepo, deviceID, evt.CallID, domainChatStorage.CallStatusAccepted, evt.Timestamp, "")
+}
+
+// handleCallReject marks a call as rejected by the other party.
+func handleCallReject(evt *events.CallReject, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string) {
+	updateCallRecord(chatStorageRepo, deviceID, evt.CallID, domainChatStorage.CallStatusRejected, evt.Timestamp, "")
+}
+
+// handleCallTerminate closes the call record and forwards a call.ended event.
+func handleCallTerminate(ctx context.Context, evt *events.CallTerminate, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	logrus.Infof("Call %s terminated (reason: %s)", evt.CallID, evt.Reason)
+
+	record := updateCallRecord(chatStorageRepo, deviceID, evt.CallID, domainChatStorage.CallStatusEnded, evt.Timestamp, evt.Reason)
+
+	go func(e *events.CallTerminate, c *whatsmeow.Client) {
+		webhookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		payload := createCallEndedPayload(webhookCtx, e, record, deviceID, c)
+		if err := forwardPayloadToConfiguredWebhooks(webhookCtx, payload, "call.ended"); err != nil {
+			logrus.Errorf("Failed to forward call ended event to webhook: %v", err)
+		}
+	}(evt, client)
+}
+
+// MarkCallRejected records that the call was rejected through the API.
+func MarkCallRejected(chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID, callID string, at time.Time) {
+	updateCallRecord(chatStorageRepo, deviceID, callID, domainChatStorage.CallStatusRejected, at, "")
+}
+
+// SendCallRejectMessage sends the device's rejection message to a caller
+// whose call was declined. Nothing is sent when no message is configured.
+func SendCallRejectMessage(ctx context.Context, client *whatsmeow.Client, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, caller types.JID) {
+	_, message := callHandling(deviceID)
+	if message == "" || client == nil {
+		return
+	}
+
+	recipient := NormalizeJIDFromLID(ctx, caller, client).ToNonAD()
+	response, err := client.SendMessage(ctx, recipient, &waE2E.Message{Conversation: proto.String(message)})
+	if err != nil {
+		logrus.Errorf("Failed to send call rejection message to %s: %v", recipient.String(), err)
+		return
+	}
+
+	if chatStorageRepo != nil {
+		senderJID := ""
+		if client.Store != nil && client.Store.ID != nil {
+			senderJID = client.Store.ID.String()
+		}
+		if err := chatStorageRepo.StoreSentMessageWithContext(ctx, response.ID, senderJID, recipient.String(), message, response.Timestamp, nil); err != nil {
+			logrus.Errorf("Failed to store call rejection message in chat storage: %v", err)
+		}
+	}
+}
+
+// allowCallRejectMessage reports whether the cooldown of this device and
+// caller has passed, and starts a new one if so. Expired entries are dropped
+// on the way so the map only holds callers seen within the cooldown.
+func allowCallRejectMessage(deviceID string, caller types.JID, now time.Time) bool {
+	key := deviceID + "|" + caller.ToNonAD().String()
+	callRejectRepliesMu.Lock()
+	defer callRejectRepliesMu.Unlock()
+	if last, ok := callRejectRepliesLast[key]; ok && now.Sub(last) < callRejectMessageCooldown {
+		return false
+	}
+	for k, last := range callRejectRepliesLast {
+		if now.Sub(last) >= callRejectMessageCooldown {
+			delete(callRejectRepliesLast, k)
+		}
+	}
+	callRejectRepliesLast[key] = now
+	return true
+}
+
+// callHandling resolves whether the device auto-rejects calls and what it
+// replies to rejected callers. Device settings win; unset ones fall back to
+// the global configuration.
+func callHandling(deviceJID string) (autoReject bool, rejectMessage string) {
+	autoReject = config.WhatsappAutoRejectCall
+	rejectMessage = config.WhatsappAutoRejectCallMessage
+	if deviceJID == "" {
+		return autoReject, rejectMessage
+	}
+
+	record, err := getDeviceRecordForTest(deviceJID)
+	if err != nil {
+		logrus.Warnf("Failed to load call settings for device %s, using global settings: %v", deviceJID, err)
+		return autoReject, rejectMessage
+	}
+	if record == nil {
+		return autoReject, rejectMessage
+	}
+	if record.CallAutoReject != nil {
+		autoReject = *record.CallAutoReject
+	}
+	if record.CallRejectMessage != "" {
+		rejectMessage = record.CallRejectMessage
+	}
+	return autoReject, rejectMessage
+}
+
+// newCallRecord builds a ringing call record from the call metadata. Group
+// calls are filed under the group, one-to-one calls under the caller.
+func newCallRecord(ctx context.Context, meta types.BasicCallMeta, deviceID string, client *whatsmeow.Client, isVideo bool) *domainChatStorage.CallRecord {
+	caller := NormalizeJIDFromLID(ctx, meta.CallCreator, client).ToNonAD().String()
+	record := &domainChatStorage.CallRecord{
+		DeviceID:  deviceID,
+		CallID:    meta.CallID,
+		ChatJID:   caller,
+		CallerJID: caller,
+		IsVideo:   isVideo,
+		Status:    domainChatStorage.CallStatusRinging,
+		OfferedAt: meta.Timestamp,
+	}
+	if !meta.GroupJID.IsEmpty() {
+		record.GroupJID = meta.GroupJID.ToNonAD().String()
+		record.ChatJID = record.GroupJID
+	}
+	return record
+}
+
+// updateCallRecord applies a lifecycle transition to a stored call. Calls
+// that were never offered to this device are not tracked and return nil.
+func updateCallRecord(chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID, callID, status string, at time.Time, reason string) *domainChatStorage.CallRecord {
+	if chatStorageRepo == nil || callID == "" {
+		return nil
+	}
+
+	record, err := chatStorageRepo.GetCallRecord(deviceID, callID)
+	if err != nil {
+		logrus.Errorf("Failed to load call record %s: %v", callID, err)
+		return nil
+	}
+	if record == nil {
+		logrus.Debugf("Ignoring %s for untracked call %s", status, callID)
+		return nil
+	}
+
+	applyCallTransition(record, status, at, reason)
+	if err := chatStorageRepo.SaveCallRecord(record); err != nil {
+		logrus.Errorf("Failed to update call record %s: %v", callID, err)
+	}
+	return record
+}
+
+// applyCallTransition moves a call record to the next lifecycle state.
+// Terminating an answered call ends it with a duration, terminating a call
+// that was still ringing marks it missed, and a rejected call stays rejected.
+func applyCallTransition(record *domainChatStorage.CallRecord, status string, at time.Time, reason string) {
+	switch status {
+	case domainChatStorage.CallStatusAccepted:
+		if record.Status == domainChatStorage.CallStatusRinging {
+			record.Status = domainChatStorage.CallStatusAccepted
+			record.AcceptedAt = &at
+		}
+	case domainChatStorage.CallStatusRejected:
+		if record.Status == domainChatStorage.CallStatusRinging {
+			record.Status = domainChatStorage.CallStatusRejected
+			record.EndedAt = &at
+		}
+	case domainChatStorage.CallStatusEnded:
+		record.EndReason = reason
+		if record.EndedAt == nil {
+			record.EndedAt = &at
+		}
+		switch record.Status {
+		case domainChatStorage.CallStatusAccepted:
+			record.Status = domainChatStorage.CallStatusEnded
+			if record.AcceptedAt != nil && at.After(*record.AcceptedAt) {
+				record.DurationSeconds = int(at.Sub(*record.AcceptedAt).Seconds())
+			}
+		case domainChatStorage.CallStatusRinging:
+			record.Status = domainChatStorage.CallStatusMissed
+		}
+	}
+}
+
+// offerIsVideo reports whether a call offer negotiates a video stream.
+func offerIsVideo(data *waBinary.Node) bool {
+	if data == nil {
+		return false
+	}
+	_, ok := data.GetOptionalChildByTag("video")
+	return ok
+}
+
 // createCallOfferPayload creates a webhook payload for incoming call events
 func createCallOfferPayload(ctx context.Context, evt *events.CallOffer, deviceID string, client *whatsmeow.Client, autoRejected bool) map[string]any {
 	body := make(map[string]any)
@@ -92,6 +331,47 @@ func createCallOfferPayload(ctx context.Context, evt *events.CallOffer, deviceID
 	return body
 }
 
+// createCallEndedPayload creates a webhook payload for a terminated call. The
+// stored record, when there is one, supplies the lifecycle details.
+func createCallEndedPayload(ctx context.Context, evt *events.CallTerminate, record *domainChatStorage.CallRecord, deviceID string, client *whatsmeow.Client) map[string]any {
+	body := make(map[string]any)
+	payload := make(map[string]any)
+
+	payload["call_id"] = evt.CallID
+	senderJID := evt.CallCreator
+	if senderJID.Server == "lid" {
+		payload["from_lid"] = senderJID.ToNonAD().String()
+	}
+	payload["from"] = NormalizeJIDFromLID(ctx, senderJID, client).ToNonAD().String()
+	if !evt.GroupJID.IsEmpty() {
+		payload["group_jid"] = evt.GroupJID.ToNonAD().String()
+	}
+	if evt.Reason != "" {
+		payload["end_reason"] = evt.Reason
+	}
+
+	payload["status"] = domainChatStorage.CallStatusEnded
+	if record != nil {
+		payload["status"] = record.Status
+		payload["is_video"] = record.IsVideo
+		payload["auto_rejected"] = record.AutoRejected
+		payload["duration_seconds"] = record.DurationSeconds
+		payload["offered_at"] = record.OfferedAt.Format(time.RFC3339)
+		if record.AcceptedAt != nil {
+			payload["accepted_at"] = record.AcceptedAt.Format(time.RFC3339)
+		}
+	}
+
+	body["event"] = "call.ended"
+	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
+	if deviceID != "" {
+		body["device_id"] = deviceID
+	}
+	body["payload"] = payload
+
+	return body
+}
+
 // forwardCallOfferToWebhook forwards incoming call events to the configured webhook URLs
 func forwardCallOfferToWebhook(ctx context.Context, evt *events.CallOffer, deviceID string, client *whatsmeow.Client, autoRejected bool) error {
 	payload := createCallOfferPayload(ctx, evt, deviceID, client, autoRejected)
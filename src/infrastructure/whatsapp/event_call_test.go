@@ -0,0 +1,127 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestApplyCallTransition(t *testing.T) {
+	offered := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	accepted := offered.Add(5 * time.Second)
+	ended := accepted.Add(95 * time.Second)
+
+	t.Run("answered call ends with a duration", func(t *testing.T) {
+		record := &domainChatStorage.CallRecord{Status: domainChatStorage.CallStatusRinging, OfferedAt: offered}
+		applyCallTransition(record, domainChatStorage.CallStatusAccepted, accepted, "")
+		applyCallTransition(record, domainChatStorage.CallStatusEnded, ended, "timeout")
+
+		if record.Status != domainChatStorage.CallStatusEnded || record.DurationSeconds != 95 || record.EndReason != "timeout" {
+			t.Fatalf("unexpected record %+v", record)
+		}
+		if record.AcceptedAt == nil || !record.AcceptedAt.Equal(accepted) || record.EndedAt == nil || !record.EndedAt.Equal(ended) {
+			t.Fatalf("unexpected timestamps accepted=%v ended=%v", record.AcceptedAt, record.EndedAt)
+		}
+	})
+
+	t.Run("unanswered call is missed", func(t *testing.T) {
+		record := &domainChatStorage.CallRecord{Status: domainChatStorage.CallStatusRinging, OfferedAt: offered}
+		applyCallTransition(record, domainChatStorage.CallStatusEnded, ended, "")
+
+		if record.Status != domainChatStorage.CallStatusMissed || record.DurationSeconds != 0 {
+			t.Fatalf("unexpected record %+v", record)
+		}
+	})
+
+	t.Run("rejected call stays rejected", func(t *testing.T) {
+		record := &domainChatStorage.CallRecord{Status: domainChatStorage.CallStatusRinging, OfferedAt: offered}
+		applyCallTransition(record, domainChatStorage.CallStatusRejected, accepted, "")
+		applyCallTransition(record, domainChatStorage.CallStatusAccepted, accepted, "")
+		applyCallTransition(record, domainChatStorage.CallStatusEnded, ended, "")
+
+		if record.Status != domainChatStorage.CallStatusRejected || record.AcceptedAt != nil {
+			t.Fatalf("unexpected record %+v", record)
+		}
+		if record.EndedAt == nil || !record.EndedAt.Equal(accepted) {
+			t.Fatalf("ended_at = %v, want the rejection time", record.EndedAt)
+		}
+	})
+}
+
+func TestCallHandlingPrefersDeviceSettings(t *testing.T) {
+	originalReject, originalMessage := config.WhatsappAutoRejectCall, config.WhatsappAutoRejectCallMessage
+	config.WhatsappAutoRejectCall = true
+	config.WhatsappAutoRejectCallMessage = "global message"
+	defer func() {
+		config.WhatsappAutoRejectCall, config.WhatsappAutoRejectCallMessage = originalReject, originalMessage
+	}()
+
+	var record *domainChatStorage.DeviceRecord
+	originalStorageForTest := webhookStorageForTest
+	webhookStorageForTest = func(string) (*domainChatStorage.DeviceRecord, error) { return record, nil }
+	defer func() { webhookStorageForTest = originalStorageForTest }()
+
+	autoReject, message := callHandling("628111@s.whatsapp.net")
+	if !autoReject || message != "global message" {
+		t.Fatalf("unknown device: got (%v, %q), want the global settings", autoReject, message)
+	}
+
+	disabled := false
+	record = &domainChatStorage.DeviceRecord{CallAutoReject: &disabled}
+	autoReject, message = callHandling("628111@s.whatsapp.net")
+	if autoReject || message != "global message" {
+		t.Fatalf("device opt-out: got (%v, %q)", autoReject, message)
+	}
+
+	record = &domainChatStorage.DeviceRecord{CallRejectMessage: "device message"}
+	autoReject, message = callHandling("628111@s.whatsapp.net")
+	if !autoReject || message != "device message" {
+		t.Fatalf("device message: got (%v, %q)", autoReject, message)
+	}
+}
+
+func TestOfferIsVideo(t *testing.T) {
+	audio := &waBinary.Node{Tag: "offer", Content: []waBinary.Node{{Tag: "audio"}}}
+	video := &waBinary.Node{Tag: "offer", Content: []waBinary.Node{{Tag: "audio"}, {Tag: "video"}}}
+
+	if offerIsVideo(nil) || offerIsVideo(audio) {
+		t.Fatal("audio offers must not be reported as video")
+	}
+	if !offerIsVideo(video) {
+		t.Fatal("offer with a video child must be reported as video")
+	}
+}
+
+func TestAllowCallRejectMessageThrottlesPerCaller(t *testing.T) {
+	t.Cleanup(func() {
+		callRejectRepliesMu.Lock()
+		callRejectRepliesLast = make(map[string]time.Time)
+		callRejectRepliesMu.Unlock()
+	})
+
+	now := time.Date(2025, 7, 13, 11, 6, 2, 0, time.UTC)
+	caller := types.NewJID("628111", types.DefaultUserServer)
+	other := types.NewJID("628222", types.DefaultUserServer)
+
+	if !allowCallRejectMessage("dev-1", caller, now) {
+		t.Fatal("first rejected call must be answered")
+	}
+	if allowCallRejectMessage("dev-1", caller, now.Add(time.Minute)) {
+		t.Fatal("a redial within the cooldown must not be answered again")
+	}
+	if !allowCallRejectMessage("dev-1", other, now.Add(time.Minute)) || !allowCallRejectMessage("dev-2", caller, now.Add(time.Minute)) {
+		t.Fatal("other callers and devices have their own cooldown")
+	}
+	if !allowCallRejectMessage("dev-1", caller, now.Add(time.Minute+callRejectMessageCooldown)) {
+		t.Fatal("the caller must be answered again after the cooldown")
+	}
+	callRejectRepliesMu.Lock()
+	defer callRejectRepliesMu.Unlock()
+	if len(callRejectRepliesLast) != 1 {
+		t.Fatalf("expired entries must be dropped, got %d", len(callRejectRepliesLast))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
@@ -13,7 +14,7 @@ import (
 // handleChatPresence handles incoming chat presence (typing notification) events.
 // These events are emitted when a user starts or stops typing in a chat.
 // Note: WhatsApp only sends these updates when the client is marked as online.
-func handleChatPresence(ctx context.Context, evt *events.ChatPresence, deviceID string, client *whatsmeow.Client) {
+func handleChatPresence(ctx context.Context, evt *events.ChatPresence, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	if evt.State == types.ChatPresenceComposing {
 		if evt.Media == types.ChatPresenceMediaAudio {
 			log.Infof("%s is recording audio in %s", evt.Sender.ToNonAD(), evt.Chat.ToNonAD())
@@ -24,6 +25,9 @@ func handleChatPresence(ctx context.Context, evt *events.ChatPresence, deviceID
 		log.Infof("%s stopped typing in %s", evt.Sender.ToNonAD(), evt.Chat.ToNonAD())
 	}
 
+	recordChatPresence(ctx, evt, chatStorageRepo, deviceID, client)
+	go relayChatPresenceToChatwoot(context.WithoutCancel(ctx), evt, deviceID, client)
+
 	// Forward chat presence event to webhook
 	go func(e *events.ChatPresence, c *whatsmeow.Client) {
 		webhookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
@@ -0,0 +1,257 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	// contactInteractionInterval is how often a direct chat's last interaction
+	// is written when nothing else about the contact changed.
+	contactInteractionInterval = 5 * time.Minute
+	// seenContactLimit bounds the cache of contacts written from messages.
+	seenContactLimit = 10000
+)
+
+// seenContact is what was last written for a message sender.
+type seenContact struct {
+	pushName      string
+	businessName  string
+	lid           string
+	interactionAt time.Time
+}
+
+// seenContactCache remembers what recordMessageContact last wrote per device
+// and sender, so a busy chat does not upsert the same contact (and look up its
+// LID) on every message.
+type seenContactCache struct {
+	mu      sync.Mutex
+	entries map[string]seenContact
+}
+
+var seenContacts = newSeenContactCache()
+
+func newSeenContactCache() *seenContactCache {
+	return &seenContactCache{entries: make(map[string]seenContact)}
+}
+
+// changed reports whether update carries a name or LID that was not written
+// yet, or a last interaction that is due, and remembers it if so. Empty
+// fields are not written by UpsertContact and never count as a change.
+func (c *seenContactCache) changed(key string, update domainChatStorage.Contact) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen, ok := c.entries[key]
+	dirty := !ok ||
+		(update.PushName != "" && update.PushName != seen.pushName) ||
+		(update.BusinessName != "" && update.BusinessName != seen.businessName) ||
+		(update.LID != "" && update.LID != seen.lid) ||
+		(update.LastInteractionAt != nil && update.LastInteractionAt.Sub(seen.interactionAt) >= contactInteractionInterval)
+	if !dirty {
+		return false
+	}
+
+	if update.PushName != "" {
+		seen.pushName = update.PushName
+	}
+	if update.BusinessName != "" {
+		seen.businessName = update.BusinessName
+	}
+	if update.LID != "" {
+		seen.lid = update.LID
+	}
+	if update.LastInteractionAt != nil && update.LastInteractionAt.After(seen.interactionAt) {
+		seen.interactionAt = *update.LastInteractionAt
+	}
+	if !ok && len(c.entries) >= seenContactLimit {
+		c.entries = make(map[string]seenContact)
+	}
+	c.entries[key] = seen
+	return true
+}
+
+// RecordContact merges what was learned about jid into the device's contact
+// book. Contacts are filed under their phone-number JID whenever it is known,
+// with the LID kept alongside; groups, newsletters and broadcasts are ignored.
+// An empty deviceID lets the device-scoped repository fill it in.
+func RecordContact(ctx context.Context, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client, deviceID string, jid types.JID, update domainChatStorage.Contact) {
+	if chatStorageRepo == nil {
+		return
+	}
+	key, lid, ok := contactKey(ctx, jid, client)
+	if !ok {
+		return
+	}
+
+	update.DeviceID = deviceID
+	update.JID = key.String()
+	if update.LID == "" {
+		update.LID = lid
+	}
+	if key.Server == types.DefaultUserServer {
+		update.Phone = key.User
+	}
+	if err := chatStorageRepo.UpsertContact(&update); err != nil {
+		log.Warnf("Failed to update contact %s: %v", update.JID, err)
+	}
+}
+
+// contactKey resolves the JID a contact is filed under and its LID. ok is
+// false for anything that is not a user.
+func contactKey(ctx context.Context, jid types.JID, client *whatsmeow.Client) (key types.JID, lid string, ok bool) {
+	switch jid.Server {
+	case types.HiddenUserServer:
+		lidJID := jid.ToNonAD()
+		if pn := NormalizeJIDFromLID(ctx, lidJID, client); pn.Server == types.DefaultUserServer {
+			return pn.ToNonAD(), lidJID.String(), true
+		}
+		return lidJID, lidJID.String(), true
+	case types.DefaultUserServer:
+		key = jid.ToNonAD()
+		if client != nil && client.Store != nil && client.Store.LIDs != nil {
+			if found, err := client.Store.LIDs.GetLIDForPN(ctx, key); err == nil && !found.IsEmpty() {
+				lid = found.String()
+			}
+		}
+		return key, lid, true
+	default:
+		return types.EmptyJID, "", false
+	}
+}
+
+// recordMessageContact updates the sender's names and, for direct chats, the
+// last interaction. Our own messages only count as an interaction with the chat.
+// Senders whose names and LID are unchanged are written at most once per
+// contactInteractionInterval.
+func recordMessageContact(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	if chatStorageRepo == nil {
+		return
+	}
+	deviceID := ""
+	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
+		deviceID = inst.ID()
+	}
+
+	chat := evt.Info.Chat
+	direct := chat.Server == types.DefaultUserServer || chat.Server == types.HiddenUserServer
+	at := evt.Info.Timestamp
+
+	if evt.Info.IsFromMe {
+		update := domainChatStorage.Contact{LastInteractionAt: &at}
+		if direct && seenContacts.changed(deviceID+"|"+chat.ToNonAD().String(), update) {
+			RecordContact(ctx, chatStorageRepo, client, "", chat, update)
+		}
+		return
+	}
+
+	update := domainChatStorage.Contact{PushName: evt.Info.PushName}
+	if evt.Info.VerifiedName != nil && evt.Info.VerifiedName.Details != nil {
+		update.BusinessName = evt.Info.VerifiedName.Details.GetVerifiedName()
+	}
+	if direct {
+		update.LastInteractionAt = &at
+	} else if update.PushName == "" && update.BusinessName == "" {
+		return
+	}
+
+	sender := evt.Info.Sender
+	if sender.Server == types.HiddenUserServer && evt.Info.SenderAlt.Server == types.DefaultUserServer {
+		update.LID = sender.ToNonAD().String()
+		sender = evt.Info.SenderAlt
+	}
+	if !seenContacts.changed(deviceID+"|"+sender.ToNonAD().String(), update) {
+		return
+	}
+	RecordContact(ctx, chatStorageRepo, client, "", sender, update)
+}
+
+func handlePushName(ctx context.Context, evt *events.PushName, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	update := domainChatStorage.Contact{PushName: evt.NewPushName}
+	jid := evt.JID
+	if jid.Server == types.HiddenUserServer && evt.JIDAlt.Server == types.DefaultUserServer {
+		update.LID = jid.ToNonAD().String()
+		jid = evt.JIDAlt
+	}
+	RecordContact(ctx, chatStorageRepo, client, deviceID, jid, update)
+}
+
+func handleBusinessName(ctx context.Context, evt *events.BusinessName, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	RecordContact(ctx, chatStorageRepo, client, deviceID, evt.JID, domainChatStorage.Contact{BusinessName: evt.NewBusinessName})
+}
+
+// handleContact records the name the contact is saved under in the phone's
+// address book, as synced through app state.
+func handleContact(ctx context.Context, evt *events.Contact, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	if evt.Action == nil {
+		return
+	}
+	name := evt.Action.GetFullName()
+	if name == "" {
+		name = evt.Action.GetFirstName()
+	}
+	if name == "" {
+		return
+	}
+
+	update := domainChatStorage.Contact{SavedName: name}
+	jid := evt.JID
+	if lid, err := types.ParseJID(evt.Action.GetLidJID()); err == nil && lid.Server == types.HiddenUserServer {
+		update.LID = lid.ToNonAD().String()
+	}
+	if jid.Server == types.HiddenUserServer {
+		if pn, err := types.ParseJID(evt.Action.GetPnJID()); err == nil && pn.Server == types.DefaultUserServer {
+			update.LID = jid.ToNonAD().String()
+			jid = pn
+		}
+	}
+	RecordContact(ctx, chatStorageRepo, client, deviceID, jid, update)
+}
+
+// handlePicture keeps the contact's avatar URL current, in the contact book and
+// on the chat's Chatwoot contact. The new URL is looked up in the background
+// since the event only carries the picture ID.
+func handlePicture(ctx context.Context, evt *events.Picture, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	go refreshChatwootAvatar(context.WithoutCancel(ctx), evt, deviceID, client)
+
+	if chatStorageRepo == nil || deviceID == "" {
+		return
+	}
+	key, _, ok := contactKey(ctx, evt.JID, client)
+	if !ok {
+		return
+	}
+
+	if evt.Remove || client == nil {
+		if err := chatStorageRepo.SetContactAvatar(deviceID, key.String(), ""); err != nil {
+			log.Warnf("Failed to clear avatar of contact %s: %v", key.String(), err)
+		}
+		return
+	}
+
+	go func(jid types.JID) {
+		pictureCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+
+		avatarURL := ""
+		pic, err := client.GetProfilePictureInfo(pictureCtx, jid, &whatsmeow.GetProfilePictureParams{})
+		switch {
+		case errors.Is(err, whatsmeow.ErrProfilePictureNotSet), errors.Is(err, whatsmeow.ErrProfilePictureUnauthorized):
+		case err != nil:
+			log.Debugf("Failed to fetch avatar of contact %s: %v", jid.String(), err)
+			return
+		case pic != nil:
+			avatarURL = pic.URL
+		}
+		if err := chatStorageRepo.SetContactAvatar(deviceID, key.String(), avatarURL); err != nil {
+			log.Warnf("Failed to update avatar of contact %s: %v", key.String(), err)
+		}
+	}(evt.JID)
+}
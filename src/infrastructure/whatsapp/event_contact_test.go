@@ -0,0 +1,140 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+type contactRepoSpy struct {
+	domainChatStorage.IChatStorageRepository
+	upserts []domainChatStorage.Contact
+}
+
+func (r *contactRepoSpy) UpsertContact(contact *domainChatStorage.Contact) error {
+	r.upserts = append(r.upserts, *contact)
+	return nil
+}
+
+func resetSeenContacts(t *testing.T) {
+	t.Helper()
+	original := seenContacts
+	seenContacts = newSeenContactCache()
+	t.Cleanup(func() { seenContacts = original })
+}
+
+func TestRecordMessageContact(t *testing.T) {
+	at := time.Date(2026, time.October, 5, 9, 0, 0, 0, time.UTC)
+	user := types.NewJID("628111", types.DefaultUserServer)
+	lid := types.NewJID("9001", types.HiddenUserServer)
+	group := types.NewJID("120363", types.GroupServer)
+
+	message := func(chat, sender types.JID, fromMe bool, pushName string) *events.Message {
+		return &events.Message{Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: chat, Sender: sender, IsFromMe: fromMe},
+			PushName:      pushName,
+			Timestamp:     at,
+		}}
+	}
+
+	t.Run("direct message records names and interaction", func(t *testing.T) {
+		resetSeenContacts(t)
+		repo := &contactRepoSpy{}
+		recordMessageContact(context.Background(), message(user, user, false, "Budi"), repo, nil)
+
+		if len(repo.upserts) != 1 {
+			t.Fatalf("upserts = %d, want 1", len(repo.upserts))
+		}
+		got := repo.upserts[0]
+		if got.JID != "628111@s.whatsapp.net" || got.Phone != "628111" || got.PushName != "Budi" {
+			t.Fatalf("unexpected contact %+v", got)
+		}
+		if got.LastInteractionAt == nil || !got.LastInteractionAt.Equal(at) {
+			t.Fatalf("last interaction = %v, want %v", got.LastInteractionAt, at)
+		}
+	})
+
+	t.Run("own message only touches the chat interaction", func(t *testing.T) {
+		resetSeenContacts(t)
+		repo := &contactRepoSpy{}
+		recordMessageContact(context.Background(), message(user, types.NewJID("628999", types.DefaultUserServer), true, "Me"), repo, nil)
+
+		if len(repo.upserts) != 1 || repo.upserts[0].JID != "628111@s.whatsapp.net" || repo.upserts[0].PushName != "" {
+			t.Fatalf("unexpected upserts %+v", repo.upserts)
+		}
+	})
+
+	t.Run("group sender records names without interaction", func(t *testing.T) {
+		resetSeenContacts(t)
+		repo := &contactRepoSpy{}
+		evt := message(group, lid, false, "Sari")
+		evt.Info.SenderAlt = user
+		recordMessageContact(context.Background(), evt, repo, nil)
+
+		if len(repo.upserts) != 1 {
+			t.Fatalf("upserts = %d, want 1", len(repo.upserts))
+		}
+		got := repo.upserts[0]
+		if got.JID != "628111@s.whatsapp.net" || got.LID != "9001@lid" || got.LastInteractionAt != nil {
+			t.Fatalf("unexpected contact %+v", got)
+		}
+	})
+
+	t.Run("nameless group sender is skipped", func(t *testing.T) {
+		resetSeenContacts(t)
+		repo := &contactRepoSpy{}
+		recordMessageContact(context.Background(), message(group, user, false, ""), repo, nil)
+
+		if len(repo.upserts) != 0 {
+			t.Fatalf("unexpected upserts %+v", repo.upserts)
+		}
+	})
+}
+
+func TestRecordMessageContactSkipsUnchangedSenders(t *testing.T) {
+	resetSeenContacts(t)
+	at := time.Date(2026, time.October, 5, 9, 0, 0, 0, time.UTC)
+	user := types.NewJID("628111", types.DefaultUserServer)
+	message := func(fromMe bool, pushName string, at time.Time) *events.Message {
+		return &events.Message{Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: user, Sender: user, IsFromMe: fromMe},
+			PushName:      pushName,
+			Timestamp:     at,
+		}}
+	}
+
+	repo := &contactRepoSpy{}
+	recordMessageContact(context.Background(), message(false, "Budi", at), repo, nil)
+	recordMessageContact(context.Background(), message(false, "Budi", at.Add(time.Minute)), repo, nil)
+	recordMessageContact(context.Background(), message(true, "", at.Add(2*time.Minute)), repo, nil)
+	if len(repo.upserts) != 1 {
+		t.Fatalf("upserts = %d, want 1 while nothing changed", len(repo.upserts))
+	}
+
+	recordMessageContact(context.Background(), message(false, "Budi S.", at.Add(3*time.Minute)), repo, nil)
+	recordMessageContact(context.Background(), message(true, "", at.Add(3*time.Minute+contactInteractionInterval)), repo, nil)
+	if len(repo.upserts) != 3 {
+		t.Fatalf("upserts = %d, want 3 after a new push name and a due interaction", len(repo.upserts))
+	}
+	if got := repo.upserts[1]; got.PushName != "Budi S." {
+		t.Fatalf("unexpected contact %+v", got)
+	}
+}
+
+func TestRecordContactIgnoresNonUsers(t *testing.T) {
+	repo := &contactRepoSpy{}
+	for _, jid := range []types.JID{
+		types.NewJID("120363", types.GroupServer),
+		types.NewJID("120363", types.NewsletterServer),
+		types.StatusBroadcastJID,
+	} {
+		RecordContact(context.Background(), repo, nil, "dev-1", jid, domainChatStorage.Contact{PushName: "x"})
+	}
+	if len(repo.upserts) != 0 {
+		t.Fatalf("unexpected upserts %+v", repo.upserts)
+	}
+}
@@ -0,0 +1,286 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// Device lifecycle webhook events.
+const (
+	deviceEventConnected        = "device.connected"
+	deviceEventDisconnected     = "device.disconnected"
+	deviceEventLoggedOut        = "device.logged_out"
+	deviceEventStreamReplaced   = "device.stream_replaced"
+	deviceEventPairSuccess      = "device.pair_success"
+	deviceEventTemporaryBan     = "device.temporary_ban"
+	deviceEventKeepAliveTimeout = "device.keepalive_timeout"
+)
+
+// deviceAlertCooldown is the minimum time between two alerts for the same
+// device and event, so a flapping connection does not flood the admin.
+const deviceAlertCooldown = 10 * time.Minute
+
+// deviceEventExitTimeout bounds how long delivering the last event may delay
+// the process exit after a stream was replaced.
+const deviceEventExitTimeout = 5 * time.Second
+
+// DeviceEvent is a change in a device's connection or session state. It is
+// sent to webhooks, WebSocket clients and, when configured, the alert channel.
+type DeviceEvent struct {
+	Event    string `json:"event"`
+	DeviceID string `json:"device_id"`
+	JID      string `json:"jid,omitempty"`
+	// Reason is a human-readable cause; ReasonCode is the WhatsApp code behind
+	// it (connect failure or temporary ban code), when there is one.
+	Reason     string         `json:"reason,omitempty"`
+	ReasonCode int            `json:"reason_code,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+	Timestamp  time.Time      `json:"timestamp"`
+}
+
+var (
+	// notifyDeviceEventFn is swapped in tests to capture lifecycle events.
+	notifyDeviceEventFn = notifyDeviceEvent
+	// sendAlertMessageFn delivers an alert text over WhatsApp. Overridable in tests.
+	sendAlertMessageFn = sendAlertMessage
+
+	deviceAlertsMu   sync.Mutex
+	deviceAlertsLast = make(map[string]time.Time)
+)
+
+// newDeviceEvent snapshots the device identity for an event. It must be built
+// before a logout clears the slot's JID.
+func newDeviceEvent(instance *DeviceInstance, event, reason string) DeviceEvent {
+	return DeviceEvent{
+		Event:     event,
+		DeviceID:  instance.ID(),
+		JID:       instance.JID(),
+		Reason:    reason,
+		Timestamp: time.Now(),
+	}
+}
+
+// loggedOutDeviceEvent describes a remote logout. Logouts reported while
+// connecting carry a connect failure code; stream errors do not.
+func loggedOutDeviceEvent(instance *DeviceInstance, evt *events.LoggedOut) DeviceEvent {
+	event := newDeviceEvent(instance, deviceEventLoggedOut, evt.PermanentDisconnectDescription())
+	if evt.OnConnect {
+		event.ReasonCode = int(evt.Reason)
+	}
+	event.Details = map[string]any{"on_connect": evt.OnConnect}
+	return event
+}
+
+// permanentDisconnectDeviceEvent describes a disconnect whatsmeow will not
+// recover from on its own. Temporary bans get their own event.
+func permanentDisconnectDeviceEvent(instance *DeviceInstance, evt events.PermanentDisconnect) DeviceEvent {
+	switch e := evt.(type) {
+	case *events.TemporaryBan:
+		event := newDeviceEvent(instance, deviceEventTemporaryBan, e.Code.String())
+		event.ReasonCode = int(e.Code)
+		if e.Expire > 0 {
+			event.Details = map[string]any{
+				"expires_in_seconds": int64(e.Expire.Seconds()),
+				"expires_at":         event.Timestamp.Add(e.Expire).UTC().Format(time.RFC3339),
+			}
+		}
+		return event
+	case *events.ConnectFailure:
+		event := newDeviceEvent(instance, deviceEventDisconnected, evt.PermanentDisconnectDescription())
+		event.ReasonCode = int(e.Reason)
+		event.Details = map[string]any{"permanent": true}
+		return event
+	}
+	event := newDeviceEvent(instance, deviceEventDisconnected, evt.PermanentDisconnectDescription())
+	event.Details = map[string]any{"permanent": true}
+	return event
+}
+
+// createDeviceEventPayload creates the webhook payload of a lifecycle event.
+func createDeviceEventPayload(event DeviceEvent) map[string]any {
+	payload := map[string]any{
+		"session_id": event.DeviceID,
+	}
+	if event.Reason != "" {
+		payload["reason"] = event.Reason
+	}
+	if event.ReasonCode != 0 {
+		payload["reason_code"] = event.ReasonCode
+	}
+	for key, value := range event.Details {
+		payload[key] = value
+	}
+
+	body := map[string]any{
+		"event":      event.Event,
+		"timestamp":  event.Timestamp.Format(time.RFC3339),
+		"session_id": event.DeviceID,
+		"payload":    payload,
+	}
+	if event.JID != "" {
+		body["device_id"] = event.JID
+	}
+	return body
+}
+
+// deviceEventWebSocketCode maps a lifecycle event to its WebSocket code, e.g.
+// device.logged_out -> DEVICE_LOGGED_OUT.
+func deviceEventWebSocketCode(event string) string {
+	return strings.ToUpper(strings.ReplaceAll(event, ".", "_"))
+}
+
+func deviceEventMessage(event DeviceEvent) string {
+	// DEVICE_LOGGED_OUT was broadcast before lifecycle events existed; clients
+	// may match its message, so it is kept as it was.
+	if event.Event == deviceEventLoggedOut {
+		return "Device logged out (slot kept)"
+	}
+	label := strings.ReplaceAll(strings.TrimPrefix(event.Event, "device."), "_", " ")
+	if event.Reason == "" {
+		return fmt.Sprintf("Device %s %s", event.DeviceID, label)
+	}
+	return fmt.Sprintf("Device %s %s: %s", event.DeviceID, label, event.Reason)
+}
+
+// notifyDeviceEvent publishes a lifecycle event without blocking the event
+// handler. The device's webhook config is resolved right away: a remote logout
+// clears the JID it is looked up by.
+func notifyDeviceEvent(ctx context.Context, event DeviceEvent) {
+	webhookConfig := deviceEventWebhookConfig(event)
+	go deliverDeviceEvent(context.WithoutCancel(ctx), event, webhookConfig)
+}
+
+func deviceEventWebhookConfig(event DeviceEvent) *domainChatStorage.DeviceWebhookConfig {
+	webhookConfig, err := getWebhookConfigForDevice(event.JID)
+	if err != nil {
+		logrus.Warnf("Failed to get webhook config for device %s, falling back to global config: %v", event.JID, err)
+		return nil
+	}
+	return webhookConfig
+}
+
+// deliverDeviceEvent broadcasts a lifecycle event to WebSocket clients and
+// delivers it to the webhooks and alert channel, returning once both are done.
+// The event's device_id keeps the field older WebSocket results carried.
+func deliverDeviceEvent(ctx context.Context, event DeviceEvent, webhookConfig *domainChatStorage.DeviceWebhookConfig) {
+	go func() {
+		websocket.Broadcast <- websocket.BroadcastMessage{
+			Code:    deviceEventWebSocketCode(event.Event),
+			Message: deviceEventMessage(event),
+			Result:  event,
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		if err := forwardPayloadWithWebhookConfig(ctx, createDeviceEventPayload(event), event.Event, webhookConfig); err != nil {
+			logrus.Errorf("Failed to forward %s event to webhook: %v", event.Event, err)
+		}
+	})
+	wg.Go(func() {
+		sendDeviceAlert(ctx, event)
+	})
+	wg.Wait()
+}
+
+// sendDeviceAlert raises an alert for events listed in WHATSAPP_ALERT_EVENTS:
+// the webhook payload is posted to WHATSAPP_ALERT_WEBHOOK and a text goes to
+// WHATSAPP_ALERT_PHONE from another connected device.
+func sendDeviceAlert(ctx context.Context, event DeviceEvent) {
+	if config.WhatsappAlertWebhook == "" && config.WhatsappAlertPhone == "" {
+		return
+	}
+	if !isDeviceAlertEvent(event.Event) || !allowDeviceAlert(event, time.Now()) {
+		return
+	}
+
+	if url := config.WhatsappAlertWebhook; url != "" {
+		if err := submitWebhookFn(ctx, createDeviceEventPayload(event), url, nil); err != nil {
+			logrus.Errorf("Failed to send %s alert for device %s to alert webhook: %v", event.Event, event.DeviceID, err)
+		}
+	}
+	if config.WhatsappAlertPhone != "" {
+		if err := sendAlertMessageFn(ctx, event.DeviceID, deviceAlertText(event)); err != nil {
+			logrus.Errorf("Failed to send %s alert for device %s over WhatsApp: %v", event.Event, event.DeviceID, err)
+		}
+	}
+}
+
+func isDeviceAlertEvent(event string) bool {
+	for _, allowed := range config.WhatsappAlertEvents {
+		if strings.EqualFold(strings.TrimSpace(allowed), event) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowDeviceAlert reports whether the cooldown of this device and event has
+// passed, and starts a new one if so.
+func allowDeviceAlert(event DeviceEvent, now time.Time) bool {
+	key := event.DeviceID + "|" + event.Event
+	deviceAlertsMu.Lock()
+	defer deviceAlertsMu.Unlock()
+	if last, ok := deviceAlertsLast[key]; ok && now.Sub(last) < deviceAlertCooldown {
+		return false
+	}
+	deviceAlertsLast[key] = now
+	return true
+}
+
+func deviceAlertText(event DeviceEvent) string {
+	device := event.DeviceID
+	if phone := utils.ExtractPhoneNumber(event.JID); phone != "" && phone != device {
+		device = fmt.Sprintf("%s (+%s)", device, phone)
+	}
+	text := fmt.Sprintf("[%s] device %s: %s", config.AppOs, device,
+		strings.ReplaceAll(strings.TrimPrefix(event.Event, "device."), "_", " "))
+	if event.Reason != "" {
+		text += " - " + event.Reason
+	}
+	return text + " at " + event.Timestamp.UTC().Format(time.RFC3339)
+}
+
+// sendAlertMessage sends an alert text to WHATSAPP_ALERT_PHONE from the first
+// connected device other than the one the alert is about.
+func sendAlertMessage(ctx context.Context, aboutDeviceID, text string) error {
+	recipient := utils.FormatJID(config.WhatsappAlertPhone)
+	if recipient.IsEmpty() {
+		return fmt.Errorf("invalid alert phone %q", config.WhatsappAlertPhone)
+	}
+	dm := GetDeviceManager()
+	if dm == nil {
+		return errors.New("device manager not initialized")
+	}
+
+	for _, inst := range dm.ListDevices() {
+		if inst.ID() == aboutDeviceID {
+			continue
+		}
+		client := inst.GetClient()
+		if client == nil || !client.IsConnected() || !client.IsLoggedIn() {
+			continue
+		}
+		if _, err := client.SendMessage(ctx, recipient, &waE2E.Message{Conversation: proto.String(text)}); err != nil {
+			return fmt.Errorf("send from device %s: %w", inst.ID(), err)
+		}
+		return nil
+	}
+	return errors.New("no other connected device to send the alert from")
+}
@@ -0,0 +1,141 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+func captureDeviceEvents(t *testing.T) *[]DeviceEvent {
+	t.Helper()
+	var captured []DeviceEvent
+	original := notifyDeviceEventFn
+	notifyDeviceEventFn = func(_ context.Context, event DeviceEvent) {
+		captured = append(captured, event)
+	}
+	t.Cleanup(func() { notifyDeviceEventFn = original })
+	return &captured
+}
+
+func TestHandlerEmitsDeviceLifecycleEvents(t *testing.T) {
+	originalLog := log
+	log = waLog.Noop
+	defer func() { log = originalLog }()
+
+	captured := captureDeviceEvents(t)
+	ctx := context.Background()
+	instance := NewDeviceInstance("sales", nil, nil)
+	instance.jid = "628123456789@s.whatsapp.net"
+
+	handler(ctx, instance, &events.Disconnected{})
+	handler(ctx, instance, &events.KeepAliveTimeout{ErrorCount: 2})
+	handler(ctx, instance, &events.TemporaryBan{Code: events.TempBanBlockedByUsers, Expire: time.Hour})
+	handler(ctx, instance, &events.ClientOutdated{})
+	handler(ctx, instance, &events.LoggedOut{OnConnect: true, Reason: events.ConnectFailureLoggedOut})
+
+	require.Len(t, *captured, 5)
+	got := *captured
+
+	assert.Equal(t, deviceEventDisconnected, got[0].Event)
+	assert.Equal(t, false, got[0].Details["permanent"])
+
+	assert.Equal(t, deviceEventKeepAliveTimeout, got[1].Event)
+	assert.Equal(t, 2, got[1].Details["error_count"])
+
+	assert.Equal(t, deviceEventTemporaryBan, got[2].Event)
+	assert.Equal(t, int(events.TempBanBlockedByUsers), got[2].ReasonCode)
+	assert.Equal(t, int64(3600), got[2].Details["expires_in_seconds"])
+
+	assert.Equal(t, deviceEventDisconnected, got[3].Event)
+	assert.Equal(t, "client outdated", got[3].Reason)
+	assert.Equal(t, true, got[3].Details["permanent"])
+
+	assert.Equal(t, deviceEventLoggedOut, got[4].Event)
+	assert.Equal(t, int(events.ConnectFailureLoggedOut), got[4].ReasonCode)
+	assert.Equal(t, "628123456789@s.whatsapp.net", got[4].JID, "the JID is captured before the slot is reset")
+	for _, event := range got {
+		assert.Equal(t, "sales", event.DeviceID)
+	}
+}
+
+func TestCreateDeviceEventPayload(t *testing.T) {
+	event := DeviceEvent{
+		Event:      deviceEventLoggedOut,
+		DeviceID:   "sales",
+		JID:        "628123456789@s.whatsapp.net",
+		Reason:     "logged out",
+		ReasonCode: 401,
+		Details:    map[string]any{"on_connect": true},
+		Timestamp:  time.Date(2025, 7, 13, 11, 6, 2, 0, time.UTC),
+	}
+
+	body := createDeviceEventPayload(event)
+	assert.Equal(t, "device.logged_out", body["event"])
+	assert.Equal(t, "628123456789@s.whatsapp.net", body["device_id"])
+	assert.Equal(t, "sales", body["session_id"])
+	assert.Equal(t, "2025-07-13T11:06:02Z", body["timestamp"])
+	assert.Equal(t, map[string]any{
+		"session_id":  "sales",
+		"reason":      "logged out",
+		"reason_code": 401,
+		"on_connect":  true,
+	}, body["payload"])
+
+	assert.Equal(t, "DEVICE_LOGGED_OUT", deviceEventWebSocketCode(event.Event))
+	assert.Equal(t, "Device logged out (slot kept)", deviceEventMessage(event))
+
+	result, err := json.Marshal(event)
+	require.NoError(t, err)
+	assert.Contains(t, string(result), `"device_id":"sales"`, "the WebSocket result keeps its device_id")
+}
+
+func TestSendDeviceAlertFiltersAndThrottles(t *testing.T) {
+	prevWebhook, prevPhone, prevEvents := config.WhatsappAlertWebhook, config.WhatsappAlertPhone, config.WhatsappAlertEvents
+	config.WhatsappAlertWebhook = "https://ops.example.com/alerts"
+	config.WhatsappAlertPhone = "628111111111"
+	config.WhatsappAlertEvents = []string{"device.logged_out"}
+
+	var webhookCalls []string
+	originalSubmit := submitWebhookFn
+	submitWebhookFn = func(_ context.Context, payload map[string]any, url string, _ *domainChatStorage.DeviceWebhookConfig) error {
+		webhookCalls = append(webhookCalls, url+" "+payload["event"].(string))
+		return nil
+	}
+	var messages []string
+	originalSend := sendAlertMessageFn
+	sendAlertMessageFn = func(_ context.Context, aboutDeviceID, text string) error {
+		messages = append(messages, aboutDeviceID+": "+text)
+		return nil
+	}
+	t.Cleanup(func() {
+		config.WhatsappAlertWebhook, config.WhatsappAlertPhone, config.WhatsappAlertEvents = prevWebhook, prevPhone, prevEvents
+		submitWebhookFn = originalSubmit
+		sendAlertMessageFn = originalSend
+		deviceAlertsMu.Lock()
+		deviceAlertsLast = make(map[string]time.Time)
+		deviceAlertsMu.Unlock()
+	})
+
+	now := time.Date(2025, 7, 13, 11, 6, 2, 0, time.UTC)
+	loggedOut := DeviceEvent{Event: deviceEventLoggedOut, DeviceID: "sales", JID: "628123456789@s.whatsapp.net", Reason: "logged out", Timestamp: now}
+
+	sendDeviceAlert(context.Background(), loggedOut)
+	// Within the cooldown the same device and event stay quiet.
+	sendDeviceAlert(context.Background(), loggedOut)
+	// Events outside WHATSAPP_ALERT_EVENTS never alert.
+	sendDeviceAlert(context.Background(), DeviceEvent{Event: deviceEventKeepAliveTimeout, DeviceID: "sales", Timestamp: now})
+
+	assert.Equal(t, []string{"https://ops.example.com/alerts device.logged_out"}, webhookCalls)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "sales: [GOWA] device sales (+628123456789): logged out - logged out at 2025-07-13T11:06:02Z", messages[0])
+
+	assert.True(t, allowDeviceAlert(loggedOut, time.Now().Add(deviceAlertCooldown)))
+}
@@ -5,14 +5,31 @@ import (
 	"fmt"
 	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
+// Webhook event names of the two kinds of GroupInfo change.
+const (
+	groupParticipantsEvent = "group.participants"
+	groupSettingsEvent     = "group.settings"
+)
+
+// groupInfoChange is one webhook-worthy change carried by a GroupInfo event:
+// a participant action with its affected users, or a settings change with its
+// new values in fields.
+type groupInfoChange struct {
+	event      string
+	actionType string
+	jids       []types.JID
+	fields     map[string]any
+}
+
 // createGroupInfoPayload creates a webhook payload for group information events
-func createGroupInfoPayload(ctx context.Context, evt *events.GroupInfo, actionType string, jids []types.JID, deviceID string, client *whatsmeow.Client) map[string]any {
+func createGroupInfoPayload(ctx context.Context, evt *events.GroupInfo, change groupInfoChange, deviceID string, client *whatsmeow.Client) map[string]any {
 	body := make(map[string]any)
 
 	// Create payload structure matching the expected format
@@ -22,14 +39,24 @@ func createGroupInfoPayload(ctx context.Context, evt *events.GroupInfo, actionTy
 	payload["chat_id"] = evt.JID.ToNonAD().String()
 
 	// Add action type and affected users (with LID resolution)
-	payload["type"] = actionType
-	payload["jids"] = jidsToStrings(ctx, jids, client)
+	payload["type"] = change.actionType
+	payload["jids"] = jidsToStrings(ctx, change.jids, client)
+
+	// Settings changes carry their new values next to the type
+	for key, value := range change.fields {
+		payload[key] = value
+	}
+
+	// Who made the change; absent for changes the server makes on its own
+	if changedBy := groupChangeAuthor(ctx, evt, client); changedBy != "" {
+		payload["changed_by"] = changedBy
+	}
 
 	// Wrap in payload structure
 	body["payload"] = payload
 
 	// Add metadata for webhook processing
-	body["event"] = "group.participants"
+	body["event"] = change.event
 	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
 	if deviceID != "" {
 		body["device_id"] = deviceID
@@ -38,6 +65,120 @@ func createGroupInfoPayload(ctx context.Context, evt *events.GroupInfo, actionTy
 	return body
 }
 
+// groupChangeAuthor returns the phone JID of the user behind a group change,
+// preferring the phone number WhatsApp sends alongside a LID sender.
+func groupChangeAuthor(ctx context.Context, evt *events.GroupInfo, client *whatsmeow.Client) string {
+	return senderAuthor(ctx, evt.Sender, evt.SenderPN, client)
+}
+
+// senderAuthor returns the phone JID form of a group event sender, or "".
+func senderAuthor(ctx context.Context, sender, senderPN *types.JID, client *whatsmeow.Client) string {
+	if senderPN != nil && !senderPN.IsEmpty() {
+		return senderPN.ToNonAD().String()
+	}
+	if sender != nil && !sender.IsEmpty() {
+		return NormalizeJIDFromLID(ctx, *sender, client).ToNonAD().String()
+	}
+	return ""
+}
+
+// groupInfoChanges splits a GroupInfo event into one change per participant
+// action and per settings change, in a stable order.
+func groupInfoChanges(evt *events.GroupInfo) []groupInfoChange {
+	changes := groupInfoParticipantChanges(evt)
+
+	setting := func(actionType string, fields map[string]any) {
+		changes = append(changes, groupInfoChange{event: groupSettingsEvent, actionType: actionType, fields: fields})
+	}
+	if evt.Name != nil {
+		setting("name", map[string]any{"name": evt.Name.Name})
+	}
+	if evt.Topic != nil {
+		setting("topic", map[string]any{
+			"topic":         evt.Topic.Topic,
+			"topic_id":      evt.Topic.TopicID,
+			"topic_deleted": evt.Topic.TopicDeleted,
+		})
+	}
+	if evt.Locked != nil {
+		setting("locked", map[string]any{"locked": evt.Locked.IsLocked})
+	}
+	if evt.Announce != nil {
+		setting("announce", map[string]any{"announce": evt.Announce.IsAnnounce})
+	}
+	if evt.Ephemeral != nil {
+		setting("ephemeral", map[string]any{
+			"ephemeral":     evt.Ephemeral.IsEphemeral,
+			"timer_seconds": evt.Ephemeral.DisappearingTimer,
+		})
+	}
+	if evt.MembershipApprovalMode != nil {
+		setting("join_approval", map[string]any{"join_approval": evt.MembershipApprovalMode.IsJoinApprovalRequired})
+	}
+	if mode := groupMemberAddModeChange(evt); mode != "" {
+		setting("member_add_mode", map[string]any{"member_add_mode": mode})
+	}
+	if evt.NewInviteLink != nil {
+		setting("invite_link", map[string]any{"invite_link": *evt.NewInviteLink})
+	}
+
+	return changes
+}
+
+// groupInfoParticipantChanges returns the non-empty join, leave, promote and
+// demote actions of a GroupInfo event.
+func groupInfoParticipantChanges(evt *events.GroupInfo) []groupInfoChange {
+	var changes []groupInfoChange
+	for _, action := range []groupInfoChange{
+		{event: groupParticipantsEvent, actionType: "join", jids: evt.Join},
+		{event: groupParticipantsEvent, actionType: "leave", jids: evt.Leave},
+		{event: groupParticipantsEvent, actionType: "promote", jids: evt.Promote},
+		{event: groupParticipantsEvent, actionType: "demote", jids: evt.Demote},
+	} {
+		if len(action.jids) > 0 {
+			changes = append(changes, action)
+		}
+	}
+	return changes
+}
+
+// resolveGroupJoinApproval fetches the join approval state a
+// membership_approval_mode change left the group in. whatsmeow flags every
+// such change as "required", including switching it off, so the event's value
+// is replaced by the live one, and the live snapshot replaces the cached
+// group. It returns false when the state could not be fetched.
+func resolveGroupJoinApproval(ctx context.Context, evt *events.GroupInfo, repo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) bool {
+	if evt.MembershipApprovalMode == nil || client == nil {
+		return false
+	}
+	info, err := client.GetGroupInfo(ctx, evt.JID)
+	if err != nil {
+		logrus.Warnf("Failed to fetch join approval state of group %s: %v", evt.JID, err)
+		return false
+	}
+	evt.MembershipApprovalMode.IsJoinApprovalRequired = info.IsJoinApprovalRequired
+	if repo != nil && deviceID != "" {
+		if err := repo.SaveGroupMetadata(GroupMetadataFromInfo(deviceID, info)); err != nil {
+			logrus.Warnf("Failed to cache group %s: %v", evt.JID, err)
+		}
+	}
+	return true
+}
+
+// groupMemberAddModeChange returns the new member add mode, which whatsmeow
+// leaves among the unparsed changes.
+func groupMemberAddModeChange(evt *events.GroupInfo) types.GroupMemberAddMode {
+	for _, node := range evt.UnknownChanges {
+		if node == nil || node.Tag != "member_add_mode" {
+			continue
+		}
+		if mode, ok := node.Content.([]byte); ok {
+			return types.GroupMemberAddMode(mode)
+		}
+	}
+	return ""
+}
+
 // jidsToStrings converts a slice of JIDs to a slice of strings, resolving LIDs to phone numbers
 func jidsToStrings(ctx context.Context, jids []types.JID, client *whatsmeow.Client) []string {
 	if len(jids) == 0 {
@@ -54,25 +195,19 @@ func jidsToStrings(ctx context.Context, jids []types.JID, client *whatsmeow.Clie
 }
 
 // forwardGroupInfoToWebhook forwards group information events to the configured webhook URLs
-func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo, deviceID string, client *whatsmeow.Client) error {
-	// Send separate webhook events for each action type
-	actions := []struct {
-		actionType string
-		jids       []types.JID
-	}{
-		{"join", evt.Join},
-		{"leave", evt.Leave},
-		{"promote", evt.Promote},
-		{"demote", evt.Demote},
-	}
-
-	for _, action := range actions {
-		if len(action.jids) > 0 {
-			payload := createGroupInfoPayload(ctx, evt, action.actionType, action.jids, deviceID, client)
+func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo, repo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) error {
+	joinApprovalKnown := evt.MembershipApprovalMode == nil || resolveGroupJoinApproval(ctx, evt, repo, deviceID, client)
 
-			if err := forwardPayloadToConfiguredWebhooks(ctx, payload, "group.participants"); err != nil {
-				logrus.Warnf("Failed to forward group %s event to webhook: %v", action.actionType, err)
-			}
+	// Send separate webhook events for each participant action and settings change
+	for _, change := range groupInfoChanges(evt) {
+		if change.actionType == "join_approval" && !joinApprovalKnown {
+			// Report the change without a state rather than a guessed one
+			delete(change.fields, "join_approval")
+		}
+		payload := createGroupInfoPayload(ctx, evt, change, deviceID, client)
+
+		if err := forwardPayloadToConfiguredWebhooks(ctx, payload, change.event); err != nil {
+			logrus.Warnf("Failed to forward group %s event to webhook: %v", change.actionType, err)
 		}
 	}
 
@@ -80,9 +215,11 @@ func forwardGroupInfoToWebhook(ctx context.Context, evt *events.GroupInfo, devic
 }
 
 // handleJoinedGroup handles the event when the connected device is added to a new group
-func handleJoinedGroup(ctx context.Context, evt *events.JoinedGroup, deviceID string, client *whatsmeow.Client) {
+func handleJoinedGroup(ctx context.Context, evt *events.JoinedGroup, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	log.Infof("Joined group %s (reason: %s, type: %s)", evt.JID, evt.Reason, evt.Type)
 
+	cacheJoinedGroup(ctx, evt, chatStorageRepo, deviceID, client)
+
 	// Forward joined group event to webhook
 	go func(e *events.JoinedGroup, c *whatsmeow.Client) {
 		webhookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
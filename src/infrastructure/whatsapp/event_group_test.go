@@ -0,0 +1,149 @@
+package whatsapp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestGroupInfoChangesEmitsSettingsWithAuthor(t *testing.T) {
+	groupJID := types.NewJID("120363402106000000", types.GroupServer)
+	admin := types.NewJID("6289685000001", types.DefaultUserServer)
+	link := "https://chat.whatsapp.com/NEWCODE"
+	evt := &events.GroupInfo{
+		JID:       groupJID,
+		Sender:    &admin,
+		Timestamp: time.Date(2026, time.March, 1, 9, 30, 0, 0, time.UTC),
+		Promote:   []types.JID{types.NewJID("6289685000002", types.DefaultUserServer)},
+		Locked:    &types.GroupLocked{IsLocked: true},
+		Topic:     &types.GroupTopic{Topic: "Rules: be kind", TopicID: "T1"},
+		Ephemeral: &types.GroupEphemeral{IsEphemeral: true, DisappearingTimer: 86400},
+		UnknownChanges: []*waBinary.Node{
+			{Tag: "member_add_mode", Content: []byte("admin_add")},
+		},
+		NewInviteLink: &link,
+	}
+
+	changes := groupInfoChanges(evt)
+
+	var got []string
+	for _, change := range changes {
+		got = append(got, change.actionType)
+	}
+	want := []string{"promote", "topic", "locked", "ephemeral", "member_add_mode", "invite_link"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("change types = %v, want %v", got, want)
+	}
+
+	promote := createGroupInfoPayload(context.Background(), evt, changes[0], "dev-1", nil)
+	if promote["event"] != "group.participants" {
+		t.Fatalf("participant change sent as %v, want group.participants", promote["event"])
+	}
+	body := createGroupInfoPayload(context.Background(), evt, changes[4], "dev-1", nil)
+	if body["event"] != "group.settings" || body["device_id"] != "dev-1" || body["timestamp"] != "2026-03-01T09:30:00Z" {
+		t.Fatalf("unexpected envelope %#v", body)
+	}
+	wantPayload := map[string]any{
+		"chat_id":         "120363402106000000@g.us",
+		"type":            "member_add_mode",
+		"jids":            []string{},
+		"member_add_mode": types.GroupMemberAddModeAdmin,
+		"changed_by":      "6289685000001@s.whatsapp.net",
+	}
+	if !reflect.DeepEqual(body["payload"], wantPayload) {
+		t.Fatalf("payload = %#v, want %#v", body["payload"], wantPayload)
+	}
+
+	topic := createGroupInfoPayload(context.Background(), evt, changes[1], "", nil)["payload"].(map[string]any)
+	if topic["topic"] != "Rules: be kind" || topic["topic_id"] != "T1" || topic["topic_deleted"] != false {
+		t.Fatalf("topic payload = %#v", topic)
+	}
+	ephemeral := createGroupInfoPayload(context.Background(), evt, changes[3], "", nil)["payload"].(map[string]any)
+	if ephemeral["ephemeral"] != true || ephemeral["timer_seconds"] != uint32(86400) {
+		t.Fatalf("ephemeral payload = %#v", ephemeral)
+	}
+}
+
+func TestGroupInfoChangesPrefersSenderPhoneNumber(t *testing.T) {
+	lid := types.NewJID("223754944819424", types.HiddenUserServer)
+	pn := types.NewJID("6289685000001", types.DefaultUserServer)
+	evt := &events.GroupInfo{
+		JID:                    types.NewJID("120363402106000000", types.GroupServer),
+		Sender:                 &lid,
+		SenderPN:               &pn,
+		MembershipApprovalMode: &types.GroupMembershipApprovalMode{IsJoinApprovalRequired: true},
+	}
+
+	changes := groupInfoChanges(evt)
+	if len(changes) != 1 || changes[0].actionType != "join_approval" || changes[0].fields["join_approval"] != true {
+		t.Fatalf("changes = %#v, want a single join_approval change", changes)
+	}
+	body := createGroupInfoPayload(context.Background(), evt, changes[0], "", nil)
+	if body["event"] != "group.settings" {
+		t.Fatalf("join approval change sent as %v, want group.settings", body["event"])
+	}
+	payload := body["payload"].(map[string]any)
+	if payload["changed_by"] != "6289685000001@s.whatsapp.net" {
+		t.Fatalf("changed_by = %v, want the sender's phone JID", payload["changed_by"])
+	}
+}
+
+func TestGroupInfoChangesWithoutSenderOmitsChangedBy(t *testing.T) {
+	evt := &events.GroupInfo{
+		JID:  types.NewJID("120363402106000000", types.GroupServer),
+		Join: []types.JID{types.NewJID("6289685000003", types.DefaultUserServer)},
+	}
+
+	changes := groupInfoChanges(evt)
+	if len(changes) != 1 || changes[0].actionType != "join" {
+		t.Fatalf("changes = %#v, want a single join", changes)
+	}
+	payload := createGroupInfoPayload(context.Background(), evt, changes[0], "", nil)["payload"].(map[string]any)
+	if _, ok := payload["changed_by"]; ok {
+		t.Fatalf("changed_by must be omitted without a sender: %#v", payload)
+	}
+	if !reflect.DeepEqual(payload["jids"], []string{"6289685000003@s.whatsapp.net"}) {
+		t.Fatalf("jids = %#v", payload["jids"])
+	}
+}
+
+func TestForwardGroupInfoOmitsUnresolvedJoinApproval(t *testing.T) {
+	originalWebhooks := config.WhatsappWebhook
+	config.WhatsappWebhook = []string{"https://hook.example.com"}
+	originalSubmit := submitWebhookFn
+	var payloads []map[string]any
+	submitWebhookFn = func(_ context.Context, payload map[string]any, _ string, _ *domainChatStorage.DeviceWebhookConfig) error {
+		payloads = append(payloads, payload)
+		return nil
+	}
+	t.Cleanup(func() {
+		config.WhatsappWebhook = originalWebhooks
+		submitWebhookFn = originalSubmit
+	})
+
+	evt := &events.GroupInfo{
+		JID:                    types.NewJID("120363402106000000", types.GroupServer),
+		MembershipApprovalMode: &types.GroupMembershipApprovalMode{IsJoinApprovalRequired: true},
+	}
+	if err := forwardGroupInfoToWebhook(context.Background(), evt, nil, "", nil); err != nil {
+		t.Fatalf("forwardGroupInfoToWebhook: %v", err)
+	}
+
+	if len(payloads) != 1 {
+		t.Fatalf("forwarded %d payloads, want 1", len(payloads))
+	}
+	payload := payloads[0]["payload"].(map[string]any)
+	if payload["type"] != "join_approval" {
+		t.Fatalf("type = %v, want join_approval", payload["type"])
+	}
+	if _, ok := payload["join_approval"]; ok {
+		t.Fatalf("an unresolved state must be omitted, got %#v", payload)
+	}
+}
@@ -38,7 +38,7 @@ func handler(ctx context.Context, instance *DeviceInstance, rawEvt any) {
 		handleAppStateSyncComplete(ctx, client, evt)
 	case *events.PairSuccess:
 		instance.ClearPasskeyState()
-		handlePairSuccess(ctx, evt)
+		handlePairSuccess(ctx, instance, evt)
 	case *events.PairPasskeyRequest:
 		handlePairPasskeyRequest(instance, evt)
 	case *events.PairPasskeyConfirmation:
@@ -46,11 +46,39 @@ func handler(ctx context.Context, instance *DeviceInstance, rawEvt any) {
 	case *events.PairPasskeyError:
 		handlePairPasskeyError(instance, evt)
 	case *events.LoggedOut:
-		handleLoggedOut(instance)
-	case *events.Connected, *events.PushNameSetting:
+		instance.RecordDisconnected(evt.PermanentDisconnectDescription())
+		handleLoggedOut(ctx, instance, evt)
+	case *events.Connected:
+		instance.RecordConnected()
+		notifyDeviceEventFn(ctx, newDeviceEvent(instance, deviceEventConnected, ""))
 		handleConnectionEvents(ctx, client, instance)
+		resubscribePresences(ctx, client, chatStorageRepo, instance.JID())
+	case *events.PushNameSetting:
+		handleConnectionEvents(ctx, client, instance)
+	case *events.Disconnected:
+		// whatsmeow starts reconnecting right away (EnableAutoReconnect).
+		instance.RecordDisconnected("")
+		instance.RecordReconnectAttempt(nil)
+		disconnected := newDeviceEvent(instance, deviceEventDisconnected, "connection lost")
+		disconnected.Details = map[string]any{"permanent": false}
+		notifyDeviceEventFn(ctx, disconnected)
+	case *events.KeepAliveTimeout:
+		instance.RecordConnectionError(fmt.Sprintf("keepalive timeout (%d failed pings)", evt.ErrorCount))
+		timeout := newDeviceEvent(instance, deviceEventKeepAliveTimeout, fmt.Sprintf("%d failed pings", evt.ErrorCount))
+		timeout.Details = map[string]any{"error_count": evt.ErrorCount}
+		if !evt.LastSuccess.IsZero() {
+			timeout.Details["last_success"] = evt.LastSuccess.Format(time.RFC3339)
+		}
+		notifyDeviceEventFn(ctx, timeout)
+	case *events.StreamError:
+		instance.RecordConnectionError(fmt.Sprintf("stream error: %s", evt.Code))
 	case *events.StreamReplaced:
-		handleStreamReplaced(ctx)
+		handleStreamReplaced(ctx, instance)
+	case events.PermanentDisconnect:
+		// Temporary bans, outdated client, connect failures: no auto-reconnect.
+		instance.RecordDisconnected(evt.PermanentDisconnectDescription())
+		log.Warnf("Device %s permanently disconnected: %s", instance.ID(), evt.PermanentDisconnectDescription())
+		notifyDeviceEventFn(ctx, permanentDisconnectDeviceEvent(instance, evt))
 	case *events.Message:
 		handleMessage(ctx, evt, chatStorageRepo, client)
 	case *events.UndecryptableMessage:
@@ -60,17 +88,17 @@ func handler(ctx context.Context, instance *DeviceInstance, rawEvt any) {
 	case *events.Archive:
 		handleArchive(ctx, evt, chatStorageRepo, client)
 	case *events.Presence:
-		handlePresence(ctx, evt)
+		handlePresence(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.ChatPresence:
-		handleChatPresence(ctx, evt, instance.JID(), client)
+		handleChatPresence(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.HistorySync:
 		handleHistorySync(ctx, evt, chatStorageRepo, client)
 	case *events.AppState:
-		handleAppState(ctx, evt, instance.JID(), client)
+		handleAppState(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.GroupInfo:
-		handleGroupInfo(ctx, evt, instance.JID(), client)
+		handleGroupInfo(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.JoinedGroup:
-		handleJoinedGroup(ctx, evt, instance.JID(), client)
+		handleJoinedGroup(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.NewsletterJoin:
 		handleNewsletterJoin(ctx, evt, instance.JID(), client)
 	case *events.NewsletterLeave:
@@ -79,8 +107,24 @@ func handler(ctx context.Context, instance *DeviceInstance, rawEvt any) {
 		handleNewsletterLiveUpdate(ctx, evt, instance.JID(), client)
 	case *events.NewsletterMuteChange:
 		handleNewsletterMuteChange(ctx, evt, instance.JID(), client)
+	case *events.PushName:
+		handlePushName(ctx, evt, chatStorageRepo, instance.JID(), client)
+	case *events.BusinessName:
+		handleBusinessName(ctx, evt, chatStorageRepo, instance.JID(), client)
+	case *events.Contact:
+		handleContact(ctx, evt, chatStorageRepo, instance.JID(), client)
+	case *events.Picture:
+		handlePicture(ctx, evt, chatStorageRepo, instance.JID(), client)
 	case *events.CallOffer:
 		handleCallOffer(ctx, evt, chatStorageRepo, instance.JID(), client)
+	case *events.CallOfferNotice:
+		handleCallOfferNotice(ctx, evt, chatStorageRepo, instance.JID(), client)
+	case *events.CallAccept:
+		handleCallAccept(evt, chatStorageRepo, instance.JID())
+	case *events.CallReject:
+		handleCallReject(evt, chatStorageRepo, instance.JID())
+	case *events.CallTerminate:
+		handleCallTerminate(ctx, evt, chatStorageRepo, instance.JID(), client)
 	}
 
 	instance.UpdateStateFromClient()
@@ -165,11 +209,20 @@ func handleAppStateSyncComplete(_ context.Context, client *whatsmeow.Client, evt
 	}
 }
 
-func handlePairSuccess(ctx context.Context, evt *events.PairSuccess) {
+func handlePairSuccess(ctx context.Context, instance *DeviceInstance, evt *events.PairSuccess) {
 	websocket.Broadcast <- websocket.BroadcastMessage{
 		Code:    "LOGIN_SUCCESS",
 		Message: fmt.Sprintf("Successfully pair with %s", evt.ID.String()),
 	}
+
+	paired := newDeviceEvent(instance, deviceEventPairSuccess, "")
+	paired.JID = evt.ID.ToNonAD().String()
+	paired.Details = map[string]any{"platform": evt.Platform}
+	if evt.BusinessName != "" {
+		paired.Details["business_name"] = evt.BusinessName
+	}
+	notifyDeviceEventFn(ctx, paired)
+
 	primaryDB, secondaryDB := getStoreContainers()
 	syncKeysDevice(ctx, primaryDB, secondaryDB, evt.ID)
 }
@@ -216,7 +269,7 @@ func handlePairPasskeyError(instance *DeviceInstance, evt *events.PairPasskeyErr
 	}
 }
 
-func handleLoggedOut(instance *DeviceInstance) {
+func handleLoggedOut(ctx context.Context, instance *DeviceInstance, evt *events.LoggedOut) {
 	logrus.Warnf("[REMOTE_LOGOUT] Received LoggedOut event for device %s - user logged out from phone", instance.ID())
 	instance.ClearPasskeyState()
 
@@ -229,17 +282,13 @@ func handleLoggedOut(instance *DeviceInstance) {
 	// a full purge via DELETE). A remote logout keeps the device slot, so truncating
 	// here would contradict the keep-slot semantics and lose the conversation history.
 
-	deviceID := instance.ID()
+	// Published before the keep-slot reset below clears the JID that identifies
+	// the device and its webhook config.
+	notifyDeviceEventFn(ctx, loggedOutDeviceEvent(instance, evt))
 
 	// TriggerLoggedOut fires the manager's keep-slot callback (resets the in-memory
 	// client + clears the persisted JID, but keeps the slot id and display name).
 	instance.TriggerLoggedOut()
-
-	websocket.Broadcast <- websocket.BroadcastMessage{
-		Code:    "DEVICE_LOGGED_OUT",
-		Message: "Device logged out (slot kept)",
-		Result:  map[string]string{"device_id": deviceID},
-	}
 }
 
 func handleConnectionEvents(_ context.Context, client *whatsmeow.Client, instance *DeviceInstance) {
@@ -303,7 +352,26 @@ func handleConnectionEvents(_ context.Context, client *whatsmeow.Client, instanc
 	sendConfiguredPresence(context.Background(), client)
 }
 
-func handleStreamReplaced(_ context.Context) {
+func handleStreamReplaced(ctx context.Context, instance *DeviceInstance) {
+	replaced := newDeviceEvent(instance, deviceEventStreamReplaced, "stream replaced by another connection")
+
+	// In HA mode another replica taking over the session is expected during
+	// failover: hand the lease back instead of exiting the whole process.
+	if coordinator := GetLeaseCoordinator(); coordinator != nil {
+		log.Warnf("Device %s stream replaced by another connection; releasing its lease", instance.ID())
+		instance.RecordDisconnected("stream replaced by another connection")
+		replaced.Details = map[string]any{"replica_id": coordinator.ReplicaID()}
+		notifyDeviceEventFn(ctx, replaced)
+		coordinator.Yield(ctx, instance)
+		return
+	}
+
+	// The process exits below, so deliver the event before that, but without
+	// holding the exit back on a slow webhook.
+	log.Warnf("Device %s stream replaced by another connection; exiting", instance.ID())
+	exitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deviceEventExitTimeout)
+	deliverDeviceEvent(exitCtx, replaced, deviceEventWebhookConfig(replaced))
+	cancel()
 	os.Exit(0)
 }
 
@@ -331,22 +399,11 @@ func handleReceipt(ctx context.Context, evt *events.Receipt, deviceID string, cl
 	}
 }
 
-func handlePresence(_ context.Context, evt *events.Presence) {
-	if evt.Unavailable {
-		if evt.LastSeen.IsZero() {
-			log.Infof("%s is now offline", evt.From)
-		} else {
-			log.Infof("%s is now offline (last seen: %s)", evt.From, evt.LastSeen)
-		}
-	} else {
-		log.Infof("%s is now online", evt.From)
-	}
-}
-
-func handleAppState(ctx context.Context, evt *events.AppState, deviceID string, client *whatsmeow.Client) {
+func handleAppState(ctx context.Context, evt *events.AppState, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	log.Debugf("App state event: %+v / %+v", evt.Index, evt.SyncActionValue)
 
 	if isLabelAppState(evt) {
+		recordLabelAppState(ctx, evt, chatStorageRepo, deviceID, client)
 		go func(e *events.AppState, c *whatsmeow.Client) {
 			webhookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
 			defer cancel()
@@ -357,15 +414,19 @@ func handleAppState(ctx context.Context, evt *events.AppState, deviceID string,
 	}
 }
 
-func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, deviceID string, client *whatsmeow.Client) {
+func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
 	// Only process events that have actual changes
 	hasChanges := len(evt.Join) > 0 || len(evt.Leave) > 0 || len(evt.Promote) > 0 || len(evt.Demote) > 0 ||
-		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil
+		evt.Name != nil || evt.Topic != nil || evt.Locked != nil || evt.Announce != nil ||
+		evt.Ephemeral != nil || evt.MembershipApprovalMode != nil || evt.NewInviteLink != nil ||
+		evt.Link != nil || evt.Unlink != nil || groupMemberAddModeChange(evt) != ""
 
 	if !hasChanges {
 		return
 	}
 
+	cacheGroupInfoEvent(ctx, evt, chatStorageRepo, deviceID, client)
+
 	// Log group events for debugging
 	if len(evt.Join) > 0 {
 		log.Infof("Group %s: %d users joined at %s", evt.JID, len(evt.Join), evt.Timestamp)
@@ -384,7 +445,7 @@ func handleGroupInfo(ctx context.Context, evt *events.GroupInfo, deviceID string
 	go func(e *events.GroupInfo, c *whatsmeow.Client) {
 		webhookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
 		defer cancel()
-		if err := forwardGroupInfoToWebhook(webhookCtx, e, deviceID, c); err != nil {
+		if err := forwardGroupInfoToWebhook(webhookCtx, e, chatStorageRepo, deviceID, c); err != nil {
 			logrus.Errorf("Failed to forward group info event to webhook: %v", err)
 		}
 	}(evt, client)
@@ -0,0 +1,54 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+func TestHandlerTracksConnectionStats(t *testing.T) {
+	originalLog := log
+	log = waLog.Noop
+	defer func() { log = originalLog }()
+	captureDeviceEvents(t)
+
+	ctx := context.Background()
+	instance := NewDeviceInstance("test-conn", nil, nil)
+
+	handler(ctx, instance, &events.Disconnected{})
+	stats := instance.ConnectionStats()
+	assert.False(t, stats.LastDisconnectedAt.IsZero())
+	assert.Equal(t, 1, stats.ReconnectAttempts)
+	assert.Empty(t, stats.LastError)
+
+	// A failed auto-reconnect attempt counts the retry and keeps its error.
+	instance.RecordReconnectAttempt(errors.New("dial tcp: i/o timeout"))
+	stats = instance.ConnectionStats()
+	assert.Equal(t, 2, stats.ReconnectAttempts)
+	assert.Equal(t, "dial tcp: i/o timeout", stats.LastError)
+
+	handler(ctx, instance, &events.KeepAliveTimeout{ErrorCount: 3})
+	assert.Equal(t, "keepalive timeout (3 failed pings)", instance.ConnectionStats().LastError)
+
+	handler(ctx, instance, &events.TemporaryBan{Code: events.TempBanSentToTooManyPeople})
+	stats = instance.ConnectionStats()
+	assert.Contains(t, stats.LastError, "temporarily banned")
+	assert.False(t, stats.LastErrorAt.IsZero())
+}
+
+func TestRecordConnectedResetsReconnectAttempts(t *testing.T) {
+	instance := NewDeviceInstance("test-conn-reset", nil, nil)
+	instance.RecordReconnectAttempt(nil)
+	instance.RecordReconnectAttempt(errors.New("boom"))
+
+	instance.RecordConnected()
+
+	stats := instance.ConnectionStats()
+	assert.Equal(t, 0, stats.ReconnectAttempts)
+	assert.False(t, stats.LastConnectedAt.IsZero())
+	assert.Equal(t, "boom", stats.LastError, "last error is kept for diagnosis after reconnecting")
+}
@@ -23,6 +23,7 @@ func recvBroadcast(t *testing.T) websocket.BroadcastMessage {
 }
 
 func TestHandlerPasskeyEvents(t *testing.T) {
+	captureDeviceEvents(t)
 	ctx := context.Background()
 	instance := NewDeviceInstance("test-passkey", nil, nil)
 
@@ -8,15 +8,26 @@ import (
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) {
+	// Root span of the inbound message; storage, auto-reply and the webhook
+	// forward (which outlives this handler) all run as its children.
+	deviceID, _ := deviceStorageFromContext(ctx)
+	ctx, span := telemetry.Start(ctx, "whatsapp.message", trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(
+		telemetry.AttrDeviceID.String(deviceID),
+		telemetry.AttrMessageID.String(evt.Info.ID),
+	))
+	defer span.End()
+
 	// Log message metadata
 	metaParts := buildMessageMetaParts(evt)
 	log.Infof("Received message %s from %s (%s): %+v",
@@ -45,8 +56,13 @@ func handleMessage(ctx context.Context, evt *events.Message, chatStorageRepo dom
 	if err := chatStorageRepo.CreateMessage(ctx, evt); err != nil {
 		// Log storage errors to avoid silent failures that could lead to data loss
 		log.Errorf("Failed to store incoming message %s: %v", evt.Info.ID, err)
+	} else {
+		notifyMessageObservers(ctx, NormalizeJIDFromLID(ctx, evt.Info.Chat, client).String())
 	}
 
+	// Keep the sender's contact book entry current
+	recordMessageContact(ctx, evt, chatStorageRepo, client)
+
 	// Handle image message if present
 	handleImageMessage(ctx, evt, client)
 
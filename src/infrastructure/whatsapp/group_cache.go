@@ -0,0 +1,360 @@
+package whatsapp
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// GroupMetadataTTL is how long a cached group snapshot is served before it is
+// fetched from WhatsApp again. Events patch the snapshot in between.
+const GroupMetadataTTL = 6 * time.Hour
+
+// GroupMetadataFresh reports whether a cached group snapshot is still within
+// GroupMetadataTTL.
+func GroupMetadataFresh(metadata *domainChatStorage.GroupMetadata) bool {
+	return metadata != nil && time.Since(metadata.UpdatedAt) < GroupMetadataTTL
+}
+
+// GroupMetadataFromInfo converts a live group snapshot into its cached form.
+func GroupMetadataFromInfo(deviceID string, info *types.GroupInfo) *domainChatStorage.GroupMetadata {
+	if info == nil {
+		return nil
+	}
+
+	metadata := &domainChatStorage.GroupMetadata{
+		DeviceID:         deviceID,
+		GroupJID:         info.JID.ToNonAD().String(),
+		Name:             info.Name,
+		Topic:            info.Topic,
+		IsLocked:         info.IsLocked,
+		IsAnnounce:       info.IsAnnounce,
+		IsEphemeral:      info.IsEphemeral,
+		EphemeralSeconds: info.DisappearingTimer,
+		JoinApproval:     info.IsJoinApprovalRequired,
+		MemberAddMode:    string(info.MemberAddMode),
+		IsParent:         info.IsParent,
+		GroupCreated:     info.GroupCreated,
+		Participants:     make([]domainChatStorage.GroupMetadataParticipant, 0, len(info.Participants)),
+	}
+	if !info.OwnerJID.IsEmpty() {
+		metadata.OwnerJID = info.OwnerJID.String()
+	}
+	if !info.LinkedParentJID.IsEmpty() {
+		metadata.LinkedParentJID = info.LinkedParentJID.String()
+	}
+	for _, participant := range info.Participants {
+		metadata.Participants = append(metadata.Participants, domainChatStorage.GroupMetadataParticipant{
+			JID:          participant.JID.String(),
+			PhoneNumber:  jidStringOrEmpty(participant.PhoneNumber),
+			LID:          jidStringOrEmpty(participant.LID),
+			DisplayName:  participant.DisplayName,
+			IsAdmin:      participant.IsAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+		})
+	}
+	return metadata
+}
+
+// GroupInfoFromMetadata rebuilds the whatsmeow view of a cached group, so cached
+// and live lookups answer with the same shape.
+func GroupInfoFromMetadata(metadata *domainChatStorage.GroupMetadata) *types.GroupInfo {
+	if metadata == nil {
+		return nil
+	}
+
+	info := &types.GroupInfo{
+		JID:                         parseJIDOrEmpty(metadata.GroupJID),
+		OwnerJID:                    parseJIDOrEmpty(metadata.OwnerJID),
+		GroupName:                   types.GroupName{Name: metadata.Name},
+		GroupTopic:                  types.GroupTopic{Topic: metadata.Topic},
+		GroupLocked:                 types.GroupLocked{IsLocked: metadata.IsLocked},
+		GroupAnnounce:               types.GroupAnnounce{IsAnnounce: metadata.IsAnnounce},
+		GroupEphemeral:              types.GroupEphemeral{IsEphemeral: metadata.IsEphemeral, DisappearingTimer: metadata.EphemeralSeconds},
+		GroupParent:                 types.GroupParent{IsParent: metadata.IsParent},
+		GroupLinkedParent:           types.GroupLinkedParent{LinkedParentJID: parseJIDOrEmpty(metadata.LinkedParentJID)},
+		GroupMembershipApprovalMode: types.GroupMembershipApprovalMode{IsJoinApprovalRequired: metadata.JoinApproval},
+		GroupCreated:                metadata.GroupCreated,
+		MemberAddMode:               types.GroupMemberAddMode(metadata.MemberAddMode),
+		ParticipantCount:            len(metadata.Participants),
+		Participants:                make([]types.GroupParticipant, 0, len(metadata.Participants)),
+	}
+	for _, participant := range metadata.Participants {
+		info.Participants = append(info.Participants, types.GroupParticipant{
+			JID:          parseJIDOrEmpty(participant.JID),
+			PhoneNumber:  parseJIDOrEmpty(participant.PhoneNumber),
+			LID:          parseJIDOrEmpty(participant.LID),
+			DisplayName:  participant.DisplayName,
+			IsAdmin:      participant.IsAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+		})
+	}
+	return info
+}
+
+func jidStringOrEmpty(jid types.JID) string {
+	if jid.IsEmpty() {
+		return ""
+	}
+	return jid.String()
+}
+
+func parseJIDOrEmpty(value string) types.JID {
+	if value == "" {
+		return types.JID{}
+	}
+	jid, err := types.ParseJID(value)
+	if err != nil {
+		return types.JID{}
+	}
+	return jid
+}
+
+// cacheGroupInfoEvent keeps the cached group and its participant and
+// description history current with a GroupInfo event. Known groups are patched in place, and the
+// event's join approval state is corrected from them before it is forwarded;
+// unknown and expired groups are re-fetched in the background. A group this
+// device left or was removed from is dropped from the cache.
+func cacheGroupInfoEvent(ctx context.Context, evt *events.GroupInfo, repo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	if repo == nil || deviceID == "" {
+		return
+	}
+
+	groupJID := evt.JID.ToNonAD().String()
+	if history := groupParticipantEvents(ctx, evt, deviceID, client); len(history) > 0 {
+		if err := repo.StoreGroupParticipantEvents(history); err != nil {
+			logrus.Warnf("Failed to store participant history of group %s: %v", groupJID, err)
+		}
+	}
+	if evt.Topic != nil {
+		if err := repo.StoreGroupDescriptionEvent(groupDescriptionEvent(ctx, evt, deviceID, client)); err != nil {
+			logrus.Warnf("Failed to store description history of group %s: %v", groupJID, err)
+		}
+	}
+	if evt.Name != nil {
+		groupNameCache.Delete(groupJID)
+	}
+
+	if leftGroup(ctx, evt, client) {
+		groupNameCache.Delete(groupJID)
+		if err := repo.DeleteGroupMetadata(deviceID, groupJID); err != nil {
+			logrus.Warnf("Failed to drop cached group %s: %v", groupJID, err)
+		}
+		return
+	}
+
+	cached, err := repo.GetGroupMetadata(deviceID, groupJID)
+	if err != nil {
+		logrus.Warnf("Failed to load cached group %s: %v", groupJID, err)
+	}
+	if cached == nil || (client != nil && !GroupMetadataFresh(cached)) {
+		if client != nil {
+			go refreshGroupMetadata(ctx, evt.JID, repo, deviceID, client)
+		}
+		return
+	}
+
+	applyGroupInfoEvent(ctx, cached, evt, client)
+	if err := repo.SaveGroupMetadata(cached); err != nil {
+		logrus.Warnf("Failed to update cached group %s: %v", groupJID, err)
+	}
+}
+
+// cacheJoinedGroup stores the snapshot carried by a JoinedGroup event and
+// records the device's own join.
+func cacheJoinedGroup(ctx context.Context, evt *events.JoinedGroup, repo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	if repo == nil || deviceID == "" {
+		return
+	}
+
+	groupJID := evt.JID.ToNonAD().String()
+	if err := repo.SaveGroupMetadata(GroupMetadataFromInfo(deviceID, &evt.GroupInfo)); err != nil {
+		logrus.Warnf("Failed to cache joined group %s: %v", groupJID, err)
+	}
+	groupNameCache.Delete(groupJID)
+
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return
+	}
+	if err := repo.StoreGroupParticipantEvents([]*domainChatStorage.GroupParticipantEvent{{
+		DeviceID:       deviceID,
+		GroupJID:       groupJID,
+		ParticipantJID: client.Store.ID.ToNonAD().String(),
+		Action:         "join",
+		ActorJID:       senderAuthor(ctx, evt.Sender, evt.SenderPN, client),
+		CreatedAt:      time.Now(),
+	}}); err != nil {
+		logrus.Warnf("Failed to store participant history of group %s: %v", groupJID, err)
+	}
+}
+
+// leftGroup reports whether the event removes this device from the group,
+// whether it left on its own or was removed by an admin.
+func leftGroup(ctx context.Context, evt *events.GroupInfo, client *whatsmeow.Client) bool {
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return false
+	}
+	own := client.Store.ID.ToNonAD()
+	ownLID := client.Store.GetLID().ToNonAD()
+	for _, jid := range evt.Leave {
+		jid = jid.ToNonAD()
+		if jid == own || (!ownLID.IsEmpty() && jid == ownLID) {
+			return true
+		}
+		if jid.Server == types.HiddenUserServer && NormalizeJIDFromLID(ctx, jid, client).ToNonAD() == own {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshGroupMetadata replaces the cached group with a live snapshot.
+func refreshGroupMetadata(ctx context.Context, groupJID types.JID, repo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	info, err := client.GetGroupInfo(refreshCtx, groupJID)
+	if err != nil {
+		logrus.Warnf("Failed to refresh cached group %s: %v", groupJID, err)
+		return
+	}
+	if err := repo.SaveGroupMetadata(GroupMetadataFromInfo(deviceID, info)); err != nil {
+		logrus.Warnf("Failed to cache group %s: %v", groupJID, err)
+	}
+}
+
+// groupParticipantEvents turns the participant actions of a GroupInfo event
+// into history rows, with participants and actor in phone JID form.
+func groupParticipantEvents(ctx context.Context, evt *events.GroupInfo, deviceID string, client *whatsmeow.Client) []*domainChatStorage.GroupParticipantEvent {
+	createdAt := evt.Timestamp
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	actor := groupChangeAuthor(ctx, evt, client)
+	groupJID := evt.JID.ToNonAD().String()
+
+	var history []*domainChatStorage.GroupParticipantEvent
+	for _, change := range groupInfoParticipantChanges(evt) {
+		for _, participant := range jidsToStrings(ctx, change.jids, client) {
+			history = append(history, &domainChatStorage.GroupParticipantEvent{
+				DeviceID:       deviceID,
+				GroupJID:       groupJID,
+				ParticipantJID: participant,
+				Action:         change.actionType,
+				ActorJID:       actor,
+				CreatedAt:      createdAt,
+			})
+		}
+	}
+	return history
+}
+
+// groupDescriptionEvent turns the topic change of a GroupInfo event into a
+// history row, with the actor in phone JID form.
+func groupDescriptionEvent(ctx context.Context, evt *events.GroupInfo, deviceID string, client *whatsmeow.Client) *domainChatStorage.GroupDescriptionEvent {
+	createdAt := evt.Timestamp
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	event := &domainChatStorage.GroupDescriptionEvent{
+		DeviceID:    deviceID,
+		GroupJID:    evt.JID.ToNonAD().String(),
+		Description: evt.Topic.Topic,
+		TopicID:     evt.Topic.TopicID,
+		Deleted:     evt.Topic.TopicDeleted,
+		ActorJID:    groupChangeAuthor(ctx, evt, client),
+		CreatedAt:   createdAt,
+	}
+	if event.Deleted {
+		event.Description = ""
+	}
+	return event
+}
+
+// applyGroupInfoEvent folds the changes of a GroupInfo event into a cached group.
+func applyGroupInfoEvent(ctx context.Context, metadata *domainChatStorage.GroupMetadata, evt *events.GroupInfo, client *whatsmeow.Client) {
+	if evt.Name != nil {
+		metadata.Name = evt.Name.Name
+	}
+	if evt.Topic != nil {
+		metadata.Topic = evt.Topic.Topic
+		if evt.Topic.TopicDeleted {
+			metadata.Topic = ""
+		}
+	}
+	if evt.Locked != nil {
+		metadata.IsLocked = evt.Locked.IsLocked
+	}
+	if evt.Announce != nil {
+		metadata.IsAnnounce = evt.Announce.IsAnnounce
+	}
+	if evt.Ephemeral != nil {
+		metadata.IsEphemeral = evt.Ephemeral.IsEphemeral
+		metadata.EphemeralSeconds = evt.Ephemeral.DisappearingTimer
+	}
+	// The join approval state of a membership_approval_mode change is not in
+	// the event; resolveGroupJoinApproval caches the live snapshot instead.
+	if mode := groupMemberAddModeChange(evt); mode != "" {
+		metadata.MemberAddMode = string(mode)
+	}
+	if evt.Link != nil && evt.Link.Type == types.GroupLinkChangeTypeParent {
+		metadata.LinkedParentJID = evt.Link.Group.JID.String()
+	}
+	if evt.Unlink != nil && evt.Unlink.Type == types.GroupLinkChangeTypeParent {
+		metadata.LinkedParentJID = ""
+	}
+
+	for _, jid := range evt.Join {
+		if findCachedParticipant(ctx, metadata, jid, client) >= 0 {
+			continue
+		}
+		participant := domainChatStorage.GroupMetadataParticipant{JID: jid.ToNonAD().String()}
+		if jid.Server == types.HiddenUserServer {
+			participant.LID = participant.JID
+			if pn := NormalizeJIDFromLID(ctx, jid, client); pn.Server == types.DefaultUserServer {
+				participant.PhoneNumber = pn.ToNonAD().String()
+			}
+		} else {
+			participant.PhoneNumber = participant.JID
+		}
+		metadata.Participants = append(metadata.Participants, participant)
+	}
+	for _, jid := range evt.Leave {
+		if i := findCachedParticipant(ctx, metadata, jid, client); i >= 0 {
+			metadata.Participants = append(metadata.Participants[:i], metadata.Participants[i+1:]...)
+		}
+	}
+	for _, jid := range evt.Promote {
+		if i := findCachedParticipant(ctx, metadata, jid, client); i >= 0 {
+			metadata.Participants[i].IsAdmin = true
+		}
+	}
+	for _, jid := range evt.Demote {
+		if i := findCachedParticipant(ctx, metadata, jid, client); i >= 0 {
+			metadata.Participants[i].IsAdmin = false
+			metadata.Participants[i].IsSuperAdmin = false
+		}
+	}
+}
+
+// findCachedParticipant returns the index of jid among the cached participants,
+// matching on any of its JID, phone number or LID, or -1.
+func findCachedParticipant(ctx context.Context, metadata *domainChatStorage.GroupMetadata, jid types.JID, client *whatsmeow.Client) int {
+	candidates := map[string]struct{}{jid.ToNonAD().String(): {}}
+	if jid.Server == types.HiddenUserServer {
+		candidates[NormalizeJIDFromLID(ctx, jid, client).ToNonAD().String()] = struct{}{}
+	}
+	for i, participant := range metadata.Participants {
+		for _, known := range []string{participant.JID, participant.PhoneNumber, participant.LID} {
+			if _, ok := candidates[known]; ok && known != "" {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,248 @@
+package whatsapp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+type groupCacheRepoSpy struct {
+	domainChatStorage.IChatStorageRepository
+	metadata *domainChatStorage.GroupMetadata
+	history  []*domainChatStorage.GroupParticipantEvent
+	topics   []*domainChatStorage.GroupDescriptionEvent
+}
+
+func (r *groupCacheRepoSpy) GetGroupMetadata(deviceID, groupJID string) (*domainChatStorage.GroupMetadata, error) {
+	if r.metadata == nil || r.metadata.DeviceID != deviceID || r.metadata.GroupJID != groupJID {
+		return nil, nil
+	}
+	return r.metadata, nil
+}
+
+func (r *groupCacheRepoSpy) SaveGroupMetadata(metadata *domainChatStorage.GroupMetadata) error {
+	r.metadata = metadata
+	return nil
+}
+
+func (r *groupCacheRepoSpy) DeleteGroupMetadata(deviceID, groupJID string) error {
+	if r.metadata != nil && r.metadata.DeviceID == deviceID && r.metadata.GroupJID == groupJID {
+		r.metadata = nil
+	}
+	return nil
+}
+
+func (r *groupCacheRepoSpy) StoreGroupParticipantEvents(events []*domainChatStorage.GroupParticipantEvent) error {
+	r.history = append(r.history, events...)
+	return nil
+}
+
+func (r *groupCacheRepoSpy) StoreGroupDescriptionEvent(event *domainChatStorage.GroupDescriptionEvent) error {
+	r.topics = append(r.topics, event)
+	return nil
+}
+
+func TestGroupMetadataRoundTripsGroupInfo(t *testing.T) {
+	info := &types.GroupInfo{
+		JID:            types.NewJID("120363402106000000", types.GroupServer),
+		OwnerJID:       types.NewJID("6289685000001", types.DefaultUserServer),
+		GroupName:      types.GroupName{Name: "Moderators"},
+		GroupTopic:     types.GroupTopic{Topic: "Rules"},
+		GroupLocked:    types.GroupLocked{IsLocked: true},
+		GroupEphemeral: types.GroupEphemeral{IsEphemeral: true, DisappearingTimer: 604800},
+		GroupCreated:   time.Date(2025, time.May, 4, 10, 0, 0, 0, time.UTC),
+		MemberAddMode:  types.GroupMemberAddModeAdmin,
+		Participants: []types.GroupParticipant{{
+			JID:          types.NewJID("6289685000001", types.DefaultUserServer),
+			PhoneNumber:  types.NewJID("6289685000001", types.DefaultUserServer),
+			LID:          types.NewJID("223754944819424", types.HiddenUserServer),
+			IsAdmin:      true,
+			IsSuperAdmin: true,
+		}},
+	}
+
+	metadata := GroupMetadataFromInfo("dev-1", info)
+	if metadata.GroupJID != "120363402106000000@g.us" || metadata.Participants[0].LID != "223754944819424@lid" {
+		t.Fatalf("unexpected metadata %#v", metadata)
+	}
+
+	got := GroupInfoFromMetadata(metadata)
+	info.ParticipantCount = 1
+	if !reflect.DeepEqual(got, info) {
+		t.Fatalf("round trip = %#v, want %#v", got, info)
+	}
+}
+
+func TestCacheGroupInfoEventPatchesCachedGroupAndRecordsHistory(t *testing.T) {
+	groupJID := types.NewJID("120363402106000000", types.GroupServer)
+	admin := types.NewJID("6289685000001", types.DefaultUserServer)
+	member := types.NewJID("6289685000002", types.DefaultUserServer)
+	newcomer := types.NewJID("6289685000003", types.DefaultUserServer)
+	repo := &groupCacheRepoSpy{metadata: &domainChatStorage.GroupMetadata{
+		DeviceID: "dev-1",
+		GroupJID: groupJID.String(),
+		Name:     "Old",
+		Participants: []domainChatStorage.GroupMetadataParticipant{
+			{JID: admin.String(), PhoneNumber: admin.String(), IsAdmin: true, IsSuperAdmin: true},
+			{JID: member.String(), PhoneNumber: member.String()},
+		},
+	}}
+	timestamp := time.Date(2026, time.March, 1, 9, 30, 0, 0, time.UTC)
+
+	cacheGroupInfoEvent(context.Background(), &events.GroupInfo{
+		JID:       groupJID,
+		Sender:    &admin,
+		Timestamp: timestamp,
+		Name:      &types.GroupName{Name: "New"},
+		Join:      []types.JID{newcomer},
+		Promote:   []types.JID{member},
+		Ephemeral: &types.GroupEphemeral{IsEphemeral: true, DisappearingTimer: 86400},
+	}, repo, "dev-1", nil)
+
+	want := []domainChatStorage.GroupMetadataParticipant{
+		{JID: admin.String(), PhoneNumber: admin.String(), IsAdmin: true, IsSuperAdmin: true},
+		{JID: member.String(), PhoneNumber: member.String(), IsAdmin: true},
+		{JID: newcomer.String(), PhoneNumber: newcomer.String()},
+	}
+	if repo.metadata.Name != "New" || repo.metadata.EphemeralSeconds != 86400 || !reflect.DeepEqual(repo.metadata.Participants, want) {
+		t.Fatalf("cached group = %#v", repo.metadata)
+	}
+
+	if len(repo.history) != 2 {
+		t.Fatalf("history = %#v, want join and promote", repo.history)
+	}
+	join := repo.history[0]
+	if join.Action != "join" || join.ParticipantJID != newcomer.String() || join.ActorJID != admin.String() || !join.CreatedAt.Equal(timestamp) {
+		t.Fatalf("join = %#v", join)
+	}
+	if repo.history[1].Action != "promote" || repo.history[1].ParticipantJID != member.String() {
+		t.Fatalf("promote = %#v", repo.history[1])
+	}
+
+	cacheGroupInfoEvent(context.Background(), &events.GroupInfo{
+		JID:    groupJID,
+		Leave:  []types.JID{member},
+		Demote: []types.JID{admin},
+	}, repo, "dev-1", nil)
+
+	want = []domainChatStorage.GroupMetadataParticipant{
+		{JID: admin.String(), PhoneNumber: admin.String()},
+		{JID: newcomer.String(), PhoneNumber: newcomer.String()},
+	}
+	if !reflect.DeepEqual(repo.metadata.Participants, want) {
+		t.Fatalf("participants = %#v, want %#v", repo.metadata.Participants, want)
+	}
+	if got := repo.history[2]; got.Action != "leave" || got.ActorJID != "" {
+		t.Fatalf("leave = %#v, want a server-side leave", got)
+	}
+}
+
+func TestCacheGroupInfoEventRecordsHistoryForUncachedGroup(t *testing.T) {
+	repo := &groupCacheRepoSpy{}
+
+	cacheGroupInfoEvent(context.Background(), &events.GroupInfo{
+		JID:  types.NewJID("120363402106000000", types.GroupServer),
+		Join: []types.JID{types.NewJID("6289685000003", types.DefaultUserServer)},
+	}, repo, "dev-1", nil)
+
+	if repo.metadata != nil {
+		t.Fatalf("an uncached group must not be cached from a partial event: %#v", repo.metadata)
+	}
+	if len(repo.history) != 1 || repo.history[0].DeviceID != "dev-1" {
+		t.Fatalf("history = %#v", repo.history)
+	}
+}
+
+func TestCacheGroupInfoEventRecordsDescriptionHistory(t *testing.T) {
+	groupJID := types.NewJID("120363402106000000", types.GroupServer)
+	admin := types.NewJID("6289685000001", types.DefaultUserServer)
+	repo := &groupCacheRepoSpy{metadata: &domainChatStorage.GroupMetadata{DeviceID: "dev-1", GroupJID: groupJID.String(), Topic: "Old rules"}}
+	timestamp := time.Date(2026, time.March, 1, 9, 30, 0, 0, time.UTC)
+
+	cacheGroupInfoEvent(context.Background(), &events.GroupInfo{
+		JID:       groupJID,
+		Sender:    &admin,
+		Timestamp: timestamp,
+		Topic:     &types.GroupTopic{Topic: "New rules", TopicID: "T2"},
+	}, repo, "dev-1", nil)
+	cacheGroupInfoEvent(context.Background(), &events.GroupInfo{
+		JID:   groupJID,
+		Topic: &types.GroupTopic{Topic: "New rules", TopicID: "T3", TopicDeleted: true},
+	}, repo, "dev-1", nil)
+
+	if len(repo.topics) != 2 {
+		t.Fatalf("description history = %#v, want two changes", repo.topics)
+	}
+	set := repo.topics[0]
+	if set.Description != "New rules" || set.TopicID != "T2" || set.Deleted || set.ActorJID != admin.String() || !set.CreatedAt.Equal(timestamp) {
+		t.Fatalf("description change = %#v", set)
+	}
+	if cleared := repo.topics[1]; !cleared.Deleted || cleared.Description != "" || cleared.ActorJID != "" {
+		t.Fatalf("description removal = %#v", cleared)
+	}
+	if repo.metadata.Topic != "" {
+		t.Fatalf("cached topic = %q, want it cleared", repo.metadata.Topic)
+	}
+}
+
+func TestCacheGroupInfoEventLeavesJoinApprovalToTheLiveState(t *testing.T) {
+	groupJID := types.NewJID("120363402106000000", types.GroupServer)
+	repo := &groupCacheRepoSpy{metadata: &domainChatStorage.GroupMetadata{DeviceID: "dev-1", GroupJID: groupJID.String(), JoinApproval: true}}
+
+	// whatsmeow reports every membership_approval_mode change as "required",
+	// so switching it off arrives as true; neither value may be trusted.
+	evt := &events.GroupInfo{
+		JID:                    groupJID,
+		MembershipApprovalMode: &types.GroupMembershipApprovalMode{IsJoinApprovalRequired: false},
+	}
+	cacheGroupInfoEvent(context.Background(), evt, repo, "dev-1", nil)
+	if !repo.metadata.JoinApproval {
+		t.Fatal("the cached join approval must only change from a live snapshot")
+	}
+	if resolveGroupJoinApproval(context.Background(), evt, repo, "dev-1", nil) {
+		t.Fatal("without a client the state cannot be resolved")
+	}
+}
+
+func TestCacheGroupInfoEventDropsGroupThisDeviceLeft(t *testing.T) {
+	groupJID := types.NewJID("120363402106000000", types.GroupServer)
+	own := types.NewJID("6289685000009", types.DefaultUserServer)
+	client := whatsmeow.NewClient(&store.Device{ID: &own}, waLog.Noop)
+	repo := &groupCacheRepoSpy{metadata: &domainChatStorage.GroupMetadata{DeviceID: "dev-1", GroupJID: groupJID.String(), UpdatedAt: time.Now()}}
+
+	cacheGroupInfoEvent(context.Background(), &events.GroupInfo{
+		JID:   groupJID,
+		Leave: []types.JID{types.NewJID("6289685000002", types.DefaultUserServer)},
+	}, repo, "dev-1", client)
+	if repo.metadata == nil {
+		t.Fatal("another participant leaving must not drop the cached group")
+	}
+
+	cacheGroupInfoEvent(context.Background(), &events.GroupInfo{JID: groupJID, Leave: []types.JID{own}}, repo, "dev-1", client)
+	if repo.metadata != nil {
+		t.Fatalf("cached group = %#v, want it dropped after this device left", repo.metadata)
+	}
+	if len(repo.history) != 2 {
+		t.Fatalf("history = %#v, want both leaves recorded", repo.history)
+	}
+}
+
+func TestGroupMetadataFreshExpires(t *testing.T) {
+	if GroupMetadataFresh(nil) {
+		t.Fatal("a missing snapshot is not fresh")
+	}
+	if !GroupMetadataFresh(&domainChatStorage.GroupMetadata{UpdatedAt: time.Now().Add(-time.Minute)}) {
+		t.Fatal("a recent snapshot should be fresh")
+	}
+	if GroupMetadataFresh(&domainChatStorage.GroupMetadata{UpdatedAt: time.Now().Add(-GroupMetadataTTL - time.Minute)}) {
+		t.Fatal("an old snapshot should expire")
+	}
+}
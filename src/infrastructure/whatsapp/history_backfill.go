@@ -0,0 +1,302 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/websocket"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	HistoryBackfillRequested  = "requested"
+	HistoryBackfillInProgress = "in_progress"
+	HistoryBackfillCompleted  = "completed"
+	HistoryBackfillTimedOut   = "timed_out"
+)
+
+const (
+	// historyBackfillTimeout is how long the phone gets to answer before the
+	// request is reported as timed out and the chat may be asked again.
+	historyBackfillTimeout = 2 * time.Minute
+	// historyBackfillCooldown spaces out requests for the same chat, so a
+	// client retrying in a loop cannot flood the phone.
+	historyBackfillCooldown = 30 * time.Second
+	// historyBackfillMaxPending caps outstanding requests per device.
+	historyBackfillMaxPending = 3
+	// historyBackfillSettle is how long after a chunk below 100% the request
+	// is considered answered when no further chunk arrives.
+	historyBackfillSettle = 15 * time.Second
+	// historyBackfillRetention is how long finished requests are remembered
+	// (for the cooldown) before they are pruned.
+	historyBackfillRetention = 10 * time.Minute
+)
+
+// HistoryBackfill is one on-demand request for older messages of a chat,
+// answered by the phone with an ON_DEMAND history sync.
+type HistoryBackfill struct {
+	RequestID       string     `json:"request_id"`
+	DeviceID        string     `json:"device_id"`
+	ChatJID         string     `json:"chat_jid"`
+	BeforeMessageID string     `json:"before_message_id"`
+	Count           int        `json:"count"`
+	Status          string     `json:"status"`
+	Messages        int        `json:"messages"`
+	Progress        int        `json:"progress,omitempty"`
+	RequestedAt     time.Time  `json:"requested_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+
+	chunks int
+}
+
+func (b *HistoryBackfill) pending() bool {
+	return b.Status == HistoryBackfillRequested || b.Status == HistoryBackfillInProgress
+}
+
+// historyBackfillTracker remembers the latest request per device and chat.
+type historyBackfillTracker struct {
+	mu       sync.Mutex
+	requests map[string]*HistoryBackfill
+	now      func() time.Time
+}
+
+var historyBackfills = newHistoryBackfillTracker()
+
+// notifyHistoryBackfillFn is swapped in tests to capture notifications.
+var notifyHistoryBackfillFn = notifyHistoryBackfill
+
+func historyBackfillKey(deviceID, chatJID string) string {
+	return deviceID + "|" + chatJID
+}
+
+func newHistoryBackfillTracker() *historyBackfillTracker {
+	return &historyBackfillTracker{requests: make(map[string]*HistoryBackfill), now: time.Now}
+}
+
+// reserve claims the chat for a new request, enforcing the guardrails.
+func (t *historyBackfillTracker) reserve(deviceID, chatJID, beforeMessageID string, count int) (*HistoryBackfill, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	pendingForDevice := 0
+	for key, backfill := range t.requests {
+		if !backfill.pending() && backfill.CompletedAt != nil && now.Sub(*backfill.CompletedAt) > historyBackfillRetention {
+			delete(t.requests, key)
+			continue
+		}
+		if backfill.DeviceID == deviceID && backfill.pending() {
+			pendingForDevice++
+		}
+	}
+
+	if last, ok := t.requests[historyBackfillKey(deviceID, chatJID)]; ok {
+		if last.pending() {
+			return nil, pkgError.WaHistoryBackfillLimitError(fmt.Sprintf("a history request for %s is already pending", chatJID))
+		}
+		if wait := last.RequestedAt.Add(historyBackfillCooldown).Sub(now); wait > 0 {
+			return nil, pkgError.WaHistoryBackfillLimitError(fmt.Sprintf("history for %s was requested recently, retry in %ds", chatJID, int(wait.Seconds())+1))
+		}
+	}
+	if pendingForDevice >= historyBackfillMaxPending {
+		return nil, pkgError.WaHistoryBackfillLimitError(fmt.Sprintf("at most %d history requests may be pending per device", historyBackfillMaxPending))
+	}
+
+	backfill := &HistoryBackfill{
+		DeviceID:        deviceID,
+		ChatJID:         chatJID,
+		BeforeMessageID: beforeMessageID,
+		Count:           count,
+		Status:          HistoryBackfillRequested,
+		RequestedAt:     now.UTC(),
+	}
+	t.requests[historyBackfillKey(deviceID, chatJID)] = backfill
+	return backfill, nil
+}
+
+// release drops a reservation whose request could not be sent.
+func (t *historyBackfillTracker) release(backfill *HistoryBackfill) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := historyBackfillKey(backfill.DeviceID, backfill.ChatJID)
+	if t.requests[key] == backfill {
+		delete(t.requests, key)
+	}
+}
+
+// record applies a received chunk to the pending request of the chat and
+// returns a snapshot to report, or nil when nothing was requested. Only a
+// chunk at 100% completes the request; phones often leave progress unset, so
+// a request still in progress is completed by settle once its chunks stop.
+func (t *historyBackfillTracker) record(deviceID, chatJID string, messages int, progress uint32) *HistoryBackfill {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	backfill, ok := t.requests[historyBackfillKey(deviceID, chatJID)]
+	if !ok || !backfill.pending() {
+		return nil
+	}
+	backfill.Messages += messages
+	backfill.Progress = int(progress)
+	backfill.chunks++
+	if progress >= 100 {
+		completedAt := t.now().UTC()
+		backfill.Status = HistoryBackfillCompleted
+		backfill.CompletedAt = &completedAt
+	} else {
+		backfill.Status = HistoryBackfillInProgress
+	}
+	snapshot := *backfill
+	return &snapshot
+}
+
+// settle completes a request whose last recorded chunk is still chunk, i.e.
+// no further chunk arrived since, and returns a snapshot to report.
+func (t *historyBackfillTracker) settle(deviceID, chatJID string, chunk int) *HistoryBackfill {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	backfill, ok := t.requests[historyBackfillKey(deviceID, chatJID)]
+	if !ok || backfill.Status != HistoryBackfillInProgress || backfill.chunks != chunk {
+		return nil
+	}
+	completedAt := t.now().UTC()
+	backfill.Status = HistoryBackfillCompleted
+	backfill.CompletedAt = &completedAt
+	snapshot := *backfill
+	return &snapshot
+}
+
+// expire marks the request timed out if the phone never answered.
+func (t *historyBackfillTracker) expire(backfill *HistoryBackfill) *HistoryBackfill {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.requests[historyBackfillKey(backfill.DeviceID, backfill.ChatJID)] != backfill || !backfill.pending() {
+		return nil
+	}
+	completedAt := t.now().UTC()
+	backfill.Status = HistoryBackfillTimedOut
+	backfill.CompletedAt = &completedAt
+	snapshot := *backfill
+	return &snapshot
+}
+
+// RequestHistoryBackfill asks the primary phone for up to count messages of
+// anchor's chat older than anchor. Results arrive later as an ON_DEMAND history
+// sync; progress is reported through the history.backfill webhook and the
+// HISTORY_BACKFILL WebSocket message.
+func RequestHistoryBackfill(ctx context.Context, client *whatsmeow.Client, deviceID string, anchor *types.MessageInfo, count int) (*HistoryBackfill, error) {
+	chatJID := anchor.Chat.ToNonAD().String()
+	backfill, err := historyBackfills.reserve(deviceID, chatJID, anchor.ID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.SendPeerMessage(ctx, client.BuildHistorySyncRequest(anchor, count))
+	if err != nil {
+		historyBackfills.release(backfill)
+		return nil, err
+	}
+
+	historyBackfills.mu.Lock()
+	backfill.RequestID = resp.ID
+	snapshot := *backfill
+	historyBackfills.mu.Unlock()
+
+	time.AfterFunc(historyBackfillTimeout, func() {
+		if expired := historyBackfills.expire(backfill); expired != nil {
+			logrus.Warnf("History request %s for %s timed out", expired.RequestID, expired.ChatJID)
+			notifyHistoryBackfillFn(context.Background(), expired)
+		}
+	})
+	notifyHistoryBackfillFn(ctx, &snapshot)
+	return &snapshot, nil
+}
+
+// completeHistoryBackfills reports the chats of an ON_DEMAND history sync to
+// the requests that asked for them.
+func completeHistoryBackfills(ctx context.Context, data *waHistorySync.HistorySync, deviceID string, client *whatsmeow.Client) {
+	for _, conv := range data.GetConversations() {
+		jid, err := types.ParseJID(conv.GetID())
+		if err != nil {
+			continue
+		}
+		chatJID := NormalizeJIDFromLID(ctx, jid, client).ToNonAD().String()
+		backfill := historyBackfills.record(deviceID, chatJID, len(conv.GetMessages()), data.GetProgress())
+		if backfill == nil {
+			continue
+		}
+		logrus.Infof("History request %s for %s: %s with %d messages", backfill.RequestID, chatJID, backfill.Status, backfill.Messages)
+		notifyHistoryBackfillFn(ctx, backfill)
+
+		if backfill.Status == HistoryBackfillInProgress {
+			tracker, chunk := historyBackfills, backfill.chunks
+			time.AfterFunc(historyBackfillSettle, func() {
+				if settled := tracker.settle(deviceID, chatJID, chunk); settled != nil {
+					logrus.Infof("History request %s for %s: completed with %d messages", settled.RequestID, chatJID, settled.Messages)
+					notifyHistoryBackfillFn(context.WithoutCancel(ctx), settled)
+				}
+			})
+		}
+	}
+}
+
+// createHistoryBackfillPayload creates the history.backfill webhook payload.
+func createHistoryBackfillPayload(backfill *HistoryBackfill) map[string]any {
+	payload := map[string]any{
+		"request_id":        backfill.RequestID,
+		"chat_id":           backfill.ChatJID,
+		"before_message_id": backfill.BeforeMessageID,
+		"count":             backfill.Count,
+		"status":            backfill.Status,
+		"messages":          backfill.Messages,
+		"requested_at":      backfill.RequestedAt.Format(time.RFC3339),
+	}
+	if backfill.Progress > 0 {
+		payload["progress"] = backfill.Progress
+	}
+	if backfill.CompletedAt != nil {
+		payload["completed_at"] = backfill.CompletedAt.Format(time.RFC3339)
+	}
+
+	body := map[string]any{
+		"event":     "history.backfill",
+		"timestamp": time.Now().Format(time.RFC3339),
+		"payload":   payload,
+	}
+	if backfill.DeviceID != "" {
+		body["device_id"] = backfill.DeviceID
+	}
+	if sessionID := sessionIDForJIDFn(backfill.DeviceID); sessionID != "" {
+		payload["session_id"] = sessionID
+		body["session_id"] = sessionID
+	}
+	return body
+}
+
+// notifyHistoryBackfill broadcasts the request state to WebSocket clients and
+// forwards it to the configured webhooks.
+func notifyHistoryBackfill(ctx context.Context, backfill *HistoryBackfill) {
+	go func(snapshot HistoryBackfill) {
+		websocket.Broadcast <- websocket.BroadcastMessage{
+			Code:    "HISTORY_BACKFILL",
+			Message: fmt.Sprintf("History request for %s %s", snapshot.ChatJID, snapshot.Status),
+			Result:  snapshot,
+		}
+	}(*backfill)
+
+	go func(body map[string]any) {
+		webhookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := forwardPayloadToConfiguredWebhooks(webhookCtx, body, "history.backfill"); err != nil {
+			logrus.Errorf("Failed to forward history backfill event to webhook: %v", err)
+		}
+	}(createHistoryBackfillPayload(backfill))
+}
@@ -0,0 +1,162 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHistoryBackfillGuardrails(t *testing.T) {
+	now := time.Date(2026, time.October, 6, 9, 0, 0, 0, time.UTC)
+	tracker := newHistoryBackfillTracker()
+	tracker.now = func() time.Time { return now }
+
+	first, err := tracker.reserve("dev-1", "628111@s.whatsapp.net", "MSG1", 50)
+	if err != nil {
+		t.Fatalf("first reserve failed: %v", err)
+	}
+
+	var limited pkgError.WaHistoryBackfillLimitError
+	if _, err := tracker.reserve("dev-1", "628111@s.whatsapp.net", "MSG1", 50); !errors.As(err, &limited) {
+		t.Fatalf("pending chat reserve err = %v, want a limit error", err)
+	}
+
+	// The phone answered without progress: the request completes once no
+	// further chunk follows, and the chat stays in cooldown for a while.
+	got := tracker.record("dev-1", "628111@s.whatsapp.net", 12, 0)
+	if got == nil || got.Status != HistoryBackfillInProgress || got.Messages != 12 {
+		t.Fatalf("record() = %+v", got)
+	}
+	if settled := tracker.settle("dev-1", "628111@s.whatsapp.net", got.chunks); settled == nil || settled.Status != HistoryBackfillCompleted {
+		t.Fatalf("settle() = %+v", settled)
+	}
+	if _, err := tracker.reserve("dev-1", "628111@s.whatsapp.net", "MSG0", 50); !errors.As(err, &limited) {
+		t.Fatalf("cooldown reserve err = %v, want a limit error", err)
+	}
+	now = now.Add(historyBackfillCooldown + time.Second)
+	if _, err := tracker.reserve("dev-1", "628111@s.whatsapp.net", "MSG0", 50); err != nil {
+		t.Fatalf("reserve after cooldown failed: %v", err)
+	}
+	if got := tracker.expire(first); got != nil {
+		t.Fatalf("a replaced request must not expire, got %+v", got)
+	}
+
+	// Only a few requests may be pending per device.
+	for _, chat := range []string{"628222@s.whatsapp.net", "628333@s.whatsapp.net"} {
+		if _, err := tracker.reserve("dev-1", chat, "X", 50); err != nil {
+			t.Fatalf("reserve %s failed: %v", chat, err)
+		}
+	}
+	if _, err := tracker.reserve("dev-1", "628444@s.whatsapp.net", "X", 50); !errors.As(err, &limited) {
+		t.Fatalf("over-limit reserve err = %v, want a limit error", err)
+	}
+	if _, err := tracker.reserve("dev-2", "628444@s.whatsapp.net", "X", 50); err != nil {
+		t.Fatalf("other devices are not limited: %v", err)
+	}
+}
+
+func TestHistoryBackfillSettleWaitsForTheLastChunk(t *testing.T) {
+	now := time.Date(2026, time.October, 6, 9, 0, 0, 0, time.UTC)
+	tracker := newHistoryBackfillTracker()
+	tracker.now = func() time.Time { return now }
+
+	if _, err := tracker.reserve("dev-1", "628111@s.whatsapp.net", "MSG1", 50); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	first := tracker.record("dev-1", "628111@s.whatsapp.net", 20, 0)
+	tracker.record("dev-1", "628111@s.whatsapp.net", 20, 0)
+	if settled := tracker.settle("dev-1", "628111@s.whatsapp.net", first.chunks); settled != nil {
+		t.Fatalf("a request that received a later chunk must not settle, got %+v", settled)
+	}
+	if done := tracker.record("dev-1", "628111@s.whatsapp.net", 10, 100); done.Status != HistoryBackfillCompleted || done.Messages != 50 {
+		t.Fatalf("record() at 100%% = %+v", done)
+	}
+
+	// Finished requests are pruned once they are past the retention.
+	now = now.Add(historyBackfillRetention + time.Minute)
+	if _, err := tracker.reserve("dev-1", "628222@s.whatsapp.net", "X", 50); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	if _, ok := tracker.requests[historyBackfillKey("dev-1", "628111@s.whatsapp.net")]; ok {
+		t.Fatal("expected the finished request to be pruned")
+	}
+}
+
+func TestHistoryBackfillExpireAndRelease(t *testing.T) {
+	tracker := newHistoryBackfillTracker()
+
+	backfill, err := tracker.reserve("dev-1", "628111@s.whatsapp.net", "MSG1", 50)
+	if err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+	if got := tracker.expire(backfill); got == nil || got.Status != HistoryBackfillTimedOut || got.CompletedAt == nil {
+		t.Fatalf("expire() = %+v", got)
+	}
+	if got := tracker.record("dev-1", "628111@s.whatsapp.net", 5, 0); got != nil {
+		t.Fatalf("late answers are not reported, got %+v", got)
+	}
+
+	// A request that could not be sent frees the chat immediately.
+	tracker.requests = make(map[string]*HistoryBackfill)
+	backfill, _ = tracker.reserve("dev-1", "628111@s.whatsapp.net", "MSG1", 50)
+	tracker.release(backfill)
+	if _, err := tracker.reserve("dev-1", "628111@s.whatsapp.net", "MSG1", 50); err != nil {
+		t.Fatalf("reserve after release failed: %v", err)
+	}
+}
+
+func TestCompleteHistoryBackfillsNotifiesRequestedChats(t *testing.T) {
+	originalTracker := historyBackfills
+	originalNotify := notifyHistoryBackfillFn
+	originalSessionID := sessionIDForJIDFn
+	defer func() {
+		historyBackfills = originalTracker
+		notifyHistoryBackfillFn = originalNotify
+		sessionIDForJIDFn = originalSessionID
+	}()
+	sessionIDForJIDFn = func(string) string { return "sales" }
+	historyBackfills = newHistoryBackfillTracker()
+	var notified []HistoryBackfill
+	notifyHistoryBackfillFn = func(_ context.Context, backfill *HistoryBackfill) {
+		notified = append(notified, *backfill)
+	}
+
+	if _, err := historyBackfills.reserve("dev-1", "628111@s.whatsapp.net", "MSG1", 50); err != nil {
+		t.Fatalf("reserve failed: %v", err)
+	}
+
+	data := &waHistorySync.HistorySync{
+		SyncType: waHistorySync.HistorySync_ON_DEMAND.Enum(),
+		Progress: proto.Uint32(40),
+		Conversations: []*waHistorySync.Conversation{
+			{ID: proto.String("628111@s.whatsapp.net"), Messages: make([]*waHistorySync.HistorySyncMsg, 3)},
+			{ID: proto.String("628999@s.whatsapp.net"), Messages: make([]*waHistorySync.HistorySyncMsg, 7)},
+		},
+	}
+	completeHistoryBackfills(context.Background(), data, "dev-1", nil)
+	data.Progress = proto.Uint32(100)
+	completeHistoryBackfills(context.Background(), data, "dev-1", nil)
+
+	if len(notified) != 2 {
+		t.Fatalf("notified %d times, want 2 (unrequested chats are ignored)", len(notified))
+	}
+	if notified[0].Status != HistoryBackfillInProgress || notified[0].Progress != 40 {
+		t.Fatalf("first notification %+v", notified[0])
+	}
+	if notified[1].Status != HistoryBackfillCompleted || notified[1].Messages != 6 {
+		t.Fatalf("second notification %+v", notified[1])
+	}
+
+	payload := createHistoryBackfillPayload(&notified[1])
+	if payload["event"] != "history.backfill" || payload["device_id"] != "dev-1" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if inner := payload["payload"].(map[string]any); inner["status"] != HistoryBackfillCompleted || inner["messages"] != 6 || inner["session_id"] != "sales" {
+		t.Fatalf("unexpected inner payload %+v", inner)
+	}
+}
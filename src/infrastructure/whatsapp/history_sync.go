@@ -70,6 +70,11 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 	case waHistorySync.HistorySync_INITIAL_BOOTSTRAP, waHistorySync.HistorySync_RECENT:
 		// Process conversation messages
 		return processConversationMessages(ctx, data, chatStorageRepo, client)
+	case waHistorySync.HistorySync_ON_DEMAND:
+		// Older messages requested through RequestHistoryBackfill
+		err := processConversationMessages(ctx, data, chatStorageRepo, client)
+		completeHistoryBackfills(ctx, data, historySyncDeviceID(ctx, client), client)
+		return err
 	case waHistorySync.HistorySync_PUSH_NAME:
 		// Process push names to update chat names
 		return processPushNames(ctx, data, chatStorageRepo, client)
@@ -80,13 +85,10 @@ func processHistorySync(ctx context.Context, data *waHistorySync.HistorySync, ch
 	}
 }
 
-// processConversationMessages processes and stores conversation messages from history sync
-func processConversationMessages(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) error {
-	conversations := data.GetConversations()
-	log.Infof("Processing %d conversations from history sync", len(conversations))
-
-	// Prioritize device JID from context (set by event handler with correct device instance)
-	// over client.Store.ID which may point to a different device in multi-device scenarios
+// historySyncDeviceID prioritizes the device JID from context (set by the event
+// handler with the correct device instance) over client.Store.ID, which may
+// point to a different device in multi-device scenarios.
+func historySyncDeviceID(ctx context.Context, client *whatsmeow.Client) string {
 	deviceID := ""
 	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
 		deviceID = inst.JID()
@@ -97,6 +99,15 @@ func processConversationMessages(ctx context.Context, data *waHistorySync.Histor
 	if deviceID == "" && client != nil && client.Store != nil && client.Store.ID != nil {
 		deviceID = client.Store.ID.ToNonAD().String()
 	}
+	return deviceID
+}
+
+// processConversationMessages processes and stores conversation messages from history sync
+func processConversationMessages(ctx context.Context, data *waHistorySync.HistorySync, chatStorageRepo domainChatStorage.IChatStorageRepository, client *whatsmeow.Client) error {
+	conversations := data.GetConversations()
+	log.Infof("Processing %d conversations from history sync", len(conversations))
+
+	deviceID := historySyncDeviceID(ctx, client)
 
 	for _, conv := range conversations {
 		rawChatJID := conv.GetID()
@@ -307,17 +318,7 @@ func processPushNames(ctx context.Context, data *waHistorySync.HistorySync, chat
 	pushnames := data.GetPushnames()
 	log.Infof("Processing %d push names from history sync", len(pushnames))
 
-	// Extract device ID from context (same pattern as processConversationMessages)
-	deviceID := ""
-	if inst, ok := DeviceFromContext(ctx); ok && inst != nil {
-		deviceID = inst.JID()
-		if deviceID == "" {
-			deviceID = inst.ID()
-		}
-	}
-	if deviceID == "" && client != nil && client.Store != nil && client.Store.ID != nil {
-		deviceID = client.Store.ID.ToNonAD().String()
-	}
+	deviceID := historySyncDeviceID(ctx, client)
 
 	for _, pushname := range pushnames {
 		rawJIDStr := pushname.GetID()
@@ -336,6 +337,8 @@ func processPushNames(ctx context.Context, data *waHistorySync.HistorySync, chat
 		jid = NormalizeJIDFromLID(ctx, jid, client)
 		jidStr := jid.String()
 
+		RecordContact(ctx, chatStorageRepo, client, deviceID, jid, domainChatStorage.Contact{PushName: name})
+
 		// Check if chat exists (device-scoped to avoid cross-device data leak)
 		existingChat, err := chatStorageRepo.GetChatByDevice(deviceID, jidStr)
 		if err != nil {
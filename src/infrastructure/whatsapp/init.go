@@ -105,22 +105,24 @@ func InitWaCLI(ctx context.Context, storeContainer, keysStoreContainer *sqlstore
 	// Create and configure the client with filtered logging to avoid noisy reconnection EOF errors
 	baseLogger := waLog.Stdout("Client", config.WhatsappLogLevel, true)
 	client := whatsmeow.NewClient(device, newFilteredLogger(baseLogger))
-	if proxyURL := config.WhatsappProxy; proxyURL != "" {
-		if err := client.SetProxyAddress(proxyURL); err != nil {
-			baseLogger.Errorf("failed to apply WHATSAPP_PROXY=%q: %v", redactProxyURL(proxyURL), err)
-		} else {
-			baseLogger.Infof("applied outbound proxy from WHATSAPP_PROXY")
-		}
-	}
 	client.EnableAutoReconnect = true
 	client.AutoTrustIdentity = true
 
 	deviceRepo := newDeviceChatStorage(instanceID, chatStorageRepo)
 	instance := NewDeviceInstance(instanceID, client, deviceRepo)
 
+	var proxyConfig *domainChatStorage.DeviceProxyConfig
+	if instanceID != "" {
+		if proxyConfig, err = chatStorageRepo.GetDeviceProxyConfig(instanceID); err != nil {
+			log.Warnf("Failed to load proxy config for device %s: %v", instanceID, err)
+		}
+	}
+	applyProxy(client, instance, proxyConfig, baseLogger)
+
 	client.AddEventHandler(func(rawEvt any) {
 		handler(ctx, instance, rawEvt)
 	})
+	installReconnectHook(client, instance)
 
 	// Register device instance in the manager for multi-device awareness
 	// Use EnsureDefault to avoid creating duplicates when a device with matching JID already exists
@@ -0,0 +1,655 @@
+package whatsapp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// LeaseCoordinator lets several replicas share one whatsmeow store without
+// connecting the same session twice. Each replica leases a fair share of the
+// paired sessions from the shared database, connects only those, renews the
+// leases every TTL/3 and takes over sessions whose lease expired because their
+// owner died. The slot id and per-device settings of every held session are
+// published next to the lease so the next owner serves it unchanged.
+type LeaseCoordinator struct {
+	store     *leaseStore
+	db        *sql.DB
+	dm        *DeviceManager
+	replicaID string
+	url       string
+	ttl       time.Duration
+
+	now        func(ctx context.Context) (time.Time, error) // database clock
+	monotonic  func() time.Time                             // local clock, only for elapsed time
+	sessions   func(ctx context.Context) (map[string]*DeviceInstance, error)
+	connected  func(inst *DeviceInstance) bool
+	connect    func(ctx context.Context, inst *DeviceInstance) error
+	disconnect func(inst *DeviceInstance, reason string)
+	settings   func(inst *DeviceInstance) ([]byte, error)
+	restore    func(inst *DeviceInstance, settings []byte) error
+
+	mu          sync.RWMutex
+	held        map[string]bool            // device keys this replica holds a lease for
+	instances   map[string]*DeviceInstance // slots of the held keys, to drop them when renewal fails
+	leases      []DeviceLease              // unexpired leases of all replicas at the last tick
+	yielded     map[string]time.Time       // device keys not to claim again before the given time
+	published   map[string]string          // settings last published per device key
+	connecting  map[string]bool            // device keys with a connect in flight
+	clockOffset time.Duration              // database clock minus local clock
+	renewedAt   time.Time                  // monotonic time of the last successful renewal
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	connects sync.WaitGroup
+}
+
+// LeaseStatus is a snapshot of the lease table as seen by one replica.
+type LeaseStatus struct {
+	ReplicaID string        `json:"replica_id"`
+	URL       string        `json:"url"`
+	LeaseTTL  string        `json:"lease_ttl"`
+	Leases    []DeviceLease `json:"leases"`
+}
+
+var (
+	leaseCoordinatorMu sync.RWMutex
+	leaseCoordinator   *LeaseCoordinator
+)
+
+// GetLeaseCoordinator returns the running coordinator, or nil outside HA mode.
+func GetLeaseCoordinator() *LeaseCoordinator {
+	leaseCoordinatorMu.RLock()
+	defer leaseCoordinatorMu.RUnlock()
+	return leaseCoordinator
+}
+
+// InitLeaseCoordinator opens the lease tables in the shared Postgres store
+// configured by DB_URI and returns a coordinator for this replica.
+func InitLeaseCoordinator(ctx context.Context, dm *DeviceManager) (*LeaseCoordinator, error) {
+	if dm == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+	dbURI := strings.Trim(config.DBURI, `"'`)
+	if !strings.HasPrefix(dbURI, "postgres:") {
+		return nil, fmt.Errorf("high availability needs a postgres DB_URI shared by all replicas")
+	}
+	if strings.TrimSpace(config.HAAdvertiseURL) == "" {
+		return nil, fmt.Errorf("HA_ADVERTISE_URL is required so other replicas can reach this one")
+	}
+	replicaID := strings.TrimSpace(config.HAReplicaID)
+	if replicaID == "" {
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			return nil, fmt.Errorf("HA_REPLICA_ID is required: hostname unavailable")
+		}
+		replicaID = hostname
+	}
+
+	db, err := sql.Open("postgres", dbURI)
+	if err != nil {
+		return nil, err
+	}
+	store, err := newLeaseStore(ctx, db)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	c := newLeaseCoordinator(store, dm, replicaID, config.HAAdvertiseURL, config.HALeaseTTL)
+	c.db = db
+	return c, nil
+}
+
+func newLeaseCoordinator(store *leaseStore, dm *DeviceManager, replicaID, url string, ttl time.Duration) *LeaseCoordinator {
+	c := &LeaseCoordinator{
+		store:      store,
+		dm:         dm,
+		replicaID:  replicaID,
+		url:        strings.TrimRight(strings.TrimSpace(url), "/"),
+		ttl:        ttl,
+		now:        store.now,
+		monotonic:  time.Now,
+		connected:  func(inst *DeviceInstance) bool { return inst.IsConnected() },
+		held:       make(map[string]bool),
+		instances:  make(map[string]*DeviceInstance),
+		yielded:    make(map[string]time.Time),
+		published:  make(map[string]string),
+		connecting: make(map[string]bool),
+		ctx:        context.Background(),
+	}
+	c.sessions = func(ctx context.Context) (map[string]*DeviceInstance, error) {
+		slotIDs, err := store.slotIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return dm.storeSessions(ctx, slotIDs)
+	}
+	c.connect = connectLeasedDevice
+	c.disconnect = disconnectLeasedDevice
+	c.settings = func(inst *DeviceInstance) ([]byte, error) {
+		settings, err := dm.deviceSettings(inst)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(settings)
+	}
+	c.restore = func(inst *DeviceInstance, data []byte) error {
+		var settings deviceBackup
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return err
+		}
+		return dm.restoreDeviceSettings(inst.ID(), settings)
+	}
+	return c
+}
+
+// ReplicaID returns the name this replica holds its leases under.
+func (c *LeaseCoordinator) ReplicaID() string {
+	return c.replicaID
+}
+
+// Start runs the first lease round right away and then renews every TTL/3.
+// It replaces the auto-connect on boot and the periodic reconnect check: a
+// replica only connects the sessions it leases.
+func (c *LeaseCoordinator) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.ctx = ctx
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	leaseCoordinatorMu.Lock()
+	leaseCoordinator = c
+	leaseCoordinatorMu.Unlock()
+
+	go func() {
+		defer close(c.done)
+		interval := c.ttl / 3
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			tickCtx, tickCancel := context.WithTimeout(ctx, interval)
+			if err := c.tick(tickCtx); err != nil {
+				logrus.WithError(err).Warn("[HA] lease round failed")
+			}
+			tickCancel()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logrus.Infof("[HA] replica %s started; lease ttl=%s url=%s", c.replicaID, c.ttl, c.url)
+}
+
+// Stop halts renewal, disconnects the leased sessions and releases their
+// leases so the remaining replicas take them over without waiting for expiry.
+func (c *LeaseCoordinator) Stop(ctx context.Context) error {
+	if c.cancel != nil {
+		c.cancel()
+		<-c.done
+	}
+	c.connects.Wait()
+
+	leaseCoordinatorMu.Lock()
+	if leaseCoordinator == c {
+		leaseCoordinator = nil
+	}
+	leaseCoordinatorMu.Unlock()
+
+	c.mu.Lock()
+	held := c.held
+	c.held = make(map[string]bool)
+	c.mu.Unlock()
+
+	if sessions, err := c.sessions(ctx); err == nil {
+		for key := range held {
+			if inst := sessions[key]; inst != nil {
+				c.disconnect(inst, "replica shutting down")
+			}
+		}
+	}
+
+	err := c.store.releaseAll(ctx, c.replicaID)
+	if c.db != nil {
+		if closeErr := c.db.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// tick is one lease round: heartbeat, renew, drop what was lost, claim up to
+// the fair share, hand back what exceeds it and connect everything held.
+func (c *LeaseCoordinator) tick(ctx context.Context) error {
+	now, err := c.now(ctx)
+	if err != nil {
+		c.renewalFailed()
+		return fmt.Errorf("read database clock: %w", err)
+	}
+	if err := c.store.heartbeat(ctx, c.replicaID, c.url, now); err != nil {
+		c.renewalFailed()
+		return fmt.Errorf("heartbeat: %w", err)
+	}
+	held, err := c.store.renew(ctx, c.replicaID, now, c.ttl)
+	if err != nil {
+		c.renewalFailed()
+		return fmt.Errorf("renew leases: %w", err)
+	}
+	renewedAt := c.monotonic()
+	c.mu.Lock()
+	c.renewedAt = renewedAt
+	c.clockOffset = now.Sub(renewedAt)
+	c.mu.Unlock()
+
+	sessions, err := c.sessions(ctx)
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+
+	c.mu.Lock()
+	previous := c.held
+	for key, until := range c.yielded {
+		if !now.Before(until) {
+			delete(c.yielded, key)
+		}
+	}
+	yielded := make(map[string]time.Time, len(c.yielded))
+	for key, until := range c.yielded {
+		yielded[key] = until
+	}
+	c.mu.Unlock()
+
+	// Leases that expired before we could renew them (e.g. a database outage
+	// longer than the TTL) may already belong to another replica.
+	for key := range previous {
+		if !held[key] {
+			if inst := sessions[key]; inst != nil && c.connected(inst) {
+				logrus.Warnf("[HA] lease of %s lost; disconnecting", key)
+				c.disconnect(inst, "device lease lost to another replica")
+			}
+		}
+	}
+
+	// Sessions that were logged out or purged no longer need a lease.
+	for key := range held {
+		if _, ok := sessions[key]; !ok {
+			if err := c.store.release(ctx, c.replicaID, key); err != nil {
+				return fmt.Errorf("release %s: %w", key, err)
+			}
+			if err := c.store.deleteSlot(ctx, key); err != nil {
+				return fmt.Errorf("forget slot of %s: %w", key, err)
+			}
+			delete(held, key)
+		}
+	}
+
+	keys := make([]string, 0, len(sessions))
+	for key := range sessions {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	claim := func(key string) (bool, error) {
+		inst := sessions[key]
+		return c.store.claim(ctx, DeviceLease{
+			DeviceKey: key,
+			DeviceID:  inst.ID(),
+			OwnerID:   c.replicaID,
+			OwnerURL:  c.url,
+			ExpiresAt: now.Add(c.ttl),
+		}, now)
+	}
+
+	// Sessions connected here without a lease (a fresh pairing through this
+	// replica) are kept when the lease is free and dropped otherwise.
+	for _, key := range keys {
+		inst := sessions[key]
+		if held[key] || !c.connected(inst) {
+			continue
+		}
+		ok, err := claim(key)
+		if err != nil {
+			return fmt.Errorf("claim %s: %w", key, err)
+		}
+		if ok {
+			held[key] = true
+			continue
+		}
+		logrus.Warnf("[HA] %s is leased by another replica; disconnecting local session", key)
+		c.disconnect(inst, "device leased by another replica")
+	}
+
+	live, err := c.store.liveReplicas(ctx, now.Add(-c.ttl))
+	if err != nil {
+		return fmt.Errorf("count replicas: %w", err)
+	}
+	share := fairShare(len(keys), live)
+
+	for _, key := range keys {
+		if len(held) >= share {
+			break
+		}
+		if held[key] {
+			continue
+		}
+		if until, ok := yielded[key]; ok && now.Before(until) {
+			continue
+		}
+		ok, err := claim(key)
+		if err != nil {
+			return fmt.Errorf("claim %s: %w", key, err)
+		}
+		if ok {
+			logrus.Infof("[HA] claimed %s", key)
+			held[key] = true
+		}
+	}
+
+	// Hand back what exceeds the share, e.g. after another replica joined.
+	if excess := len(held) - share; excess > 0 {
+		for i := len(keys) - 1; i >= 0 && excess > 0; i-- {
+			key := keys[i]
+			if !held[key] {
+				continue
+			}
+			c.disconnect(sessions[key], "device handed over to another replica")
+			if err := c.store.release(ctx, c.replicaID, key); err != nil {
+				return fmt.Errorf("release %s: %w", key, err)
+			}
+			logrus.Infof("[HA] handed %s back for rebalancing", key)
+			delete(held, key)
+			yielded[key] = now.Add(c.ttl)
+			excess--
+		}
+	}
+
+	// Sessions taken over from another replica get the slot settings their
+	// previous owner published before they connect; then every held session
+	// publishes its own settings whenever they changed.
+	for key := range held {
+		if previous[key] {
+			continue
+		}
+		if err := c.restoreSlot(ctx, key, sessions[key]); err != nil {
+			logrus.WithError(err).Warnf("[HA] restoring settings of %s failed", key)
+		}
+	}
+	for key := range held {
+		if err := c.publishSlot(ctx, key, sessions[key], now); err != nil {
+			logrus.WithError(err).Warnf("[HA] publishing settings of %s failed", key)
+		}
+	}
+
+	leases, err := c.store.activeLeases(ctx, now)
+	if err != nil {
+		return fmt.Errorf("list leases: %w", err)
+	}
+
+	instances := make(map[string]*DeviceInstance, len(held))
+	for key := range held {
+		instances[key] = sessions[key]
+	}
+	c.mu.Lock()
+	c.held = held
+	c.instances = instances
+	c.leases = leases
+	for key, until := range yielded {
+		c.yielded[key] = until
+	}
+	for key := range c.published {
+		if !held[key] {
+			delete(c.published, key)
+		}
+	}
+	c.mu.Unlock()
+
+	// Leases are renewed above; connecting happens outside the round, one
+	// goroutine per session, so a slow handshake cannot hold up the renewal of
+	// every other lease.
+	for key := range held {
+		if inst := sessions[key]; !c.connected(inst) {
+			c.connectAsync(key, inst)
+		}
+	}
+	return nil
+}
+
+// connectAsync connects a held session in the background unless a connect for
+// it is already in flight. A session whose lease was lost meanwhile is
+// disconnected again right away.
+func (c *LeaseCoordinator) connectAsync(key string, inst *DeviceInstance) {
+	c.mu.Lock()
+	if c.connecting[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.connecting[key] = true
+	c.mu.Unlock()
+
+	c.connects.Add(1)
+	go func() {
+		defer c.connects.Done()
+		err := c.connect(c.ctx, inst)
+
+		c.mu.Lock()
+		delete(c.connecting, key)
+		stillHeld := c.held[key]
+		c.mu.Unlock()
+
+		if err != nil {
+			logrus.WithError(err).Warnf("[HA] connect %s failed", key)
+			return
+		}
+		if !stillHeld {
+			logrus.Warnf("[HA] lease of %s lost while connecting; disconnecting", key)
+			c.disconnect(inst, "device lease lost to another replica")
+		}
+	}()
+}
+
+// renewalFailed drops every held session once its lease may lapse before the
+// next round could renew it, so a replica cut off from the database never
+// keeps a socket open for a session another replica is allowed to take.
+func (c *LeaseCoordinator) renewalFailed() {
+	c.mu.Lock()
+	if c.renewedAt.IsZero() || c.monotonic().Sub(c.renewedAt) < c.ttl/2 {
+		c.mu.Unlock()
+		return
+	}
+	instances := c.instances
+	c.held = make(map[string]bool)
+	c.instances = make(map[string]*DeviceInstance)
+	c.published = make(map[string]string)
+	c.mu.Unlock()
+
+	for key, inst := range instances {
+		logrus.Warnf("[HA] lease of %s could not be renewed; disconnecting", key)
+		c.disconnect(inst, "device lease could not be renewed")
+	}
+}
+
+// restoreSlot applies the settings another replica published for a session
+// this replica just took over.
+func (c *LeaseCoordinator) restoreSlot(ctx context.Context, key string, inst *DeviceInstance) error {
+	slot, err := c.store.slot(ctx, key)
+	if err != nil || slot == nil || len(slot.Settings) == 0 {
+		return err
+	}
+	if err := c.restore(inst, slot.Settings); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.published[key] = string(slot.Settings)
+	c.mu.Unlock()
+	return nil
+}
+
+// publishSlot records the slot id and settings of a held session when they
+// differ from what this replica published last.
+func (c *LeaseCoordinator) publishSlot(ctx context.Context, key string, inst *DeviceInstance, now time.Time) error {
+	settings, err := c.settings(inst)
+	if err != nil {
+		return err
+	}
+	c.mu.RLock()
+	unchanged := c.published[key] == string(settings)
+	c.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+	if err := c.store.publishSlot(ctx, key, DeviceSlot{DeviceID: inst.ID(), Settings: settings}, now); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.published[key] = string(settings)
+	c.mu.Unlock()
+	return nil
+}
+
+// clock estimates the database clock from the offset measured at the last
+// round, for lookups that must not query the database.
+func (c *LeaseCoordinator) clock() time.Time {
+	c.mu.RLock()
+	offset := c.clockOffset
+	c.mu.RUnlock()
+	return c.monotonic().Add(offset)
+}
+
+// fairShare is the number of sessions one of live replicas should own.
+func fairShare(sessions, live int) int {
+	if live < 1 {
+		live = 1
+	}
+	return (sessions + live - 1) / live
+}
+
+// Yield gives up the lease of a session whose stream was replaced: another
+// replica connected it, so this one backs off for a TTL instead of fighting.
+func (c *LeaseCoordinator) Yield(ctx context.Context, inst *DeviceInstance) {
+	key := sessionKey(inst)
+	if key == "" {
+		return
+	}
+
+	until := c.clock().Add(c.ttl)
+	c.mu.Lock()
+	delete(c.held, key)
+	delete(c.instances, key)
+	c.yielded[key] = until
+	c.mu.Unlock()
+
+	if err := c.store.release(ctx, c.replicaID, key); err != nil {
+		logrus.WithError(err).Warnf("[HA] release %s failed", key)
+	}
+}
+
+// RemoteOwner returns the base URL of the replica serving deviceID when that
+// is another replica with an unexpired lease. Devices this replica owns, and
+// devices nobody leases (e.g. a slot that is still pairing), stay local.
+func (c *LeaseCoordinator) RemoteOwner(deviceID string) (string, bool) {
+	deviceID = strings.TrimSpace(deviceID)
+	if deviceID == "" {
+		return "", false
+	}
+
+	key := ""
+	if inst, _, err := c.dm.ResolveDevice(deviceID); err == nil {
+		if key = sessionKey(inst); key == "" {
+			return "", false
+		}
+	}
+
+	now := c.clock()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, lease := range c.leases {
+		if !lease.ExpiresAt.After(now) {
+			continue
+		}
+		if key != "" && lease.DeviceKey != key {
+			continue
+		}
+		if key == "" && !leaseMatchesDeviceID(lease, deviceID) {
+			continue
+		}
+		if lease.OwnerID == c.replicaID || lease.OwnerURL == "" {
+			return "", false
+		}
+		return lease.OwnerURL, true
+	}
+	return "", false
+}
+
+// leaseMatchesDeviceID matches a device this replica has no slot for by the
+// owner's slot id, its AD JID or its phone JID.
+func leaseMatchesDeviceID(lease DeviceLease, deviceID string) bool {
+	if lease.DeviceID == deviceID || lease.DeviceKey == deviceID {
+		return true
+	}
+	jid, err := types.ParseJID(lease.DeviceKey)
+	return err == nil && jid.ToNonAD().String() == deviceID
+}
+
+// Status returns the lease table as of the last round.
+func (c *LeaseCoordinator) Status() LeaseStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return LeaseStatus{
+		ReplicaID: c.replicaID,
+		URL:       c.url,
+		LeaseTTL:  c.ttl.String(),
+		Leases:    slices.Clone(c.leases),
+	}
+}
+
+// connectLeasedDevice connects a session this replica just leased.
+func connectLeasedDevice(ctx context.Context, inst *DeviceInstance) error {
+	dm := GetDeviceManager()
+	if dm == nil {
+		return fmt.Errorf("device manager not initialized")
+	}
+	inst, err := dm.EnsureClient(ctx, inst.ID())
+	if err != nil {
+		return err
+	}
+	client := inst.GetClient()
+	if client == nil || client.Store == nil || client.Store.ID == nil {
+		return fmt.Errorf("device %s is not logged in", inst.ID())
+	}
+	if client.IsConnected() {
+		return nil
+	}
+	err = client.Connect()
+	inst.UpdateStateFromClient()
+	if err != nil {
+		inst.RecordConnectFailure(err)
+	}
+	return err
+}
+
+// disconnectLeasedDevice drops a session this replica no longer leases. The
+// manual disconnect keeps whatsmeow from reconnecting on its own.
+func disconnectLeasedDevice(inst *DeviceInstance, reason string) {
+	if inst == nil {
+		return
+	}
+	if client := inst.GetClient(); client != nil {
+		client.Disconnect()
+	}
+	inst.RecordDisconnected(reason)
+	inst.UpdateStateFromClient()
+}
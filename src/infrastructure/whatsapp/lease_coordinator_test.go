@@ -0,0 +1,249 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testReplica is one replica of a simulated cluster: its own device manager
+// with slots for every shared session, and fake connections.
+type testReplica struct {
+	c         *LeaseCoordinator
+	sessions  map[string]*DeviceInstance
+	mu        sync.Mutex
+	connected map[string]bool
+	settings  map[string]string // per-device settings, keyed by slot id
+}
+
+func newTestReplica(t *testing.T, store *leaseStore, id string, clock *time.Time, keys []string) *testReplica {
+	t.Helper()
+
+	dm := NewDeviceManager(nil, nil, nil)
+	r := &testReplica{
+		sessions:  make(map[string]*DeviceInstance),
+		connected: make(map[string]bool),
+		settings:  make(map[string]string),
+	}
+	for _, key := range keys {
+		inst := NewDeviceInstance(key, nil, nil)
+		inst.adJID = key
+		dm.AddDevice(inst)
+		r.sessions[key] = inst
+	}
+
+	r.c = newLeaseCoordinator(store, dm, id, "http://"+id+":3000/", 30*time.Second)
+	r.c.now = func(context.Context) (time.Time, error) { return *clock, nil }
+	r.c.monotonic = func() time.Time { return *clock }
+	r.c.sessions = func(context.Context) (map[string]*DeviceInstance, error) { return r.sessions, nil }
+	r.c.connected = func(inst *DeviceInstance) bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.connected[inst.ADJID()]
+	}
+	r.c.connect = func(_ context.Context, inst *DeviceInstance) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.connected[inst.ADJID()] = true
+		return nil
+	}
+	r.c.disconnect = func(inst *DeviceInstance, _ string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.connected[inst.ADJID()] = false
+	}
+	r.c.settings = func(inst *DeviceInstance) ([]byte, error) { return []byte(r.settings[inst.ID()]), nil }
+	r.c.restore = func(inst *DeviceInstance, settings []byte) error {
+		r.settings[inst.ID()] = string(settings)
+		return nil
+	}
+	return r
+}
+
+// tick runs one lease round and waits for the connects it started.
+func (r *testReplica) tick(t *testing.T) {
+	t.Helper()
+	err := r.c.tick(context.Background())
+	r.c.connects.Wait()
+	if err != nil {
+		t.Fatalf("tick %s: %v", r.c.ReplicaID(), err)
+	}
+}
+
+func (r *testReplica) connectedCount() int {
+	n := 0
+	for _, ok := range r.connected {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+func testSessionKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("62812000000%02d:1@s.whatsapp.net", i)
+	}
+	return keys
+}
+
+func TestLeaseCoordinatorSplitsRebalancesAndFailsOver(t *testing.T) {
+	store := newTestLeaseStore(t)
+	clock := time.UnixMilli(1_700_000_000_000)
+	keys := testSessionKeys(4)
+
+	a := newTestReplica(t, store, "a", &clock, keys)
+	b := newTestReplica(t, store, "b", &clock, keys)
+
+	// Alone, a takes every session.
+	a.tick(t)
+	if got := a.connectedCount(); got != 4 {
+		t.Fatalf("expected a to connect all 4 sessions, got %d", got)
+	}
+
+	// b joins: everything is leased, so it waits until a hands back its excess.
+	clock = clock.Add(time.Second)
+	b.tick(t)
+	if got := b.connectedCount(); got != 0 {
+		t.Fatalf("expected b to connect nothing while a holds every lease, got %d", got)
+	}
+	clock = clock.Add(time.Second)
+	a.tick(t)
+	clock = clock.Add(time.Second)
+	b.tick(t)
+	if a.connectedCount() != 2 || b.connectedCount() != 2 {
+		t.Fatalf("expected a 2/2 split, got a=%d b=%d", a.connectedCount(), b.connectedCount())
+	}
+	for _, key := range keys {
+		if a.connected[key] == b.connected[key] {
+			t.Fatalf("session %s connected on both or neither replica", key)
+		}
+	}
+
+	// Requests for b's sessions are routed to b once a has seen its leases;
+	// a's own stay local.
+	a.tick(t)
+	for _, key := range keys {
+		url, remote := a.c.RemoteOwner(key)
+		if b.connected[key] {
+			if !remote || url != "http://b:3000" {
+				t.Fatalf("expected %s to route to b, got %q remote=%v", key, url, remote)
+			}
+		} else if remote {
+			t.Fatalf("expected %s to stay local on a, got %q", key, url)
+		}
+	}
+
+	// a dies without releasing: once its leases expire b takes everything.
+	clock = clock.Add(31 * time.Second)
+	b.tick(t)
+	if got := b.connectedCount(); got != 4 {
+		t.Fatalf("expected b to take over all 4 sessions, got %d", got)
+	}
+}
+
+func TestLeaseCoordinatorYieldReleasesAndBacksOff(t *testing.T) {
+	store := newTestLeaseStore(t)
+	clock := time.UnixMilli(1_700_000_000_000)
+	keys := testSessionKeys(1)
+
+	a := newTestReplica(t, store, "a", &clock, keys)
+	b := newTestReplica(t, store, "b", &clock, keys)
+
+	a.tick(t)
+	if !a.connected[keys[0]] {
+		t.Fatalf("expected a to connect %s", keys[0])
+	}
+
+	// The session was connected elsewhere: a hands the lease back at once.
+	a.connected[keys[0]] = false
+	a.c.Yield(context.Background(), a.sessions[keys[0]])
+
+	clock = clock.Add(time.Second)
+	a.tick(t)
+	if a.connected[keys[0]] {
+		t.Fatal("expected a not to reclaim a yielded session within the lease TTL")
+	}
+	b.tick(t)
+	if !b.connected[keys[0]] {
+		t.Fatal("expected b to claim the yielded session")
+	}
+}
+
+func TestLeaseCoordinatorDropsUnleasedLocalSession(t *testing.T) {
+	store := newTestLeaseStore(t)
+	clock := time.UnixMilli(1_700_000_000_000)
+	keys := testSessionKeys(1)
+
+	a := newTestReplica(t, store, "a", &clock, keys)
+	b := newTestReplica(t, store, "b", &clock, keys)
+
+	a.tick(t)
+
+	// A session that b connected on its own (e.g. a stale auto-connect) is
+	// dropped while a owns it.
+	b.connected[keys[0]] = true
+	b.tick(t)
+	if b.connected[keys[0]] {
+		t.Fatal("expected b to disconnect a session leased by a")
+	}
+}
+
+func TestLeaseCoordinatorHandsSettingsToTheNextOwner(t *testing.T) {
+	store := newTestLeaseStore(t)
+	clock := time.UnixMilli(1_700_000_000_000)
+	keys := testSessionKeys(1)
+
+	a := newTestReplica(t, store, "a", &clock, keys)
+	b := newTestReplica(t, store, "b", &clock, keys)
+	a.settings[keys[0]] = `{"webhook":"https://a.example/hook"}`
+
+	a.tick(t)
+	slot, err := store.slot(context.Background(), keys[0])
+	if err != nil || slot == nil || slot.DeviceID != keys[0] {
+		t.Fatalf("expected a to publish its slot, got %+v err=%v", slot, err)
+	}
+
+	// a dies; b takes the session over with a's settings before connecting.
+	clock = clock.Add(31 * time.Second)
+	b.tick(t)
+	if !b.connected[keys[0]] {
+		t.Fatal("expected b to take the session over")
+	}
+	if got := b.settings[keys[0]]; got != `{"webhook":"https://a.example/hook"}` {
+		t.Fatalf("expected b to restore a's settings, got %q", got)
+	}
+}
+
+func TestLeaseCoordinatorDisconnectsWhenRenewalFails(t *testing.T) {
+	store := newTestLeaseStore(t)
+	clock := time.UnixMilli(1_700_000_000_000)
+	keys := testSessionKeys(2)
+
+	a := newTestReplica(t, store, "a", &clock, keys)
+	a.tick(t)
+	if got := a.connectedCount(); got != 2 {
+		t.Fatalf("expected a to connect both sessions, got %d", got)
+	}
+
+	// The database goes away. One missed round is tolerated; once the leases
+	// could lapse before the next round, the sessions are dropped.
+	a.c.now = func(context.Context) (time.Time, error) { return time.Time{}, fmt.Errorf("connection refused") }
+	clock = clock.Add(10 * time.Second)
+	if err := a.c.tick(context.Background()); err == nil {
+		t.Fatal("expected the round to fail")
+	}
+	if got := a.connectedCount(); got != 2 {
+		t.Fatalf("expected a to keep its sessions after one missed round, got %d", got)
+	}
+	clock = clock.Add(10 * time.Second)
+	if err := a.c.tick(context.Background()); err == nil {
+		t.Fatal("expected the round to fail")
+	}
+	if got := a.connectedCount(); got != 0 {
+		t.Fatalf("expected a to drop its sessions, got %d connected", got)
+	}
+}
@@ -0,0 +1,240 @@
+package whatsapp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DeviceLease records which replica currently owns a paired WhatsApp session.
+type DeviceLease struct {
+	DeviceKey string    `json:"device_key"` // companion AD JID, the session that must never be connected twice
+	DeviceID  string    `json:"device_id"`  // slot id on the owning replica
+	OwnerID   string    `json:"owner_id"`
+	OwnerURL  string    `json:"owner_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// leaseSchema lives in the shared whatsmeow database. The SQL sticks to what
+// Postgres and SQLite both accept so the store can be tested against SQLite.
+const leaseSchema = `
+CREATE TABLE IF NOT EXISTS gowa_device_leases (
+    device_key TEXT PRIMARY KEY,
+    device_id TEXT NOT NULL,
+    owner_id TEXT NOT NULL,
+    owner_url TEXT NOT NULL,
+    expires_at BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_gowa_device_leases_owner ON gowa_device_leases(owner_id);
+CREATE TABLE IF NOT EXISTS gowa_replicas (
+    replica_id TEXT PRIMARY KEY,
+    url TEXT NOT NULL,
+    heartbeat_at BIGINT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS gowa_device_slots (
+    device_key TEXT PRIMARY KEY,
+    device_id TEXT NOT NULL,
+    settings TEXT NOT NULL,
+    updated_at BIGINT NOT NULL
+);`
+
+// Queries reading the database clock in epoch milliseconds. Lease expiry is
+// always computed from it so replicas with skewed wall clocks agree on when a
+// lease lapses.
+const (
+	postgresNowQuery = `SELECT CAST(EXTRACT(EPOCH FROM clock_timestamp()) * 1000 AS BIGINT)`
+	sqliteNowQuery   = `SELECT CAST((julianday('now') - 2440587.5) * 86400000 AS INTEGER)`
+)
+
+// DeviceSlot is the slot a session runs under and its per-device settings,
+// published by the lease owner so the replica taking the session over serves
+// it under the same device id and configuration.
+type DeviceSlot struct {
+	DeviceID string
+	Settings []byte
+}
+
+type leaseStore struct {
+	db       *sql.DB
+	nowQuery string
+}
+
+func newLeaseStore(ctx context.Context, db *sql.DB) (*leaseStore, error) {
+	if _, err := db.ExecContext(ctx, leaseSchema); err != nil {
+		return nil, fmt.Errorf("create lease tables: %w", err)
+	}
+	return &leaseStore{db: db, nowQuery: postgresNowQuery}, nil
+}
+
+// now returns the database clock.
+func (s *leaseStore) now(ctx context.Context) (time.Time, error) {
+	var ms int64
+	if err := s.db.QueryRowContext(ctx, s.nowQuery).Scan(&ms); err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
+
+// heartbeat announces a live replica; fair shares are computed from the
+// replicas that sent one within the last lease TTL.
+func (s *leaseStore) heartbeat(ctx context.Context, replicaID, url string, now time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+INSERT INTO gowa_replicas (replica_id, url, heartbeat_at) VALUES ($1, $2, $3)
+ON CONFLICT (replica_id) DO UPDATE SET url = excluded.url, heartbeat_at = excluded.heartbeat_at`,
+		replicaID, url, now.UnixMilli())
+	return err
+}
+
+func (s *leaseStore) liveReplicas(ctx context.Context, since time.Time) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM gowa_replicas WHERE heartbeat_at > $1`, since.UnixMilli()).Scan(&n)
+	return n, err
+}
+
+// claim takes the lease of a device when it is free, expired or already held
+// by owner, and reports whether owner holds it afterwards.
+func (s *leaseStore) claim(ctx context.Context, lease DeviceLease, now time.Time) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+INSERT INTO gowa_device_leases (device_key, device_id, owner_id, owner_url, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (device_key) DO UPDATE SET
+    device_id = excluded.device_id,
+    owner_id = excluded.owner_id,
+    owner_url = excluded.owner_url,
+    expires_at = excluded.expires_at
+WHERE gowa_device_leases.owner_id = excluded.owner_id OR gowa_device_leases.expires_at <= $6`,
+		lease.DeviceKey, lease.DeviceID, lease.OwnerID, lease.OwnerURL, lease.ExpiresAt.UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// renew extends every unexpired lease of owner and returns the device keys it
+// still holds. Leases that expired in the meantime may already belong to
+// another replica and are not renewed.
+func (s *leaseStore) renew(ctx context.Context, ownerID string, now time.Time, ttl time.Duration) (map[string]bool, error) {
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE gowa_device_leases SET expires_at = $1 WHERE owner_id = $2 AND expires_at > $3`,
+		now.Add(ttl).UnixMilli(), ownerID, now.UnixMilli()); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT device_key FROM gowa_device_leases WHERE owner_id = $1 AND expires_at > $2`,
+		ownerID, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	held := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		held[key] = true
+	}
+	return held, rows.Err()
+}
+
+// release drops a lease held by owner so another replica can take it at once.
+func (s *leaseStore) release(ctx context.Context, ownerID, deviceKey string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM gowa_device_leases WHERE owner_id = $1 AND device_key = $2`, ownerID, deviceKey)
+	return err
+}
+
+// releaseAll drops every lease of owner and its heartbeat, for clean shutdowns.
+func (s *leaseStore) releaseAll(ctx context.Context, ownerID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM gowa_device_leases WHERE owner_id = $1`, ownerID); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM gowa_replicas WHERE replica_id = $1`, ownerID)
+	return err
+}
+
+// activeLeases lists the unexpired leases of all replicas.
+func (s *leaseStore) activeLeases(ctx context.Context, now time.Time) ([]DeviceLease, error) {
+	rows, err := s.db.QueryContext(ctx, `
+SELECT device_key, device_id, owner_id, owner_url, expires_at
+FROM gowa_device_leases WHERE expires_at > $1 ORDER BY device_key`, now.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leases []DeviceLease
+	for rows.Next() {
+		var lease DeviceLease
+		var expiresAt int64
+		if err := rows.Scan(&lease.DeviceKey, &lease.DeviceID, &lease.OwnerID, &lease.OwnerURL, &expiresAt); err != nil {
+			return nil, err
+		}
+		lease.ExpiresAt = time.UnixMilli(expiresAt)
+		leases = append(leases, lease)
+	}
+	return leases, rows.Err()
+}
+
+// publishSlot records the slot id and settings of a session held by this
+// replica. The row outlives the lease: it is what a later owner restores.
+func (s *leaseStore) publishSlot(ctx context.Context, deviceKey string, slot DeviceSlot, now time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+INSERT INTO gowa_device_slots (device_key, device_id, settings, updated_at) VALUES ($1, $2, $3, $4)
+ON CONFLICT (device_key) DO UPDATE SET
+    device_id = excluded.device_id,
+    settings = excluded.settings,
+    updated_at = excluded.updated_at`,
+		deviceKey, slot.DeviceID, string(slot.Settings), now.UnixMilli())
+	return err
+}
+
+// slot returns the published slot of a session, or nil when none was published.
+func (s *leaseStore) slot(ctx context.Context, deviceKey string) (*DeviceSlot, error) {
+	var slot DeviceSlot
+	var settings string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT device_id, settings FROM gowa_device_slots WHERE device_key = $1`, deviceKey).
+		Scan(&slot.DeviceID, &settings)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	slot.Settings = []byte(settings)
+	return &slot, nil
+}
+
+// slotIDs maps every published session to its slot id.
+func (s *leaseStore) slotIDs(ctx context.Context) (map[string]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT device_key, device_id FROM gowa_device_slots`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string)
+	for rows.Next() {
+		var key, id string
+		if err := rows.Scan(&key, &id); err != nil {
+			return nil, err
+		}
+		ids[key] = id
+	}
+	return ids, rows.Err()
+}
+
+// deleteSlot forgets a session that was logged out or purged.
+func (s *leaseStore) deleteSlot(ctx context.Context, deviceKey string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM gowa_device_slots WHERE device_key = $1`, deviceKey)
+	return err
+}
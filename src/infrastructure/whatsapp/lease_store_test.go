@@ -0,0 +1,163 @@
+package whatsapp
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/sqlite"
+)
+
+func newTestLeaseStore(t *testing.T) *leaseStore {
+	t.Helper()
+
+	uri := sqlite.FormatChatStorageURI("file:"+filepath.Join(t.TempDir(), "leases.db"), true, true)
+	db, err := sql.Open(sqlite.DriverName, uri)
+	if err != nil {
+		t.Fatalf("open lease db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	store, err := newLeaseStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("create lease store: %v", err)
+	}
+	store.nowQuery = sqliteNowQuery
+	return store
+}
+
+func TestLeaseStoreClaimIsExclusiveUntilExpiry(t *testing.T) {
+	ctx := context.Background()
+	store := newTestLeaseStore(t)
+	now := time.UnixMilli(1_700_000_000_000)
+	ttl := 30 * time.Second
+	key := "6281234567890:12@s.whatsapp.net"
+
+	lease := func(owner string, at time.Time) DeviceLease {
+		return DeviceLease{DeviceKey: key, DeviceID: "sales", OwnerID: owner, OwnerURL: "http://" + owner, ExpiresAt: at.Add(ttl)}
+	}
+
+	if ok, err := store.claim(ctx, lease("a", now), now); err != nil || !ok {
+		t.Fatalf("first claim: ok=%v err=%v", ok, err)
+	}
+	if ok, err := store.claim(ctx, lease("b", now.Add(ttl/2)), now.Add(ttl/2)); err != nil || ok {
+		t.Fatalf("claim of a live lease by another replica: ok=%v err=%v", ok, err)
+	}
+	if ok, err := store.claim(ctx, lease("a", now.Add(ttl/2)), now.Add(ttl/2)); err != nil || !ok {
+		t.Fatalf("re-claim by the owner: ok=%v err=%v", ok, err)
+	}
+
+	held, err := store.renew(ctx, "a", now.Add(ttl/2), ttl)
+	if err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if !held[key] {
+		t.Fatalf("expected a to still hold %s, got %v", key, held)
+	}
+
+	// Once the renewed lease lapses, another replica may take it over and the
+	// former owner can no longer renew it.
+	later := now.Add(ttl/2 + ttl)
+	if ok, err := store.claim(ctx, lease("b", later), later); err != nil || !ok {
+		t.Fatalf("takeover of an expired lease: ok=%v err=%v", ok, err)
+	}
+	held, err = store.renew(ctx, "a", later, ttl)
+	if err != nil {
+		t.Fatalf("renew after takeover: %v", err)
+	}
+	if len(held) != 0 {
+		t.Fatalf("expected a to hold nothing after takeover, got %v", held)
+	}
+
+	leases, err := store.activeLeases(ctx, later)
+	if err != nil {
+		t.Fatalf("active leases: %v", err)
+	}
+	if len(leases) != 1 || leases[0].OwnerID != "b" || leases[0].OwnerURL != "http://b" || leases[0].DeviceID != "sales" {
+		t.Fatalf("unexpected leases: %+v", leases)
+	}
+}
+
+func TestLeaseStoreReleaseAllDropsLeasesAndHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	store := newTestLeaseStore(t)
+	now := time.UnixMilli(1_700_000_000_000)
+
+	for _, replica := range []string{"a", "b"} {
+		if err := store.heartbeat(ctx, replica, "http://"+replica, now); err != nil {
+			t.Fatalf("heartbeat %s: %v", replica, err)
+		}
+	}
+	if _, err := store.claim(ctx, DeviceLease{DeviceKey: "k1", DeviceID: "k1", OwnerID: "a", ExpiresAt: now.Add(time.Minute)}, now); err != nil {
+		t.Fatalf("claim: %v", err)
+	}
+
+	if err := store.releaseAll(ctx, "a"); err != nil {
+		t.Fatalf("release all: %v", err)
+	}
+
+	live, err := store.liveReplicas(ctx, now.Add(-time.Second))
+	if err != nil {
+		t.Fatalf("live replicas: %v", err)
+	}
+	if live != 1 {
+		t.Fatalf("expected 1 live replica, got %d", live)
+	}
+	leases, err := store.activeLeases(ctx, now)
+	if err != nil {
+		t.Fatalf("active leases: %v", err)
+	}
+	if len(leases) != 0 {
+		t.Fatalf("expected no leases after release, got %+v", leases)
+	}
+}
+
+func TestLeaseStoreReadsDatabaseClock(t *testing.T) {
+	store := newTestLeaseStore(t)
+
+	now, err := store.now(context.Background())
+	if err != nil {
+		t.Fatalf("now: %v", err)
+	}
+	if drift := time.Since(now); drift < -time.Minute || drift > time.Minute {
+		t.Fatalf("database clock %s is not close to the local clock", now)
+	}
+}
+
+func TestLeaseStorePublishesSlots(t *testing.T) {
+	ctx := context.Background()
+	store := newTestLeaseStore(t)
+	now := time.UnixMilli(1_700_000_000_000)
+	key := "6281234567890:12@s.whatsapp.net"
+
+	if slot, err := store.slot(ctx, key); err != nil || slot != nil {
+		t.Fatalf("expected no slot before publishing, got %+v err=%v", slot, err)
+	}
+	if err := store.publishSlot(ctx, key, DeviceSlot{DeviceID: "sales", Settings: []byte(`{"v":1}`)}, now); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if err := store.publishSlot(ctx, key, DeviceSlot{DeviceID: "sales", Settings: []byte(`{"v":2}`)}, now); err != nil {
+		t.Fatalf("republish: %v", err)
+	}
+
+	slot, err := store.slot(ctx, key)
+	if err != nil || slot == nil || slot.DeviceID != "sales" || string(slot.Settings) != `{"v":2}` {
+		t.Fatalf("unexpected slot %+v err=%v", slot, err)
+	}
+	ids, err := store.slotIDs(ctx)
+	if err != nil || ids[key] != "sales" {
+		t.Fatalf("unexpected slot ids %v err=%v", ids, err)
+	}
+
+	if err := store.deleteSlot(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if slot, err := store.slot(ctx, key); err != nil || slot != nil {
+		t.Fatalf("expected the slot to be gone, got %+v err=%v", slot, err)
+	}
+}
@@ -0,0 +1,41 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+)
+
+// MessageObserver is told which chat of which device received a newly stored
+// message. deviceID is the DeviceInstance id.
+type MessageObserver func(deviceID, chatJID string)
+
+var (
+	messageObserversMu sync.RWMutex
+	messageObservers   []MessageObserver
+)
+
+// AddMessageObserver registers fn to run after every message stored from a
+// WhatsApp event. Observers run on the event goroutine and must not block.
+func AddMessageObserver(fn MessageObserver) {
+	if fn == nil {
+		return
+	}
+	messageObserversMu.Lock()
+	defer messageObserversMu.Unlock()
+	messageObservers = append(messageObservers, fn)
+}
+
+// notifyMessageObservers reports a stored message of chatJID to every
+// registered observer. Events without a device in ctx are not reported.
+func notifyMessageObservers(ctx context.Context, chatJID string) {
+	inst, ok := DeviceFromContext(ctx)
+	if !ok || inst == nil || chatJID == "" {
+		return
+	}
+	messageObserversMu.RLock()
+	observers := messageObservers
+	messageObserversMu.RUnlock()
+	for _, fn := range observers {
+		fn(inst.ID(), chatJID)
+	}
+}
@@ -0,0 +1,264 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+const (
+	// presenceFlushDelay coalesces the presence writes of one user: the
+	// latest state is written once the user stayed quiet this long.
+	presenceFlushDelay = 2 * time.Second
+	// presenceCacheTTL and presenceCacheLimit bound the in-memory presences;
+	// evicted entries are read back from chat storage on demand.
+	presenceCacheTTL   = time.Hour
+	presenceCacheLimit = 10000
+)
+
+// presenceStore keeps the last known presence per device and user in memory,
+// in front of chat storage, so typing updates are answered without a query.
+// Writes to storage are debounced per user and flushed in the background.
+type presenceStore struct {
+	mu        sync.RWMutex
+	presences map[string]domainChatStorage.Presence
+	pending   map[string]pendingPresence
+}
+
+// pendingPresence is a presence waiting for its debounced write.
+type pendingPresence struct {
+	repo     domainChatStorage.IChatStorageRepository
+	presence domainChatStorage.Presence
+}
+
+var presences = newPresenceStore()
+
+func newPresenceStore() *presenceStore {
+	return &presenceStore{
+		presences: make(map[string]domainChatStorage.Presence),
+		pending:   make(map[string]pendingPresence),
+	}
+}
+
+func presenceKey(deviceID, jid string) string {
+	return deviceID + "|" + jid
+}
+
+// get returns the cached presence, falling back to (and warming from) storage.
+func (s *presenceStore) get(chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID, jid string) (*domainChatStorage.Presence, error) {
+	s.mu.RLock()
+	cached, ok := s.presences[presenceKey(deviceID, jid)]
+	s.mu.RUnlock()
+	if ok {
+		return &cached, nil
+	}
+	if chatStorageRepo == nil {
+		return nil, nil
+	}
+
+	stored, err := chatStorageRepo.GetPresence(deviceID, jid)
+	if err != nil || stored == nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	if _, ok := s.presences[presenceKey(deviceID, jid)]; !ok {
+		s.presences[presenceKey(deviceID, jid)] = *stored
+	}
+	s.mu.Unlock()
+	return stored, nil
+}
+
+// update applies mutate to the last known presence and schedules its write.
+func (s *presenceStore) update(chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID, jid string, mutate func(*domainChatStorage.Presence)) {
+	current, err := s.get(chatStorageRepo, deviceID, jid)
+	if err != nil {
+		log.Warnf("Failed to load presence of %s: %v", jid, err)
+	}
+	if current == nil {
+		current = &domainChatStorage.Presence{DeviceID: deviceID, JID: jid}
+	}
+	mutate(current)
+	current.UpdatedAt = time.Now().UTC()
+
+	key := presenceKey(deviceID, jid)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, cached := s.presences[key]; !cached && len(s.presences) >= presenceCacheLimit {
+		s.pruneLocked(current.UpdatedAt)
+	}
+	s.presences[key] = *current
+
+	if chatStorageRepo == nil {
+		return
+	}
+	if _, scheduled := s.pending[key]; !scheduled {
+		time.AfterFunc(presenceFlushDelay, func() { s.flush(key) })
+	}
+	s.pending[key] = pendingPresence{repo: chatStorageRepo, presence: *current}
+}
+
+// flush writes the pending presence of key, if any.
+func (s *presenceStore) flush(key string) {
+	s.mu.Lock()
+	pending, ok := s.pending[key]
+	delete(s.pending, key)
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	if err := pending.repo.SavePresence(&pending.presence); err != nil {
+		log.Warnf("Failed to store presence of %s: %v", pending.presence.JID, err)
+	}
+}
+
+// flushAll writes every pending presence without waiting for its delay.
+func (s *presenceStore) flushAll() {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.pending))
+	for key := range s.pending {
+		keys = append(keys, key)
+	}
+	s.mu.RUnlock()
+	for _, key := range keys {
+		s.flush(key)
+	}
+}
+
+// FlushPresences writes every debounced presence to chat storage right away.
+// Call it on shutdown, before chat storage is closed, so no update is lost.
+func FlushPresences() {
+	presences.flushAll()
+}
+
+// pruneLocked drops presences older than presenceCacheTTL and, when the cache
+// is still full, arbitrary entries until there is room. Entries with a pending
+// write are kept so reads never go back to an older stored state.
+func (s *presenceStore) pruneLocked(now time.Time) {
+	for key, presence := range s.presences {
+		if _, pending := s.pending[key]; !pending && now.Sub(presence.UpdatedAt) > presenceCacheTTL {
+			delete(s.presences, key)
+		}
+	}
+	for key := range s.presences {
+		if len(s.presences) < presenceCacheLimit {
+			return
+		}
+		if _, pending := s.pending[key]; !pending {
+			delete(s.presences, key)
+		}
+	}
+}
+
+// LastKnownPresence returns the latest presence the device saw for jid, or nil
+// when nothing is known.
+func LastKnownPresence(chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, jid types.JID) (*domainChatStorage.Presence, error) {
+	return presences.get(chatStorageRepo, deviceID, jid.ToNonAD().String())
+}
+
+// SubscribePresence asks WhatsApp for jid's presence updates and remembers the
+// subscription so it is renewed after every reconnect.
+func SubscribePresence(ctx context.Context, client *whatsmeow.Client, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, jid types.JID) error {
+	if err := client.SubscribePresence(ctx, jid); err != nil {
+		return err
+	}
+	return chatStorageRepo.AddPresenceSubscription(deviceID, jid.ToNonAD().String())
+}
+
+// UnsubscribePresence forgets jid's presence subscription so it is no longer
+// renewed after a reconnect. whatsmeow has no unsubscribe call and the raw
+// stanza is not sent on purpose: WhatsApp drops subscriptions whenever the
+// connection is lost, so updates already flowing stop at the next reconnect.
+func UnsubscribePresence(chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, jid types.JID) error {
+	return chatStorageRepo.RemovePresenceSubscription(deviceID, jid.ToNonAD().String())
+}
+
+// resubscribePresences renews the device's stored presence subscriptions,
+// which WhatsApp drops whenever the connection is lost.
+func resubscribePresences(ctx context.Context, client *whatsmeow.Client, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string) {
+	if client == nil || chatStorageRepo == nil || deviceID == "" {
+		return
+	}
+
+	go func() {
+		subscribeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+		defer cancel()
+
+		jids, err := chatStorageRepo.GetPresenceSubscriptions(deviceID)
+		if err != nil {
+			log.Warnf("Failed to load presence subscriptions: %v", err)
+			return
+		}
+		for _, raw := range jids {
+			jid, err := types.ParseJID(raw)
+			if err != nil {
+				continue
+			}
+			if err := client.SubscribePresence(subscribeCtx, jid); err != nil {
+				log.Warnf("Failed to renew presence subscription of %s: %v", raw, err)
+			}
+		}
+		if len(jids) > 0 {
+			log.Infof("Renewed %d presence subscriptions", len(jids))
+		}
+	}()
+}
+
+func handlePresence(ctx context.Context, evt *events.Presence, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	if evt.Unavailable {
+		if evt.LastSeen.IsZero() {
+			log.Infof("%s is now offline", evt.From)
+		} else {
+			log.Infof("%s is now offline (last seen: %s)", evt.From, evt.LastSeen)
+		}
+	} else {
+		log.Infof("%s is now online", evt.From)
+	}
+
+	if deviceID == "" {
+		return
+	}
+	key, _, ok := contactKey(ctx, evt.From, client)
+	if !ok {
+		return
+	}
+	presences.update(chatStorageRepo, deviceID, key.String(), func(presence *domainChatStorage.Presence) {
+		presence.Available = !evt.Unavailable
+		// Users hiding their last seen send no timestamp; keep the last one known.
+		if !evt.LastSeen.IsZero() {
+			lastSeen := evt.LastSeen.UTC()
+			presence.LastSeenAt = &lastSeen
+		}
+	})
+}
+
+// recordChatPresence keeps the sender's latest typing state. Typing implies
+// the user is online, even without a presence subscription.
+func recordChatPresence(ctx context.Context, evt *events.ChatPresence, chatStorageRepo domainChatStorage.IChatStorageRepository, deviceID string, client *whatsmeow.Client) {
+	if deviceID == "" {
+		return
+	}
+	key, _, ok := contactKey(ctx, evt.Sender, client)
+	if !ok {
+		return
+	}
+
+	chatJID := evt.Chat.ToNonAD()
+	if chatJID.Server == types.HiddenUserServer {
+		chatJID = NormalizeJIDFromLID(ctx, chatJID, client).ToNonAD()
+	}
+	now := time.Now().UTC()
+	presences.update(chatStorageRepo, deviceID, key.String(), func(presence *domainChatStorage.Presence) {
+		if evt.State == types.ChatPresenceComposing {
+			presence.Available = true
+		}
+		presence.ChatJID = chatJID.String()
+		presence.ChatState = string(evt.State)
+		presence.ChatMedia = string(evt.Media)
+		presence.ChatStateAt = &now
+	})
+}
@@ -0,0 +1,98 @@
+package whatsapp
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+type presenceRepoSpy struct {
+	domainChatStorage.IChatStorageRepository
+	saved  []domainChatStorage.Presence
+	stored *domainChatStorage.Presence
+}
+
+func (r *presenceRepoSpy) SavePresence(presence *domainChatStorage.Presence) error {
+	r.saved = append(r.saved, *presence)
+	return nil
+}
+
+func (r *presenceRepoSpy) GetPresence(string, string) (*domainChatStorage.Presence, error) {
+	return r.stored, nil
+}
+
+func TestPresenceEventsUpdateLastKnownPresence(t *testing.T) {
+	originalLog := log
+	originalPresences := presences
+	defer func() {
+		log = originalLog
+		presences = originalPresences
+	}()
+	log = waLog.Noop
+	presences = newPresenceStore()
+
+	user := types.NewJID("628111", types.DefaultUserServer)
+	lastSeen := time.Date(2026, time.October, 4, 8, 0, 0, 0, time.UTC)
+	// Storage already knows a last seen from before a restart.
+	repo := &presenceRepoSpy{stored: &domainChatStorage.Presence{DeviceID: "dev-1", JID: user.String(), LastSeenAt: &lastSeen}}
+	ctx := context.Background()
+
+	handlePresence(ctx, &events.Presence{From: user}, repo, "dev-1", nil)
+	got, err := LastKnownPresence(repo, "dev-1", user)
+	if err != nil || got == nil {
+		t.Fatalf("LastKnownPresence() = %v, %v", got, err)
+	}
+	if !got.Available || got.LastSeenAt == nil || !got.LastSeenAt.Equal(lastSeen) {
+		t.Fatalf("unexpected presence after online %+v", got)
+	}
+
+	typing := &events.ChatPresence{
+		MessageSource: types.MessageSource{Chat: user, Sender: user},
+		State:         types.ChatPresenceComposing,
+	}
+	recordChatPresence(ctx, typing, repo, "dev-1", nil)
+
+	// Going offline with hidden last seen keeps the last known timestamp.
+	handlePresence(ctx, &events.Presence{From: user, Unavailable: true}, repo, "dev-1", nil)
+
+	got, _ = LastKnownPresence(repo, "dev-1", user)
+	if got.Available || got.ChatState != "composing" || got.ChatJID != user.String() || got.LastSeenAt == nil {
+		t.Fatalf("unexpected presence after offline %+v", got)
+	}
+	// The three updates are coalesced into a single write of the latest state.
+	if len(repo.saved) != 0 {
+		t.Fatalf("saved %d presences before the flush, want 0", len(repo.saved))
+	}
+	presences.flushAll()
+	if len(repo.saved) != 1 || repo.saved[0].Available || repo.saved[0].ChatState != "composing" {
+		t.Fatalf("unexpected flushed presences %+v", repo.saved)
+	}
+
+	// Groups and newsletters have no presence.
+	handlePresence(ctx, &events.Presence{From: types.NewJID("120363", types.GroupServer)}, repo, "dev-1", nil)
+	presences.flushAll()
+	if len(repo.saved) != 1 {
+		t.Fatalf("group presence was stored")
+	}
+}
+
+func TestPresenceCacheDropsExpiredEntriesWhenFull(t *testing.T) {
+	store := newPresenceStore()
+	stale := time.Now().UTC().Add(-2 * presenceCacheTTL)
+	for i := 0; i < presenceCacheLimit; i++ {
+		store.presences[presenceKey("dev-1", strconv.Itoa(i))] = domainChatStorage.Presence{UpdatedAt: stale}
+	}
+
+	store.update(nil, "dev-1", "628111@s.whatsapp.net", func(presence *domainChatStorage.Presence) {
+		presence.Available = true
+	})
+	if len(store.presences) != 1 {
+		t.Fatalf("cache holds %d presences, want only the fresh one", len(store.presences))
+	}
+}
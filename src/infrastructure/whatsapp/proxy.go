@@ -1,14 +1,248 @@
 package whatsapp
 
-import "net/url"
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
-// redactProxyURL returns a form of the proxy URL safe for logging: the
-// password (and any unparseable URL entirely) is masked so credentials from
-// WHATSAPP_PROXY never reach the logs.
-func redactProxyURL(raw string) string {
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"go.mau.fi/whatsmeow"
+	waLog "go.mau.fi/whatsmeow/util/log"
+)
+
+// proxyHealthTimeout bounds a single TCP probe of a proxy.
+const proxyHealthTimeout = 3 * time.Second
+
+// proxyHealthTTL is how long a probe result is trusted before the proxy is
+// probed again.
+const proxyHealthTTL = 30 * time.Second
+
+// proxyHealthCheck reports whether a proxy accepts TCP connections. It is a
+// variable so tests can stub the network out.
+var proxyHealthCheck = func(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	conn, err := net.DialTimeout("tcp", proxyHostPort(u), proxyHealthTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// proxyHealthState is the last probe result of one proxy.
+type proxyHealthState struct {
+	healthy   bool
+	checkedAt time.Time
+	probing   bool
+}
+
+// proxyHealthCache keeps the last probe result per proxy URL. Probes run in
+// the background, so failover (which runs inside whatsmeow's reconnect hook
+// and inside HTTP handlers) never waits on the network.
+type proxyHealthCache struct {
+	mu      sync.Mutex
+	entries map[string]*proxyHealthState
+	probes  sync.WaitGroup
+}
+
+var proxyHealth = newProxyHealthCache()
+
+func newProxyHealthCache() *proxyHealthCache {
+	return &proxyHealthCache{entries: make(map[string]*proxyHealthState)}
+}
+
+// status returns the cached health of proxy and whether a result is known,
+// starting a background probe when the result is missing or older than
+// proxyHealthTTL.
+func (c *proxyHealthCache) status(proxy string) (healthy bool, known bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.entries[proxy]
+	if !ok {
+		state = &proxyHealthState{}
+		c.entries[proxy] = state
+	}
+	if !state.probing && time.Since(state.checkedAt) > proxyHealthTTL {
+		state.probing = true
+		c.probes.Add(1)
+		go c.probe(proxy)
+	}
+	return state.healthy, !state.checkedAt.IsZero()
+}
+
+func (c *proxyHealthCache) probe(proxy string) {
+	defer c.probes.Done()
+	err := proxyHealthCheck(proxy)
+	if err != nil {
+		log.Warnf("proxy %s failed health check: %v", RedactProxyURL(proxy), err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if state, ok := c.entries[proxy]; ok {
+		state.healthy = err == nil
+		state.checkedAt = time.Now()
+		state.probing = false
+	}
+}
+
+// refresh starts background probes for every proxy whose result is stale.
+func (c *proxyHealthCache) refresh(proxies []string) {
+	for _, proxy := range proxies {
+		c.status(proxy)
+	}
+}
+
+// RedactProxyURL returns a form of the proxy URL safe for logging and API
+// responses: the username and password (and any unparseable URL entirely) are
+// masked so proxy credentials never leak.
+func RedactProxyURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
 	u, err := url.Parse(raw)
 	if err != nil {
 		return "<unparseable-proxy-url>"
 	}
-	return u.Redacted()
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword("xxxxx", "xxxxx")
+		} else {
+			u.User = url.User("xxxxx")
+		}
+	}
+	return u.String()
+}
+
+// ValidateProxyURL checks that raw is a proxy address whatsmeow can dial:
+// an http, https or socks5 URL with a host.
+func ValidateProxyURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid proxy url %s", RedactProxyURL(raw))
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q, use http, https or socks5", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("proxy url %s has no host", RedactProxyURL(raw))
+	}
+	return nil
+}
+
+// proxyHostPort returns the dial address of a proxy URL, filling in the
+// scheme's default port.
+func proxyHostPort(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	port := "1080"
+	switch u.Scheme {
+	case "http":
+		port = "80"
+	case "https":
+		port = "443"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// proxyCandidates returns the proxies a device may use, in failover order: its
+// own proxy, then its pool, or WHATSAPP_PROXY when the device sets neither.
+// Blank and duplicate entries are dropped.
+func proxyCandidates(cfg *domainChatStorage.DeviceProxyConfig) []string {
+	var raw []string
+	if cfg != nil {
+		raw = append(raw, cfg.ProxyURL)
+		raw = append(raw, cfg.Pool...)
+	}
+
+	seen := make(map[string]bool)
+	candidates := make([]string, 0, len(raw))
+	for _, entry := range raw {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || seen[entry] {
+			continue
+		}
+		seen[entry] = true
+		candidates = append(candidates, entry)
+	}
+	if len(candidates) == 0 && config.WhatsappProxy != "" {
+		candidates = append(candidates, config.WhatsappProxy)
+	}
+	return candidates
+}
+
+// applyProxy points the client at the device's first proxy candidate and
+// resets the instance's failover state. No candidates means a direct
+// connection; the client's transport is only reset when it used a proxy.
+// A pooled device has its proxies probed in the background so failover can
+// pick a healthy one without dialing.
+func applyProxy(client *whatsmeow.Client, instance *DeviceInstance, cfg *domainChatStorage.DeviceProxyConfig, logger waLog.Logger) {
+	candidates := proxyCandidates(cfg)
+	active, hadProxy := instance.setProxyCandidates(candidates)
+	if len(candidates) > 1 {
+		proxyHealth.refresh(candidates)
+	}
+	if active == "" && !hadProxy {
+		return
+	}
+	if err := client.SetProxyAddress(active); err != nil {
+		logger.Errorf("failed to apply proxy %s for device %s: %v", RedactProxyURL(active), instance.ID(), err)
+		return
+	}
+	if active == "" {
+		logger.Infof("removed outbound proxy for device %s", instance.ID())
+		return
+	}
+	logger.Infof("applied outbound proxy %s for device %s", RedactProxyURL(active), instance.ID())
+}
+
+// failoverProxy counts a failed connection attempt against the active proxy.
+// Once the attempts reach WHATSAPP_PROXY_FAILOVER_ATTEMPTS it moves the client
+// to the next pool entry last known to be healthy, wrapping around. It only
+// reads cached probe results (stale ones are re-probed in the background), so
+// it never blocks the reconnect hook or an HTTP handler on the network. When no
+// entry is known healthy, the next one not known to be down is used, and
+// failing that the next one, so the device keeps cycling through its pool.
+func failoverProxy(client *whatsmeow.Client, instance *DeviceInstance) {
+	candidates, due := instance.recordProxyFailure(config.WhatsappProxyFailoverAttempts)
+	if !due {
+		return
+	}
+
+	next, fallback := "", ""
+	for _, candidate := range candidates {
+		healthy, known := proxyHealth.status(candidate)
+		if healthy {
+			if next == "" {
+				next = candidate
+			}
+			continue
+		}
+		if !known && fallback == "" {
+			fallback = candidate
+		}
+	}
+	if next == "" {
+		next = fallback
+	}
+	if next == "" {
+		next = candidates[0]
+	}
+
+	if err := client.SetProxyAddress(next); err != nil {
+		log.Errorf("failed to fail over to proxy %s for device %s: %v", RedactProxyURL(next), instance.ID(), err)
+		return
+	}
+	instance.setActiveProxy(next)
+	log.Warnf("device %s switched to proxy %s after repeated connection failures", instance.ID(), RedactProxyURL(next))
 }
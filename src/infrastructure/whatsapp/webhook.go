@@ -4,19 +4,30 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
 	"net/http"
+	neturl "net/url"
 	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
-	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func submitWebhook(ctx context.Context, payload map[string]any, url string, webhookConfig *chatstorage.DeviceWebhookConfig) error {
+func submitWebhook(ctx context.Context, payload map[string]any, url string, webhookConfig *chatstorage.DeviceWebhookConfig) (err error) {
+	event, _ := payload["event"].(string)
+	ctx, span := telemetry.Start(ctx, "webhook.submit", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		telemetry.AttrEvent.String(event),
+		semconv.HTTPRequestMethodKey.String(webhookMethod(webhookConfig)),
+		semconv.URLFull(redactWebhookURL(url)),
+	))
+	defer func() { telemetry.End(span, err) }()
+
 	// Determine effective config - use device-specific if set, otherwise fall back to global
 	insecureSkipVerify := config.WhatsappWebhookInsecureSkipVerify
 	webhookSecret := config.WhatsappWebhookSecret
@@ -41,28 +52,33 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string, webh
 		Transport: transport,
 	}
 
-	postBody, err := json.Marshal(payload)
+	postBody, err := renderWebhookBody(payload, webhookConfig)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+		return pkgError.WebhookError(fmt.Sprintf("Failed to build body: %v", err))
 	}
 
 	// Pass the body to NewRequestWithContext so it sets ContentLength and GetBody.
 	// Building the request with a nil body makes Go fall back to chunked transfer
 	// encoding with no Content-Length, which some receivers (notably PHP reading
 	// php://input behind nginx/FPM) deliver to the application as an empty body.
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(postBody))
+	req, err := http.NewRequestWithContext(ctx, webhookMethod(webhookConfig), url, bytes.NewReader(postBody))
 	if err != nil {
 		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
 	}
 
-	secretKey := []byte(webhookSecret)
-	signature, err := utils.GetMessageDigestOrSignature(postBody, secretKey)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+	req.Header.Set("Content-Type", webhookContentType(postBody, webhookConfig))
+	// Static device headers may replace Content-Type (e.g. for a form-encoded
+	// template) but never the signature, which is set after them.
+	if webhookConfig != nil {
+		for name, value := range webhookConfig.WebhookHeaders {
+			req.Header.Set(name, value)
+		}
 	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
+	if header, signature := signWebhookBody(postBody, webhookSecret, webhookConfig); header != "" {
+		req.Header.Set(header, signature)
+	}
+	// W3C traceparent/tracestate so receivers can join the originating trace.
+	telemetry.InjectHTTPHeaders(ctx, req.Header)
 
 	var attempt int
 	var maxAttempts = 5
@@ -80,7 +96,9 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string, webh
 		resp, err := client.Do(req)
 		if err == nil {
 			defer resp.Body.Close()
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
 			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				span.SetAttributes(attribute.Int("gowa.webhook.attempts", attempt+1))
 				logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
 				return nil
 			}
@@ -95,3 +113,16 @@ func submitWebhook(ctx context.Context, payload map[string]any, url string, webh
 
 	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
 }
+
+// redactWebhookURL strips userinfo and the query string from a webhook URL
+// before it is recorded on a span, since both commonly carry credentials.
+func redactWebhookURL(raw string) string {
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
@@ -0,0 +1,122 @@
+package whatsapp
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+)
+
+var webhookSignatures = map[string]struct {
+	header string
+	hash   func() hash.Hash
+}{
+	chatstorage.WebhookSignatureSHA256: {"X-Hub-Signature-256", sha256.New},
+	chatstorage.WebhookSignatureSHA1:   {"X-Hub-Signature", sha1.New},
+	chatstorage.WebhookSignatureSHA512: {"X-Hub-Signature-512", sha512.New},
+}
+
+// webhookTemplate is a parsed body template with the source it was parsed from.
+type webhookTemplate struct {
+	source string
+	tmpl   *template.Template
+}
+
+// webhookTemplates caches the parsed body template of each device, keyed by
+// the device of the payload. An entry is replaced when the device's template
+// changes, so the cache holds at most one template per device.
+var webhookTemplates sync.Map
+
+// deviceWebhookTemplate returns the parsed body template of a device,
+// re-parsing it when the configured source changed.
+func deviceWebhookTemplate(deviceKey, source string) (*template.Template, error) {
+	if deviceKey != "" {
+		if cached, ok := webhookTemplates.Load(deviceKey); ok && cached.(webhookTemplate).source == source {
+			return cached.(webhookTemplate).tmpl, nil
+		}
+	}
+	tmpl, err := utils.ParseWebhookTemplate(source)
+	if err != nil {
+		return nil, err
+	}
+	if deviceKey != "" {
+		webhookTemplates.Store(deviceKey, webhookTemplate{source: source, tmpl: tmpl})
+	}
+	return tmpl, nil
+}
+
+// webhookMethod returns the HTTP method a device webhook is delivered with.
+func webhookMethod(cfg *chatstorage.DeviceWebhookConfig) string {
+	if cfg != nil && cfg.WebhookMethod != "" {
+		return strings.ToUpper(cfg.WebhookMethod)
+	}
+	return http.MethodPost
+}
+
+// renderWebhookBody builds the request body: the payload rendered through the
+// device's body template, or the payload itself as JSON.
+func renderWebhookBody(payload map[string]any, cfg *chatstorage.DeviceWebhookConfig) ([]byte, error) {
+	if cfg == nil || cfg.WebhookTemplate == "" {
+		return json.Marshal(payload)
+	}
+	deviceKey, _ := payload["device_id"].(string)
+	tmpl, err := deviceWebhookTemplate(deviceKey, cfg.WebhookTemplate)
+	if err != nil {
+		return nil, err
+	}
+	// Round-trip through JSON so templates see the same field names and shapes
+	// as receivers of the raw payload (e.g. times as strings, structs as maps).
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber() // keep timestamps and ids from rendering as floats
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// webhookContentType returns the Content-Type of a request body: JSON for the
+// raw payload and for templates that render JSON, plain text otherwise.
+// Devices can still set their own through WebhookHeaders.
+func webhookContentType(body []byte, cfg *chatstorage.DeviceWebhookConfig) string {
+	if cfg == nil || cfg.WebhookTemplate == "" || json.Valid(body) {
+		return "application/json"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// signWebhookBody returns the signature header of a body, or an empty name
+// when the device disabled signing.
+func signWebhookBody(body []byte, secret string, cfg *chatstorage.DeviceWebhookConfig) (string, string) {
+	algorithm := chatstorage.WebhookSignatureSHA256
+	if cfg != nil && cfg.WebhookSignatureAlgorithm != "" {
+		algorithm = strings.ToLower(cfg.WebhookSignatureAlgorithm)
+	}
+	signature, ok := webhookSignatures[algorithm]
+	if !ok {
+		return "", ""
+	}
+	mac := hmac.New(signature.hash, []byte(secret))
+	mac.Write(body)
+	return signature.header, algorithm + "=" + hex.EncodeToString(mac.Sum(nil))
+}
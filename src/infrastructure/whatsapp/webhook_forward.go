@@ -12,10 +12,12 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -41,6 +43,9 @@ var (
 	// registered via POST /devices, e.g. "org_2") for a connected WhatsApp JID.
 	// It is a seam so tests can stub the device-manager lookup.
 	sessionIDForJIDFn = sessionIDForJID
+	// deviceTagsForSessionFn resolves the operator-assigned tags of a session.
+	// It is a seam so tests can stub the device-manager lookup.
+	deviceTagsForSessionFn = deviceTagsForSession
 )
 
 // mutexShardCount is the number of mutex shards for contact synchronization.
@@ -97,8 +102,14 @@ func getContactMutex(phone string) *sync.Mutex {
 // forwardPayloadToConfiguredWebhooks attempts to deliver the provided payload to every configured webhook URL.
 // It only returns an error when all webhook deliveries fail. Partial failures are logged and suppressed so
 // successful targets still receive the event.
-func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]any, eventName string) error {
+func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]any, eventName string) (err error) {
 	deviceJID, _ := payload["device_id"].(string)
+	ctx, span := telemetry.Start(ctx, "webhook.forward", trace.WithAttributes(
+		telemetry.AttrEvent.String(eventName),
+		telemetry.AttrDeviceID.String(deviceJID),
+	))
+	defer func() { telemetry.End(span, err) }()
+
 	webhookConfig, err := getWebhookConfigForDevice(deviceJID)
 	if err != nil {
 		// A config lookup failure is not a delivery failure: fall back to the global
@@ -107,6 +118,13 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 		webhookConfig = nil
 	}
 
+	return forwardPayloadWithWebhookConfig(ctx, payload, eventName, webhookConfig)
+}
+
+// forwardPayloadWithWebhookConfig delivers a payload using an already resolved
+// device webhook config (nil for the global one). Callers that outlive the
+// device's JID, such as a remote logout, resolve the config first.
+func forwardPayloadWithWebhookConfig(ctx context.Context, payload map[string]any, eventName string, webhookConfig *domainChatStorage.DeviceWebhookConfig) error {
 	webhookAllowed := isEventWhitelistedForDevice(eventName, webhookConfig) &&
 		!shouldIgnoreWebhookJID(payload)
 	chatwootAllowed := config.ChatwootEnabled && shouldForwardEventToChatwoot(eventName) && isEventWhitelistedForChatwoot(eventName)
@@ -128,6 +146,7 @@ func forwardPayloadToConfiguredWebhooks(ctx context.Context, payload map[string]
 	// shared payload map is never mutated concurrently.
 	if webhookAllowed {
 		addWebhookSessionID(payload)
+		addWebhookDeviceTags(payload)
 	}
 
 	var webhookErr error
@@ -173,12 +192,21 @@ func getWebhookConfigForDevice(deviceJID string) (*domainChatStorage.DeviceWebho
 	}
 	if record != nil && record.WebhookURL != nil && *record.WebhookURL != "" {
 		logrus.Debugf("Using device-specific webhook config for %s", deviceJID)
-		return &domainChatStorage.DeviceWebhookConfig{
+		webhookConfig := &domainChatStorage.DeviceWebhookConfig{
 			WebhookURL:                record.WebhookURL,
 			WebhookSecret:             record.WebhookSecret,
 			WebhookEvents:             record.WebhookEvents,
 			WebhookInsecureSkipVerify: record.WebhookInsecureSkipVerify,
-		}, nil
+			WebhookMethod:             record.WebhookMethod,
+			WebhookTemplate:           record.WebhookTemplate,
+			WebhookSignatureAlgorithm: record.WebhookSignatureAlgorithm,
+		}
+		if record.WebhookHeaders != "" {
+			if err := json.Unmarshal([]byte(record.WebhookHeaders), &webhookConfig.WebhookHeaders); err != nil {
+				return nil, fmt.Errorf("failed to decode webhook headers: %w", err)
+			}
+		}
+		return webhookConfig, nil
 	}
 
 	return nil, nil
@@ -268,6 +296,37 @@ func sessionIDForJID(jid string) string {
 	return ""
 }
 
+// addWebhookDeviceTags injects the tags of the payload's session as
+// device_tags, so receivers can route events by tenant or team without keeping
+// their own device map. Untagged or unresolved sessions get no field.
+func addWebhookDeviceTags(payload map[string]any) {
+	if payload == nil {
+		return
+	}
+	if _, exists := payload["device_tags"]; exists {
+		return
+	}
+	sessionID, _ := payload["session_id"].(string)
+	if tags := deviceTagsForSessionFn(sessionID); len(tags) > 0 {
+		payload["device_tags"] = tags
+	}
+}
+
+// deviceTagsForSession returns the tags of a device slot by its session id.
+func deviceTagsForSession(sessionID string) []string {
+	if sessionID == "" {
+		return nil
+	}
+	dm := GetDeviceManager()
+	if dm == nil {
+		return nil
+	}
+	if inst, ok := dm.GetDevice(sessionID); ok && inst != nil {
+		return inst.Tags()
+	}
+	return nil
+}
+
 // forwardToWebhooks delivers the payload to each URL in the webhookURLs slice.
 // It logs successes and failures, returning an error only if all deliveries fail.
 // Partial failures (some succeed, some fail) are logged but do not cause a return error.
@@ -322,6 +381,7 @@ type chatwootSyncResult struct {
 	MessageID      int
 	ConversationID int
 	InboxID        int
+	Contact        *chatwoot.Contact
 }
 
 // extractChatwootContactInfo extracts contact identifier and name from message payload.
@@ -940,10 +1000,11 @@ func syncMessageToChatwoot(cw *chatwoot.Client, info *chatwootContactInfo, conte
 		MessageID:      msgID,
 		ConversationID: conversation.ID,
 		InboxID:        cw.InboxID,
+		Contact:        contact,
 	}, nil
 }
 
-func chatwootLinkStorageFromContext(ctx context.Context) (string, domainChatStorage.IChatStorageRepository) {
+func deviceStorageFromContext(ctx context.Context) (string, domainChatStorage.IChatStorageRepository) {
 	instance, ok := DeviceFromContext(ctx)
 	if !ok || instance == nil {
 		return "", nil
@@ -1168,7 +1229,7 @@ func isRetryableChatwootForwardEvent(eventName string) bool {
 	}
 }
 
-func enqueueChatwootForwardRetry(linkRepo domainChatStorage.IChatStorageRepository, deviceID, eventName string, payload map[string]any, syncErr error) bool {
+func enqueueChatwootForwardRetry(ctx context.Context, linkRepo domainChatStorage.IChatStorageRepository, deviceID, eventName string, payload map[string]any, syncErr error) bool {
 	if !isRetryableChatwootForwardEvent(eventName) || linkRepo == nil || strings.TrimSpace(deviceID) == "" || !chatwoot.Retryable(syncErr) {
 		return false
 	}
@@ -1191,6 +1252,7 @@ func enqueueChatwootForwardRetry(linkRepo domainChatStorage.IChatStorageReposito
 		PayloadJSON:       string(payloadJSON),
 		LastError:         truncateChatwootForwardError(syncErr),
 		NextAttemptAt:     time.Now().Add(chatwootForwardRetryDelay(0)),
+		TraceParent:       telemetry.TraceParent(ctx),
 	}
 	if err := linkRepo.EnqueueChatwootForwardEvent(event); err != nil {
 		logrus.Errorf("Chatwoot: Failed to enqueue retry for %s: %v", messageID, err)
@@ -1304,6 +1366,7 @@ func syncPayloadToChatwoot(ctx context.Context, payload map[string]any, eventNam
 	if err != nil {
 		return err
 	}
+	go cw.SyncContactAvatar(ctx, ClientFromContext(ctx), result.Contact, info.ChatJID, false)
 	if eventName == "message" && linkRepo != nil {
 		if link := buildChatwootForwardMessageLink(deviceID, resolved.ConfigID, cw.AccountID, data, msgOpts, result); link != nil {
 			if err := linkRepo.UpsertChatwootMessageLink(link); err != nil {
@@ -1316,14 +1379,20 @@ func syncPayloadToChatwoot(ctx context.Context, payload map[string]any, eventNam
 
 func forwardToChatwoot(ctx context.Context, payload map[string]any, eventName string) {
 	logrus.Infof("Chatwoot: Attempting to forward %s...", eventName)
-	deviceID, linkRepo := chatwootLinkStorageFromContext(ctx)
-	if err := syncPayloadToChatwoot(ctx, payload, eventName, deviceID, linkRepo); err != nil {
+	deviceID, linkRepo := deviceStorageFromContext(ctx)
+	ctx, span := telemetry.Start(ctx, "chatwoot.forward", trace.WithAttributes(
+		telemetry.AttrEvent.String(eventName),
+		telemetry.AttrDeviceID.String(deviceID),
+	))
+	err := syncPayloadToChatwoot(ctx, payload, eventName, deviceID, linkRepo)
+	telemetry.End(span, err)
+	if err != nil {
 		logrus.Errorf("Chatwoot: %v", err)
-		enqueueChatwootForwardRetry(linkRepo, deviceID, eventName, payload, err)
+		enqueueChatwootForwardRetry(ctx, linkRepo, deviceID, eventName, payload, err)
 	}
 }
 
-func processChatwootForwardRetryEvent(repo domainChatStorage.IChatStorageRepository, event *domainChatStorage.ChatwootForwardEvent) error {
+func processChatwootForwardRetryEvent(repo domainChatStorage.IChatStorageRepository, event *domainChatStorage.ChatwootForwardEvent) (err error) {
 	if event == nil {
 		return nil
 	}
@@ -1341,6 +1410,13 @@ func processChatwootForwardRetryEvent(repo domainChatStorage.IChatStorageReposit
 			ctx = ContextWithDevice(ctx, instance)
 		}
 	}
+	// The retry runs in its own trace, linked to the forward that queued it.
+	ctx, span := telemetry.Start(ctx, "chatwoot.forward.retry", telemetry.LinkTraceParent(event.TraceParent), trace.WithAttributes(
+		telemetry.AttrEvent.String(event.EventName),
+		telemetry.AttrDeviceID.String(event.DeviceID),
+		telemetry.AttrMessageID.String(event.WhatsAppMessageID),
+	))
+	defer func() { telemetry.End(span, err) }()
 	return syncPayloadToChatwoot(ctx, payload, event.EventName, event.DeviceID, repo)
 }
 
@@ -1424,7 +1500,8 @@ func isEventWhitelisted(eventName string) bool {
 }
 
 // getGroupName fetches the group name from WhatsApp using the group JID.
-// Uses a TTL cache to avoid repeated API calls for the same group.
+// Uses a TTL cache and the persisted group metadata to avoid repeated API calls
+// for the same group.
 func getGroupName(ctx context.Context, groupJID string) string {
 	// Check cache first
 	if name, ok := getCachedGroupName(groupJID); ok {
@@ -1432,6 +1509,15 @@ func getGroupName(ctx context.Context, groupJID string) string {
 		return name
 	}
 
+	// Then the group metadata persisted from group events
+	deviceID, repo := deviceStorageFromContext(ctx)
+	if repo != nil && deviceID != "" {
+		if metadata, err := repo.GetGroupMetadata(deviceID, groupJID); err == nil && metadata != nil && metadata.Name != "" {
+			setCachedGroupName(groupJID, metadata.Name)
+			return metadata.Name
+		}
+	}
+
 	client := ClientFromContext(ctx)
 	if client == nil {
 		logrus.Debug("Chatwoot: ClientFromContext returned nil, trying GetClient()")
@@ -1464,6 +1550,11 @@ func getGroupName(ctx context.Context, groupJID string) string {
 		logrus.Infof("Chatwoot: Got group name: %s", groupInfo.Name)
 		// Cache the result
 		setCachedGroupName(groupJID, groupInfo.Name)
+		if repo != nil && deviceID != "" {
+			if err := repo.SaveGroupMetadata(GroupMetadataFromInfo(deviceID, groupInfo)); err != nil {
+				logrus.Debugf("Chatwoot: Failed to persist group info for %s: %v", groupJID, err)
+			}
+		}
 		return groupInfo.Name
 	}
 
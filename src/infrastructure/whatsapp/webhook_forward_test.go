@@ -11,6 +11,10 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 type chatwootForwardQueueTestRepo struct {
@@ -219,7 +223,7 @@ func TestEnqueueChatwootForwardRetryStoresTransientMessageFailure(t *testing.T)
 		},
 	}
 
-	queued := enqueueChatwootForwardRetry(repo, "device-a@s.whatsapp.net", "message", payload, &chatwoot.HTTPStatusError{
+	queued := enqueueChatwootForwardRetry(context.Background(), repo, "device-a@s.whatsapp.net", "message", payload, &chatwoot.HTTPStatusError{
 		StatusCode: http.StatusInternalServerError,
 		Op:         "create message",
 		Body:       "unavailable",
@@ -245,7 +249,7 @@ func TestEnqueueChatwootForwardRetrySkipsPermanentFailure(t *testing.T) {
 		"payload": map[string]any{"id": "wa-permanent"},
 	}
 
-	queued := enqueueChatwootForwardRetry(repo, "device-a@s.whatsapp.net", "message", payload, &chatwoot.HTTPStatusError{
+	queued := enqueueChatwootForwardRetry(context.Background(), repo, "device-a@s.whatsapp.net", "message", payload, &chatwoot.HTTPStatusError{
 		StatusCode: http.StatusBadRequest,
 		Op:         "create message",
 		Body:       "bad payload",
@@ -307,6 +311,44 @@ func TestProcessDueChatwootForwardRetriesReschedulesOnRegistryUnavailable(t *tes
 	}
 }
 
+func TestChatwootForwardRetryLinksToQueuingTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	telemetry.Install(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	orig := getChatwootClientFn
+	t.Cleanup(func() { getChatwootClientFn = orig })
+	getChatwootClientFn = func(string) (*chatwoot.ResolvedConfig, error) { return nil, nil }
+
+	queue := &chatwootForwardQueueTestRepo{}
+	ctx, parent := telemetry.Start(context.Background(), "test.forward")
+	enqueueChatwootForwardRetry(ctx, queue, "dev", "message", map[string]any{"payload": map[string]any{"id": "wa-1"}},
+		&chatwoot.HTTPStatusError{StatusCode: http.StatusBadGateway, Op: "create message"})
+	parent.End()
+	if len(queue.events) != 1 || queue.events[0].TraceParent == "" {
+		t.Fatalf("expected the queued retry to carry the trace parent, got %+v", queue.events)
+	}
+
+	queued := queue.events[0]
+	queued.ID = 3
+	processDueChatwootForwardRetries(&retryWorkerTestRepo{due: []*chatstorage.ChatwootForwardEvent{queued}})
+
+	for _, span := range exporter.GetSpans() {
+		if span.Name != "chatwoot.forward.retry" {
+			continue
+		}
+		if span.Parent.IsValid() {
+			t.Error("the retry span should start its own trace")
+		}
+		if len(span.Links) != 1 || span.Links[0].SpanContext.SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("retry span links = %+v, want the queuing span", span.Links)
+		}
+		return
+	}
+	t.Fatal("expected a chatwoot.forward.retry span")
+}
+
 func TestEnqueueChatwootForwardRetrySkipsRegistryUnavailable(t *testing.T) {
 	// An uninitialized registry is a wiring/startup condition, not a transient
 	// network failure. The live path must NOT enqueue a retry that would only
@@ -316,7 +358,7 @@ func TestEnqueueChatwootForwardRetrySkipsRegistryUnavailable(t *testing.T) {
 		"payload": map[string]any{"id": "wa-no-registry"},
 	}
 
-	queued := enqueueChatwootForwardRetry(repo, "device-a@s.whatsapp.net", "message", payload, chatwoot.ErrClientRegistryUnavailable)
+	queued := enqueueChatwootForwardRetry(context.Background(), repo, "device-a@s.whatsapp.net", "message", payload, chatwoot.ErrClientRegistryUnavailable)
 	if queued {
 		t.Fatal("registry-unavailable failure should not be queued")
 	}
@@ -705,3 +747,29 @@ func TestForwardPayloadInjectsSessionID(t *testing.T) {
 		t.Fatalf("expected forwarded payload session_id=org_2, got %v", captured["session_id"])
 	}
 }
+
+// TestAddWebhookDeviceTags verifies the session's tags ride along as
+// device_tags, and that untagged sessions keep the payload unchanged.
+func TestAddWebhookDeviceTags(t *testing.T) {
+	orig := deviceTagsForSessionFn
+	defer func() { deviceTagsForSessionFn = orig }()
+	deviceTagsForSessionFn = func(sessionID string) []string {
+		if sessionID == "org_2" {
+			return []string{"tenant-acme", "support"}
+		}
+		return nil
+	}
+
+	payload := map[string]any{"device_id": "556283088170@s.whatsapp.net", "session_id": "org_2"}
+	addWebhookDeviceTags(payload)
+	tags, ok := payload["device_tags"].([]string)
+	if !ok || len(tags) != 2 || tags[0] != "tenant-acme" {
+		t.Fatalf("expected device_tags of org_2, got %v", payload["device_tags"])
+	}
+
+	untagged := map[string]any{"device_id": "x@s.whatsapp.net", "session_id": "org_3"}
+	addWebhookDeviceTags(untagged)
+	if _, ok := untagged["device_tags"]; ok {
+		t.Fatalf("expected no device_tags for an untagged session, got %v", untagged["device_tags"])
+	}
+}
@@ -2,17 +2,29 @@ package whatsapp
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 type capturedRequest struct {
+	method           string
 	contentLength    int64
 	transferEncoding []string
 	body             string
+	header           http.Header
 }
 
 // startCapturingWebhookServer returns a server that records every request it
@@ -33,9 +45,11 @@ func startCapturingWebhookServer(t *testing.T, statuses ...int) (*httptest.Serve
 		mu.Lock()
 		idx := len(captured)
 		captured = append(captured, capturedRequest{
+			method:           r.Method,
 			contentLength:    r.ContentLength,
 			transferEncoding: r.TransferEncoding,
 			body:             string(body),
+			header:           r.Header.Clone(),
 		})
 		mu.Unlock()
 
@@ -96,3 +110,128 @@ func TestSubmitWebhookRetryKeepsFullBody(t *testing.T) {
 		t.Errorf("retry Content-Length = %d, want %d", reqs[1].contentLength, len(reqs[1].body))
 	}
 }
+
+func TestSubmitWebhookPropagatesTraceContext(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	telemetry.Install(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	srv, captured := startCapturingWebhookServer(t, http.StatusOK)
+
+	ctx, parent := telemetry.Start(context.Background(), "test.parent")
+	payload := map[string]any{"event": "message", "body": "hello"}
+	if err := submitWebhook(ctx, payload, srv.URL+"/hook?token=secret", nil); err != nil {
+		t.Fatalf("submitWebhook returned error: %v", err)
+	}
+	parent.End()
+
+	if len(*captured) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(*captured))
+	}
+	traceparent := (*captured)[0].header.Get("Traceparent")
+	if traceparent == "" {
+		t.Fatal("expected traceparent header on webhook request")
+	}
+
+	var submit *tracetest.SpanStub
+	spans := exporter.GetSpans()
+	for i := range spans {
+		if spans[i].Name == "webhook.submit" {
+			submit = &spans[i]
+		}
+	}
+	if submit == nil {
+		t.Fatalf("expected webhook.submit span, got %d spans", len(spans))
+	}
+	if submit.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("webhook.submit span should be a child of the caller span")
+	}
+	if want := submit.SpanContext.SpanID().String(); !strings.Contains(traceparent, want) {
+		t.Errorf("traceparent %q should reference the webhook.submit span %s", traceparent, want)
+	}
+	for _, attr := range submit.Attributes {
+		if strings.Contains(attr.Value.Emit(), "secret") {
+			t.Errorf("span attribute %s leaks the webhook query string", attr.Key)
+		}
+	}
+}
+
+func TestSubmitWebhookAppliesDeviceDeliverySettings(t *testing.T) {
+	srv, captured := startCapturingWebhookServer(t, http.StatusOK)
+
+	cfg := &chatstorage.DeviceWebhookConfig{
+		WebhookSecret:             "device-secret",
+		WebhookMethod:             http.MethodPut,
+		WebhookHeaders:            map[string]string{"Authorization": "Bearer t0ken", "Content-Type": "text/plain"},
+		WebhookTemplate:           `{{ .event }} from {{ .payload.from }} at {{ .payload.timestamp }}: {{ json .payload.body }}`,
+		WebhookSignatureAlgorithm: "sha512",
+	}
+	payload := map[string]any{
+		"event":   "message",
+		"payload": map[string]any{"from": "628123@s.whatsapp.net", "timestamp": int64(1752404762), "body": `say "hi"`},
+	}
+	if err := submitWebhook(context.Background(), payload, srv.URL, cfg); err != nil {
+		t.Fatalf("submitWebhook returned error: %v", err)
+	}
+
+	got := (*captured)[0]
+	if got.method != http.MethodPut {
+		t.Errorf("method = %s, want PUT", got.method)
+	}
+	if want := `message from 628123@s.whatsapp.net at 1752404762: "say \"hi\""`; got.body != want {
+		t.Errorf("body = %q, want %q", got.body, want)
+	}
+	if got.header.Get("Authorization") != "Bearer t0ken" || got.header.Get("Content-Type") != "text/plain" {
+		t.Errorf("custom headers not sent: %v", got.header)
+	}
+
+	mac := hmac.New(sha512.New, []byte("device-secret"))
+	mac.Write([]byte(got.body))
+	if want := "sha512=" + hex.EncodeToString(mac.Sum(nil)); got.header.Get("X-Hub-Signature-512") != want {
+		t.Errorf("X-Hub-Signature-512 = %q, want %q", got.header.Get("X-Hub-Signature-512"), want)
+	}
+	if got.header.Get("X-Hub-Signature-256") != "" {
+		t.Error("the default sha256 signature must not be sent alongside sha512")
+	}
+}
+
+func TestSubmitWebhookWithoutSignature(t *testing.T) {
+	srv, captured := startCapturingWebhookServer(t, http.StatusOK)
+
+	cfg := &chatstorage.DeviceWebhookConfig{WebhookSignatureAlgorithm: "none"}
+	if err := submitWebhook(context.Background(), map[string]any{"event": "message"}, srv.URL, cfg); err != nil {
+		t.Fatalf("submitWebhook returned error: %v", err)
+	}
+	got := (*captured)[0]
+	if got.method != http.MethodPost || got.body != `{"event":"message"}` {
+		t.Errorf("unexpected default delivery: %s %s", got.method, got.body)
+	}
+	for name := range got.header {
+		if strings.HasPrefix(name, "X-Hub-Signature") {
+			t.Errorf("unexpected signature header %s", name)
+		}
+	}
+}
+
+func TestSubmitWebhookTemplateContentType(t *testing.T) {
+	srv, captured := startCapturingWebhookServer(t, http.StatusOK)
+
+	payload := map[string]any{"event": "message", "device_id": "628111@s.whatsapp.net", "payload": map[string]any{"body": "hi"}}
+	text := &chatstorage.DeviceWebhookConfig{WebhookTemplate: `text={{ .payload.body }}`}
+	if err := submitWebhook(context.Background(), payload, srv.URL, text); err != nil {
+		t.Fatalf("submitWebhook returned error: %v", err)
+	}
+	structured := &chatstorage.DeviceWebhookConfig{WebhookTemplate: `{"text": {{ json .payload.body }}}`}
+	if err := submitWebhook(context.Background(), payload, srv.URL, structured); err != nil {
+		t.Fatalf("submitWebhook returned error: %v", err)
+	}
+
+	if got := (*captured)[0]; got.body != "text=hi" || got.header.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("text template sent %q as %q", got.body, got.header.Get("Content-Type"))
+	}
+	// The device's template changed: its cached template is replaced.
+	if got := (*captured)[1]; got.body != `{"text": "hi"}` || got.header.Get("Content-Type") != "application/json" {
+		t.Errorf("JSON template sent %q as %q", got.body, got.header.Get("Content-Type"))
+	}
+}
@@ -83,6 +83,24 @@ func (err notFoundError) StatusCode() int {
 	return http.StatusNotFound
 }
 
+// ConflictError reports a request that clashes with existing state, e.g.
+// restoring a session that is already present.
+type ConflictError string
+
+func (err ConflictError) Error() string {
+	return string(err)
+}
+
+// ErrCode will return the error code based on the error data type
+func (err ConflictError) ErrCode() string {
+	return "CONFLICT"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (err ConflictError) StatusCode() int {
+	return http.StatusConflict
+}
+
 var (
 	ErrAlreadyLoggedIn = LoginError("you are already logged in.")
 	ErrNotConnected    = AuthError("you are not connect to services server, please reconnect")
@@ -91,4 +109,8 @@ var (
 	ErrQrChannel       = qrChannelError("QR channel error")
 	ErrSessionSaved   = sessionSavedError("your session have been saved, please wait to connect 2 second and refresh again")
 	ErrDeviceNotFound = notFoundError("device not found")
+	ErrPostNotFound   = notFoundError("newsletter post not found")
+	ErrContactNotFound = notFoundError("contact not found")
+	ErrOAuthClientNotFound = notFoundError("oauth client not found")
+	ErrOAuthGrantNotFound  = notFoundError("oauth grant not found")
 )
@@ -87,6 +87,23 @@ func (e WaReachoutTimelockError) StatusCode() int {
 	return http.StatusTooManyRequests
 }
 
+type WaHistoryBackfillLimitError string
+
+// Error for complying the error interface
+func (e WaHistoryBackfillLimitError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e WaHistoryBackfillLimitError) ErrCode() string {
+	return "HISTORY_BACKFILL_LIMITED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e WaHistoryBackfillLimitError) StatusCode() int {
+	return http.StatusTooManyRequests
+}
+
 const (
 	ErrUserNotRegistered  = InvalidJID("user is not registered")
 	ErrWaCLI              = WaCliError("your WhatsApp CLI is invalid or empty")
@@ -0,0 +1,174 @@
+// Package telemetry wires OpenTelemetry tracing for gowa. When tracing is
+// disabled the global no-op provider stays in place, so the Start/End helpers
+// used across the REST, usecase and infrastructure layers cost next to nothing.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies spans created by this module.
+const InstrumentationName = "github.com/aldinokemal/go-whatsapp-web-multidevice"
+
+// Attribute keys shared by gowa spans that have no semconv equivalent.
+const (
+	AttrDeviceID  = attribute.Key("gowa.device_id")
+	AttrEvent     = attribute.Key("gowa.event")
+	AttrRecipient = attribute.Key("gowa.recipient")
+	AttrMessageID = attribute.Key("gowa.message_id")
+)
+
+// Init installs an OTLP/HTTP exporting tracer provider when config.OtelEnabled
+// is set. The returned shutdown func flushes pending spans and is always
+// non-nil, so callers can defer it unconditionally.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !config.OtelEnabled {
+		return noop, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if endpoint := strings.TrimSpace(config.OtelEndpoint); endpoint != "" {
+		if strings.Contains(endpoint, "://") {
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(endpoint))
+		}
+	}
+	if config.OtelInsecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return noop, fmt.Errorf("create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.OtelServiceName),
+		semconv.ServiceVersion(config.AppVersion),
+	))
+	if err != nil {
+		return noop, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.OtelSampleRatio))),
+	)
+	Install(provider)
+	return provider.Shutdown, nil
+}
+
+// Install sets provider as the global tracer provider together with the W3C
+// trace-context and baggage propagators. Tests use it with an in-memory
+// exporter.
+func Install(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+}
+
+// Tracer returns the module tracer from the current global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// Start opens a span named name as a child of any span already in ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End records err (when non-nil) on span, marks its status and ends it.
+// Intended for `defer func() { telemetry.End(span, err) }()` with named
+// returns.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectHTTPHeaders writes the trace context carried by ctx into header so
+// downstream receivers (webhook targets, Chatwoot) can join the trace.
+func InjectHTTPHeaders(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// TraceParent encodes the span carried by ctx as a W3C traceparent value, or
+// returns "" when ctx carries none. Work queued for later, such as Chatwoot
+// forward retries, stores it to link back to the trace that queued it.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// LinkTraceParent returns a start option linking the new span to the span
+// encoded in traceParent. Empty or malformed values add no link.
+func LinkTraceParent(traceParent string) trace.SpanStartOption {
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{"traceparent": traceParent})
+	link := trace.LinkFromContext(ctx)
+	if !link.SpanContext.IsValid() {
+		return trace.WithLinks()
+	}
+	return trace.WithLinks(link)
+}
+
+// Transport wraps base so every outbound request gets a client span and
+// propagated trace headers. base defaults to http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &tracingTransport{base: base}
+}
+
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.URLPath(req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	InjectHTTPHeaders(ctx, req.Header)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
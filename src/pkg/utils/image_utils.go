@@ -31,6 +31,16 @@ func ProcessGroupPhoto(file *multipart.FileHeader) (*bytes.Buffer, error) {
 	}
 	defer src.Close()
 
+	return processGroupPhoto(src)
+}
+
+// ProcessGroupPhotoBytes is ProcessGroupPhoto for an image already in memory,
+// e.g. one downloaded from a URL.
+func ProcessGroupPhotoBytes(data []byte) (*bytes.Buffer, error) {
+	return processGroupPhoto(bytes.NewReader(data))
+}
+
+func processGroupPhoto(src io.Reader) (*bytes.Buffer, error) {
 	// Decode the image
 	img, format, err := image.Decode(src)
 	if err != nil {
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"encoding/json"
+	"text/template"
+)
+
+// webhookTemplateFuncs are available to webhook body templates on top of the
+// text/template builtins: json encodes a value (quoting strings), default falls
+// back when a value is missing or empty.
+var webhookTemplateFuncs = template.FuncMap{
+	"json": func(value any) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+	"default": func(fallback, value any) any {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// ParseWebhookTemplate parses a device webhook body template with the helpers
+// templates may use.
+func ParseWebhookTemplate(source string) (*template.Template, error) {
+	return template.New("webhook").Funcs(webhookTemplateFuncs).Parse(source)
+}
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+)
+
+// readOnlyToolActions lists tool actions that do not mutate anything and are
+// therefore left out of the audit trail. Every other call is recorded.
+var readOnlyToolActions = map[string]map[string]bool{
+	"whatsapp_message":    {"download_media": true},
+	"whatsapp_chat":       {"list_chats": true, "list_contacts": true, "get_messages": true},
+	"whatsapp_group":      {"info": true, "participants": true, "participant_history": true, "topic_history": true, "join_requests": true, "community_groups": true, "community_participants": true},
+	"whatsapp_app":        {"status": true},
+	"whatsapp_newsletter": {"list": true, "messages": true, "reactions": true},
+	"whatsapp_user":       {"check": true, "info": true, "avatar": true, "business_profile": true, "presence": true, "privacy": true},
+	"whatsapp_device":     {"list": true, "get": true, "health": true, "get_webhook": true},
+}
+
+// auditTargetArgs are the tool arguments, in priority order, that name the
+// chat/user/group a call acts on.
+var auditTargetArgs = []string{"phone", "group_id", "community_id", "chat_jid", "newsletter_id", "jid"}
+
+// auditToolMiddleware records every mutating tool call in the audit trail,
+// mirroring the REST audit middleware. The principal comes from the HTTP
+// layer (basic-auth user or OAuth subject, see route.go).
+func auditToolMiddleware(service domainAudit.IAuditUsecase) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+			operation := toolOperation(request)
+			if readOnlyToolActions[request.Params.Name][operation] {
+				return next(ctx, request)
+			}
+
+			result, err := next(ctx, request)
+			recordToolAudit(ctx, service, request, operation, result, err)
+			return result, err
+		}
+	}
+}
+
+// toolOperation returns the action (or send type) a consolidated tool call
+// dispatches on.
+func toolOperation(request mcpg.CallToolRequest) string {
+	if action := request.GetString("action", ""); action != "" {
+		return action
+	}
+	return request.GetString("type", "")
+}
+
+func recordToolAudit(ctx context.Context, service domainAudit.IAuditUsecase, request mcpg.CallToolRequest, operation string, result *mcpg.CallToolResult, callErr error) {
+	principal, _ := domainAudit.PrincipalFromContext(ctx)
+
+	action := request.Params.Name
+	if operation != "" {
+		action += "." + operation
+	}
+
+	entry := domainAudit.RecordRequest{
+		Source:    domainAudit.SourceMCP,
+		Principal: principal,
+		DeviceID:  toolDeviceID(ctx, request),
+		Action:    action,
+		TargetJID: toolTarget(request),
+		MessageID: strings.TrimSpace(request.GetString("message_id", "")),
+		Result:    domainAudit.ResultSuccess,
+	}
+	switch {
+	case callErr != nil:
+		entry.Result = domainAudit.ResultError
+		entry.ErrorMessage = callErr.Error()
+	case result != nil && result.IsError:
+		entry.Result = domainAudit.ResultError
+		entry.ErrorMessage = toolResultText(result)
+	}
+	if entry.MessageID == "" && result != nil {
+		entry.MessageID = structuredMessageID(result.StructuredContent)
+	}
+
+	if err := service.Record(context.WithoutCancel(ctx), entry); err != nil {
+		logrus.Warnf("Failed to record MCP audit entry for %s: %v", action, err)
+	}
+}
+
+func toolDeviceID(ctx context.Context, request mcpg.CallToolRequest) string {
+	if id := strings.TrimSpace(request.GetString("device_id", "")); id != "" {
+		return id
+	}
+	if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
+		return inst.ID()
+	}
+	return ""
+}
+
+func toolTarget(request mcpg.CallToolRequest) string {
+	for _, arg := range auditTargetArgs {
+		if value := strings.TrimSpace(request.GetString(arg, "")); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func toolResultText(result *mcpg.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcpg.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// structuredMessageID pulls message_id out of a structured tool result (send
+// responses carry the id of the message they created).
+func structuredMessageID(structured any) string {
+	if structured == nil {
+		return ""
+	}
+	raw, err := json.Marshal(structured)
+	if err != nil {
+		return ""
+	}
+	var fields struct {
+		MessageID string `json:"message_id"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return ""
+	}
+	return fields.MessageID
+}
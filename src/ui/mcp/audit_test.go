@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAuditService struct {
+	domainAudit.IAuditUsecase
+	recorded []domainAudit.RecordRequest
+}
+
+func (s *stubAuditService) Record(_ context.Context, request domainAudit.RecordRequest) error {
+	s.recorded = append(s.recorded, request)
+	return nil
+}
+
+func toolReq(name string, args map[string]any) mcpg.CallToolRequest {
+	req := callReq(args)
+	req.Params.Name = name
+	return req
+}
+
+func TestAuditToolMiddleware(t *testing.T) {
+	principalCtx := domainAudit.ContextWithPrincipal(context.Background(), domainAudit.Principal{
+		Type:     domainAudit.PrincipalOAuth,
+		Subject:  "admin",
+		ClientID: "client-1",
+	})
+
+	t.Run("records mutating call with principal and message id", func(t *testing.T) {
+		svc := &stubAuditService{}
+		handler := auditToolMiddleware(svc)(func(context.Context, mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+			return mcpg.NewToolResultStructuredOnly(map[string]any{"message_id": "3EB0XYZ"}), nil
+		})
+
+		_, err := handler(principalCtx, toolReq("whatsapp_send", map[string]any{
+			"type": "text", "phone": "628123", "device_id": "dev-1",
+		}))
+		require.NoError(t, err)
+
+		require.Len(t, svc.recorded, 1)
+		entry := svc.recorded[0]
+		assert.Equal(t, domainAudit.SourceMCP, entry.Source)
+		assert.Equal(t, "whatsapp_send.text", entry.Action)
+		assert.Equal(t, "admin", entry.Principal.Subject)
+		assert.Equal(t, "client-1", entry.Principal.ClientID)
+		assert.Equal(t, "dev-1", entry.DeviceID)
+		assert.Equal(t, "628123", entry.TargetJID)
+		assert.Equal(t, "3EB0XYZ", entry.MessageID)
+		assert.Equal(t, domainAudit.ResultSuccess, entry.Result)
+	})
+
+	t.Run("records tool errors", func(t *testing.T) {
+		svc := &stubAuditService{}
+		handler := auditToolMiddleware(svc)(func(context.Context, mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+			return mcpg.NewToolResultError("group not found"), nil
+		})
+
+		_, err := handler(principalCtx, toolReq("whatsapp_group", map[string]any{
+			"action": "leave", "group_id": "1203@g.us",
+		}))
+		require.NoError(t, err)
+
+		require.Len(t, svc.recorded, 1)
+		assert.Equal(t, "whatsapp_group.leave", svc.recorded[0].Action)
+		assert.Equal(t, domainAudit.ResultError, svc.recorded[0].Result)
+		assert.Equal(t, "group not found", svc.recorded[0].ErrorMessage)
+	})
+
+	t.Run("records handler errors", func(t *testing.T) {
+		svc := &stubAuditService{}
+		handler := auditToolMiddleware(svc)(func(context.Context, mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+			return nil, errors.New("boom")
+		})
+
+		_, err := handler(principalCtx, toolReq("whatsapp_app", map[string]any{"action": "logout"}))
+		require.Error(t, err)
+
+		require.Len(t, svc.recorded, 1)
+		assert.Equal(t, "boom", svc.recorded[0].ErrorMessage)
+	})
+
+	t.Run("skips read-only actions", func(t *testing.T) {
+		svc := &stubAuditService{}
+		handler := auditToolMiddleware(svc)(func(context.Context, mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+			return mcpg.NewToolResultText("ok"), nil
+		})
+
+		_, err := handler(principalCtx, toolReq("whatsapp_chat", map[string]any{"action": "list_chats"}))
+		require.NoError(t, err)
+		_, err = handler(principalCtx, toolReq("whatsapp_app", map[string]any{"action": "status"}))
+		require.NoError(t, err)
+
+		assert.Empty(t, svc.recorded)
+	})
+}
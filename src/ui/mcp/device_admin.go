@@ -0,0 +1,191 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp/oauth"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DeviceHandler administers the devices themselves. Unlike the other tools,
+// device_id names the device being administered rather than the device the
+// call acts as, so it does not go through resolveDeviceContext.
+type DeviceHandler struct {
+	deviceService domainDevice.IDeviceUsecase
+}
+
+func InitMcpDevice(deviceService domainDevice.IDeviceUsecase) *DeviceHandler {
+	return &DeviceHandler{deviceService: deviceService}
+}
+
+func (h *DeviceHandler) AddDeviceTools(mcpServer *server.MCPServer) {
+	tool := mcpg.NewTool("whatsapp_device",
+		mcpg.WithDescription("Administer the WhatsApp devices served by this instance: list devices and their state, get one device, health (connection history), add or remove a device, and read or set a device's webhook. Use the listed device IDs as device_id in the other tools."),
+		mcpg.WithTitleAnnotation("Device Administration"),
+		mcpg.WithReadOnlyHintAnnotation(false),
+		mcpg.WithDestructiveHintAnnotation(true),
+		mcpg.WithIdempotentHintAnnotation(false),
+		mcpg.WithRawInputSchema(json.RawMessage(deviceSchema)),
+	)
+	// NewTool defaults InputSchema.Type to "object"; clear it so only
+	// RawInputSchema is set, or MarshalJSON rejects the tool as conflicting.
+	tool.InputSchema = mcpg.ToolInputSchema{}
+	mcpServer.AddTool(tool, h.handleDevice)
+}
+
+func (h *DeviceHandler) handleDevice(ctx context.Context, request mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+	if h.deviceService == nil {
+		return mcpg.NewToolResultError("device management is not available"), nil
+	}
+
+	action, err := request.RequireString("action")
+	if err != nil {
+		return mcpg.NewToolResultError(err.Error()), nil
+	}
+
+	deviceID := strings.TrimSpace(request.GetString("device_id", ""))
+	switch action {
+	case "list":
+		devices, err := h.deviceService.ListDevices(ctx, domainDevice.DeviceFilter{})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		if access, ok := oauth.AccessFromContext(ctx); ok && access.DeviceRestricted() {
+			devices = slices.DeleteFunc(devices, func(device domainDevice.Device) bool { return !access.AllowsDevice(device.ID) })
+		}
+		return mcpg.NewToolResultStructured(map[string]any{"devices": devices}, fmt.Sprintf("Found %d devices", len(devices))), nil
+	case "get":
+		device, err := h.deviceService.GetDevice(ctx, deviceID)
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(device, fmt.Sprintf("Device %s is %s", device.ID, device.State)), nil
+	case "health":
+		health, err := h.deviceService.GetDevicesHealth(ctx)
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		if access, ok := oauth.AccessFromContext(ctx); ok && access.DeviceRestricted() {
+			health = restrictDevicesHealth(health, access)
+		}
+		return mcpg.NewToolResultStructured(health, fmt.Sprintf("%d devices, %d connected, %d logged in", health.Total, health.Connected, health.LoggedIn)), nil
+	case "add":
+		// The webhook is only configured when a webhook argument was given,
+		// mirroring POST /devices.
+		var webhook *chatstorage.DeviceWebhookConfig
+		if args := request.GetArguments(); args != nil {
+			for _, key := range []string{"webhook_url", "webhook_secret", "webhook_events", "webhook_insecure_skip_verify"} {
+				if _, ok := args[key]; ok {
+					webhook = deviceWebhookConfig(request)
+					break
+				}
+			}
+		}
+		device, err := h.deviceService.AddDevice(ctx, deviceID, webhook)
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(device, fmt.Sprintf("Added device %s; log it in with whatsapp_app and device_id %s", device.ID, device.ID)), nil
+	case "remove":
+		if err := h.deviceService.RemoveDevice(ctx, deviceID); err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultText(fmt.Sprintf("Removed device %s", deviceID)), nil
+	case "get_webhook":
+		webhook, err := h.deviceService.GetDeviceWebhookConfig(ctx, deviceID)
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(deviceWebhookResult(deviceID, webhook), fmt.Sprintf("Retrieved webhook of device %s", deviceID)), nil
+	case "set_webhook":
+		webhook := deviceWebhookConfig(request)
+		// The tool does not expose the delivery settings (method, headers,
+		// template, signature), so keep the ones configured over REST.
+		if current, err := h.deviceService.GetDeviceWebhookConfig(ctx, deviceID); err == nil && current != nil {
+			webhook.WebhookMethod = current.WebhookMethod
+			webhook.WebhookHeaders = current.WebhookHeaders
+			webhook.WebhookTemplate = current.WebhookTemplate
+			webhook.WebhookSignatureAlgorithm = current.WebhookSignatureAlgorithm
+		}
+		if err := h.deviceService.SetDeviceWebhookConfig(ctx, deviceID, webhook); err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(deviceWebhookResult(deviceID, webhook), fmt.Sprintf("Updated webhook of device %s", deviceID)), nil
+	default:
+		return mcpg.NewToolResultError(fmt.Sprintf("unknown device action: %s", action)), nil
+	}
+}
+
+// restrictDevicesHealth keeps only the devices an OAuth grant may see and
+// recounts the totals.
+func restrictDevicesHealth(health domainDevice.DevicesHealth, access oauth.Access) domainDevice.DevicesHealth {
+	restricted := domainDevice.DevicesHealth{Devices: []domainDevice.DeviceHealth{}}
+	for _, device := range health.Devices {
+		if !access.AllowsDevice(device.ID) {
+			continue
+		}
+		restricted.Devices = append(restricted.Devices, device)
+		restricted.Total++
+		if device.IsConnected {
+			restricted.Connected++
+		}
+		if device.IsLoggedIn {
+			restricted.LoggedIn++
+		}
+	}
+	return restricted
+}
+
+func deviceWebhookConfig(request mcpg.CallToolRequest) *chatstorage.DeviceWebhookConfig {
+	webhookURL := strings.TrimSpace(request.GetString("webhook_url", ""))
+	return &chatstorage.DeviceWebhookConfig{
+		WebhookURL:                &webhookURL,
+		WebhookSecret:             request.GetString("webhook_secret", ""),
+		WebhookEvents:             strings.TrimSpace(request.GetString("webhook_events", "")),
+		WebhookInsecureSkipVerify: request.GetBool("webhook_insecure_skip_verify", false),
+	}
+}
+
+// deviceWebhookResult reports a webhook config without its secret: tool
+// results end up in the model's context, so only whether one is set is shown.
+func deviceWebhookResult(deviceID string, webhook *chatstorage.DeviceWebhookConfig) map[string]any {
+	result := map[string]any{
+		"device_id":                    deviceID,
+		"webhook_url":                  "",
+		"webhook_secret_set":           false,
+		"webhook_events":               "",
+		"webhook_insecure_skip_verify": false,
+	}
+	if webhook == nil {
+		return result
+	}
+	if webhook.WebhookURL != nil {
+		result["webhook_url"] = *webhook.WebhookURL
+	}
+	result["webhook_secret_set"] = webhook.WebhookSecret != ""
+	result["webhook_events"] = webhook.WebhookEvents
+	result["webhook_insecure_skip_verify"] = webhook.WebhookInsecureSkipVerify
+	if webhook.WebhookMethod != "" {
+		result["webhook_method"] = webhook.WebhookMethod
+	}
+	if len(webhook.WebhookHeaders) > 0 {
+		// Header values usually carry credentials; list the names only.
+		names := slices.Sorted(maps.Keys(webhook.WebhookHeaders))
+		result["webhook_header_names"] = names
+	}
+	if webhook.WebhookTemplate != "" {
+		result["webhook_template_set"] = true
+	}
+	if webhook.WebhookSignatureAlgorithm != "" {
+		result["webhook_signature_algorithm"] = webhook.WebhookSignatureAlgorithm
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDeviceService struct {
+	domainDevice.IDeviceUsecase
+	addedID    string
+	addWebhook *chatstorage.DeviceWebhookConfig
+	removed    string
+	webhookID  string
+	webhook    *chatstorage.DeviceWebhookConfig
+}
+
+func (s *stubDeviceService) ListDevices(_ context.Context, _ domainDevice.DeviceFilter) ([]domainDevice.Device, error) {
+	return []domainDevice.Device{{ID: "d1", State: domainDevice.DeviceStateConnected}}, nil
+}
+func (s *stubDeviceService) AddDevice(_ context.Context, deviceID string, webhook *chatstorage.DeviceWebhookConfig) (*domainDevice.Device, error) {
+	s.addedID, s.addWebhook = deviceID, webhook
+	if deviceID == "" {
+		deviceID = "generated"
+	}
+	return &domainDevice.Device{ID: deviceID}, nil
+}
+func (s *stubDeviceService) RemoveDevice(_ context.Context, deviceID string) error {
+	s.removed = deviceID
+	return nil
+}
+func (s *stubDeviceService) SetDeviceWebhookConfig(_ context.Context, deviceID string, config *chatstorage.DeviceWebhookConfig) error {
+	s.webhookID, s.webhook = deviceID, config
+	return nil
+}
+func (s *stubDeviceService) GetDeviceWebhookConfig(_ context.Context, _ string) (*chatstorage.DeviceWebhookConfig, error) {
+	url := "https://hooks.example.com/wa"
+	return &chatstorage.DeviceWebhookConfig{
+		WebhookURL:     &url,
+		WebhookSecret:  "s3cret",
+		WebhookMethod:  "PUT",
+		WebhookHeaders: map[string]string{"Authorization": "Bearer t0ken"},
+	}, nil
+}
+
+func TestHandleDeviceDispatch(t *testing.T) {
+	t.Run("list", func(t *testing.T) {
+		h := InitMcpDevice(&stubDeviceService{})
+		res, err := h.handleDevice(context.Background(), callReq(map[string]any{"action": "list"}))
+		require.NoError(t, err)
+		assert.Equal(t, "Found 1 devices", toolResultText(res))
+		devices := res.StructuredContent.(map[string]any)["devices"].([]domainDevice.Device)
+		assert.Equal(t, "d1", devices[0].ID)
+	})
+
+	t.Run("add without webhook arguments", func(t *testing.T) {
+		svc := &stubDeviceService{}
+		h := InitMcpDevice(svc)
+		res, err := h.handleDevice(context.Background(), callReq(map[string]any{"action": "add"}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		assert.Equal(t, "", svc.addedID)
+		assert.Nil(t, svc.addWebhook)
+	})
+
+	t.Run("add with webhook", func(t *testing.T) {
+		svc := &stubDeviceService{}
+		h := InitMcpDevice(svc)
+		_, err := h.handleDevice(context.Background(), callReq(map[string]any{
+			"action": "add", "device_id": " sales ", "webhook_url": "https://hooks.example.com/wa",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "sales", svc.addedID)
+		require.NotNil(t, svc.addWebhook)
+		assert.Equal(t, "https://hooks.example.com/wa", *svc.addWebhook.WebhookURL)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		svc := &stubDeviceService{}
+		h := InitMcpDevice(svc)
+		_, err := h.handleDevice(context.Background(), callReq(map[string]any{"action": "remove", "device_id": "d1"}))
+		require.NoError(t, err)
+		assert.Equal(t, "d1", svc.removed)
+	})
+
+	t.Run("webhook secrets are not returned", func(t *testing.T) {
+		svc := &stubDeviceService{}
+		h := InitMcpDevice(svc)
+		res, err := h.handleDevice(context.Background(), callReq(map[string]any{"action": "get_webhook", "device_id": "d1"}))
+		require.NoError(t, err)
+		result := res.StructuredContent.(map[string]any)
+		assert.Equal(t, "https://hooks.example.com/wa", result["webhook_url"])
+		assert.Equal(t, true, result["webhook_secret_set"])
+		assert.NotContains(t, result, "webhook_secret")
+		assert.Equal(t, []string{"Authorization"}, result["webhook_header_names"])
+
+		res, err = h.handleDevice(context.Background(), callReq(map[string]any{
+			"action": "set_webhook", "device_id": "d1", "webhook_url": "", "webhook_secret": "new",
+		}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		assert.Equal(t, "d1", svc.webhookID)
+		assert.Equal(t, "", *svc.webhook.WebhookURL)
+		assert.Equal(t, "new", svc.webhook.WebhookSecret)
+		// Delivery settings configured over REST survive an MCP update.
+		assert.Equal(t, "PUT", svc.webhook.WebhookMethod)
+		assert.Equal(t, "Bearer t0ken", svc.webhook.WebhookHeaders["Authorization"])
+	})
+
+	t.Run("without device service", func(t *testing.T) {
+		res, err := InitMcpDevice(nil).handleDevice(context.Background(), callReq(map[string]any{"action": "list"}))
+		require.NoError(t, err)
+		assert.True(t, res.IsError)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	mcpg "github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type GroupHandler struct {
@@ -24,7 +25,7 @@ func InitMcpGroup(groupService domainGroup.IGroupUsecase, resolver deviceResolve
 
 func (h *GroupHandler) AddGroupTools(mcpServer *server.MCPServer) {
 	tool := mcpg.NewTool("whatsapp_group",
-		mcpg.WithDescription("Manage WhatsApp groups: create, join_with_link, leave, info, participants, add/remove/promote/demote participants, invite_link, set_name, set_topic, set_settings (announce/locked), join_requests, manage_join_requests."),
+		mcpg.WithDescription("Manage WhatsApp groups: create, join_with_link, leave, info, participants, participant_history, add/remove/promote/demote participants, invite_link, set_name, set_topic, topic_history, set_settings (announce/locked/member_add_mode/join_approval/ephemeral_seconds), join_requests, manage_join_requests; communities: create_community, community_groups, link_group, unlink_group, community_participants, community_announce."),
 		mcpg.WithTitleAnnotation("Group Management"),
 		mcpg.WithReadOnlyHintAnnotation(false),
 		mcpg.WithDestructiveHintAnnotation(true),
@@ -51,12 +52,14 @@ func (h *GroupHandler) handleGroup(ctx context.Context, request mcpg.CallToolReq
 	groupID := strings.TrimSpace(request.GetString("group_id", ""))
 	utils.SanitizePhone(&groupID)
 	participants := trimAll(request.GetStringSlice("participants", nil))
+	communityID := strings.TrimSpace(request.GetString("community_id", ""))
+	utils.SanitizePhone(&communityID)
 
 	switch action {
 	case "create":
 		title := strings.TrimSpace(request.GetString("title", ""))
 		newGroupID, err := h.groupService.CreateGroup(ctx, domainGroup.CreateGroupRequest{
-			Title: title, Participants: participants,
+			Title: title, Participants: participants, CommunityID: communityID,
 		})
 		if err != nil {
 			return mcpg.NewToolResultError(err.Error()), nil
@@ -77,17 +80,29 @@ func (h *GroupHandler) handleGroup(ctx context.Context, request mcpg.CallToolReq
 		}
 		return mcpg.NewToolResultText(fmt.Sprintf("Left group %s", groupID)), nil
 	case "info":
-		resp, err := h.groupService.GroupInfo(ctx, domainGroup.GroupInfoRequest{GroupID: groupID})
+		resp, err := h.groupService.GroupInfo(ctx, domainGroup.GroupInfoRequest{GroupID: groupID, Refresh: request.GetBool("refresh", false)})
 		if err != nil {
 			return mcpg.NewToolResultError(err.Error()), nil
 		}
 		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Fetched group info for %s", groupID)), nil
 	case "participants":
-		resp, err := h.groupService.GetGroupParticipants(ctx, domainGroup.GetGroupParticipantsRequest{GroupID: groupID})
+		resp, err := h.groupService.GetGroupParticipants(ctx, domainGroup.GetGroupParticipantsRequest{GroupID: groupID, Refresh: request.GetBool("refresh", false)})
 		if err != nil {
 			return mcpg.NewToolResultError(err.Error()), nil
 		}
 		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Group %s has %d participants", resp.GroupID, len(resp.Participants))), nil
+	case "participant_history":
+		resp, err := h.groupService.GetGroupParticipantHistory(ctx, domainGroup.GetGroupParticipantHistoryRequest{
+			GroupID:     groupID,
+			Participant: strings.TrimSpace(request.GetString("participant", "")),
+			Action:      strings.TrimSpace(request.GetString("history_action", "")),
+			Limit:       request.GetInt("limit", 0),
+			Offset:      request.GetInt("offset", 0),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Group %s has %d participant changes (showing %d)", resp.GroupID, resp.Pagination.Total, len(resp.Data))), nil
 	case "add_participants", "remove_participants", "promote", "demote":
 		change := map[string]whatsmeow.ParticipantChange{
 			"add_participants":    whatsmeow.ParticipantChangeAdd,
@@ -122,6 +137,16 @@ func (h *GroupHandler) handleGroup(ctx context.Context, request mcpg.CallToolReq
 			return mcpg.NewToolResultError(err.Error()), nil
 		}
 		return mcpg.NewToolResultText(fmt.Sprintf("Updated group %s topic", groupID)), nil
+	case "topic_history":
+		resp, err := h.groupService.GetGroupDescriptionHistory(ctx, domainGroup.GetGroupDescriptionHistoryRequest{
+			GroupID: groupID,
+			Limit:   request.GetInt("limit", 0),
+			Offset:  request.GetInt("offset", 0),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Group %s has %d topic changes (showing %d)", resp.GroupID, resp.Pagination.Total, len(resp.Data))), nil
 	case "set_settings":
 		args := request.GetArguments()
 		applied := []string{}
@@ -141,8 +166,32 @@ func (h *GroupHandler) handleGroup(ctx context.Context, request mcpg.CallToolReq
 			}
 			applied = append(applied, "locked")
 		}
+		if _, ok := args["member_add_mode"]; ok {
+			if err := h.groupService.SetGroupMemberAddMode(ctx, domainGroup.SetGroupMemberAddModeRequest{
+				GroupID: groupID, Mode: types.GroupMemberAddMode(request.GetString("member_add_mode", "")),
+			}); err != nil {
+				return mcpg.NewToolResultError(err.Error()), nil
+			}
+			applied = append(applied, "member_add_mode")
+		}
+		if _, ok := args["join_approval"]; ok {
+			if err := h.groupService.SetGroupJoinApproval(ctx, domainGroup.SetGroupJoinApprovalRequest{
+				GroupID: groupID, Enabled: request.GetBool("join_approval", false),
+			}); err != nil {
+				return mcpg.NewToolResultError(err.Error()), nil
+			}
+			applied = append(applied, "join_approval")
+		}
+		if _, ok := args["ephemeral_seconds"]; ok {
+			if err := h.groupService.SetGroupEphemeral(ctx, domainGroup.SetGroupEphemeralRequest{
+				GroupID: groupID, TimerSeconds: uint32(request.GetInt("ephemeral_seconds", 0)),
+			}); err != nil {
+				return mcpg.NewToolResultError(err.Error()), nil
+			}
+			applied = append(applied, "ephemeral")
+		}
 		if len(applied) == 0 {
-			return mcpg.NewToolResultError("set_settings requires announce and/or locked"), nil
+			return mcpg.NewToolResultError("set_settings requires at least one of announce, locked, member_add_mode, join_approval, ephemeral_seconds"), nil
 		}
 		return mcpg.NewToolResultText(fmt.Sprintf("Updated %s for group %s", strings.Join(applied, "+"), groupID)), nil
 	case "join_requests":
@@ -163,6 +212,46 @@ func (h *GroupHandler) handleGroup(ctx context.Context, request mcpg.CallToolReq
 			return mcpg.NewToolResultError(err.Error()), nil
 		}
 		return mcpg.NewToolResultStructured(result, fmt.Sprintf("Applied %s to %d pending requests for %s", request.GetString("request_action", ""), len(participants), groupID)), nil
+	case "create_community":
+		name := strings.TrimSpace(request.GetString("name", ""))
+		newCommunityID, err := h.groupService.CreateCommunity(ctx, domainGroup.CreateCommunityRequest{
+			Name: name, Description: strings.TrimSpace(request.GetString("description", "")), Participants: participants,
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		structured := map[string]any{"community_id": newCommunityID, "name": name}
+		return mcpg.NewToolResultStructured(structured, fmt.Sprintf("Created community %s", newCommunityID)), nil
+	case "community_groups":
+		resp, err := h.groupService.GetCommunityGroups(ctx, domainGroup.GetCommunityGroupsRequest{CommunityID: communityID})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Community %s has %d linked groups", resp.CommunityID, len(resp.Groups))), nil
+	case "link_group", "unlink_group":
+		change := h.groupService.LinkGroup
+		verb := "Linked"
+		if action == "unlink_group" {
+			change, verb = h.groupService.UnlinkGroup, "Unlinked"
+		}
+		if err := change(ctx, domainGroup.CommunityGroupRequest{CommunityID: communityID, GroupID: groupID}); err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultText(fmt.Sprintf("%s group %s and community %s", verb, groupID, communityID)), nil
+	case "community_participants":
+		resp, err := h.groupService.GetCommunityParticipants(ctx, domainGroup.GetCommunityParticipantsRequest{CommunityID: communityID})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Community %s has %d participants", resp.CommunityID, len(resp.Participants))), nil
+	case "community_announce":
+		resp, err := h.groupService.SendCommunityAnnouncement(ctx, domainGroup.CommunityAnnouncementRequest{
+			CommunityID: communityID, Message: request.GetString("message", ""),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Posted announcement %s to %s", resp.MessageID, resp.AnnouncementGroupID)), nil
 	default:
 		return mcpg.NewToolResultError(fmt.Sprintf("unknown group action: %s", action)), nil
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type stubGroupService struct {
@@ -25,6 +26,17 @@ type stubGroupService struct {
 	lockedReq    *domainGroup.SetGroupLockedRequest
 	joinReqsReq  *domainGroup.GetGroupRequestParticipantsRequest
 	managedJoins *domainGroup.GroupRequestParticipantsRequest
+	community    *domainGroup.CreateCommunityRequest
+	linked       *domainGroup.CommunityGroupRequest
+	unlinked     *domainGroup.CommunityGroupRequest
+	subGroupsReq *domainGroup.GetCommunityGroupsRequest
+	membersReq   *domainGroup.GetCommunityParticipantsRequest
+	announced    *domainGroup.CommunityAnnouncementRequest
+	addModeReq   *domainGroup.SetGroupMemberAddModeRequest
+	approvalReq  *domainGroup.SetGroupJoinApprovalRequest
+	ephemeralReq *domainGroup.SetGroupEphemeralRequest
+	historyReq   *domainGroup.GetGroupParticipantHistoryRequest
+	topicHistReq *domainGroup.GetGroupDescriptionHistoryRequest
 }
 
 func (s *stubGroupService) CreateGroup(_ context.Context, r domainGroup.CreateGroupRequest) (string, error) {
@@ -47,6 +59,10 @@ func (s *stubGroupService) GetGroupParticipants(_ context.Context, r domainGroup
 	s.partsReq = &r
 	return domainGroup.GetGroupParticipantsResponse{}, nil
 }
+func (s *stubGroupService) GetGroupParticipantHistory(_ context.Context, r domainGroup.GetGroupParticipantHistoryRequest) (domainGroup.GetGroupParticipantHistoryResponse, error) {
+	s.historyReq = &r
+	return domainGroup.GetGroupParticipantHistoryResponse{GroupID: r.GroupID}, nil
+}
 func (s *stubGroupService) ManageParticipant(_ context.Context, r domainGroup.ParticipantRequest) ([]domainGroup.ParticipantStatus, error) {
 	s.managed = &r
 	return nil, nil
@@ -63,6 +79,10 @@ func (s *stubGroupService) SetGroupTopic(_ context.Context, r domainGroup.SetGro
 	s.topicReq = &r
 	return nil
 }
+func (s *stubGroupService) GetGroupDescriptionHistory(_ context.Context, r domainGroup.GetGroupDescriptionHistoryRequest) (domainGroup.GetGroupDescriptionHistoryResponse, error) {
+	s.topicHistReq = &r
+	return domainGroup.GetGroupDescriptionHistoryResponse{GroupID: r.GroupID}, nil
+}
 func (s *stubGroupService) SetGroupAnnounce(_ context.Context, r domainGroup.SetGroupAnnounceRequest) error {
 	s.announceReq = &r
 	return nil
@@ -71,6 +91,18 @@ func (s *stubGroupService) SetGroupLocked(_ context.Context, r domainGroup.SetGr
 	s.lockedReq = &r
 	return nil
 }
+func (s *stubGroupService) SetGroupMemberAddMode(_ context.Context, r domainGroup.SetGroupMemberAddModeRequest) error {
+	s.addModeReq = &r
+	return nil
+}
+func (s *stubGroupService) SetGroupJoinApproval(_ context.Context, r domainGroup.SetGroupJoinApprovalRequest) error {
+	s.approvalReq = &r
+	return nil
+}
+func (s *stubGroupService) SetGroupEphemeral(_ context.Context, r domainGroup.SetGroupEphemeralRequest) error {
+	s.ephemeralReq = &r
+	return nil
+}
 func (s *stubGroupService) GetGroupRequestParticipants(_ context.Context, r domainGroup.GetGroupRequestParticipantsRequest) ([]domainGroup.GetGroupRequestParticipantsResponse, error) {
 	s.joinReqsReq = &r
 	return nil, nil
@@ -80,6 +112,31 @@ func (s *stubGroupService) ManageGroupRequestParticipants(_ context.Context, r d
 	return nil, nil
 }
 
+func (s *stubGroupService) CreateCommunity(_ context.Context, r domainGroup.CreateCommunityRequest) (string, error) {
+	s.community = &r
+	return "999@g.us", nil
+}
+func (s *stubGroupService) LinkGroup(_ context.Context, r domainGroup.CommunityGroupRequest) error {
+	s.linked = &r
+	return nil
+}
+func (s *stubGroupService) UnlinkGroup(_ context.Context, r domainGroup.CommunityGroupRequest) error {
+	s.unlinked = &r
+	return nil
+}
+func (s *stubGroupService) GetCommunityGroups(_ context.Context, r domainGroup.GetCommunityGroupsRequest) (domainGroup.GetCommunityGroupsResponse, error) {
+	s.subGroupsReq = &r
+	return domainGroup.GetCommunityGroupsResponse{CommunityID: r.CommunityID}, nil
+}
+func (s *stubGroupService) GetCommunityParticipants(_ context.Context, r domainGroup.GetCommunityParticipantsRequest) (domainGroup.GetCommunityParticipantsResponse, error) {
+	s.membersReq = &r
+	return domainGroup.GetCommunityParticipantsResponse{CommunityID: r.CommunityID}, nil
+}
+func (s *stubGroupService) SendCommunityAnnouncement(_ context.Context, r domainGroup.CommunityAnnouncementRequest) (domainGroup.CommunityAnnouncementResponse, error) {
+	s.announced = &r
+	return domainGroup.CommunityAnnouncementResponse{CommunityID: r.CommunityID, AnnouncementGroupID: "998@g.us", MessageID: "3EB0"}, nil
+}
+
 func TestHandleGroupDispatch(t *testing.T) {
 	newHandler := func() (*stubGroupService, *GroupHandler) {
 		svc := &stubGroupService{}
@@ -125,6 +182,37 @@ func TestHandleGroupDispatch(t *testing.T) {
 		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{"action": "participants", "group_id": "123@g.us"}))
 		require.NoError(t, err)
 		require.NotNil(t, svc.partsReq)
+		assert.False(t, svc.partsReq.Refresh)
+	})
+
+	t.Run("info with refresh", func(t *testing.T) {
+		svc, h := newHandler()
+		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{"action": "info", "group_id": "123@g.us", "refresh": true}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.infoReq)
+		assert.True(t, svc.infoReq.Refresh)
+	})
+
+	t.Run("participant_history", func(t *testing.T) {
+		svc, h := newHandler()
+		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{
+			"action": "participant_history", "group_id": "123@g.us", "participant": " 628 ", "history_action": "leave", "limit": 20,
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.historyReq)
+		assert.Equal(t, domainGroup.GetGroupParticipantHistoryRequest{
+			GroupID: "123@g.us", Participant: "628", Action: "leave", Limit: 20,
+		}, *svc.historyReq)
+	})
+
+	t.Run("topic_history", func(t *testing.T) {
+		svc, h := newHandler()
+		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{
+			"action": "topic_history", "group_id": "123@g.us", "limit": 10, "offset": 5,
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.topicHistReq)
+		assert.Equal(t, domainGroup.GetGroupDescriptionHistoryRequest{GroupID: "123@g.us", Limit: 10, Offset: 5}, *svc.topicHistReq)
 	})
 
 	t.Run("participant changes map to whatsmeow actions", func(t *testing.T) {
@@ -195,6 +283,31 @@ func TestHandleGroupDispatch(t *testing.T) {
 		assert.Nil(t, svc.lockedReq)
 	})
 
+	t.Run("set_settings member add mode, join approval and ephemeral", func(t *testing.T) {
+		svc, h := newHandler()
+		res, err := h.handleGroup(deviceCtx(), callReq(map[string]any{
+			"action": "set_settings", "group_id": "123@g.us",
+			"member_add_mode": "admin_add", "join_approval": true, "ephemeral_seconds": 86400,
+		}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		require.NotNil(t, svc.addModeReq)
+		assert.Equal(t, types.GroupMemberAddModeAdmin, svc.addModeReq.Mode)
+		require.NotNil(t, svc.approvalReq)
+		assert.True(t, svc.approvalReq.Enabled)
+		require.NotNil(t, svc.ephemeralReq)
+		assert.Equal(t, uint32(86400), svc.ephemeralReq.TimerSeconds)
+		assert.Nil(t, svc.announceReq)
+		assert.Nil(t, svc.lockedReq)
+	})
+
+	t.Run("set_settings without any setting", func(t *testing.T) {
+		_, h := newHandler()
+		res, err := h.handleGroup(deviceCtx(), callReq(map[string]any{"action": "set_settings", "group_id": "123@g.us"}))
+		require.NoError(t, err)
+		assert.True(t, res.IsError)
+	})
+
 	t.Run("join_requests", func(t *testing.T) {
 		svc, h := newHandler()
 		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{"action": "join_requests", "group_id": "123@g.us"}))
@@ -212,4 +325,69 @@ func TestHandleGroupDispatch(t *testing.T) {
 		require.NotNil(t, svc.managedJoins)
 		assert.Equal(t, whatsmeow.ParticipantChangeApprove, svc.managedJoins.Action)
 	})
+
+	t.Run("create inside a community", func(t *testing.T) {
+		svc, h := newHandler()
+		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{
+			"action": "create", "title": "Support", "participants": []any{"628"}, "community_id": "999@g.us",
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.created)
+		assert.Equal(t, "999@g.us", svc.created.CommunityID)
+	})
+
+	t.Run("create_community", func(t *testing.T) {
+		svc, h := newHandler()
+		res, err := h.handleGroup(deviceCtx(), callReq(map[string]any{
+			"action": "create_community", "name": " Customers ", "description": "All customer groups",
+		}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		require.NotNil(t, svc.community)
+		assert.Equal(t, "Customers", svc.community.Name)
+		assert.Equal(t, "All customer groups", svc.community.Description)
+	})
+
+	t.Run("link_group and unlink_group", func(t *testing.T) {
+		svc, h := newHandler()
+		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{
+			"action": "link_group", "community_id": "999@g.us", "group_id": "123@g.us",
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.linked)
+		assert.Equal(t, domainGroup.CommunityGroupRequest{CommunityID: "999@g.us", GroupID: "123@g.us"}, *svc.linked)
+		assert.Nil(t, svc.unlinked)
+
+		_, err = h.handleGroup(deviceCtx(), callReq(map[string]any{
+			"action": "unlink_group", "community_id": "999@g.us", "group_id": "123@g.us",
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.unlinked)
+	})
+
+	t.Run("community_groups", func(t *testing.T) {
+		svc, h := newHandler()
+		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{"action": "community_groups", "community_id": "999@g.us"}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.subGroupsReq)
+		assert.Equal(t, "999@g.us", svc.subGroupsReq.CommunityID)
+	})
+
+	t.Run("community_participants", func(t *testing.T) {
+		svc, h := newHandler()
+		_, err := h.handleGroup(deviceCtx(), callReq(map[string]any{"action": "community_participants", "community_id": "999@g.us"}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.membersReq)
+	})
+
+	t.Run("community_announce", func(t *testing.T) {
+		svc, h := newHandler()
+		res, err := h.handleGroup(deviceCtx(), callReq(map[string]any{
+			"action": "community_announce", "community_id": "999@g.us", "message": "Maintenance tonight",
+		}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		require.NotNil(t, svc.announced)
+		assert.Equal(t, "Maintenance tonight", svc.announced.Message)
+	})
 }
@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type NewsletterHandler struct {
+	newsletterService domainNewsletter.INewsletterUsecase
+	userService       domainUser.IUserUsecase
+	resolver          deviceResolver
+}
+
+func InitMcpNewsletter(newsletterService domainNewsletter.INewsletterUsecase, userService domainUser.IUserUsecase, resolver deviceResolver) *NewsletterHandler {
+	return &NewsletterHandler{newsletterService: newsletterService, userService: userService, resolver: resolver}
+}
+
+func (h *NewsletterHandler) AddNewsletterTools(mcpServer *server.MCPServer) {
+	tool := mcpg.NewTool("whatsapp_newsletter",
+		mcpg.WithDescription("Manage WhatsApp newsletters (channels): list followed channels, create, follow, unfollow, update name/description/picture, mute; as channel admin publish text/image/video posts, edit_post, delete_post; read messages and per-post reactions."),
+		mcpg.WithTitleAnnotation("Newsletter Management"),
+		mcpg.WithReadOnlyHintAnnotation(false),
+		mcpg.WithDestructiveHintAnnotation(true),
+		mcpg.WithIdempotentHintAnnotation(false),
+		mcpg.WithRawInputSchema(json.RawMessage(newsletterSchema)),
+	)
+	// NewTool defaults InputSchema.Type to "object"; clear it so only
+	// RawInputSchema is set, or MarshalJSON rejects the tool as conflicting.
+	tool.InputSchema = mcpg.ToolInputSchema{}
+	mcpServer.AddTool(tool, h.handleNewsletter)
+}
+
+func (h *NewsletterHandler) handleNewsletter(ctx context.Context, request mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+	ctx, _, err := resolveDeviceContext(ctx, request, h.resolver)
+	if err != nil {
+		return mcpg.NewToolResultError(err.Error()), nil
+	}
+
+	action, err := request.RequireString("action")
+	if err != nil {
+		return mcpg.NewToolResultError(err.Error()), nil
+	}
+
+	newsletterID := request.GetString("newsletter_id", "")
+	switch action {
+	case "list":
+		resp, err := h.userService.MyListNewsletter(ctx)
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Found %d newsletters", len(resp.Data))), nil
+	case "create":
+		resp, err := h.newsletterService.Create(ctx, domainNewsletter.CreateRequest{
+			Name:        request.GetString("name", ""),
+			Description: request.GetString("description", ""),
+			PictureURL:  request.GetString("picture_url", ""),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Created newsletter %s", resp.NewsletterID)), nil
+	case "follow":
+		if err := h.newsletterService.Follow(ctx, domainNewsletter.FollowRequest{NewsletterID: newsletterID}); err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultText(fmt.Sprintf("Followed newsletter %s", newsletterID)), nil
+	case "unfollow":
+		if err := h.newsletterService.Unfollow(ctx, domainNewsletter.UnfollowRequest{NewsletterID: newsletterID}); err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultText(fmt.Sprintf("Unfollowed newsletter %s", newsletterID)), nil
+	case "update":
+		req := domainNewsletter.UpdateRequest{
+			NewsletterID: newsletterID,
+			PictureURL:   request.GetString("picture_url", ""),
+		}
+		// Only fields present in the call are changed, so an explicit empty
+		// description clears it.
+		if args := request.GetArguments(); args != nil {
+			if _, ok := args["name"]; ok {
+				v := request.GetString("name", "")
+				req.Name = &v
+			}
+			if _, ok := args["description"]; ok {
+				v := request.GetString("description", "")
+				req.Description = &v
+			}
+		}
+		if err := h.newsletterService.Update(ctx, req); err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultText(fmt.Sprintf("Updated newsletter %s", newsletterID)), nil
+	case "mute":
+		mute := request.GetBool("mute", true)
+		if err := h.newsletterService.Mute(ctx, domainNewsletter.MuteRequest{NewsletterID: newsletterID, Mute: mute}); err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		if !mute {
+			return mcpg.NewToolResultText(fmt.Sprintf("Unmuted newsletter %s", newsletterID)), nil
+		}
+		return mcpg.NewToolResultText(fmt.Sprintf("Muted newsletter %s", newsletterID)), nil
+	case "messages":
+		resp, err := h.newsletterService.GetMessages(ctx, domainNewsletter.GetMessagesRequest{
+			NewsletterID: newsletterID,
+			Count:        request.GetInt("count", 0),
+			Before:       request.GetInt("before", 0),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Retrieved %d messages from %s", len(resp.Data), newsletterID)), nil
+	case "post":
+		resp, err := h.newsletterService.SendPost(ctx, domainNewsletter.SendPostRequest{
+			NewsletterID: newsletterID,
+			Type:         request.GetString("type", ""),
+			Message:      request.GetString("message", ""),
+			Caption:      request.GetString("caption", ""),
+			MediaURL:     request.GetString("media_url", ""),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, resp.Status), nil
+	case "edit_post":
+		resp, err := h.newsletterService.EditPost(ctx, domainNewsletter.EditPostRequest{
+			NewsletterID: newsletterID,
+			MessageID:    request.GetString("message_id", ""),
+			Message:      request.GetString("message", ""),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, resp.Status), nil
+	case "delete_post":
+		resp, err := h.newsletterService.DeletePost(ctx, domainNewsletter.DeletePostRequest{
+			NewsletterID: newsletterID,
+			MessageID:    request.GetString("message_id", ""),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, resp.Status), nil
+	case "reactions":
+		resp, err := h.newsletterService.GetPostReactions(ctx, domainNewsletter.GetPostReactionsRequest{
+			NewsletterID: newsletterID,
+			ServerID:     request.GetInt("server_id", 0),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Post %d: %d views, %d reactions", resp.ServerID, resp.ViewsCount, resp.TotalReactions)), nil
+	default:
+		return mcpg.NewToolResultError(fmt.Sprintf("unknown newsletter action: %s", action)), nil
+	}
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubNewsletterService struct {
+	domainNewsletter.INewsletterUsecase
+	update domainNewsletter.UpdateRequest
+	mute   *domainNewsletter.MuteRequest
+	post   domainNewsletter.SendPostRequest
+	react  domainNewsletter.GetPostReactionsRequest
+}
+
+func (s *stubNewsletterService) Update(_ context.Context, request domainNewsletter.UpdateRequest) error {
+	s.update = request
+	return nil
+}
+func (s *stubNewsletterService) Mute(_ context.Context, request domainNewsletter.MuteRequest) error {
+	s.mute = &request
+	return nil
+}
+func (s *stubNewsletterService) SendPost(_ context.Context, request domainNewsletter.SendPostRequest) (domainNewsletter.PostResponse, error) {
+	s.post = request
+	return domainNewsletter.PostResponse{MessageID: "M1", ServerID: 7, Status: "published"}, nil
+}
+func (s *stubNewsletterService) GetPostReactions(_ context.Context, request domainNewsletter.GetPostReactionsRequest) (domainNewsletter.PostReactionsResponse, error) {
+	s.react = request
+	return domainNewsletter.PostReactionsResponse{ServerID: request.ServerID, ViewsCount: 10, TotalReactions: 3}, nil
+}
+
+func TestHandleNewsletterDispatch(t *testing.T) {
+	t.Run("update only sends present fields", func(t *testing.T) {
+		svc := &stubNewsletterService{}
+		h := InitMcpNewsletter(svc, nil, &stubResolver{})
+		res, err := h.handleNewsletter(deviceCtx(), callReq(map[string]any{
+			"action": "update", "newsletter_id": "1203@newsletter", "description": "",
+		}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		assert.Nil(t, svc.update.Name)
+		require.NotNil(t, svc.update.Description)
+		assert.Equal(t, "", *svc.update.Description)
+	})
+
+	t.Run("mute defaults to true", func(t *testing.T) {
+		svc := &stubNewsletterService{}
+		h := InitMcpNewsletter(svc, nil, &stubResolver{})
+		res, err := h.handleNewsletter(deviceCtx(), callReq(map[string]any{"action": "mute", "newsletter_id": "1203@newsletter"}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		require.NotNil(t, svc.mute)
+		assert.True(t, svc.mute.Mute)
+	})
+
+	t.Run("post", func(t *testing.T) {
+		svc := &stubNewsletterService{}
+		h := InitMcpNewsletter(svc, nil, &stubResolver{})
+		res, err := h.handleNewsletter(deviceCtx(), callReq(map[string]any{
+			"action": "post", "newsletter_id": "1203@newsletter", "type": "image", "media_url": "http://x/a.png", "caption": "launch",
+		}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		assert.Equal(t, "image", svc.post.Type)
+		assert.Equal(t, "http://x/a.png", svc.post.MediaURL)
+		assert.Equal(t, "launch", svc.post.Caption)
+	})
+
+	t.Run("reactions", func(t *testing.T) {
+		svc := &stubNewsletterService{}
+		h := InitMcpNewsletter(svc, nil, &stubResolver{})
+		res, err := h.handleNewsletter(deviceCtx(), callReq(map[string]any{"action": "reactions", "newsletter_id": "1203@newsletter", "server_id": float64(42)}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		assert.Equal(t, 42, svc.react.ServerID)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		h := InitMcpNewsletter(&stubNewsletterService{}, nil, &stubResolver{})
+		res, err := h.handleNewsletter(deviceCtx(), callReq(map[string]any{"action": "archive"}))
+		require.NoError(t, err)
+		assert.True(t, res.IsError)
+	})
+}
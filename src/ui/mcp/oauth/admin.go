@@ -0,0 +1,82 @@
+package oauth
+
+import (
+	"database/sql"
+	"errors"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+// RegisterAdmin mounts the connected-apps API that lets operators see which
+// MCP clients are registered and disconnect them. router must already require
+// Basic Auth; unlike RegisterPublic, these routes are not for OAuth clients.
+func (s *Server) RegisterAdmin(router fiber.Router) {
+	router.Get("/mcp/oauth/clients", s.listConnectedClients)
+	router.Get("/mcp/oauth/clients/:client_id", s.getConnectedClient)
+	router.Delete("/mcp/oauth/clients/:client_id", s.revokeConnectedClient)
+	router.Delete("/mcp/oauth/grants/:grant_id", s.revokeConnectedGrant)
+}
+
+func (s *Server) listConnectedClients(c fiber.Ctx) error {
+	clients, err := s.store.listConnectedClients(c.Context(), "", s.now())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "List OAuth clients",
+		Results: clients,
+	})
+}
+
+func (s *Server) getConnectedClient(c fiber.Ctx) error {
+	clients, err := s.store.listConnectedClients(c.Context(), c.Params("client_id"), s.now())
+	utils.PanicIfNeeded(err)
+	if len(clients) == 0 {
+		utils.PanicIfNeeded(pkgError.ErrOAuthClientNotFound)
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "OAuth client info",
+		Results: clients[0],
+	})
+}
+
+// revokeConnectedClient deletes the client registration together with all of
+// its authorization codes and tokens. The client has to register again to
+// reconnect.
+func (s *Server) revokeConnectedClient(c fiber.Ctx) error {
+	err := s.store.deleteClient(c.Context(), c.Params("client_id"))
+	if errors.Is(err, sql.ErrNoRows) {
+		err = pkgError.ErrOAuthClientNotFound
+	}
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "OAuth client revoked",
+		Results: nil,
+	})
+}
+
+// revokeConnectedGrant revokes one authorization and its tokens while keeping
+// the client registered, so the user can authorize it again.
+func (s *Server) revokeConnectedGrant(c fiber.Ctx) error {
+	err := s.store.revokeGrant(c.Context(), c.Params("grant_id"), s.now())
+	if errors.Is(err, sql.ErrNoRows) {
+		err = pkgError.ErrOAuthGrantNotFound
+	}
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "OAuth grant revoked",
+		Results: nil,
+	})
+}
@@ -0,0 +1,84 @@
+package oauth
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type connectedClientsResponse struct {
+	Code    string            `json:"code"`
+	Results []ConnectedClient `json:"results"`
+}
+
+func TestAdminListsAndRevokesConnectedClients(t *testing.T) {
+	srv, app := newOAuthTestServer(t, testIssuer, testResource)
+	admin := fiber.New()
+	admin.Use(middleware.Recovery())
+	srv.RegisterAdmin(admin)
+
+	clientID := registerTestClient(t, app)
+	code, verifier := authorizeTestClient(t, app, clientID)
+	tokens := exchangeTestCode(t, app, clientID, code, verifier, testResource)
+
+	resp, err := admin.Test(httptest.NewRequest("GET", "/mcp/oauth/clients", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var listed connectedClientsResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&listed))
+	require.Len(t, listed.Results, 1)
+	client := listed.Results[0]
+	assert.Equal(t, clientID, client.ClientID)
+	assert.Equal(t, "Claude", client.ClientName)
+	require.NotNil(t, client.LastUsedAt, "the token exchange counts as use")
+	require.Len(t, client.Grants, 1)
+	grant := client.Grants[0]
+	assert.Equal(t, "user", grant.Subject)
+	assert.Equal(t, ScopeFull, grant.Scope)
+	assert.NotEmpty(t, grant.GrantID)
+	assert.NotNil(t, grant.LastUsedAt)
+
+	// Revoking the grant disconnects the tokens but keeps the client.
+	resp, err = admin.Test(httptest.NewRequest("DELETE", "/mcp/oauth/grants/"+url.PathEscape(grant.GrantID), nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	_, err = srv.store.validateAccessToken(t.Context(), tokens.AccessToken, testResource, time.Now())
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	resp, err = admin.Test(httptest.NewRequest("GET", "/mcp/oauth/clients/"+clientID, nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var single struct {
+		Results ConnectedClient `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&single))
+	assert.Equal(t, clientID, single.Results.ClientID)
+	assert.Empty(t, single.Results.Grants)
+
+	resp, err = admin.Test(httptest.NewRequest("DELETE", "/mcp/oauth/grants/"+url.PathEscape(grant.GrantID), nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode, "an already revoked grant is gone")
+
+	// Revoking the client removes it together with everything it was issued.
+	code, verifier = authorizeTestClient(t, app, clientID)
+	tokens = exchangeTestCode(t, app, clientID, code, verifier, testResource)
+	resp, err = admin.Test(httptest.NewRequest("DELETE", "/mcp/oauth/clients/"+clientID, nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	_, err = srv.store.validateAccessToken(t.Context(), tokens.AccessToken, testResource, time.Now())
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	resp, err = admin.Test(httptest.NewRequest("GET", "/mcp/oauth/clients/"+clientID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	resp, err = admin.Test(httptest.NewRequest("DELETE", "/mcp/oauth/clients/"+clientID, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,148 @@
+package oauth
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// OAuth scopes of the MCP endpoint. ScopeFull is the original all-access
+// scope and still implies every fine-grained scope, so existing clients and
+// grants keep working.
+const (
+	ScopeFull        = "mcp"
+	ScopeRead        = "mcp:read"
+	ScopeSend        = "mcp:send"
+	ScopeGroupAdmin  = "mcp:group.admin"
+	ScopeDeviceAdmin = "mcp:device.admin"
+)
+
+// maxGrantDevices bounds the device restriction list chosen on the consent page.
+const maxGrantDevices = 50
+
+// grantableScopes are the fine-grained scopes offered on the consent page, in
+// display order.
+var grantableScopes = []string{ScopeRead, ScopeSend, ScopeGroupAdmin, ScopeDeviceAdmin}
+
+var scopeDescriptions = map[string]string{
+	ScopeRead:        "Read chats, messages, contacts, groups and device status",
+	ScopeSend:        "Send, edit, react to and delete messages; post to channels",
+	ScopeGroupAdmin:  "Create, join, leave and administer groups, communities and channels",
+	ScopeDeviceAdmin: "Log devices in and out, add or remove devices and change their webhooks",
+}
+
+// Access is what an OAuth grant allows: its scopes and, when Devices is not
+// empty, the only devices it may act on.
+type Access struct {
+	Scopes  []string
+	Devices []string
+}
+
+// Allows reports whether the grant includes scope, directly or through
+// ScopeFull.
+func (a Access) Allows(scope string) bool {
+	return slices.Contains(a.Scopes, ScopeFull) || slices.Contains(a.Scopes, scope)
+}
+
+// AllowsDevice reports whether the grant may act on deviceID.
+func (a Access) AllowsDevice(deviceID string) bool {
+	return len(a.Devices) == 0 || slices.Contains(a.Devices, deviceID)
+}
+
+// DeviceRestricted reports whether the grant is limited to some devices.
+func (a Access) DeviceRestricted() bool {
+	return len(a.Devices) > 0
+}
+
+type accessContextKey struct{}
+
+// ContextWithAccess stores the grant of a bearer-token request on ctx.
+func ContextWithAccess(ctx context.Context, access Access) context.Context {
+	return context.WithValue(ctx, accessContextKey{}, access)
+}
+
+// AccessFromContext returns the grant stored by ContextWithAccess. Requests
+// authenticated otherwise (Basic Auth, or OAuth disabled) carry none and are
+// not restricted.
+func AccessFromContext(ctx context.Context) (Access, bool) {
+	if ctx == nil {
+		return Access{}, false
+	}
+	access, ok := ctx.Value(accessContextKey{}).(Access)
+	return access, ok
+}
+
+// parseScope splits a space-delimited scope parameter, dropping duplicates,
+// and reports whether every scope is supported. An empty parameter requests
+// ScopeFull.
+func parseScope(raw string) ([]string, bool) {
+	fields := strings.Fields(raw)
+	if len(fields) == 0 {
+		return []string{ScopeFull}, true
+	}
+	scopes := make([]string, 0, len(fields))
+	for _, scope := range fields {
+		if scope != ScopeFull && !slices.Contains(grantableScopes, scope) {
+			return nil, false
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, true
+}
+
+// offeredScopes expands the requested scopes into the fine-grained scopes the
+// consent page offers.
+func offeredScopes(requested []string) []string {
+	if slices.Contains(requested, ScopeFull) {
+		return slices.Clone(grantableScopes)
+	}
+	offered := make([]string, 0, len(requested))
+	for _, scope := range grantableScopes {
+		if slices.Contains(requested, scope) {
+			offered = append(offered, scope)
+		}
+	}
+	return offered
+}
+
+// grantedScope returns the scope string stored with a grant for the scopes
+// chosen on the consent page. Choosing everything offered for a ScopeFull
+// request keeps ScopeFull, so the grant also covers future scopes.
+func grantedScope(requested, chosen []string) (string, bool) {
+	offered := offeredScopes(requested)
+	granted := make([]string, 0, len(offered))
+	for _, scope := range offered {
+		if slices.Contains(chosen, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	for _, scope := range chosen {
+		if !slices.Contains(offered, scope) {
+			return "", false
+		}
+	}
+	if len(granted) == 0 {
+		return "", false
+	}
+	if slices.Contains(requested, ScopeFull) && len(granted) == len(offered) {
+		return ScopeFull, true
+	}
+	return strings.Join(granted, " "), true
+}
+
+// parseDevices splits the comma- or space-separated device restriction from
+// the consent page. An empty value allows every device.
+func parseDevices(raw string) ([]string, bool) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	})
+	devices := make([]string, 0, len(fields))
+	for _, device := range fields {
+		if !slices.Contains(devices, device) {
+			devices = append(devices, device)
+		}
+	}
+	return devices, len(devices) <= maxGrantDevices
+}
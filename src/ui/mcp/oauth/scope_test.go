@@ -0,0 +1,65 @@
+package oauth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScope(t *testing.T) {
+	scopes, ok := parseScope("")
+	assert.True(t, ok)
+	assert.Equal(t, []string{ScopeFull}, scopes)
+
+	scopes, ok = parseScope("mcp:read  mcp:send mcp:read")
+	assert.True(t, ok)
+	assert.Equal(t, []string{ScopeRead, ScopeSend}, scopes)
+
+	_, ok = parseScope("mcp:read admin")
+	assert.False(t, ok)
+}
+
+func TestGrantedScope(t *testing.T) {
+	everything := []string{ScopeRead, ScopeSend, ScopeGroupAdmin, ScopeDeviceAdmin}
+
+	scope, ok := grantedScope([]string{ScopeFull}, everything)
+	assert.True(t, ok)
+	assert.Equal(t, ScopeFull, scope, "granting everything offered keeps the all-access scope")
+
+	scope, ok = grantedScope([]string{ScopeFull}, []string{ScopeSend, ScopeRead})
+	assert.True(t, ok)
+	assert.Equal(t, "mcp:read mcp:send", scope)
+
+	scope, ok = grantedScope([]string{ScopeRead}, []string{ScopeRead})
+	assert.True(t, ok)
+	assert.Equal(t, ScopeRead, scope)
+
+	_, ok = grantedScope([]string{ScopeRead}, []string{ScopeRead, ScopeDeviceAdmin})
+	assert.False(t, ok, "the consent page cannot widen the requested scope")
+
+	_, ok = grantedScope([]string{ScopeFull}, nil)
+	assert.False(t, ok)
+}
+
+func TestParseDevices(t *testing.T) {
+	devices, ok := parseDevices(" sales, support\nsales ")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"sales", "support"}, devices)
+
+	devices, ok = parseDevices("")
+	assert.True(t, ok)
+	assert.Empty(t, devices)
+}
+
+func TestAccess(t *testing.T) {
+	full := Access{Scopes: []string{ScopeFull}}
+	assert.True(t, full.Allows(ScopeDeviceAdmin))
+	assert.True(t, full.AllowsDevice("any"))
+	assert.False(t, full.DeviceRestricted())
+
+	limited := Access{Scopes: []string{ScopeRead}, Devices: []string{"sales"}}
+	assert.True(t, limited.Allows(ScopeRead))
+	assert.False(t, limited.Allows(ScopeSend))
+	assert.True(t, limited.AllowsDevice("sales"))
+	assert.False(t, limited.AllowsDevice("support"))
+}
@@ -3,6 +3,7 @@ package oauth
 import (
 	"bytes"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -14,13 +15,14 @@ import (
 	"strings"
 	"time"
 
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
 	"github.com/gofiber/fiber/v3"
 	"github.com/valyala/fasthttp"
 )
 
 const (
 	defaultStorageURI    = "file:storages/oauth.db"
-	defaultScope         = "mcp"
+	defaultScope         = ScopeFull
 	codeTTL              = 5 * time.Minute
 	accessTokenTTL       = time.Hour
 	refreshTokenTTL      = 30 * 24 * time.Hour
@@ -58,6 +60,12 @@ type authorizationRequest struct {
 	State               string `form:"state"`
 	Username            string `form:"username"`
 	Password            string `form:"password"`
+	// Consent page choices: the scopes granted out of those requested and an
+	// optional device restriction. ScopeChoice marks a submission from the
+	// page, so unticking every scope is not mistaken for a form without them.
+	ScopeChoice  string   `form:"scope_choice"`
+	GrantedScope []string `form:"granted_scope"`
+	Devices      string   `form:"devices"`
 }
 
 type tokenRequest struct {
@@ -70,6 +78,17 @@ type tokenRequest struct {
 	Resource     string `form:"resource"`
 }
 
+type revocationRequest struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+}
+
+type introspectionRequest struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+}
+
 type authorizePageData struct {
 	ClientName          string
 	RedirectTarget      string
@@ -83,16 +102,24 @@ type authorizePageData struct {
 	Resource            string
 	Scope               string
 	State               string
+	Scopes              []scopeOption
+	Devices             string
 	Error               string
 }
 
+type scopeOption struct {
+	Value       string
+	Description string
+	Checked     bool
+}
+
 var authorizeTemplate = template.Must(template.New("oauth-authorize").Parse(`<!doctype html>
 <html lang="en">
 <head>
   <meta charset="utf-8">
   <meta name="viewport" content="width=device-width,initial-scale=1">
   <title>Authorize {{.ClientName}}</title>
-  <style>body{font-family:system-ui,sans-serif;max-width:28rem;margin:4rem auto;padding:0 1rem}label{display:block;margin-top:1rem}input{box-sizing:border-box;width:100%;padding:.65rem}button{margin-top:1.25rem;padding:.7rem 1rem}.error{color:#a00}</style>
+  <style>body{font-family:system-ui,sans-serif;max-width:28rem;margin:4rem auto;padding:0 1rem}label{display:block;margin-top:1rem}input{box-sizing:border-box;width:100%;padding:.65rem}input[type=checkbox]{width:auto;margin-right:.4rem}fieldset{margin-top:1rem}button{margin-top:1.25rem;padding:.7rem 1rem}.error{color:#a00}</style>
 </head>
 <body>
   <h1>Authorize {{.ClientName}}</h1>
@@ -109,6 +136,13 @@ var authorizeTemplate = template.Must(template.New("oauth-authorize").Parse(`<!d
     <input type="hidden" name="resource" value="{{.Resource}}">
     <input type="hidden" name="scope" value="{{.Scope}}">
     <input type="hidden" name="state" value="{{.State}}">
+    <input type="hidden" name="scope_choice" value="1">
+    <fieldset>
+      <legend>Permissions</legend>
+      {{range .Scopes}}<label><input type="checkbox" name="granted_scope" value="{{.Value}}"{{if .Checked}} checked{{end}}> {{.Description}} <code>{{.Value}}</code></label>
+      {{end}}
+    </fieldset>
+    <label>Limit to device IDs (comma-separated, leave empty for all devices)<input name="devices" value="{{.Devices}}" autocomplete="off"></label>
     <label>Username<input name="username" autocomplete="username" required></label>
     <label>Password<input type="password" name="password" autocomplete="current-password" required></label>
     <button type="submit">Authorize</button>
@@ -165,14 +199,30 @@ func DefaultResourceURL(issuerURL, appBasePath string) (string, error) {
 	return resource.String(), nil
 }
 
-func (s *Server) RegisterPublic(app *fiber.App) {
+// RegisterPublic mounts the discovery and OAuth endpoints. grantHandlers run
+// ahead of the token and revocation endpoints only, e.g. to audit grants.
+func (s *Server) RegisterPublic(app *fiber.App, grantHandlers ...fiber.Handler) {
 	s.limitPublicPOSTBodies(app)
 	app.Get(wellKnownPath("oauth-authorization-server", s.issuer.Path), s.authorizationServerMetadata)
 	app.Get(wellKnownPath("oauth-protected-resource", s.resource.Path), s.protectedResourceMetadata)
 	app.Post(s.issuerEndpointPath("/oauth/register"), s.registerClient)
 	app.Get(s.issuerEndpointPath("/oauth/authorize"), s.authorizeGET)
 	app.Post(s.issuerEndpointPath("/oauth/authorize"), s.authorizePOST)
-	app.Post(s.issuerEndpointPath("/oauth/token"), s.token)
+	postWithHandlers(app, s.issuerEndpointPath("/oauth/token"), grantHandlers, s.token)
+	postWithHandlers(app, s.issuerEndpointPath("/oauth/revoke"), grantHandlers, s.revoke)
+	app.Post(s.issuerEndpointPath("/oauth/introspect"), s.introspect)
+}
+
+func postWithHandlers(app *fiber.App, path string, before []fiber.Handler, handler fiber.Handler) {
+	if len(before) == 0 {
+		app.Post(path, handler)
+		return
+	}
+	handlers := make([]any, 0, len(before))
+	for _, h := range before[1:] {
+		handlers = append(handlers, h)
+	}
+	app.Post(path, before[0], append(handlers, handler)...)
 }
 
 // limitPublicPOSTBodies applies the OAuth cap in fasthttp immediately after
@@ -182,6 +232,8 @@ func (s *Server) limitPublicPOSTBodies(app *fiber.App) {
 		routingPathKey(s.issuerEndpointPath("/oauth/register")),
 		routingPathKey(s.issuerEndpointPath("/oauth/authorize")),
 		routingPathKey(s.issuerEndpointPath("/oauth/token")),
+		routingPathKey(s.issuerEndpointPath("/oauth/revoke")),
+		routingPathKey(s.issuerEndpointPath("/oauth/introspect")),
 	}
 	previous := app.Server().HeaderReceived
 	app.Server().HeaderReceived = func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
@@ -242,6 +294,15 @@ func (s *Server) MCPAuthMiddleware(basic CredentialValidator) fiber.Handler {
 			}
 			c.Locals("oauth_subject", principal.Subject)
 			c.Locals("oauth_client_id", principal.ClientID)
+			ctx := domainAudit.ContextWithPrincipal(c.Context(), domainAudit.Principal{
+				Type:     domainAudit.PrincipalOAuth,
+				Subject:  principal.Subject,
+				ClientID: principal.ClientID,
+			})
+			c.SetContext(ContextWithAccess(ctx, Access{
+				Scopes:  strings.Fields(principal.Scope),
+				Devices: principal.Devices,
+			}))
 			return c.Next()
 		case "basic":
 			if basic == nil {
@@ -251,6 +312,10 @@ func (s *Server) MCPAuthMiddleware(basic CredentialValidator) fiber.Handler {
 			if !ok || !basic(username, password) {
 				return s.mcpUnauthorized(c, true, false)
 			}
+			c.SetContext(domainAudit.ContextWithPrincipal(c.Context(), domainAudit.Principal{
+				Type:    domainAudit.PrincipalBasic,
+				Subject: username,
+			}))
 			return c.Next()
 		default:
 			return s.mcpUnauthorized(c, basic != nil, false)
@@ -261,15 +326,18 @@ func (s *Server) MCPAuthMiddleware(basic CredentialValidator) fiber.Handler {
 func (s *Server) authorizationServerMetadata(c fiber.Ctx) error {
 	s.setNoStore(c)
 	return c.JSON(fiber.Map{
-		"issuer":                                         s.issuer.String(),
-		"authorization_endpoint":                         s.issuerEndpointURL("/oauth/authorize"),
-		"token_endpoint":                                 s.issuerEndpointURL("/oauth/token"),
-		"registration_endpoint":                          s.issuerEndpointURL("/oauth/register"),
+		"issuer":                 s.issuer.String(),
+		"authorization_endpoint": s.issuerEndpointURL("/oauth/authorize"),
+		"token_endpoint":         s.issuerEndpointURL("/oauth/token"),
+		"registration_endpoint":  s.issuerEndpointURL("/oauth/register"),
+		"revocation_endpoint":    s.issuerEndpointURL("/oauth/revoke"),
+		"revocation_endpoint_auth_methods_supported":     []string{"none"},
+		"introspection_endpoint":                         s.issuerEndpointURL("/oauth/introspect"),
 		"response_types_supported":                       []string{"code"},
 		"grant_types_supported":                          []string{"authorization_code", "refresh_token"},
 		"token_endpoint_auth_methods_supported":          []string{"none"},
 		"code_challenge_methods_supported":               []string{"S256"},
-		"scopes_supported":                               []string{defaultScope},
+		"scopes_supported":                               supportedScopes(),
 		"authorization_response_iss_parameter_supported": true,
 	})
 }
@@ -280,7 +348,7 @@ func (s *Server) protectedResourceMetadata(c fiber.Ctx) error {
 		"resource":                 s.resource.String(),
 		"authorization_servers":    []string{s.issuer.String()},
 		"bearer_methods_supported": []string{"header"},
-		"scopes_supported":         []string{defaultScope},
+		"scopes_supported":         supportedScopes(),
 		"resource_name":            "GOWA MCP",
 	})
 }
@@ -398,13 +466,27 @@ func (s *Server) authorizePOST(c fiber.Ctx) error {
 	if !s.validateCredential(req.Username, req.Password) {
 		return s.renderAuthorize(c, fiber.StatusUnauthorized, req, client, "Invalid username or password.")
 	}
+	scope := req.Scope
+	if req.ScopeChoice != "" {
+		requested, _ := parseScope(req.Scope)
+		granted, ok := grantedScope(requested, req.GrantedScope)
+		if !ok {
+			return s.renderAuthorize(c, fiber.StatusBadRequest, req, client, "Select at least one permission.")
+		}
+		scope = granted
+	}
+	devices, ok := parseDevices(req.Devices)
+	if !ok {
+		return s.renderAuthorize(c, fiber.StatusBadRequest, req, client, fmt.Sprintf("List at most %d devices.", maxGrantDevices))
+	}
 	code, err := s.store.issueAuthorizationCode(c.Context(), AuthorizationGrant{
 		ClientID:      req.ClientID,
 		Subject:       req.Username,
 		RedirectURI:   req.RedirectURI,
 		CodeChallenge: req.CodeChallenge,
 		Resource:      req.Resource,
-		Scope:         req.Scope,
+		Scope:         scope,
+		Devices:       devices,
 	}, s.now(), codeTTL)
 	if err != nil {
 		return oauthError(c, fiber.StatusInternalServerError, "server_error", "could not issue authorization code")
@@ -477,6 +559,83 @@ func (s *Server) token(c fiber.Ctx) error {
 	})
 }
 
+// revoke implements RFC 7009 token revocation for public clients. Revoking
+// either token of a grant revokes the whole grant. Unknown tokens and tokens
+// of other clients are answered with 200 like revoked ones, so the endpoint
+// reveals nothing about them. token_type_hint is not needed and is ignored.
+func (s *Server) revoke(c fiber.Ctx) error {
+	s.setNoStore(c)
+	var req revocationRequest
+	if err := c.Bind().Body(&req); err != nil || req.Token == "" {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_request", "token is required")
+	}
+	if _, err := s.store.getClient(c.Context(), req.ClientID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return oauthError(c, fiber.StatusUnauthorized, "invalid_client", "unknown client_id")
+		}
+		return oauthError(c, fiber.StatusInternalServerError, "server_error", "revocation request failed")
+	}
+	record, err := s.store.lookupToken(c.Context(), req.Token)
+	if errors.Is(err, ErrInvalidToken) {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	if err != nil {
+		return oauthError(c, fiber.StatusInternalServerError, "server_error", "revocation request failed")
+	}
+	if record.ClientID == req.ClientID {
+		if err := s.store.revokeGrant(c.Context(), record.FamilyID, s.now()); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return oauthError(c, fiber.StatusInternalServerError, "server_error", "revocation request failed")
+		}
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// introspect implements RFC 7662 token introspection. Callers authenticate
+// with an APP_BASIC_AUTH account, the same operators who may sign in on the
+// authorization page.
+func (s *Server) introspect(c fiber.Ctx) error {
+	s.setNoStore(c)
+	scheme, credentials := splitAuthorization(c.Get(fiber.HeaderAuthorization))
+	username, password, ok := decodeBasicCredentials(credentials)
+	if !strings.EqualFold(scheme, "basic") || !ok || !s.validateCredential(username, password) {
+		c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="GOWA OAuth"`)
+		return oauthError(c, fiber.StatusUnauthorized, "invalid_client", "introspection requires Basic Auth credentials")
+	}
+	var req introspectionRequest
+	if err := c.Bind().Body(&req); err != nil || req.Token == "" {
+		return oauthError(c, fiber.StatusBadRequest, "invalid_request", "token is required")
+	}
+	record, err := s.store.lookupToken(c.Context(), req.Token)
+	if errors.Is(err, ErrInvalidToken) {
+		return c.JSON(fiber.Map{"active": false})
+	}
+	if err != nil {
+		return oauthError(c, fiber.StatusInternalServerError, "server_error", "introspection request failed")
+	}
+	if record.Revoked || !s.now().Before(record.ExpiresAt) || record.Resource != s.resource.String() {
+		return c.JSON(fiber.Map{"active": false})
+	}
+	response := fiber.Map{
+		"active":     true,
+		"scope":      record.Scope,
+		"client_id":  record.ClientID,
+		"username":   record.Subject,
+		"token_type": "Bearer",
+		"exp":        record.ExpiresAt.Unix(),
+		"iat":        record.CreatedAt.Unix(),
+		"sub":        record.Subject,
+		"aud":        record.Resource,
+		"iss":        s.issuer.String(),
+	}
+	if record.Type == "refresh" {
+		response["token_type"] = "refresh_token"
+	}
+	if len(record.Devices) > 0 {
+		response["devices"] = record.Devices
+	}
+	return c.JSON(response)
+}
+
 func (s *Server) validateAuthorizationRequest(c fiber.Ctx, req *authorizationRequest) (Client, bool, error) {
 	client, err := s.store.getClient(c.Context(), req.ClientID)
 	if err != nil {
@@ -497,15 +656,19 @@ func (s *Server) validateAuthorizationRequest(c fiber.Ctx, req *authorizationReq
 	if req.Resource != s.resource.String() {
 		return Client{}, false, s.authorizationError(c, *req, "invalid_target", "resource does not match this MCP server")
 	}
-	if req.Scope == "" {
-		req.Scope = defaultScope
-	}
-	if req.Scope != defaultScope {
-		return Client{}, false, s.authorizationError(c, *req, "invalid_scope", "only the mcp scope is supported")
+	scopes, ok := parseScope(req.Scope)
+	if !ok {
+		return Client{}, false, s.authorizationError(c, *req, "invalid_scope", "supported scopes are "+strings.Join(supportedScopes(), ", "))
 	}
+	req.Scope = strings.Join(scopes, " ")
 	return client, true, nil
 }
 
+// supportedScopes lists every scope a client may request.
+func supportedScopes() []string {
+	return append([]string{ScopeFull}, grantableScopes...)
+}
+
 func (s *Server) renderAuthorize(c fiber.Ctx, status int, req authorizationRequest, client Client, pageError string) error {
 	s.setNoStore(c)
 	c.Set(fiber.HeaderContentSecurityPolicy, authorizationPageCSP(req.RedirectURI))
@@ -514,6 +677,17 @@ func (s *Server) renderAuthorize(c fiber.Ctx, status int, req authorizationReque
 	c.Type("html", "utf-8")
 	c.Status(status)
 	redirectTarget, localRedirect := redirectDisplay(req.RedirectURI)
+	requested, _ := parseScope(req.Scope)
+	var scopes []scopeOption
+	for _, scope := range offeredScopes(requested) {
+		scopes = append(scopes, scopeOption{
+			Value:       scope,
+			Description: scopeDescriptions[scope],
+			// Everything requested starts ticked; a re-rendered page keeps
+			// the user's choices.
+			Checked: req.ScopeChoice == "" || slices.Contains(req.GrantedScope, scope),
+		})
+	}
 	return authorizeTemplate.Execute(c, authorizePageData{
 		ClientName:          client.Name,
 		RedirectTarget:      redirectTarget,
@@ -527,6 +701,8 @@ func (s *Server) renderAuthorize(c fiber.Ctx, status int, req authorizationReque
 		Resource:            req.Resource,
 		Scope:               req.Scope,
 		State:               req.State,
+		Scopes:              scopes,
+		Devices:             req.Devices,
 		Error:               pageError,
 	})
 }
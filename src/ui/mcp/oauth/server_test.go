@@ -58,6 +58,8 @@ func TestMetadataUsesRFCWellKnownPathInsertion(t *testing.T) {
 	assert.Equal(t, "https://gowa.example.com/gowa/oauth/authorize", authMetadata["authorization_endpoint"])
 	assert.Equal(t, "https://gowa.example.com/gowa/oauth/token", authMetadata["token_endpoint"])
 	assert.Equal(t, "https://gowa.example.com/gowa/oauth/register", authMetadata["registration_endpoint"])
+	assert.Equal(t, "https://gowa.example.com/gowa/oauth/revoke", authMetadata["revocation_endpoint"])
+	assert.Equal(t, "https://gowa.example.com/gowa/oauth/introspect", authMetadata["introspection_endpoint"])
 	assert.Equal(t, []any{"S256"}, authMetadata["code_challenge_methods_supported"])
 	assert.Equal(t, true, authMetadata["authorization_response_iss_parameter_supported"])
 	_, claimsCIMD := authMetadata["client_id_metadata_document_supported"]
@@ -253,6 +255,176 @@ func TestRefreshRotationDetectsReuseAndRevokesFamily(t *testing.T) {
 	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode, "reuse must revoke access tokens in the refresh family")
 }
 
+func TestConsentPageGrantsChosenScopesAndDevices(t *testing.T) {
+	srv, app := newOAuthTestServer(t, testIssuer, testResource)
+	clientID := registerTestClient(t, app)
+
+	verifier := strings.Repeat("c", 43)
+	sum := sha256.Sum256([]byte(verifier))
+	form := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {clientID},
+		"redirect_uri":          {testRedirect},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+		"code_challenge_method": {"S256"},
+		"resource":              {testResource},
+		"scope":                 {"mcp"},
+		"state":                 {"state-123"},
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/oauth/authorize?"+form.Encode(), nil))
+	require.NoError(t, err)
+	page, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	for _, scope := range []string{ScopeRead, ScopeSend, ScopeGroupAdmin, ScopeDeviceAdmin} {
+		assert.Contains(t, string(page), `name="granted_scope" value="`+scope+`" checked`)
+	}
+	assert.Contains(t, string(page), `name="devices"`)
+
+	form.Set("username", "user")
+	form.Set("password", "secret")
+	form.Set("scope_choice", "1")
+
+	resp = postForm(t, app, "/oauth/authorize", form)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, "unticking every scope must not grant anything")
+
+	form["granted_scope"] = []string{ScopeRead, ScopeSend}
+	form.Set("devices", "sales, support")
+	resp = postForm(t, app, "/oauth/authorize", form)
+	require.Equal(t, fiber.StatusFound, resp.StatusCode)
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	tokens := exchangeTestCode(t, app, clientID, callback.Query().Get("code"), verifier, testResource)
+	assert.Equal(t, "mcp:read mcp:send", tokens.Scope)
+
+	resp = postForm(t, app, "/oauth/token", url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {clientID},
+		"refresh_token": {tokens.RefreshToken},
+		"resource":      {testResource},
+	})
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var refreshed tokenResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&refreshed))
+	assert.Equal(t, "mcp:read mcp:send", refreshed.Scope, "refreshing keeps the granted scope")
+
+	var access Access
+	protected := app.Group("", srv.MCPAuthMiddleware(nil))
+	protected.Post("/mcp", func(c fiber.Ctx) error {
+		access, _ = AccessFromContext(c.Context())
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	bearerReq := httptest.NewRequest("POST", "/mcp", nil)
+	bearerReq.Header.Set("Authorization", "Bearer "+refreshed.AccessToken)
+	resp, err = app.Test(bearerReq)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, []string{ScopeRead, ScopeSend}, access.Scopes)
+	assert.Equal(t, []string{"sales", "support"}, access.Devices)
+}
+
+func TestAuthorizationAcceptsFineGrainedScopeRequest(t *testing.T) {
+	_, app := newOAuthTestServer(t, testIssuer, testResource)
+	clientID := registerTestClient(t, app)
+	code, verifier := authorizeTestClientWithScope(t, app, clientID, "mcp:read")
+	tokens := exchangeTestCode(t, app, clientID, code, verifier, testResource)
+	assert.Equal(t, ScopeRead, tokens.Scope)
+}
+
+func TestRevocationRevokesTheWholeGrant(t *testing.T) {
+	_, app := newOAuthTestServer(t, testIssuer, testResource)
+	clientID := registerTestClient(t, app)
+	otherClientID := registerTestClient(t, app)
+	code, verifier := authorizeTestClient(t, app, clientID)
+	tokens := exchangeTestCode(t, app, clientID, code, verifier, testResource)
+
+	resp := postForm(t, app, "/oauth/revoke", url.Values{"client_id": {clientID}})
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assertOAuthError(t, resp, "invalid_request")
+
+	resp = postForm(t, app, "/oauth/revoke", url.Values{"client_id": {"unknown"}, "token": {tokens.AccessToken}})
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	assertOAuthError(t, resp, "invalid_client")
+
+	// Unknown tokens and tokens of another client are acknowledged without
+	// revealing anything, and nothing is revoked.
+	resp = postForm(t, app, "/oauth/revoke", url.Values{"client_id": {clientID}, "token": {"gowa_at_unknown"}})
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	resp = postForm(t, app, "/oauth/revoke", url.Values{"client_id": {otherClientID}, "token": {tokens.AccessToken}})
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.True(t, introspectTestToken(t, app, tokens.AccessToken)["active"].(bool))
+
+	resp = postForm(t, app, "/oauth/revoke", url.Values{
+		"client_id":       {clientID},
+		"token":           {tokens.AccessToken},
+		"token_type_hint": {"access_token"},
+	})
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.False(t, introspectTestToken(t, app, tokens.AccessToken)["active"].(bool))
+
+	resp = postForm(t, app, "/oauth/token", url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {clientID},
+		"refresh_token": {tokens.RefreshToken},
+		"resource":      {testResource},
+	})
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, "revoking the access token also revokes its refresh token")
+	assertOAuthError(t, resp, "invalid_grant")
+}
+
+func TestIntrospectionReportsTokenState(t *testing.T) {
+	_, app := newOAuthTestServer(t, testIssuer, testResource)
+	clientID := registerTestClient(t, app)
+	code, verifier := authorizeTestClient(t, app, clientID)
+	tokens := exchangeTestCode(t, app, clientID, code, verifier, testResource)
+
+	resp := postForm(t, app, "/oauth/introspect", url.Values{"token": {tokens.AccessToken}})
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, `Basic realm="GOWA OAuth"`, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+	assertOAuthError(t, resp, "invalid_client")
+
+	req := httptest.NewRequest("POST", "/oauth/introspect", strings.NewReader(url.Values{"token": {tokens.AccessToken}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("user", "wrong")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	access := introspectTestToken(t, app, tokens.AccessToken)
+	assert.Equal(t, true, access["active"])
+	assert.Equal(t, ScopeFull, access["scope"])
+	assert.Equal(t, clientID, access["client_id"])
+	assert.Equal(t, "user", access["username"])
+	assert.Equal(t, "user", access["sub"])
+	assert.Equal(t, "Bearer", access["token_type"])
+	assert.Equal(t, testResource, access["aud"])
+	assert.Equal(t, testIssuer, access["iss"])
+	assert.NotZero(t, access["exp"])
+	assert.NotZero(t, access["iat"])
+	_, restricted := access["devices"]
+	assert.False(t, restricted)
+
+	refresh := introspectTestToken(t, app, tokens.RefreshToken)
+	assert.Equal(t, true, refresh["active"])
+	assert.Equal(t, "refresh_token", refresh["token_type"])
+
+	assert.Equal(t, map[string]any{"active": false}, introspectTestToken(t, app, "gowa_at_unknown"))
+}
+
+func introspectTestToken(t *testing.T, app *fiber.App, token string) map[string]any {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/oauth/introspect", strings.NewReader(url.Values{"token": {token}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("user", "secret")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var payload map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	return payload
+}
+
 func registerTestClient(t *testing.T, app *fiber.App) string {
 	t.Helper()
 	body := `{"client_name":"Claude","redirect_uris":["https://claude.ai/api/mcp/auth_callback"],"application_type":"web","token_endpoint_auth_method":"none"}`
@@ -270,6 +442,11 @@ func registerTestClient(t *testing.T, app *fiber.App) string {
 }
 
 func authorizeTestClient(t *testing.T, app *fiber.App, clientID string) (string, string) {
+	t.Helper()
+	return authorizeTestClientWithScope(t, app, clientID, "mcp")
+}
+
+func authorizeTestClientWithScope(t *testing.T, app *fiber.App, clientID, scope string) (string, string) {
 	t.Helper()
 	verifier := strings.Repeat("b", 43)
 	sum := sha256.Sum256([]byte(verifier))
@@ -281,7 +458,7 @@ func authorizeTestClient(t *testing.T, app *fiber.App, clientID string) (string,
 		"code_challenge":        {challenge},
 		"code_challenge_method": {"S256"},
 		"resource":              {testResource},
-		"scope":                 {"mcp"},
+		"scope":                 {scope},
 		"state":                 {"state-123"},
 		"username":              {"user"},
 		"password":              {"secret"},
@@ -327,3 +504,27 @@ func assertOAuthError(t *testing.T, resp *http.Response, want string) {
 	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
 	assert.Equal(t, want, payload["error"])
 }
+
+func TestRegisterPublicRunsGrantHandlersOnTokenAndRevokeOnly(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "oauth.db")
+	srv, err := New(Config{
+		IssuerURL:   testIssuer,
+		ResourceURL: testResource,
+		StorageURI:  "file:" + filepath.ToSlash(dbPath),
+	}, func(string, string) bool { return false })
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, srv.Close()) })
+
+	var seen []string
+	app := fiber.New()
+	srv.RegisterPublic(app, func(c fiber.Ctx) error {
+		seen = append(seen, c.Route().Path)
+		return c.Next()
+	})
+
+	for _, path := range []string{"/oauth/register", "/oauth/token", "/oauth/revoke", "/oauth/introspect"} {
+		_, err := app.Test(httptest.NewRequest("POST", path, strings.NewReader("{}")))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"/oauth/token", "/oauth/revoke"}, seen)
+}
@@ -24,6 +24,10 @@ type store struct {
 	unusedClientTTL           time.Duration
 }
 
+// lastUsedResolution throttles last_used_at writes: every MCP request
+// validates its access token, and operators only need minute precision.
+const lastUsedResolution = time.Minute
+
 const (
 	defaultMaxClients                = 1000
 	defaultMaxRegistrationsPerWindow = 40
@@ -38,7 +42,8 @@ CREATE TABLE IF NOT EXISTS oauth_clients (
     redirect_uris_json TEXT NOT NULL,
     application_type TEXT NOT NULL,
     token_endpoint_auth_method TEXT NOT NULL,
-    created_at INTEGER NOT NULL
+    created_at INTEGER NOT NULL,
+    last_used_at INTEGER
 );
 CREATE INDEX IF NOT EXISTS idx_oauth_clients_created_at ON oauth_clients(created_at);
 CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
@@ -49,6 +54,7 @@ CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
     code_challenge TEXT NOT NULL,
     resource TEXT NOT NULL,
     scope TEXT NOT NULL,
+    devices_json TEXT NOT NULL DEFAULT '[]',
     expires_at INTEGER NOT NULL,
     used_at INTEGER,
     created_at INTEGER NOT NULL,
@@ -64,9 +70,11 @@ CREATE TABLE IF NOT EXISTS oauth_tokens (
     subject TEXT NOT NULL,
     resource TEXT NOT NULL,
     scope TEXT NOT NULL,
+    devices_json TEXT NOT NULL DEFAULT '[]',
     expires_at INTEGER NOT NULL,
     revoked_at INTEGER,
     created_at INTEGER NOT NULL,
+    last_used_at INTEGER,
     FOREIGN KEY (client_id) REFERENCES oauth_clients(client_id) ON DELETE CASCADE
 );
 CREATE INDEX IF NOT EXISTS idx_oauth_tokens_family ON oauth_tokens(family_id);
@@ -92,6 +100,20 @@ func openStore(uri string) (*store, error) {
 		_ = db.Close()
 		return nil, fmt.Errorf("initialize oauth storage: %w", err)
 	}
+	// Columns added after the first release. Older databases lack them; the
+	// devices_json default keeps their grants unrestricted.
+	migrations := []struct{ table, column, definition string }{
+		{"oauth_authorization_codes", "devices_json", `TEXT NOT NULL DEFAULT '[]'`},
+		{"oauth_tokens", "devices_json", `TEXT NOT NULL DEFAULT '[]'`},
+		{"oauth_tokens", "last_used_at", `INTEGER`},
+		{"oauth_clients", "last_used_at", `INTEGER`},
+	}
+	for _, m := range migrations {
+		if err := ensureColumn(ctx, db, m.table, m.column, m.definition); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("migrate oauth storage: %w", err)
+		}
+	}
 	return &store{
 		db:                        db,
 		maxClients:                defaultMaxClients,
@@ -101,6 +123,29 @@ func openStore(uri string) (*store, error) {
 	}, nil
 }
 
+// ensureColumn adds column to table unless it already exists.
+func ensureColumn(ctx context.Context, db *sql.DB, table, column, definition string) error {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+	return err
+}
+
 func (s *store) Close() error {
 	if s == nil || s.db == nil {
 		return nil
@@ -210,6 +255,10 @@ func (s *store) issueAuthorizationCode(ctx context.Context, grant AuthorizationG
 	if err != nil {
 		return "", err
 	}
+	devicesJSON, err := encodeDevices(grant.Devices)
+	if err != nil {
+		return "", err
+	}
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return "", err
@@ -221,8 +270,8 @@ func (s *store) issueAuthorizationCode(ctx context.Context, grant AuthorizationG
 	_, err = tx.ExecContext(ctx, `
 INSERT INTO oauth_authorization_codes (
     code_hash, client_id, subject, redirect_uri, code_challenge,
-    resource, scope, expires_at, created_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+    resource, scope, devices_json, expires_at, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		hashSecret(code),
 		grant.ClientID,
 		grant.Subject,
@@ -230,6 +279,7 @@ INSERT INTO oauth_authorization_codes (
 		grant.CodeChallenge,
 		grant.Resource,
 		grant.Scope,
+		devicesJSON,
 		now.Add(ttl).Unix(),
 		now.Unix(),
 	)
@@ -265,12 +315,13 @@ func (s *store) exchangeAuthorizationCode(
 		codeChallenge string
 		resource      string
 		scope         string
+		devicesJSON   string
 		expiresAt     int64
 		usedAt        sql.NullInt64
 	)
 	err = tx.QueryRowContext(ctx, `
 SELECT client_id, subject, redirect_uri, code_challenge, resource, scope,
-       expires_at, used_at
+       devices_json, expires_at, used_at
 FROM oauth_authorization_codes
 WHERE code_hash = ?`, hashSecret(req.Code)).Scan(
 		&clientID,
@@ -279,6 +330,7 @@ WHERE code_hash = ?`, hashSecret(req.Code)).Scan(
 		&codeChallenge,
 		&resource,
 		&scope,
+		&devicesJSON,
 		&expiresAt,
 		&usedAt,
 	)
@@ -314,10 +366,13 @@ WHERE code_hash = ? AND used_at IS NULL`, now.Unix(), hashSecret(req.Code))
 	if err != nil {
 		return TokenPair{}, err
 	}
-	pair, err := issueTokenPairTx(ctx, tx, familyID, clientID, subject, resource, scope, now, accessTTL, refreshTTL)
+	pair, err := issueTokenPairTx(ctx, tx, familyID, clientID, subject, resource, scope, devicesJSON, now, accessTTL, refreshTTL)
 	if err != nil {
 		return TokenPair{}, err
 	}
+	if err := touchGrant(ctx, tx, familyID, clientID, now); err != nil {
+		return TokenPair{}, err
+	}
 	if err := tx.Commit(); err != nil {
 		return TokenPair{}, err
 	}
@@ -341,16 +396,17 @@ func (s *store) rotateRefreshToken(
 	}
 
 	var (
-		familyID  string
-		clientID  string
-		subject   string
-		resource  string
-		scope     string
-		expiresAt int64
-		revokedAt sql.NullInt64
+		familyID    string
+		clientID    string
+		subject     string
+		resource    string
+		scope       string
+		devicesJSON string
+		expiresAt   int64
+		revokedAt   sql.NullInt64
 	)
 	err = tx.QueryRowContext(ctx, `
-SELECT family_id, client_id, subject, resource, scope, expires_at, revoked_at
+SELECT family_id, client_id, subject, resource, scope, devices_json, expires_at, revoked_at
 FROM oauth_tokens
 WHERE token_hash = ? AND token_type = 'refresh'`, hashSecret(req.RefreshToken)).Scan(
 		&familyID,
@@ -358,6 +414,7 @@ WHERE token_hash = ? AND token_type = 'refresh'`, hashSecret(req.RefreshToken)).
 		&subject,
 		&resource,
 		&scope,
+		&devicesJSON,
 		&expiresAt,
 		&revokedAt,
 	)
@@ -399,10 +456,13 @@ WHERE token_hash = ? AND token_type = 'refresh' AND revoked_at IS NULL`,
 		return TokenPair{}, ErrInvalidGrant
 	}
 
-	pair, err := issueTokenPairTx(ctx, tx, familyID, clientID, subject, resource, scope, now, accessTTL, refreshTTL)
+	pair, err := issueTokenPairTx(ctx, tx, familyID, clientID, subject, resource, scope, devicesJSON, now, accessTTL, refreshTTL)
 	if err != nil {
 		return TokenPair{}, err
 	}
+	if err := touchGrant(ctx, tx, familyID, clientID, now); err != nil {
+		return TokenPair{}, err
+	}
 	if err := tx.Commit(); err != nil {
 		return TokenPair{}, err
 	}
@@ -439,17 +499,21 @@ WHERE created_at <= ?
 func (s *store) validateAccessToken(ctx context.Context, rawToken, resource string, now time.Time) (Principal, error) {
 	var (
 		principal    Principal
+		familyID     string
 		storedTarget string
+		devicesJSON  string
 		expiresAt    int64
 		revokedAt    sql.NullInt64
 	)
 	err := s.db.QueryRowContext(ctx, `
-SELECT subject, client_id, scope, resource, expires_at, revoked_at
+SELECT family_id, subject, client_id, scope, devices_json, resource, expires_at, revoked_at
 FROM oauth_tokens
 WHERE token_hash = ? AND token_type = 'access'`, hashSecret(rawToken)).Scan(
+		&familyID,
 		&principal.Subject,
 		&principal.ClientID,
 		&principal.Scope,
+		&devicesJSON,
 		&storedTarget,
 		&expiresAt,
 		&revokedAt,
@@ -463,9 +527,197 @@ WHERE token_hash = ? AND token_type = 'access'`, hashSecret(rawToken)).Scan(
 	if revokedAt.Valid || now.Unix() >= expiresAt || storedTarget != resource {
 		return Principal{}, ErrInvalidToken
 	}
+	if err := json.Unmarshal([]byte(devicesJSON), &principal.Devices); err != nil {
+		return Principal{}, fmt.Errorf("decode oauth grant devices: %w", err)
+	}
+	if err := touchGrant(ctx, s.db, familyID, principal.ClientID, now); err != nil {
+		return Principal{}, err
+	}
 	return principal, nil
 }
 
+// touchGrant records that a grant and its client were just used, at most once
+// per lastUsedResolution.
+func touchGrant(ctx context.Context, ex execer, familyID, clientID string, now time.Time) error {
+	stale := now.Add(-lastUsedResolution).Unix()
+	if _, err := ex.ExecContext(ctx, `
+UPDATE oauth_tokens
+SET last_used_at = ?
+WHERE family_id = ? AND (last_used_at IS NULL OR last_used_at <= ?)`, now.Unix(), familyID, stale); err != nil {
+		return fmt.Errorf("record oauth grant use: %w", err)
+	}
+	if _, err := ex.ExecContext(ctx, `
+UPDATE oauth_clients
+SET last_used_at = ?
+WHERE client_id = ? AND (last_used_at IS NULL OR last_used_at <= ?)`, now.Unix(), clientID, stale); err != nil {
+		return fmt.Errorf("record oauth client use: %w", err)
+	}
+	return nil
+}
+
+// lookupToken returns the stored record of an access or refresh token,
+// whatever its state, or ErrInvalidToken when it is unknown.
+func (s *store) lookupToken(ctx context.Context, rawToken string) (tokenRecord, error) {
+	var (
+		record      tokenRecord
+		devicesJSON string
+		expiresAt   int64
+		createdAt   int64
+		revokedAt   sql.NullInt64
+	)
+	err := s.db.QueryRowContext(ctx, `
+SELECT token_type, family_id, client_id, subject, resource, scope, devices_json,
+       expires_at, created_at, revoked_at
+FROM oauth_tokens
+WHERE token_hash = ?`, hashSecret(rawToken)).Scan(
+		&record.Type,
+		&record.FamilyID,
+		&record.ClientID,
+		&record.Subject,
+		&record.Resource,
+		&record.Scope,
+		&devicesJSON,
+		&expiresAt,
+		&createdAt,
+		&revokedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tokenRecord{}, ErrInvalidToken
+	}
+	if err != nil {
+		return tokenRecord{}, err
+	}
+	if err := json.Unmarshal([]byte(devicesJSON), &record.Devices); err != nil {
+		return tokenRecord{}, fmt.Errorf("decode oauth grant devices: %w", err)
+	}
+	record.ExpiresAt = time.Unix(expiresAt, 0).UTC()
+	record.CreatedAt = time.Unix(createdAt, 0).UTC()
+	record.Revoked = revokedAt.Valid
+	return record, nil
+}
+
+// revokeGrant revokes every token of a grant. It returns sql.ErrNoRows when
+// the grant has no token left to revoke.
+func (s *store) revokeGrant(ctx context.Context, familyID string, now time.Time) error {
+	result, err := s.db.ExecContext(ctx, `
+UPDATE oauth_tokens
+SET revoked_at = ?
+WHERE family_id = ? AND revoked_at IS NULL AND expires_at > ?`, now.Unix(), familyID, now.Unix())
+	if err != nil {
+		return fmt.Errorf("revoke oauth grant: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// deleteClient removes a client; its authorization codes and tokens go with
+// it through ON DELETE CASCADE. It returns sql.ErrNoRows for unknown clients.
+func (s *store) deleteClient(ctx context.Context, clientID string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM oauth_clients WHERE client_id = ?`, clientID)
+	if err != nil {
+		return fmt.Errorf("delete oauth client: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// listConnectedClients returns the registered clients, newest first, with
+// their active grants. clientID, when not empty, selects a single client.
+func (s *store) listConnectedClients(ctx context.Context, clientID string, now time.Time) ([]ConnectedClient, error) {
+	rows, err := s.db.QueryContext(ctx, `
+SELECT client_id, client_name, redirect_uris_json, application_type, created_at, last_used_at
+FROM oauth_clients
+WHERE ? = '' OR client_id = ?
+ORDER BY created_at DESC, client_id`, clientID, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("list oauth clients: %w", err)
+	}
+	defer rows.Close()
+
+	clients := []ConnectedClient{}
+	index := map[string]int{}
+	for rows.Next() {
+		var (
+			client        ConnectedClient
+			redirectsJSON string
+			createdAt     int64
+			lastUsedAt    sql.NullInt64
+		)
+		if err := rows.Scan(&client.ClientID, &client.ClientName, &redirectsJSON, &client.ApplicationType, &createdAt, &lastUsedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(redirectsJSON), &client.RedirectURIs); err != nil {
+			return nil, fmt.Errorf("decode oauth redirect URIs: %w", err)
+		}
+		client.CreatedAt = time.Unix(createdAt, 0).UTC()
+		client.LastUsedAt = unixTimePtr(lastUsedAt)
+		client.Grants = []ActiveGrant{}
+		index[client.ClientID] = len(clients)
+		clients = append(clients, client)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// A grant is active while its current refresh token is: rotation revokes
+	// the previous one, and revoking the grant revokes them all.
+	grantRows, err := s.db.QueryContext(ctx, `
+SELECT family_id, client_id, subject, scope, devices_json, created_at, expires_at, last_used_at
+FROM oauth_tokens
+WHERE token_type = 'refresh' AND revoked_at IS NULL AND expires_at > ?
+  AND (? = '' OR client_id = ?)
+ORDER BY created_at DESC, family_id`, now.Unix(), clientID, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("list oauth grants: %w", err)
+	}
+	defer grantRows.Close()
+	for grantRows.Next() {
+		var (
+			grant       ActiveGrant
+			devicesJSON string
+			refreshedAt int64
+			expiresAt   int64
+			lastUsedAt  sql.NullInt64
+		)
+		if err := grantRows.Scan(&grant.GrantID, &grant.ClientID, &grant.Subject, &grant.Scope, &devicesJSON, &refreshedAt, &expiresAt, &lastUsedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(devicesJSON), &grant.Devices); err != nil {
+			return nil, fmt.Errorf("decode oauth grant devices: %w", err)
+		}
+		grant.RefreshedAt = time.Unix(refreshedAt, 0).UTC()
+		grant.ExpiresAt = time.Unix(expiresAt, 0).UTC()
+		grant.LastUsedAt = unixTimePtr(lastUsedAt)
+		if i, ok := index[grant.ClientID]; ok {
+			clients[i].Grants = append(clients[i].Grants, grant)
+		}
+	}
+	if err := grantRows.Err(); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+func unixTimePtr(value sql.NullInt64) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := time.Unix(value.Int64, 0).UTC()
+	return &t
+}
+
 func issueTokenPairTx(
 	ctx context.Context,
 	tx *sql.Tx,
@@ -473,7 +725,8 @@ func issueTokenPairTx(
 	clientID,
 	subject,
 	resource,
-	scope string,
+	scope,
+	devicesJSON string,
 	now time.Time,
 	accessTTL,
 	refreshTTL time.Duration,
@@ -489,18 +742,18 @@ func issueTokenPairTx(
 	if _, err := tx.ExecContext(ctx, `
 INSERT INTO oauth_tokens (
     token_hash, token_type, family_id, client_id, subject, resource, scope,
-    expires_at, created_at
-) VALUES (?, 'access', ?, ?, ?, ?, ?, ?, ?)`,
-		hashSecret(accessToken), familyID, clientID, subject, resource, scope,
+    devices_json, expires_at, created_at
+) VALUES (?, 'access', ?, ?, ?, ?, ?, ?, ?, ?)`,
+		hashSecret(accessToken), familyID, clientID, subject, resource, scope, devicesJSON,
 		now.Add(accessTTL).Unix(), now.Unix()); err != nil {
 		return TokenPair{}, err
 	}
 	if _, err := tx.ExecContext(ctx, `
 INSERT INTO oauth_tokens (
     token_hash, token_type, family_id, client_id, subject, resource, scope,
-    expires_at, created_at
-) VALUES (?, 'refresh', ?, ?, ?, ?, ?, ?, ?)`,
-		hashSecret(refreshToken), familyID, clientID, subject, resource, scope,
+    devices_json, expires_at, created_at
+) VALUES (?, 'refresh', ?, ?, ?, ?, ?, ?, ?, ?)`,
+		hashSecret(refreshToken), familyID, clientID, subject, resource, scope, devicesJSON,
 		now.Add(refreshTTL).Unix(), now.Unix()); err != nil {
 		return TokenPair{}, err
 	}
@@ -512,6 +765,18 @@ INSERT INTO oauth_tokens (
 	}, nil
 }
 
+// encodeDevices stores a grant's device restriction; no devices is "[]".
+func encodeDevices(devices []string) (string, error) {
+	if devices == nil {
+		devices = []string{}
+	}
+	encoded, err := json.Marshal(devices)
+	if err != nil {
+		return "", fmt.Errorf("encode oauth grant devices: %w", err)
+	}
+	return string(encoded), nil
+}
+
 func hashSecret(secret string) string {
 	sum := sha256.Sum256([]byte(secret))
 	return hex.EncodeToString(sum[:])
@@ -6,9 +6,11 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/sqlite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -106,6 +108,91 @@ func TestStoreAccessTokenIsBoundToResource(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidToken)
 }
 
+func TestStoreMigratesGrantDevicesColumn(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "oauth.db")
+	uri := "file:" + filepath.ToSlash(dbPath)
+
+	// A database from before per-device grants.
+	legacy, err := sql.Open(sqlite.DriverName, sqlite.FormatChatStorageURI(uri, true, true))
+	require.NoError(t, err)
+	_, err = legacy.ExecContext(ctx, `
+CREATE TABLE oauth_authorization_codes (
+    code_hash TEXT PRIMARY KEY, client_id TEXT NOT NULL, subject TEXT NOT NULL,
+    redirect_uri TEXT NOT NULL, code_challenge TEXT NOT NULL, resource TEXT NOT NULL,
+    scope TEXT NOT NULL, expires_at INTEGER NOT NULL, used_at INTEGER, created_at INTEGER NOT NULL
+);
+CREATE TABLE oauth_tokens (
+    token_hash TEXT PRIMARY KEY, token_type TEXT NOT NULL, family_id TEXT NOT NULL,
+    client_id TEXT NOT NULL, subject TEXT NOT NULL, resource TEXT NOT NULL, scope TEXT NOT NULL,
+    expires_at INTEGER NOT NULL, revoked_at INTEGER, created_at INTEGER NOT NULL
+);
+INSERT INTO oauth_tokens VALUES ('`+hashSecret("legacy-token")+`', 'access', 'f', 'c', 'user', '`+testResource+`', 'mcp', 4102444800, NULL, 0);`)
+	require.NoError(t, err)
+	require.NoError(t, legacy.Close())
+
+	store, err := openStore(uri)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, store.Close()) })
+
+	principal, err := store.validateAccessToken(ctx, "legacy-token", testResource, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "mcp", principal.Scope)
+	assert.Empty(t, principal.Devices, "grants from before the migration stay unrestricted")
+}
+
+func TestStoreThrottlesLastUsedUpdates(t *testing.T) {
+	ctx := context.Background()
+	store, err := openStore("file:" + filepath.ToSlash(filepath.Join(t.TempDir(), "oauth.db")))
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, store.Close()) })
+
+	now := time.Unix(1_800_000_000, 0).UTC()
+	require.NoError(t, store.createClient(ctx, Client{
+		ID:                      "client-1",
+		Name:                    "Client",
+		RedirectURIs:            []string{testRedirect},
+		ApplicationType:         "web",
+		TokenEndpointAuthMethod: "none",
+		CreatedAt:               now,
+	}))
+	verifier := strings.Repeat("v", 43)
+	sum := sha256.Sum256([]byte(verifier))
+	code, err := store.issueAuthorizationCode(ctx, AuthorizationGrant{
+		ClientID:      "client-1",
+		Subject:       "user",
+		RedirectURI:   testRedirect,
+		CodeChallenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+		Resource:      testResource,
+		Scope:         "mcp",
+	}, now, time.Minute)
+	require.NoError(t, err)
+	pair, err := store.exchangeAuthorizationCode(ctx, CodeExchange{
+		ClientID: "client-1", Code: code, RedirectURI: testRedirect, CodeVerifier: verifier, Resource: testResource,
+	}, now, time.Hour, 24*time.Hour)
+	require.NoError(t, err)
+
+	lastUsed := func() time.Time {
+		clients, err := store.listConnectedClients(ctx, "client-1", now)
+		require.NoError(t, err)
+		require.Len(t, clients, 1)
+		require.Len(t, clients[0].Grants, 1)
+		require.NotNil(t, clients[0].LastUsedAt)
+		require.NotNil(t, clients[0].Grants[0].LastUsedAt)
+		assert.Equal(t, *clients[0].LastUsedAt, *clients[0].Grants[0].LastUsedAt)
+		return *clients[0].LastUsedAt
+	}
+	assert.Equal(t, now, lastUsed())
+
+	_, err = store.validateAccessToken(ctx, pair.AccessToken, testResource, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, now, lastUsed(), "uses within lastUsedResolution are not written")
+
+	_, err = store.validateAccessToken(ctx, pair.AccessToken, testResource, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(2*time.Minute), lastUsed())
+}
+
 func TestStorePrunesExpiredCodesAndTokensBeforeWrites(t *testing.T) {
 	ctx := context.Background()
 	dbPath := filepath.Join(t.TempDir(), "oauth.db")
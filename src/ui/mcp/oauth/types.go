@@ -37,6 +37,8 @@ type AuthorizationGrant struct {
 	CodeChallenge string
 	Resource      string
 	Scope         string
+	// Devices, when not empty, limits the grant to these device IDs.
+	Devices []string
 }
 
 type CodeExchange struct {
@@ -64,6 +66,45 @@ type Principal struct {
 	Subject  string
 	ClientID string
 	Scope    string
+	Devices  []string
+}
+
+// tokenRecord is a stored token as seen by revocation and introspection.
+type tokenRecord struct {
+	Type      string
+	FamilyID  string
+	ClientID  string
+	Subject   string
+	Resource  string
+	Scope     string
+	Devices   []string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	Revoked   bool
+}
+
+// ConnectedClient is a registered OAuth client as listed by the admin API.
+type ConnectedClient struct {
+	ClientID        string        `json:"client_id"`
+	ClientName      string        `json:"client_name"`
+	RedirectURIs    []string      `json:"redirect_uris"`
+	ApplicationType string        `json:"application_type"`
+	CreatedAt       time.Time     `json:"created_at"`
+	LastUsedAt      *time.Time    `json:"last_used_at"`
+	Grants          []ActiveGrant `json:"grants"`
+}
+
+// ActiveGrant is an authorization a user gave a client that can still be
+// refreshed. GrantID identifies it for revocation.
+type ActiveGrant struct {
+	GrantID     string     `json:"grant_id"`
+	ClientID    string     `json:"client_id"`
+	Subject     string     `json:"subject"`
+	Scope       string     `json:"scope"`
+	Devices     []string   `json:"devices"`
+	RefreshedAt time.Time  `json:"refreshed_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
 }
 
 type tokenResponse struct {
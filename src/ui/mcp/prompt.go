@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// promptHistoryLimit bounds how many recent messages a prompt quotes.
+const promptHistoryLimit = 30
+
+// PromptHandler provides ready-made prompts built from a chat's history.
+type PromptHandler struct {
+	chatService domainChat.IChatUsecase
+}
+
+func InitMcpPrompt(chatService domainChat.IChatUsecase) *PromptHandler {
+	return &PromptHandler{chatService: chatService}
+}
+
+func (h *PromptHandler) AddPrompts(mcpServer *server.MCPServer) {
+	mcpServer.AddPrompt(mcpg.NewPrompt("summarize_unread_chat",
+		mcpg.WithPromptDescription("Summarize the messages received in a chat since the device last replied."),
+		mcpg.WithArgument("chat_jid", mcpg.RequiredArgument(), mcpg.ArgumentDescription("Chat JID, e.g. 628123456789@s.whatsapp.net")),
+	), h.summarizeUnreadChat)
+	mcpServer.AddPrompt(mcpg.NewPrompt("draft_reply",
+		mcpg.WithPromptDescription("Draft a reply to the latest messages of a chat."),
+		mcpg.WithArgument("chat_jid", mcpg.RequiredArgument(), mcpg.ArgumentDescription("Chat JID, e.g. 628123456789@s.whatsapp.net")),
+		mcpg.WithArgument("instructions", mcpg.ArgumentDescription("Optional guidance for the reply, e.g. tone or points to cover")),
+	), h.draftReply)
+}
+
+func (h *PromptHandler) summarizeUnreadChat(ctx context.Context, request mcpg.GetPromptRequest) (*mcpg.GetPromptResult, error) {
+	chatJID, messages, err := h.promptHistory(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without read receipts in chat storage, "unread" is everything received
+	// after the device's own last message.
+	unread := messages
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].IsFromMe {
+			unread = messages[i+1:]
+			break
+		}
+	}
+
+	var text strings.Builder
+	if len(unread) == 0 {
+		fmt.Fprintf(&text, "There are no messages in chat %s since the last reply. Say so briefly.", chatJID)
+	} else {
+		fmt.Fprintf(&text, "Summarize these %d WhatsApp messages received in chat %s since the last reply. "+
+			"List questions or requests that need an answer, then any other key points.\n\n", len(unread), chatJID)
+		writeTranscript(&text, unread)
+	}
+
+	return mcpg.NewGetPromptResult(
+		fmt.Sprintf("Unread messages in %s", chatJID),
+		[]mcpg.PromptMessage{mcpg.NewPromptMessage(mcpg.RoleUser, mcpg.NewTextContent(text.String()))},
+	), nil
+}
+
+func (h *PromptHandler) draftReply(ctx context.Context, request mcpg.GetPromptRequest) (*mcpg.GetPromptResult, error) {
+	chatJID, messages, err := h.promptHistory(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Draft a WhatsApp reply for chat %s, written as \"Me\" in the conversation below. "+
+		"Keep it short and in the language of the conversation.", chatJID)
+	if instructions := strings.TrimSpace(request.Params.Arguments["instructions"]); instructions != "" {
+		fmt.Fprintf(&text, " Follow these instructions: %s", instructions)
+	}
+	text.WriteString(" Only send it with the whatsapp_send tool after the user approves the draft.\n\n")
+	if len(messages) == 0 {
+		text.WriteString("(The chat has no stored messages.)\n")
+	} else {
+		writeTranscript(&text, messages)
+	}
+
+	return mcpg.NewGetPromptResult(
+		fmt.Sprintf("Reply draft for %s", chatJID),
+		[]mcpg.PromptMessage{mcpg.NewPromptMessage(mcpg.RoleUser, mcpg.NewTextContent(text.String()))},
+	), nil
+}
+
+// promptHistory returns the chat's recent messages, oldest first.
+func (h *PromptHandler) promptHistory(ctx context.Context, request mcpg.GetPromptRequest) (string, []domainChat.MessageInfo, error) {
+	if err := requireResourceDevice(ctx); err != nil {
+		return "", nil, err
+	}
+	chatJID := strings.TrimSpace(request.Params.Arguments["chat_jid"])
+	if chatJID == "" {
+		return "", nil, errors.New("chat_jid is required")
+	}
+	resp, err := h.chatService.GetChatMessages(ctx, domainChat.GetChatMessagesRequest{ChatJID: chatJID, Limit: promptHistoryLimit})
+	if err != nil {
+		return "", nil, err
+	}
+	messages := make([]domainChat.MessageInfo, 0, len(resp.Data))
+	for i := len(resp.Data) - 1; i >= 0; i-- {
+		messages = append(messages, resp.Data[i])
+	}
+	return chatJID, messages, nil
+}
+
+// writeTranscript renders messages as "[timestamp] sender: content" lines.
+func writeTranscript(text *strings.Builder, messages []domainChat.MessageInfo) {
+	for _, msg := range messages {
+		sender := msg.SenderDisplayName
+		if msg.IsFromMe {
+			sender = "Me"
+		} else if sender == "" {
+			sender = msg.SenderJID
+		}
+		content := msg.Content
+		if content == "" && msg.MediaType != "" {
+			content = fmt.Sprintf("[%s]", msg.MediaType)
+		}
+		fmt.Fprintf(text, "[%s] %s: %s\n", msg.Timestamp, sender, content)
+	}
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func promptReq(args map[string]string) mcpg.GetPromptRequest {
+	req := mcpg.GetPromptRequest{}
+	req.Params.Arguments = args
+	return req
+}
+
+func promptText(t *testing.T, res *mcpg.GetPromptResult) string {
+	t.Helper()
+	require.Len(t, res.Messages, 1)
+	return res.Messages[0].Content.(mcpg.TextContent).Text
+}
+
+func TestSummarizeUnreadChat(t *testing.T) {
+	// GetChatMessages returns newest first.
+	cs := &stubResourceChatService{messages: []domainChat.MessageInfo{
+		{Content: "are you there?", SenderDisplayName: "Bob"},
+		{Content: "ok", IsFromMe: true},
+		{Content: "lunch?", SenderDisplayName: "Bob"},
+	}}
+	h := InitMcpPrompt(cs)
+
+	res, err := h.summarizeUnreadChat(deviceCtx(), promptReq(map[string]string{"chat_jid": "628123@s.whatsapp.net"}))
+	require.NoError(t, err)
+	assert.Equal(t, promptHistoryLimit, cs.fetched.Limit)
+	text := promptText(t, res)
+	assert.Contains(t, text, "these 1 WhatsApp messages")
+	assert.Contains(t, text, "Bob: are you there?")
+	assert.NotContains(t, text, "lunch?")
+
+	cs.messages = []domainChat.MessageInfo{{Content: "ok", IsFromMe: true}}
+	res, err = h.summarizeUnreadChat(deviceCtx(), promptReq(map[string]string{"chat_jid": "628123@s.whatsapp.net"}))
+	require.NoError(t, err)
+	assert.Contains(t, promptText(t, res), "no messages")
+}
+
+func TestDraftReply(t *testing.T) {
+	cs := &stubResourceChatService{messages: []domainChat.MessageInfo{
+		{Content: "see you at 5", SenderJID: "628123@s.whatsapp.net"},
+		{MediaType: "image", IsFromMe: true},
+	}}
+	h := InitMcpPrompt(cs)
+
+	res, err := h.draftReply(deviceCtx(), promptReq(map[string]string{
+		"chat_jid":     "628123@s.whatsapp.net",
+		"instructions": " decline politely ",
+	}))
+	require.NoError(t, err)
+	text := promptText(t, res)
+	assert.Contains(t, text, "Follow these instructions: decline politely")
+	assert.Contains(t, text, "whatsapp_send")
+	// Oldest first, with media shown by type and unnamed senders by JID.
+	assert.Less(t, strings.Index(text, "Me: [image]"), strings.Index(text, "628123@s.whatsapp.net: see you at 5"))
+}
+
+func TestPromptValidation(t *testing.T) {
+	h := InitMcpPrompt(&stubResourceChatService{})
+
+	_, err := h.draftReply(deviceCtx(), promptReq(map[string]string{}))
+	require.Error(t, err)
+
+	_, err = h.summarizeUnreadChat(context.Background(), promptReq(map[string]string{"chat_jid": "628123@s.whatsapp.net"}))
+	require.Error(t, err)
+}
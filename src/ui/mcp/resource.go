@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	chatsResourceURI    = "whatsapp://chats"
+	contactsResourceURI = "whatsapp://contacts"
+	// {+jid} (reserved expansion) lets the "@" of a JID appear unescaped.
+	chatMessagesResourceTemplate = "whatsapp://chats/{+jid}/messages"
+	groupResourceTemplate        = "whatsapp://groups/{+jid}"
+
+	resourceChatLimit    = 50
+	resourceMessageLimit = 50
+)
+
+// chatMessagesResourceURI returns the message-thread resource URI of a chat.
+func chatMessagesResourceURI(chatJID string) string {
+	return "whatsapp://chats/" + chatJID + "/messages"
+}
+
+// ResourceHandler exposes chats, message threads, contacts and groups as MCP
+// resources so assistants can browse without calling whatsapp_chat. Resources
+// act on the device picked by the X-Device-Id header.
+type ResourceHandler struct {
+	chatService  domainChat.IChatUsecase
+	userService  domainUser.IUserUsecase
+	groupService domainGroup.IGroupUsecase
+}
+
+func InitMcpResource(chatService domainChat.IChatUsecase, userService domainUser.IUserUsecase, groupService domainGroup.IGroupUsecase) *ResourceHandler {
+	return &ResourceHandler{chatService: chatService, userService: userService, groupService: groupService}
+}
+
+func (h *ResourceHandler) AddResources(mcpServer *server.MCPServer) {
+	mcpServer.AddResource(mcpg.NewResource(chatsResourceURI, "Chats",
+		mcpg.WithResourceDescription(fmt.Sprintf("The %d most recently active chats of the device.", resourceChatLimit)),
+		mcpg.WithMIMEType("application/json"),
+	), h.readChats)
+	mcpServer.AddResource(mcpg.NewResource(contactsResourceURI, "Contacts",
+		mcpg.WithResourceDescription("Contacts saved on the device."),
+		mcpg.WithMIMEType("application/json"),
+	), h.readContacts)
+	mcpServer.AddResourceTemplate(mcpg.NewResourceTemplate(chatMessagesResourceTemplate, "Chat messages",
+		mcpg.WithTemplateDescription(fmt.Sprintf("The %d latest messages of a chat, newest first. Subscribe to be notified of new messages.", resourceMessageLimit)),
+		mcpg.WithTemplateMIMEType("application/json"),
+	), h.readChatMessages)
+	mcpServer.AddResourceTemplate(mcpg.NewResourceTemplate(groupResourceTemplate, "Group info",
+		mcpg.WithTemplateDescription("Name, topic, settings and participants of a group the device has joined."),
+		mcpg.WithTemplateMIMEType("application/json"),
+	), h.readGroup)
+}
+
+func (h *ResourceHandler) readChats(ctx context.Context, request mcpg.ReadResourceRequest) ([]mcpg.ResourceContents, error) {
+	if err := requireResourceDevice(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := h.chatService.ListChats(ctx, domainChat.ListChatsRequest{Limit: resourceChatLimit})
+	if err != nil {
+		return nil, err
+	}
+	return jsonResourceContents(request.Params.URI, resp.Data)
+}
+
+func (h *ResourceHandler) readContacts(ctx context.Context, request mcpg.ReadResourceRequest) ([]mcpg.ResourceContents, error) {
+	if err := requireResourceDevice(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := h.userService.MyListContacts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return jsonResourceContents(request.Params.URI, resp.Data)
+}
+
+func (h *ResourceHandler) readChatMessages(ctx context.Context, request mcpg.ReadResourceRequest) ([]mcpg.ResourceContents, error) {
+	if err := requireResourceDevice(ctx); err != nil {
+		return nil, err
+	}
+	chatJID, err := resourceJID(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.chatService.GetChatMessages(ctx, domainChat.GetChatMessagesRequest{ChatJID: chatJID, Limit: resourceMessageLimit})
+	if err != nil {
+		return nil, err
+	}
+	return jsonResourceContents(request.Params.URI, resp)
+}
+
+func (h *ResourceHandler) readGroup(ctx context.Context, request mcpg.ReadResourceRequest) ([]mcpg.ResourceContents, error) {
+	if err := requireResourceDevice(ctx); err != nil {
+		return nil, err
+	}
+	groupJID, err := resourceJID(request)
+	if err != nil {
+		return nil, err
+	}
+	// GroupInfo answers from the group cache and only asks WhatsApp on a miss.
+	resp, err := h.groupService.GroupInfo(ctx, domainGroup.GroupInfoRequest{GroupID: groupJID})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return nil, fmt.Errorf("group %s not found among the device's groups", groupJID)
+	}
+	return jsonResourceContents(request.Params.URI, resp.Data)
+}
+
+// requireResourceDevice fails a resource read that has no device: unlike
+// tools, resources take no device_id argument, so only the X-Device-Id header
+// (or the default device) applies.
+func requireResourceDevice(ctx context.Context) error {
+	if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
+		return nil
+	}
+	return errors.New("device identification required: set the X-Device-Id header")
+}
+
+// resourceJID returns the unescaped {jid} variable of a templated resource.
+func resourceJID(request mcpg.ReadResourceRequest) (string, error) {
+	var jid string
+	switch v := request.Params.Arguments["jid"].(type) {
+	case []string:
+		if len(v) > 0 {
+			jid = v[0]
+		}
+	case string:
+		jid = v
+	}
+	if unescaped, err := url.PathUnescape(jid); err == nil {
+		jid = unescaped
+	}
+	jid = strings.TrimSpace(jid)
+	if jid == "" {
+		return "", errors.New("jid is required")
+	}
+	return jid, nil
+}
+
+func jsonResourceContents(uri string, v any) ([]mcpg.ResourceContents, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return []mcpg.ResourceContents{mcpg.TextResourceContents{
+		URI:      uri,
+		MIMEType: "application/json",
+		Text:     string(data),
+	}}, nil
+}
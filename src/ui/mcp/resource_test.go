@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type stubResourceChatService struct {
+	stubChatService
+	messages []domainChat.MessageInfo
+}
+
+func (s *stubResourceChatService) GetChatMessages(_ context.Context, r domainChat.GetChatMessagesRequest) (domainChat.GetChatMessagesResponse, error) {
+	s.fetched = &r
+	return domainChat.GetChatMessagesResponse{Data: s.messages}, nil
+}
+
+type stubResourceUserService struct {
+	stubUserService
+}
+
+type stubResourceGroupService struct {
+	stubGroupService
+	groups []types.GroupInfo
+}
+
+func (s *stubResourceGroupService) GroupInfo(_ context.Context, r domainGroup.GroupInfoRequest) (domainGroup.GroupInfoResponse, error) {
+	s.infoReq = &r
+	for _, group := range s.groups {
+		if group.JID.String() == r.GroupID {
+			return domainGroup.GroupInfoResponse{Data: group}, nil
+		}
+	}
+	return domainGroup.GroupInfoResponse{}, errors.New("not a participant")
+}
+
+func readReq(uri string, jid any) mcpg.ReadResourceRequest {
+	req := mcpg.ReadResourceRequest{}
+	req.Params.URI = uri
+	if jid != nil {
+		req.Params.Arguments = map[string]any{"jid": jid}
+	}
+	return req
+}
+
+func TestResourceReads(t *testing.T) {
+	t.Run("chats require a device", func(t *testing.T) {
+		h := InitMcpResource(&stubResourceChatService{}, &stubResourceUserService{}, &stubResourceGroupService{})
+		_, err := h.readChats(context.Background(), readReq(chatsResourceURI, nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "X-Device-Id")
+	})
+
+	t.Run("chats", func(t *testing.T) {
+		cs := &stubResourceChatService{}
+		h := InitMcpResource(cs, &stubResourceUserService{}, &stubResourceGroupService{})
+		contents, err := h.readChats(deviceCtx(), readReq(chatsResourceURI, nil))
+		require.NoError(t, err)
+		require.NotNil(t, cs.listed)
+		assert.Equal(t, resourceChatLimit, cs.listed.Limit)
+		require.Len(t, contents, 1)
+		text := contents[0].(mcpg.TextResourceContents)
+		assert.Equal(t, chatsResourceURI, text.URI)
+		assert.Equal(t, "application/json", text.MIMEType)
+	})
+
+	t.Run("contacts", func(t *testing.T) {
+		us := &stubResourceUserService{}
+		h := InitMcpResource(&stubResourceChatService{}, us, &stubResourceGroupService{})
+		_, err := h.readContacts(deviceCtx(), readReq(contactsResourceURI, nil))
+		require.NoError(t, err)
+		assert.True(t, us.contactsCalled)
+	})
+
+	t.Run("chat messages unescape the jid", func(t *testing.T) {
+		cs := &stubResourceChatService{messages: []domainChat.MessageInfo{{ID: "m1", Content: "hi"}}}
+		h := InitMcpResource(cs, &stubResourceUserService{}, &stubResourceGroupService{})
+		uri := "whatsapp://chats/628123%40s.whatsapp.net/messages"
+		contents, err := h.readChatMessages(deviceCtx(), readReq(uri, []string{"628123%40s.whatsapp.net"}))
+		require.NoError(t, err)
+		require.NotNil(t, cs.fetched)
+		assert.Equal(t, "628123@s.whatsapp.net", cs.fetched.ChatJID)
+		assert.Equal(t, resourceMessageLimit, cs.fetched.Limit)
+
+		var resp domainChat.GetChatMessagesResponse
+		require.NoError(t, json.Unmarshal([]byte(contents[0].(mcpg.TextResourceContents).Text), &resp))
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, "m1", resp.Data[0].ID)
+	})
+
+	t.Run("chat messages require a jid", func(t *testing.T) {
+		h := InitMcpResource(&stubResourceChatService{}, &stubResourceUserService{}, &stubResourceGroupService{})
+		_, err := h.readChatMessages(deviceCtx(), readReq("whatsapp://chats//messages", []string{" "}))
+		require.Error(t, err)
+	})
+
+	t.Run("group found and missing", func(t *testing.T) {
+		group := types.GroupInfo{JID: types.NewJID("120363", types.GroupServer)}
+		group.Name = "Team"
+		gs := &stubResourceGroupService{groups: []types.GroupInfo{group}}
+		h := InitMcpResource(&stubResourceChatService{}, &stubResourceUserService{}, gs)
+
+		contents, err := h.readGroup(deviceCtx(), readReq("whatsapp://groups/120363@g.us", []string{"120363@g.us"}))
+		require.NoError(t, err)
+		assert.Contains(t, contents[0].(mcpg.TextResourceContents).Text, "Team")
+		require.NotNil(t, gs.infoReq)
+		assert.False(t, gs.infoReq.Refresh, "group reads must be served from the cache")
+
+		_, err = h.readGroup(deviceCtx(), readReq("whatsapp://groups/999@g.us", []string{"999@g.us"}))
+		require.Error(t, err)
+	})
+}
+
+func TestResourceTemplateMatchesRawJID(t *testing.T) {
+	cs := &stubResourceChatService{}
+	s := server.NewMCPServer("test", "1.0", server.WithResourceCapabilities(true, false))
+	InitMcpResource(cs, &stubResourceUserService{}, &stubResourceGroupService{}).AddResources(s)
+
+	msg := s.HandleMessage(deviceCtx(), json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"whatsapp://chats/628123@s.whatsapp.net/messages"}}`))
+	_, isErr := msg.(mcpg.JSONRPCError)
+	require.False(t, isErr, "unexpected error: %+v", msg)
+	require.NotNil(t, cs.fetched)
+	assert.Equal(t, "628123@s.whatsapp.net", cs.fetched.ChatJID)
+}
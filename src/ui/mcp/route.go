@@ -2,13 +2,19 @@ package mcp
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp/oauth"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/adaptor"
+	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/sirupsen/logrus"
 )
@@ -30,11 +36,14 @@ func Register(router fiber.Router, dm *whatsapp.DeviceManager, deps Deps) {
 
 	httpServer := server.NewStreamableHTTPServer(
 		NewServer(deps, resolver),
-		// Stateless: no server-initiated notifications or subscriptions are
-		// used, and it avoids tying a session store to Fiber's shutdown.
-		server.WithStateLess(true),
-		// No server-initiated notifications are used, so the standalone GET
-		// SSE stream is never needed. Without this, a GET reaches mcp-go's
+		// Sessions carry resource subscriptions. Clients that never send the
+		// Mcp-Session-Id header still work statelessly, and idle sessions
+		// are swept so clients that vanish without DELETE do not leak.
+		server.WithSessionIdManager(newIssuedSessionIdManager()),
+		server.WithSessionIdleTTL(mcpSessionIdleTTL),
+		// Resource notifications are queued on the session and delivered as
+		// SSE on its next POST, so the standalone GET SSE stream is never
+		// needed. Without this, a GET reaches mcp-go's
 		// "for { select { case <-writeChan: ...; case <-ctx.Done(): } }"
 		// loop; ctx there is the *fasthttp.RequestCtx, whose Done() channel
 		// only closes on server shutdown (not client disconnect), so the
@@ -42,6 +51,18 @@ func Register(router fiber.Router, dm *whatsapp.DeviceManager, deps Deps) {
 		// would all be pinned forever through the fasthttp adaptor.
 		server.WithDisableStreaming(true),
 		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+			// Carry the authenticated principal (set on the Fiber context by
+			// the auth middleware) into tool calls for the audit trail.
+			if fiberCtx, ok := adaptor.LocalContextFromHTTPRequest(r); ok {
+				if principal, ok := domainAudit.PrincipalFromContext(fiberCtx); ok {
+					ctx = domainAudit.ContextWithPrincipal(ctx, principal)
+				}
+				// The OAuth grant's scopes and devices, enforced per call
+				// (see scope.go).
+				if access, ok := oauth.AccessFromContext(fiberCtx); ok {
+					ctx = oauth.ContextWithAccess(ctx, access)
+				}
+			}
 			if dm == nil {
 				return ctx
 			}
@@ -57,7 +78,7 @@ func Register(router fiber.Router, dm *whatsapp.DeviceManager, deps Deps) {
 		}),
 	)
 
-	handler := adaptor.HTTPHandler(httpServer)
+	handler := adaptor.HTTPHandlerWithContext(httpServer)
 	// POST carries JSON-RPC calls; DELETE is part of the streamable-HTTP
 	// session lifecycle. GET is intentionally not mounted: with streaming
 	// disabled mcp-go would just 405 it, so Fiber's own 404 for an
@@ -65,3 +86,43 @@ func Register(router fiber.Router, dm *whatsapp.DeviceManager, deps Deps) {
 	router.Post("/mcp", handler)
 	router.Delete("/mcp", handler)
 }
+
+// mcpSessionIdleTTL is how long an MCP session and its resource
+// subscriptions survive without a request.
+const mcpSessionIdleTTL = 30 * time.Minute
+
+// issuedSessionIdManager only accepts the session ids it issued on initialize,
+// so a client cannot attach to another client's session (and its resource
+// notifications) by sending a made-up or leaked Mcp-Session-Id. Requests
+// without the header are still served statelessly; an unknown id, e.g. one
+// issued before a restart, is answered with 404 so the client initializes a
+// new session, as the MCP spec prescribes.
+type issuedSessionIdManager struct {
+	sessions sync.Map // session id -> struct{}
+}
+
+func newIssuedSessionIdManager() *issuedSessionIdManager {
+	return &issuedSessionIdManager{}
+}
+
+func (m *issuedSessionIdManager) Generate() string {
+	sessionID := "mcp-session-" + uuid.NewString()
+	m.sessions.Store(sessionID, struct{}{})
+	return sessionID
+}
+
+func (m *issuedSessionIdManager) Validate(sessionID string) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+	if _, ok := m.sessions.Load(sessionID); !ok {
+		return false, fmt.Errorf("unknown session id %s", sessionID)
+	}
+	return false, nil
+}
+
+// Terminate forgets a session on DELETE and when the idle sweeper expires it.
+func (m *issuedSessionIdManager) Terminate(sessionID string) (bool, error) {
+	m.sessions.Delete(sessionID)
+	return false, nil
+}
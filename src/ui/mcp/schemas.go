@@ -96,28 +96,43 @@ const groupSchema = `{
   "type": "object",
   "required": ["action"],
   "properties": {
-    "action": {"type": "string", "enum": ["create","join_with_link","leave","info","participants","add_participants","remove_participants","promote","demote","invite_link","set_name","set_topic","set_settings","join_requests","manage_join_requests"], "description": "Group operation"},
+    "action": {"type": "string", "enum": ["create","join_with_link","leave","info","participants","participant_history","add_participants","remove_participants","promote","demote","invite_link","set_name","set_topic","topic_history","set_settings","join_requests","manage_join_requests","create_community","community_groups","link_group","unlink_group","community_participants","community_announce"], "description": "Group or community operation"},
     "device_id": {"type": "string", "description": "Act as this device instead of the connection default"},
-    "group_id": {"type": "string", "description": "Group JID or numeric ID (all actions except create/join_with_link)"},
+    "group_id": {"type": "string", "description": "Group JID or numeric ID (group actions except create/join_with_link; link_group/unlink_group: the group to (un)link)"},
+    "community_id": {"type": "string", "description": "Community JID: community_groups / link_group / unlink_group / community_participants / community_announce; create (optional): create the group inside this community"},
     "title": {"type": "string", "description": "create: group subject"},
-    "participants": {"type": "array", "items": {"type": "string"}, "description": "create (optional) / add_participants / remove_participants / promote / demote / manage_join_requests: phone numbers without suffix"},
+    "participants": {"type": "array", "items": {"type": "string"}, "description": "create / create_community (optional) / add_participants / remove_participants / promote / demote / manage_join_requests: phone numbers without suffix"},
     "invite_link": {"type": "string", "description": "join_with_link: WhatsApp invite link"},
+    "refresh": {"type": "boolean", "description": "info / participants: fetch from WhatsApp instead of the cached group (default false)"},
+    "participant": {"type": "string", "description": "participant_history: only this member's history (phone number or JID, optional)"},
+    "history_action": {"type": "string", "enum": ["join","leave","promote","demote"], "description": "participant_history: only this kind of change (optional)"},
+    "limit": {"type": "integer", "minimum": 1, "maximum": 500, "description": "participant_history, topic_history: page size (default 50)"},
+    "offset": {"type": "integer", "minimum": 0, "description": "participant_history, topic_history: rows to skip (default 0)"},
     "reset": {"type": "boolean", "description": "invite_link: revoke and regenerate the link (default false)"},
-    "name": {"type": "string", "description": "set_name: new group name"},
+    "name": {"type": "string", "description": "set_name: new group name; create_community: community name"},
+    "description": {"type": "string", "description": "create_community: community description (optional)"},
+    "message": {"type": "string", "description": "community_announce: text posted to the community announcement group"},
     "topic": {"type": "string", "description": "set_topic: new topic/description"},
     "announce": {"type": "boolean", "description": "set_settings: true = only admins can send"},
     "locked": {"type": "boolean", "description": "set_settings: true = only admins can edit group info"},
+    "member_add_mode": {"type": "string", "enum": ["admin_add","all_member_add"], "description": "set_settings: who can add members"},
+    "join_approval": {"type": "boolean", "description": "set_settings: true = admins must approve new members"},
+    "ephemeral_seconds": {"type": "integer", "enum": [0, 86400, 604800, 7776000], "description": "set_settings: default disappearing-message timer, 0 = off"},
     "request_action": {"type": "string", "enum": ["approve","reject"], "description": "manage_join_requests: what to do with the listed requesters"}
   },
   "allOf": [
     {"if": {"properties": {"action": {"const": "create"}}},         "then": {"required": ["title"]}},
     {"if": {"properties": {"action": {"const": "join_with_link"}}}, "then": {"required": ["invite_link"]}},
-    {"if": {"properties": {"action": {"enum": ["leave","info","participants","invite_link","join_requests"]}}}, "then": {"required": ["group_id"]}},
+    {"if": {"properties": {"action": {"enum": ["leave","info","participants","participant_history","topic_history","invite_link","join_requests"]}}}, "then": {"required": ["group_id"]}},
     {"if": {"properties": {"action": {"enum": ["add_participants","remove_participants","promote","demote"]}}}, "then": {"required": ["group_id", "participants"]}},
     {"if": {"properties": {"action": {"const": "set_name"}}},  "then": {"required": ["group_id", "name"]}},
     {"if": {"properties": {"action": {"const": "set_topic"}}}, "then": {"required": ["group_id", "topic"]}},
-    {"if": {"properties": {"action": {"const": "set_settings"}}}, "then": {"required": ["group_id"], "anyOf": [{"required": ["announce"]}, {"required": ["locked"]}]}},
-    {"if": {"properties": {"action": {"const": "manage_join_requests"}}}, "then": {"required": ["group_id", "participants", "request_action"]}}
+    {"if": {"properties": {"action": {"const": "set_settings"}}}, "then": {"required": ["group_id"], "anyOf": [{"required": ["announce"]}, {"required": ["locked"]}, {"required": ["member_add_mode"]}, {"required": ["join_approval"]}, {"required": ["ephemeral_seconds"]}]}},
+    {"if": {"properties": {"action": {"const": "manage_join_requests"}}}, "then": {"required": ["group_id", "participants", "request_action"]}},
+    {"if": {"properties": {"action": {"const": "create_community"}}}, "then": {"required": ["name"]}},
+    {"if": {"properties": {"action": {"enum": ["community_groups","community_participants"]}}}, "then": {"required": ["community_id"]}},
+    {"if": {"properties": {"action": {"enum": ["link_group","unlink_group"]}}}, "then": {"required": ["community_id", "group_id"]}},
+    {"if": {"properties": {"action": {"const": "community_announce"}}}, "then": {"required": ["community_id", "message"]}}
   ]
 }`
 
@@ -133,3 +148,67 @@ const appSchema = `{
     {"if": {"properties": {"action": {"const": "login_code"}}}, "then": {"required": ["phone"]}}
   ]
 }`
+
+const newsletterSchema = `{
+  "type": "object",
+  "required": ["action"],
+  "properties": {
+    "action": {"type": "string", "enum": ["list","create","follow","unfollow","update","mute","messages","post","edit_post","delete_post","reactions"], "description": "Newsletter (channel) operation. post/edit_post/delete_post/update require channel admin rights; delete_post removes the post for all followers (destructive)"},
+    "device_id": {"type": "string", "description": "Act as this device instead of the connection default"},
+    "newsletter_id": {"type": "string", "pattern": "@newsletter$", "description": "Newsletter JID (e.g. 120363...@newsletter); every action except list/create"},
+    "name": {"type": "string", "maxLength": 100, "description": "create / update: channel name"},
+    "description": {"type": "string", "maxLength": 2048, "description": "create / update: channel description; update with an empty string clears it"},
+    "picture_url": {"type": "string", "description": "create / update: URL of the channel picture (cropped to a square JPEG server-side)"},
+    "mute": {"type": "boolean", "description": "mute: true mutes, false unmutes (default true)"},
+    "count": {"type": "integer", "minimum": 1, "maximum": 100, "description": "messages: max messages (default 50)"},
+    "before": {"type": "integer", "minimum": 0, "description": "messages: only messages older than this server ID"},
+    "type": {"type": "string", "enum": ["text","image","video"], "description": "post: kind of post (default text)"},
+    "message": {"type": "string", "description": "post: text body (type=text) or caption fallback; edit_post: replacement text"},
+    "caption": {"type": "string", "description": "post: caption of an image/video post"},
+    "media_url": {"type": "string", "description": "post: URL of the image/video (fetched server-side)"},
+    "message_id": {"type": "string", "description": "edit_post / delete_post: the post's message ID"},
+    "server_id": {"type": "integer", "minimum": 1, "description": "reactions: the post's server ID (see messages)"}
+  },
+  "allOf": [
+    {"if": {"properties": {"action": {"enum": ["follow","unfollow","update","mute","messages","post","edit_post","delete_post","reactions"]}}}, "then": {"required": ["newsletter_id"]}},
+    {"if": {"properties": {"action": {"const": "create"}}}, "then": {"required": ["name"]}},
+    {"if": {"properties": {"action": {"const": "update"}}}, "then": {"anyOf": [{"required": ["name"]}, {"required": ["description"]}, {"required": ["picture_url"]}]}},
+    {"if": {"properties": {"action": {"const": "post"}, "type": {"const": "text"}}}, "then": {"required": ["message"]}},
+    {"if": {"properties": {"action": {"const": "post"}, "type": {"enum": ["image","video"]}}, "required": ["type"]}, "then": {"required": ["media_url"]}},
+    {"if": {"properties": {"action": {"const": "edit_post"}}},   "then": {"required": ["message_id", "message"]}},
+    {"if": {"properties": {"action": {"const": "delete_post"}}}, "then": {"required": ["message_id"]}},
+    {"if": {"properties": {"action": {"const": "reactions"}}},   "then": {"required": ["server_id"]}}
+  ]
+}`
+
+const userSchema = `{
+  "type": "object",
+  "required": ["action"],
+  "properties": {
+    "action": {"type": "string", "enum": ["check","info","avatar","business_profile","presence","privacy"], "description": "User lookup. check = whether the number is registered on WhatsApp (verify before messaging a new number); privacy = the device's own privacy settings"},
+    "device_id": {"type": "string", "description": "Act as this device instead of the connection default"},
+    "phone": {"type": "string", "description": "Phone number or JID of the user (every action except privacy)"},
+    "is_preview": {"type": "boolean", "description": "avatar: return the low-resolution preview (default false)"},
+    "is_community": {"type": "boolean", "description": "avatar: phone is a community JID (default false)"}
+  },
+  "allOf": [
+    {"if": {"properties": {"action": {"enum": ["check","info","avatar","business_profile","presence"]}}}, "then": {"required": ["phone"]}}
+  ]
+}`
+
+const deviceSchema = `{
+  "type": "object",
+  "required": ["action"],
+  "properties": {
+    "action": {"type": "string", "enum": ["list","get","health","add","remove","get_webhook","set_webhook"], "description": "Multi-device administration. list/health show every device so you can pick the device_id for other tools; remove logs the device out and deletes it (destructive). Log a new device in with whatsapp_app and its device_id"},
+    "device_id": {"type": "string", "description": "Target device ID (get/remove/get_webhook/set_webhook; add: optional, generated when omitted)"},
+    "webhook_url": {"type": "string", "description": "add (optional) / set_webhook: webhook URL for this device; set_webhook with an empty string falls back to the global webhooks"},
+    "webhook_secret": {"type": "string", "description": "add / set_webhook: HMAC secret for this device's webhook (optional)"},
+    "webhook_events": {"type": "string", "description": "add / set_webhook: comma-separated event whitelist, e.g. message,message.ack (optional, default all)"},
+    "webhook_insecure_skip_verify": {"type": "boolean", "description": "add / set_webhook: skip TLS verification of the webhook URL (default false)"}
+  },
+  "allOf": [
+    {"if": {"properties": {"action": {"enum": ["get","remove","get_webhook"]}}}, "then": {"required": ["device_id"]}},
+    {"if": {"properties": {"action": {"const": "set_webhook"}}}, "then": {"required": ["device_id", "webhook_url"]}}
+  ]
+}`
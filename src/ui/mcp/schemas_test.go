@@ -86,12 +86,28 @@ func TestSchemas(t *testing.T) {
 		// ---- whatsapp_group ----
 		{"group create ok", groupSchema, `{"action":"create","title":"T","participants":["628"]}`, false},
 		{"group create missing title", groupSchema, `{"action":"create"}`, true},
+		{"group create in community ok", groupSchema, `{"action":"create","title":"T","participants":["628"],"community_id":"999@g.us"}`, false},
+		{"group create_community ok", groupSchema, `{"action":"create_community","name":"Customers"}`, false},
+		{"group create_community missing name", groupSchema, `{"action":"create_community"}`, true},
+		{"group community_groups ok", groupSchema, `{"action":"community_groups","community_id":"999@g.us"}`, false},
+		{"group community_groups missing id", groupSchema, `{"action":"community_groups"}`, true},
+		{"group link_group ok", groupSchema, `{"action":"link_group","community_id":"999@g.us","group_id":"123@g.us"}`, false},
+		{"group unlink_group missing group", groupSchema, `{"action":"unlink_group","community_id":"999@g.us"}`, true},
+		{"group community_participants ok", groupSchema, `{"action":"community_participants","community_id":"999@g.us"}`, false},
+		{"group community_announce ok", groupSchema, `{"action":"community_announce","community_id":"999@g.us","message":"hi"}`, false},
+		{"group community_announce missing message", groupSchema, `{"action":"community_announce","community_id":"999@g.us"}`, true},
 		{"group join ok", groupSchema, `{"action":"join_with_link","invite_link":"http://chat.whatsapp.com/x"}`, false},
 		{"group join missing link", groupSchema, `{"action":"join_with_link"}`, true},
 		{"group leave ok", groupSchema, `{"action":"leave","group_id":"123@g.us"}`, false},
 		{"group leave missing id", groupSchema, `{"action":"leave"}`, true},
 		{"group info ok", groupSchema, `{"action":"info","group_id":"123@g.us"}`, false},
+		{"group info refresh ok", groupSchema, `{"action":"info","group_id":"123@g.us","refresh":true}`, false},
 		{"group participants ok", groupSchema, `{"action":"participants","group_id":"123@g.us"}`, false},
+		{"group participant_history ok", groupSchema, `{"action":"participant_history","group_id":"123@g.us","participant":"628","history_action":"leave","limit":20}`, false},
+		{"group participant_history missing id", groupSchema, `{"action":"participant_history"}`, true},
+		{"group topic_history ok", groupSchema, `{"action":"topic_history","group_id":"123@g.us","limit":20}`, false},
+		{"group topic_history missing id", groupSchema, `{"action":"topic_history"}`, true},
+		{"group participant_history bad action", groupSchema, `{"action":"participant_history","group_id":"123@g.us","history_action":"kick"}`, true},
 		{"group add ok", groupSchema, `{"action":"add_participants","group_id":"123@g.us","participants":["628"]}`, false},
 		{"group add missing participants", groupSchema, `{"action":"add_participants","group_id":"123@g.us"}`, true},
 		{"group remove ok", groupSchema, `{"action":"remove_participants","group_id":"123@g.us","participants":["628"]}`, false},
@@ -104,6 +120,11 @@ func TestSchemas(t *testing.T) {
 		{"group set_settings announce", groupSchema, `{"action":"set_settings","group_id":"123@g.us","announce":true}`, false},
 		{"group set_settings locked", groupSchema, `{"action":"set_settings","group_id":"123@g.us","locked":false}`, false},
 		{"group set_settings neither", groupSchema, `{"action":"set_settings","group_id":"123@g.us"}`, true},
+		{"group set_settings member_add_mode ok", groupSchema, `{"action":"set_settings","group_id":"123@g.us","member_add_mode":"admin_add"}`, false},
+		{"group set_settings bad member_add_mode", groupSchema, `{"action":"set_settings","group_id":"123@g.us","member_add_mode":"anyone"}`, true},
+		{"group set_settings ephemeral ok", groupSchema, `{"action":"set_settings","group_id":"123@g.us","ephemeral_seconds":604800}`, false},
+		{"group set_settings bad ephemeral", groupSchema, `{"action":"set_settings","group_id":"123@g.us","ephemeral_seconds":60}`, true},
+		{"group set_settings join_approval ok", groupSchema, `{"action":"set_settings","group_id":"123@g.us","join_approval":false}`, false},
 		{"group join_requests ok", groupSchema, `{"action":"join_requests","group_id":"123@g.us"}`, false},
 		{"group manage_join_requests ok", groupSchema, `{"action":"manage_join_requests","group_id":"123@g.us","participants":["628"],"request_action":"approve"}`, false},
 		{"group manage_join_requests bad verb", groupSchema, `{"action":"manage_join_requests","group_id":"123@g.us","participants":["628"],"request_action":"ban"}`, true},
@@ -117,11 +138,46 @@ func TestSchemas(t *testing.T) {
 		{"app logout ok", appSchema, `{"action":"logout"}`, false},
 		{"app reconnect ok", appSchema, `{"action":"reconnect"}`, false},
 		{"app bad action", appSchema, `{"action":"restart"}`, true},
+
+		// ---- whatsapp_newsletter ----
+		{"newsletter list ok", newsletterSchema, `{"action":"list"}`, false},
+		{"newsletter create ok", newsletterSchema, `{"action":"create","name":"Brand","picture_url":"http://x/a.png"}`, false},
+		{"newsletter create missing name", newsletterSchema, `{"action":"create"}`, true},
+		{"newsletter follow missing id", newsletterSchema, `{"action":"follow"}`, true},
+		{"newsletter follow group jid", newsletterSchema, `{"action":"follow","newsletter_id":"1203@g.us"}`, true},
+		{"newsletter update description ok", newsletterSchema, `{"action":"update","newsletter_id":"1203@newsletter","description":""}`, false},
+		{"newsletter update nothing", newsletterSchema, `{"action":"update","newsletter_id":"1203@newsletter"}`, true},
+		{"newsletter mute ok", newsletterSchema, `{"action":"mute","newsletter_id":"1203@newsletter","mute":false}`, false},
+		{"newsletter post text ok", newsletterSchema, `{"action":"post","newsletter_id":"1203@newsletter","message":"hi"}`, false},
+		{"newsletter post text missing message", newsletterSchema, `{"action":"post","newsletter_id":"1203@newsletter"}`, true},
+		{"newsletter post image ok", newsletterSchema, `{"action":"post","newsletter_id":"1203@newsletter","type":"image","media_url":"http://x/a.png"}`, false},
+		{"newsletter post video missing url", newsletterSchema, `{"action":"post","newsletter_id":"1203@newsletter","type":"video","message":"hi"}`, true},
+		{"newsletter edit_post missing message", newsletterSchema, `{"action":"edit_post","newsletter_id":"1203@newsletter","message_id":"M1"}`, true},
+		{"newsletter delete_post ok", newsletterSchema, `{"action":"delete_post","newsletter_id":"1203@newsletter","message_id":"M1"}`, false},
+		{"newsletter reactions ok", newsletterSchema, `{"action":"reactions","newsletter_id":"1203@newsletter","server_id":42}`, false},
+		{"newsletter reactions missing server_id", newsletterSchema, `{"action":"reactions","newsletter_id":"1203@newsletter"}`, true},
+
+		// ---- whatsapp_user ----
+		{"user check ok", userSchema, `{"action":"check","phone":"628"}`, false},
+		{"user check missing phone", userSchema, `{"action":"check"}`, true},
+		{"user avatar ok", userSchema, `{"action":"avatar","phone":"628","is_preview":true}`, false},
+		{"user business_profile missing phone", userSchema, `{"action":"business_profile"}`, true},
+		{"user privacy ok", userSchema, `{"action":"privacy"}`, false},
+		{"user bad action", userSchema, `{"action":"block","phone":"628"}`, true},
+
+		// ---- whatsapp_device ----
+		{"device list ok", deviceSchema, `{"action":"list"}`, false},
+		{"device add without id ok", deviceSchema, `{"action":"add"}`, false},
+		{"device get missing id", deviceSchema, `{"action":"get"}`, true},
+		{"device remove missing id", deviceSchema, `{"action":"remove"}`, true},
+		{"device set_webhook ok", deviceSchema, `{"action":"set_webhook","device_id":"d1","webhook_url":""}`, false},
+		{"device set_webhook missing url", deviceSchema, `{"action":"set_webhook","device_id":"d1","webhook_secret":"s"}`, true},
+		{"device bad action", deviceSchema, `{"action":"rename","device_id":"d1"}`, true},
 	}
 
 	// compile each schema once
 	compiled := map[string]*jsonschema.Schema{}
-	for _, raw := range []string{sendSchema, messageSchema, chatSchema, groupSchema, appSchema} {
+	for _, raw := range []string{sendSchema, messageSchema, chatSchema, groupSchema, appSchema, newsletterSchema, userSchema, deviceSchema} {
 		compiled[raw] = compileSchema(t, raw)
 	}
 
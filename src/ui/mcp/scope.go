@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp/oauth"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolWriteScopes is the OAuth scope a tool's mutating actions need. Read-only
+// actions (readOnlyToolActions) need oauth.ScopeRead instead.
+var toolWriteScopes = map[string]string{
+	"whatsapp_send":       oauth.ScopeSend,
+	"whatsapp_message":    oauth.ScopeSend,
+	"whatsapp_chat":       oauth.ScopeSend,
+	"whatsapp_newsletter": oauth.ScopeSend,
+	"whatsapp_group":      oauth.ScopeGroupAdmin,
+	"whatsapp_app":        oauth.ScopeDeviceAdmin,
+	"whatsapp_device":     oauth.ScopeDeviceAdmin,
+}
+
+// toolActionScopes overrides toolWriteScopes for single actions.
+var toolActionScopes = map[string]map[string]string{
+	// Creating or editing a channel is administration, posting to it is not.
+	"whatsapp_newsletter": {"create": oauth.ScopeGroupAdmin, "update": oauth.ScopeGroupAdmin},
+}
+
+// requiredToolScope returns the scope a tool action needs. Tools without a
+// mapping need the all-access scope.
+func requiredToolScope(tool, action string) string {
+	if readOnlyToolActions[tool][action] {
+		return oauth.ScopeRead
+	}
+	if scope, ok := toolActionScopes[tool][action]; ok {
+		return scope
+	}
+	if scope, ok := toolWriteScopes[tool]; ok {
+		return scope
+	}
+	return oauth.ScopeFull
+}
+
+// scopeToolMiddleware rejects tool calls the caller's OAuth grant does not
+// cover, before the handler runs. Calls without a grant (Basic Auth) pass.
+func scopeToolMiddleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+			access, ok := oauth.AccessFromContext(ctx)
+			if !ok {
+				return next(ctx, request)
+			}
+
+			operation := toolOperation(request)
+			if scope := requiredToolScope(request.Params.Name, operation); !access.Allows(scope) {
+				return mcpg.NewToolResultError(fmt.Sprintf("insufficient scope: %s.%s requires %s", request.Params.Name, operation, scope)), nil
+			}
+			if err := checkToolDevice(ctx, access, request, operation); err != nil {
+				return mcpg.NewToolResultError(err.Error()), nil
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// checkToolDevice enforces a grant's device restriction. whatsapp_device
+// names the administered device in device_id; every other tool acts on
+// device_id or the connection's device.
+func checkToolDevice(ctx context.Context, access oauth.Access, request mcpg.CallToolRequest, operation string) error {
+	if !access.DeviceRestricted() {
+		return nil
+	}
+	deviceID := strings.TrimSpace(request.GetString("device_id", ""))
+	if request.Params.Name == "whatsapp_device" {
+		switch operation {
+		case "list", "health":
+			// The handler filters the result to the allowed devices.
+			return nil
+		case "add":
+			return fmt.Errorf("this authorization is limited to devices %s and cannot add devices", strings.Join(access.Devices, ", "))
+		}
+	} else if deviceID == "" {
+		if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
+			deviceID = inst.ID()
+		}
+	}
+	if deviceID == "" || !access.AllowsDevice(deviceID) {
+		return fmt.Errorf("this authorization is limited to devices %s", strings.Join(access.Devices, ", "))
+	}
+	return nil
+}
+
+// checkReadAccess guards resources and prompts, which read the connection's
+// device.
+func checkReadAccess(ctx context.Context) error {
+	access, ok := oauth.AccessFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if !access.Allows(oauth.ScopeRead) {
+		return fmt.Errorf("insufficient scope: requires %s", oauth.ScopeRead)
+	}
+	if access.DeviceRestricted() {
+		inst, ok := whatsapp.DeviceFromContext(ctx)
+		if !ok || inst == nil || !access.AllowsDevice(inst.ID()) {
+			return fmt.Errorf("this authorization is limited to devices %s", strings.Join(access.Devices, ", "))
+		}
+	}
+	return nil
+}
+
+func scopeResourceMiddleware() server.ResourceHandlerMiddleware {
+	return func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+		return func(ctx context.Context, request mcpg.ReadResourceRequest) ([]mcpg.ResourceContents, error) {
+			if err := checkReadAccess(ctx); err != nil {
+				return nil, err
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+func scopePromptMiddleware() server.PromptHandlerMiddleware {
+	return func(next server.PromptHandlerFunc) server.PromptHandlerFunc {
+		return func(ctx context.Context, request mcpg.GetPromptRequest) (*mcpg.GetPromptResult, error) {
+			if err := checkReadAccess(ctx); err != nil {
+				return nil, err
+			}
+			return next(ctx, request)
+		}
+	}
+}
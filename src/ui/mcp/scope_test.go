@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp/oauth"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredToolScope(t *testing.T) {
+	assert.Equal(t, oauth.ScopeRead, requiredToolScope("whatsapp_chat", "list_chats"))
+	assert.Equal(t, oauth.ScopeSend, requiredToolScope("whatsapp_send", "text"))
+	assert.Equal(t, oauth.ScopeGroupAdmin, requiredToolScope("whatsapp_group", "leave"))
+	assert.Equal(t, oauth.ScopeGroupAdmin, requiredToolScope("whatsapp_newsletter", "create"))
+	assert.Equal(t, oauth.ScopeSend, requiredToolScope("whatsapp_newsletter", "send"))
+	assert.Equal(t, oauth.ScopeDeviceAdmin, requiredToolScope("whatsapp_device", "remove"))
+	assert.Equal(t, oauth.ScopeFull, requiredToolScope("whatsapp_unknown", "anything"))
+}
+
+func TestScopeToolMiddleware(t *testing.T) {
+	called := false
+	handler := scopeToolMiddleware()(func(context.Context, mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+		called = true
+		return mcpg.NewToolResultText("ok"), nil
+	})
+	call := func(ctx context.Context, req mcpg.CallToolRequest) *mcpg.CallToolResult {
+		called = false
+		res, err := handler(ctx, req)
+		require.NoError(t, err)
+		return res
+	}
+	readOnly := oauth.ContextWithAccess(context.Background(), oauth.Access{Scopes: []string{oauth.ScopeRead}})
+
+	t.Run("requests without a grant pass", func(t *testing.T) {
+		res := call(context.Background(), toolReq("whatsapp_group", map[string]any{"action": "leave"}))
+		assert.False(t, res.IsError)
+		assert.True(t, called)
+	})
+
+	t.Run("read scope allows read-only actions", func(t *testing.T) {
+		res := call(readOnly, toolReq("whatsapp_chat", map[string]any{"action": "list_chats"}))
+		assert.False(t, res.IsError)
+		assert.True(t, called)
+	})
+
+	t.Run("read scope refuses mutating actions before the handler", func(t *testing.T) {
+		res := call(readOnly, toolReq("whatsapp_group", map[string]any{"action": "leave"}))
+		assert.True(t, res.IsError)
+		assert.False(t, called)
+		assert.Equal(t, "insufficient scope: whatsapp_group.leave requires mcp:group.admin", toolResultText(res))
+	})
+
+	t.Run("all-access scope allows everything", func(t *testing.T) {
+		ctx := oauth.ContextWithAccess(context.Background(), oauth.Access{Scopes: []string{oauth.ScopeFull}})
+		res := call(ctx, toolReq("whatsapp_device", map[string]any{"action": "remove", "device_id": "d1"}))
+		assert.False(t, res.IsError)
+		assert.True(t, called)
+	})
+
+	restricted := oauth.ContextWithAccess(context.Background(), oauth.Access{
+		Scopes:  []string{oauth.ScopeRead, oauth.ScopeSend, oauth.ScopeDeviceAdmin},
+		Devices: []string{"sales"},
+	})
+
+	t.Run("device restriction checks device_id", func(t *testing.T) {
+		res := call(restricted, toolReq("whatsapp_send", map[string]any{"type": "text", "device_id": "sales"}))
+		assert.False(t, res.IsError)
+
+		res = call(restricted, toolReq("whatsapp_send", map[string]any{"type": "text", "device_id": "support"}))
+		assert.True(t, res.IsError)
+		assert.False(t, called)
+		assert.Equal(t, "this authorization is limited to devices sales", toolResultText(res))
+	})
+
+	t.Run("device restriction checks the connection's device", func(t *testing.T) {
+		ctx := whatsapp.ContextWithDevice(restricted, whatsapp.NewDeviceInstance("support", nil, nil))
+		res := call(ctx, toolReq("whatsapp_send", map[string]any{"type": "text"}))
+		assert.True(t, res.IsError)
+
+		ctx = whatsapp.ContextWithDevice(restricted, whatsapp.NewDeviceInstance("sales", nil, nil))
+		res = call(ctx, toolReq("whatsapp_send", map[string]any{"type": "text"}))
+		assert.False(t, res.IsError)
+	})
+
+	t.Run("restricted grants cannot add devices", func(t *testing.T) {
+		res := call(restricted, toolReq("whatsapp_device", map[string]any{"action": "add", "device_id": "sales"}))
+		assert.True(t, res.IsError)
+		assert.False(t, called)
+
+		res = call(restricted, toolReq("whatsapp_device", map[string]any{"action": "list"}))
+		assert.False(t, res.IsError)
+	})
+}
+
+func TestScopeResourceAndPromptMiddleware(t *testing.T) {
+	sendOnly := oauth.ContextWithAccess(context.Background(), oauth.Access{Scopes: []string{oauth.ScopeSend}})
+
+	resource := scopeResourceMiddleware()(func(context.Context, mcpg.ReadResourceRequest) ([]mcpg.ResourceContents, error) {
+		return nil, nil
+	})
+	_, err := resource(sendOnly, mcpg.ReadResourceRequest{})
+	assert.EqualError(t, err, "insufficient scope: requires mcp:read")
+	_, err = resource(context.Background(), mcpg.ReadResourceRequest{})
+	assert.NoError(t, err)
+
+	prompt := scopePromptMiddleware()(func(context.Context, mcpg.GetPromptRequest) (*mcpg.GetPromptResult, error) {
+		return &mcpg.GetPromptResult{}, nil
+	})
+	_, err = prompt(sendOnly, mcpg.GetPromptRequest{})
+	assert.EqualError(t, err, "insufficient scope: requires mcp:read")
+
+	restricted := oauth.ContextWithAccess(context.Background(), oauth.Access{Scopes: []string{oauth.ScopeRead}, Devices: []string{"sales"}})
+	_, err = prompt(whatsapp.ContextWithDevice(restricted, whatsapp.NewDeviceInstance("support", nil, nil)), mcpg.GetPromptRequest{})
+	assert.Error(t, err)
+	_, err = prompt(whatsapp.ContextWithDevice(restricted, whatsapp.NewDeviceInstance("sales", nil, nil)), mcpg.GetPromptRequest{})
+	assert.NoError(t, err)
+}
+
+func TestHandleDeviceFiltersRestrictedGrant(t *testing.T) {
+	svc := &stubDeviceService{}
+	h := InitMcpDevice(svc)
+	ctx := oauth.ContextWithAccess(context.Background(), oauth.Access{Scopes: []string{oauth.ScopeRead}, Devices: []string{"sales"}})
+
+	res, err := h.handleDevice(ctx, callReq(map[string]any{"action": "list"}))
+	require.NoError(t, err)
+	assert.Equal(t, "Found 0 devices", toolResultText(res))
+
+	health := restrictDevicesHealth(domainDevice.DevicesHealth{
+		Total: 2, Connected: 2, LoggedIn: 1,
+		Devices: []domainDevice.DeviceHealth{
+			{ID: "sales", IsConnected: true, IsLoggedIn: true},
+			{ID: "support", IsConnected: true},
+		},
+	}, oauth.Access{Devices: []string{"sales"}})
+	assert.Equal(t, 1, health.Total)
+	assert.Equal(t, 1, health.Connected)
+	assert.Equal(t, 1, health.LoggedIn)
+	require.Len(t, health.Devices, 1)
+	assert.Equal(t, "sales", health.Devices[0].ID)
+}
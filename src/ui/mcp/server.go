@@ -3,40 +3,73 @@ package mcp
 import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // Deps carries the usecase instances the MCP tools call — the same instances
 // the REST handlers hold, so both surfaces share one whatsmeow session.
 type Deps struct {
-	App     domainApp.IAppUsecase
-	Send    domainSend.ISendUsecase
-	Chat    domainChat.IChatUsecase
-	User    domainUser.IUserUsecase
-	Message domainMessage.IMessageUsecase
-	Group   domainGroup.IGroupUsecase
+	App        domainApp.IAppUsecase
+	Send       domainSend.ISendUsecase
+	Chat       domainChat.IChatUsecase
+	User       domainUser.IUserUsecase
+	Message    domainMessage.IMessageUsecase
+	Group      domainGroup.IGroupUsecase
+	Newsletter domainNewsletter.INewsletterUsecase
+	Device     domainDevice.IDeviceUsecase
+	// Audit, when set, records every mutating tool call in the audit trail.
+	Audit domainAudit.IAuditUsecase
 }
 
-// NewServer builds the MCPServer with the 5 consolidated tools registered.
+// NewServer builds the MCPServer with the 8 consolidated tools, the chat,
+// contact and group resources and the chat prompts registered. Subscribed
+// sessions are notified when a message is stored for a watched chat.
 func NewServer(deps Deps, resolver deviceResolver) *server.MCPServer {
-	s := server.NewMCPServer(
-		"WhatsApp Web Multidevice MCP Server",
-		config.AppVersion,
+	subscriptions := newResourceSubscriptions()
+	opts := []server.ServerOption{
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(true, false),
+		server.WithPromptCapabilities(false),
+		server.WithHooks(subscriptions.hooks()),
 		// Enforce the schemas' allOf/if/then conditionals at the mcp-go
 		// layer, before any handler runs (SEP-1303). Without this,
 		// inputValidator stays nil and the conditionals are advisory only.
 		server.WithInputSchemaValidation(),
+	}
+	if deps.Audit != nil {
+		opts = append(opts, server.WithToolHandlerMiddleware(auditToolMiddleware(deps.Audit)))
+	}
+	// Inside the audit middleware, so calls refused for missing OAuth scopes
+	// are still recorded.
+	opts = append(opts,
+		server.WithToolHandlerMiddleware(scopeToolMiddleware()),
+		server.WithResourceHandlerMiddleware(scopeResourceMiddleware()),
+		server.WithPromptHandlerMiddleware(scopePromptMiddleware()),
+	)
+	s := server.NewMCPServer(
+		"WhatsApp Web Multidevice MCP Server",
+		config.AppVersion,
+		opts...,
 	)
 	InitMcpSend(deps.Send, resolver).AddSendTools(s)
 	InitMcpMessage(deps.Message, resolver).AddMessageTools(s)
 	InitMcpChat(deps.Chat, deps.User, resolver).AddChatTools(s)
 	InitMcpGroup(deps.Group, resolver).AddGroupTools(s)
+	InitMcpNewsletter(deps.Newsletter, deps.User, resolver).AddNewsletterTools(s)
 	InitMcpApp(deps.App, resolver).AddAppTools(s)
+	InitMcpUser(deps.User, resolver).AddUserTools(s)
+	InitMcpDevice(deps.Device).AddDeviceTools(s)
+	InitMcpResource(deps.Chat, deps.User, deps.Group).AddResources(s)
+	InitMcpPrompt(deps.Chat).AddPrompts(s)
+	whatsapp.AddMessageObserver(subscriptions.messageObserver(s))
 	return s
 }
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sync"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
+)
+
+// resourceSubscriptions tracks resources/subscribe requests per MCP session
+// so new messages only notify the sessions watching that chat. mcp-go only
+// acknowledges subscriptions; the bookkeeping happens in its hooks.
+type resourceSubscriptions struct {
+	mu sync.Mutex
+	// session id -> subscribed URI -> device id the session acts on ("" = any)
+	sessions map[string]map[string]string
+}
+
+func newResourceSubscriptions() *resourceSubscriptions {
+	return &resourceSubscriptions{sessions: make(map[string]map[string]string)}
+}
+
+// hooks returns the mcp-go hooks that keep the subscriptions current.
+func (r *resourceSubscriptions) hooks() *server.Hooks {
+	hooks := &server.Hooks{}
+	// mcp-go acknowledges resources/subscribe without consulting the resource
+	// handlers, so the read scope is enforced here: a grant that may not read
+	// the device's resources must not be notified about them either.
+	hooks.AddOnRequestInitialization(func(ctx context.Context, _ any, message any) error {
+		if !isSubscribeRequest(message) {
+			return nil
+		}
+		return checkReadAccess(ctx)
+	})
+	hooks.AddAfterSubscribe(func(ctx context.Context, _ any, message *mcpg.SubscribeRequest, _ *mcpg.EmptyResult) {
+		session := server.ClientSessionFromContext(ctx)
+		if session == nil || session.SessionID() == "" {
+			// Stateless requests have nowhere to deliver notifications.
+			return
+		}
+		deviceID := ""
+		if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
+			deviceID = inst.ID()
+		}
+		r.subscribe(session.SessionID(), message.Params.URI, deviceID)
+	})
+	hooks.AddAfterUnsubscribe(func(ctx context.Context, _ any, message *mcpg.UnsubscribeRequest, _ *mcpg.EmptyResult) {
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			r.unsubscribe(session.SessionID(), message.Params.URI)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.sessions, session.SessionID())
+	})
+	return hooks
+}
+
+// isSubscribeRequest reports whether a raw JSON-RPC message is resources/subscribe.
+func isSubscribeRequest(message any) bool {
+	raw, ok := message.(json.RawMessage)
+	if !ok {
+		return false
+	}
+	var request struct {
+		Method mcpg.MCPMethod `json:"method"`
+	}
+	return json.Unmarshal(raw, &request) == nil && request.Method == mcpg.MethodResourcesSubscribe
+}
+
+func (r *resourceSubscriptions) subscribe(sessionID, uri, deviceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	uris, ok := r.sessions[sessionID]
+	if !ok {
+		uris = make(map[string]string)
+		r.sessions[sessionID] = uris
+	}
+	uris[canonicalResourceURI(uri)] = deviceID
+}
+
+func (r *resourceSubscriptions) unsubscribe(sessionID, uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if uris, ok := r.sessions[sessionID]; ok {
+		delete(uris, canonicalResourceURI(uri))
+		if len(uris) == 0 {
+			delete(r.sessions, sessionID)
+		}
+	}
+}
+
+// subscribers returns the sessions subscribed to uri on deviceID, paired with
+// the URI each should be notified about.
+func (r *resourceSubscriptions) subscribers(deviceID string, uris ...string) map[string][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	matches := make(map[string][]string)
+	for sessionID, subscribed := range r.sessions {
+		for _, uri := range uris {
+			if subscribedDevice, ok := subscribed[uri]; ok && (subscribedDevice == "" || subscribedDevice == deviceID) {
+				matches[sessionID] = append(matches[sessionID], uri)
+			}
+		}
+	}
+	return matches
+}
+
+// messageObserver returns the callback that sends notifications/resources/updated
+// for the chat's message thread and the chat list when a message is stored.
+// Notifications are queued on the session and reach the client with its next
+// request, since the standalone SSE stream is not served (see route.go).
+func (r *resourceSubscriptions) messageObserver(mcpServer *server.MCPServer) whatsapp.MessageObserver {
+	return func(deviceID, chatJID string) {
+		for sessionID, uris := range r.subscribers(deviceID, chatMessagesResourceURI(chatJID), chatsResourceURI) {
+			for _, uri := range uris {
+				if err := mcpServer.SendNotificationToSpecificClient(sessionID, mcpg.MethodNotificationResourceUpdated, map[string]any{"uri": uri}); err != nil {
+					logrus.Debugf("MCP resource notification to session %s failed: %v", sessionID, err)
+				}
+			}
+		}
+	}
+}
+
+// canonicalResourceURI unescapes a subscribed URI so "%40" and "@" in a JID
+// match the same chat.
+func canonicalResourceURI(uri string) string {
+	if unescaped, err := url.PathUnescape(uri); err == nil {
+		return unescaped
+	}
+	return uri
+}
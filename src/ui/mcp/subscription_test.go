@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/mcp/oauth"
+	"github.com/google/uuid"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceSubscriptions(t *testing.T) {
+	r := newResourceSubscriptions()
+	thread := chatMessagesResourceURI("628123@s.whatsapp.net")
+
+	r.subscribe("s1", "whatsapp://chats/628123%40s.whatsapp.net/messages", "dev-a")
+	r.subscribe("s2", chatsResourceURI, "")
+	r.subscribe("s3", thread, "dev-b")
+
+	matches := r.subscribers("dev-a", thread, chatsResourceURI)
+	assert.Equal(t, map[string][]string{
+		"s1": {thread},
+		"s2": {chatsResourceURI},
+	}, matches)
+
+	r.unsubscribe("s1", thread)
+	assert.NotContains(t, r.subscribers("dev-a", thread), "s1")
+	assert.NotContains(t, r.sessions, "s1")
+
+	assert.Equal(t, map[string][]string{"s3": {thread}}, r.subscribers("dev-b", thread))
+}
+
+func TestSubscribeRequiresReadScope(t *testing.T) {
+	r := newResourceSubscriptions()
+	s := server.NewMCPServer("test", "1.0", server.WithResourceCapabilities(true, false), server.WithHooks(r.hooks()))
+	subscribe := json.RawMessage(`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"whatsapp://chats"}}`)
+
+	sendOnly := oauth.ContextWithAccess(deviceCtx(), oauth.Access{Scopes: []string{oauth.ScopeSend}})
+	_, isErr := s.HandleMessage(sendOnly, subscribe).(mcpg.JSONRPCError)
+	assert.True(t, isErr, "a grant without the read scope must not subscribe")
+
+	readOnly := oauth.ContextWithAccess(deviceCtx(), oauth.Access{Scopes: []string{oauth.ScopeRead}})
+	_, isErr = s.HandleMessage(readOnly, subscribe).(mcpg.JSONRPCError)
+	assert.False(t, isErr)
+}
+
+func TestIssuedSessionIdManager(t *testing.T) {
+	m := newIssuedSessionIdManager()
+	issued := m.Generate()
+
+	_, err := m.Validate(issued)
+	require.NoError(t, err)
+	_, err = m.Validate("")
+	require.NoError(t, err, "requests without a session stay stateless")
+	_, err = m.Validate("mcp-session-" + uuid.NewString())
+	require.Error(t, err, "ids the server did not issue are rejected")
+
+	_, err = m.Terminate(issued)
+	require.NoError(t, err)
+	_, err = m.Validate(issued)
+	require.Error(t, err, "terminated sessions cannot be resumed")
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	mcpg "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type UserHandler struct {
+	userService domainUser.IUserUsecase
+	resolver    deviceResolver
+}
+
+func InitMcpUser(userService domainUser.IUserUsecase, resolver deviceResolver) *UserHandler {
+	return &UserHandler{userService: userService, resolver: resolver}
+}
+
+func (h *UserHandler) AddUserTools(mcpServer *server.MCPServer) {
+	tool := mcpg.NewTool("whatsapp_user",
+		mcpg.WithDescription("Look up WhatsApp users: check whether a number is on WhatsApp before messaging it, info (name, status, devices), avatar URL, business_profile, last-known presence, or the device's own privacy settings."),
+		mcpg.WithTitleAnnotation("User Lookup"),
+		mcpg.WithReadOnlyHintAnnotation(true),
+		mcpg.WithDestructiveHintAnnotation(false),
+		mcpg.WithIdempotentHintAnnotation(true),
+		mcpg.WithRawInputSchema(json.RawMessage(userSchema)),
+	)
+	// NewTool defaults InputSchema.Type to "object"; clear it so only
+	// RawInputSchema is set, or MarshalJSON rejects the tool as conflicting.
+	tool.InputSchema = mcpg.ToolInputSchema{}
+	mcpServer.AddTool(tool, h.handleUser)
+}
+
+func (h *UserHandler) handleUser(ctx context.Context, request mcpg.CallToolRequest) (*mcpg.CallToolResult, error) {
+	ctx, _, err := resolveDeviceContext(ctx, request, h.resolver)
+	if err != nil {
+		return mcpg.NewToolResultError(err.Error()), nil
+	}
+
+	action, err := request.RequireString("action")
+	if err != nil {
+		return mcpg.NewToolResultError(err.Error()), nil
+	}
+
+	phone := strings.TrimSpace(request.GetString("phone", ""))
+	switch action {
+	case "check":
+		resp, err := h.userService.IsOnWhatsApp(ctx, domainUser.CheckRequest{Phone: phone})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		if !resp.IsOnWhatsApp {
+			return mcpg.NewToolResultStructured(resp, fmt.Sprintf("%s is not on WhatsApp", phone)), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("%s is on WhatsApp", phone)), nil
+	case "info":
+		resp, err := h.userService.Info(ctx, domainUser.InfoRequest{Phone: phone})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Retrieved info for %s", phone)), nil
+	case "avatar":
+		resp, err := h.userService.Avatar(ctx, domainUser.AvatarRequest{
+			Phone:       phone,
+			IsPreview:   request.GetBool("is_preview", false),
+			IsCommunity: request.GetBool("is_community", false),
+		})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, resp.URL), nil
+	case "business_profile":
+		resp, err := h.userService.BusinessProfile(ctx, domainUser.BusinessProfileRequest{Phone: phone})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("Retrieved business profile for %s", phone)), nil
+	case "presence":
+		resp, err := h.userService.Presence(ctx, domainUser.PresenceRequest{Phone: phone})
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, fmt.Sprintf("%s is %s", resp.JID, resp.Status)), nil
+	case "privacy":
+		resp, err := h.userService.MyPrivacySetting(ctx)
+		if err != nil {
+			return mcpg.NewToolResultError(err.Error()), nil
+		}
+		return mcpg.NewToolResultStructured(resp, "Retrieved privacy settings"), nil
+	default:
+		return mcpg.NewToolResultError(fmt.Sprintf("unknown user action: %s", action)), nil
+	}
+}
@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubUserLookupService struct {
+	domainUser.IUserUsecase
+	checked *domainUser.CheckRequest
+	avatar  *domainUser.AvatarRequest
+	privacy bool
+}
+
+func (s *stubUserLookupService) IsOnWhatsApp(_ context.Context, r domainUser.CheckRequest) (domainUser.CheckResponse, error) {
+	s.checked = &r
+	return domainUser.CheckResponse{IsOnWhatsApp: r.Phone == "628123"}, nil
+}
+func (s *stubUserLookupService) Avatar(_ context.Context, r domainUser.AvatarRequest) (domainUser.AvatarResponse, error) {
+	s.avatar = &r
+	return domainUser.AvatarResponse{URL: "https://pps.whatsapp.net/a.jpg"}, nil
+}
+func (s *stubUserLookupService) MyPrivacySetting(_ context.Context) (domainUser.MyPrivacySettingResponse, error) {
+	s.privacy = true
+	return domainUser.MyPrivacySettingResponse{LastSeen: "contacts"}, nil
+}
+
+func TestHandleUserDispatch(t *testing.T) {
+	t.Run("check trims phone and reports the result", func(t *testing.T) {
+		svc := &stubUserLookupService{}
+		h := InitMcpUser(svc, &stubResolver{})
+		res, err := h.handleUser(deviceCtx(), callReq(map[string]any{"action": "check", "phone": " 628123 "}))
+		require.NoError(t, err)
+		require.NotNil(t, svc.checked)
+		assert.Equal(t, "628123", svc.checked.Phone)
+		assert.Equal(t, "628123 is on WhatsApp", toolResultText(res))
+
+		res, err = h.handleUser(deviceCtx(), callReq(map[string]any{"action": "check", "phone": "629"}))
+		require.NoError(t, err)
+		assert.Equal(t, "629 is not on WhatsApp", toolResultText(res))
+	})
+
+	t.Run("avatar", func(t *testing.T) {
+		svc := &stubUserLookupService{}
+		h := InitMcpUser(svc, &stubResolver{})
+		res, err := h.handleUser(deviceCtx(), callReq(map[string]any{"action": "avatar", "phone": "628", "is_preview": true}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		require.NotNil(t, svc.avatar)
+		assert.True(t, svc.avatar.IsPreview)
+		assert.False(t, svc.avatar.IsCommunity)
+	})
+
+	t.Run("privacy", func(t *testing.T) {
+		svc := &stubUserLookupService{}
+		h := InitMcpUser(svc, &stubResolver{})
+		res, err := h.handleUser(deviceCtx(), callReq(map[string]any{"action": "privacy"}))
+		require.NoError(t, err)
+		assert.False(t, res.IsError)
+		assert.True(t, svc.privacy)
+	})
+
+	t.Run("no device is a tool error", func(t *testing.T) {
+		h := InitMcpUser(&stubUserLookupService{}, &stubResolver{})
+		res, err := h.handleUser(context.Background(), callReq(map[string]any{"action": "check", "phone": "628"}))
+		require.NoError(t, err)
+		assert.True(t, res.IsError)
+	})
+}
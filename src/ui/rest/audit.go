@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v3"
+	"github.com/sirupsen/logrus"
+)
+
+type Audit struct {
+	Service domainAudit.IAuditUsecase
+}
+
+func InitRestAudit(app fiber.Router, service domainAudit.IAuditUsecase) Audit {
+	rest := Audit{Service: service}
+	app.Get("/audit", rest.ListAudit)
+	app.Get("/audit/export", rest.ExportAudit)
+	return rest
+}
+
+func (controller *Audit) ListAudit(c fiber.Ctx) error {
+	var request domainAudit.ListAuditRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListAudit(c.Context(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get audit log",
+		Results: response,
+	})
+}
+
+// ExportAudit streams every entry matching the filters as JSON lines
+// (one entry object per line, newest first). Invalid filters are rejected
+// before anything is sent; a failure mid-stream can only end the body early.
+func (controller *Audit) ExportAudit(c fiber.Ctx) error {
+	var request domainAudit.ListAuditRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	// The body is written after the handler returns, when the request
+	// context may already be recycled.
+	write, err := controller.Service.ExportAudit(context.Background(), request)
+	utils.PanicIfNeeded(err)
+
+	c.Attachment(fmt.Sprintf("audit-%s.jsonl", time.Now().UTC().Format("20060102-150405")))
+	c.Set(fiber.HeaderContentType, "application/x-ndjson; charset=utf-8")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		if err := write(w); err != nil {
+			logrus.Errorf("Audit export ended early: %v", err)
+		}
+	})
+}
@@ -14,6 +14,7 @@ type Call struct {
 func InitRestCall(app fiber.Router, service domainCall.ICallUsecase) Call {
 	rest := Call{Service: service}
 	app.Post("/call/reject", rest.RejectCall)
+	app.Get("/calls", rest.ListCalls)
 	return rest
 }
 
@@ -36,3 +37,19 @@ func (controller *Call) RejectCall(c fiber.Ctx) error {
 		Results: nil,
 	})
 }
+
+func (controller *Call) ListCalls(c fiber.Ctx) error {
+	var request domainCall.ListCallsRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListCalls(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get call history",
+		Results: response,
+	})
+}
@@ -1,8 +1,12 @@
 package rest
 
 import (
+	"context"
+	"io"
 	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
@@ -19,10 +23,13 @@ func InitRestChat(app fiber.Router, service domainChat.IChatUsecase) Chat {
 
 	// Chat endpoints
 	app.Get("/chats", rest.ListChats)
+	app.Get("/chats/export", rest.ExportChats)
 	app.Get("/chat/:chat_jid/messages", rest.GetChatMessages)
+	app.Get("/chat/:chat_jid/export", rest.ExportChat)
 	app.Post("/chat/:chat_jid/pin", rest.PinChat)
 	app.Post("/chat/:chat_jid/disappearing", rest.SetDisappearingTimer)
 	app.Post("/chat/:chat_jid/archive", rest.ArchiveChat)
+	app.Post("/chat/:chat_jid/history", rest.RequestHistory)
 
 	return rest
 }
@@ -205,12 +212,125 @@ func (controller *Chat) ArchiveChat(c fiber.Ctx) error {
 	})
 }
 
+// RequestHistory asks the phone for older messages of a chat.
+func (controller *Chat) RequestHistory(c fiber.Ctx) error {
+	var request domainChat.RequestHistoryRequest
+
+	// Parse JSON body; an empty body requests the defaults
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&request); err != nil {
+			return c.Status(400).JSON(utils.ResponseData{
+				Status:  400,
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request body",
+				Results: nil,
+			})
+		}
+	}
+
+	chatJID, err := chatJIDParam(c)
+	if err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "invalid chat_jid path parameter: " + err.Error(),
+			Results: nil,
+		})
+	}
+	request.ChatJID = chatJID
+
+	response, err := controller.Service.RequestHistory(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "History requested from the phone",
+		Results: response,
+	})
+}
+
+// chatExportTimeout replaces the default request deadline for exports, which
+// walk whole histories and may download every media file.
+const chatExportTimeout = 30 * time.Minute
+
+// ExportChats exports every chat of the device.
+func (controller *Chat) ExportChats(c fiber.Ctx) error {
+	return controller.export(c, "")
+}
+
+// ExportChat exports a single chat.
+func (controller *Chat) ExportChat(c fiber.Ctx) error {
+	chatJID, err := chatJIDParam(c)
+	if err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "invalid chat_jid path parameter: " + err.Error(),
+			Results: nil,
+		})
+	}
+	return controller.export(c, chatJID)
+}
+
+// export spools the document to a temp file so failures still surface as a
+// JSON error response, then streams it as an attachment. The file is removed
+// once the stream is closed.
+func (controller *Chat) export(c fiber.Ctx, chatJID string) error {
+	var request domainChat.ExportChatRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+	request.ChatJID = chatJID
+
+	file, err := os.CreateTemp("", "chat-export-*")
+	utils.PanicIfNeeded(err)
+	spool := &tempFileStream{File: file}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Context()), chatExportTimeout)
+	defer cancel()
+
+	response, err := controller.Service.ExportChat(whatsapp.ContextWithDevice(ctx, getDeviceFromCtx(c)), request, file)
+	if err != nil {
+		spool.Close()
+		utils.PanicIfNeeded(err)
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		spool.Close()
+		utils.PanicIfNeeded(err)
+	}
+
+	c.Attachment(response.Filename)
+	c.Set(fiber.HeaderContentType, response.ContentType)
+	return c.SendStream(spool, int(size))
+}
+
+// tempFileStream deletes its file when the response stream is closed.
+type tempFileStream struct {
+	*os.File
+}
+
+func (f *tempFileStream) Close() error {
+	err := f.File.Close()
+	os.Remove(f.Name())
+	return err
+}
+
 // chatJIDParam returns the chat_jid path parameter with percent-encoding
 // decoded. Fiber does not unescape path params, so URL-encoding clients send
-// "...%40g.us" which would miss every chat-storage lookup. strings.Clone
-// detaches the no-escapes passthrough from fiber's reusable param buffer.
+// "...%40g.us" which would miss every chat-storage lookup.
 func chatJIDParam(c fiber.Ctx) (string, error) {
-	decoded, err := url.PathUnescape(c.Params("chat_jid"))
+	return unescapedParam(c, "chat_jid")
+}
+
+// unescapedParam returns a path parameter with percent-encoding decoded.
+// strings.Clone detaches the no-escapes passthrough from fiber's reusable
+// param buffer.
+func unescapedParam(c fiber.Ctx, key string) (string, error) {
+	decoded, err := url.PathUnescape(c.Params(key))
 	if err != nil {
 		return "", err
 	}
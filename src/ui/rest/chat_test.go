@@ -1,12 +1,16 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http/httptest"
 	"testing"
 
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/middleware"
 	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/require"
 )
@@ -76,3 +80,57 @@ func TestChatJIDParamRejectsMalformedEscape(t *testing.T) {
 	require.Equal(t, "BAD_REQUEST", envelope.Code)
 	require.Contains(t, envelope.Message, "invalid chat_jid path parameter")
 }
+
+type exportChatServiceStub struct {
+	domainChat.IChatUsecase
+	request domainChat.ExportChatRequest
+	err     error
+}
+
+func (s *exportChatServiceStub) ExportChat(_ context.Context, request domainChat.ExportChatRequest, w io.Writer) (domainChat.ExportChatResponse, error) {
+	s.request = request
+	if s.err != nil {
+		return domainChat.ExportChatResponse{}, s.err
+	}
+	_, err := io.WriteString(w, "01/03/2026, 09:30 - Alice: hi\n")
+	return domainChat.ExportChatResponse{Filename: "chat-628123-20260301-093000.txt", ContentType: "text/plain; charset=utf-8"}, err
+}
+
+func TestExportChatStreamsAttachment(t *testing.T) {
+	service := &exportChatServiceStub{}
+	app := fiber.New()
+	app.Use(middleware.Recovery())
+	InitRestChat(app, service)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/chat/628123%40s.whatsapp.net/export?format=txt&include_media=false&timezone=Asia/Jakarta", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/plain; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+	require.Contains(t, resp.Header.Get(fiber.HeaderContentDisposition), "chat-628123-20260301-093000.txt")
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "01/03/2026, 09:30 - Alice: hi\n", string(body))
+	require.Equal(t, domainChat.ExportChatRequest{
+		ChatJID:  "628123@s.whatsapp.net",
+		Format:   "txt",
+		Timezone: "Asia/Jakarta",
+	}, service.request)
+}
+
+func TestExportChatsReportsErrorsAsJSON(t *testing.T) {
+	service := &exportChatServiceStub{err: pkgError.ValidationError("format: must be a valid value.")}
+	app := fiber.New()
+	app.Use(middleware.Recovery())
+	InitRestChat(app, service)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/chats/export?format=pdf&include_media=true", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	require.Empty(t, service.request.ChatJID)
+	require.True(t, service.request.IncludeMedia)
+
+	var envelope utils.ResponseData
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&envelope))
+	require.Equal(t, "VALIDATION_ERROR", envelope.Code)
+}
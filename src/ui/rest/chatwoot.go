@@ -331,6 +331,14 @@ func (h *ChatwootHandler) resolveChatwootForDevice(deviceID string) *chatwoot.Re
 	return rc
 }
 
+// normalizeChatwootPayloadAccount fills the account from the conversation for
+// events without a top-level account, such as typing events.
+func normalizeChatwootPayloadAccount(payload *chatwoot.WebhookPayload) {
+	if payload.Account.ID == 0 {
+		payload.Account.ID = payload.Conversation.AccountID
+	}
+}
+
 // HandleWebhook is the shared (legacy / single-webhook) Chatwoot endpoint. The
 // device is resolved from the payload (conversation link, contact attrs, inbox
 // map, or env fallback).
@@ -346,6 +354,7 @@ func (h *ChatwootHandler) HandleWebhook(c fiber.Ctx) error {
 	if err := c.Bind().Body(&payload); err != nil {
 		return utils.ResponseError(c, "Invalid payload")
 	}
+	normalizeChatwootPayloadAccount(&payload)
 	return h.processChatwootWebhook(c, payload, nil)
 }
 
@@ -364,12 +373,15 @@ func (h *ChatwootHandler) HandleDeviceWebhook(c fiber.Ctx) error {
 	if err := c.Bind().Body(&payload); err != nil {
 		return utils.ResponseError(c, "Invalid payload")
 	}
+	normalizeChatwootPayloadAccount(&payload)
 
-	// Only message events carry the account/inbox fields the route-by-config
-	// check below relies on. Everything else (conversation_*, webhook
-	// verification pings, ...) is ignored by processChatwootWebhook anyway, so
-	// acknowledge it here instead of 401-ing on missing fields.
-	if payload.Event != "message_created" && payload.Event != "message_updated" {
+	// Only message and typing events carry the account/inbox fields the
+	// route-by-config check below relies on. Conversation events are checked
+	// against the inbox by handleChatwootConversationEvent; everything else
+	// (webhook verification pings, ...) is ignored by processChatwootWebhook
+	// anyway, so acknowledge it here instead of 401-ing on missing fields.
+	conversationEvent := isChatwootConversationEvent(payload.Event)
+	if payload.Event != "message_created" && payload.Event != "message_updated" && !conversationEvent && !isChatwootTypingEvent(payload.Event) {
 		return c.SendStatus(fiber.StatusOK)
 	}
 
@@ -392,6 +404,10 @@ func (h *ChatwootHandler) HandleDeviceWebhook(c fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusOK)
 	}
 
+	if conversationEvent {
+		return h.handleChatwootConversationEvent(c, payload, rc)
+	}
+
 	// Route-by-config check: reject payloads whose account/inbox do not match
 	// this device's configured destination.
 	if payload.Account.ID != rc.Client.AccountID || payload.Conversation.InboxID != rc.Client.InboxID {
@@ -417,6 +433,9 @@ func (h *ChatwootHandler) processChatwootWebhook(c fiber.Ctx, payload chatwoot.W
 	logrus.Debugf("Chatwoot Webhook: event=%s message_type=%s contact_id=%d contact_phone=%s",
 		payload.Event, payload.MessageType, contact.ID, contact.PhoneNumber)
 
+	if isChatwootTypingEvent(payload.Event) {
+		return h.handleChatwootTypingEvent(c, payload, forced)
+	}
 	if payload.Event != "message_created" {
 		if payload.Event == "message_updated" && chatwootPayloadDeleted(payload) {
 			return h.handleDeletedChatwootMessage(c, payload, forced)
@@ -15,13 +15,18 @@ import (
 
 // chatwootConfigRequest is the PUT body for a per-device Chatwoot config. An
 // empty api_token on update keeps the stored token (so other fields can be
-// changed without re-sending the secret). enabled defaults to true on create.
+// changed without re-sending the secret). enabled defaults to true on create;
+// the conversation sync options default to off and keep their stored value
+// when omitted on update.
 type chatwootConfigRequest struct {
-	ChatwootURL string `json:"chatwoot_url"`
-	AccountID   int    `json:"account_id"`
-	InboxID     int    `json:"inbox_id"`
-	APIToken    string `json:"api_token"`
-	Enabled     *bool  `json:"enabled"`
+	ChatwootURL      string  `json:"chatwoot_url"`
+	AccountID        int     `json:"account_id"`
+	InboxID          int     `json:"inbox_id"`
+	APIToken         string  `json:"api_token"`
+	Enabled          *bool   `json:"enabled"`
+	LabelSync        *bool   `json:"label_sync"`
+	ArchiveOnResolve *bool   `json:"archive_on_resolve"`
+	ClosingMessage   *string `json:"closing_message"`
 }
 
 // maskAPIToken redacts a stored token for read responses, revealing only the
@@ -52,16 +57,19 @@ func perDeviceWebhookURL(deviceID string) string {
 
 func chatwootConfigView(cfg *domainChatStorage.ChatwootDeviceConfig) map[string]any {
 	return map[string]any{
-		"device_id":    cfg.DeviceID,
-		"device_jid":   cfg.DeviceJID,
-		"chatwoot_url": cfg.ChatwootURL,
-		"account_id":   cfg.AccountID,
-		"inbox_id":     cfg.InboxID,
-		"api_token":    maskAPIToken(cfg.APIToken),
-		"enabled":      cfg.Enabled,
-		"webhook_url":  perDeviceWebhookURL(cfg.DeviceID),
-		"created_at":   cfg.CreatedAt,
-		"updated_at":   cfg.UpdatedAt,
+		"device_id":          cfg.DeviceID,
+		"device_jid":         cfg.DeviceJID,
+		"chatwoot_url":       cfg.ChatwootURL,
+		"account_id":         cfg.AccountID,
+		"inbox_id":           cfg.InboxID,
+		"api_token":          maskAPIToken(cfg.APIToken),
+		"enabled":            cfg.Enabled,
+		"webhook_url":        perDeviceWebhookURL(cfg.DeviceID),
+		"label_sync":         cfg.LabelSync,
+		"archive_on_resolve": cfg.ArchiveOnResolve,
+		"closing_message":    cfg.ClosingMessage,
+		"created_at":         cfg.CreatedAt,
+		"updated_at":         cfg.UpdatedAt,
 	}
 }
 
@@ -179,6 +187,18 @@ func (h *ChatwootHandler) UpsertChatwootConfig(c fiber.Ctx) error {
 	if existing != nil {
 		cfg.ID = existing.ID
 		cfg.CreatedAt = existing.CreatedAt
+		cfg.LabelSync = existing.LabelSync
+		cfg.ArchiveOnResolve = existing.ArchiveOnResolve
+		cfg.ClosingMessage = existing.ClosingMessage
+	}
+	if req.LabelSync != nil {
+		cfg.LabelSync = *req.LabelSync
+	}
+	if req.ArchiveOnResolve != nil {
+		cfg.ArchiveOnResolve = *req.ArchiveOnResolve
+	}
+	if req.ClosingMessage != nil {
+		cfg.ClosingMessage = strings.TrimSpace(*req.ClosingMessage)
 	}
 
 	if err := h.ChatStorageRepo.SaveChatwootDeviceConfig(cfg); err != nil {
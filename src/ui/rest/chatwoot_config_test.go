@@ -269,6 +269,32 @@ func TestChatwootConfigMaskedTokenRoundTrip(t *testing.T) {
 	}
 }
 
+func TestChatwootConfigConversationSyncOptions(t *testing.T) {
+	store := newFakeConfigStore()
+	app := newConfigTestApp(t, store)
+
+	resp, body := doJSON(t, app, http.MethodPut, "/devices/dev/chatwoot/config",
+		`{"chatwoot_url":"https://203.0.113.10","account_id":1,"inbox_id":5,"api_token":"tok","label_sync":true,"archive_on_resolve":true,"closing_message":"  Thanks!  "}`)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("create status = %d body=%s", resp.StatusCode, body)
+	}
+	cfg := store.configs["dev"]
+	if !cfg.LabelSync || !cfg.ArchiveOnResolve || cfg.ClosingMessage != "Thanks!" {
+		t.Fatalf("sync options not saved: %+v", cfg)
+	}
+	if !strings.Contains(body, `"closing_message":"Thanks!"`) || !strings.Contains(body, `"label_sync":true`) {
+		t.Fatalf("sync options missing from response: %s", body)
+	}
+
+	// Omitted options keep their stored values; explicit ones replace them.
+	doJSON(t, app, http.MethodPut, "/devices/dev/chatwoot/config",
+		`{"chatwoot_url":"https://203.0.113.10","account_id":1,"inbox_id":5,"label_sync":false}`)
+	cfg = store.configs["dev"]
+	if cfg.LabelSync || !cfg.ArchiveOnResolve || cfg.ClosingMessage != "Thanks!" {
+		t.Fatalf("update did not keep omitted options: %+v", cfg)
+	}
+}
+
 func TestChatwootConfigRejectsSSRFURL(t *testing.T) {
 	store := newFakeConfigStore()
 	app := newConfigTestApp(t, store)
@@ -0,0 +1,136 @@
+package rest
+
+import (
+	"context"
+	"strings"
+
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/gofiber/fiber/v3"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func isChatwootConversationEvent(event string) bool {
+	return event == "conversation_status_changed" || event == "conversation_updated"
+}
+
+// chatwootConversationSyncEnabled reports whether any conversation sync option
+// is set for the device's Chatwoot config.
+func chatwootConversationSyncEnabled(rc *chatwoot.ResolvedConfig) bool {
+	return rc.LabelSync || rc.ArchiveOnResolve || strings.TrimSpace(rc.ClosingMessage) != ""
+}
+
+// handleChatwootConversationEvent mirrors a conversation's labels and status
+// to its WhatsApp chat. Conversation payloads carry the conversation at the
+// top level and no account, so only the inbox is checked against the device's
+// config; the conversation is then routed like an agent reply.
+func (h *ChatwootHandler) handleChatwootConversationEvent(c fiber.Ctx, payload chatwoot.WebhookPayload, rc *chatwoot.ResolvedConfig) error {
+	if !chatwootConversationSyncEnabled(rc) {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	if payload.InboxID != rc.Client.InboxID {
+		logrus.Warnf("Chatwoot Webhook: conversation %d inbox %d does not match device %q inbox %d; rejecting",
+			payload.ID, payload.InboxID, rc.DeviceID, rc.Client.InboxID)
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+
+	payload.Account.ID = rc.Client.AccountID
+	payload.Conversation = chatwoot.ConversationWebhook{ID: payload.ID, InboxID: payload.InboxID, Meta: payload.Meta}
+	forced := &chatwootWebhookRoute{
+		DeviceID:  rc.DeviceID,
+		ConfigID:  rc.ConfigID,
+		AccountID: rc.Client.AccountID,
+		InboxID:   rc.Client.InboxID,
+	}
+	route := h.resolveChatwootWebhookRoute(payload, forced)
+	route.DeviceID = forced.DeviceID
+	route.ConfigID = forced.ConfigID
+	route.AccountID = forced.AccountID
+	route.InboxID = forced.InboxID
+
+	chatJID := chatwootLinkChatJID(route.Destination)
+	jid, err := types.ParseJID(chatJID)
+	if chatJID == "" || err != nil {
+		logrus.Warnf("Chatwoot Webhook: No WhatsApp chat for conversation %d", payload.ID)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	if h.DeviceManager == nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	instance, resolvedID, err := h.DeviceManager.ResolveDevice(route.DeviceID)
+	if err != nil || instance == nil || instance.GetClient() == nil || !instance.IsLoggedIn() {
+		logrus.Warnf("Chatwoot Webhook: Device %q unavailable for conversation %d; skipping %s", route.DeviceID, payload.ID, payload.Event)
+		return c.SendStatus(fiber.StatusOK)
+	}
+	client := instance.GetClient()
+	storageDeviceID := chatwootStorageDeviceID(instance, resolvedID)
+	ctx := whatsapp.ContextWithDevice(c.Context(), instance)
+
+	switch payload.Event {
+	case "conversation_updated":
+		// An update without a labels field says nothing about the labels;
+		// syncing it would strip every label from the chat.
+		if !rc.LabelSync || payload.Labels == nil {
+			break
+		}
+		if err := whatsapp.ApplyChatwootLabels(ctx, client, h.ChatStorageRepo, storageDeviceID, jid, *payload.Labels); err != nil {
+			logrus.Errorf("Chatwoot Webhook: Failed to sync labels of conversation %d to %s: %v", payload.ID, chatJID, err)
+		}
+	case "conversation_status_changed":
+		switch payload.Status {
+		case "resolved":
+			if closing := strings.TrimSpace(rc.ClosingMessage); closing != "" {
+				h.sendChatwootClosingMessage(ctx, rc, route, storageDeviceID, chatJID, payload.ID, closing)
+			}
+			if rc.ArchiveOnResolve {
+				h.setChatwootChatArchived(ctx, client, storageDeviceID, jid, true)
+			}
+		case "open":
+			if rc.ArchiveOnResolve {
+				h.setChatwootChatArchived(ctx, client, storageDeviceID, jid, false)
+			}
+		}
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func (h *ChatwootHandler) setChatwootChatArchived(ctx context.Context, client *whatsmeow.Client, deviceID string, jid types.JID, archived bool) {
+	if err := whatsapp.SetChatArchived(ctx, client, h.ChatStorageRepo, deviceID, jid, archived); err != nil {
+		logrus.Errorf("Chatwoot Webhook: Failed to set archived=%v on %s: %v", archived, jid, err)
+		return
+	}
+	logrus.Infof("Chatwoot Webhook: Set archived=%v on %s", archived, jid)
+}
+
+// sendChatwootClosingMessage sends the closing message to WhatsApp and posts
+// it to the resolved conversation as well, so agents see it in the
+// transcript. Outgoing messages do not reopen a resolved conversation.
+func (h *ChatwootHandler) sendChatwootClosingMessage(ctx context.Context, rc *chatwoot.ResolvedConfig, route chatwootWebhookRoute, deviceID, chatJID string, conversationID int, message string) {
+	if h.SendUsecase == nil {
+		return
+	}
+	sendDestination, _ := resolveSendDestination(route.Destination)
+	req := domainSend.MessageRequest{Message: message}
+	req.Phone = sendDestination
+	resp, err := h.SendUsecase.SendText(ctx, req)
+	if err != nil {
+		logrus.Errorf("Chatwoot Webhook: Failed to send closing message to %s: %v", sendDestination, err)
+		return
+	}
+
+	sourceID := "WAID:" + resp.MessageID
+	messageID, err := rc.Client.CreateMessage(conversationID, message, "outgoing", nil, chatwoot.MessageOptions{SourceID: sourceID})
+	if err != nil {
+		logrus.Errorf("Chatwoot Webhook: Failed to post closing message to conversation %d: %v", conversationID, err)
+		return
+	}
+	chatwoot.MarkMessageAsSent(rc.Client.AccountID, messageID)
+	h.storeChatwootOutboundLink(route, deviceID, chatJID, chatwoot.WebhookPayload{
+		ID:           messageID,
+		SourceID:     sourceID,
+		Conversation: chatwoot.ConversationWebhook{ID: conversationID, InboxID: rc.Client.InboxID},
+	}, resp.MessageID)
+}
@@ -84,6 +84,93 @@ func TestHandleDeviceWebhookValidatesAccountInbox(t *testing.T) {
 	}
 }
 
+// TestHandleDeviceWebhookConversationEvents covers the gate for conversation
+// events: they are ignored while no sync option is set, and once one is set
+// their inbox must match the device's config (they carry no account).
+func TestHandleDeviceWebhookConversationEvents(t *testing.T) {
+	repo := &deviceWebhookTestRepo{
+		cfg:   &domainChatStorage.ChatwootDeviceConfig{ID: 1, DeviceID: "d", ChatwootURL: "https://chat.example.com", AccountID: 1, InboxID: 2, APIToken: "t", Enabled: true},
+		count: 1,
+	}
+	chatwoot.InitClientRegistry(repo)
+	t.Cleanup(func() { chatwoot.InitClientRegistry(nil) })
+
+	handler := &ChatwootHandler{ChatStorageRepo: repo}
+	app := fiber.New()
+	app.Post("/chatwoot/webhook/:device_id", handler.HandleDeviceWebhook)
+
+	post := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/chatwoot/webhook/d", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	resolved := `{"event":"conversation_status_changed","id":5,"inbox_id":77,"status":"resolved"}`
+	if got := post(resolved); got != fiber.StatusOK {
+		t.Fatalf("sync disabled status = %d, want 200", got)
+	}
+
+	repo.cfg.ArchiveOnResolve = true
+	chatwoot.InitClientRegistry(repo)
+	if got := post(resolved); got != fiber.StatusUnauthorized {
+		t.Fatalf("inbox mismatch status = %d, want 401", got)
+	}
+	// Matching inbox passes the gate; without a device manager nothing is sent.
+	if got := post(`{"event":"conversation_status_changed","id":5,"inbox_id":2,"status":"resolved","meta":{"sender":{"phone_number":"+628111"}}}`); got != fiber.StatusOK {
+		t.Fatalf("matching status = %d, want 200", got)
+	}
+}
+
+// TestHandleDeviceWebhookTypingEvents covers the gate for typing events: their
+// account comes from the conversation, so a mismatch is still rejected, and
+// matching events are acknowledged whether or not they are relayed.
+func TestHandleDeviceWebhookTypingEvents(t *testing.T) {
+	repo := &deviceWebhookTestRepo{
+		cfg:   &domainChatStorage.ChatwootDeviceConfig{ID: 1, DeviceID: "d", ChatwootURL: "https://chat.example.com", AccountID: 1, InboxID: 2, APIToken: "t", Enabled: true},
+		count: 1,
+	}
+	chatwoot.InitClientRegistry(repo)
+	t.Cleanup(func() { chatwoot.InitClientRegistry(nil) })
+
+	original := config.ChatwootTyping
+	config.ChatwootTyping = true
+	t.Cleanup(func() { config.ChatwootTyping = original })
+
+	handler := &ChatwootHandler{ChatStorageRepo: repo}
+	app := fiber.New()
+	app.Post("/chatwoot/webhook/:device_id", handler.HandleDeviceWebhook)
+
+	post := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/chatwoot/webhook/d", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if got := post(`{"event":"conversation_typing_on","user":{"type":"user"},"conversation":{"id":5,"account_id":999,"inbox_id":2}}`); got != fiber.StatusUnauthorized {
+		t.Fatalf("account mismatch status = %d, want 401", got)
+	}
+	for _, body := range []string{
+		// Agent typing; without a device manager nothing is sent.
+		`{"event":"conversation_typing_on","user":{"type":"user"},"conversation":{"id":5,"account_id":1,"inbox_id":2,"meta":{"sender":{"phone_number":"+628111"}}}}`,
+		// Our own relay of the contact's typing.
+		`{"event":"conversation_typing_off","user":{"type":"contact"},"conversation":{"id":5,"account_id":1,"inbox_id":2}}`,
+		// Private note.
+		`{"event":"conversation_typing_on","user":{"type":"user"},"is_private":true,"conversation":{"id":5,"account_id":1,"inbox_id":2}}`,
+	} {
+		if got := post(body); got != fiber.StatusOK {
+			t.Fatalf("status = %d, want 200 for %s", got, body)
+		}
+	}
+}
+
 // TestProcessChatwootWebhookDropsUnroutablePayload proves the fail-fast is
 // enforced at delivery: in per-device mode an unmapped conversation must be
 // acknowledged WITHOUT resolving a device — an empty DeviceID would otherwise
@@ -0,0 +1,53 @@
+package rest
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/chatwoot"
+	"github.com/gofiber/fiber/v3"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func isChatwootTypingEvent(event string) bool {
+	return event == "conversation_typing_on" || event == "conversation_typing_off"
+}
+
+// handleChatwootTypingEvent shows an agent typing in Chatwoot as "typing..." on
+// WhatsApp. Only agents count: the contact's typing that we relay to Chatwoot
+// comes back as a typing event of type "contact", and private notes are never
+// seen by the customer.
+func (h *ChatwootHandler) handleChatwootTypingEvent(c fiber.Ctx, payload chatwoot.WebhookPayload, forced *chatwootWebhookRoute) error {
+	if !config.ChatwootTyping || payload.IsPrivate || payload.User.Type != "user" {
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	route := h.resolveChatwootWebhookRoute(payload, forced)
+	if forced != nil {
+		route.DeviceID = forced.DeviceID
+		route.ConfigID = forced.ConfigID
+		route.AccountID = forced.AccountID
+		route.InboxID = forced.InboxID
+		route.Unroutable = false
+	}
+	if route.Unroutable || h.DeviceManager == nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	chatJID := chatwootLinkChatJID(route.Destination)
+	jid, err := types.ParseJID(chatJID)
+	if chatJID == "" || err != nil {
+		return c.SendStatus(fiber.StatusOK)
+	}
+	instance, _, err := h.DeviceManager.ResolveDevice(route.DeviceID)
+	if err != nil || instance == nil || instance.GetClient() == nil || !instance.IsLoggedIn() {
+		return c.SendStatus(fiber.StatusOK)
+	}
+
+	state := types.ChatPresencePaused
+	if payload.Event == "conversation_typing_on" {
+		state = types.ChatPresenceComposing
+	}
+	if err := instance.GetClient().SendChatPresence(c.Context(), jid, state, types.ChatPresenceMediaText); err != nil {
+		logrus.Debugf("Chatwoot Webhook: Failed to send %s presence to %s: %v", state, chatJID, err)
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
@@ -0,0 +1,101 @@
+package rest
+
+import (
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+type Contact struct {
+	Service domainContact.IContactUsecase
+}
+
+func InitRestContact(app fiber.Router, service domainContact.IContactUsecase) Contact {
+	rest := Contact{Service: service}
+	app.Get("/contacts", rest.ListContacts)
+	// Registered before /contacts/:jid so "sync" is not taken as a JID.
+	app.Post("/contacts/sync", rest.SyncContacts)
+	app.Get("/contacts/:jid", rest.GetContact)
+	app.Patch("/contacts/:jid", rest.UpdateContact)
+	return rest
+}
+
+func (controller *Contact) ListContacts(c fiber.Ctx) error {
+	var request domainContact.ListContactsRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListContacts(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get contacts",
+		Results: response,
+	})
+}
+
+func (controller *Contact) GetContact(c fiber.Ctx) error {
+	jid, err := unescapedParam(c, "jid")
+	if err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "invalid jid path parameter: " + err.Error(),
+			Results: nil,
+		})
+	}
+
+	response, err := controller.Service.GetContact(
+		whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)),
+		domainContact.GetContactRequest{JID: jid},
+	)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get contact",
+		Results: response,
+	})
+}
+
+func (controller *Contact) UpdateContact(c fiber.Ctx) error {
+	var request domainContact.UpdateContactRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	request.JID, err = unescapedParam(c, "jid")
+	if err != nil {
+		return c.Status(400).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "invalid jid path parameter: " + err.Error(),
+			Results: nil,
+		})
+	}
+
+	response, err := controller.Service.UpdateContact(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Contact updated",
+		Results: response,
+	})
+}
+
+func (controller *Contact) SyncContacts(c fiber.Ctx) error {
+	response, err := controller.Service.SyncContacts(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Contacts synced from the WhatsApp address book",
+		Results: response,
+	})
+}
@@ -2,10 +2,16 @@ package rest
 
 import (
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v3"
 )
@@ -19,8 +25,12 @@ func InitRestDevice(app fiber.Router, service device.IDeviceUsecase) Device {
 
 	app.Get("/devices", rest.ListDevices)
 	app.Post("/devices", rest.AddDevice)
+	// Registered before /devices/:device_id so "health" is not taken as an id.
+	app.Get("/devices/health", rest.DevicesHealth)
+	app.Post("/devices/import", rest.ImportDeviceBackup)
 
 	app.Get("/devices/:device_id", rest.GetDevice)
+	app.Patch("/devices/:device_id", rest.UpdateDevice)
 	app.Delete("/devices/:device_id", rest.RemoveDevice)
 
 	app.Get("/devices/:device_id/login", rest.LoginDevice)
@@ -30,12 +40,29 @@ func InitRestDevice(app fiber.Router, service device.IDeviceUsecase) Device {
 	app.Get("/devices/:device_id/status", rest.Status)
 	app.Patch("/devices/:device_id/webhook", rest.UpdateDeviceWebhook)
 	app.Get("/devices/:device_id/webhook", rest.GetDeviceWebhook)
+	app.Patch("/devices/:device_id/call", rest.UpdateDeviceCall)
+	app.Get("/devices/:device_id/call", rest.GetDeviceCall)
+	app.Patch("/devices/:device_id/proxy", rest.UpdateDeviceProxy)
+	app.Get("/devices/:device_id/proxy", rest.GetDeviceProxy)
+	app.Post("/devices/:device_id/backup", rest.ExportDeviceBackup)
 
 	return rest
 }
 
+// ListDevices returns the devices, optionally narrowed by the tag (comma
+// separated, all required), state and search query parameters.
 func (handler *Device) ListDevices(c fiber.Ctx) error {
-	devices, err := handler.Service.ListDevices(c.Context())
+	filter := device.DeviceFilter{
+		State:  device.DeviceState(strings.TrimSpace(c.Query("state"))),
+		Search: c.Query("search"),
+	}
+	for _, tag := range strings.Split(c.Query("tag"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+
+	devices, err := handler.Service.ListDevices(c.Context(), filter)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -59,13 +86,43 @@ func (handler *Device) GetDevice(c fiber.Ctx) error {
 	})
 }
 
+// UpdateDevice handles PATCH /devices/:device_id, setting the device's tags
+// and metadata. Omitted fields keep their value; [] and {} clear them.
+func (handler *Device) UpdateDevice(c fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	var req device.UpdateDeviceRequest
+
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	updated, err := handler.Service.UpdateDevice(c.Context(), deviceID, req)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Device updated",
+		Results: updated,
+	})
+}
+
 func (handler *Device) AddDevice(c fiber.Ctx) error {
 	var req struct {
-		DeviceID                  string `json:"device_id"`
-		WebhookURL                string `json:"webhook_url"`
-		WebhookSecret             string `json:"webhook_secret"`
-		WebhookEvents             string `json:"webhook_events"`
-		WebhookInsecureSkipVerify bool   `json:"webhook_insecure_skip_verify"`
+		DeviceID                  string            `json:"device_id"`
+		WebhookURL                string            `json:"webhook_url"`
+		WebhookSecret             string            `json:"webhook_secret"`
+		WebhookEvents             string            `json:"webhook_events"`
+		WebhookInsecureSkipVerify bool              `json:"webhook_insecure_skip_verify"`
+		WebhookMethod             string            `json:"webhook_method"`
+		WebhookHeaders            map[string]string `json:"webhook_headers"`
+		WebhookTemplate           string            `json:"webhook_template"`
+		WebhookSignatureAlgorithm string            `json:"webhook_signature_algorithm"`
 	}
 
 	if err := c.Bind().Body(&req); err != nil {
@@ -78,12 +135,17 @@ func (handler *Device) AddDevice(c fiber.Ctx) error {
 	}
 
 	var webhook *chatstorage.DeviceWebhookConfig
-	if req.WebhookURL != "" || req.WebhookSecret != "" || req.WebhookEvents != "" || req.WebhookInsecureSkipVerify {
+	if req.WebhookURL != "" || req.WebhookSecret != "" || req.WebhookEvents != "" || req.WebhookInsecureSkipVerify ||
+		req.WebhookMethod != "" || len(req.WebhookHeaders) > 0 || req.WebhookTemplate != "" || req.WebhookSignatureAlgorithm != "" {
 		webhook = &chatstorage.DeviceWebhookConfig{
 			WebhookURL:                &req.WebhookURL,
 			WebhookSecret:             req.WebhookSecret,
 			WebhookEvents:             req.WebhookEvents,
 			WebhookInsecureSkipVerify: req.WebhookInsecureSkipVerify,
+			WebhookMethod:             req.WebhookMethod,
+			WebhookHeaders:            req.WebhookHeaders,
+			WebhookTemplate:           req.WebhookTemplate,
+			WebhookSignatureAlgorithm: req.WebhookSignatureAlgorithm,
 		}
 	}
 
@@ -98,10 +160,11 @@ func (handler *Device) AddDevice(c fiber.Ctx) error {
 		"created_at":   device.CreatedAt,
 	}
 	if webhook != nil {
-		result["webhook_url"] = req.WebhookURL
-		result["webhook_secret"] = req.WebhookSecret
-		result["webhook_events"] = req.WebhookEvents
-		result["webhook_insecure_skip_verify"] = req.WebhookInsecureSkipVerify
+		for key, value := range deviceWebhookResult(device.ID, webhook) {
+			if key != "device_id" {
+				result[key] = value
+			}
+		}
 	}
 
 	return c.JSON(utils.ResponseData{
@@ -201,14 +264,34 @@ func (handler *Device) Status(c fiber.Ctx) error {
 	})
 }
 
-// UpdateDeviceWebhook handles PATCH /devices/:device_id/webhook.
+// DevicesHealth handles GET /devices/health: connection state, last
+// connect/disconnect time, reconnect attempts and last error per device.
+func (handler *Device) DevicesHealth(c fiber.Ctx) error {
+	health, err := handler.Service.GetDevicesHealth(c.Context())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Devices health",
+		Results: health,
+	})
+}
+
+// UpdateDeviceWebhook handles PATCH /devices/:device_id/webhook. The URL,
+// secret, events and TLS setting are replaced; the delivery settings (method,
+// headers, template, signature) only change when present in the body.
 func (handler *Device) UpdateDeviceWebhook(c fiber.Ctx) error {
 	deviceID := c.Params("device_id")
 	var req struct {
-		WebhookURL                *string `json:"webhook_url"`
-		WebhookSecret             string  `json:"webhook_secret"`
-		WebhookEvents             string  `json:"webhook_events"`
-		WebhookInsecureSkipVerify bool    `json:"webhook_insecure_skip_verify"`
+		WebhookURL                *string            `json:"webhook_url"`
+		WebhookSecret             string             `json:"webhook_secret"`
+		WebhookEvents             string             `json:"webhook_events"`
+		WebhookInsecureSkipVerify bool               `json:"webhook_insecure_skip_verify"`
+		WebhookMethod             *string            `json:"webhook_method"`
+		WebhookHeaders            *map[string]string `json:"webhook_headers"`
+		WebhookTemplate           *string            `json:"webhook_template"`
+		WebhookSignatureAlgorithm *string            `json:"webhook_signature_algorithm"`
 	}
 
 	if err := c.Bind().Body(&req); err != nil {
@@ -229,27 +312,41 @@ func (handler *Device) UpdateDeviceWebhook(c fiber.Ctx) error {
 		})
 	}
 
-	config := &chatstorage.DeviceWebhookConfig{
+	webhook := &chatstorage.DeviceWebhookConfig{
 		WebhookURL:                req.WebhookURL,
 		WebhookSecret:             req.WebhookSecret,
 		WebhookEvents:             req.WebhookEvents,
 		WebhookInsecureSkipVerify: req.WebhookInsecureSkipVerify,
 	}
+	current, err := handler.Service.GetDeviceWebhookConfig(c.Context(), deviceID)
+	utils.PanicIfNeeded(err)
+	if current != nil {
+		webhook.WebhookMethod = current.WebhookMethod
+		webhook.WebhookHeaders = current.WebhookHeaders
+		webhook.WebhookTemplate = current.WebhookTemplate
+		webhook.WebhookSignatureAlgorithm = current.WebhookSignatureAlgorithm
+	}
+	if req.WebhookMethod != nil {
+		webhook.WebhookMethod = *req.WebhookMethod
+	}
+	if req.WebhookHeaders != nil {
+		webhook.WebhookHeaders = *req.WebhookHeaders
+	}
+	if req.WebhookTemplate != nil {
+		webhook.WebhookTemplate = *req.WebhookTemplate
+	}
+	if req.WebhookSignatureAlgorithm != nil {
+		webhook.WebhookSignatureAlgorithm = *req.WebhookSignatureAlgorithm
+	}
 
-	err := handler.Service.SetDeviceWebhookConfig(c.Context(), deviceID, config)
+	err = handler.Service.SetDeviceWebhookConfig(c.Context(), deviceID, webhook)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
 		Message: "Device webhook updated",
-		Results: map[string]any{
-			"device_id":                    deviceID,
-			"webhook_url":                  *req.WebhookURL,
-			"webhook_secret":               req.WebhookSecret,
-			"webhook_events":               req.WebhookEvents,
-			"webhook_insecure_skip_verify": req.WebhookInsecureSkipVerify,
-		},
+		Results: deviceWebhookResult(deviceID, webhook),
 	})
 }
 
@@ -259,36 +356,257 @@ func (handler *Device) GetDeviceWebhook(c fiber.Ctx) error {
 	config, err := handler.Service.GetDeviceWebhookConfig(c.Context(), deviceID)
 	utils.PanicIfNeeded(err)
 
-	webhookURL := ""
-	if config != nil && config.WebhookURL != nil {
-		webhookURL = *config.WebhookURL
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Device webhook retrieved",
+		Results: deviceWebhookResult(deviceID, config),
+	})
+}
+
+func deviceWebhookResult(deviceID string, config *chatstorage.DeviceWebhookConfig) map[string]any {
+	result := map[string]any{
+		"device_id":                    deviceID,
+		"webhook_url":                  "",
+		"webhook_secret":               "",
+		"webhook_events":               "",
+		"webhook_insecure_skip_verify": false,
+		"webhook_method":               "POST",
+		"webhook_header_names":         []string{},
+		"webhook_template":             "",
+		"webhook_signature_algorithm":  "sha256",
+	}
+	if config == nil {
+		return result
+	}
+	if config.WebhookURL != nil {
+		result["webhook_url"] = *config.WebhookURL
+	}
+	result["webhook_secret"] = config.WebhookSecret
+	result["webhook_events"] = config.WebhookEvents
+	result["webhook_insecure_skip_verify"] = config.WebhookInsecureSkipVerify
+	if config.WebhookMethod != "" {
+		result["webhook_method"] = config.WebhookMethod
+	}
+	if len(config.WebhookHeaders) > 0 {
+		// Header values usually carry credentials; list the names only.
+		result["webhook_header_names"] = slices.Sorted(maps.Keys(config.WebhookHeaders))
+	}
+	result["webhook_template"] = config.WebhookTemplate
+	if config.WebhookSignatureAlgorithm != "" {
+		result["webhook_signature_algorithm"] = config.WebhookSignatureAlgorithm
+	}
+	return result
+}
+
+// UpdateDeviceCall sets how the device handles incoming calls. An omitted
+// auto_reject or an empty reject_message falls back to the global settings.
+func (handler *Device) UpdateDeviceCall(c fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	var req struct {
+		AutoReject    *bool  `json:"auto_reject"`
+		RejectMessage string `json:"reject_message"`
+	}
+
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	config := &chatstorage.DeviceCallConfig{
+		AutoReject:    req.AutoReject,
+		RejectMessage: strings.TrimSpace(req.RejectMessage),
 	}
 
+	err := handler.Service.SetDeviceCallConfig(c.Context(), deviceID, config)
+	utils.PanicIfNeeded(err)
+
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: "Device webhook retrieved",
+		Message: "Device call handling updated",
+		Results: deviceCallResult(deviceID, config),
+	})
+}
+
+// GetDeviceCall returns the device's call handling settings.
+func (handler *Device) GetDeviceCall(c fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	config, err := handler.Service.GetDeviceCallConfig(c.Context(), deviceID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Device call handling retrieved",
+		Results: deviceCallResult(deviceID, config),
+	})
+}
+
+func deviceCallResult(deviceID string, config *chatstorage.DeviceCallConfig) map[string]any {
+	result := map[string]any{
+		"device_id":      deviceID,
+		"auto_reject":    nil,
+		"reject_message": "",
+	}
+	if config != nil {
+		if config.AutoReject != nil {
+			result["auto_reject"] = *config.AutoReject
+		}
+		result["reject_message"] = config.RejectMessage
+	}
+	return result
+}
+
+// ExportDeviceBackup downloads the device's session and settings encrypted
+// with the given passphrase.
+func (handler *Device) ExportDeviceBackup(c fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	var req struct {
+		Passphrase string `json:"passphrase"`
+	}
+
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	backup, err := handler.Service.ExportDeviceBackup(c.Context(), deviceID, req.Passphrase)
+	utils.PanicIfNeeded(err)
+
+	c.Type("json")
+	c.Attachment(fmt.Sprintf("gowa-backup-%s-%s.json", sanitizeBackupName(deviceID), time.Now().UTC().Format("20060102-150405")))
+	return c.Send(backup)
+}
+
+// ImportDeviceBackup restores an exported device from a multipart upload
+// ("backup" file, "passphrase" and an optional "device_id").
+func (handler *Device) ImportDeviceBackup(c fiber.Ctx) error {
+	file, err := c.FormFile("backup")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "backup file is required",
+			Results: nil,
+		})
+	}
+	src, err := file.Open()
+	utils.PanicIfNeeded(err)
+	defer src.Close()
+	backup, err := io.ReadAll(io.LimitReader(src, maxDeviceBackupSize+1))
+	utils.PanicIfNeeded(err)
+	if len(backup) > maxDeviceBackupSize {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(utils.ResponseData{
+			Status:  413,
+			Code:    "BACKUP_TOO_LARGE",
+			Message: "backup file is too large",
+			Results: nil,
+		})
+	}
+
+	device, err := handler.Service.ImportDeviceBackup(c.Context(), backup, c.FormValue("passphrase"), c.FormValue("device_id"))
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Device restored; reconnect it once the original instance no longer runs it",
 		Results: map[string]any{
-			"device_id":   deviceID,
-			"webhook_url": webhookURL,
-			"webhook_secret": func() string {
-				if config != nil {
-					return config.WebhookSecret
-				}
-				return ""
-			}(),
-			"webhook_events": func() string {
-				if config != nil {
-					return config.WebhookEvents
-				}
-				return ""
-			}(),
-			"webhook_insecure_skip_verify": func() bool {
-				if config != nil {
-					return config.WebhookInsecureSkipVerify
-				}
-				return false
-			}(),
+			"id":           device.ID,
+			"display_name": device.DisplayName,
+			"jid":          device.JID,
+			"state":        device.State,
+			"created_at":   device.CreatedAt,
 		},
 	})
 }
+
+// maxDeviceBackupSize bounds uploaded backups; a session with its keys and
+// contacts stays well below it.
+const maxDeviceBackupSize = 64 << 20
+
+func sanitizeBackupName(deviceID string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, deviceID)
+}
+
+// UpdateDeviceProxy sets the device's outbound proxy and optional fallback
+// pool, and reconnects the device through it. An empty proxy_url and
+// proxy_pool fall back to WHATSAPP_PROXY.
+func (handler *Device) UpdateDeviceProxy(c fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	var req struct {
+		ProxyURL  string   `json:"proxy_url"`
+		ProxyPool []string `json:"proxy_pool"`
+	}
+
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
+			Status:  400,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request body",
+			Results: nil,
+		})
+	}
+
+	config := &chatstorage.DeviceProxyConfig{ProxyURL: strings.TrimSpace(req.ProxyURL)}
+	for _, proxyURL := range req.ProxyPool {
+		if proxyURL = strings.TrimSpace(proxyURL); proxyURL != "" {
+			config.Pool = append(config.Pool, proxyURL)
+		}
+	}
+
+	err := handler.Service.SetDeviceProxyConfig(c.Context(), deviceID, config)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Device proxy updated",
+		Results: deviceProxyResult(deviceID, config),
+	})
+}
+
+// GetDeviceProxy returns the device's proxy settings with credentials masked.
+func (handler *Device) GetDeviceProxy(c fiber.Ctx) error {
+	deviceID := c.Params("device_id")
+	config, err := handler.Service.GetDeviceProxyConfig(c.Context(), deviceID)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Device proxy retrieved",
+		Results: deviceProxyResult(deviceID, config),
+	})
+}
+
+func deviceProxyResult(deviceID string, config *chatstorage.DeviceProxyConfig) map[string]any {
+	result := map[string]any{
+		"device_id":  deviceID,
+		"proxy_url":  "",
+		"proxy_pool": []string{},
+	}
+	if config != nil {
+		result["proxy_url"] = whatsapp.RedactProxyURL(config.ProxyURL)
+		pool := make([]string, 0, len(config.Pool))
+		for _, proxyURL := range config.Pool {
+			pool = append(pool, whatsapp.RedactProxyURL(proxyURL))
+		}
+		result["proxy_pool"] = pool
+	}
+	return result
+}
@@ -2,6 +2,7 @@ package rest
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"fmt"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/gofiber/fiber/v3"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type Group struct {
@@ -28,6 +30,7 @@ func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	app.Post("/group/leave", rest.LeaveGroup)
 	app.Get("/group/participants", rest.ListParticipants)
 	app.Get("/group/participants/export", rest.ExportParticipants)
+	app.Get("/group/participants/history", rest.ListParticipantHistory)
 	app.Post("/group/participants", rest.AddParticipants)
 	app.Post("/group/participants/remove", rest.DeleteParticipants)
 	app.Post("/group/participants/promote", rest.PromoteParticipants)
@@ -40,7 +43,17 @@ func InitRestGroup(app fiber.Router, service domainGroup.IGroupUsecase) Group {
 	app.Post("/group/locked", rest.SetGroupLocked)
 	app.Post("/group/announce", rest.SetGroupAnnounce)
 	app.Post("/group/topic", rest.SetGroupTopic)
+	app.Get("/group/topic/history", rest.ListTopicHistory)
+	app.Post("/group/member-add-mode", rest.SetGroupMemberAddMode)
+	app.Post("/group/join-approval", rest.SetGroupJoinApproval)
+	app.Post("/group/ephemeral", rest.SetGroupEphemeral)
 	app.Get("/group/invite-link", rest.GetGroupInviteLink)
+	app.Post("/group/community", rest.CreateCommunity)
+	app.Get("/group/community/groups", rest.ListCommunityGroups)
+	app.Post("/group/community/link", rest.LinkCommunityGroup)
+	app.Post("/group/community/unlink", rest.UnlinkCommunityGroup)
+	app.Get("/group/community/participants", rest.ListCommunityParticipants)
+	app.Post("/group/community/announce", rest.SendCommunityAnnouncement)
 	return rest
 }
 
@@ -100,6 +113,8 @@ func (controller *Group) CreateGroup(c fiber.Ctx) error {
 	err := c.Bind().Body(&request)
 	utils.PanicIfNeeded(err)
 
+	utils.SanitizePhone(&request.CommunityID)
+
 	groupID, err := controller.Service.CreateGroup(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
 	utils.PanicIfNeeded(err)
 
@@ -139,6 +154,24 @@ func (controller *Group) ListParticipants(c fiber.Ctx) error {
 	})
 }
 
+func (controller *Group) ListParticipantHistory(c fiber.Ctx) error {
+	var request domainGroup.GetGroupParticipantHistoryRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.GetGroupParticipantHistory(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group participant history",
+		Results: response,
+	})
+}
+
 func (controller *Group) ExportParticipants(c fiber.Ctx) error {
 	var request domainGroup.GetGroupParticipantsRequest
 	err := c.Bind().Query(&request)
@@ -408,6 +441,90 @@ func (controller *Group) SetGroupTopic(c fiber.Ctx) error {
 	})
 }
 
+func (controller *Group) ListTopicHistory(c fiber.Ctx) error {
+	var request domainGroup.GetGroupDescriptionHistoryRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.GetGroupDescriptionHistory(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group description history",
+		Results: response,
+	})
+}
+
+func (controller *Group) SetGroupMemberAddMode(c fiber.Ctx) error {
+	var request domainGroup.SetGroupMemberAddModeRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.SetGroupMemberAddMode(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Success allow all members to add participants"
+	if request.Mode == types.GroupMemberAddModeAdmin {
+		message = "Success allow only admins to add participants"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+	})
+}
+
+func (controller *Group) SetGroupJoinApproval(c fiber.Ctx) error {
+	var request domainGroup.SetGroupJoinApprovalRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.SetGroupJoinApproval(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Success disable join approval"
+	if request.Enabled {
+		message = "Success enable join approval"
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+	})
+}
+
+func (controller *Group) SetGroupEphemeral(c fiber.Ctx) error {
+	var request domainGroup.SetGroupEphemeralRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.SetGroupEphemeral(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Success disable disappearing messages"
+	if request.TimerSeconds > 0 {
+		message = fmt.Sprintf("Success set disappearing messages to %d seconds", request.TimerSeconds)
+	}
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+	})
+}
+
 // GroupInfo handles the /group/info endpoint to fetch group information
 func (controller *Group) GroupInfo(c fiber.Ctx) error {
 	var request domainGroup.GroupInfoRequest
@@ -444,3 +561,100 @@ func (controller *Group) GetGroupInviteLink(c fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Group) CreateCommunity(c fiber.Ctx) error {
+	var request domainGroup.CreateCommunityRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	communityID, err := controller.Service.CreateCommunity(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success created community with id %s", communityID),
+		Results: map[string]string{
+			"community_id": communityID,
+		},
+	})
+}
+
+func (controller *Group) ListCommunityGroups(c fiber.Ctx) error {
+	var request domainGroup.GetCommunityGroupsRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+
+	response, err := controller.Service.GetCommunityGroups(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get community groups",
+		Results: response,
+	})
+}
+
+func (controller *Group) LinkCommunityGroup(c fiber.Ctx) error {
+	return controller.changeCommunityLink(c, controller.Service.LinkGroup, "Success link group to community")
+}
+
+func (controller *Group) UnlinkCommunityGroup(c fiber.Ctx) error {
+	return controller.changeCommunityLink(c, controller.Service.UnlinkGroup, "Success unlink group from community")
+}
+
+// Generalized community link/unlink handler
+func (controller *Group) changeCommunityLink(c fiber.Ctx, change func(context.Context, domainGroup.CommunityGroupRequest) error, successMsg string) error {
+	var request domainGroup.CommunityGroupRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+	utils.SanitizePhone(&request.CommunityID)
+	utils.SanitizePhone(&request.GroupID)
+	err = change(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: successMsg,
+		Results: request,
+	})
+}
+
+func (controller *Group) ListCommunityParticipants(c fiber.Ctx) error {
+	var request domainGroup.GetCommunityParticipantsRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+
+	response, err := controller.Service.GetCommunityParticipants(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success getting community participants",
+		Results: response,
+	})
+}
+
+func (controller *Group) SendCommunityAnnouncement(c fiber.Ctx) error {
+	var request domainGroup.CommunityAnnouncementRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.CommunityID)
+
+	response, err := controller.Service.SendCommunityAnnouncement(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
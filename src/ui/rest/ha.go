@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v3"
+)
+
+type HA struct {
+	Coordinator *whatsapp.LeaseCoordinator
+}
+
+// InitRestHA exposes the device lease table of a high-availability cluster.
+func InitRestHA(app fiber.Router, coordinator *whatsapp.LeaseCoordinator) HA {
+	rest := HA{Coordinator: coordinator}
+	app.Get("/ha/status", rest.Status)
+	return rest
+}
+
+func (handler *HA) Status(c fiber.Ctx) error {
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Fetch HA status success",
+		Results: handler.Coordinator.Status(),
+	})
+}
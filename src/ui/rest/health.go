@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// healthCheckTimeout bounds each readiness check so one hung dependency
+// cannot stall the probe past the kubelet's own timeout.
+const healthCheckTimeout = 3 * time.Second
+
+// HealthCheck is a named dependency probe run by /readyz.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type Health struct {
+	Checks []HealthCheck
+}
+
+// InitRestHealth registers the unauthenticated Kubernetes probes. /healthz
+// only reports that the process is serving requests; /readyz runs every check
+// and answers 503 when any of them fails.
+func InitRestHealth(app fiber.Router, checks ...HealthCheck) Health {
+	rest := Health{Checks: checks}
+	app.Get("/healthz", rest.Liveness)
+	app.Get("/readyz", rest.Readiness)
+	return rest
+}
+
+func (handler *Health) Liveness(c fiber.Ctx) error {
+	return c.SendString("OK")
+}
+
+func (handler *Health) Readiness(c fiber.Ctx) error {
+	results := runHealthChecks(c.Context(), handler.Checks)
+
+	ready := true
+	for _, result := range results {
+		if result != "ok" {
+			ready = false
+			break
+		}
+	}
+
+	if !ready {
+		return c.Status(http.StatusServiceUnavailable).JSON(utils.ResponseData{
+			Status:  http.StatusServiceUnavailable,
+			Code:    "NOT_READY",
+			Message: "One or more dependencies are unavailable",
+			Results: results,
+		})
+	}
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "READY",
+		Message: "All dependencies are reachable",
+		Results: results,
+	})
+}
+
+// runHealthChecks runs the checks concurrently and maps each name to "ok" or
+// "error". The probe is unauthenticated, so failures are only detailed in the
+// server log.
+func runHealthChecks(ctx context.Context, checks []HealthCheck) map[string]string {
+	results := make(map[string]string, len(checks))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+			status := "ok"
+			if err := runHealthCheck(ctx, check); err != nil {
+				logrus.WithError(err).Warnf("Readiness check %s failed", check.Name)
+				status = "error"
+			}
+			mu.Lock()
+			results[check.Name] = status
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+	return results
+}
+
+// runHealthCheck enforces healthCheckTimeout even for checks that ignore ctx.
+func runHealthCheck(ctx context.Context, check HealthCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- check.Check(ctx) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,75 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeReadiness(t *testing.T, app *fiber.App) (int, string, map[string]string) {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	require.NoError(t, err)
+	var payload struct {
+		Code    string            `json:"code"`
+		Results map[string]string `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	return resp.StatusCode, payload.Code, payload.Results
+}
+
+func TestHealthz(t *testing.T) {
+	app := fiber.New()
+	InitRestHealth(app, HealthCheck{Name: "db", Check: func(context.Context) error { return errors.New("down") }})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/healthz", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode, "liveness must not depend on readiness checks")
+}
+
+func TestReadyz(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+
+	t.Run("all checks pass", func(t *testing.T) {
+		app := fiber.New()
+		InitRestHealth(app, HealthCheck{Name: "whatsapp_store", Check: ok}, HealthCheck{Name: "chat_storage", Check: ok})
+
+		status, code, results := decodeReadiness(t, app)
+		assert.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "READY", code)
+		assert.Equal(t, map[string]string{"whatsapp_store": "ok", "chat_storage": "ok"}, results)
+	})
+
+	t.Run("failing check returns 503 without its error", func(t *testing.T) {
+		app := fiber.New()
+		InitRestHealth(app,
+			HealthCheck{Name: "whatsapp_store", Check: ok},
+			HealthCheck{Name: "chat_storage", Check: func(context.Context) error { return errors.New("database is locked") }},
+		)
+
+		status, code, results := decodeReadiness(t, app)
+		assert.Equal(t, fiber.StatusServiceUnavailable, status)
+		assert.Equal(t, "NOT_READY", code)
+		assert.Equal(t, "ok", results["whatsapp_store"])
+		assert.Equal(t, "error", results["chat_storage"], "the error text is only logged")
+	})
+}
+
+func TestRunHealthCheckTimesOutChecksIgnoringContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	block := make(chan struct{})
+	defer close(block)
+	err := runHealthCheck(ctx, HealthCheck{Name: "hung", Check: func(context.Context) error {
+		<-block
+		return nil
+	}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/basicauth"
+	"github.com/sirupsen/logrus"
+)
+
+// auditTargetFields are the request fields, in priority order, that name the
+// chat/user/group a call acts on.
+var auditTargetFields = []string{"phone", "group_id", "community_id", "chat_jid", "newsletter_id", "jid", "caller_jid"}
+
+// Principal copies the basic-auth user (when the request was authenticated
+// that way) onto the request context, so audit records and MCP tool calls can
+// attribute the action. A principal already set upstream (MCP OAuth) wins.
+func Principal() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if _, ok := domainAudit.PrincipalFromContext(c.Context()); !ok {
+			if username := basicauth.UsernameFromContext(c); username != "" {
+				c.SetContext(domainAudit.ContextWithPrincipal(c.Context(), domainAudit.Principal{
+					Type:    domainAudit.PrincipalBasic,
+					Subject: username,
+				}))
+			}
+		}
+		return c.Next()
+	}
+}
+
+// Audit appends an audit entry for every mutating request (see
+// mutatingRequest) that matched a route, once the handler has finished.
+// Requests that reach no route (404s for unknown paths) are not recorded.
+// Requests for which skip returns true are passed through untouched; the MCP
+// endpoint uses this because it audits per tool call instead. Panics are
+// recorded as errors and re-raised so Recovery still renders the response.
+func Audit(service domainAudit.IAuditUsecase, skip func(c fiber.Ctx) bool) fiber.Handler {
+	return func(c fiber.Ctx) (err error) {
+		if !mutatingRequest(c) || (skip != nil && skip(c)) {
+			return c.Next()
+		}
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				status, message := statusFromPanic(recovered)
+				recordRESTAudit(c, service, status, message)
+				panic(recovered)
+			}
+			if !c.Matched() {
+				return
+			}
+			status := c.Response().StatusCode()
+			message := ""
+			if err != nil {
+				status = statusFromError(err)
+				message = err.Error()
+			}
+			recordRESTAudit(c, service, status, message)
+		}()
+
+		return c.Next()
+	}
+}
+
+// mutatingRequest reports whether a request can change state: anything other
+// than GET/HEAD/OPTIONS, plus GET /group/invite-link?reset=true, which revokes
+// the group's current invite link.
+func mutatingRequest(c fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodHead, fiber.MethodOptions:
+		return false
+	case fiber.MethodGet:
+		return strings.HasSuffix(c.Path(), "/group/invite-link") && fiber.Query[bool](c, "reset", false)
+	}
+	return true
+}
+
+func recordRESTAudit(c fiber.Ctx, service domainAudit.IAuditUsecase, status int, errorMessage string) {
+	response := map[string]any{}
+	_ = json.Unmarshal(c.Response().Body(), &response)
+
+	result := domainAudit.ResultSuccess
+	if status >= http.StatusBadRequest {
+		result = domainAudit.ResultError
+		if errorMessage == "" {
+			errorMessage, _ = response["message"].(string)
+		}
+	}
+
+	principal, _ := domainAudit.PrincipalFromContext(c.Context())
+	body := auditRequestBody(c)
+
+	entry := domainAudit.RecordRequest{
+		Source:       domainAudit.SourceREST,
+		Principal:    principal,
+		DeviceID:     auditDeviceID(c),
+		Action:       fmt.Sprintf("%s %s", c.Method(), c.Route().Path),
+		TargetJID:    auditTarget(c, body),
+		MessageID:    auditMessageID(c, body, response),
+		Result:       result,
+		StatusCode:   status,
+		ErrorMessage: errorMessage,
+	}
+	// The request context may already be cancelled (timeouts); the entry
+	// must be written regardless.
+	if err := service.Record(context.WithoutCancel(c.Context()), entry); err != nil {
+		logrus.Warnf("Failed to record audit entry for %s: %v", entry.Action, err)
+	}
+}
+
+// auditRequestBody decodes a JSON body into a map. It returns nil for
+// multipart/form bodies, which are read field by field through FormValue.
+func auditRequestBody(c fiber.Ctx) map[string]any {
+	if !isJSONRequest(c) {
+		return nil
+	}
+	body := map[string]any{}
+	_ = json.Unmarshal(c.Body(), &body)
+	return body
+}
+
+func isJSONRequest(c fiber.Ctx) bool {
+	return strings.HasPrefix(strings.ToLower(c.Get(fiber.HeaderContentType)), fiber.MIMEApplicationJSON)
+}
+
+func auditField(c fiber.Ctx, body map[string]any, field string) string {
+	if value := strings.TrimSpace(c.Params(field)); value != "" {
+		return value
+	}
+	if body == nil {
+		return strings.TrimSpace(c.FormValue(field))
+	}
+	if value, ok := body[field].(string); ok {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
+
+func auditTarget(c fiber.Ctx, body map[string]any) string {
+	for _, field := range auditTargetFields {
+		if value := auditField(c, body, field); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func auditMessageID(c fiber.Ctx, body, response map[string]any) string {
+	if id := auditField(c, body, "message_id"); id != "" {
+		return id
+	}
+	// Sends return the new message id in the results envelope.
+	if results, ok := response["results"].(map[string]any); ok {
+		if id, ok := results["message_id"].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+func auditDeviceID(c fiber.Ctx) string {
+	if inst, ok := whatsapp.DeviceFromContext(c.Context()); ok && inst != nil {
+		return inst.ID()
+	}
+	if id := strings.TrimSpace(c.Params("device_id")); id != "" {
+		return id
+	}
+	return strings.TrimSpace(c.Get(DeviceIDHeader))
+}
+
+func statusFromPanic(recovered any) (int, string) {
+	err, ok := recovered.(error)
+	if !ok {
+		return http.StatusInternalServerError, fmt.Sprintf("%v", recovered)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, err.Error()
+	}
+	var genericErr pkgError.GenericError
+	if errors.As(err, &genericErr) {
+		return genericErr.StatusCode(), genericErr.Error()
+	}
+	return http.StatusInternalServerError, err.Error()
+}
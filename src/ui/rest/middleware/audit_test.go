@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAuditService struct {
+	domainAudit.IAuditUsecase
+	recorded []domainAudit.RecordRequest
+}
+
+func (s *stubAuditService) Record(_ context.Context, request domainAudit.RecordRequest) error {
+	s.recorded = append(s.recorded, request)
+	return nil
+}
+
+func newAuditTestApp(service *stubAuditService) *fiber.App {
+	app := fiber.New()
+	app.Use(Recovery())
+	app.Use(func(c fiber.Ctx) error {
+		c.SetContext(domainAudit.ContextWithPrincipal(c.Context(), domainAudit.Principal{
+			Type:    domainAudit.PrincipalBasic,
+			Subject: "admin",
+		}))
+		return c.Next()
+	})
+	app.Use(Audit(service, func(c fiber.Ctx) bool { return c.Path() == "/mcp" }))
+	return app
+}
+
+func TestAudit_RecordsSuccessfulMutation(t *testing.T) {
+	service := &stubAuditService{}
+	app := newAuditTestApp(service)
+	app.Post("/send/message", func(c fiber.Ctx) error {
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Results: map[string]any{"message_id": "3EB0ABC"},
+		})
+	})
+
+	req := httptest.NewRequest("POST", "/send/message", strings.NewReader(`{"phone":"628123@s.whatsapp.net","message":"hi"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeviceIDHeader, "dev-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	require.Len(t, service.recorded, 1)
+	entry := service.recorded[0]
+	assert.Equal(t, domainAudit.SourceREST, entry.Source)
+	assert.Equal(t, "POST /send/message", entry.Action)
+	assert.Equal(t, "admin", entry.Principal.Subject)
+	assert.Equal(t, domainAudit.PrincipalBasic, entry.Principal.Type)
+	assert.Equal(t, "dev-1", entry.DeviceID)
+	assert.Equal(t, "628123@s.whatsapp.net", entry.TargetJID)
+	assert.Equal(t, "3EB0ABC", entry.MessageID)
+	assert.Equal(t, domainAudit.ResultSuccess, entry.Result)
+	assert.Equal(t, 200, entry.StatusCode)
+}
+
+func TestAudit_RecordsPanicAsErrorAndRethrows(t *testing.T) {
+	service := &stubAuditService{}
+	app := newAuditTestApp(service)
+	app.Post("/message/:message_id/revoke", func(c fiber.Ctx) error {
+		panic(pkgError.ValidationError("phone: cannot be blank."))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/message/ABC/revoke", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode, "Recovery should still render the error")
+
+	require.Len(t, service.recorded, 1)
+	entry := service.recorded[0]
+	assert.Equal(t, "POST /message/:message_id/revoke", entry.Action)
+	assert.Equal(t, "ABC", entry.MessageID)
+	assert.Equal(t, domainAudit.ResultError, entry.Result)
+	assert.Equal(t, 400, entry.StatusCode)
+	assert.Equal(t, "phone: cannot be blank.", entry.ErrorMessage)
+}
+
+func TestAudit_SkipsReadsAndSkippedPaths(t *testing.T) {
+	service := &stubAuditService{}
+	app := newAuditTestApp(service)
+	app.Get("/user/info", func(c fiber.Ctx) error { return c.SendString("ok") })
+	app.Post("/mcp", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+	_, err := app.Test(httptest.NewRequest("GET", "/user/info", nil))
+	require.NoError(t, err)
+	_, err = app.Test(httptest.NewRequest("POST", "/mcp", nil))
+	require.NoError(t, err)
+
+	assert.Empty(t, service.recorded)
+}
+
+func TestAudit_RecordsInviteLinkReset(t *testing.T) {
+	service := &stubAuditService{}
+	app := newAuditTestApp(service)
+	app.Get("/group/invite-link", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+	_, err := app.Test(httptest.NewRequest("GET", "/group/invite-link?group_id=120363@g.us", nil))
+	require.NoError(t, err)
+	assert.Empty(t, service.recorded, "reading the link is not audited")
+
+	_, err = app.Test(httptest.NewRequest("GET", "/group/invite-link?group_id=120363@g.us&reset=true", nil))
+	require.NoError(t, err)
+	require.Len(t, service.recorded, 1)
+	assert.Equal(t, "GET /group/invite-link", service.recorded[0].Action)
+	assert.Equal(t, "120363@g.us", service.recorded[0].TargetJID)
+}
+
+func TestAudit_SkipsUnmatchedRoutes(t *testing.T) {
+	service := &stubAuditService{}
+	app := newAuditTestApp(service)
+	app.Post("/send/message", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/send/unknown", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	assert.Empty(t, service.recorded)
+}
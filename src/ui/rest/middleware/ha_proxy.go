@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/contrib/v3/websocket"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/proxy"
+	"github.com/sirupsen/logrus"
+)
+
+// HAForwardedHeader marks a request one replica forwarded to another. The
+// receiving replica always serves it locally, so a stale lease view on either
+// side cannot bounce a request back and forth.
+const HAForwardedHeader = "X-Gowa-Forwarded-By"
+
+// DeviceOwnerResolver reports which replica serves a device in HA mode.
+type DeviceOwnerResolver interface {
+	ReplicaID() string
+	RemoteOwner(deviceID string) (ownerURL string, ok bool)
+}
+
+// HAProxy forwards requests naming a device (X-Device-Id, ?device_id= or
+// /devices/:device_id) to the replica that holds the device's lease. It runs
+// before authentication bookkeeping so the owner authenticates and audits the
+// call exactly once; requests for local or unleased devices pass through.
+func HAProxy(resolver DeviceOwnerResolver) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if c.Get(HAForwardedHeader) != "" || websocket.IsWebSocketUpgrade(c) {
+			return c.Next()
+		}
+
+		deviceID := requestDeviceID(c)
+		if deviceID == "" {
+			return c.Next()
+		}
+		ownerURL, ok := resolver.RemoteOwner(deviceID)
+		if !ok {
+			return c.Next()
+		}
+
+		c.Request().Header.Set(HAForwardedHeader, resolver.ReplicaID())
+		if err := proxy.Do(c, ownerURL+c.OriginalURL()); err != nil {
+			logrus.WithError(err).Warnf("[HA] forwarding device %s to %s failed", deviceID, ownerURL)
+			return c.Status(fiber.StatusBadGateway).JSON(utils.ResponseData{
+				Status:  fiber.StatusBadGateway,
+				Code:    "DEVICE_OWNER_UNREACHABLE",
+				Message: "the replica serving this device cannot be reached; retry once its lease fails over",
+				Results: map[string]string{"device_id": deviceID},
+			})
+		}
+		return nil
+	}
+}
+
+// requestDeviceID returns the device a request targets, using the same sources
+// as DeviceMiddleware plus the /devices/:device_id path of device management.
+func requestDeviceID(c fiber.Ctx) string {
+	deviceID := strings.TrimSpace(c.Get(DeviceIDHeader))
+	if decoded, err := url.QueryUnescape(deviceID); err == nil {
+		deviceID = decoded
+	}
+	if deviceID == "" {
+		deviceID = strings.TrimSpace(c.Query("device_id"))
+	}
+	if deviceID != "" {
+		return deviceID
+	}
+
+	rest, ok := strings.CutPrefix(c.Path(), config.AppBasePath+"/devices/")
+	if !ok {
+		return ""
+	}
+	segment, _, _ := strings.Cut(rest, "/")
+	if segment == "health" || segment == "import" {
+		return ""
+	}
+	if decoded, err := url.PathUnescape(segment); err == nil {
+		segment = decoded
+	}
+	return strings.TrimSpace(segment)
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"io"
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubOwnerResolver struct {
+	owners map[string]string
+}
+
+func (s stubOwnerResolver) ReplicaID() string { return "replica-a" }
+
+func (s stubOwnerResolver) RemoteOwner(deviceID string) (string, bool) {
+	url, ok := s.owners[deviceID]
+	return url, ok
+}
+
+// startOwnerReplica serves a fake owner replica that echoes where a request
+// landed and who forwarded it.
+func startOwnerReplica(t *testing.T) string {
+	t.Helper()
+
+	owner := fiber.New()
+	owner.All("/*", func(c fiber.Ctx) error {
+		return c.SendString("owner " + c.Method() + " " + c.OriginalURL() + " from " + c.Get(HAForwardedHeader))
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = owner.Listener(ln, fiber.ListenConfig{DisableStartupMessage: true}) }()
+	t.Cleanup(func() { _ = owner.Shutdown() })
+	return "http://" + ln.Addr().String()
+}
+
+func newHAProxyTestApp(resolver DeviceOwnerResolver) *fiber.App {
+	app := fiber.New()
+	app.Use(HAProxy(resolver))
+	app.All("/*", func(c fiber.Ctx) error {
+		return c.SendString("local")
+	})
+	return app
+}
+
+func haProxyBody(t *testing.T, app *fiber.App, method, target string, headers map[string]string) string {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestHAProxy_ForwardsRemoteDevicesOnly(t *testing.T) {
+	ownerURL := startOwnerReplica(t)
+	app := newHAProxyTestApp(stubOwnerResolver{owners: map[string]string{"sales": ownerURL}})
+
+	assert.Equal(t, "owner POST /send/message?x=1 from replica-a",
+		haProxyBody(t, app, fiber.MethodPost, "/send/message?x=1", map[string]string{DeviceIDHeader: "sales"}))
+	assert.Equal(t, "owner GET /user/info?device_id=sales from replica-a",
+		haProxyBody(t, app, fiber.MethodGet, "/user/info?device_id=sales", nil))
+	assert.Equal(t, "owner POST /devices/sales/reconnect from replica-a",
+		haProxyBody(t, app, fiber.MethodPost, "/devices/sales/reconnect", nil))
+
+	assert.Equal(t, "local", haProxyBody(t, app, fiber.MethodPost, "/send/message", map[string]string{DeviceIDHeader: "support"}))
+	assert.Equal(t, "local", haProxyBody(t, app, fiber.MethodGet, "/devices", nil))
+	assert.Equal(t, "local", haProxyBody(t, app, fiber.MethodGet, "/devices/health", nil))
+}
+
+func TestHAProxy_ServesForwardedRequestsLocally(t *testing.T) {
+	app := newHAProxyTestApp(stubOwnerResolver{owners: map[string]string{"sales": "http://127.0.0.1:1"}})
+
+	assert.Equal(t, "local", haProxyBody(t, app, fiber.MethodPost, "/send/message", map[string]string{
+		DeviceIDHeader:    "sales",
+		HAForwardedHeader: "replica-b",
+	}))
+}
+
+func TestHAProxy_UnreachableOwnerReturnsBadGateway(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	deadURL := "http://" + ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	app := newHAProxyTestApp(stubOwnerResolver{owners: map[string]string{"sales": deadURL}})
+	req := httptest.NewRequest(fiber.MethodGet, "/user/info", nil)
+	req.Header.Set(DeviceIDHeader, "sales")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadGateway, resp.StatusCode)
+}
+
+func TestHAProxy_ForwardedRequestsAreLeftToTheOwnerAudit(t *testing.T) {
+	ownerURL := startOwnerReplica(t)
+	service := &stubAuditService{}
+	app := fiber.New()
+	app.Use(Audit(service, nil))
+	app.Use(HAProxy(stubOwnerResolver{owners: map[string]string{"sales": ownerURL}}))
+	app.All("/*", func(c fiber.Ctx) error {
+		return c.SendString("local")
+	})
+
+	haProxyBody(t, app, fiber.MethodPost, "/send/message", map[string]string{DeviceIDHeader: "sales"})
+	assert.Empty(t, service.recorded)
+
+	haProxyBody(t, app, fiber.MethodPost, "/send/message", map[string]string{DeviceIDHeader: "support"})
+	assert.Len(t, service.recorded, 1)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fiberHeaderCarrier adapts the Fiber request headers to a TextMapCarrier so
+// an incoming traceparent continues the caller's trace.
+type fiberHeaderCarrier struct {
+	c fiber.Ctx
+}
+
+func (h fiberHeaderCarrier) Get(key string) string { return h.c.Get(key) }
+
+func (h fiberHeaderCarrier) Set(key, value string) { h.c.Request().Header.Set(key, value) }
+
+func (h fiberHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(h.c.GetReqHeaders()))
+	for k := range h.c.GetReqHeaders() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Tracing opens a server span for every request and stores it on the request
+// context, so usecases and WhatsApp sends invoked by the handler nest beneath
+// it. The span is renamed to the matched route once the handler has run.
+func Tracing() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.Context(), fiberHeaderCarrier{c: c})
+		ctx, span := telemetry.Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+			),
+		)
+		defer span.End()
+		c.SetContext(ctx)
+
+		err := c.Next()
+
+		route := c.Route().Path
+		span.SetName(fmt.Sprintf("%s %s", c.Method(), route))
+		span.SetAttributes(semconv.HTTPRoute(route))
+		if inst, ok := whatsapp.DeviceFromContext(c.Context()); ok && inst != nil {
+			span.SetAttributes(telemetry.AttrDeviceID.String(inst.ID()))
+		}
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = statusFromError(err)
+			span.RecordError(err)
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		return err
+	}
+}
+
+func statusFromError(err error) int {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	var genericErr pkgError.GenericError
+	if errors.As(err, &genericErr) {
+		return genericErr.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
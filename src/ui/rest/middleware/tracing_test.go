@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func installTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	telemetry.Install(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func TestTracing_CreatesServerSpanNamedByRoute(t *testing.T) {
+	exporter := installTestTracer(t)
+
+	app := fiber.New()
+	app.Use(Tracing())
+	var handlerSpan trace.SpanContext
+	app.Get("/user/:phone", func(c fiber.Ctx) error {
+		handlerSpan = trace.SpanContextFromContext(c.Context())
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/user/628", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /user/:phone", spans[0].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	assert.Equal(t, spans[0].SpanContext.SpanID(), handlerSpan.SpanID(), "handler context should carry the server span")
+}
+
+func TestTracing_ContinuesIncomingTraceparent(t *testing.T) {
+	exporter := installTestTracer(t)
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Get("/ping", func(c fiber.Ctx) error { return c.SendString("pong") })
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	_, err := app.Test(req)
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+}
+
+func TestTracing_MarksServerErrors(t *testing.T) {
+	exporter := installTestTracer(t)
+
+	app := fiber.New()
+	app.Use(Tracing())
+	app.Get("/boom", func(c fiber.Ctx) error { return fiber.NewError(fiber.StatusBadGateway, "upstream") })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/boom", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadGateway, resp.StatusCode)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
@@ -13,11 +13,54 @@ type Newsletter struct {
 
 func InitRestNewsletter(app fiber.Router, service domainNewsletter.INewsletterUsecase) Newsletter {
 	rest := Newsletter{Service: service}
+	app.Post("/newsletter", rest.Create)
+	app.Post("/newsletter/follow", rest.Follow)
 	app.Post("/newsletter/unfollow", rest.Unfollow)
+	app.Post("/newsletter/update", rest.Update)
+	app.Post("/newsletter/mute", rest.Mute)
 	app.Get("/newsletter/messages", rest.GetMessages)
+	app.Post("/newsletter/post", rest.SendPost)
+	app.Post("/newsletter/post/edit", rest.EditPost)
+	app.Post("/newsletter/post/delete", rest.DeletePost)
+	app.Get("/newsletter/post/reactions", rest.GetPostReactions)
 	return rest
 }
 
+func (controller *Newsletter) Create(c fiber.Ctx) error {
+	var request domainNewsletter.CreateRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	if picture, errFile := c.FormFile("picture"); errFile == nil {
+		request.Picture = picture
+	}
+
+	response, err := controller.Service.Create(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success create newsletter",
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) Follow(c fiber.Ctx) error {
+	var request domainNewsletter.FollowRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	err = controller.Service.Follow(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success follow newsletter",
+	})
+}
+
 func (controller *Newsletter) Unfollow(c fiber.Ctx) error {
 	var request domainNewsletter.UnfollowRequest
 	err := c.Bind().Body(&request)
@@ -33,6 +76,44 @@ func (controller *Newsletter) Unfollow(c fiber.Ctx) error {
 	})
 }
 
+func (controller *Newsletter) Update(c fiber.Ctx) error {
+	var request domainNewsletter.UpdateRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	if picture, errFile := c.FormFile("picture"); errFile == nil {
+		request.Picture = picture
+	}
+
+	err = controller.Service.Update(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update newsletter",
+	})
+}
+
+func (controller *Newsletter) Mute(c fiber.Ctx) error {
+	var request domainNewsletter.MuteRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	err = controller.Service.Mute(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	message := "Success unmute newsletter"
+	if request.Mute {
+		message = "Success mute newsletter"
+	}
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+	})
+}
+
 func (controller *Newsletter) GetMessages(c fiber.Ctx) error {
 	var request domainNewsletter.GetMessagesRequest
 	err := c.Bind().Query(&request)
@@ -48,3 +129,71 @@ func (controller *Newsletter) GetMessages(c fiber.Ctx) error {
 		Results: response,
 	})
 }
+
+func (controller *Newsletter) SendPost(c fiber.Ctx) error {
+	var request domainNewsletter.SendPostRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	if media, errFile := c.FormFile("media"); errFile == nil {
+		request.Media = media
+	}
+
+	response, err := controller.Service.SendPost(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) EditPost(c fiber.Ctx) error {
+	var request domainNewsletter.EditPostRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.EditPost(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) DeletePost(c fiber.Ctx) error {
+	var request domainNewsletter.DeletePostRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.DeletePost(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Newsletter) GetPostReactions(c fiber.Ctx) error {
+	var request domainNewsletter.GetPostReactionsRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.GetPostReactions(whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c)), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get newsletter post reactions",
+		Results: response,
+	})
+}
@@ -23,6 +23,9 @@ func InitRestUser(app fiber.Router, service domainUser.IUserUsecase) User {
 	app.Get("/user/my/contacts", rest.UserMyListContacts)
 	app.Get("/user/check", rest.UserCheck)
 	app.Get("/user/business-profile", rest.UserBusinessProfile)
+	app.Get("/user/presence", rest.UserPresence)
+	app.Post("/user/presence/subscribe", rest.UserSubscribePresence)
+	app.Post("/user/presence/unsubscribe", rest.UserUnsubscribePresence)
 
 	return rest
 }
@@ -204,3 +207,61 @@ func getDeviceFromCtx(c fiber.Ctx) *whatsapp.DeviceInstance {
 	}
 	return nil
 }
+
+func (controller *User) UserPresence(c fiber.Ctx) error {
+	var request domainUser.PresenceRequest
+	err := c.Bind().Query(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	ctx := whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c))
+
+	response, err := controller.Service.Presence(ctx, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get presence",
+		Results: response,
+	})
+}
+
+func (controller *User) UserSubscribePresence(c fiber.Ctx) error {
+	var request domainUser.PresenceSubscriptionRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	ctx := whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c))
+
+	err = controller.Service.SubscribePresence(ctx, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Subscribed to presence of " + request.Phone,
+	})
+}
+
+func (controller *User) UserUnsubscribePresence(c fiber.Ctx) error {
+	var request domainUser.PresenceSubscriptionRequest
+	err := c.Bind().Body(&request)
+	utils.PanicIfNeeded(err)
+
+	utils.SanitizePhone(&request.Phone)
+
+	ctx := whatsapp.ContextWithDevice(c.Context(), getDeviceFromCtx(c))
+
+	err = controller.Service.UnsubscribePresence(ctx, request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Unsubscribed from presence of " + request.Phone,
+	})
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+// auditExportBatchSize bounds how many rows the JSON-lines export holds in
+// memory at once.
+const auditExportBatchSize = 500
+
+type serviceAudit struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewAuditService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainAudit.IAuditUsecase {
+	return &serviceAudit{chatStorageRepo: chatStorageRepo}
+}
+
+func (service serviceAudit) Record(_ context.Context, request domainAudit.RecordRequest) error {
+	principalType := request.Principal.Type
+	if principalType == "" {
+		principalType = domainAudit.PrincipalAnonymous
+	}
+	return service.chatStorageRepo.StoreAuditEntry(&domainChatStorage.AuditEntry{
+		Source:        request.Source,
+		PrincipalType: principalType,
+		Principal:     request.Principal.Subject,
+		ClientID:      request.Principal.ClientID,
+		DeviceID:      request.DeviceID,
+		Action:        request.Action,
+		TargetJID:     request.TargetJID,
+		MessageID:     request.MessageID,
+		Result:        request.Result,
+		StatusCode:    request.StatusCode,
+		ErrorMessage:  request.ErrorMessage,
+		CreatedAt:     time.Now().UTC(),
+	})
+}
+
+func (service serviceAudit) ListAudit(ctx context.Context, request domainAudit.ListAuditRequest) (response domainAudit.ListAuditResponse, err error) {
+	if err = validations.ValidateListAudit(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := buildAuditFilter(request)
+	filter.Limit = request.Limit
+	filter.Offset = request.Offset
+
+	entries, err := service.chatStorageRepo.GetAuditEntries(filter)
+	if err != nil {
+		return response, err
+	}
+	total, err := service.chatStorageRepo.CountAuditEntries(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainAudit.Entry, 0, len(entries))
+	for _, entry := range entries {
+		response.Data = append(response.Data, toAuditEntry(entry))
+	}
+	response.Pagination = domainAudit.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(total),
+	}
+	return response, nil
+}
+
+func (service serviceAudit) ExportAudit(ctx context.Context, request domainAudit.ListAuditRequest) (func(w io.Writer) error, error) {
+	// Pagination is meaningless for an export; clear it so validation only
+	// checks the filters.
+	request.Limit, request.Offset = 0, 0
+	if err := validations.ValidateListAudit(ctx, &request); err != nil {
+		return nil, err
+	}
+
+	filter := buildAuditFilter(request)
+	// Pin the upper bound so rows appended mid-export cannot shift the
+	// offset-based pages and duplicate entries.
+	if filter.Until == nil {
+		now := time.Now().UTC()
+		filter.Until = &now
+	}
+	filter.Limit = auditExportBatchSize
+
+	return func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		flusher, _ := w.(interface{ Flush() error })
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			entries, err := service.chatStorageRepo.GetAuditEntries(filter)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if err := encoder.Encode(toAuditEntry(entry)); err != nil {
+					return err
+				}
+			}
+			// Hand each batch to the client before reading the next one.
+			if flusher != nil {
+				if err := flusher.Flush(); err != nil {
+					return err
+				}
+			}
+			if len(entries) < auditExportBatchSize {
+				return nil
+			}
+			filter.Offset += len(entries)
+		}
+	}, nil
+}
+
+func buildAuditFilter(request domainAudit.ListAuditRequest) *domainChatStorage.AuditFilter {
+	filter := &domainChatStorage.AuditFilter{
+		Source:    request.Source,
+		Principal: request.Principal,
+		DeviceID:  request.DeviceID,
+		Action:    request.Action,
+		TargetJID: request.TargetJID,
+		MessageID: request.MessageID,
+		Result:    request.Result,
+	}
+	// Timestamps were validated as RFC3339 already. Entries are stored in
+	// UTC, so normalize the bounds for a consistent comparison.
+	if since, err := time.Parse(time.RFC3339, request.Since); err == nil {
+		since = since.UTC()
+		filter.Since = &since
+	}
+	if until, err := time.Parse(time.RFC3339, request.Until); err == nil {
+		until = until.UTC()
+		filter.Until = &until
+	}
+	return filter
+}
+
+func toAuditEntry(entry *domainChatStorage.AuditEntry) domainAudit.Entry {
+	return domainAudit.Entry{
+		ID:        entry.ID,
+		Timestamp: entry.CreatedAt.UTC().Format(time.RFC3339),
+		Source:    entry.Source,
+		Principal: domainAudit.Principal{
+			Type:     entry.PrincipalType,
+			Subject:  entry.Principal,
+			ClientID: entry.ClientID,
+		},
+		DeviceID:     entry.DeviceID,
+		Action:       entry.Action,
+		TargetJID:    entry.TargetJID,
+		MessageID:    entry.MessageID,
+		Result:       entry.Result,
+		StatusCode:   entry.StatusCode,
+		ErrorMessage: entry.ErrorMessage,
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	domainAudit "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/audit"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// pagedAuditRepo serves a fixed number of audit rows page by page.
+type pagedAuditRepo struct {
+	domainChatStorage.IChatStorageRepository
+	total int
+	reads int
+}
+
+func (r *pagedAuditRepo) GetAuditEntries(filter *domainChatStorage.AuditFilter) ([]*domainChatStorage.AuditEntry, error) {
+	r.reads++
+	var entries []*domainChatStorage.AuditEntry
+	for i := filter.Offset; i < r.total && len(entries) < filter.Limit; i++ {
+		entries = append(entries, &domainChatStorage.AuditEntry{ID: int64(i + 1), Action: "POST /send/message"})
+	}
+	return entries, nil
+}
+
+// flushCountingWriter records how often the export handed a batch over.
+type flushCountingWriter struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (w *flushCountingWriter) Flush() error {
+	w.flushes++
+	return nil
+}
+
+func TestExportAuditStreamsInBatches(t *testing.T) {
+	repo := &pagedAuditRepo{total: auditExportBatchSize + 3}
+	service := NewAuditService(repo)
+
+	var validationErr pkgError.ValidationError
+	if _, err := service.ExportAudit(context.Background(), domainAudit.ListAuditRequest{Source: "ftp"}); !errors.As(err, &validationErr) {
+		t.Fatalf("expected a validation error before streaming, got %v", err)
+	}
+	if repo.reads != 0 {
+		t.Fatalf("expected no reads for an invalid export, got %d", repo.reads)
+	}
+
+	write, err := service.ExportAudit(context.Background(), domainAudit.ListAuditRequest{})
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	if repo.reads != 0 {
+		t.Fatalf("expected the export to read nothing until written, got %d reads", repo.reads)
+	}
+
+	var out flushCountingWriter
+	if err := write(&out); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != repo.total {
+		t.Fatalf("expected %d lines, got %d", repo.total, lines)
+	}
+	if repo.reads != 2 || out.flushes != 2 {
+		t.Fatalf("expected 2 batches flushed, got %d reads and %d flushes", repo.reads, out.flushes)
+	}
+}
@@ -6,21 +6,28 @@ import (
 	"time"
 
 	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
 )
 
-type serviceCall struct{}
+type serviceCall struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
 
-func NewCallService() domainCall.ICallUsecase {
-	return &serviceCall{}
+func NewCallService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainCall.ICallUsecase {
+	return &serviceCall{chatStorageRepo: chatStorageRepo}
 }
 
-func (service serviceCall) RejectCall(ctx context.Context, callerJID string, callID string) error {
-	if err := validations.ValidateRejectCall(ctx, callerJID, callID); err != nil {
+func (service serviceCall) RejectCall(ctx context.Context, callerJID string, callID string) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.call.RejectCall")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateRejectCall(ctx, callerJID, callID); err != nil {
 		return err
 	}
 
@@ -38,11 +45,96 @@ func (service serviceCall) RejectCall(ctx context.Context, callerJID string, cal
 	rejectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	if err := client.RejectCall(rejectCtx, parsedJID, callID); err != nil {
+	if err = client.RejectCall(rejectCtx, parsedJID, callID); err != nil {
 		logrus.WithError(err).Error("Failed to reject call")
 		return fmt.Errorf("failed to reject call: %w", err)
 	}
 
 	logrus.Info("Rejected call successfully")
+
+	deviceID := deviceIDFromContext(ctx)
+	whatsapp.MarkCallRejected(service.chatStorageRepo, deviceID, callID, time.Now())
+	whatsapp.SendCallRejectMessage(ctx, client, service.chatStorageRepo, deviceID, parsedJID)
 	return nil
 }
+
+func (service serviceCall) ListCalls(ctx context.Context, request domainCall.ListCallsRequest) (response domainCall.ListCallsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.call.ListCalls")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateListCalls(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := buildCallRecordFilter(request)
+	filter.DeviceID = deviceIDFromContext(ctx)
+
+	records, err := service.chatStorageRepo.GetCallRecords(filter)
+	if err != nil {
+		return response, err
+	}
+	total, err := service.chatStorageRepo.CountCallRecords(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainCall.CallInfo, 0, len(records))
+	for _, record := range records {
+		response.Data = append(response.Data, toCallInfo(record))
+	}
+	response.Pagination = domainCall.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(total),
+	}
+	return response, nil
+}
+
+func buildCallRecordFilter(request domainCall.ListCallsRequest) *domainChatStorage.CallRecordFilter {
+	filter := &domainChatStorage.CallRecordFilter{
+		Status: request.Status,
+		Limit:  request.Limit,
+		Offset: request.Offset,
+	}
+	// Calls are filed under the non-AD JID, so "628123" and
+	// "628123:2@s.whatsapp.net" find the same history.
+	if request.ChatJID != "" {
+		if jid := utils.FormatJID(request.ChatJID); !jid.IsEmpty() {
+			filter.ChatJID = jid.String()
+		} else {
+			filter.ChatJID = request.ChatJID
+		}
+	}
+	// Timestamps were validated as RFC3339 already.
+	if since, err := time.Parse(time.RFC3339, request.Since); err == nil {
+		filter.Since = &since
+	}
+	if until, err := time.Parse(time.RFC3339, request.Until); err == nil {
+		filter.Until = &until
+	}
+	return filter
+}
+
+func toCallInfo(record *domainChatStorage.CallRecord) domainCall.CallInfo {
+	info := domainCall.CallInfo{
+		CallID:          record.CallID,
+		ChatJID:         record.ChatJID,
+		CallerJID:       record.CallerJID,
+		GroupJID:        record.GroupJID,
+		IsVideo:         record.IsVideo,
+		Status:          record.Status,
+		AutoRejected:    record.AutoRejected,
+		RemotePlatform:  record.RemotePlatform,
+		RemoteVersion:   record.RemoteVersion,
+		EndReason:       record.EndReason,
+		OfferedAt:       record.OfferedAt.Format(time.RFC3339),
+		DurationSeconds: record.DurationSeconds,
+	}
+	if record.AcceptedAt != nil {
+		info.AcceptedAt = record.AcceptedAt.Format(time.RFC3339)
+	}
+	if record.EndedAt != nil {
+		info.EndedAt = record.EndedAt.Format(time.RFC3339)
+	}
+	return info
+}
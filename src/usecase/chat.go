@@ -10,10 +10,12 @@ import (
 	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
 )
 
 type serviceChat struct {
@@ -54,6 +56,9 @@ func chatDisplayName(jid, name string) string {
 }
 
 func (service serviceChat) ListChats(ctx context.Context, request domainChat.ListChatsRequest) (response domainChat.ListChatsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.chat.ListChats")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateListChats(ctx, &request); err != nil {
 		return response, err
 	}
@@ -86,16 +91,7 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 	// Convert entities to domain objects
 	chatInfos := make([]domainChat.ChatInfo, 0, len(chats))
 	for _, chat := range chats {
-		chatInfo := domainChat.ChatInfo{
-			JID:                 chat.JID,
-			Name:                chatDisplayName(chat.JID, chat.Name),
-			LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
-			EphemeralExpiration: chat.EphemeralExpiration,
-			CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
-			Archived:            chat.Archived,
-		}
-		chatInfos = append(chatInfos, chatInfo)
+		chatInfos = append(chatInfos, toChatInfo(chat))
 	}
 
 	// Create pagination response
@@ -118,6 +114,9 @@ func (service serviceChat) ListChats(ctx context.Context, request domainChat.Lis
 }
 
 func (service serviceChat) GetChatMessages(ctx context.Context, request domainChat.GetChatMessagesRequest) (response domainChat.GetChatMessagesResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.chat.GetChatMessages")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateGetChatMessages(ctx, &request); err != nil {
 		return response, err
 	}
@@ -207,58 +206,15 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	}
 
 	// Convert entities to domain objects
-	client := whatsapp.ClientFromContext(ctx)
-	deviceDisplayName := ""
-	if instance, ok := whatsapp.DeviceFromContext(ctx); ok && instance != nil {
-		deviceDisplayName = instance.DisplayName()
-	}
-	senderDisplayNameCache := whatsapp.NewSenderDisplayNameCache(
-		whatsapp.NewSenderDisplayNameResolver(client, deviceDisplayName),
-	)
+	senderDisplayNameCache := newSenderDisplayNameCache(ctx)
 
 	messageInfos := make([]domainChat.MessageInfo, 0, len(messages))
 	for _, message := range messages {
-		messageInfo := domainChat.MessageInfo{
-			ID:                message.ID,
-			ChatJID:           message.ChatJID,
-			SenderJID:         message.Sender,
-			SenderDisplayName: senderDisplayNameCache.Resolve(ctx, message.Sender, message.IsFromMe, ""),
-			Content:           message.Content,
-			Timestamp:         message.Timestamp.Format(time.RFC3339),
-			IsFromMe:          message.IsFromMe,
-			MediaType:         message.MediaType,
-			CallMetadata:      message.CallMetadata,
-			Filename:          message.Filename,
-			URL:               message.URL,
-			FileLength:        message.FileLength,
-			CreatedAt:         message.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:         message.UpdatedAt.Format(time.RFC3339),
-		}
-		if len(message.Reactions) > 0 {
-			messageInfo.Reactions = make([]domainChat.ReactionInfo, 0, len(message.Reactions))
-			for _, reaction := range message.Reactions {
-				messageInfo.Reactions = append(messageInfo.Reactions, domainChat.ReactionInfo{
-					Emoji:             reaction.Emoji,
-					SenderJID:         reaction.ReactorJID,
-					SenderDisplayName: senderDisplayNameCache.Resolve(ctx, reaction.ReactorJID, reaction.IsFromMe, ""),
-					IsFromMe:          reaction.IsFromMe,
-					Timestamp:         reaction.Timestamp.Format(time.RFC3339),
-				})
-			}
-		}
-		messageInfos = append(messageInfos, messageInfo)
+		messageInfos = append(messageInfos, toMessageInfo(ctx, senderDisplayNameCache, message))
 	}
 
 	// Create chat info for response
-	chatInfo := domainChat.ChatInfo{
-		JID:                 chat.JID,
-		Name:                chatDisplayName(chat.JID, chat.Name),
-		LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
-		EphemeralExpiration: chat.EphemeralExpiration,
-		CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
-		Archived:            chat.Archived,
-	}
+	chatInfo := toChatInfo(chat)
 
 	// Create pagination response
 	pagination := domainChat.PaginationResponse{
@@ -281,6 +237,62 @@ func (service serviceChat) GetChatMessages(ctx context.Context, request domainCh
 	return response, nil
 }
 
+func toChatInfo(chat *domainChatStorage.Chat) domainChat.ChatInfo {
+	return domainChat.ChatInfo{
+		JID:                 chat.JID,
+		Name:                chatDisplayName(chat.JID, chat.Name),
+		LastMessageTime:     chat.LastMessageTime.Format(time.RFC3339),
+		EphemeralExpiration: chat.EphemeralExpiration,
+		CreatedAt:           chat.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           chat.UpdatedAt.Format(time.RFC3339),
+		Archived:            chat.Archived,
+	}
+}
+
+func toMessageInfo(ctx context.Context, names *whatsapp.SenderDisplayNameCache, message *domainChatStorage.Message) domainChat.MessageInfo {
+	messageInfo := domainChat.MessageInfo{
+		ID:                message.ID,
+		ChatJID:           message.ChatJID,
+		SenderJID:         message.Sender,
+		SenderDisplayName: names.Resolve(ctx, message.Sender, message.IsFromMe, ""),
+		Content:           message.Content,
+		Timestamp:         message.Timestamp.Format(time.RFC3339),
+		IsFromMe:          message.IsFromMe,
+		MediaType:         message.MediaType,
+		CallMetadata:      message.CallMetadata,
+		Filename:          message.Filename,
+		URL:               message.URL,
+		FileLength:        message.FileLength,
+		CreatedAt:         message.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         message.UpdatedAt.Format(time.RFC3339),
+	}
+	if len(message.Reactions) > 0 {
+		messageInfo.Reactions = make([]domainChat.ReactionInfo, 0, len(message.Reactions))
+		for _, reaction := range message.Reactions {
+			messageInfo.Reactions = append(messageInfo.Reactions, domainChat.ReactionInfo{
+				Emoji:             reaction.Emoji,
+				SenderJID:         reaction.ReactorJID,
+				SenderDisplayName: names.Resolve(ctx, reaction.ReactorJID, reaction.IsFromMe, ""),
+				IsFromMe:          reaction.IsFromMe,
+				Timestamp:         reaction.Timestamp.Format(time.RFC3339),
+			})
+		}
+	}
+	return messageInfo
+}
+
+// newSenderDisplayNameCache builds the per-request sender name cache for the
+// device in ctx.
+func newSenderDisplayNameCache(ctx context.Context) *whatsapp.SenderDisplayNameCache {
+	deviceDisplayName := ""
+	if instance, ok := whatsapp.DeviceFromContext(ctx); ok && instance != nil {
+		deviceDisplayName = instance.DisplayName()
+	}
+	return whatsapp.NewSenderDisplayNameCache(
+		whatsapp.NewSenderDisplayNameResolver(whatsapp.ClientFromContext(ctx), deviceDisplayName),
+	)
+}
+
 func deviceIDFromContext(ctx context.Context) string {
 	if inst, ok := whatsapp.DeviceFromContext(ctx); ok && inst != nil {
 		if jid := inst.JID(); jid != "" {
@@ -292,6 +304,9 @@ func deviceIDFromContext(ctx context.Context) string {
 }
 
 func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinChatRequest) (response domainChat.PinChatResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.chat.PinChat")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidatePinChat(ctx, &request); err != nil {
 		return response, err
 	}
@@ -339,6 +354,9 @@ func (service serviceChat) PinChat(ctx context.Context, request domainChat.PinCh
 }
 
 func (service serviceChat) SetDisappearingTimer(ctx context.Context, request domainChat.SetDisappearingTimerRequest) (response domainChat.SetDisappearingTimerResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.chat.SetDisappearingTimer")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateSetDisappearingTimer(ctx, &request); err != nil {
 		return response, err
 	}
@@ -389,6 +407,9 @@ func (service serviceChat) SetDisappearingTimer(ctx context.Context, request dom
 }
 
 func (service serviceChat) ArchiveChat(ctx context.Context, request domainChat.ArchiveChatRequest) (response domainChat.ArchiveChatResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.chat.ArchiveChat")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateArchiveChat(ctx, &request); err != nil {
 		return response, err
 	}
@@ -440,3 +461,72 @@ func (service serviceChat) ArchiveChat(ctx context.Context, request domainChat.A
 
 	return response, nil
 }
+
+func (service serviceChat) RequestHistory(ctx context.Context, request domainChat.RequestHistoryRequest) (response domainChat.RequestHistoryResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.chat.RequestHistory")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateRequestHistory(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	chatJID, err := utils.ValidateJidWithLogin(client, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+	chatJID = chatJID.ToNonAD()
+
+	// The phone pages backwards from a message it knows, so the request is
+	// anchored on a stored message of the chat.
+	deviceID := deviceIDFromContext(ctx)
+	var anchor *domainChatStorage.Message
+	if request.BeforeMessageID != "" {
+		anchor, err = service.chatStorageRepo.GetMessageByIDAndDevice(deviceID, request.BeforeMessageID)
+		if err != nil {
+			return response, err
+		}
+		if anchor == nil || anchor.ChatJID != chatJID.String() {
+			return response, pkgError.ValidationError(fmt.Sprintf("message %s is not stored for chat %s", request.BeforeMessageID, chatJID.String()))
+		}
+	} else {
+		oldest, err := service.chatStorageRepo.GetMessages(&domainChatStorage.MessageFilter{
+			DeviceID:    deviceID,
+			ChatJID:     chatJID.String(),
+			Limit:       1,
+			OldestFirst: true,
+		})
+		if err != nil {
+			return response, err
+		}
+		if len(oldest) == 0 {
+			return response, pkgError.ValidationError(fmt.Sprintf("no stored messages for chat %s to request older history from", chatJID.String()))
+		}
+		anchor = oldest[0]
+	}
+
+	backfill, err := whatsapp.RequestHistoryBackfill(ctx, client, deviceID, &types.MessageInfo{
+		MessageSource: types.MessageSource{
+			Chat:     chatJID,
+			IsFromMe: anchor.IsFromMe,
+			IsGroup:  chatJID.Server == types.GroupServer,
+		},
+		ID:        anchor.ID,
+		Timestamp: anchor.Timestamp,
+	}, request.Count)
+	if err != nil {
+		return response, err
+	}
+
+	response.RequestID = backfill.RequestID
+	response.ChatJID = backfill.ChatJID
+	response.BeforeMessageID = backfill.BeforeMessageID
+	response.Count = backfill.Count
+	response.Status = backfill.Status
+	response.RequestedAt = backfill.RequestedAt.Format(time.RFC3339)
+	return response, nil
+}
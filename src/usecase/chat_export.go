@@ -0,0 +1,682 @@
+package usecase
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+)
+
+// chatExportPageSize is how many messages an export reads from storage per
+// query; pages are walked oldest first.
+const chatExportPageSize = 500
+
+var exportContentTypes = map[string]string{
+	domainChat.ExportFormatJSON: "application/json",
+	domainChat.ExportFormatCSV:  "text/csv; charset=utf-8",
+	domainChat.ExportFormatHTML: "text/html; charset=utf-8",
+	domainChat.ExportFormatText: "text/plain; charset=utf-8",
+}
+
+// ExportChat implements domainChat.IChatUsecase. Messages are read from
+// storage a page at a time and written straight to w, so memory use does not
+// grow with the size of the chat.
+func (service serviceChat) ExportChat(ctx context.Context, request domainChat.ExportChatRequest, w io.Writer) (response domainChat.ExportChatResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.chat.ExportChat")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateExportChat(ctx, &request); err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	if deviceID == "" {
+		return response, fmt.Errorf("device identification required")
+	}
+
+	var client *whatsmeow.Client
+	if request.IncludeMedia {
+		client = whatsapp.ClientFromContext(ctx)
+		if client == nil {
+			return response, pkgError.ErrWaCLI
+		}
+	}
+
+	chats, err := service.exportChats(deviceID, request.ChatJID)
+	if err != nil {
+		return response, err
+	}
+
+	filter := domainChatStorage.MessageFilter{DeviceID: deviceID, Limit: chatExportPageSize, OldestFirst: true}
+	if request.StartTime != "" {
+		startTime, _ := time.Parse(time.RFC3339, request.StartTime)
+		filter.StartTime = &startTime
+	}
+	if request.EndTime != "" {
+		endTime, _ := time.Parse(time.RFC3339, request.EndTime)
+		filter.EndTime = &endTime
+	}
+	location := time.UTC
+	if request.Timezone != "" {
+		location, _ = time.LoadLocation(request.Timezone)
+	}
+
+	exportedAt := time.Now().UTC()
+	multiChat := request.ChatJID == ""
+	baseName := chatExportBaseName(request.ChatJID, exportedAt)
+	response.ChatCount = len(chats)
+	response.ContentType = exportContentTypes[request.Format]
+	response.Filename = baseName + "." + request.Format
+
+	export := &chatExport{
+		repo:     service.chatStorageRepo,
+		deviceID: deviceID,
+		filter:   filter,
+		names:    newSenderDisplayNameCache(ctx),
+	}
+
+	if !request.IncludeMedia {
+		err = export.run(ctx, newChatExportWriter(request.Format, w, deviceID, exportedAt, location, multiChat), chats)
+		response.MessageCount = export.messages
+		return response, err
+	}
+
+	// Media entries are streamed into the archive as messages are read, so the
+	// transcript (which references them) is spooled to a temp file and added
+	// as the last entry.
+	transcript, err := os.CreateTemp("", "chat-export-*")
+	if err != nil {
+		return response, err
+	}
+	defer func() {
+		transcript.Close()
+		os.Remove(transcript.Name())
+	}()
+
+	archive := zip.NewWriter(w)
+	export.media = &chatExportMedia{client: client, archive: archive, names: map[string]bool{}}
+	writer := newChatExportWriter(request.Format, transcript, deviceID, exportedAt, location, multiChat)
+	if err = export.run(ctx, writer, chats); err != nil {
+		return response, err
+	}
+	if _, err = transcript.Seek(0, io.SeekStart); err != nil {
+		return response, err
+	}
+	entry, err := archive.Create(response.Filename)
+	if err != nil {
+		return response, err
+	}
+	if _, err = io.Copy(entry, transcript); err != nil {
+		return response, err
+	}
+	if err = archive.Close(); err != nil {
+		return response, err
+	}
+
+	response.Filename = baseName + ".zip"
+	response.ContentType = "application/zip"
+	response.MessageCount = export.messages
+	response.MediaCount = export.media.count
+
+	logrus.WithFields(logrus.Fields{
+		"device_id": deviceID,
+		"chats":     response.ChatCount,
+		"messages":  response.MessageCount,
+		"media":     response.MediaCount,
+	}).Info("Exported chat history with media")
+
+	return response, nil
+}
+
+// exportChats resolves the chats to export: the requested one (an empty
+// placeholder when it has not been stored yet, like GetChatMessages) or every
+// chat of the device.
+func (service serviceChat) exportChats(deviceID, chatJID string) ([]*domainChatStorage.Chat, error) {
+	if chatJID == "" {
+		return service.chatStorageRepo.GetChats(&domainChatStorage.ChatFilter{DeviceID: deviceID})
+	}
+	chat, err := service.chatStorageRepo.GetChatByDevice(deviceID, chatJID)
+	if err != nil {
+		return nil, err
+	}
+	if chat == nil {
+		chat = &domainChatStorage.Chat{DeviceID: deviceID, JID: chatJID}
+	}
+	return []*domainChatStorage.Chat{chat}, nil
+}
+
+func chatExportBaseName(chatJID string, exportedAt time.Time) string {
+	stamp := exportedAt.Format("20060102-150405")
+	if chatJID == "" {
+		return "chats-" + stamp
+	}
+	return "chat-" + utils.ExtractPhoneFromJID(chatJID) + "-" + stamp
+}
+
+type chatExport struct {
+	repo     domainChatStorage.IChatStorageRepository
+	deviceID string
+	filter   domainChatStorage.MessageFilter
+	names    *whatsapp.SenderDisplayNameCache
+	media    *chatExportMedia
+	messages int
+}
+
+func (e *chatExport) run(ctx context.Context, writer chatExportWriter, chats []*domainChatStorage.Chat) error {
+	if err := writer.Begin(); err != nil {
+		return err
+	}
+	for _, chat := range chats {
+		if err := writer.BeginChat(toChatInfo(chat)); err != nil {
+			return err
+		}
+		if err := e.writeMessages(ctx, writer, chat.JID); err != nil {
+			return err
+		}
+		if err := writer.EndChat(); err != nil {
+			return err
+		}
+	}
+	return writer.End()
+}
+
+func (e *chatExport) writeMessages(ctx context.Context, writer chatExportWriter, chatJID string) error {
+	for offset := 0; ; offset += chatExportPageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		filter := e.filter
+		filter.ChatJID = chatJID
+		filter.Offset = offset
+		messages, err := e.repo.GetMessages(&filter)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range messages {
+			exported := domainChat.ExportMessage{MessageInfo: toMessageInfo(ctx, e.names, message)}
+
+			edits, err := e.repo.GetMessageEdits(message.ID, e.deviceID)
+			if err != nil {
+				return err
+			}
+			for _, edit := range edits {
+				exported.Edits = append(exported.Edits, domainChat.MessageEditInfo{
+					EditorJID:       edit.Editor,
+					PreviousContent: edit.PreviousContent,
+					NewContent:      edit.NewContent,
+					EditedAt:        edit.EditedAt.Format(time.RFC3339),
+				})
+			}
+
+			if e.media != nil {
+				exported.MediaFile = e.media.add(ctx, message)
+			}
+
+			if err := writer.WriteMessage(exported); err != nil {
+				return err
+			}
+			e.messages++
+		}
+
+		if len(messages) < chatExportPageSize {
+			return nil
+		}
+	}
+}
+
+// chatExportMedia downloads media messages into the zip archive.
+type chatExportMedia struct {
+	client  *whatsmeow.Client
+	archive *zip.Writer
+	names   map[string]bool
+	count   int
+}
+
+var reExportFileName = regexp.MustCompile(`[^a-zA-Z0-9_\-.]`)
+
+// add downloads message's media into the archive and returns its path there,
+// or "" when the message has no media, is larger than the download limit, or
+// could no longer be downloaded
+// (expired media is common for old history and must not fail the export).
+func (m *chatExportMedia) add(ctx context.Context, message *domainChatStorage.Message) string {
+	directPath := utils.ResolveMediaDirectPath(message.DirectPath, message.URL)
+	if message.MediaType == "" || message.MediaType == "call" || directPath == "" || len(message.MediaKey) == 0 {
+		return ""
+	}
+	// The stored length is what WhatsApp declared for the file, so oversized
+	// media is skipped without downloading it. Messages stored without one
+	// are still checked once downloaded.
+	if int64(message.FileLength) > config.WhatsappSettingMaxDownloadSize {
+		logrus.WithField("message_id", message.ID).Warn("Chat export: skipping media larger than the download limit")
+		return ""
+	}
+
+	downloadable, err := utils.BuildDownloadableMessage(
+		message.MediaType,
+		message.URL,
+		directPath,
+		message.Filename,
+		message.MediaKey,
+		message.FileSHA256,
+		message.FileEncSHA256,
+		message.FileLength,
+	)
+	if err != nil {
+		return ""
+	}
+
+	data, err := m.client.Download(ctx, downloadable)
+	if err != nil {
+		logrus.WithError(err).WithField("message_id", message.ID).Warn("Chat export: skipping media that could not be downloaded")
+		return ""
+	}
+	if int64(len(data)) > config.WhatsappSettingMaxDownloadSize {
+		logrus.WithField("message_id", message.ID).Warn("Chat export: skipping media larger than the download limit")
+		return ""
+	}
+
+	name := m.fileName(message)
+	entry, err := m.archive.Create(name)
+	if err != nil {
+		logrus.WithError(err).WithField("message_id", message.ID).Warn("Chat export: failed to add media to archive")
+		return ""
+	}
+	if _, err := entry.Write(data); err != nil {
+		logrus.WithError(err).WithField("message_id", message.ID).Warn("Chat export: failed to add media to archive")
+		return ""
+	}
+	m.count++
+	return name
+}
+
+// fileName returns a unique archive path for message's media, keeping the
+// original document name when there is one.
+func (m *chatExportMedia) fileName(message *domainChatStorage.Message) string {
+	base := message.Timestamp.UTC().Format("20060102-150405") + "-" + reExportFileName.ReplaceAllString(message.ID, "_")
+	if message.Filename != "" {
+		base += "-" + reExportFileName.ReplaceAllString(strings.TrimSuffix(message.Filename, filepath.Ext(message.Filename)), "_")
+	}
+	ext := chatExportMediaExtension(message.MediaType, message.Filename)
+
+	name := path.Join("media", base+ext)
+	for i := 2; m.names[name]; i++ {
+		name = path.Join("media", base+"-"+strconv.Itoa(i)+ext)
+	}
+	m.names[name] = true
+	return name
+}
+
+func chatExportMediaExtension(mediaType, filename string) string {
+	if ext := filepath.Ext(filename); ext != "" {
+		return reExportFileName.ReplaceAllString(ext, "_")
+	}
+	switch mediaType {
+	case "image":
+		return ".jpg"
+	case "video":
+		return ".mp4"
+	case "audio", "ptt":
+		return ".ogg"
+	case "sticker":
+		return ".webp"
+	default:
+		return ".bin"
+	}
+}
+
+// chatExportWriter renders an export document. Begin and End are called once,
+// BeginChat/EndChat around the messages of each chat.
+type chatExportWriter interface {
+	Begin() error
+	BeginChat(chat domainChat.ChatInfo) error
+	WriteMessage(message domainChat.ExportMessage) error
+	EndChat() error
+	End() error
+}
+
+func newChatExportWriter(format string, w io.Writer, deviceID string, exportedAt time.Time, location *time.Location, multiChat bool) chatExportWriter {
+	switch format {
+	case domainChat.ExportFormatCSV:
+		return &csvChatExportWriter{w: csv.NewWriter(w), location: location}
+	case domainChat.ExportFormatHTML:
+		return &htmlChatExportWriter{w: w, deviceID: deviceID, exportedAt: exportedAt, location: location}
+	case domainChat.ExportFormatText:
+		return &textChatExportWriter{w: w, location: location, multiChat: multiChat}
+	default:
+		return &jsonChatExportWriter{w: w, deviceID: deviceID, exportedAt: exportedAt}
+	}
+}
+
+// jsonChatExportWriter writes {"device_id", "exported_at", "chats": [chat
+// fields + "messages": [...]]} incrementally.
+type jsonChatExportWriter struct {
+	w          io.Writer
+	deviceID   string
+	exportedAt time.Time
+	chats      int
+	messages   int
+}
+
+func (j *jsonChatExportWriter) Begin() error {
+	header, err := json.Marshal(map[string]string{
+		"device_id":   j.deviceID,
+		"exported_at": j.exportedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(j.w, `%s,"chats":[`, header[:len(header)-1])
+	return err
+}
+
+func (j *jsonChatExportWriter) BeginChat(chat domainChat.ChatInfo) error {
+	raw, err := json.Marshal(chat)
+	if err != nil {
+		return err
+	}
+	separator := ""
+	if j.chats > 0 {
+		separator = ","
+	}
+	j.chats++
+	j.messages = 0
+	_, err = fmt.Fprintf(j.w, `%s%s,"messages":[`, separator, raw[:len(raw)-1])
+	return err
+}
+
+func (j *jsonChatExportWriter) WriteMessage(message domainChat.ExportMessage) error {
+	raw, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if j.messages > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.messages++
+	_, err = j.w.Write(raw)
+	return err
+}
+
+func (j *jsonChatExportWriter) EndChat() error {
+	_, err := io.WriteString(j.w, "]}")
+	return err
+}
+
+func (j *jsonChatExportWriter) End() error {
+	_, err := io.WriteString(j.w, "]}\n")
+	return err
+}
+
+var chatExportCSVHeader = []string{
+	"chat_jid", "chat_name", "message_id", "timestamp", "sender_jid", "sender_name", "is_from_me",
+	"content", "media_type", "filename", "url", "media_file", "reactions", "edits",
+}
+
+// csvChatExportWriter writes one row per message. Reactions are flattened to
+// "emoji sender" pairs and edits to a JSON array so no information is lost.
+type csvChatExportWriter struct {
+	w        *csv.Writer
+	location *time.Location
+	chat     domainChat.ChatInfo
+}
+
+func (c *csvChatExportWriter) Begin() error {
+	return c.w.Write(chatExportCSVHeader)
+}
+
+func (c *csvChatExportWriter) BeginChat(chat domainChat.ChatInfo) error {
+	c.chat = chat
+	return nil
+}
+
+func (c *csvChatExportWriter) WriteMessage(message domainChat.ExportMessage) error {
+	reactions := make([]string, 0, len(message.Reactions))
+	for _, reaction := range message.Reactions {
+		reactions = append(reactions, reaction.Emoji+" "+reaction.SenderDisplayName)
+	}
+	edits := ""
+	if len(message.Edits) > 0 {
+		raw, err := json.Marshal(message.Edits)
+		if err != nil {
+			return err
+		}
+		edits = string(raw)
+	}
+	return c.w.Write([]string{
+		c.chat.JID,
+		c.chat.Name,
+		message.ID,
+		formatExportTime(message.Timestamp, c.location, "2006-01-02 15:04:05"),
+		message.SenderJID,
+		message.SenderDisplayName,
+		strconv.FormatBool(message.IsFromMe),
+		message.Content,
+		message.MediaType,
+		message.Filename,
+		message.URL,
+		message.MediaFile,
+		strings.Join(reactions, "; "),
+		edits,
+	})
+}
+
+func (c *csvChatExportWriter) EndChat() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvChatExportWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// textChatExportWriter mirrors the transcript of WhatsApp's "Export chat":
+// "dd/mm/yyyy, HH:MM - Sender: text", with "<Media omitted>" for media that
+// is not bundled and "<name> (file attached)" for media that is.
+type textChatExportWriter struct {
+	w         io.Writer
+	location  *time.Location
+	multiChat bool
+	chats     int
+}
+
+func (t *textChatExportWriter) Begin() error { return nil }
+
+func (t *textChatExportWriter) BeginChat(chat domainChat.ChatInfo) error {
+	if !t.multiChat {
+		return nil
+	}
+	separator := ""
+	if t.chats > 0 {
+		separator = "\n"
+	}
+	t.chats++
+	_, err := fmt.Fprintf(t.w, "%s===== %s (%s) =====\n", separator, chat.Name, chat.JID)
+	return err
+}
+
+func (t *textChatExportWriter) WriteMessage(message domainChat.ExportMessage) error {
+	sender := message.SenderDisplayName
+	if sender == "" {
+		sender = utils.ExtractPhoneFromJID(message.SenderJID)
+	}
+
+	var body string
+	switch {
+	case message.MediaFile != "":
+		body = path.Base(message.MediaFile) + " (file attached)"
+		if message.Content != "" {
+			body += "\n" + message.Content
+		}
+	case message.MediaType != "" && message.MediaType != "call":
+		body = "<Media omitted>"
+	default:
+		body = message.Content
+	}
+	if len(message.Edits) > 0 {
+		body += " <This message was edited>"
+	}
+
+	_, err := fmt.Fprintf(t.w, "%s - %s: %s\n", formatExportTime(message.Timestamp, t.location, "02/01/2006, 15:04"), sender, body)
+	return err
+}
+
+func (t *textChatExportWriter) EndChat() error { return nil }
+
+func (t *textChatExportWriter) End() error { return nil }
+
+// htmlChatExportWriter writes a single self-contained page (inline CSS, no
+// external assets); bundled media is referenced relative to the archive root.
+type htmlChatExportWriter struct {
+	w          io.Writer
+	deviceID   string
+	exportedAt time.Time
+	location   *time.Location
+}
+
+var chatExportHTML = template.Must(template.New("export").Funcs(template.FuncMap{
+	"mediaKind": func(mediaType string) string {
+		switch mediaType {
+		case "image", "sticker":
+			return "image"
+		case "video":
+			return "video"
+		case "audio", "ptt":
+			return "audio"
+		}
+		return ""
+	},
+}).Parse(`
+{{- define "begin" -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>WhatsApp chat export</title>
+<style>
+body{font-family:-apple-system,"Segoe UI",Roboto,Helvetica,Arial,sans-serif;background:#efeae2;margin:0;padding:24px;color:#111b21}
+header{max-width:820px;margin:0 auto 16px;color:#54656f;font-size:13px}
+section{max-width:820px;margin:0 auto 32px}
+h2{font-size:18px;margin:0 0 4px}
+.jid{color:#667781;font-size:12px;margin:0 0 12px}
+.msg{max-width:75%;margin:4px 0;padding:6px 10px;border-radius:8px;background:#fff;box-shadow:0 1px .5px rgba(0,0,0,.13);clear:both}
+.msg.me{margin-left:auto;background:#d9fdd3}
+.meta{font-size:11px;color:#667781;margin-bottom:2px}
+.sender{font-weight:600;color:#1f7aec}
+.content{white-space:pre-wrap;word-wrap:break-word}
+.media{margin:4px 0}
+.media img,.media video{max-width:100%;border-radius:6px}
+.placeholder{font-style:italic;color:#667781}
+.reactions{font-size:12px;margin-top:4px}
+details{font-size:12px;color:#667781;margin-top:4px}
+</style>
+</head>
+<body>
+<header>Exported {{.ExportedAt}} &middot; device {{.DeviceID}}</header>
+{{- end}}
+{{- define "chat"}}
+<section>
+<h2>{{.Name}}</h2>
+<p class="jid">{{.JID}}</p>
+{{- end}}
+{{- define "message"}}
+<div class="msg{{if .IsFromMe}} me{{end}}" id="{{.ID}}">
+<div class="meta"><span class="sender">{{.Sender}}</span> &middot; {{.Time}}{{if .Edits}} &middot; edited{{end}}</div>
+{{- if .MediaFile}}
+<div class="media">
+{{- $kind := mediaKind .MediaType}}
+{{- if eq $kind "image"}}<img src="{{.MediaFile}}" alt="{{.Filename}}">
+{{- else if eq $kind "video"}}<video src="{{.MediaFile}}" controls></video>
+{{- else if eq $kind "audio"}}<audio src="{{.MediaFile}}" controls></audio>
+{{- else}}<a href="{{.MediaFile}}">{{or .Filename .MediaFile}}</a>{{end}}
+</div>
+{{- else if .MediaType}}
+<div class="placeholder">[{{.MediaType}}{{if .Filename}}: {{.Filename}}{{end}}]</div>
+{{- end}}
+{{- if .Content}}
+<div class="content">{{.Content}}</div>
+{{- end}}
+{{- if .Reactions}}
+<div class="reactions">{{range .Reactions}}<span title="{{.SenderDisplayName}}">{{.Emoji}}</span> {{end}}</div>
+{{- end}}
+{{- if .Edits}}
+<details><summary>Edit history</summary>{{range .Edits}}<div>{{.EditedAt}}: {{.PreviousContent}} &rarr; {{.NewContent}}</div>{{end}}</details>
+{{- end}}
+</div>
+{{- end}}
+{{- define "endChat"}}
+</section>
+{{- end}}
+{{- define "end"}}
+</body>
+</html>
+{{end}}`))
+
+func (h *htmlChatExportWriter) Begin() error {
+	return chatExportHTML.ExecuteTemplate(h.w, "begin", map[string]string{
+		"ExportedAt": h.exportedAt.In(h.location).Format("2006-01-02 15:04 MST"),
+		"DeviceID":   h.deviceID,
+	})
+}
+
+func (h *htmlChatExportWriter) BeginChat(chat domainChat.ChatInfo) error {
+	return chatExportHTML.ExecuteTemplate(h.w, "chat", chat)
+}
+
+func (h *htmlChatExportWriter) WriteMessage(message domainChat.ExportMessage) error {
+	sender := message.SenderDisplayName
+	if sender == "" {
+		sender = utils.ExtractPhoneFromJID(message.SenderJID)
+	}
+	return chatExportHTML.ExecuteTemplate(h.w, "message", struct {
+		domainChat.ExportMessage
+		Sender string
+		Time   string
+	}{
+		ExportMessage: message,
+		Sender:        sender,
+		Time:          formatExportTime(message.Timestamp, h.location, "2006-01-02 15:04"),
+	})
+}
+
+func (h *htmlChatExportWriter) EndChat() error {
+	return chatExportHTML.ExecuteTemplate(h.w, "endChat", nil)
+}
+
+func (h *htmlChatExportWriter) End() error {
+	return chatExportHTML.ExecuteTemplate(h.w, "end", nil)
+}
+
+// formatExportTime re-renders an RFC3339 message timestamp in location.
+func formatExportTime(timestamp string, location *time.Location, layout string) string {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return parsed.In(location).Format(layout)
+}
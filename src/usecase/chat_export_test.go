@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+const (
+	exportTestDeviceID = "628999999999@s.whatsapp.net"
+	exportTestChatJID  = "628123456789@s.whatsapp.net"
+)
+
+var exportTestStart = time.Date(2026, time.March, 1, 9, 30, 0, 0, time.UTC)
+
+type chatExportRepoStub struct {
+	domainChatStorage.IChatStorageRepository
+	chats    []*domainChatStorage.Chat
+	messages map[string][]*domainChatStorage.Message // by chat JID, oldest first
+	edits    map[string][]*domainChatStorage.MessageEdit
+	filters  []domainChatStorage.MessageFilter
+}
+
+func (r *chatExportRepoStub) GetChats(*domainChatStorage.ChatFilter) ([]*domainChatStorage.Chat, error) {
+	return r.chats, nil
+}
+
+func (r *chatExportRepoStub) GetChatByDevice(_, jid string) (*domainChatStorage.Chat, error) {
+	for _, chat := range r.chats {
+		if chat.JID == jid {
+			return chat, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *chatExportRepoStub) GetMessages(filter *domainChatStorage.MessageFilter) ([]*domainChatStorage.Message, error) {
+	r.filters = append(r.filters, *filter)
+	all := r.messages[filter.ChatJID]
+	if filter.Offset >= len(all) {
+		return nil, nil
+	}
+	end := min(filter.Offset+filter.Limit, len(all))
+	return all[filter.Offset:end], nil
+}
+
+func (r *chatExportRepoStub) GetMessageEdits(id, _ string) ([]*domainChatStorage.MessageEdit, error) {
+	return r.edits[id], nil
+}
+
+func newChatExportRepoStub() *chatExportRepoStub {
+	return &chatExportRepoStub{
+		chats: []*domainChatStorage.Chat{{
+			DeviceID: exportTestDeviceID, JID: exportTestChatJID, Name: "Alice",
+			LastMessageTime: exportTestStart, CreatedAt: exportTestStart, UpdatedAt: exportTestStart,
+		}},
+		messages: map[string][]*domainChatStorage.Message{
+			exportTestChatJID: {
+				{
+					ID: "msg-1", ChatJID: exportTestChatJID, DeviceID: exportTestDeviceID,
+					Sender: exportTestChatJID, Content: "see you <b>tomorrow</b>", Timestamp: exportTestStart,
+					Reactions: []domainChatStorage.Reaction{{
+						MessageID: "msg-1", ReactorJID: exportTestDeviceID, Emoji: "\U0001f44d", IsFromMe: true,
+						Timestamp: exportTestStart.Add(time.Minute),
+					}},
+				},
+				{
+					ID: "msg-2", ChatJID: exportTestChatJID, DeviceID: exportTestDeviceID,
+					Sender: exportTestChatJID, MediaType: "image", Filename: "photo.jpg",
+					Timestamp: exportTestStart.Add(2 * time.Minute),
+				},
+			},
+		},
+		edits: map[string][]*domainChatStorage.MessageEdit{
+			"msg-1": {{
+				OriginalMessageID: "msg-1", Editor: exportTestChatJID,
+				PreviousContent: "see you today", NewContent: "see you <b>tomorrow</b>",
+				EditedAt: exportTestStart.Add(30 * time.Second),
+			}},
+		},
+	}
+}
+
+func exportTestContext() context.Context {
+	return whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance(exportTestDeviceID, nil, nil))
+}
+
+func TestExportChatJSON(t *testing.T) {
+	repo := newChatExportRepoStub()
+	var out bytes.Buffer
+
+	response, err := NewChatService(repo).ExportChat(exportTestContext(), domainChat.ExportChatRequest{ChatJID: exportTestChatJID}, &out)
+	if err != nil {
+		t.Fatalf("export chat: %v", err)
+	}
+	if response.ContentType != "application/json" || !strings.HasSuffix(response.Filename, ".json") {
+		t.Fatalf("unexpected response %#v", response)
+	}
+	if response.ChatCount != 1 || response.MessageCount != 2 {
+		t.Fatalf("counts = %d chats / %d messages, want 1 / 2", response.ChatCount, response.MessageCount)
+	}
+
+	var document struct {
+		DeviceID string `json:"device_id"`
+		Chats    []struct {
+			JID      string                     `json:"jid"`
+			Name     string                     `json:"name"`
+			Messages []domainChat.ExportMessage `json:"messages"`
+		} `json:"chats"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &document); err != nil {
+		t.Fatalf("export is not valid JSON: %v\n%s", err, out.String())
+	}
+	if document.DeviceID != exportTestDeviceID || len(document.Chats) != 1 || document.Chats[0].Name != "Alice" {
+		t.Fatalf("unexpected document header %#v", document)
+	}
+	messages := document.Chats[0].Messages
+	if len(messages) != 2 || messages[0].ID != "msg-1" || messages[1].ID != "msg-2" {
+		t.Fatalf("messages = %#v, want msg-1 then msg-2", messages)
+	}
+	if len(messages[0].Edits) != 1 || messages[0].Edits[0].PreviousContent != "see you today" {
+		t.Fatalf("edits not exported: %#v", messages[0].Edits)
+	}
+	if len(messages[0].Reactions) != 1 || messages[0].Reactions[0].Emoji != "\U0001f44d" {
+		t.Fatalf("reactions not exported: %#v", messages[0].Reactions)
+	}
+	if len(repo.filters) == 0 || !repo.filters[0].OldestFirst || repo.filters[0].DeviceID != exportTestDeviceID {
+		t.Fatalf("messages must be read oldest first for the device, got %#v", repo.filters)
+	}
+}
+
+func TestExportChatPagesThroughEveryMessage(t *testing.T) {
+	repo := newChatExportRepoStub()
+	second := &domainChatStorage.Chat{DeviceID: exportTestDeviceID, JID: "120363000000000000@g.us", Name: "Team"}
+	repo.chats = append(repo.chats, second)
+	for i := 0; i < chatExportPageSize+3; i++ {
+		repo.messages[second.JID] = append(repo.messages[second.JID], &domainChatStorage.Message{
+			ID: fmt.Sprintf("bulk-%d", i), ChatJID: second.JID, Sender: exportTestChatJID,
+			Content: "bulk", Timestamp: exportTestStart.Add(time.Duration(i) * time.Second),
+		})
+	}
+	var out bytes.Buffer
+
+	response, err := NewChatService(repo).ExportChat(exportTestContext(), domainChat.ExportChatRequest{}, &out)
+	if err != nil {
+		t.Fatalf("export all chats: %v", err)
+	}
+	if response.ChatCount != 2 || response.MessageCount != chatExportPageSize+5 {
+		t.Fatalf("counts = %d chats / %d messages, want 2 / %d", response.ChatCount, response.MessageCount, chatExportPageSize+5)
+	}
+	if !strings.HasPrefix(response.Filename, "chats-") {
+		t.Fatalf("filename = %q, want chats-* for an all-chats export", response.Filename)
+	}
+	var document map[string]any
+	if err := json.Unmarshal(out.Bytes(), &document); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+}
+
+func TestExportChatCSV(t *testing.T) {
+	var out bytes.Buffer
+
+	_, err := NewChatService(newChatExportRepoStub()).ExportChat(exportTestContext(), domainChat.ExportChatRequest{
+		ChatJID: exportTestChatJID,
+		Format:  "CSV",
+	}, &out)
+	if err != nil {
+		t.Fatalf("export chat: %v", err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(chatExportCSVHeader, ",") {
+		t.Fatalf("unexpected CSV records %q", records)
+	}
+	row := map[string]string{}
+	for i, column := range records[0] {
+		row[column] = records[1][i]
+	}
+	if row["message_id"] != "msg-1" || row["timestamp"] != "2026-03-01 09:30:00" || row["chat_name"] != "Alice" {
+		t.Fatalf("unexpected first row %#v", row)
+	}
+	if !strings.HasPrefix(row["reactions"], "\U0001f44d ") || !strings.Contains(row["edits"], "see you today") {
+		t.Fatalf("reactions/edits not flattened: %#v", row)
+	}
+}
+
+func TestExportChatWhatsAppText(t *testing.T) {
+	var out bytes.Buffer
+
+	_, err := NewChatService(newChatExportRepoStub()).ExportChat(exportTestContext(), domainChat.ExportChatRequest{
+		ChatJID:  exportTestChatJID,
+		Format:   domainChat.ExportFormatText,
+		Timezone: "Asia/Jakarta",
+	}, &out)
+	if err != nil {
+		t.Fatalf("export chat: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("lines = %q, want 2", lines)
+	}
+	if lines[0] != "01/03/2026, 16:30 - 628123456789: see you <b>tomorrow</b> <This message was edited>" {
+		t.Fatalf("first line = %q", lines[0])
+	}
+	if lines[1] != "01/03/2026, 16:32 - 628123456789: <Media omitted>" {
+		t.Fatalf("second line = %q", lines[1])
+	}
+}
+
+func TestExportChatHTMLEscapesContent(t *testing.T) {
+	var out bytes.Buffer
+
+	_, err := NewChatService(newChatExportRepoStub()).ExportChat(exportTestContext(), domainChat.ExportChatRequest{
+		ChatJID: exportTestChatJID,
+		Format:  domainChat.ExportFormatHTML,
+	}, &out)
+	if err != nil {
+		t.Fatalf("export chat: %v", err)
+	}
+
+	html := out.String()
+	if !strings.HasPrefix(html, "<!DOCTYPE html>") || !strings.HasSuffix(strings.TrimSpace(html), "</html>") {
+		t.Fatalf("not a complete HTML document:\n%s", html)
+	}
+	if strings.Contains(html, "<b>tomorrow</b>") || !strings.Contains(html, "&lt;b&gt;tomorrow&lt;/b&gt;") {
+		t.Fatalf("message content must be escaped:\n%s", html)
+	}
+	if !strings.Contains(html, "[image: photo.jpg]") || !strings.Contains(html, "Edit history") {
+		t.Fatalf("media placeholder or edit history missing:\n%s", html)
+	}
+}
+
+func TestExportChatRejectsInvalidRequests(t *testing.T) {
+	service := NewChatService(newChatExportRepoStub())
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		request domainChat.ExportChatRequest
+		want    error
+	}{
+		{name: "unknown format", ctx: exportTestContext(), request: domainChat.ExportChatRequest{Format: "pdf"}},
+		{name: "bad start time", ctx: exportTestContext(), request: domainChat.ExportChatRequest{StartTime: "yesterday"}},
+		{name: "bad timezone", ctx: exportTestContext(), request: domainChat.ExportChatRequest{Timezone: "Mars/Olympus"}},
+		{name: "media without client", ctx: exportTestContext(), request: domainChat.ExportChatRequest{IncludeMedia: true}, want: pkgError.ErrWaCLI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ExportChat(tt.ctx, tt.request, &bytes.Buffer{})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestChatExportMediaSkipsOversizedMediaWithoutDownloading(t *testing.T) {
+	// A nil client would panic if the media were downloaded.
+	media := &chatExportMedia{names: map[string]bool{}}
+	message := &domainChatStorage.Message{
+		ID: "msg-big", MediaType: "video", DirectPath: "/v/t62/big", MediaKey: []byte("key"),
+		FileLength: uint64(config.WhatsappSettingMaxDownloadSize) + 1,
+	}
+	if got := media.add(context.Background(), message); got != "" || media.count != 0 {
+		t.Fatalf("add() = %q (count %d), want the oversized media skipped", got, media.count)
+	}
+}
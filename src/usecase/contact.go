@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type serviceContact struct {
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
+
+func NewContactService(chatStorageRepo domainChatStorage.IChatStorageRepository) domainContact.IContactUsecase {
+	return &serviceContact{chatStorageRepo: chatStorageRepo}
+}
+
+func (service serviceContact) ListContacts(ctx context.Context, request domainContact.ListContactsRequest) (response domainContact.ListContactsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.contact.ListContacts")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateListContacts(ctx, &request); err != nil {
+		return response, err
+	}
+
+	filter := &domainChatStorage.ContactFilter{
+		DeviceID: deviceIDFromContext(ctx),
+		Search:   request.Search,
+		Tag:      request.Tag,
+		Limit:    request.Limit,
+		Offset:   request.Offset,
+	}
+
+	contacts, err := service.chatStorageRepo.GetContacts(filter)
+	if err != nil {
+		return response, err
+	}
+	total, err := service.chatStorageRepo.CountContacts(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.Data = make([]domainContact.Contact, 0, len(contacts))
+	for _, contact := range contacts {
+		response.Data = append(response.Data, toContact(contact))
+	}
+	response.Pagination = domainContact.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(total),
+	}
+	return response, nil
+}
+
+func (service serviceContact) GetContact(ctx context.Context, request domainContact.GetContactRequest) (response domainContact.Contact, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.contact.GetContact")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateGetContact(ctx, request); err != nil {
+		return response, err
+	}
+
+	jid, err := contactJID(ctx, request.JID)
+	if err != nil {
+		return response, err
+	}
+
+	contact, err := service.chatStorageRepo.GetContact(deviceIDFromContext(ctx), jid)
+	if err != nil {
+		return response, err
+	}
+	if contact == nil {
+		return response, pkgError.ErrContactNotFound
+	}
+	return toContact(contact), nil
+}
+
+func (service serviceContact) UpdateContact(ctx context.Context, request domainContact.UpdateContactRequest) (response domainContact.Contact, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.contact.UpdateContact")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateUpdateContact(ctx, &request); err != nil {
+		return response, err
+	}
+
+	jid, err := contactJID(ctx, request.JID)
+	if err != nil {
+		return response, err
+	}
+
+	var tags []string
+	if request.Tags != nil {
+		tags = *request.Tags
+	}
+	deviceID := deviceIDFromContext(ctx)
+	if err = service.chatStorageRepo.UpdateContactAnnotations(deviceID, jid, tags, request.Notes); err != nil {
+		return response, err
+	}
+
+	contact, err := service.chatStorageRepo.GetContact(deviceID, jid)
+	if err != nil {
+		return response, err
+	}
+	if contact == nil {
+		return response, pkgError.ErrContactNotFound
+	}
+	return toContact(contact), nil
+}
+
+func (service serviceContact) SyncContacts(ctx context.Context) (response domainContact.SyncContactsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.contact.SyncContacts")
+	defer func() { telemetry.End(span, err) }()
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+	utils.MustLogin(client)
+
+	contacts, err := client.Store.Contacts.GetAllContacts(ctx)
+	if err != nil {
+		return response, err
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	for jid, info := range contacts {
+		if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+			continue
+		}
+		savedName := info.FullName
+		if savedName == "" {
+			savedName = info.FirstName
+		}
+		whatsapp.RecordContact(ctx, service.chatStorageRepo, client, deviceID, jid, domainChatStorage.Contact{
+			PushName:     info.PushName,
+			BusinessName: info.BusinessName,
+			SavedName:    savedName,
+		})
+		response.Synced++
+	}
+	return response, nil
+}
+
+// contactJID normalizes a phone number or JID to the key contacts are filed
+// under: the non-AD phone-number JID, resolving LIDs when the mapping is known.
+func contactJID(ctx context.Context, raw string) (string, error) {
+	jid, err := utils.ParseJID(raw)
+	if err != nil {
+		return "", pkgError.ValidationError(err.Error())
+	}
+	jid = jid.ToNonAD()
+	if jid.Server == types.HiddenUserServer {
+		if client := whatsapp.ClientFromContext(ctx); client != nil {
+			jid = whatsapp.NormalizeJIDFromLID(ctx, jid, client).ToNonAD()
+		}
+	}
+	return jid.String(), nil
+}
+
+func toContact(contact *domainChatStorage.Contact) domainContact.Contact {
+	response := domainContact.Contact{
+		JID:          contact.JID,
+		Phone:        contact.Phone,
+		LID:          contact.LID,
+		Name:         contactName(contact),
+		PushName:     contact.PushName,
+		BusinessName: contact.BusinessName,
+		SavedName:    contact.SavedName,
+		AvatarURL:    contact.AvatarURL,
+		Tags:         contact.Tags,
+		Notes:        contact.Notes,
+		UpdatedAt:    contact.UpdatedAt.Format(time.RFC3339),
+	}
+	if response.Tags == nil {
+		response.Tags = []string{}
+	}
+	if contact.LastInteractionAt != nil {
+		response.LastInteractionAt = contact.LastInteractionAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+// contactName follows the sender-label precedence: the saved address-book
+// name, then the push name, then the verified business name.
+func contactName(contact *domainChatStorage.Contact) string {
+	for _, name := range []string{contact.SavedName, contact.PushName, contact.BusinessName} {
+		if name != "" {
+			return name
+		}
+	}
+	return contact.Phone
+}
@@ -3,6 +3,9 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	domainApp "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
@@ -26,7 +29,13 @@ func NewDeviceService(manager *whatsapp.DeviceManager, app domainApp.IAppUsecase
 	}
 }
 
-func (s *serviceDevice) ListDevices(_ context.Context) ([]domainDevice.Device, error) {
+func (s *serviceDevice) ListDevices(_ context.Context, filter domainDevice.DeviceFilter) ([]domainDevice.Device, error) {
+	switch filter.State {
+	case "", domainDevice.DeviceStateDisconnected, domainDevice.DeviceStateConnecting,
+		domainDevice.DeviceStateConnected, domainDevice.DeviceStateLoggedIn:
+	default:
+		return nil, pkgError.ValidationError(fmt.Sprintf("unknown device state %q", filter.State))
+	}
 	if s.manager == nil {
 		return []domainDevice.Device{}, nil
 	}
@@ -34,11 +43,39 @@ func (s *serviceDevice) ListDevices(_ context.Context) ([]domainDevice.Device, e
 	var result []domainDevice.Device
 	for _, inst := range s.manager.ListDevices() {
 		inst.UpdateStateFromClient()
-		result = append(result, convertInstance(inst))
+		if device := convertInstance(inst); matchesDeviceFilter(device, filter) {
+			result = append(result, device)
+		}
 	}
 	return result, nil
 }
 
+// matchesDeviceFilter reports whether a device carries every filter tag, is in
+// the filter state and contains the search text in one of its fields.
+func matchesDeviceFilter(device domainDevice.Device, filter domainDevice.DeviceFilter) bool {
+	if filter.State != "" && device.State != filter.State {
+		return false
+	}
+	for _, tag := range filter.Tags {
+		if !slices.ContainsFunc(device.Tags, func(own string) bool { return strings.EqualFold(own, tag) }) {
+			return false
+		}
+	}
+
+	search := strings.ToLower(strings.TrimSpace(filter.Search))
+	if search == "" {
+		return true
+	}
+	fields := []string{device.ID, device.DisplayName, device.PhoneNumber, device.JID}
+	fields = append(fields, device.Tags...)
+	for _, value := range device.Metadata {
+		fields = append(fields, value)
+	}
+	return slices.ContainsFunc(fields, func(field string) bool {
+		return strings.Contains(strings.ToLower(field), search)
+	})
+}
+
 func (s *serviceDevice) GetDevice(_ context.Context, deviceID string) (*domainDevice.Device, error) {
 	if s.manager == nil {
 		return nil, fmt.Errorf("device manager not initialized")
@@ -50,10 +87,83 @@ func (s *serviceDevice) GetDevice(_ context.Context, deviceID string) (*domainDe
 	return nil, fmt.Errorf("device %s not found", deviceID)
 }
 
+// UpdateDevice sets a device's tags and metadata. Fields left nil in the
+// request keep their current value.
+func (s *serviceDevice) UpdateDevice(ctx context.Context, deviceID string, request domainDevice.UpdateDeviceRequest) (*domainDevice.Device, error) {
+	if s.manager == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+
+	inst, ok := s.manager.GetDevice(deviceID)
+	if !ok {
+		return nil, pkgError.ErrDeviceNotFound
+	}
+
+	request = normalizeUpdateDeviceRequest(request)
+	if err := validations.ValidateUpdateDevice(ctx, request); err != nil {
+		return nil, err
+	}
+
+	attributes := &chatstorage.DeviceAttributes{Tags: inst.Tags(), Metadata: inst.Metadata()}
+	if request.Tags != nil {
+		attributes.Tags = request.Tags
+	}
+	if request.Metadata != nil {
+		attributes.Metadata = request.Metadata
+	}
+
+	storage := s.manager.GetStorage()
+	if storage == nil {
+		return nil, fmt.Errorf("storage not available")
+	}
+	if err := storage.SetDeviceAttributes(deviceID, attributes); err != nil {
+		return nil, fmt.Errorf("failed to set device attributes: %w", err)
+	}
+	inst.SetAttributes(attributes)
+
+	websocket.Broadcast <- websocket.BroadcastMessage{
+		Code:    "DEVICE_UPDATED",
+		Message: fmt.Sprintf("Device %s updated", deviceID),
+		Result: map[string]any{
+			"device_id": deviceID,
+		},
+	}
+
+	device := convertInstance(inst)
+	return &device, nil
+}
+
+// normalizeUpdateDeviceRequest trims tags and metadata keys and drops blank or
+// case-insensitively repeated tags, keeping nil fields nil.
+func normalizeUpdateDeviceRequest(request domainDevice.UpdateDeviceRequest) domainDevice.UpdateDeviceRequest {
+	if request.Tags != nil {
+		tags := make([]string, 0, len(request.Tags))
+		for _, tag := range request.Tags {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || slices.ContainsFunc(tags, func(seen string) bool { return strings.EqualFold(seen, tag) }) {
+				continue
+			}
+			tags = append(tags, tag)
+		}
+		request.Tags = tags
+	}
+	if request.Metadata != nil {
+		metadata := make(map[string]string, len(request.Metadata))
+		for key, value := range request.Metadata {
+			metadata[strings.TrimSpace(key)] = value
+		}
+		request.Metadata = metadata
+	}
+	return request
+}
+
 func (s *serviceDevice) AddDevice(ctx context.Context, deviceID string, webhook *chatstorage.DeviceWebhookConfig) (*domainDevice.Device, error) {
 	if s.manager == nil {
 		return nil, fmt.Errorf("device manager not initialized")
 	}
+	if err := validateDeviceWebhookConfig(ctx, webhook); err != nil {
+		return nil, err
+	}
 
 	inst, err := s.manager.CreateDevice(ctx, deviceID)
 	if err != nil {
@@ -165,7 +275,11 @@ func (s *serviceDevice) ReconnectDevice(_ context.Context, deviceID string) erro
 		}
 
 		client.Disconnect()
-		return client.Connect()
+		if err := client.Connect(); err != nil {
+			inst.RecordConnectFailure(err)
+			return err
+		}
+		return nil
 	}
 	return fmt.Errorf("device %s not found", deviceID)
 }
@@ -270,6 +384,9 @@ func (s *serviceDevice) SetDeviceWebhookConfig(ctx context.Context, deviceID str
 	if !ok {
 		return pkgError.ErrDeviceNotFound
 	}
+	if err := validateDeviceWebhookConfig(ctx, config); err != nil {
+		return err
+	}
 
 	storage := s.manager.GetStorage()
 	if storage == nil {
@@ -291,6 +408,25 @@ func (s *serviceDevice) SetDeviceWebhookConfig(ctx context.Context, deviceID str
 	return nil
 }
 
+// validateDeviceWebhookConfig normalizes the delivery settings of a webhook
+// config in place and rejects invalid ones.
+func validateDeviceWebhookConfig(ctx context.Context, config *chatstorage.DeviceWebhookConfig) error {
+	if config == nil {
+		return nil
+	}
+	config.WebhookMethod = strings.ToUpper(strings.TrimSpace(config.WebhookMethod))
+	config.WebhookSignatureAlgorithm = strings.ToLower(strings.TrimSpace(config.WebhookSignatureAlgorithm))
+	if len(config.WebhookHeaders) > 0 {
+		headers := make(map[string]string, len(config.WebhookHeaders))
+		for name, value := range config.WebhookHeaders {
+			headers[strings.TrimSpace(name)] = value
+		}
+		config.WebhookHeaders = headers
+	}
+
+	return validations.ValidateDeviceWebhookDelivery(ctx, config)
+}
+
 // GetDeviceWebhookConfig retrieves the complete webhook configuration for a specific device.
 func (s *serviceDevice) GetDeviceWebhookConfig(ctx context.Context, deviceID string) (*chatstorage.DeviceWebhookConfig, error) {
 	if s.manager == nil {
@@ -315,6 +451,217 @@ func (s *serviceDevice) GetDeviceWebhookConfig(ctx context.Context, deviceID str
 	return config, nil
 }
 
+// SetDeviceCallConfig sets how a specific device handles incoming calls.
+func (s *serviceDevice) SetDeviceCallConfig(ctx context.Context, deviceID string, config *chatstorage.DeviceCallConfig) error {
+	if s.manager == nil {
+		return fmt.Errorf("device manager not initialized")
+	}
+
+	_, ok := s.manager.GetDevice(deviceID)
+	if !ok {
+		return pkgError.ErrDeviceNotFound
+	}
+
+	storage := s.manager.GetStorage()
+	if storage == nil {
+		return fmt.Errorf("storage not available")
+	}
+
+	if err := storage.SetDeviceCallConfig(deviceID, config); err != nil {
+		return fmt.Errorf("failed to set device call config: %w", err)
+	}
+
+	websocket.Broadcast <- websocket.BroadcastMessage{
+		Code:    "DEVICE_CALL_CONFIG_UPDATED",
+		Message: fmt.Sprintf("Device %s call config updated", deviceID),
+		Result: map[string]any{
+			"device_id": deviceID,
+		},
+	}
+
+	return nil
+}
+
+// GetDeviceCallConfig retrieves how a specific device handles incoming calls.
+func (s *serviceDevice) GetDeviceCallConfig(ctx context.Context, deviceID string) (*chatstorage.DeviceCallConfig, error) {
+	if s.manager == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+
+	_, ok := s.manager.GetDevice(deviceID)
+	if !ok {
+		return nil, pkgError.ErrDeviceNotFound
+	}
+
+	storage := s.manager.GetStorage()
+	if storage == nil {
+		return nil, fmt.Errorf("storage not available")
+	}
+
+	config, err := storage.GetDeviceCallConfig(deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device call config: %w", err)
+	}
+
+	return config, nil
+}
+
+// SetDeviceProxyConfig sets a device's outbound proxy and fallback pool and
+// applies them to its client.
+func (s *serviceDevice) SetDeviceProxyConfig(ctx context.Context, deviceID string, config *chatstorage.DeviceProxyConfig) error {
+	if s.manager == nil {
+		return fmt.Errorf("device manager not initialized")
+	}
+
+	_, ok := s.manager.GetDevice(deviceID)
+	if !ok {
+		return pkgError.ErrDeviceNotFound
+	}
+
+	if config == nil {
+		return pkgError.ValidationError("proxy config is required")
+	}
+	for _, proxyURL := range append([]string{config.ProxyURL}, config.Pool...) {
+		if proxyURL == "" {
+			continue
+		}
+		if err := whatsapp.ValidateProxyURL(proxyURL); err != nil {
+			return pkgError.ValidationError(err.Error())
+		}
+	}
+
+	storage := s.manager.GetStorage()
+	if storage == nil {
+		return fmt.Errorf("storage not available")
+	}
+
+	if err := storage.SetDeviceProxyConfig(deviceID, config); err != nil {
+		return fmt.Errorf("failed to set device proxy config: %w", err)
+	}
+
+	if err := s.manager.ApplyDeviceProxy(deviceID); err != nil {
+		return err
+	}
+
+	websocket.Broadcast <- websocket.BroadcastMessage{
+		Code:    "DEVICE_PROXY_UPDATED",
+		Message: fmt.Sprintf("Device %s proxy updated", deviceID),
+		Result: map[string]any{
+			"device_id": deviceID,
+		},
+	}
+
+	return nil
+}
+
+// GetDeviceProxyConfig retrieves a device's outbound proxy and fallback pool.
+func (s *serviceDevice) GetDeviceProxyConfig(ctx context.Context, deviceID string) (*chatstorage.DeviceProxyConfig, error) {
+	if s.manager == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+
+	_, ok := s.manager.GetDevice(deviceID)
+	if !ok {
+		return nil, pkgError.ErrDeviceNotFound
+	}
+
+	storage := s.manager.GetStorage()
+	if storage == nil {
+		return nil, fmt.Errorf("storage not available")
+	}
+
+	config, err := storage.GetDeviceProxyConfig(deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device proxy config: %w", err)
+	}
+
+	return config, nil
+}
+
+// ExportDeviceBackup returns a device's session and settings encrypted with passphrase.
+func (s *serviceDevice) ExportDeviceBackup(ctx context.Context, deviceID string, passphrase string) ([]byte, error) {
+	if err := validations.ValidateDeviceID(ctx, deviceID); err != nil {
+		return nil, err
+	}
+	if err := validations.ValidateBackupPassphrase(ctx, passphrase); err != nil {
+		return nil, err
+	}
+	if s.manager == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+	return s.manager.ExportDeviceBackup(ctx, deviceID, passphrase)
+}
+
+// ImportDeviceBackup restores a backup as a device slot and prepares its client
+// without connecting it, so the caller decides when the session goes live.
+func (s *serviceDevice) ImportDeviceBackup(ctx context.Context, backup []byte, passphrase string, deviceID string) (*domainDevice.Device, error) {
+	if len(backup) == 0 {
+		return nil, pkgError.ValidationError("backup file is required")
+	}
+	if err := validations.ValidateBackupPassphrase(ctx, passphrase); err != nil {
+		return nil, err
+	}
+	if s.manager == nil {
+		return nil, fmt.Errorf("device manager not initialized")
+	}
+
+	restored, err := s.manager.ImportDeviceBackup(ctx, backup, passphrase, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	// The client's event handler outlives this request, so it gets a fresh context.
+	inst, err := s.manager.EnsureClient(context.Background(), restored.ID())
+	if err != nil {
+		return nil, fmt.Errorf("device %s restored but its client could not be created: %w", restored.ID(), err)
+	}
+
+	device := convertInstance(inst)
+	return &device, nil
+}
+
+func (s *serviceDevice) GetDevicesHealth(_ context.Context) (domainDevice.DevicesHealth, error) {
+	result := domainDevice.DevicesHealth{Devices: []domainDevice.DeviceHealth{}}
+	if s.manager == nil {
+		return result, nil
+	}
+
+	for _, inst := range s.manager.ListDevices() {
+		health := convertHealth(inst)
+		if health.IsConnected {
+			result.Connected++
+		}
+		if health.IsLoggedIn {
+			result.LoggedIn++
+		}
+		result.Devices = append(result.Devices, health)
+	}
+	result.Total = len(result.Devices)
+	return result, nil
+}
+
+func convertHealth(inst *whatsapp.DeviceInstance) domainDevice.DeviceHealth {
+	stats := inst.ConnectionStats()
+	return domainDevice.DeviceHealth{
+		ID:                 inst.ID(),
+		JID:                inst.JID(),
+		State:              deriveState(inst),
+		IsConnected:        inst.IsConnected(),
+		IsLoggedIn:         inst.IsLoggedIn(),
+		LastConnectedAt:    optionalTime(stats.LastConnectedAt),
+		LastDisconnectedAt: optionalTime(stats.LastDisconnectedAt),
+		ReconnectAttempts:  stats.ReconnectAttempts,
+		LastError:          stats.LastError,
+		LastErrorAt:        optionalTime(stats.LastErrorAt),
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
 func convertInstance(inst *whatsapp.DeviceInstance) domainDevice.Device {
 	if inst == nil {
 		return domainDevice.Device{}
@@ -328,6 +675,9 @@ func convertInstance(inst *whatsapp.DeviceInstance) domainDevice.Device {
 		DisplayName: inst.DisplayName(),
 		State:       state,
 		JID:         inst.JID(),
+		Proxy:       inst.ActiveProxy(),
+		Tags:        inst.Tags(),
+		Metadata:    inst.Metadata(),
 		CreatedAt:   inst.CreatedAt(),
 	}
 }
@@ -33,4 +33,56 @@ func TestGetDeviceWebhook_InvalidManager(t *testing.T) {
 	}
 }
 
+func TestMatchesDeviceFilter(t *testing.T) {
+	device := domainDevice.Device{
+		ID:       "sales-1",
+		JID:      "628123456789@s.whatsapp.net",
+		State:    domainDevice.DeviceStateLoggedIn,
+		Tags:     []string{"Tenant-Acme", "sales"},
+		Metadata: map[string]string{"customer": "Acme Corp"},
+	}
+
+	cases := []struct {
+		name   string
+		filter domainDevice.DeviceFilter
+		want   bool
+	}{
+		{"empty filter", domainDevice.DeviceFilter{}, true},
+		{"all tags, any case", domainDevice.DeviceFilter{Tags: []string{"tenant-acme", "SALES"}}, true},
+		{"missing tag", domainDevice.DeviceFilter{Tags: []string{"tenant-acme", "support"}}, false},
+		{"state", domainDevice.DeviceFilter{State: domainDevice.DeviceStateLoggedIn}, true},
+		{"other state", domainDevice.DeviceFilter{State: domainDevice.DeviceStateDisconnected}, false},
+		{"search metadata", domainDevice.DeviceFilter{Search: "acme corp"}, true},
+		{"search jid", domainDevice.DeviceFilter{Search: "62812345"}, true},
+		{"search miss", domainDevice.DeviceFilter{Search: "globex"}, false},
+	}
+	for _, tc := range cases {
+		if got := matchesDeviceFilter(device, tc.filter); got != tc.want {
+			t.Errorf("%s: matchesDeviceFilter() = %t, want %t", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestListDevices_RejectsUnknownState(t *testing.T) {
+	svc := &serviceDevice{manager: nil}
+	if _, err := svc.ListDevices(context.Background(), domainDevice.DeviceFilter{State: "online"}); err == nil {
+		t.Fatal("expected a validation error for an unknown state")
+	}
+}
 
+func TestNormalizeUpdateDeviceRequest(t *testing.T) {
+	got := normalizeUpdateDeviceRequest(domainDevice.UpdateDeviceRequest{
+		Tags:     []string{" tenant-acme ", "", "Tenant-ACME", "sales"},
+		Metadata: map[string]string{" team ": "Support"},
+	})
+	if len(got.Tags) != 2 || got.Tags[0] != "tenant-acme" || got.Tags[1] != "sales" {
+		t.Fatalf("unexpected tags: %v", got.Tags)
+	}
+	if got.Metadata["team"] != "Support" {
+		t.Fatalf("expected trimmed metadata keys, got %v", got.Metadata)
+	}
+
+	if untouched := normalizeUpdateDeviceRequest(domainDevice.UpdateDeviceRequest{}); untouched.Tags != nil || untouched.Metadata != nil {
+		t.Fatalf("omitted fields must stay nil, got %+v", untouched)
+	}
+}
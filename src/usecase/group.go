@@ -3,26 +3,39 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 )
 
-type serviceGroup struct{}
+type serviceGroup struct {
+	sendService     domainSend.ISendUsecase
+	chatStorageRepo domainChatStorage.IChatStorageRepository
+}
 
-func NewGroupService() domainGroup.IGroupUsecase {
-	return &serviceGroup{}
+// NewGroupService builds the group usecase. sendService posts community
+// announcements so they go through the regular send pipeline; chatStorageRepo
+// holds the group metadata cache and participant history.
+func NewGroupService(sendService domainSend.ISendUsecase, chatStorageRepo domainChatStorage.IChatStorageRepository) domainGroup.IGroupUsecase {
+	return &serviceGroup{sendService: sendService, chatStorageRepo: chatStorageRepo}
 }
 
 func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domainGroup.JoinGroupWithLinkRequest) (groupID string, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.JoinGroupWithLink")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateJoinGroupWithLink(ctx, request); err != nil {
 		return groupID, err
 	}
@@ -41,6 +54,9 @@ func (service serviceGroup) JoinGroupWithLink(ctx context.Context, request domai
 }
 
 func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.LeaveGroupRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.LeaveGroup")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateLeaveGroup(ctx, request); err != nil {
 		return err
 	}
@@ -59,6 +75,9 @@ func (service serviceGroup) LeaveGroup(ctx context.Context, request domainGroup.
 }
 
 func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (groupID string, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.CreateGroup")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateCreateGroup(ctx, request); err != nil {
 		return groupID, err
 	}
@@ -74,11 +93,20 @@ func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup
 		return
 	}
 
+	var linkedParent types.GroupLinkedParent
+	if request.CommunityID != "" {
+		community, err := service.communityInfo(ctx, client, request.CommunityID)
+		if err != nil {
+			return groupID, err
+		}
+		linkedParent.LinkedParentJID = community.JID
+	}
+
 	groupConfig := whatsmeow.ReqCreateGroup{
 		Name:              request.Title,
 		Participants:      participantsJID,
 		GroupParent:       types.GroupParent{},
-		GroupLinkedParent: types.GroupLinkedParent{},
+		GroupLinkedParent: linkedParent,
 	}
 
 	groupInfo, err := client.CreateGroup(ctx, groupConfig)
@@ -90,6 +118,9 @@ func (service serviceGroup) CreateGroup(ctx context.Context, request domainGroup
 }
 
 func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request domainGroup.GetGroupInfoFromLinkRequest) (response domainGroup.GetGroupInfoFromLinkResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GetGroupInfoFromLink")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateGetGroupInfoFromLink(ctx, request); err != nil {
 		return response, err
 	}
@@ -121,6 +152,9 @@ func (service serviceGroup) GetGroupInfoFromLink(ctx context.Context, request do
 }
 
 func (service serviceGroup) ManageParticipant(ctx context.Context, request domainGroup.ParticipantRequest) (result []domainGroup.ParticipantStatus, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.ManageParticipant")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateParticipant(ctx, request); err != nil {
 		return result, err
 	}
@@ -166,6 +200,9 @@ func (service serviceGroup) ManageParticipant(ctx context.Context, request domai
 }
 
 func (service serviceGroup) GetGroupParticipants(ctx context.Context, request domainGroup.GetGroupParticipantsRequest) (response domainGroup.GetGroupParticipantsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GetGroupParticipants")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateGetGroupParticipants(ctx, request); err != nil {
 		return response, err
 	}
@@ -180,7 +217,7 @@ func (service serviceGroup) GetGroupParticipants(ctx context.Context, request do
 		return response, err
 	}
 
-	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
+	groupInfo, err := service.groupInfo(ctx, client, groupJID, request.Refresh)
 	if err != nil {
 		return response, err
 	}
@@ -212,7 +249,117 @@ func (service serviceGroup) GetGroupParticipants(ctx context.Context, request do
 	return response, nil
 }
 
+func (service serviceGroup) GetGroupParticipantHistory(ctx context.Context, request domainGroup.GetGroupParticipantHistoryRequest) (response domainGroup.GetGroupParticipantHistoryResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GetGroupParticipantHistory")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateGetGroupParticipantHistory(ctx, &request); err != nil {
+		return response, err
+	}
+
+	// History is read from storage, so it stays available while the device is offline.
+	groupJID, err := utils.ParseJID(request.GroupID)
+	if err != nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("group_id: %v", err))
+	}
+	filter := &domainChatStorage.GroupParticipantEventFilter{
+		DeviceID: deviceIDFromContext(ctx),
+		GroupJID: groupJID.ToNonAD().String(),
+		Action:   request.Action,
+		Limit:    request.Limit,
+		Offset:   request.Offset,
+	}
+	if request.Participant != "" {
+		participantJID, err := utils.ParseJID(request.Participant)
+		if err != nil {
+			return response, pkgError.ValidationError(fmt.Sprintf("participant: %v", err))
+		}
+		filter.ParticipantJID = participantJID.ToNonAD().String()
+	}
+
+	events, err := service.chatStorageRepo.GetGroupParticipantEvents(filter)
+	if err != nil {
+		return response, err
+	}
+	total, err := service.chatStorageRepo.CountGroupParticipantEvents(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.GroupID = filter.GroupJID
+	response.Data = make([]domainGroup.GroupParticipantHistoryEntry, 0, len(events))
+	for _, event := range events {
+		response.Data = append(response.Data, domainGroup.GroupParticipantHistoryEntry{
+			ID:          event.ID,
+			Participant: event.ParticipantJID,
+			Action:      event.Action,
+			ChangedBy:   event.ActorJID,
+			Timestamp:   event.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	response.Pagination = domainGroup.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(total),
+	}
+
+	return response, nil
+}
+
+func (service serviceGroup) GetGroupDescriptionHistory(ctx context.Context, request domainGroup.GetGroupDescriptionHistoryRequest) (response domainGroup.GetGroupDescriptionHistoryResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GetGroupDescriptionHistory")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateGetGroupDescriptionHistory(ctx, &request); err != nil {
+		return response, err
+	}
+
+	// History is read from storage, so it stays available while the device is offline.
+	groupJID, err := utils.ParseJID(request.GroupID)
+	if err != nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("group_id: %v", err))
+	}
+	filter := &domainChatStorage.GroupDescriptionEventFilter{
+		DeviceID: deviceIDFromContext(ctx),
+		GroupJID: groupJID.ToNonAD().String(),
+		Limit:    request.Limit,
+		Offset:   request.Offset,
+	}
+
+	events, err := service.chatStorageRepo.GetGroupDescriptionEvents(filter)
+	if err != nil {
+		return response, err
+	}
+	total, err := service.chatStorageRepo.CountGroupDescriptionEvents(filter)
+	if err != nil {
+		return response, err
+	}
+
+	response.GroupID = filter.GroupJID
+	response.Data = make([]domainGroup.GroupDescriptionHistoryEntry, 0, len(events))
+	for _, event := range events {
+		response.Data = append(response.Data, domainGroup.GroupDescriptionHistoryEntry{
+			ID:            event.ID,
+			Description:   event.Description,
+			DescriptionID: event.TopicID,
+			Deleted:       event.Deleted,
+			ChangedBy:     event.ActorJID,
+			Timestamp:     event.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	response.Pagination = domainGroup.PaginationResponse{
+		Limit:  request.Limit,
+		Offset: request.Offset,
+		Total:  int(total),
+	}
+
+	return response, nil
+}
+
 func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, request domainGroup.GetGroupRequestParticipantsRequest) (result []domainGroup.GetGroupRequestParticipantsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GetGroupRequestParticipants")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateGetGroupRequestParticipants(ctx, request); err != nil {
 		return result, err
 	}
@@ -250,6 +397,9 @@ func (service serviceGroup) GetGroupRequestParticipants(ctx context.Context, req
 }
 
 func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context, request domainGroup.GroupRequestParticipantsRequest) (result []domainGroup.ParticipantStatus, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.ManageGroupRequestParticipants")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateManageGroupRequestParticipants(ctx, request); err != nil {
 		return result, err
 	}
@@ -293,6 +443,33 @@ func (service serviceGroup) ManageGroupRequestParticipants(ctx context.Context,
 	return result, nil
 }
 
+// groupInfo answers from the cached group unless refresh is set or the group
+// is not cached yet. Live answers are written back to the cache.
+func (service serviceGroup) groupInfo(ctx context.Context, client *whatsmeow.Client, groupJID types.JID, refresh bool) (*types.GroupInfo, error) {
+	deviceID := deviceIDFromContext(ctx)
+	cacheable := service.chatStorageRepo != nil && deviceID != ""
+
+	if cacheable && !refresh {
+		metadata, err := service.chatStorageRepo.GetGroupMetadata(deviceID, groupJID.ToNonAD().String())
+		if err != nil {
+			logrus.Warnf("Failed to read cached group %s: %v", groupJID, err)
+		} else if whatsapp.GroupMetadataFresh(metadata) {
+			return whatsapp.GroupInfoFromMetadata(metadata), nil
+		}
+	}
+
+	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
+	if err != nil {
+		return nil, err
+	}
+	if cacheable && groupInfo != nil {
+		if err := service.chatStorageRepo.SaveGroupMetadata(whatsapp.GroupMetadataFromInfo(deviceID, groupInfo)); err != nil {
+			logrus.Warnf("Failed to cache group %s: %v", groupJID, err)
+		}
+	}
+	return groupInfo, nil
+}
+
 func (service serviceGroup) participantToJID(ctx context.Context, participants []string) ([]types.JID, error) {
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
@@ -315,6 +492,9 @@ func (service serviceGroup) participantToJID(ctx context.Context, participants [
 }
 
 func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGroup.SetGroupPhotoRequest) (pictureID string, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SetGroupPhoto")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateSetGroupPhoto(ctx, request); err != nil {
 		return pictureID, err
 	}
@@ -357,6 +537,9 @@ func (service serviceGroup) SetGroupPhoto(ctx context.Context, request domainGro
 }
 
 func (service serviceGroup) SetGroupName(ctx context.Context, request domainGroup.SetGroupNameRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SetGroupName")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateSetGroupName(ctx, request); err != nil {
 		return err
 	}
@@ -375,6 +558,9 @@ func (service serviceGroup) SetGroupName(ctx context.Context, request domainGrou
 }
 
 func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGroup.SetGroupLockedRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SetGroupLocked")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateSetGroupLocked(ctx, request); err != nil {
 		return err
 	}
@@ -393,6 +579,9 @@ func (service serviceGroup) SetGroupLocked(ctx context.Context, request domainGr
 }
 
 func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domainGroup.SetGroupAnnounceRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SetGroupAnnounce")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateSetGroupAnnounce(ctx, request); err != nil {
 		return err
 	}
@@ -411,6 +600,9 @@ func (service serviceGroup) SetGroupAnnounce(ctx context.Context, request domain
 }
 
 func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGroup.SetGroupTopicRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SetGroupTopic")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateSetGroupTopic(ctx, request); err != nil {
 		return err
 	}
@@ -429,8 +621,76 @@ func (service serviceGroup) SetGroupTopic(ctx context.Context, request domainGro
 	return client.SetGroupTopic(ctx, groupJID, "", "", request.Topic)
 }
 
+func (service serviceGroup) SetGroupMemberAddMode(ctx context.Context, request domainGroup.SetGroupMemberAddModeRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SetGroupMemberAddMode")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateSetGroupMemberAddMode(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return client.SetGroupMemberAddMode(ctx, groupJID, request.Mode)
+}
+
+func (service serviceGroup) SetGroupJoinApproval(ctx context.Context, request domainGroup.SetGroupJoinApprovalRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SetGroupJoinApproval")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateSetGroupJoinApproval(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return client.SetGroupJoinApprovalMode(ctx, groupJID, request.Enabled)
+}
+
+func (service serviceGroup) SetGroupEphemeral(ctx context.Context, request domainGroup.SetGroupEphemeralRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SetGroupEphemeral")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateSetGroupEphemeral(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	// For groups the timer is a group setting: it becomes the default for
+	// every member rather than a per-chat preference.
+	return client.SetDisappearingTimer(ctx, groupJID, time.Duration(request.TimerSeconds)*time.Second, time.Now())
+}
+
 // GroupInfo retrieves detailed information about a WhatsApp group
 func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.GroupInfoRequest) (response domainGroup.GroupInfoResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GroupInfo")
+	defer func() { telemetry.End(span, err) }()
+
 	// Validate the incoming request
 	if err = validations.ValidateGroupInfo(ctx, request); err != nil {
 		return response, err
@@ -450,8 +710,8 @@ func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.G
 		return response, err
 	}
 
-	// Fetch group information from WhatsApp
-	groupInfo, err := client.GetGroupInfo(ctx, groupJID)
+	// Fetch group information from the cache, or from WhatsApp on a miss or refresh
+	groupInfo, err := service.groupInfo(ctx, client, groupJID, request.Refresh)
 	if err != nil {
 		return response, err
 	}
@@ -465,6 +725,9 @@ func (service serviceGroup) GroupInfo(ctx context.Context, request domainGroup.G
 }
 
 func (service serviceGroup) GetGroupInviteLink(ctx context.Context, request domainGroup.GetGroupInviteLinkRequest) (response domainGroup.GetGroupInviteLinkResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GetGroupInviteLink")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateGetGroupInviteLink(ctx, request); err != nil {
 		return response, err
 	}
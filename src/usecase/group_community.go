@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+func (service serviceGroup) CreateCommunity(ctx context.Context, request domainGroup.CreateCommunityRequest) (communityID string, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.CreateCommunity")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateCreateCommunity(ctx, request); err != nil {
+		return communityID, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return communityID, pkgError.ErrWaCLI
+	}
+	utils.MustLogin(client)
+
+	participantsJID, err := service.participantToJID(ctx, request.Participants)
+	if err != nil {
+		return communityID, err
+	}
+
+	// The server creates the community's announcement group on its own.
+	communityInfo, err := client.CreateGroup(ctx, whatsmeow.ReqCreateGroup{
+		Name:         request.Name,
+		Participants: participantsJID,
+		GroupParent:  types.GroupParent{IsParent: true},
+	})
+	if err != nil {
+		return communityID, err
+	}
+	communityID = communityInfo.JID.String()
+
+	if request.Description != "" {
+		// The community exists at this point, so a failed description is not
+		// worth failing the whole request over.
+		if err := client.SetGroupTopic(ctx, communityInfo.JID, "", "", request.Description); err != nil {
+			logrus.Warnf("Failed to set description of community %s: %v", communityID, err)
+		}
+	}
+
+	return communityID, nil
+}
+
+func (service serviceGroup) LinkGroup(ctx context.Context, request domainGroup.CommunityGroupRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.LinkGroup")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateCommunityGroup(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	community, groupJID, err := service.communityAndGroup(ctx, client, request)
+	if err != nil {
+		return err
+	}
+
+	return client.LinkGroup(ctx, community.JID, groupJID)
+}
+
+func (service serviceGroup) UnlinkGroup(ctx context.Context, request domainGroup.CommunityGroupRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.UnlinkGroup")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateCommunityGroup(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	community, groupJID, err := service.communityAndGroup(ctx, client, request)
+	if err != nil {
+		return err
+	}
+
+	return client.UnlinkGroup(ctx, community.JID, groupJID)
+}
+
+func (service serviceGroup) GetCommunityGroups(ctx context.Context, request domainGroup.GetCommunityGroupsRequest) (response domainGroup.GetCommunityGroupsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GetCommunityGroups")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateGetCommunityGroups(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	community, err := service.communityInfo(ctx, client, request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+
+	subGroups, err := client.GetSubGroups(ctx, community.JID)
+	if err != nil {
+		return response, err
+	}
+
+	response.CommunityID = community.JID.String()
+	response.Name = community.Name
+	response.Groups = make([]domainGroup.CommunityGroup, 0, len(subGroups))
+	for _, subGroup := range subGroups {
+		response.Groups = append(response.Groups, domainGroup.CommunityGroup{
+			JID:            subGroup.JID.String(),
+			Name:           subGroup.Name,
+			IsAnnouncement: subGroup.IsDefaultSubGroup,
+		})
+	}
+
+	return response, nil
+}
+
+func (service serviceGroup) GetCommunityParticipants(ctx context.Context, request domainGroup.GetCommunityParticipantsRequest) (response domainGroup.GetCommunityParticipantsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.GetCommunityParticipants")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateGetCommunityParticipants(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	community, err := service.communityInfo(ctx, client, request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+
+	participants, err := client.GetLinkedGroupsParticipants(ctx, community.JID)
+	if err != nil {
+		return response, err
+	}
+
+	uniq := make(map[types.JID]struct{}, len(participants))
+	for _, participant := range participants {
+		addJIDForUSyncQuery(uniq, utils.ResolveLIDToPhone(ctx, participant, client), participant)
+	}
+	userInfo := userInfoMapForJIDs(ctx, client, mapKeysToSlice(uniq))
+
+	response.CommunityID = community.JID.String()
+	response.Participants = make([]domainGroup.CommunityParticipant, 0, len(participants))
+	for _, participant := range participants {
+		lookupJID, phoneDigits := wireParticipantLookupAndPhone(ctx, client, participant)
+		response.Participants = append(response.Participants, domainGroup.CommunityParticipant{
+			JID:         participant.String(),
+			PhoneNumber: phoneDigits,
+			DisplayName: resolveParticipantDisplayName(ctx, client, "", lookupJID, participant, userInfo),
+		})
+	}
+
+	return response, nil
+}
+
+func (service serviceGroup) SendCommunityAnnouncement(ctx context.Context, request domainGroup.CommunityAnnouncementRequest) (response domainGroup.CommunityAnnouncementResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.group.SendCommunityAnnouncement")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateCommunityAnnouncement(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	community, err := service.communityInfo(ctx, client, request.CommunityID)
+	if err != nil {
+		return response, err
+	}
+
+	subGroups, err := client.GetSubGroups(ctx, community.JID)
+	if err != nil {
+		return response, err
+	}
+
+	var announcementJID types.JID
+	for _, subGroup := range subGroups {
+		if subGroup.IsDefaultSubGroup {
+			announcementJID = subGroup.JID
+			break
+		}
+	}
+	if announcementJID.IsEmpty() {
+		return response, fmt.Errorf("community %s has no announcement group", community.JID)
+	}
+
+	sent, err := service.sendService.SendText(ctx, domainSend.MessageRequest{
+		BaseRequest: domainSend.BaseRequest{Phone: announcementJID.String()},
+		Message:     request.Message,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response = domainGroup.CommunityAnnouncementResponse{
+		CommunityID:         community.JID.String(),
+		AnnouncementGroupID: announcementJID.String(),
+		MessageID:           sent.MessageID,
+		Status:              sent.Status,
+	}
+
+	return response, nil
+}
+
+// communityInfo fetches communityID and rejects plain groups, which the server
+// would otherwise answer with an opaque error on community-only queries.
+func (service serviceGroup) communityInfo(ctx context.Context, client *whatsmeow.Client, communityID string) (*types.GroupInfo, error) {
+	communityJID, err := utils.ValidateJidWithLogin(client, communityID)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := client.GetGroupInfo(ctx, communityJID)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsParent {
+		return nil, pkgError.ValidationError(fmt.Sprintf("community_id: %s is not a community.", communityJID))
+	}
+	return info, nil
+}
+
+func (service serviceGroup) communityAndGroup(ctx context.Context, client *whatsmeow.Client, request domainGroup.CommunityGroupRequest) (*types.GroupInfo, types.JID, error) {
+	community, err := service.communityInfo(ctx, client, request.CommunityID)
+	if err != nil {
+		return nil, types.JID{}, err
+	}
+
+	groupJID, err := utils.ValidateJidWithLogin(client, request.GroupID)
+	if err != nil {
+		return nil, types.JID{}, err
+	}
+
+	return community, groupJID, nil
+}
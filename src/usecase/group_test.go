@@ -2,8 +2,13 @@ package usecase
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waVnameCert"
 	"go.mau.fi/whatsmeow/store"
@@ -89,3 +94,64 @@ func TestResolveParticipantDisplayNamePreservesSourceOrdering(t *testing.T) {
 		})
 	}
 }
+
+type groupHistoryRepoStub struct {
+	domainChatStorage.IChatStorageRepository
+	filter *domainChatStorage.GroupParticipantEventFilter
+	events []*domainChatStorage.GroupParticipantEvent
+}
+
+func (r *groupHistoryRepoStub) GetGroupParticipantEvents(filter *domainChatStorage.GroupParticipantEventFilter) ([]*domainChatStorage.GroupParticipantEvent, error) {
+	r.filter = filter
+	return r.events, nil
+}
+
+func (r *groupHistoryRepoStub) CountGroupParticipantEvents(*domainChatStorage.GroupParticipantEventFilter) (int64, error) {
+	return int64(len(r.events)) + 10, nil
+}
+
+func TestGetGroupParticipantHistoryReadsStorageWithoutClient(t *testing.T) {
+	repo := &groupHistoryRepoStub{events: []*domainChatStorage.GroupParticipantEvent{{
+		ID:             7,
+		ParticipantJID: "628123456789@s.whatsapp.net",
+		Action:         domainGroup.ParticipantActionPromote,
+		ActorJID:       "628111111111@s.whatsapp.net",
+		CreatedAt:      time.Date(2026, time.March, 1, 9, 30, 0, 0, time.FixedZone("WIB", 7*3600)),
+	}}}
+	ctx := whatsapp.ContextWithDevice(context.Background(), whatsapp.NewDeviceInstance("628999999999@s.whatsapp.net", nil, nil))
+
+	response, err := NewGroupService(nil, repo).GetGroupParticipantHistory(ctx, domainGroup.GetGroupParticipantHistoryRequest{
+		GroupID:     "120363402106000000@g.us",
+		Participant: "+628123456789",
+		Limit:       5,
+		Offset:      5,
+	})
+	if err != nil {
+		t.Fatalf("participant history: %v", err)
+	}
+
+	wantFilter := &domainChatStorage.GroupParticipantEventFilter{
+		DeviceID:       "628999999999@s.whatsapp.net",
+		GroupJID:       "120363402106000000@g.us",
+		ParticipantJID: "628123456789@s.whatsapp.net",
+		Limit:          5,
+		Offset:         5,
+	}
+	if !reflect.DeepEqual(repo.filter, wantFilter) {
+		t.Fatalf("filter = %#v, want %#v", repo.filter, wantFilter)
+	}
+	want := domainGroup.GetGroupParticipantHistoryResponse{
+		GroupID: "120363402106000000@g.us",
+		Data: []domainGroup.GroupParticipantHistoryEntry{{
+			ID:          7,
+			Participant: "628123456789@s.whatsapp.net",
+			Action:      "promote",
+			ChangedBy:   "628111111111@s.whatsapp.net",
+			Timestamp:   "2026-03-01T02:30:00Z",
+		}},
+		Pagination: domainGroup.PaginationResponse{Limit: 5, Offset: 5, Total: 11},
+	}
+	if !reflect.DeepEqual(response, want) {
+		t.Fatalf("response = %#v, want %#v", response, want)
+	}
+}
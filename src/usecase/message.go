@@ -12,6 +12,7 @@ import (
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
@@ -75,6 +76,9 @@ func (service serviceMessage) deleteStoredMessage(ctx context.Context, client *w
 }
 
 func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMessage.MarkAsReadRequest) (response domainMessage.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.message.MarkAsRead")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateMarkAsRead(ctx, request); err != nil {
 		return response, err
 	}
@@ -107,6 +111,9 @@ func (service serviceMessage) MarkAsRead(ctx context.Context, request domainMess
 }
 
 func (service serviceMessage) ReactMessage(ctx context.Context, request domainMessage.ReactionRequest) (response domainMessage.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.message.ReactMessage")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateReactMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -161,6 +168,9 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 }
 
 func (service serviceMessage) RevokeMessage(ctx context.Context, request domainMessage.RevokeRequest) (response domainMessage.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.message.RevokeMessage")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateRevokeMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -210,6 +220,9 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 }
 
 func (service serviceMessage) DeleteMessage(ctx context.Context, request domainMessage.DeleteRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.message.DeleteMessage")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateDeleteMessage(ctx, request); err != nil {
 		return err
 	}
@@ -250,6 +263,9 @@ func (service serviceMessage) DeleteMessage(ctx context.Context, request domainM
 }
 
 func (service serviceMessage) UpdateMessage(ctx context.Context, request domainMessage.UpdateMessageRequest) (response domainMessage.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.message.UpdateMessage")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateUpdateMessage(ctx, request); err != nil {
 		return response, err
 	}
@@ -277,6 +293,9 @@ func (service serviceMessage) UpdateMessage(ctx context.Context, request domainM
 
 // StarMessage implements message.IMessageService.
 func (service serviceMessage) StarMessage(ctx context.Context, request domainMessage.StarRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.message.StarMessage")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateStarMessage(ctx, request); err != nil {
 		return err
 	}
@@ -306,6 +325,9 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 
 // DownloadMedia implements message.IMessageService.
 func (service serviceMessage) DownloadMedia(ctx context.Context, request domainMessage.DownloadMediaRequest) (response domainMessage.DownloadMediaResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.message.DownloadMedia")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateDownloadMedia(ctx, request); err != nil {
 		return response, err
 	}
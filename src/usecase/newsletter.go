@@ -1,12 +1,17 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
 	"time"
 
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"go.mau.fi/whatsmeow"
@@ -19,7 +24,71 @@ func NewNewsletterService() domainNewsletter.INewsletterUsecase {
 	return &serviceNewsletter{}
 }
 
+func (service serviceNewsletter) Create(ctx context.Context, request domainNewsletter.CreateRequest) (response domainNewsletter.CreateResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.Create")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateCreateNewsletter(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+	utils.MustLogin(client)
+
+	picture, err := newsletterPicture(request.Picture, request.PictureURL)
+	if err != nil {
+		return response, err
+	}
+
+	metadata, err := client.CreateNewsletter(ctx, whatsmeow.CreateNewsletterParams{
+		Name:        request.Name,
+		Description: request.Description,
+		Picture:     picture,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response = domainNewsletter.CreateResponse{
+		NewsletterID: metadata.ID.String(),
+		Name:         metadata.ThreadMeta.Name.Text,
+		Description:  metadata.ThreadMeta.Description.Text,
+	}
+	if metadata.ThreadMeta.InviteCode != "" {
+		response.InviteLink = whatsmeow.NewsletterLinkPrefix + metadata.ThreadMeta.InviteCode
+	}
+
+	return response, nil
+}
+
+func (service serviceNewsletter) Follow(ctx context.Context, request domainNewsletter.FollowRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.Follow")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateFollowNewsletter(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	if err != nil {
+		return err
+	}
+
+	return client.FollowNewsletter(ctx, JID)
+}
+
 func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNewsletter.UnfollowRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.Unfollow")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateUnfollowNewsletter(ctx, request); err != nil {
 		return err
 	}
@@ -37,7 +106,83 @@ func (service serviceNewsletter) Unfollow(ctx context.Context, request domainNew
 	return client.UnfollowNewsletter(ctx, JID)
 }
 
+// mutationUpdateNewsletter is the WhatsApp mex query whatsmeow uses for
+// newsletter updates but does not expose a method for. whatsmeow swaps in the
+// desktop query ID itself when needed.
+const mutationUpdateNewsletter = "7150902998257522"
+
+func (service serviceNewsletter) Update(ctx context.Context, request domainNewsletter.UpdateRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.Update")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateUpdateNewsletter(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	if err != nil {
+		return err
+	}
+
+	picture, err := newsletterPicture(request.Picture, request.PictureURL)
+	if err != nil {
+		return err
+	}
+
+	updates := newsletterUpdates(request, picture)
+	_, err = client.DangerousInternals().SendMexIQ(ctx, mutationUpdateNewsletter, map[string]any{
+		"newsletter_id": JID.String(),
+		"updates":       updates,
+	})
+	return err
+}
+
+// newsletterUpdates builds the "updates" variable of the update mutation,
+// carrying only the fields the caller asked to change.
+func newsletterUpdates(request domainNewsletter.UpdateRequest, picture []byte) map[string]any {
+	updates := map[string]any{}
+	if request.Name != nil {
+		updates["name"] = *request.Name
+	}
+	if request.Description != nil {
+		updates["description"] = *request.Description
+	}
+	if len(picture) > 0 {
+		updates["picture"] = base64.StdEncoding.EncodeToString(picture)
+	}
+	return updates
+}
+
+func (service serviceNewsletter) Mute(ctx context.Context, request domainNewsletter.MuteRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.Mute")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateMuteNewsletter(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	if err != nil {
+		return err
+	}
+
+	return client.NewsletterToggleMute(ctx, JID, request.Mute)
+}
+
 func (service serviceNewsletter) GetMessages(ctx context.Context, request domainNewsletter.GetMessagesRequest) (response domainNewsletter.GetMessagesResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.GetMessages")
+	defer func() { telemetry.End(span, err) }()
+
 	if err = validations.ValidateGetNewsletterMessages(ctx, &request); err != nil {
 		return response, err
 	}
@@ -79,3 +224,29 @@ func (service serviceNewsletter) GetMessages(ctx context.Context, request domain
 
 	return response, nil
 }
+
+// newsletterPicture returns the channel picture as a square JPEG, read from
+// the uploaded file or fetched from pictureURL. Neither set means no picture.
+func newsletterPicture(file *multipart.FileHeader, pictureURL string) ([]byte, error) {
+	var (
+		processed *bytes.Buffer
+		err       error
+	)
+	switch {
+	case file != nil:
+		processed, err = utils.ProcessGroupPhoto(file)
+	case pictureURL != "":
+		var data []byte
+		data, _, err = utils.DownloadImageFromURL(pictureURL)
+		if err != nil {
+			return nil, err
+		}
+		processed, err = utils.ProcessGroupPhotoBytes(data)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("picture: %v", err))
+	}
+	return processed.Bytes(), nil
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func (service serviceNewsletter) SendPost(ctx context.Context, request domainNewsletter.SendPostRequest) (response domainNewsletter.PostResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.SendPost")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateSendNewsletterPost(ctx, &request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	msg := &waE2E.Message{Conversation: proto.String(request.Message)}
+	var extra whatsmeow.SendRequestExtra
+	if request.Type != domainNewsletter.PostTypeText {
+		data, err := newsletterPostMedia(request)
+		if err != nil {
+			return response, err
+		}
+		mimeType := http.DetectContentType(data)
+		if !strings.HasPrefix(mimeType, request.Type+"/") {
+			return response, pkgError.ValidationError(fmt.Sprintf("media: expected %s content, got %s", request.Type, mimeType))
+		}
+
+		mediaType := whatsmeow.MediaImage
+		if request.Type == domainNewsletter.PostTypeVideo {
+			mediaType = whatsmeow.MediaVideo
+		}
+		// Newsletter media is uploaded unencrypted and referenced by the
+		// upload handle instead of a media key.
+		uploaded, err := client.UploadNewsletter(ctx, data, mediaType)
+		if err != nil {
+			return response, fmt.Errorf("failed to upload %s: %w", request.Type, err)
+		}
+		extra.MediaHandle = uploaded.Handle
+		msg = newsletterMediaMessage(request, mimeType, uploaded)
+	}
+
+	sent, err := client.SendMessage(ctx, JID, msg, extra)
+	if err != nil {
+		return response, err
+	}
+
+	response = domainNewsletter.PostResponse{
+		MessageID: sent.ID,
+		ServerID:  int(sent.ServerID),
+		Status:    fmt.Sprintf("Post published to %s (server timestamp: %s)", JID, sent.Timestamp.String()),
+	}
+	return response, nil
+}
+
+// newsletterPostMedia reads the media of an image/video post from the upload
+// or, failing that, from its URL.
+func newsletterPostMedia(request domainNewsletter.SendPostRequest) ([]byte, error) {
+	if request.Media != nil {
+		file, err := request.Media.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open media: %w", err)
+		}
+		defer file.Close()
+		return io.ReadAll(file)
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if request.Type == domainNewsletter.PostTypeVideo {
+		data, _, err = utils.DownloadVideoFromURL(request.MediaURL)
+	} else {
+		data, _, err = utils.DownloadImageFromURL(request.MediaURL)
+	}
+	return data, err
+}
+
+func newsletterMediaMessage(request domainNewsletter.SendPostRequest, mimeType string, uploaded whatsmeow.UploadResponse) *waE2E.Message {
+	caption := request.Caption
+	if caption == "" {
+		caption = request.Message
+	}
+
+	if request.Type == domainNewsletter.PostTypeVideo {
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			Caption:    proto.String(caption),
+			Mimetype:   proto.String(mimeType),
+			URL:        proto.String(uploaded.URL),
+			DirectPath: proto.String(uploaded.DirectPath),
+			FileSHA256: uploaded.FileSHA256,
+			FileLength: proto.Uint64(uploaded.FileLength),
+		}}
+	}
+	return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+		Caption:    proto.String(caption),
+		Mimetype:   proto.String(mimeType),
+		URL:        proto.String(uploaded.URL),
+		DirectPath: proto.String(uploaded.DirectPath),
+		FileSHA256: uploaded.FileSHA256,
+		FileLength: proto.Uint64(uploaded.FileLength),
+	}}
+}
+
+func (service serviceNewsletter) EditPost(ctx context.Context, request domainNewsletter.EditPostRequest) (response domainNewsletter.PostResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.EditPost")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateEditNewsletterPost(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	edit := client.BuildEdit(JID, request.MessageID, &waE2E.Message{Conversation: proto.String(request.Message)})
+	sent, err := client.SendMessage(ctx, JID, edit)
+	if err != nil {
+		return response, err
+	}
+
+	response = domainNewsletter.PostResponse{
+		MessageID: request.MessageID,
+		Status:    fmt.Sprintf("Post %s edited (server timestamp: %s)", request.MessageID, sent.Timestamp.String()),
+	}
+	return response, nil
+}
+
+func (service serviceNewsletter) DeletePost(ctx context.Context, request domainNewsletter.DeletePostRequest) (response domainNewsletter.PostResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.DeletePost")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateDeleteNewsletterPost(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	// An empty sender marks the key as our own, i.e. an admin deleting a post.
+	revoke := client.BuildRevoke(JID, types.EmptyJID, request.MessageID)
+	sent, err := client.SendMessage(ctx, JID, revoke)
+	if err != nil {
+		return response, err
+	}
+
+	response = domainNewsletter.PostResponse{
+		MessageID: request.MessageID,
+		Status:    fmt.Sprintf("Post %s deleted (server timestamp: %s)", request.MessageID, sent.Timestamp.String()),
+	}
+	return response, nil
+}
+
+func (service serviceNewsletter) GetPostReactions(ctx context.Context, request domainNewsletter.GetPostReactionsRequest) (response domainNewsletter.PostReactionsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.newsletter.GetPostReactions")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidateGetNewsletterPostReactions(ctx, request); err != nil {
+		return response, err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return response, pkgError.ErrWaCLI
+	}
+
+	JID, err := utils.ValidateJidWithLogin(client, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+
+	// Before is exclusive, so asking for one message before ServerID+1
+	// returns exactly the requested post when it still exists.
+	messages, err := client.GetNewsletterMessages(ctx, JID, &whatsmeow.GetNewsletterMessagesParams{
+		Count:  1,
+		Before: types.MessageServerID(request.ServerID + 1),
+	})
+	if err != nil {
+		return response, err
+	}
+
+	for _, msg := range messages {
+		if int(msg.MessageServerID) == request.ServerID {
+			return postReactions(msg), nil
+		}
+	}
+	return response, pkgError.ErrPostNotFound
+}
+
+func postReactions(msg *types.NewsletterMessage) domainNewsletter.PostReactionsResponse {
+	response := domainNewsletter.PostReactionsResponse{
+		ServerID:       int(msg.MessageServerID),
+		MessageID:      string(msg.MessageID),
+		ViewsCount:     msg.ViewsCount,
+		ReactionCounts: msg.ReactionCounts,
+	}
+	if response.ReactionCounts == nil {
+		response.ReactionCounts = map[string]int{}
+	}
+	for _, count := range response.ReactionCounts {
+		response.TotalReactions += count
+	}
+	return response
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestNewsletterUpdatesOnlyCarriesRequestedFields(t *testing.T) {
+	empty := ""
+	got := newsletterUpdates(domainNewsletter.UpdateRequest{Description: &empty}, []byte{0xff, 0xd8})
+	want := map[string]any{"description": "", "picture": "/9g="}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("updates = %#v, want %#v", got, want)
+	}
+
+	name := "Brand Updates"
+	got = newsletterUpdates(domainNewsletter.UpdateRequest{Name: &name}, nil)
+	want = map[string]any{"name": "Brand Updates"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("updates = %#v, want %#v", got, want)
+	}
+}
+
+func TestPostReactionsSumsCounts(t *testing.T) {
+	got := postReactions(&types.NewsletterMessage{
+		MessageServerID: 42,
+		MessageID:       "3EB0AA",
+		ViewsCount:      120,
+		ReactionCounts:  map[string]int{"👍": 7, "❤️": 3},
+	})
+	if got.ServerID != 42 || got.MessageID != "3EB0AA" || got.ViewsCount != 120 || got.TotalReactions != 10 {
+		t.Fatalf("unexpected reactions %#v", got)
+	}
+
+	none := postReactions(&types.NewsletterMessage{MessageServerID: 43})
+	if none.ReactionCounts == nil || none.TotalReactions != 0 {
+		t.Fatalf("a post without reactions must report an empty map, got %#v", none)
+	}
+}
@@ -24,6 +24,7 @@ import (
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/ui/rest/helpers"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
@@ -34,6 +35,7 @@ import (
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/types"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -184,10 +186,21 @@ func NewSendService(appService app.IAppUsecase, chatStorageRepo domainChatStorag
 // "reach-out timelock" rejection is a WhatsApp server-side restriction that the
 // client cannot retry around, so it is surfaced as-is via normalizeSendError.
 func (service serviceSend) wrapSendMessage(ctx context.Context, client *whatsmeow.Client, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
-	ts, err := client.SendMessage(ctx, recipient, msg)
+	sendCtx, span := telemetry.Start(ctx, "whatsmeow.SendMessage",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			telemetry.AttrDeviceID.String(deviceIDFromContext(ctx)),
+			telemetry.AttrRecipient.String(recipient.String()),
+		),
+	)
+	ts, err := client.SendMessage(sendCtx, recipient, msg)
 	if err != nil {
-		return whatsmeow.SendResponse{}, normalizeSendError(err)
+		err = normalizeSendError(err)
+		telemetry.End(span, err)
+		return whatsmeow.SendResponse{}, err
 	}
+	span.SetAttributes(telemetry.AttrMessageID.String(ts.ID))
+	span.End()
 
 	// Store the sent message using chatstorage
 	senderJID := ""
@@ -255,6 +268,9 @@ func normalizeSendError(err error) error {
 }
 
 func (service serviceSend) SendText(ctx context.Context, request domainSend.MessageRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendText")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendMessage(ctx, request)
 	if err != nil {
 		return response, err
@@ -319,6 +335,9 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 }
 
 func (service serviceSend) SendImage(ctx context.Context, request domainSend.ImageRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendImage")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendImage(ctx, request)
 	if err != nil {
 		return response, err
@@ -494,6 +513,9 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 }
 
 func (service serviceSend) SendFile(ctx context.Context, request domainSend.FileRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendFile")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendFile(ctx, request)
 	if err != nil {
 		return response, err
@@ -857,6 +879,9 @@ func generateDefaultWaveform() []byte {
 }
 
 func (service serviceSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendVideo")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendVideo(ctx, request)
 	if err != nil {
 		return response, err
@@ -1034,6 +1059,9 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 }
 
 func (service serviceSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendContact")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendContact(ctx, request)
 	if err != nil {
 		return response, err
@@ -1088,6 +1116,9 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 }
 
 func (service serviceSend) SendLink(ctx context.Context, request domainSend.LinkRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendLink")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendLink(ctx, request)
 	if err != nil {
 		return response, err
@@ -1183,6 +1214,9 @@ func buildLinkMessageText(caption, link string) string {
 }
 
 func (service serviceSend) SendLocation(ctx context.Context, request domainSend.LocationRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendLocation")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendLocation(ctx, request)
 	if err != nil {
 		return response, err
@@ -1234,6 +1268,9 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 }
 
 func (service serviceSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendAudio")
+	defer func() { telemetry.End(span, err) }()
+
 	// Validate request
 	err = validations.ValidateSendAudio(ctx, request)
 	if err != nil {
@@ -1442,6 +1479,9 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 }
 
 func (service serviceSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendPoll")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendPoll(ctx, request)
 	if err != nil {
 		return response, err
@@ -1479,6 +1519,9 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 }
 
 func (service serviceSend) SendPresence(ctx context.Context, request domainSend.PresenceRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendPresence")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendPresence(ctx, request)
 	if err != nil {
 		return response, err
@@ -1500,6 +1543,9 @@ func (service serviceSend) SendPresence(ctx context.Context, request domainSend.
 }
 
 func (service serviceSend) SendChatPresence(ctx context.Context, request domainSend.ChatPresenceRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendChatPresence")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateSendChatPresence(ctx, request)
 	if err != nil {
 		return response, err
@@ -1589,6 +1635,9 @@ func (service serviceSend) getMentionsFromList(ctx context.Context, mentions []s
 }
 
 func (service serviceSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (response domainSend.GenericResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.send.SendSticker")
+	defer func() { telemetry.End(span, err) }()
+
 	// Validate request
 	err = validations.ValidateSendSticker(ctx, request)
 	if err != nil {
@@ -13,6 +13,7 @@ import (
 	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/infrastructure/whatsapp"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/telemetry"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/disintegration/imaging"
@@ -33,6 +34,9 @@ func NewUserService(chatStorageRepo domainChatStorage.IChatStorageRepository) do
 }
 
 func (service serviceUser) Info(ctx context.Context, request domainUser.InfoRequest) (response domainUser.InfoResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.Info")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateUserInfo(ctx, request)
 	if err != nil {
 		return response, err
@@ -121,6 +125,9 @@ func (service serviceUser) Info(ctx context.Context, request domainUser.InfoRequ
 }
 
 func (service serviceUser) Avatar(ctx context.Context, request domainUser.AvatarRequest) (response domainUser.AvatarResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.Avatar")
+	defer func() { telemetry.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -194,6 +201,9 @@ func (service serviceUser) Avatar(ctx context.Context, request domainUser.Avatar
 // For more details, see: https://github.com/tulir/whatsmeow/blob/main/group.go
 // Related issue: https://github.com/aldinokemal/go-whatsapp-web-multidevice/issues/553
 func (service serviceUser) MyListGroups(ctx context.Context) (response domainUser.MyListGroupsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.MyListGroups")
+	defer func() { telemetry.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -212,6 +222,9 @@ func (service serviceUser) MyListGroups(ctx context.Context) (response domainUse
 }
 
 func (service serviceUser) MyListNewsletter(ctx context.Context) (response domainUser.MyListNewsletterResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.MyListNewsletter")
+	defer func() { telemetry.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -274,6 +287,9 @@ func (service serviceUser) MyListNewsletter(ctx context.Context) (response domai
 }
 
 func (service serviceUser) MyPrivacySetting(ctx context.Context) (response domainUser.MyPrivacySettingResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.MyPrivacySetting")
+	defer func() { telemetry.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -293,6 +309,9 @@ func (service serviceUser) MyPrivacySetting(ctx context.Context) (response domai
 }
 
 func (service serviceUser) MyListContacts(ctx context.Context) (response domainUser.MyListContactsResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.MyListContacts")
+	defer func() { telemetry.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -315,6 +334,9 @@ func (service serviceUser) MyListContacts(ctx context.Context) (response domainU
 }
 
 func (service serviceUser) ChangeAvatar(ctx context.Context, request domainUser.ChangeAvatarRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.ChangeAvatar")
+	defer func() { telemetry.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return pkgError.ErrWaCLI
@@ -373,6 +395,9 @@ func (service serviceUser) ChangeAvatar(ctx context.Context, request domainUser.
 }
 
 func (service serviceUser) ChangePushName(ctx context.Context, request domainUser.ChangePushNameRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.ChangePushName")
+	defer func() { telemetry.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return pkgError.ErrWaCLI
@@ -387,6 +412,9 @@ func (service serviceUser) ChangePushName(ctx context.Context, request domainUse
 }
 
 func (service serviceUser) IsOnWhatsApp(ctx context.Context, request domainUser.CheckRequest) (response domainUser.CheckResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.IsOnWhatsApp")
+	defer func() { telemetry.End(span, err) }()
+
 	client := whatsapp.ClientFromContext(ctx)
 	if client == nil {
 		return response, pkgError.ErrWaCLI
@@ -401,6 +429,9 @@ func (service serviceUser) IsOnWhatsApp(ctx context.Context, request domainUser.
 }
 
 func (service serviceUser) BusinessProfile(ctx context.Context, request domainUser.BusinessProfileRequest) (response domainUser.BusinessProfileResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.BusinessProfile")
+	defer func() { telemetry.End(span, err) }()
+
 	err = validations.ValidateBusinessProfile(ctx, request)
 	if err != nil {
 		return response, err
@@ -456,3 +487,117 @@ func (service serviceUser) BusinessProfile(ctx context.Context, request domainUs
 
 	return response, nil
 }
+
+// chatStateFreshness bounds how long a "composing" state counts as typing;
+// WhatsApp clients repeat it while typing and may never send "paused".
+const chatStateFreshness = 25 * time.Second
+
+func (service serviceUser) SubscribePresence(ctx context.Context, request domainUser.PresenceSubscriptionRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.SubscribePresence")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidatePresenceSubscription(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	jid, err := utils.ValidateJidWithLogin(client, request.Phone)
+	if err != nil {
+		return err
+	}
+	if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+		return pkgError.ValidationError("presence is only available for users")
+	}
+
+	return whatsapp.SubscribePresence(ctx, client, service.chatStorageRepo, deviceIDFromContext(ctx), jid)
+}
+
+func (service serviceUser) UnsubscribePresence(ctx context.Context, request domainUser.PresenceSubscriptionRequest) (err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.UnsubscribePresence")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidatePresenceSubscription(ctx, request); err != nil {
+		return err
+	}
+
+	client := whatsapp.ClientFromContext(ctx)
+	if client == nil {
+		return pkgError.ErrWaCLI
+	}
+
+	jid, err := utils.ValidateJidWithLogin(client, request.Phone)
+	if err != nil {
+		return err
+	}
+	if jid.Server != types.DefaultUserServer && jid.Server != types.HiddenUserServer {
+		return pkgError.ValidationError("presence is only available for users")
+	}
+
+	return whatsapp.UnsubscribePresence(service.chatStorageRepo, deviceIDFromContext(ctx), jid)
+}
+
+func (service serviceUser) Presence(ctx context.Context, request domainUser.PresenceRequest) (response domainUser.PresenceResponse, err error) {
+	ctx, span := telemetry.Start(ctx, "usecase.user.Presence")
+	defer func() { telemetry.End(span, err) }()
+
+	if err = validations.ValidatePresence(ctx, request); err != nil {
+		return response, err
+	}
+
+	raw, err := contactJID(ctx, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	jid, err := types.ParseJID(raw)
+	if err != nil {
+		return response, pkgError.ValidationError(err.Error())
+	}
+
+	deviceID := deviceIDFromContext(ctx)
+	response.JID = raw
+	response.Subscribed, err = service.chatStorageRepo.HasPresenceSubscription(deviceID, raw)
+	if err != nil {
+		return response, err
+	}
+
+	presence, err := whatsapp.LastKnownPresence(service.chatStorageRepo, deviceID, jid)
+	if err != nil {
+		return response, err
+	}
+	return presenceResponse(response, presence, time.Now()), nil
+}
+
+// presenceResponse fills response from the last known presence. A composing
+// state older than chatStateFreshness no longer counts as typing.
+func presenceResponse(response domainUser.PresenceResponse, presence *domainChatStorage.Presence, now time.Time) domainUser.PresenceResponse {
+	response.Status = "unknown"
+	if presence == nil {
+		return response
+	}
+
+	response.Status = "offline"
+	if presence.Available {
+		response.Status = "online"
+	}
+	if presence.LastSeenAt != nil {
+		response.LastSeen = presence.LastSeenAt.Format(time.RFC3339)
+	}
+	if presence.ChatStateAt != nil {
+		response.ChatJID = presence.ChatJID
+		response.ChatState = presence.ChatState
+		response.ChatMedia = presence.ChatMedia
+		response.ChatStateAt = presence.ChatStateAt.Format(time.RFC3339)
+		if presence.ChatState == string(types.ChatPresenceComposing) && now.Sub(*presence.ChatStateAt) <= chatStateFreshness {
+			response.Status = "typing"
+			if presence.ChatMedia == string(types.ChatPresenceMediaAudio) {
+				response.Status = "recording"
+			}
+		}
+	}
+	response.UpdatedAt = presence.UpdatedAt.Format(time.RFC3339)
+	return response
+}
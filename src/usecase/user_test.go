@@ -2,7 +2,10 @@ package usecase
 
 import (
 	"testing"
+	"time"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainUser "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/user"
 	"go.mau.fi/whatsmeow/types"
 )
 
@@ -41,3 +44,31 @@ func TestContactDisplayName(t *testing.T) {
 		})
 	}
 }
+
+func TestPresenceResponseStatus(t *testing.T) {
+	now := time.Date(2026, time.October, 4, 9, 0, 0, 0, time.UTC)
+	recent := now.Add(-10 * time.Second)
+	stale := now.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		presence *domainChatStorage.Presence
+		want     string
+	}{
+		{name: "unknown without any presence", presence: nil, want: "unknown"},
+		{name: "offline", presence: &domainChatStorage.Presence{}, want: "offline"},
+		{name: "online", presence: &domainChatStorage.Presence{Available: true}, want: "online"},
+		{name: "typing while composing is fresh", presence: &domainChatStorage.Presence{Available: true, ChatState: "composing", ChatStateAt: &recent}, want: "typing"},
+		{name: "recording audio", presence: &domainChatStorage.Presence{ChatState: "composing", ChatMedia: "audio", ChatStateAt: &recent}, want: "recording"},
+		{name: "stale composing falls back to online", presence: &domainChatStorage.Presence{Available: true, ChatState: "composing", ChatStateAt: &stale}, want: "online"},
+		{name: "paused is not typing", presence: &domainChatStorage.Presence{Available: true, ChatState: "paused", ChatStateAt: &recent}, want: "online"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := presenceResponse(domainUser.PresenceResponse{}, tt.presence, now)
+			if got.Status != tt.want {
+				t.Fatalf("status = %q, want %q", got.Status, tt.want)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package validations
 import (
 	"context"
 	"strings"
+	"time"
 
 	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
@@ -27,3 +29,30 @@ func ValidateRejectCall(ctx context.Context, callerJID string, callID string) er
 
 	return nil
 }
+
+func ValidateListCalls(ctx context.Context, request *domainCall.ListCallsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+		validation.Field(&request.Status, validation.In(
+			domainChatStorage.CallStatusRinging,
+			domainChatStorage.CallStatusAccepted,
+			domainChatStorage.CallStatusRejected,
+			domainChatStorage.CallStatusMissed,
+			domainChatStorage.CallStatusEnded,
+		)),
+		validation.Field(&request.Since, validation.Date(time.RFC3339)),
+		validation.Field(&request.Until, validation.Date(time.RFC3339)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
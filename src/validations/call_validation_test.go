@@ -0,0 +1,36 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainCall "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/call"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateListCalls(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainCall.ListCallsRequest
+		wantErr   bool
+		wantLimit int
+	}{
+		{name: "defaults the limit", request: domainCall.ListCallsRequest{}, wantLimit: 50},
+		{name: "accepts filters", request: domainCall.ListCallsRequest{Limit: 10, Status: "missed", Since: "2026-10-01T00:00:00Z", Until: "2026-10-02T00:00:00+07:00"}, wantLimit: 10},
+		{name: "rejects unknown status", request: domainCall.ListCallsRequest{Status: "busy"}, wantErr: true},
+		{name: "rejects non RFC3339 since", request: domainCall.ListCallsRequest{Since: "2026-10-01"}, wantErr: true},
+		{name: "rejects oversized limit", request: domainCall.ListCallsRequest{Limit: 501}, wantErr: true},
+		{name: "rejects negative offset", request: domainCall.ListCallsRequest{Offset: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateListCalls(context.Background(), &tt.request)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
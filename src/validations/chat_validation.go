@@ -2,6 +2,9 @@ package validations
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
@@ -102,3 +105,59 @@ func ValidateArchiveChat(ctx context.Context, request *domainChat.ArchiveChatReq
 
 	return nil
 }
+
+func ValidateRequestHistory(ctx context.Context, request *domainChat.RequestHistoryRequest) error {
+	// Set default count if not provided
+	if request.Count == 0 {
+		request.Count = 50
+	}
+	request.BeforeMessageID = strings.TrimSpace(request.BeforeMessageID)
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.ChatJID, validation.Required),
+		validation.Field(&request.Count, validation.Min(1), validation.Max(100)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateExportChat(ctx context.Context, request *domainChat.ExportChatRequest) error {
+	// Default to JSON, the only format that round-trips every field
+	request.Format = strings.ToLower(strings.TrimSpace(request.Format))
+	if request.Format == "" {
+		request.Format = domainChat.ExportFormatJSON
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Format, validation.In(
+			domainChat.ExportFormatJSON,
+			domainChat.ExportFormatCSV,
+			domainChat.ExportFormatHTML,
+			domainChat.ExportFormatText,
+		)),
+		validation.Field(&request.StartTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.EndTime, validation.Date(time.RFC3339)),
+		validation.Field(&request.Timezone, validation.By(validateTimezone)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func validateTimezone(value any) error {
+	name, _ := value.(string)
+	if name == "" {
+		return nil
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return errors.New("must be an IANA time zone such as Asia/Jakarta")
+	}
+	return nil
+}
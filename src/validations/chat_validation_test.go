@@ -250,3 +250,44 @@ func TestValidateSetDisappearingTimer(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRequestHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainChat.RequestHistoryRequest
+		err       any
+		wantCount int
+	}{
+		{
+			name:      "should default the count",
+			request:   domainChat.RequestHistoryRequest{ChatJID: "6289685028129@s.whatsapp.net"},
+			wantCount: 50,
+		},
+		{
+			name:      "should accept an anchor message",
+			request:   domainChat.RequestHistoryRequest{ChatJID: "6289685028129@s.whatsapp.net", BeforeMessageID: " 3EB0ABC ", Count: 100},
+			wantCount: 100,
+		},
+		{
+			name:    "should error with empty chat_jid",
+			request: domainChat.RequestHistoryRequest{},
+			err:     pkgError.ValidationError("chat_jid: cannot be blank."),
+		},
+		{
+			name:    "should error with oversized count",
+			request: domainChat.RequestHistoryRequest{ChatJID: "6289685028129@s.whatsapp.net", Count: 101},
+			err:     pkgError.ValidationError("count: must be no greater than 100."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequestHistory(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Equal(t, tt.wantCount, tt.request.Count)
+				assert.NotContains(t, tt.request.BeforeMessageID, " ")
+			}
+		})
+	}
+}
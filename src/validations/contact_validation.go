@@ -0,0 +1,97 @@
+package validations
+
+import (
+	"context"
+	"strings"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	maxContactTags      = 20
+	maxContactTagLength = 50
+	maxContactNotes     = 4000
+)
+
+func ValidateListContacts(ctx context.Context, request *domainContact.ListContactsRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+	request.Search = strings.TrimSpace(request.Search)
+	request.Tag = strings.TrimSpace(request.Tag)
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetContact(ctx context.Context, request domainContact.GetContactRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.JID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// ValidateUpdateContact trims the tags and drops duplicates before checking
+// them, so the stored tags are exactly what filtering by tag expects.
+func ValidateUpdateContact(ctx context.Context, request *domainContact.UpdateContactRequest) error {
+	if request.Tags == nil && request.Notes == nil {
+		return pkgError.ValidationError("tags or notes is required")
+	}
+
+	if request.Tags != nil {
+		seen := make(map[string]bool, len(*request.Tags))
+		tags := make([]string, 0, len(*request.Tags))
+		for _, tag := range *request.Tags {
+			tag = strings.TrimSpace(tag)
+			if seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+		request.Tags = &tags
+	}
+
+	var tags []string
+	if request.Tags != nil {
+		tags = *request.Tags
+	}
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.JID, validation.Required),
+	)
+	if err == nil {
+		err = validation.Errors{
+			"tags": validation.Validate(tags,
+				validation.Length(0, maxContactTags),
+				validation.Each(validation.Required, validation.RuneLength(1, maxContactTagLength)),
+			),
+		}.Filter()
+	}
+	if err == nil && request.Notes != nil {
+		err = validation.Errors{
+			"notes": validation.Validate(*request.Notes, validation.RuneLength(0, maxContactNotes)),
+		}.Filter()
+	}
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
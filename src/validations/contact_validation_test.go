@@ -0,0 +1,57 @@
+package validations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	domainContact "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/contact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateListContacts(t *testing.T) {
+	request := domainContact.ListContactsRequest{Search: "  budi ", Tag: " vip "}
+	require.NoError(t, ValidateListContacts(context.Background(), &request))
+	assert.Equal(t, 50, request.Limit)
+	assert.Equal(t, "budi", request.Search)
+	assert.Equal(t, "vip", request.Tag)
+
+	assert.Error(t, ValidateListContacts(context.Background(), &domainContact.ListContactsRequest{Limit: 501}))
+	assert.Error(t, ValidateListContacts(context.Background(), &domainContact.ListContactsRequest{Offset: -1}))
+}
+
+func TestValidateUpdateContact(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	tagsPtr := func(tags ...string) *[]string { return &tags }
+
+	tests := []struct {
+		name     string
+		request  domainContact.UpdateContactRequest
+		wantErr  bool
+		wantTags []string
+	}{
+		{name: "requires tags or notes", request: domainContact.UpdateContactRequest{JID: "628111"}, wantErr: true},
+		{name: "requires jid", request: domainContact.UpdateContactRequest{Notes: strPtr("hi")}, wantErr: true},
+		{name: "trims and dedupes tags", request: domainContact.UpdateContactRequest{JID: "628111", Tags: tagsPtr(" vip", "vip ", "lead")}, wantTags: []string{"vip", "lead"}},
+		{name: "empty tags clear them", request: domainContact.UpdateContactRequest{JID: "628111", Tags: tagsPtr()}, wantTags: []string{}},
+		{name: "notes only", request: domainContact.UpdateContactRequest{JID: "628111", Notes: strPtr("")}},
+		{name: "rejects blank tag", request: domainContact.UpdateContactRequest{JID: "628111", Tags: tagsPtr("  ")}, wantErr: true},
+		{name: "rejects long tag", request: domainContact.UpdateContactRequest{JID: "628111", Tags: tagsPtr(strings.Repeat("a", 51))}, wantErr: true},
+		{name: "rejects long notes", request: domainContact.UpdateContactRequest{JID: "628111", Notes: strPtr(strings.Repeat("a", 4001))}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateContact(context.Background(), &tt.request)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantTags != nil {
+				require.NotNil(t, tt.request.Tags)
+				assert.Equal(t, tt.wantTags, *tt.request.Tags)
+			}
+		})
+	}
+}
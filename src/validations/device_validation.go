@@ -2,9 +2,14 @@ package validations
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"strings"
 
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	domainDevice "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/device"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 )
 
 // ValidateDeviceID ensures a device id was provided before any logout/remove work.
@@ -19,3 +24,120 @@ func ValidateDeviceID(_ context.Context, deviceID string) error {
 	}
 	return nil
 }
+
+// ValidateBackupPassphrase rejects passphrases too short to protect a device
+// backup, which holds the full WhatsApp session.
+func ValidateBackupPassphrase(_ context.Context, passphrase string) error {
+	if len(strings.TrimSpace(passphrase)) < 8 {
+		return pkgError.ValidationError("passphrase must be at least 8 characters")
+	}
+	return nil
+}
+
+const (
+	maxDeviceTags          = 20
+	maxDeviceTagLength     = 50
+	maxDeviceMetadataKeys  = 50
+	maxDeviceMetadataKey   = 64
+	maxDeviceMetadataValue = 500
+)
+
+// ValidateUpdateDevice bounds the tags and metadata an operator can attach to
+// a device. Tags may not contain commas, which separate tags in list filters.
+func ValidateUpdateDevice(_ context.Context, request domainDevice.UpdateDeviceRequest) error {
+	if len(request.Tags) > maxDeviceTags {
+		return pkgError.ValidationError(fmt.Sprintf("a device can have at most %d tags", maxDeviceTags))
+	}
+	for _, tag := range request.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return pkgError.ValidationError("tags cannot be empty")
+		}
+		if len(tag) > maxDeviceTagLength {
+			return pkgError.ValidationError(fmt.Sprintf("tag %q is longer than %d characters", tag, maxDeviceTagLength))
+		}
+		if strings.Contains(tag, ",") {
+			return pkgError.ValidationError(fmt.Sprintf("tag %q cannot contain a comma", tag))
+		}
+	}
+
+	if len(request.Metadata) > maxDeviceMetadataKeys {
+		return pkgError.ValidationError(fmt.Sprintf("a device can have at most %d metadata keys", maxDeviceMetadataKeys))
+	}
+	for key, value := range request.Metadata {
+		if strings.TrimSpace(key) == "" {
+			return pkgError.ValidationError("metadata keys cannot be empty")
+		}
+		if len(key) > maxDeviceMetadataKey {
+			return pkgError.ValidationError(fmt.Sprintf("metadata key %q is longer than %d characters", key, maxDeviceMetadataKey))
+		}
+		if len(value) > maxDeviceMetadataValue {
+			return pkgError.ValidationError(fmt.Sprintf("metadata value of %q is longer than %d characters", key, maxDeviceMetadataValue))
+		}
+	}
+	return nil
+}
+
+// reservedWebhookHeaders are set by the delivery itself and cannot be
+// overridden through WebhookHeaders.
+var reservedWebhookHeaders = []string{
+	"Host", "Content-Length", "Transfer-Encoding", "Connection",
+	"X-Hub-Signature", "X-Hub-Signature-256", "X-Hub-Signature-512",
+}
+
+// ValidateDeviceWebhookDelivery checks the delivery settings of a device
+// webhook: method, headers, body template and signature algorithm.
+func ValidateDeviceWebhookDelivery(_ context.Context, cfg *domainChatStorage.DeviceWebhookConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	switch strings.ToUpper(cfg.WebhookMethod) {
+	case "", http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return pkgError.ValidationError(fmt.Sprintf("webhook_method must be POST, PUT or PATCH, got %q", cfg.WebhookMethod))
+	}
+
+	switch strings.ToLower(cfg.WebhookSignatureAlgorithm) {
+	case "", domainChatStorage.WebhookSignatureSHA256, domainChatStorage.WebhookSignatureSHA1,
+		domainChatStorage.WebhookSignatureSHA512, domainChatStorage.WebhookSignatureNone:
+	default:
+		return pkgError.ValidationError(fmt.Sprintf("webhook_signature_algorithm must be sha256, sha1, sha512 or none, got %q", cfg.WebhookSignatureAlgorithm))
+	}
+
+	for name, value := range cfg.WebhookHeaders {
+		if !validHeaderName(name) {
+			return pkgError.ValidationError(fmt.Sprintf("invalid webhook header name %q", name))
+		}
+		for _, reserved := range reservedWebhookHeaders {
+			if strings.EqualFold(name, reserved) {
+				return pkgError.ValidationError(fmt.Sprintf("webhook header %s is set by the server and cannot be overridden", reserved))
+			}
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return pkgError.ValidationError(fmt.Sprintf("webhook header %s has an invalid value", name))
+		}
+	}
+
+	if cfg.WebhookTemplate != "" {
+		if _, err := utils.ParseWebhookTemplate(cfg.WebhookTemplate); err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("invalid webhook_template: %v", err))
+		}
+	}
+	return nil
+}
+
+// validHeaderName reports whether name is an RFC 7230 token.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainChatStorage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chatstorage"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDeviceWebhookDelivery(t *testing.T) {
+	valid := &domainChatStorage.DeviceWebhookConfig{
+		WebhookMethod:             "patch",
+		WebhookHeaders:            map[string]string{"X-Api-Key": "k"},
+		WebhookTemplate:           `{"text": {{ json (default "n/a" .payload.body) }}}`,
+		WebhookSignatureAlgorithm: "SHA1",
+	}
+	assert.NoError(t, ValidateDeviceWebhookDelivery(context.Background(), valid))
+
+	invalid := map[string]*domainChatStorage.DeviceWebhookConfig{
+		"method":           {WebhookMethod: "GET"},
+		"algorithm":        {WebhookSignatureAlgorithm: "md5"},
+		"header name":      {WebhookHeaders: map[string]string{"Bad Header": "x"}},
+		"reserved header":  {WebhookHeaders: map[string]string{"x-hub-signature-256": "forged"}},
+		"header injection": {WebhookHeaders: map[string]string{"X-Token": "a\r\nX-Other: b"}},
+		"template":         {WebhookTemplate: "{{ .event "},
+	}
+	for name, cfg := range invalid {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateDeviceWebhookDelivery(context.Background(), cfg)
+			assert.ErrorAs(t, err, new(pkgError.ValidationError))
+		})
+	}
+}
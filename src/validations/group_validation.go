@@ -7,6 +7,7 @@ import (
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 func ValidateJoinGroupWithLink(ctx context.Context, request domainGroup.JoinGroupWithLinkRequest) error {
@@ -85,6 +86,50 @@ func ValidateGetGroupParticipants(ctx context.Context, request domainGroup.GetGr
 	return nil
 }
 
+func ValidateGetGroupParticipantHistory(ctx context.Context, request *domainGroup.GetGroupParticipantHistoryRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.Action, validation.In(
+			domainGroup.ParticipantActionJoin,
+			domainGroup.ParticipantActionLeave,
+			domainGroup.ParticipantActionPromote,
+			domainGroup.ParticipantActionDemote,
+		)),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetGroupDescriptionHistory(ctx context.Context, request *domainGroup.GetGroupDescriptionHistoryRequest) error {
+	// Set default limit if not provided
+	if request.Limit == 0 {
+		request.Limit = 50
+	}
+
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.Limit, validation.Min(1), validation.Max(500)),
+		validation.Field(&request.Offset, validation.Min(0)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateGetGroupRequestParticipants(ctx context.Context, request domainGroup.GetGroupRequestParticipantsRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.GroupID, validation.Required),
@@ -205,6 +250,46 @@ func ValidateSetGroupTopic(ctx context.Context, request domainGroup.SetGroupTopi
 	return nil
 }
 
+func ValidateSetGroupMemberAddMode(ctx context.Context, request domainGroup.SetGroupMemberAddModeRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+		validation.Field(&request.Mode, validation.Required, validation.In(types.GroupMemberAddModeAdmin, types.GroupMemberAddModeAllMember)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateSetGroupJoinApproval(ctx context.Context, request domainGroup.SetGroupJoinApprovalRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+		// Enabled is a boolean, no additional validation needed
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateSetGroupEphemeral(ctx context.Context, request domainGroup.SetGroupEphemeralRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.GroupID, validation.Required),
+		// Groups accept the same timers as one-to-one chats
+		validation.Field(&request.TimerSeconds, validation.By(validateTimerValue)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
 func ValidateGroupInfo(ctx context.Context, request domainGroup.GroupInfoRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.GroupID, validation.Required),
@@ -228,3 +313,66 @@ func ValidateGetGroupInviteLink(ctx context.Context, request domainGroup.GetGrou
 
 	return nil
 }
+
+func ValidateCreateCommunity(ctx context.Context, request domainGroup.CreateCommunityRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required),
+		validation.Field(&request.Participants, validation.Each(validation.Required)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateCommunityGroup(ctx context.Context, request domainGroup.CommunityGroupRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+		validation.Field(&request.GroupID, validation.Required, validation.NotIn(request.CommunityID).Error("must differ from community_id")),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetCommunityGroups(ctx context.Context, request domainGroup.GetCommunityGroupsRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetCommunityParticipants(ctx context.Context, request domainGroup.GetCommunityParticipantsRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateCommunityAnnouncement(ctx context.Context, request domainGroup.CommunityAnnouncementRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.CommunityID, validation.Required),
+		validation.Field(&request.Message, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/stretchr/testify/assert"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 func TestValidateJoinGroupWithLink(t *testing.T) {
@@ -706,3 +707,239 @@ func TestValidateGroupInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCreateCommunity(t *testing.T) {
+	type args struct {
+		request domainGroup.CreateCommunityRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with name only",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Name: "Customers",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty name",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Description: "All customer groups",
+			}},
+			err: pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name: "should error with blank participant",
+			args: args{request: domainGroup.CreateCommunityRequest{
+				Name:         "Customers",
+				Participants: []string{"6281234567890", ""},
+			}},
+			err: pkgError.ValidationError("participants: (1: cannot be blank.)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateCommunity(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateCommunityGroup(t *testing.T) {
+	type args struct {
+		request domainGroup.CommunityGroupRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with community and group",
+			args: args{request: domainGroup.CommunityGroupRequest{
+				CommunityID: "120363000000000001@g.us",
+				GroupID:     "120363000000000002@g.us",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty community id",
+			args: args{request: domainGroup.CommunityGroupRequest{
+				GroupID: "120363000000000002@g.us",
+			}},
+			err: pkgError.ValidationError("community_id: cannot be blank."),
+		},
+		{
+			name: "should error when linking the community to itself",
+			args: args{request: domainGroup.CommunityGroupRequest{
+				CommunityID: "120363000000000001@g.us",
+				GroupID:     "120363000000000001@g.us",
+			}},
+			err: pkgError.ValidationError("group_id: must differ from community_id."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCommunityGroup(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateCommunityAnnouncement(t *testing.T) {
+	type args struct {
+		request domainGroup.CommunityAnnouncementRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with community and message",
+			args: args{request: domainGroup.CommunityAnnouncementRequest{
+				CommunityID: "120363000000000001@g.us",
+				Message:     "Maintenance tonight at 22:00",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty message",
+			args: args{request: domainGroup.CommunityAnnouncementRequest{
+				CommunityID: "120363000000000001@g.us",
+			}},
+			err: pkgError.ValidationError("message: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCommunityAnnouncement(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSetGroupMemberAddMode(t *testing.T) {
+	type args struct {
+		request domainGroup.SetGroupMemberAddModeRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with admin_add",
+			args: args{request: domainGroup.SetGroupMemberAddModeRequest{
+				GroupID: "123456789@g.us",
+				Mode:    types.GroupMemberAddModeAdmin,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown mode",
+			args: args{request: domainGroup.SetGroupMemberAddModeRequest{
+				GroupID: "123456789@g.us",
+				Mode:    "everyone",
+			}},
+			err: pkgError.ValidationError("mode: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSetGroupMemberAddMode(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSetGroupEphemeral(t *testing.T) {
+	type args struct {
+		request domainGroup.SetGroupEphemeralRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success turning the timer off",
+			args: args{request: domainGroup.SetGroupEphemeralRequest{
+				GroupID: "123456789@g.us",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with 7 days",
+			args: args{request: domainGroup.SetGroupEphemeralRequest{
+				GroupID:      "123456789@g.us",
+				TimerSeconds: 604800,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unsupported timer",
+			args: args{request: domainGroup.SetGroupEphemeralRequest{
+				GroupID:      "123456789@g.us",
+				TimerSeconds: 3600,
+			}},
+			err: pkgError.ValidationError("timer_seconds: timer_seconds must be one of: 0 (off), 86400 (24h), 604800 (7d), 7776000 (90d)."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSetGroupEphemeral(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateGetGroupParticipantHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		request   domainGroup.GetGroupParticipantHistoryRequest
+		err       any
+		wantLimit int
+	}{
+		{
+			name:      "should default the limit",
+			request:   domainGroup.GetGroupParticipantHistoryRequest{GroupID: "123456789@g.us"},
+			err:       nil,
+			wantLimit: 50,
+		},
+		{
+			name:      "should success with action filter",
+			request:   domainGroup.GetGroupParticipantHistoryRequest{GroupID: "123456789@g.us", Action: domainGroup.ParticipantActionPromote, Limit: 10},
+			err:       nil,
+			wantLimit: 10,
+		},
+		{
+			name:      "should error with unknown action",
+			request:   domainGroup.GetGroupParticipantHistoryRequest{GroupID: "123456789@g.us", Action: "kick"},
+			err:       pkgError.ValidationError("action: must be a valid value."),
+			wantLimit: 50,
+		},
+		{
+			name:      "should error with empty group id",
+			request:   domainGroup.GetGroupParticipantHistoryRequest{Limit: 501},
+			err:       pkgError.ValidationError("group_id: cannot be blank; limit: must be no greater than 500."),
+			wantLimit: 501,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGetGroupParticipantHistory(context.Background(), &tt.request)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantLimit, tt.request.Limit)
+		})
+	}
+}
@@ -2,12 +2,15 @@ package validations
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/dustin/go-humanize"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
 
 func ValidateUnfollowNewsletter(ctx context.Context, request domainNewsletter.UnfollowRequest) error {
@@ -56,3 +59,133 @@ func validateNewsletterJIDShape(value any) error {
 	}
 	return nil
 }
+
+func ValidateCreateNewsletter(ctx context.Context, request domainNewsletter.CreateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&request.Description, validation.Length(0, 2048)),
+		validation.Field(&request.PictureURL, is.URL),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateFollowNewsletter(ctx context.Context, request domainNewsletter.FollowRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required, validation.By(validateNewsletterJIDShape)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateUpdateNewsletter(ctx context.Context, request domainNewsletter.UpdateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required, validation.By(validateNewsletterJIDShape)),
+		validation.Field(&request.Name, validation.NilOrNotEmpty, validation.Length(1, 100)),
+		validation.Field(&request.Description, validation.Length(0, 2048)),
+		validation.Field(&request.PictureURL, is.URL),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Name == nil && request.Description == nil && request.Picture == nil && request.PictureURL == "" {
+		return pkgError.ValidationError("at least one of name, description, picture or picture_url is required")
+	}
+
+	return nil
+}
+
+func ValidateMuteNewsletter(ctx context.Context, request domainNewsletter.MuteRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required, validation.By(validateNewsletterJIDShape)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateSendNewsletterPost(ctx context.Context, request *domainNewsletter.SendPostRequest) error {
+	if request.Type == "" {
+		request.Type = domainNewsletter.PostTypeText
+	}
+
+	isText := request.Type == domainNewsletter.PostTypeText
+	err := validation.ValidateStructWithContext(ctx, request,
+		validation.Field(&request.NewsletterID, validation.Required, validation.By(validateNewsletterJIDShape)),
+		validation.Field(&request.Type, validation.In(domainNewsletter.PostTypeText, domainNewsletter.PostTypeImage, domainNewsletter.PostTypeVideo)),
+		validation.Field(&request.Message, validation.When(isText, validation.Required)),
+		validation.Field(&request.MediaURL, is.URL),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if !isText && request.Media == nil && request.MediaURL == "" {
+		return pkgError.ValidationError("media or media_url is required for " + request.Type + " posts")
+	}
+
+	if !isText && request.Media != nil {
+		kind, maxSize := "image", config.WhatsappSettingMaxImageSize
+		if request.Type == domainNewsletter.PostTypeVideo {
+			kind, maxSize = "video", config.WhatsappSettingMaxVideoSize
+		}
+		if request.Media.Size > maxSize {
+			maxSizeString := humanize.Bytes(uint64(maxSize))
+			return pkgError.ValidationError(fmt.Sprintf("max %s upload is %s, please upload in cloud and send via text if your file is higher than %s", kind, maxSizeString, maxSizeString))
+		}
+	}
+
+	return nil
+}
+
+func ValidateEditNewsletterPost(ctx context.Context, request domainNewsletter.EditPostRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required, validation.By(validateNewsletterJIDShape)),
+		validation.Field(&request.MessageID, validation.Required),
+		validation.Field(&request.Message, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateDeleteNewsletterPost(ctx context.Context, request domainNewsletter.DeletePostRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required, validation.By(validateNewsletterJIDShape)),
+		validation.Field(&request.MessageID, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateGetNewsletterPostReactions(ctx context.Context, request domainNewsletter.GetPostReactionsRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.NewsletterID, validation.Required, validation.By(validateNewsletterJIDShape)),
+		validation.Field(&request.ServerID, validation.Required, validation.Min(1)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
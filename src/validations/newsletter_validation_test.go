@@ -2,8 +2,10 @@ package validations
 
 import (
 	"context"
+	"mime/multipart"
 	"testing"
 
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/stretchr/testify/assert"
@@ -115,3 +117,145 @@ func TestValidateGetNewsletterMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCreateNewsletter(t *testing.T) {
+	tests := []struct {
+		name    string
+		request domainNewsletter.CreateRequest
+		err     any
+	}{
+		{
+			name:    "should success with name only",
+			request: domainNewsletter.CreateRequest{Name: "Brand Updates"},
+		},
+		{
+			name:    "should error with empty name",
+			request: domainNewsletter.CreateRequest{Description: "news"},
+			err:     pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name:    "should error with invalid picture url",
+			request: domainNewsletter.CreateRequest{Name: "Brand Updates", PictureURL: "not a url"},
+			err:     pkgError.ValidationError("picture_url: must be a valid URL."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCreateNewsletter(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateUpdateNewsletter(t *testing.T) {
+	name := "Brand Updates"
+	empty := ""
+	tests := []struct {
+		name    string
+		request domainNewsletter.UpdateRequest
+		err     any
+	}{
+		{
+			name:    "should success clearing the description",
+			request: domainNewsletter.UpdateRequest{NewsletterID: "120363123456789@newsletter", Description: &empty},
+		},
+		{
+			name:    "should success with a new name",
+			request: domainNewsletter.UpdateRequest{NewsletterID: "120363123456789@newsletter", Name: &name},
+		},
+		{
+			name:    "should error without any change",
+			request: domainNewsletter.UpdateRequest{NewsletterID: "120363123456789@newsletter"},
+			err:     pkgError.ValidationError("at least one of name, description, picture or picture_url is required"),
+		},
+		{
+			name:    "should error with an empty name",
+			request: domainNewsletter.UpdateRequest{NewsletterID: "120363123456789@newsletter", Name: &empty},
+			err:     pkgError.ValidationError("name: cannot be blank."),
+		},
+		{
+			name:    "should error with a group jid",
+			request: domainNewsletter.UpdateRequest{NewsletterID: "120363123456789@g.us", Name: &name},
+			err:     pkgError.ValidationError("newsletter_id: must end with @newsletter."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdateNewsletter(context.Background(), tt.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendNewsletterPost(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  domainNewsletter.SendPostRequest
+		err      any
+		wantType string
+	}{
+		{
+			name:     "should default to a text post",
+			request:  domainNewsletter.SendPostRequest{NewsletterID: "120363123456789@newsletter", Message: "hello"},
+			wantType: domainNewsletter.PostTypeText,
+		},
+		{
+			name:     "should error on a text post without message",
+			request:  domainNewsletter.SendPostRequest{NewsletterID: "120363123456789@newsletter"},
+			err:      pkgError.ValidationError("message: cannot be blank."),
+			wantType: domainNewsletter.PostTypeText,
+		},
+		{
+			name:     "should success with an image url",
+			request:  domainNewsletter.SendPostRequest{NewsletterID: "120363123456789@newsletter", Type: "image", MediaURL: "https://example.com/a.jpg"},
+			wantType: domainNewsletter.PostTypeImage,
+		},
+		{
+			name:     "should error on a video post without media",
+			request:  domainNewsletter.SendPostRequest{NewsletterID: "120363123456789@newsletter", Type: "video"},
+			err:      pkgError.ValidationError("media or media_url is required for video posts"),
+			wantType: domainNewsletter.PostTypeVideo,
+		},
+		{
+			name: "should error on an oversized image upload",
+			request: domainNewsletter.SendPostRequest{NewsletterID: "120363123456789@newsletter", Type: "image",
+				Media: &multipart.FileHeader{Filename: "a.jpg", Size: config.WhatsappSettingMaxImageSize + 1}},
+			err:      pkgError.ValidationError("max image upload is 20 MB, please upload in cloud and send via text if your file is higher than 20 MB"),
+			wantType: domainNewsletter.PostTypeImage,
+		},
+		{
+			name: "should error on an oversized video upload",
+			request: domainNewsletter.SendPostRequest{NewsletterID: "120363123456789@newsletter", Type: "video",
+				Media: &multipart.FileHeader{Filename: "a.mp4", Size: config.WhatsappSettingMaxVideoSize + 1}},
+			err:      pkgError.ValidationError("max video upload is 100 MB, please upload in cloud and send via text if your file is higher than 100 MB"),
+			wantType: domainNewsletter.PostTypeVideo,
+		},
+		{
+			name:     "should error on an unsupported type",
+			request:  domainNewsletter.SendPostRequest{NewsletterID: "120363123456789@newsletter", Type: "audio"},
+			err:      pkgError.ValidationError("type: must be a valid value."),
+			wantType: "audio",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.request
+			err := ValidateSendNewsletterPost(context.Background(), &req)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantType, req.Type)
+		})
+	}
+}
+
+func TestValidateGetNewsletterPostReactions(t *testing.T) {
+	assert.NoError(t, ValidateGetNewsletterPostReactions(context.Background(), domainNewsletter.GetPostReactionsRequest{
+		NewsletterID: "120363123456789@newsletter",
+		ServerID:     42,
+	}))
+	assert.Equal(t, pkgError.ValidationError("server_id: cannot be blank."), ValidateGetNewsletterPostReactions(context.Background(), domainNewsletter.GetPostReactionsRequest{
+		NewsletterID: "120363123456789@newsletter",
+	}))
+}